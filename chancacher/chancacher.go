@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -34,6 +35,20 @@ var (
 // without a clean way to triage. It's best to just enforce a sensible maximum.
 const MaxDepth = 1000000
 
+// OverflowPolicy controls what happens to new values when the backing store has reached its
+// maximum size.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks writers until the cache drains below its maximum size.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest data in the backing store to make room. Data is
+	// discarded a backing file at a time, so up to half the backing store may be dropped at once.
+	OverflowDropOldest
+	// OverflowDropNew discards new values that do not fit in the backing store.
+	OverflowDropNew
+)
+
 // A ChanCacher is a pipeline of channels with a variable-sized internal
 // buffer. The buffer can also cache to disk. The user is expected to connect
 // ChanCacher.In and ChanCacher.Out.
@@ -56,6 +71,9 @@ type ChanCacher struct {
 	cacheAck       chan bool
 	cacheIsDone    bool
 	cacheCommitted bool
+	cacheEvict     chan bool // nil unless the overflow policy is OverflowDropOldest
+	policy         OverflowPolicy
+	dropped        atomic.Uint64
 
 	fileLock *flock.Flock
 }
@@ -73,6 +91,17 @@ type ChanCacher struct {
 // way, you can recover data sent to disk on a crash or previous use of
 // Commit().
 func NewChanCacher(maxDepth int, cachePath string, maxSize int) (*ChanCacher, error) {
+	return NewChanCacherEx(maxDepth, cachePath, maxSize, OverflowBlock)
+}
+
+// NewChanCacherEx creates a new ChanCacher as NewChanCacher does, with the given policy applied
+// when the backing store reaches maxSize.
+func NewChanCacherEx(maxDepth int, cachePath string, maxSize int, policy OverflowPolicy) (*ChanCacher, error) {
+	switch policy {
+	case OverflowBlock, OverflowDropOldest, OverflowDropNew:
+	default:
+		return nil, fmt.Errorf("Invalid overflow policy %d", policy)
+	}
 	if cachePath != "" {
 		if fi, err := os.Stat(cachePath); err != nil {
 			if !os.IsNotExist(err) {
@@ -98,6 +127,10 @@ func NewChanCacher(maxDepth int, cachePath string, maxSize int) (*ChanCacher, er
 		cacheDone:   make(chan bool),
 		cacheAck:    make(chan bool),
 		maxSize:     maxSize,
+		policy:      policy,
+	}
+	if policy == OverflowDropOldest {
+		c.cacheEvict = make(chan bool, 1)
 	}
 
 	// we start the cache unpaused, and because of go idioms, we have to
@@ -259,6 +292,7 @@ func (c *ChanCacher) cacheHandler() {
 
 		dec := gob.NewDecoder(c.cacheR)
 		var v interface{}
+		var evicting bool
 		for {
 			err = dec.Decode(&v)
 			if err != nil {
@@ -267,8 +301,24 @@ func (c *ChanCacher) cacheHandler() {
 			if v == nil {
 				continue
 			}
+			if evicting {
+				c.dropped.Add(1)
+				continue
+			}
 
-			c.Out <- v
+			for sent := false; !sent && !evicting; {
+				select {
+				case c.Out <- v:
+					sent = true
+				case <-c.cacheEvict:
+					// ignore stale requests, the cache may have since drained
+					if c.Size() >= c.maxSize {
+						// this file holds the oldest data, discard the rest of it
+						evicting = true
+						c.dropped.Add(1)
+					}
+				}
+			}
 		}
 		if err != io.EOF {
 			// TODO: log
@@ -294,6 +344,8 @@ func (c *ChanCacher) cacheHandler() {
 				close(c.cacheAck)
 				return
 			case <-time.After(time.Second):
+			case <-c.cacheEvict:
+				// W is full, swap now so the oldest data can be discarded
 			}
 		}
 
@@ -313,6 +365,16 @@ func (c *ChanCacher) cacheValue(v interface{}) {
 		return
 	}
 	for c.maxSize != 0 && c.Size() >= c.maxSize {
+		switch c.policy {
+		case OverflowDropNew:
+			c.dropped.Add(1)
+			return
+		case OverflowDropOldest:
+			select {
+			case c.cacheEvict <- true:
+			default:
+			}
+		}
 		time.Sleep(100 * time.Millisecond)
 	}
 
@@ -432,6 +494,11 @@ func (c *ChanCacher) finishCache() {
 	}
 }
 
+// Dropped returns the number of values discarded by the overflow policy.
+func (c *ChanCacher) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
 // Returns the number of bytes committed to disk. This does not include data in
 // the in-memory buffer.
 func (c *ChanCacher) Size() int {
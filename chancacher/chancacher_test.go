@@ -698,6 +698,89 @@ func TestCacheMaxSize(t *testing.T) {
 	}
 }
 
+func TestCacheDropNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chancachertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := NewChanCacherEx(0, dir, 10, OverflowDropNew)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing is reading, but writes never block
+	for i := 0; i < 10; i++ {
+		select {
+		case c.In <- &ChanCacheTester{V: i}:
+		case <-time.After(DEFAULT_TIMEOUT):
+			t.Fatal("channel should not block!")
+		}
+	}
+	// let the last value reach the cache before reading
+	time.Sleep(100 * time.Millisecond)
+	if c.Dropped() == 0 {
+		t.Fatal("no values were dropped")
+	}
+
+	// the oldest values are retained
+	if v := (<-c.Out).(*ChanCacheTester); v.V != 0 {
+		t.Fatalf("expected the oldest value, got %v", v.V)
+	}
+}
+
+func TestCacheDropOldest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chancachertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := NewChanCacherEx(0, dir, 100, OverflowDropOldest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing is reading, but writes never block
+	const count = 50
+	for i := 0; i < count; i++ {
+		select {
+		case c.In <- &ChanCacheTester{V: i}:
+		case <-time.After(DEFAULT_TIMEOUT):
+			t.Fatalf("channel blocked on value %d", i)
+		}
+		if i == count/2 {
+			// give the cache a chance to start reading out the oldest values
+			time.Sleep(1500 * time.Millisecond)
+		}
+	}
+	if c.Dropped() == 0 {
+		t.Fatal("no values were dropped")
+	}
+
+	// values come out in order, ending with the newest
+	last := -1
+	for last != count-1 {
+		select {
+		case v := <-c.Out:
+			if n := v.(*ChanCacheTester).V; n <= last {
+				t.Fatalf("out of order value %d after %d", n, last)
+			} else {
+				last = n
+			}
+		case <-time.After(DEFAULT_TIMEOUT):
+			t.Fatalf("newest value never came out, last was %d", last)
+		}
+	}
+}
+
+func TestCacheBadPolicy(t *testing.T) {
+	if _, err := NewChanCacherEx(0, "", 0, OverflowPolicy(99)); err == nil {
+		t.Fatal("invalid policy was accepted")
+	}
+}
+
 // TestCacheEntries verifies that we can write entries, with EVs
 // attached, and read them back out.
 func TestCacheEntries(t *testing.T) {
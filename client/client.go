@@ -11,6 +11,7 @@ package client
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -86,6 +87,7 @@ type Opts struct {
 	Server                 string
 	UseHttps               bool
 	InsecureNoEnforceCerts bool
+	RootCAs                *x509.CertPool // optional CAs trusted for the server certificate, nil uses the system pool
 	ObjLogger              objlog.ObjLog
 }
 
@@ -136,7 +138,7 @@ func NewOpts(opts Opts) (*Client, error) {
 	if opts.UseHttps {
 		wsScheme = `wss`
 		httpScheme = `https`
-		tlsConfig = &tls.Config{InsecureSkipVerify: opts.InsecureNoEnforceCerts}
+		tlsConfig = &tls.Config{InsecureSkipVerify: opts.InsecureNoEnforceCerts, RootCAs: opts.RootCAs}
 	} else {
 		wsScheme = `ws`
 		httpScheme = `http`
@@ -181,6 +181,26 @@ func (c *Client) GetResource(name string) ([]byte, error) {
 	return ioutil.ReadAll(resp.Body)
 }
 
+// GetResourceReader returns the contents of the resource with the specified name as a stream,
+// rather than reading them into memory. The name is resolved as in GetResource. The caller must
+// close the returned reader.
+func (c *Client) GetResourceReader(name string) (io.ReadCloser, error) {
+	guid, err := c.LookupResourceGUID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.methodRequestURL(http.MethodGet, resourcesGuidRawUrl(guid), ``, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer drainResponse(resp)
+		return nil, &ClientError{resp.Status, resp.StatusCode, getBodyErr(resp.Body)}
+	}
+	return resp.Body, nil
+}
+
 // LookupResourceGUID attempts to resolve the GUID for a resource with the specified
 // user-friendly name. It follows precedence as defined on the GetResource method.
 func (c *Client) LookupResourceGUID(name string) (string, error) {
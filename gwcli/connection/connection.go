@@ -17,10 +17,12 @@ consistent access and parameters.
 package connection
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"github.com/gravwell/gravwell/v3/gwcli/clilog"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/cfgdir"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/uniques"
 	"io"
 	"os"
@@ -41,6 +43,40 @@ var Client *grav.Client
 
 var MyInfo types.UserDetails
 
+// name of the profile in use, if any.
+// Login tokens are stored in the profile rather than the shared token file while it is set.
+var profile string
+
+// TLS settings applied by Initialize, see ConfigureTLS
+var tlsSkipVerify bool
+var tlsRootCAs *x509.CertPool
+
+// ConfigureTLS sets the TLS settings used by subsequent calls to Initialize.
+// If skipVerify, the server certificate is not verified. If caFile is given, the PEM bundle it
+// names is trusted in place of the system CAs; an unreadable or certificate-less bundle is an
+// error.
+func ConfigureTLS(skipVerify bool, caFile string) error {
+	var pool *x509.CertPool
+	if caFile != "" {
+		b, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return fmt.Errorf("CA file %v does not contain any PEM encoded certificates", caFile)
+		}
+	}
+	tlsSkipVerify, tlsRootCAs = skipVerify, pool
+	return nil
+}
+
+// UseProfile directs token login and creation to the named profile.
+// An empty name reverts to the shared token file.
+func UseProfile(name string) {
+	profile = name
+}
+
 // Initializes Client using the given connection string of the form <host>:<port>.
 // Destroys a pre-existing connection (but does not log out), if there was one.
 // restLogPath should be left empty outside of test packages
@@ -69,7 +105,8 @@ func Initialize(conn string, UseHttps, InsecureNoEnforceCerts bool, restLogPath
 		grav.Opts{
 			Server:                 conn,
 			UseHttps:               UseHttps,
-			InsecureNoEnforceCerts: InsecureNoEnforceCerts,
+			InsecureNoEnforceCerts: InsecureNoEnforceCerts || tlsSkipVerify,
+			RootCAs:                tlsRootCAs,
 			ObjLogger:              l,
 		}); err != nil {
 		return err
@@ -96,7 +133,9 @@ func Login(cred Credentials, scriptMode bool) (err error) {
 	// login is attempted via JWT token first
 	// If any stage in the process fails
 	// the error is logged and we fall back to flags and prompting
-	if err := LoginViaToken(); err != nil {
+	// A token near expiry is renewed by logging in again, if we have or can prompt for credentials
+	renew := !scriptMode || (cred.Username != "" && (cred.Password != "" || cred.PassfilePath != ""))
+	if err := loginViaToken(renew); err != nil {
 		// jwt token failure; log and move on
 		clilog.Writer.Warnf("Failed to login via JWT token: %v", err)
 
@@ -142,10 +181,26 @@ func Login(cred Credentials, scriptMode bool) (err error) {
 // Attempts to login via JWT token in the user's config directory.
 // Returns an error on failures. This error should be considered nonfatal and the user logged in via
 // an alternative method instead.
-func LoginViaToken() (err error) {
+// Expired tokens are refused without contacting the server.
+func LoginViaToken() error {
+	return loginViaToken(false)
+}
+
+// Implements LoginViaToken. If renew, tokens near expiry are refused as well.
+func loginViaToken(renew bool) (err error) {
 	var tknbytes []byte
+	if profile != "" {
+		if tknbytes, err = profileToken(); err != nil {
+			return
+		}
+	} else {
+		tknbytes, err = os.ReadFile(cfgdir.DefaultTokenPath)
+	}
+	if err == nil {
+		err = checkTokenExpiry(string(tknbytes), renew)
+	}
 	// NOTE the reversal of standard error checking (`err == nil`)
-	if tknbytes, err = os.ReadFile(cfgdir.DefaultTokenPath); err == nil {
+	if err == nil {
 		if err = Client.ImportLoginToken(string(tknbytes)); err == nil {
 			if err = Client.TestLogin(); err == nil {
 				return nil
@@ -155,6 +210,21 @@ func LoginViaToken() (err error) {
 	return
 }
 
+// Returns the login token saved in the active profile.
+func profileToken() ([]byte, error) {
+	s, err := profiles.Load()
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.Get(profile)
+	if err != nil {
+		return nil, err
+	} else if p.Token == "" {
+		return nil, fmt.Errorf("profile %v has no token", profile)
+	}
+	return []byte(p.Token), nil
+}
+
 // Attempts to login via the given credentials struct.
 // A given password takes presidence over a passfile.
 func loginViaCredentials(cred Credentials, scriptMode bool) error {
@@ -196,7 +266,7 @@ func loginViaCredentials(cred Credentials, scriptMode bool) error {
 }
 
 // Creates a login token for future use.
-// The token's path is saved to an environment variable to be looked up on future runs
+// The token is saved to the active profile, if there is one, or the shared token file.
 func CreateToken() error {
 	var (
 		err   error
@@ -206,6 +276,21 @@ func CreateToken() error {
 		return fmt.Errorf("failed to export login token: %v", err)
 	}
 
+	if profile != "" {
+		s, err := profiles.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load profiles: %v", err)
+		}
+		if err = s.SetToken(profile, token); err != nil {
+			return fmt.Errorf("failed to update token: %v", err)
+		}
+		if err = s.Save(); err != nil {
+			return fmt.Errorf("failed to save token: %v", err)
+		}
+		clilog.Writer.Infof("Saved token to profile %v", profile)
+		return nil
+	}
+
 	// write out the token
 	fd, err := os.OpenFile(cfgdir.DefaultTokenPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
@@ -308,10 +393,23 @@ func invalidCronWord(word, entryNumber string, lowBound, highBound int) (invalid
 //
 // Returns a handle to executing searching.
 func StartQuery(qry string, durFromNow time.Duration) (grav.Search, error) {
-	var err error
 	if durFromNow > 0 {
 		return grav.Search{}, fmt.Errorf("duration must be negative or zero (given %v)", durFromNow)
 	}
+	end := time.Now()
+	return StartQueryRange(qry, end.Add(durFromNow), end)
+}
+
+// Validates and submits the given query to the connected server instance, searching over the
+// given time range.
+//
+// Returns a handle to executing searching.
+func StartQueryRange(qry string, start, end time.Time) (grav.Search, error) {
+	var err error
+	if end.Before(start) {
+		return grav.Search{}, fmt.Errorf("end (%v) must not be before start (%v)",
+			end.Format(uniques.SearchTimeFormat), start.Format(uniques.SearchTimeFormat))
+	}
 
 	// validate search query
 	if err = Client.ParseSearch(qry); err != nil {
@@ -320,9 +418,8 @@ func StartQuery(qry string, durFromNow time.Duration) (grav.Search, error) {
 
 	// check for scheduling
 
-	end := time.Now()
 	sreq := types.StartSearchRequest{
-		SearchStart:  end.Add(durFromNow).Format(uniques.SearchTimeFormat),
+		SearchStart:  start.Format(uniques.SearchTimeFormat),
 		SearchEnd:    end.Format(uniques.SearchTimeFormat),
 		Background:   false,
 		SearchString: qry, // pull query from the commandline
@@ -0,0 +1,115 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/cfgdir"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+)
+
+// NearExpiry is how long before it expires that a login token is considered near expiry.
+// Near expiry tokens are replaced at login if credentials are given or can be prompted for;
+// otherwise they are used with a warning.
+const NearExpiry time.Duration = 15 * time.Minute
+
+var ErrTokenExpired = errors.New("login token has expired")
+
+// TokenExpiry returns when the given login token expires.
+// The token is not verified, that is left to the server.
+// ok is false if the token cannot be parsed or does not expire.
+func TokenExpiry(token string) (exp time.Time, ok bool) {
+	var claims jwt.StandardClaims
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.ExpiresAt, 0), true
+}
+
+// ExpiryNotice returns a warning for the user if the login token of the Client has expired or
+// is near expiry, or an empty string if it is not (or the expiry is unknown).
+func ExpiryNotice() string {
+	if Client == nil || !Client.LoggedIn() {
+		return ""
+	}
+	token, err := Client.ExportLoginToken()
+	if err != nil {
+		return ""
+	}
+	exp, ok := TokenExpiry(token)
+	if !ok {
+		return ""
+	}
+	if left := time.Until(exp); left <= 0 {
+		return fmt.Sprintf("Your login token expired at %v. "+
+			"Log out and log back in to continue.", exp.Local().Format(time.Kitchen))
+	} else if left < NearExpiry {
+		return fmt.Sprintf("Your login token expires in %v. "+
+			"Log out and log back in to renew it.", left.Round(time.Minute))
+	}
+	return ""
+}
+
+// checkTokenExpiry returns an error if the given token has expired or, if renew, is near expiry.
+// Tokens without a known expiry are left to the server to judge.
+func checkTokenExpiry(token string, renew bool) error {
+	exp, ok := TokenExpiry(token)
+	if !ok {
+		return nil
+	}
+	if left := time.Until(exp); left <= 0 {
+		return fmt.Errorf("%w at %v", ErrTokenExpired, exp.Format(time.RFC3339))
+	} else if renew && left < NearExpiry {
+		return fmt.Errorf("login token expires at %v and will be renewed", exp.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Logout ends the session of the Client on the server, revoking its login token, and deletes the
+// saved token from the active profile or the shared token file so the next login requests
+// credentials.
+// The saved token is deleted even if the session could not be ended.
+func Logout() (err error) {
+	if Client != nil && Client.LoggedIn() {
+		if err = Client.Logout(); err != nil {
+			err = fmt.Errorf("failed to end the session: %v", err)
+		}
+	}
+	if rerr := removeToken(); rerr != nil {
+		err = errors.Join(err, rerr)
+	}
+	return
+}
+
+// Deletes the saved login token from the active profile or the shared token file.
+func removeToken() error {
+	if profile != "" {
+		s, err := profiles.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load profiles: %v", err)
+		} else if err = s.SetToken(profile, ""); err != nil {
+			return fmt.Errorf("failed to remove token: %v", err)
+		} else if err = s.Save(); err != nil {
+			return fmt.Errorf("failed to save profiles: %v", err)
+		}
+		clilog.Writer.Infof("Removed token from profile %v", profile)
+		return nil
+	}
+	if err := os.Remove(cfgdir.DefaultTokenPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove token file: %v", err)
+	}
+	clilog.Writer.Infof("Removed token file @ %v", cfgdir.DefaultTokenPath)
+	return nil
+}
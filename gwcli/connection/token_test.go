@@ -0,0 +1,100 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package connection
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/cfgdir"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+)
+
+// signs a token expiring at exp, or never if exp is zero
+func testToken(t *testing.T, exp time.Time) string {
+	t.Helper()
+	var claims jwt.StandardClaims
+	if !exp.IsZero() {
+		claims.ExpiresAt = exp.Unix()
+	}
+	tkn, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tkn
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	if got, ok := TokenExpiry(testToken(t, exp)); !ok || !got.Equal(exp) {
+		t.Fatalf("bad expiry %v (ok: %v), want %v", got, ok, exp)
+	}
+	for _, tkn := range []string{testToken(t, time.Time{}), "", "not.a.token"} {
+		if got, ok := TokenExpiry(tkn); ok {
+			t.Fatalf("found expiry %v for token %q", got, tkn)
+		}
+	}
+
+	// near expiry is only refused when renewing
+	valid, near, expired := testToken(t, exp), testToken(t, time.Now().Add(NearExpiry/2)), testToken(t, time.Now().Add(-time.Minute))
+	if err := checkTokenExpiry(valid, true); err != nil {
+		t.Fatal(err)
+	} else if err = checkTokenExpiry(near, false); err != nil {
+		t.Fatal(err)
+	} else if err = checkTokenExpiry(near, true); err == nil {
+		t.Fatal("near expiry token was not renewed")
+	} else if err = checkTokenExpiry(expired, false); !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("expired token was not refused: %v", err)
+	} else if err = checkTokenExpiry(testToken(t, time.Time{}), true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveToken(t *testing.T) {
+	clilog.Init(path.Join(t.TempDir(), "gwcli.TestRemoveToken.log"), "DEBUG")
+	prevTkn, prevProfiles := cfgdir.DefaultTokenPath, profiles.Path
+	t.Cleanup(func() { cfgdir.DefaultTokenPath, profiles.Path = prevTkn, prevProfiles; UseProfile("") })
+	cfgdir.DefaultTokenPath = path.Join(t.TempDir(), "token")
+	profiles.Path = path.Join(t.TempDir(), "profiles.json")
+
+	// shared token file, a missing file is not an error
+	if err := os.WriteFile(cfgdir.DefaultTokenPath, []byte("tkn"), 0600); err != nil {
+		t.Fatal(err)
+	} else if err = removeToken(); err != nil {
+		t.Fatal(err)
+	} else if _, err = os.Stat(cfgdir.DefaultTokenPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("token file was not removed: %v", err)
+	} else if err = removeToken(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the active profile keeps everything but its token
+	s := profiles.Store{}
+	if err := s.Set("prod", profiles.Profile{Server: "prod:443", Token: "tkn"}); err != nil {
+		t.Fatal(err)
+	} else if err = s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	UseProfile("prod")
+	if err := removeToken(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := profiles.Load(); err != nil {
+		t.Fatal(err)
+	} else if p, err := s.Get("prod"); err != nil {
+		t.Fatal(err)
+	} else if p.Token != "" || p.Server != "prod:443" {
+		t.Fatalf("bad profile after logout %+v", p)
+	}
+}
@@ -13,9 +13,13 @@ Builtins are special, meta actions users can invoke from Mother's prompt, no mat
 */
 
 import (
+	"fmt"
 	"github.com/gravwell/gravwell/v3/gwcli/clilog"
 	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/qhistory"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -42,9 +46,13 @@ func initBuiltins() {
 			stylesheet.ExampleStyle.Render("help ~ kits list") +
 			", " +
 			stylesheet.ExampleStyle.Render("help query"),
-		"history": "List previous commands. Navigate history via " + stylesheet.UpDown,
-		"quit":    "Kill the application",
-		"exit":    "Kill the application",
+		"history": "List previous commands. Navigate history via " + stylesheet.UpDown + ".\n" +
+			stylesheet.ExampleStyle.Render("history query") +
+			" lists previous queries, which persist across sessions and can be recalled in the query editor via " +
+			stylesheet.UpDown + ".\n" +
+			"Re-run a query by passing its index. Ex: " + stylesheet.ExampleStyle.Render("history query 3"),
+		"quit": "Kill the application",
+		"exit": "Kill the application",
 	}
 }
 
@@ -83,7 +91,11 @@ func contextHelp(m *Mother, args []string) tea.Cmd {
 }
 
 // Returns a print tea.Cmd to display records from oldest (top) to newest (bottom).
-func listHistory(m *Mother, _ []string) tea.Cmd {
+// If the first argument is "query", operates on the query history instead.
+func listHistory(m *Mother, args []string) tea.Cmd {
+	if len(args) > 0 && args[0] == "query" {
+		return queryHistory(m, args[1:])
+	}
 	toPrint := strings.Builder{}
 	rs := m.history.getAllRecords()
 
@@ -96,6 +108,44 @@ func listHistory(m *Mother, _ []string) tea.Cmd {
 	return tea.Println(strings.TrimSpace(toPrint.String()))
 }
 
+// Displays the persisted query history from oldest (top) to newest (bottom), with the indices that
+// can be given to re-run a query.
+// If given an index, hands the associated query off to the query action.
+func queryHistory(m *Mother, args []string) tea.Cmd {
+	if len(args) == 0 {
+		rs, err := qhistory.Load()
+		if err != nil {
+			return tea.Println(stylesheet.ErrStyle.Render(err.Error()))
+		} else if len(rs) == 0 {
+			return tea.Println("no queries in history")
+		}
+		toPrint := strings.Builder{}
+		width := len(strconv.Itoa(len(rs)))
+		for i, r := range rs {
+			fmt.Fprintf(&toPrint, "%*d  %s  %s\n", width, i+1,
+				r.Timestamp.Local().Format(time.DateTime),
+				strings.Join(strings.Fields(r.Query), " ")) // keep multi-line queries on one line
+		}
+		return tea.Println(strings.TrimSuffix(toPrint.String(), "\n"))
+	}
+
+	idx, err := strconv.Atoi(args[0])
+	if err != nil {
+		return tea.Println(stylesheet.ErrStyle.Render("history index must be a number"))
+	}
+	r, err := qhistory.Get(idx)
+	if err != nil {
+		return tea.Println(stylesheet.ErrStyle.Render(err.Error()))
+	}
+	wr := walk(m.root, []string{"query"})
+	if wr.status != foundAction {
+		clilog.Writer.Errorf("failed to find the query action from root: %#v", wr)
+		return tea.Println(stylesheet.ErrStyle.Render("failed to find the query action"))
+	}
+	// "--" ensures a query beginning with a dash is not parsed as a flag
+	return tea.Sequence(tea.Println(r.Query), handoffArgs(m, wr.endCommand, []string{"--", r.Query}))
+}
+
 func quit(*Mother, []string) tea.Cmd {
 	return tea.Sequence(tea.Println("Bye"), tea.Quit)
 }
@@ -366,6 +366,10 @@ func processInput(m *Mother) tea.Cmd {
 
 		// reconstitute remaining tokens to re-split them via shlex
 		cmd := processActionHandoff(m, wr.endCommand, wr.remainingString)
+		// long sessions can outlive the login token, so warn before the action fails on it
+		if notice := connection.ExpiryNotice(); notice != "" {
+			return tea.Sequence(historyCmd, tea.Println(stylesheet.WarnStyle.Render(notice)), cmd)
+		}
 		return tea.Sequence(historyCmd, cmd)
 
 	case invalidCommand:
@@ -403,13 +407,19 @@ func (m *Mother) promptString() string {
 // These commands are either commands the action wants run to setup or an error print if an error
 // occurred
 func processActionHandoff(m *Mother, actionCmd *cobra.Command, remString string) tea.Cmd {
-	m.mode = handoff
-
 	// split remaining tokens
 	args, err := shlex.Split(remString)
 	if err != nil {
 		clilog.Writer.Errorf("failed to split remaining string %v: %v", remString, err)
 	}
+	return handoffArgs(m, actionCmd, args)
+}
+
+// helper subroutine for processActionHandoff
+//
+// Hands off control to the named action with the given, already split, arguments.
+func handoffArgs(m *Mother, actionCmd *cobra.Command, args []string) tea.Cmd {
+	m.mode = handoff
 
 	// look up the subroutines to load
 	m.active.model, _ = action.GetModel(actionCmd) // save add-on subroutines
@@ -428,7 +438,7 @@ func processActionHandoff(m *Mother, actionCmd *cobra.Command, remString string)
 			fStr.WriteString(fmt.Sprintf("%s - %s", f.Name, f.Value))
 		})
 		clilog.Writer.Debugf("Passing args (%v) and inherited flags (%#v) into %s\n",
-			remString,
+			args,
 			fStr.String(),
 			m.active.command.Name())
 	}
@@ -439,6 +449,7 @@ func processActionHandoff(m *Mother, actionCmd *cobra.Command, remString string)
 	var (
 		invalid string
 		cmd     tea.Cmd
+		err     error
 	)
 	if invalid, cmd, err = m.active.model.SetArgs(
 		m.active.command.InheritedFlags(), args,
@@ -446,9 +457,9 @@ func processActionHandoff(m *Mother, actionCmd *cobra.Command, remString string)
 		m.unsetAction()
 
 		if err != nil {
-			errString := fmt.Sprintf("Failed to set args %v: %v", remString, err)
+			errString := fmt.Sprintf("Failed to set args %v: %v", args, err)
 			clilog.Writer.Errorf("%v\nactive model %v\nactive command%v",
-				errString, m.active.model, remString)
+				errString, m.active.model, args)
 			return tea.Println(errString)
 		}
 		return tea.Println("invalid arguments: " + invalid + "\n" +
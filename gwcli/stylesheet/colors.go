@@ -22,6 +22,7 @@ const (
 	AccentColor1   = lipgloss.Color("#f79c7a")
 	AccentColor2   = lipgloss.Color("#7af79c")
 	ErrorColor     = lipgloss.Color("#f77a96")
+	WarnColor      = lipgloss.Color("#f7e07a")
 	NavColor       = SecondaryColor
 	ActionColor    = AccentColor1
 	FocusedColor   = AccentColor2   // an element currently in focus
@@ -36,7 +36,9 @@ var Name = struct {
 	Append string // append to file output
 	CSV    string
 	JSON   string
+	YAML   string
 	Table  string
+	Watch  string // re-run on an interval
 }{
 	Dryrun:    "dryrun",
 	Name:      "name",
@@ -54,7 +56,9 @@ var Name = struct {
 	Append: "append",
 	CSV:    "csv",
 	JSON:   "json",
+	YAML:   "yaml",
 	Table:  "table",
+	Watch:  "watch",
 }
 
 // Common flag usage description used across a variety of actions
@@ -75,7 +79,9 @@ var Usage = struct {
 	Append string // append to file output
 	CSV    string
 	JSON   string
+	YAML   string
 	Table  string
+	Watch  string
 }{
 	Dryrun: "feigns, describing actions that " +
 		lipgloss.NewStyle().Italic(true).Render("would") +
@@ -96,8 +102,11 @@ var Usage = struct {
 
 	Output: "file to write results to.\nTruncates file unless --append is also given.",
 	Append: "append to the given output file instead of truncating it.",
-	CSV:    "display results as CSV.\nMutually exclusive with --json, --table.",
-	JSON:   "display results as JSON.\nMutually exclusive with --csv, --table.",
-	Table: "display results in a fancy table.\nMutually exclusive with --json, --csv.\n" +
+	CSV:    "display results as CSV.\nMutually exclusive with --json, --yaml, --table.",
+	JSON:   "display results as JSON.\nMutually exclusive with --csv, --yaml, --table.",
+	YAML:   "display results as YAML.\nMutually exclusive with --csv, --json, --table.",
+	Table: "display results in a fancy table.\nMutually exclusive with --json, --yaml, --csv.\n" +
 		"Default if no format flags are given.",
+	Watch: "re-query and redraw the results on the given interval (ex: 5s, 1m) until interrupted.\n" +
+		"Must be at least 1s.",
 }
@@ -16,6 +16,7 @@ var (
 	NavStyle    = lipgloss.NewStyle().Foreground(NavColor)
 	ActionStyle = lipgloss.NewStyle().Foreground(ActionColor)
 	ErrStyle    = lipgloss.NewStyle().Foreground(ErrorColor)
+	WarnStyle   = lipgloss.NewStyle().Foreground(WarnColor)
 
 	// styles useful when displaying multiple, composed models
 	Composable = struct {
@@ -16,11 +16,13 @@ import (
 	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
 	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldcreate"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/google/uuid"
 	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
@@ -155,10 +157,29 @@ func NewExtractorsCreateAction() action.Pair {
 		},
 	}
 
-	return scaffoldcreate.NewCreateAction("extractor", fields, create, func() (fs pflag.FlagSet) {
+	p := scaffoldcreate.NewCreateAction("extractor", fields, create, func() (fs pflag.FlagSet) {
 		fs.Bool(ft.Name.Dryrun, false, ft.Usage.Dryrun)
 		return fs
 	})
+	if err := p.Action.RegisterFlagCompletionFunc(fields[ktags].FlagName, completeTags); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// completeTags completes the last tag in the comma-separated --tags list
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var prev string
+	if i := strings.LastIndex(toComplete, ","); i != -1 {
+		prev, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+	tags, directive := treeutils.CompleteFrom(func() ([]string, error) {
+		return connection.Client.GetTags()
+	})(cmd, args, toComplete)
+	for i := range tags {
+		tags[i] = prev + tags[i]
+	}
+	return tags, directive | cobra.ShellCompDirectiveNoSpace
 }
 
 func create(_ scaffoldcreate.Config, vals scaffoldcreate.Values, fs *pflag.FlagSet) (any, string, error) {
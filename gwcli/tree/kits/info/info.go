@@ -0,0 +1,119 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package info implements an action for displaying the details of an installed or staged kit.
+package info
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "info"
+	short string = "display the details of a kit"
+	long  string = "Displays the details and items of an installed or staged kit, given its UUID or ID " +
+		"(ex: io.gravwell.netflow)."
+)
+
+var aliases []string = []string{"show"}
+
+func NewKitsInfoAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli kits info io.gravwell.netflow --json"
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.Bool(ft.Name.JSON, false, ft.Usage.JSON)
+	return fs
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 1 {
+		return "exactly one kit UUID or ID is required", nil
+	}
+	ks, err := lookup(fs.Arg(0))
+	if err != nil {
+		return err.Error(), nil
+	}
+	if js, err := fs.GetBool(ft.Name.JSON); err != nil {
+		clilog.LogFlagFailedGet(ft.Name.JSON, err)
+	} else if js {
+		b, err := json.Marshal(ks)
+		if err != nil {
+			clilog.Writer.Errorf("Failed to marshal kit: %v", err)
+			return err.Error(), nil
+		}
+		return string(b), nil
+	}
+	return describe(ks), nil
+}
+
+// lookup fetches a kit by UUID or, failing that, by kit ID
+func lookup(id string) (types.IdKitState, error) {
+	if u, err := uuid.Parse(id); err == nil {
+		return connection.Client.KitInfo(u)
+	}
+	kits, err := connection.Client.ListKits()
+	if err != nil {
+		return types.IdKitState{}, err
+	}
+	for _, k := range kits {
+		if k.ID == id {
+			return k, nil
+		}
+	}
+	return types.IdKitState{}, errors.New("no kit with ID " + id + " was found")
+}
+
+// describe renders a kit for human consumption
+func describe(ks types.IdKitState) string {
+	var sb strings.Builder
+	state := "staged"
+	if ks.Installed {
+		state = "installed " + ks.InstallationTime.Format("2006-01-02 15:04:05 MST")
+	}
+	fmt.Fprintf(&sb, "%v (%v) version %v\n", ks.Name, ks.ID, ks.Version)
+	fmt.Fprintf(&sb, "UUID: %v\n", ks.UUID)
+	fmt.Fprintf(&sb, "State: %v\n", state)
+	fmt.Fprintf(&sb, "Signed: %v\n", ks.Signed)
+	fmt.Fprintf(&sb, "Global: %v\n", ks.Global)
+	if len(ks.Labels) > 0 {
+		fmt.Fprintf(&sb, "Labels: %v\n", strings.Join(ks.Labels, ", "))
+	}
+	if ks.Description != "" {
+		sb.WriteString(ks.Description + "\n")
+	}
+	fmt.Fprintf(&sb, "Items (%d):\n", len(ks.Items))
+	for _, itm := range ks.Items {
+		fmt.Fprintf(&sb, "  %v: %v\n", itm.Type, itm.Name)
+	}
+	if len(ks.ModifiedItems) > 0 {
+		fmt.Fprintf(&sb, "Modified since installation (%d):\n", len(ks.ModifiedItems))
+		for _, itm := range ks.ModifiedItems {
+			fmt.Fprintf(&sb, "  %v: %v\n", itm.Type, itm.Name)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,194 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package install implements an action for installing kits from a local file or the kit server.
+package install
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldconfirm"
+
+	"github.com/google/uuid"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "install"
+	short string = "install a kit from a file or the kit server"
+	long  string = "Installs a kit from a local .kit file or, given its UUID or ID (ex: io.gravwell.netflow)," +
+		" from the kit server.\n" +
+		"The kit is staged and the resources it will create are displayed for confirmation before " +
+		"it is installed. --yes skips confirmation and is required in script mode.\n" +
+		"Config macros are set to their default values."
+)
+
+const (
+	flagOverwrite     = "overwrite"
+	flagAllowUnsigned = "allow-unsigned"
+
+	declinedText  = "Abstained from installing %v."
+	installedText = "Installed %v (UUID %v)."
+)
+
+var aliases []string = []string{}
+
+func NewKitsInstallAction() action.Pair {
+	p := scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, flags)
+	p.Action.Example = "./gwcli kits install ./netflow.kit\n./gwcli kits install io.gravwell.netflow --yes"
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.Bool(flagOverwrite, false, "overwrite existing items that conflict with items in the kit")
+	fs.Bool(flagAllowUnsigned, false, "allow installation of kits that are not signed")
+	return fs
+}
+
+// options collected from the flagset
+type options struct {
+	src           string // path or kit server ID
+	overwrite     bool
+	allowUnsigned bool
+}
+
+func getOptions(fs *pflag.FlagSet) (opts options, err error) {
+	if fs.NArg() != 1 {
+		return opts, errors.New("exactly one kit file or kit ID is required")
+	}
+	opts.src = fs.Arg(0)
+	if opts.overwrite, err = fs.GetBool(flagOverwrite); err != nil {
+		return
+	}
+	opts.allowUnsigned, err = fs.GetBool(flagAllowUnsigned)
+	return
+}
+
+// plan stages the kit so the resources it will create can be reviewed before installation
+func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+	opts, err := getOptions(fs)
+	if err != nil {
+		return p, err.Error(), nil
+	}
+	ks, err := stage(opts.src)
+	if err != nil {
+		return p, "", fmt.Errorf("failed to stage kit: %w", err)
+	}
+	p.Summary = summarize(ks)
+	p.Commit = func() (string, error) {
+		if err := install(ks, opts); err != nil {
+			return "", fmt.Errorf("failed to install kit: %w", err)
+		}
+		return fmt.Sprintf(installedText, ks.Name, ks.UUID), nil
+	}
+	p.Abort = func() string {
+		discard(ks)
+		return fmt.Sprintf(declinedText, ks.Name)
+	}
+	return
+}
+
+// stage uploads or pulls the kit named by src, returning its staged state.
+// src is treated as a path if a file exists there, otherwise as a kit on the kit server.
+func stage(src string) (types.KitState, error) {
+	if fi, err := os.Stat(src); err == nil && !fi.IsDir() {
+		return connection.Client.UploadKit(src)
+	}
+	id, err := remoteID(src, connection.Client.ListRemoteKits)
+	if err != nil {
+		return types.KitState{}, err
+	}
+	return connection.Client.PullKit(id)
+}
+
+// remoteID resolves a kit server UUID or ID (ex: io.gravwell.netflow) to the UUID to pull.
+func remoteID(src string, listRemote func(all bool) ([]types.KitMetadata, error)) (uuid.UUID, error) {
+	if id, err := uuid.Parse(src); err == nil {
+		return id, nil
+	} else if strings.HasSuffix(src, ".kit") {
+		return uuid.Nil, fmt.Errorf("no kit file found at %v", src)
+	}
+	mds, err := listRemote(false)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	for _, md := range mds {
+		if md.ID == src {
+			return uuid.Parse(md.UUID)
+		}
+	}
+	return uuid.Nil, fmt.Errorf("kit %v was not found on the kit server", src)
+}
+
+// install installs a staged kit, setting its config macros to their defaults
+func install(ks types.KitState, opts options) error {
+	cfg := types.KitConfig{
+		OverwriteExisting: opts.overwrite,
+		AllowUnsigned:     opts.allowUnsigned,
+		ConfigMacros:      make([]types.KitConfigMacro, len(ks.ConfigMacros)),
+	}
+	for i, m := range ks.ConfigMacros {
+		if m.Value == "" {
+			m.Value = m.DefaultValue
+		}
+		cfg.ConfigMacros[i] = m
+	}
+	return connection.Client.InstallKit(ks.UUID, cfg)
+}
+
+// discard removes a kit that was staged but not installed
+func discard(ks types.KitState) {
+	if err := connection.Client.DeleteKit(ks.UUID); err != nil {
+		clilog.Writer.Warnf("failed to remove staged kit %v: %v", ks.UUID, err)
+	}
+}
+
+// summarize describes the kit and the resources installing it will create
+func summarize(ks types.KitState) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v (%v) version %v\n", ks.Name, ks.ID, ks.Version)
+	if ks.Description != "" {
+		sb.WriteString(ks.Description + "\n")
+	}
+	if !ks.Signed {
+		sb.WriteString("WARNING: this kit is not signed\n")
+	}
+	fmt.Fprintf(&sb, "Installing will create %d item(s):\n", len(ks.Items))
+	for _, itm := range ks.Items {
+		fmt.Fprintf(&sb, "  %v: %v\n", itm.Type, itm.Name)
+	}
+	if len(ks.ConflictingItems) > 0 {
+		fmt.Fprintf(&sb, "%d item(s) conflict with existing items and require --%v:\n",
+			len(ks.ConflictingItems), flagOverwrite)
+		for _, itm := range ks.ConflictingItems {
+			fmt.Fprintf(&sb, "  %v: %v\n", itm.Type, itm.Name)
+		}
+	}
+	if len(ks.RequiredDependencies) > 0 {
+		sb.WriteString("Requires the following kits:\n")
+		for _, dep := range ks.RequiredDependencies {
+			fmt.Fprintf(&sb, "  %v (%v) version %v\n", dep.Name, dep.ID, dep.Version)
+		}
+	}
+	for _, m := range ks.ConfigMacros {
+		v := m.Value
+		if v == "" {
+			v = m.DefaultValue
+		}
+		fmt.Fprintf(&sb, "Macro %v will be set to %q\n", m.MacroName, v)
+	}
+	return sb.String()
+}
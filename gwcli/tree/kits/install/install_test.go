@@ -0,0 +1,83 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package install
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+func TestRemoteID(t *testing.T) {
+	netflow := uuid.New()
+	var listed bool
+	list := func(bool) ([]types.KitMetadata, error) {
+		listed = true
+		return []types.KitMetadata{{ID: "io.gravwell.netflow", UUID: netflow.String()}}, nil
+	}
+
+	if id, err := remoteID(netflow.String(), list); err != nil || id != netflow || listed {
+		t.Fatalf("UUID was not used directly: %v %v (listed: %v)", id, err, listed)
+	}
+	if id, err := remoteID("io.gravwell.netflow", list); err != nil || id != netflow {
+		t.Fatalf("failed to resolve kit ID: %v %v", id, err)
+	}
+	if _, err := remoteID("io.gravwell.missing", list); err == nil {
+		t.Fatal("unknown kit ID was resolved")
+	}
+	listed = false
+	if _, err := remoteID("./missing.kit", list); err == nil || listed {
+		t.Fatal("missing kit file was looked up on the kit server")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	fs := flags()
+	if err := fs.Parse([]string{"--overwrite", "io.gravwell.netflow"}); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := getOptions(&fs)
+	if err != nil {
+		t.Fatal(err)
+	} else if opts != (options{src: "io.gravwell.netflow", overwrite: true}) {
+		t.Fatalf("bad options: %+v", opts)
+	}
+
+	fs = flags()
+	if err := fs.Parse([]string{"a.kit", "b.kit"}); err != nil {
+		t.Fatal(err)
+	} else if _, err = getOptions(&fs); err == nil {
+		t.Fatal("multiple sources were accepted")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := summarize(types.KitState{
+		ID:               "io.gravwell.netflow",
+		Name:             "Netflow",
+		Version:          3,
+		Items:            []types.KitItem{{Type: "dashboard", Name: "Overview"}, {Type: "macro", Name: "NETFLOW"}},
+		ConflictingItems: []types.KitItem{{Type: "macro", Name: "NETFLOW"}},
+		ConfigMacros:     []types.KitConfigMacro{{MacroName: "KIT_NETFLOW_TAG", DefaultValue: "netflow"}},
+	})
+	for _, want := range []string{
+		"Netflow (io.gravwell.netflow) version 3",
+		"not signed",
+		"create 2 item(s)",
+		"  dashboard: Overview",
+		"1 item(s) conflict",
+		`KIT_NETFLOW_TAG will be set to "netflow"`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("summary is missing %q:\n%s", want, s)
+		}
+	}
+}
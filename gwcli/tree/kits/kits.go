@@ -10,7 +10,10 @@ package kits
 
 import (
 	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/kits/info"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/kits/install"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/kits/list"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/kits/uninstall"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 
 	"github.com/spf13/cobra"
@@ -18,7 +21,7 @@ import (
 
 const (
 	use   string = "kits"
-	short string = "view kits associated to this instance"
+	short string = "view and manage kits associated to this instance"
 	long  string = "Kits bundle up of related items (dashboards, queries, scheduled searches," +
 		" autoextractors) for easy installation."
 )
@@ -28,5 +31,10 @@ var aliases []string = []string{"kit"}
 func NewKitsNav() *cobra.Command {
 	return treeutils.GenerateNav(use, short, long, aliases,
 		[]*cobra.Command{},
-		[]action.Pair{list.NewKitsListAction()})
+		[]action.Pair{
+			list.NewKitsListAction(),
+			info.NewKitsInfoAction(),
+			install.NewKitsInstallAction(),
+			uninstall.NewKitsUninstallAction(),
+		})
 }
@@ -0,0 +1,75 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package uninstall implements an action for removing installed or staged kits.
+package uninstall
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffolddelete"
+
+	"github.com/google/uuid"
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+const (
+	use   string = "uninstall"
+	short string = "uninstall a kit"
+	long  string = "Uninstalls a kit by UUID or selection, removing the items it installed.\n" +
+		"Kits with items that were modified after installation are not removed."
+)
+
+var aliases []string = []string{"remove"}
+
+func NewKitsUninstallAction() action.Pair {
+	p := scaffolddelete.NewDeleteAction("kit", "kits", del, fetch)
+	p.Action.Use = use
+	p.Action.Short = short
+	p.Action.Long = long
+	p.Action.Aliases = aliases
+	p.Action.Example = "./gwcli kits uninstall 0e0b1a7c-6d0e-4f4e-9c8f-3b2f7d6b9c11"
+	return p
+}
+
+func fetch() ([]scaffolddelete.Item[uuid.UUID], error) {
+	kits, err := connection.Client.ListKits()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(kits, func(k1, k2 types.IdKitState) int {
+		return strings.Compare(k1.Name, k2.Name)
+	})
+	items := make([]scaffolddelete.Item[uuid.UUID], len(kits))
+	for i, k := range kits {
+		items[i] = scaffolddelete.NewItem(k.Name,
+			fmt.Sprintf("%v version %v\n%v", k.ID, k.Version, k.Description),
+			k.UUID)
+	}
+	return items, nil
+}
+
+func del(dryrun bool, id uuid.UUID) error {
+	if dryrun {
+		_, err := connection.Client.KitInfo(id)
+		return err
+	}
+	modified, err := connection.Client.DeleteKitEx(id.String())
+	if err != nil && len(modified) > 0 {
+		names := make([]string, len(modified))
+		for i, itm := range modified {
+			names[i] = itm.Type + ": " + itm.Name
+		}
+		return fmt.Errorf("%w; modified items: %v", err, strings.Join(names, ", "))
+	}
+	return err
+}
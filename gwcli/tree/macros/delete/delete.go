@@ -10,42 +10,46 @@ package delete
 
 import (
 	"fmt"
+
 	"github.com/gravwell/gravwell/v3/gwcli/action"
 	"github.com/gravwell/gravwell/v3/gwcli/connection"
-	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
-	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffolddelete"
-	"slices"
-	"strings"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/resolve"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldconfirm"
+
+	"github.com/spf13/pflag"
+)
 
-	"github.com/gravwell/gravwell/v3/client/types"
+const (
+	use   string = "delete"
+	short string = "delete a macro"
+	long  string = "Deletes a macro, given its name.\n" +
+		"--yes skips confirmation and is required in script mode."
 )
 
+var aliases []string = []string{"del", "remove", "rm"}
+
 func NewMacroDeleteAction() action.Pair {
-	return scaffolddelete.NewDeleteAction("macro", "macros", del,
-		func() ([]scaffolddelete.Item[uint64], error) {
-			ms, err := connection.Client.GetUserGroupsMacros()
-			if err != nil {
-				return nil, err
-			}
-			slices.SortFunc(ms, func(m1, m2 types.SearchMacro) int {
-				return strings.Compare(m1.Name, m2.Name)
-			})
-			var items = make([]scaffolddelete.Item[uint64], len(ms))
-			for i, m := range ms {
-				items[i] = scaffolddelete.NewItem(
-					m.Name,
-					fmt.Sprintf("Expansion: '%v'\n%v",
-						stylesheet.Header2Style.Render(m.Expansion), m.Description),
-					m.ID)
-			}
-			return items, nil
-		})
+	p := scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, nil)
+	p.Action.Example = "./gwcli macros delete WEBLOGS --yes"
+	p.Action.ValidArgsFunction = resolve.Complete
+	return p
 }
 
-func del(dryrun bool, id uint64) error {
-	if dryrun {
-		_, err := connection.Client.GetMacro(id)
-		return err
+func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+	if fs.NArg() != 1 {
+		return p, "exactly one macro name is required", nil
+	}
+	m, err := resolve.Lookup(fs.Arg(0))
+	if err != nil {
+		return p, "", err
+	}
+	p.Summary = fmt.Sprintf("Macro %v (%v scope, expands to %q) will be deleted.\n",
+		m.Name, resolve.Scope(m), m.Expansion)
+	p.Commit = func() (string, error) {
+		if err := connection.Client.DeleteMacro(m.ID); err != nil {
+			return "", fmt.Errorf("failed to delete %v: %w", m.Name, err)
+		}
+		return fmt.Sprintf("Deleted macro %v.", m.Name), nil
 	}
-	return connection.Client.DeleteMacro(id)
+	return
 }
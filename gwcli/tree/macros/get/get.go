@@ -0,0 +1,66 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package get implements an action for displaying a single macro.
+package get
+
+import (
+	"encoding/json"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/resolve"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "get"
+	short string = "display a macro"
+	long  string = "Displays the expansion, scope, and owner of a macro, given its name."
+)
+
+var aliases []string = []string{"show"}
+
+func NewMacroGetAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli macros get WEBLOGS --json"
+	p.Action.ValidArgsFunction = resolve.Complete
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.Bool(ft.Name.JSON, false, ft.Usage.JSON)
+	return fs
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 1 {
+		return "exactly one macro name is required", nil
+	}
+	m, err := resolve.Lookup(fs.Arg(0))
+	if err != nil {
+		return err.Error(), nil
+	}
+	if js, err := fs.GetBool(ft.Name.JSON); err != nil {
+		clilog.LogFlagFailedGet(ft.Name.JSON, err)
+	} else if js {
+		b, err := json.Marshal(m)
+		if err != nil {
+			clilog.Writer.Errorf("Failed to marshal macro: %v", err)
+			return err.Error(), nil
+		}
+		return string(b), nil
+	}
+	return resolve.Describe(m, resolve.Owner(resolve.Owners(), m.UID)), nil
+}
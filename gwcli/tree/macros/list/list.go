@@ -9,28 +9,56 @@
 package list
 
 import (
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/gravwell/gravwell/v3/gwcli/action"
 	"github.com/gravwell/gravwell/v3/gwcli/clilog"
 	"github.com/gravwell/gravwell/v3/gwcli/connection"
 	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/resolve"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldlist"
 
 	grav "github.com/gravwell/gravwell/v3/client"
-	"github.com/spf13/pflag"
-
 	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/pflag"
 )
 
 var (
 	short string = "list your macros"
 	long  string = "lists all macros associated to your user, a group," +
-		"or the system itself"
-	defaultColumns []string = []string{"ID", "Name", "Description", "Expansion"}
+		"or the system itself.\n" +
+		"Expansions are truncated unless --full is given."
+	defaultColumns []string = []string{"Name", "Expansion", "Scope", "Owner"}
+	verboseColumns []string = []string{"ID", "Name", "Expansion", "Scope", "Owner", "Description",
+		"Labels", "LastUpdated"}
 )
 
+const (
+	flagFull = "full"
+
+	// longest expansion displayed without --full, including the ellipsis
+	truncateLen = 60
+)
+
+// a single macro, as displayed
+type macro struct {
+	ID          uint64
+	Name        string
+	Expansion   string
+	Scope       string
+	Owner       string
+	Description string
+	Labels      []string
+	LastUpdated time.Time
+}
+
 func NewMacroListAction() action.Pair {
-	return scaffoldlist.NewListAction("", short, long, defaultColumns,
-		types.SearchMacro{}, listMacros, flags)
+	p := scaffoldlist.NewListAction("", short, long, defaultColumns,
+		macro{}, listMacros, flags, scaffoldlist.WithVerboseColumns(verboseColumns))
+	p.Action.Example = "./gwcli macros list --full --json"
+	return p
 }
 
 func flags() pflag.FlagSet {
@@ -39,10 +67,44 @@ func flags() pflag.FlagSet {
 		"Ignored if you are not an admin.\n"+
 		"Supersedes --group.")
 	addtlFlags.Int32("group", 0, "Fetches all macros shared with the given group id.")
+	addtlFlags.Bool(flagFull, false, "display expansions in their entirety.")
 	return addtlFlags
 }
 
-func listMacros(c *grav.Client, fs *pflag.FlagSet) ([]types.SearchMacro, error) {
+func listMacros(c *grav.Client, fs *pflag.FlagSet) ([]macro, error) {
+	ms, err := fetch(c, fs)
+	if err != nil {
+		return nil, err
+	}
+	full, err := fs.GetBool(flagFull)
+	if err != nil {
+		clilog.LogFlagFailedGet(flagFull, err)
+	}
+	owners := resolve.Owners()
+	macros := make([]macro, len(ms))
+	for i, m := range ms {
+		exp := m.Expansion
+		if !full {
+			exp = truncate(exp)
+		}
+		macros[i] = macro{
+			ID:          m.ID,
+			Name:        m.Name,
+			Expansion:   exp,
+			Scope:       resolve.Scope(m),
+			Owner:       resolve.Owner(owners, m.UID),
+			Description: m.Description,
+			Labels:      m.Labels,
+			LastUpdated: m.LastUpdated,
+		}
+	}
+	slices.SortFunc(macros, func(m1, m2 macro) int {
+		return strings.Compare(m1.Name, m2.Name)
+	})
+	return macros, nil
+}
+
+func fetch(c *grav.Client, fs *pflag.FlagSet) ([]types.SearchMacro, error) {
 	if all, err := fs.GetBool(ft.Name.ListAll); err != nil {
 		clilog.LogFlagFailedGet(ft.Name.ListAll, err)
 	} else if all {
@@ -56,3 +118,12 @@ func listMacros(c *grav.Client, fs *pflag.FlagSet) ([]types.SearchMacro, error)
 
 	return c.GetUserMacros(connection.MyInfo.UID)
 }
+
+// truncate shortens an expansion to a single line of at most truncateLen runes
+func truncate(exp string) string {
+	exp = strings.Join(strings.Fields(exp), " ")
+	if r := []rune(exp); len(r) > truncateLen {
+		return string(r[:truncateLen-3]) + "..."
+	}
+	return exp
+}
@@ -0,0 +1,36 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package list
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		exp  string
+		want string
+	}{
+		{"tag=apache", "tag=apache"},
+		{"tag=apache\n\t| grep  foo", "tag=apache | grep foo"},
+		{strings.Repeat("a", truncateLen), strings.Repeat("a", truncateLen)},
+		{strings.Repeat("a", truncateLen+1), strings.Repeat("a", truncateLen-3) + "..."},
+		{strings.Repeat("é", truncateLen+1), strings.Repeat("é", truncateLen-3) + "..."},
+	}
+	for i, tt := range tests {
+		got := truncate(tt.exp)
+		if got != tt.want {
+			t.Errorf("%d: truncate(%q) = %q, want %q", i, tt.exp, got, tt.want)
+		} else if utf8.RuneCountInString(got) > truncateLen {
+			t.Errorf("%d: %q is longer than %d runes", i, got, truncateLen)
+		}
+	}
+}
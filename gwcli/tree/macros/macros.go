@@ -13,7 +13,9 @@ import (
 	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/create"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/delete"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/edit"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/get"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/list"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/set"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 
 	"github.com/spf13/cobra"
@@ -32,5 +34,7 @@ func NewMacrosNav() *cobra.Command {
 		[]action.Pair{list.NewMacroListAction(),
 			create.NewMacroCreateAction(),
 			delete.NewMacroDeleteAction(),
-			edit.NewMacroEditAction()})
+			edit.NewMacroEditAction(),
+			get.NewMacroGetAction(),
+			set.NewMacroSetAction()})
 }
@@ -0,0 +1,125 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package resolve provides the name resolution and scope helpers shared by the macro actions.
+package resolve
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+// Scopes a macro may be visible in.
+const (
+	ScopeGlobal = "global"
+	ScopeGroup  = "group"
+	ScopeUser   = "user"
+)
+
+// Find fetches the macro with the given name from those available to the user, if there is one.
+// Macro names are case-insensitive.
+func Find(name string) (m types.SearchMacro, found bool, err error) {
+	ms, err := connection.Client.GetUserGroupsMacros()
+	if err != nil {
+		return m, false, err
+	}
+	for _, v := range ms {
+		if !strings.EqualFold(v.Name, name) {
+			continue
+		} else if found {
+			// prefer the user's own macro over one shared with them
+			if v.UID != connection.MyInfo.UID {
+				continue
+			}
+		}
+		m, found = v, true
+	}
+	return m, found, nil
+}
+
+// Lookup is Find, erroring if the macro does not exist.
+func Lookup(name string) (types.SearchMacro, error) {
+	m, found, err := Find(name)
+	if err != nil {
+		return m, err
+	} else if !found {
+		return m, errors.New("no macro named " + strings.ToUpper(name) + " was found")
+	}
+	return m, nil
+}
+
+// Scope describes who a macro is visible to.
+func Scope(m types.SearchMacro) string {
+	if m.Global {
+		return ScopeGlobal
+	} else if len(m.GIDs) > 0 {
+		return ScopeGroup
+	}
+	return ScopeUser
+}
+
+// Owners maps UIDs to usernames.
+// Failing to fetch the user list is not fatal; Owner falls back to displaying the UID.
+func Owners() map[int32]string {
+	uds, err := connection.Client.GetUserList()
+	if err != nil {
+		return nil
+	}
+	mp := make(map[int32]string, len(uds))
+	for _, ud := range uds {
+		mp[ud.UID] = ud.User
+	}
+	return mp
+}
+
+// Owner returns the username of the given UID, or the UID itself if it is unknown.
+func Owner(owners map[int32]string, uid int32) string {
+	if name, ok := owners[uid]; ok {
+		return name
+	}
+	return strconv.FormatInt(int64(uid), 10)
+}
+
+// Describe renders a macro for human consumption.
+func Describe(m types.SearchMacro, owner string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v (ID %v)\n", m.Name, m.ID)
+	fmt.Fprintf(&sb, "Expansion: %v\n", m.Expansion)
+	if m.Description != "" {
+		fmt.Fprintf(&sb, "Description: %v\n", m.Description)
+	}
+	fmt.Fprintf(&sb, "Scope: %v\n", Scope(m))
+	fmt.Fprintf(&sb, "Owner: %v\n", owner)
+	if len(m.Labels) > 0 {
+		fmt.Fprintf(&sb, "Labels: %v\n", strings.Join(m.Labels, ", "))
+	}
+	if !m.LastUpdated.IsZero() {
+		fmt.Fprintf(&sb, "Last Updated: %v\n", m.LastUpdated.Format("2006-01-02 15:04:05 MST"))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Complete offers the names of the macros available to the user.
+var Complete = treeutils.CompleteFrom(func() ([]string, error) {
+	ms, err := connection.Client.GetUserGroupsMacros()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(ms))
+	for i, m := range ms {
+		names[i] = m.Name
+	}
+	return names, nil
+})
@@ -0,0 +1,100 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package set implements an action for creating or updating a macro in one step.
+package set
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/macros/resolve"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "set"
+	short string = "create or update a macro"
+	long  string = "Sets the expansion of the named macro, creating it if it does not exist.\n" +
+		"Macro names are upper-cased.\n" +
+		"--global makes the macro visible to every user and is admin-only; " +
+		"--global=false returns it to your own scope. " +
+		"The scope of an existing macro is unchanged unless --global is given."
+)
+
+var aliases []string = []string{}
+
+const flagGlobal = "global"
+
+func NewMacroSetAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli macros set WEBLOGS \"tag=apache,nginx\" --description \"all web server logs\""
+	p.Action.ValidArgsFunction = resolve.Complete
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.String(ft.Name.Desc, "", ft.Usage.Desc("macro")+"\nLeft unchanged on existing macros if omitted.")
+	fs.Bool(flagGlobal, false, "share the macro with every user. Admin-only.")
+	return fs
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 2 {
+		return "a macro name and expansion are required", nil
+	}
+	name, exp := strings.ToUpper(strings.TrimSpace(fs.Arg(0))), fs.Arg(1)
+	if err := types.CheckMacroName(name); err != nil {
+		return err.Error(), nil
+	} else if strings.TrimSpace(exp) == "" {
+		return "expansion cannot be empty", nil
+	}
+
+	m, found, err := resolve.Find(name)
+	if err != nil {
+		return err.Error(), nil
+	} else if !found {
+		m = types.SearchMacro{Name: name}
+	}
+	m.Expansion = exp
+	if fs.Changed(ft.Name.Desc) {
+		if m.Description, err = fs.GetString(ft.Name.Desc); err != nil {
+			clilog.LogFlagFailedGet(ft.Name.Desc, err)
+		}
+	}
+	if fs.Changed(flagGlobal) {
+		if m.Global, err = fs.GetBool(flagGlobal); err != nil {
+			clilog.LogFlagFailedGet(flagGlobal, err)
+		}
+		if m.Global && !connection.MyInfo.Admin {
+			return "only admins may create global macros", nil
+		}
+	}
+
+	if found {
+		if err := connection.Client.UpdateMacro(m); err != nil {
+			return fmt.Sprintf("failed to update %v: %v", name, err), nil
+		}
+		return fmt.Sprintf("Updated macro %v (ID %v).", name, m.ID), nil
+	}
+	id, err := connection.Client.AddMacro(m)
+	if err != nil {
+		return fmt.Sprintf("failed to create %v: %v", name, err), nil
+	}
+	return fmt.Sprintf("Created macro %v (ID %v).", name, id), nil
+}
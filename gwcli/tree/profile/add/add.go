@@ -0,0 +1,102 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package add implements an action for creating or updating a connection profile.
+package add
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "add"
+	short string = "add or update a connection profile"
+	long  string = "Saves a named profile for the given --server.\n" +
+		"The login token is saved to the profile the next time you log in with it, " +
+		"unless one is given via --token.\n" +
+		"Updating a profile keeps its token unless the server changes."
+)
+
+var aliases []string = []string{"set"}
+
+func NewProfileAddAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli profile add staging --server gravwell.staging.example.com:443 --default"
+	treeutils.SkipLogin(p.Action)
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.String("server", "", "<host>:<port> of the instance this profile connects to.")
+	fs.Bool("insecure", false, "do not use HTTPS and do not enforce certs.")
+	fs.String("token", "", "login token to save to the profile.")
+	fs.Bool("default", false, "make this the default profile.")
+	return fs
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	name, err := profiles.NameFromArgs(fs.Args())
+	if err != nil {
+		return err.Error(), nil
+	}
+	var (
+		p   profiles.Profile
+		def bool
+	)
+	if p.Server, err = fs.GetString("server"); err != nil {
+		clilog.LogFlagFailedGet("server", err)
+		return err.Error(), nil
+	} else if p.Insecure, err = fs.GetBool("insecure"); err != nil {
+		clilog.LogFlagFailedGet("insecure", err)
+		return err.Error(), nil
+	} else if p.Token, err = fs.GetString("token"); err != nil {
+		clilog.LogFlagFailedGet("token", err)
+		return err.Error(), nil
+	} else if def, err = fs.GetBool("default"); err != nil {
+		clilog.LogFlagFailedGet("default", err)
+		return err.Error(), nil
+	}
+	p.Server, p.Token = strings.TrimSpace(p.Server), strings.TrimSpace(p.Token)
+
+	s, err := profiles.Load()
+	if err != nil {
+		clilog.Writer.Error(err.Error())
+		return err.Error(), nil
+	}
+	// a token is only valid for the server that issued it
+	if prev, err := s.Get(name); err == nil && p.Token == "" && prev.Server == p.Server {
+		p.Token = prev.Token
+	}
+	if err = s.Set(name, p); err != nil {
+		return err.Error(), nil
+	}
+	if def {
+		s.Default = name
+	}
+	if err = s.Save(); err != nil {
+		clilog.Writer.Errorf("failed to save profiles: %v", err)
+		return fmt.Sprintf("failed to save profile: %v", err), nil
+	}
+	clilog.Writer.Infof("Saved profile %v (server %v)", name, p.Server)
+	if def {
+		return fmt.Sprintf("Saved default profile %v", name), nil
+	}
+	return fmt.Sprintf("Saved profile %v", name), nil
+}
@@ -0,0 +1,68 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package list implements an action for listing the saved connection profiles.
+package list
+
+import (
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldlist"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	grav "github.com/gravwell/gravwell/v3/client"
+	"github.com/spf13/pflag"
+)
+
+const (
+	short string = "list saved connection profiles"
+	long  string = "Lists the saved profiles and the servers they connect to.\n" +
+		"Tokens are never displayed; HasToken reports whether one is saved."
+)
+
+var defaultColumns []string = []string{"Name", "Server", "Insecure", "Default", "HasToken"}
+
+// a profile as displayed, sans token
+type profile struct {
+	Name     string
+	Server   string
+	Insecure bool
+	Default  bool
+	HasToken bool
+}
+
+func NewProfileListAction() action.Pair {
+	p := scaffoldlist.NewListAction("", short, long, defaultColumns, profile{}, list, nil)
+	treeutils.SkipLogin(p.Action)
+	return p
+}
+
+func list(_ *grav.Client, _ *pflag.FlagSet) ([]profile, error) {
+	s, err := profiles.Load()
+	if err != nil {
+		return nil, err
+	}
+	return summarize(s), nil
+}
+
+// summarize converts the store into displayable profiles, ordered by name
+func summarize(s profiles.Store) []profile {
+	names := s.Names()
+	ps := make([]profile, len(names))
+	for i, n := range names {
+		p := s.Profiles[n]
+		ps[i] = profile{
+			Name:     n,
+			Server:   p.Server,
+			Insecure: p.Insecure,
+			Default:  n == s.Default,
+			HasToken: p.Token != "",
+		}
+	}
+	return ps
+}
@@ -0,0 +1,44 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package list
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+	"github.com/gravwell/gravwell/v3/utils/weave"
+)
+
+func TestSummarize(t *testing.T) {
+	s := profiles.Store{
+		Default: "prod",
+		Profiles: map[string]profiles.Profile{
+			"staging": {Server: "stage:443", Token: "secret-token"},
+			"prod":    {Server: "prod:443", Insecure: true},
+		},
+	}
+	got := summarize(s)
+	want := []profile{
+		{Name: "prod", Server: "prod:443", Insecure: true, Default: true},
+		{Name: "staging", Server: "stage:443", HasToken: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("summarize() = %+v, want %+v", got, want)
+	}
+	// the token must not be reachable by any column
+	cols, err := weave.StructFields(profile{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := weave.ToCSV(got, cols); strings.Contains(out, "secret-token") {
+		t.Fatalf("token was output: %s", out)
+	}
+}
@@ -0,0 +1,42 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package profile contains the actions for managing saved connection profiles.
+package profile
+
+import (
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/profile/add"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/profile/list"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/profile/remove"
+	profileuse "github.com/gravwell/gravwell/v3/gwcli/tree/profile/use"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	use   string = "profile"
+	short string = "manage saved connection profiles"
+	long  string = "Profiles save the server of a Gravwell instance and your login token for it, " +
+		"so you can switch between instances via --profile rather than re-passing connection flags.\n" +
+		"The default profile (set via `use`) is used whenever --profile is not given.\n" +
+		"Passwords are never saved."
+)
+
+var aliases []string = []string{"profiles"}
+
+func NewProfileNav() *cobra.Command {
+	return treeutils.GenerateNav(use, short, long, aliases, nil,
+		[]action.Pair{
+			add.NewProfileAddAction(),
+			list.NewProfileListAction(),
+			profileuse.NewProfileUseAction(),
+			remove.NewProfileRemoveAction(),
+		})
+}
@@ -0,0 +1,61 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package remove implements an action for deleting a connection profile.
+package remove
+
+import (
+	"fmt"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "remove"
+	short string = "delete a connection profile"
+	long  string = "Deletes the named profile and its saved token.\n" +
+		"If it was the default profile, there will be no default until another is selected."
+)
+
+var aliases []string = []string{"rm", "delete"}
+
+func NewProfileRemoveAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run,
+		func() pflag.FlagSet { return pflag.FlagSet{} }) // parse the profile name argument
+	p.Action.Example = "./gwcli profile remove staging"
+	treeutils.SkipLogin(p.Action)
+	return p
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	name, err := profiles.NameFromArgs(fs.Args())
+	if err != nil {
+		return err.Error(), nil
+	}
+	s, err := profiles.Load()
+	if err != nil {
+		clilog.Writer.Error(err.Error())
+		return err.Error(), nil
+	}
+	if err = s.Remove(name); err != nil {
+		return err.Error(), nil
+	}
+	if err = s.Save(); err != nil {
+		clilog.Writer.Errorf("failed to save profiles: %v", err)
+		return fmt.Sprintf("failed to save profiles: %v", err), nil
+	}
+	return fmt.Sprintf("Removed profile %v", name), nil
+}
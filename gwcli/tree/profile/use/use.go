@@ -0,0 +1,61 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package use implements an action for selecting the default connection profile.
+package use
+
+import (
+	"fmt"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "use"
+	short string = "set the default connection profile"
+	long  string = "Sets the profile gwcli connects with when --profile is not given.\n" +
+		"Takes effect on the next invocation of gwcli."
+)
+
+var aliases []string = []string{"default"}
+
+func NewProfileUseAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run,
+		func() pflag.FlagSet { return pflag.FlagSet{} }) // parse the profile name argument
+	p.Action.Example = "./gwcli profile use staging"
+	treeutils.SkipLogin(p.Action)
+	return p
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	name, err := profiles.NameFromArgs(fs.Args())
+	if err != nil {
+		return err.Error(), nil
+	}
+	s, err := profiles.Load()
+	if err != nil {
+		clilog.Writer.Error(err.Error())
+		return err.Error(), nil
+	}
+	if err = s.Use(name); err != nil {
+		return err.Error(), nil
+	}
+	if err = s.Save(); err != nil {
+		clilog.Writer.Errorf("failed to save profiles: %v", err)
+		return fmt.Sprintf("failed to save profiles: %v", err), nil
+	}
+	return fmt.Sprintf("Profile %v is now the default", name), nil
+}
@@ -0,0 +1,266 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+Package definition provides the portable, file-based form of a scheduled search shared by the
+scheduled query actions.
+
+A Definition holds only what a user authors (name, schedule, query, etc), never instance state
+(IDs, owners, last run results), so a definition exported from one instance via `get` can be kept
+in version control and re-imported via `import`, on the same or another instance.
+Definitions are written as YAML or JSON and read from either.
+
+Scheduled searches are identified by name, numeric ID, or GUID, resolved via Lookup.
+*/
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	"github.com/google/uuid"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is the user-authored portion of a scheduled search.
+// Exactly one of Query, Reference, Script, or Flow must be set.
+type Definition struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Schedule    string   `json:"schedule" yaml:"schedule"` // cron spec
+	Timezone    string   `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	Disabled    bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Backfill    bool     `json:"backfill,omitempty" yaml:"backfill,omitempty"`
+
+	// searches
+	Query        string `json:"query,omitempty" yaml:"query,omitempty"`
+	Reference    string `json:"reference,omitempty" yaml:"reference,omitempty"` // saved query UUID
+	Duration     string `json:"duration,omitempty" yaml:"duration,omitempty"`   // lookback (ex: 1h)
+	SinceLastRun bool   `json:"since_last_run,omitempty" yaml:"since_last_run,omitempty"`
+	Offset       string `json:"offset,omitempty" yaml:"offset,omitempty"` // timeframe offset (ex: 5m)
+
+	// scripts
+	Script         string `json:"script,omitempty" yaml:"script,omitempty"`
+	ScriptLanguage string `json:"script_language,omitempty" yaml:"script_language,omitempty"`
+
+	// flows
+	Flow string `json:"flow,omitempty" yaml:"flow,omitempty"`
+}
+
+// FromSearch extracts the definition of an existing scheduled search.
+func FromSearch(ss types.ScheduledSearch) Definition {
+	d := Definition{
+		Name:        ss.Name,
+		Description: ss.Description,
+		Labels:      ss.Labels,
+		Schedule:    ss.Schedule,
+		Timezone:    ss.Timezone,
+		Disabled:    ss.Disabled,
+		Backfill:    ss.BackfillEnabled,
+	}
+	switch {
+	case ss.Flow != "":
+		d.Flow = ss.Flow
+	case ss.Script != "":
+		d.Script = ss.Script
+		d.ScriptLanguage = ss.ScriptLanguage.String()
+	default:
+		// on a GET, the query of a referenced search is populated alongside the reference
+		if ss.SearchReference != uuid.Nil {
+			d.Reference = ss.SearchReference.String()
+		} else {
+			d.Query = ss.SearchString
+		}
+		d.SinceLastRun = ss.SearchSinceLastRun
+		if !ss.SearchSinceLastRun && ss.Duration != 0 {
+			d.Duration = seconds(ss.Duration).String()
+		}
+		if ss.TimeframeOffset != 0 {
+			d.Offset = seconds(ss.TimeframeOffset).String()
+		}
+	}
+	return d
+}
+
+// seconds converts a (negative) count of seconds into a positive duration
+func seconds(s int64) time.Duration {
+	if s < 0 {
+		s = -s
+	}
+	return time.Duration(s) * time.Second
+}
+
+// Apply validates the definition and sets it on the given scheduled search.
+// Fields not covered by a definition (ID, owner, groups, etc) are left untouched, so an existing
+// search may be passed to update it in place.
+func (d Definition) Apply(ss *types.ScheduledSearch) error {
+	if d.Name == "" {
+		return errors.New("name is required")
+	} else if d.Schedule == "" {
+		return errors.New("schedule is required")
+	}
+	var kinds int
+	for _, s := range []string{d.Query, d.Reference, d.Script, d.Flow} {
+		if s != "" {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errors.New("exactly one of query, reference, script, or flow is required")
+	}
+
+	ss.Name = d.Name
+	ss.Description = d.Description
+	ss.Labels = d.Labels
+	ss.Schedule = d.Schedule
+	ss.Timezone = d.Timezone
+	ss.Disabled = d.Disabled
+	ss.BackfillEnabled = d.Backfill
+
+	// clear out whatever kind the search previously was
+	ss.SearchString, ss.SearchReference = "", uuid.Nil
+	ss.Duration, ss.SearchSinceLastRun, ss.TimeframeOffset = 0, false, 0
+	ss.Script, ss.ScriptLanguage = "", types.ScriptAnko
+	ss.Flow = ""
+
+	switch {
+	case d.Flow != "":
+		ss.ScheduledType = types.ScheduledTypeFlow
+		ss.Flow = d.Flow
+	case d.Script != "":
+		ss.ScheduledType = types.ScheduledTypeScript
+		ss.Script = d.Script
+		if d.ScriptLanguage != "" {
+			lang, err := types.ParseScriptLang(d.ScriptLanguage)
+			if err != nil {
+				return fmt.Errorf("script_language %q: %w", d.ScriptLanguage, err)
+			}
+			ss.ScriptLanguage = lang
+		}
+	default:
+		ss.ScheduledType = types.ScheduledTypeSearch
+		if d.Reference != "" {
+			ref, err := uuid.Parse(d.Reference)
+			if err != nil {
+				return fmt.Errorf("reference %q is not a UUID", d.Reference)
+			}
+			ss.SearchReference = ref
+		} else {
+			ss.SearchString = d.Query
+		}
+		ss.SearchSinceLastRun = d.SinceLastRun
+		if d.Duration != "" {
+			dur, err := parseLookback("duration", d.Duration)
+			if err != nil {
+				return err
+			}
+			ss.Duration = dur
+		} else if !d.SinceLastRun {
+			return errors.New("duration is required unless since_last_run is set")
+		}
+		if d.Offset != "" {
+			off, err := parseLookback("offset", d.Offset)
+			if err != nil {
+				return err
+			}
+			ss.TimeframeOffset = off
+		}
+	}
+	return nil
+}
+
+// parseLookback parses a duration into the negative seconds the scheduled search API expects.
+// The sign of the given duration is ignored.
+func parseLookback(field, s string) (int64, error) {
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%v %q is not a duration (ex: 1h30m)", field, s)
+	} else if dur < 0 {
+		dur = -dur
+	}
+	if dur < time.Second {
+		return 0, fmt.Errorf("%v %q must be at least 1s", field, s)
+	}
+	return -int64(dur / time.Second), nil
+}
+
+// Marshal encodes the definition as YAML or, if asJSON, as indented JSON.
+func (d Definition) Marshal(asJSON bool) ([]byte, error) {
+	if asJSON {
+		return json.MarshalIndent(d, "", "  ")
+	}
+	return yaml.Marshal(d)
+}
+
+// Parse decodes a definition from YAML or JSON, rejecting unknown fields.
+func Parse(b []byte) (d Definition, err error) {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err = dec.Decode(&d); err != nil {
+		return d, fmt.Errorf("failed to parse definition: %w", err)
+	}
+	return d, nil
+}
+
+// Lookup fetches a scheduled search by numeric ID, GUID, or name.
+// Names must be unique among the searches available to the user.
+func Lookup(id string) (types.ScheduledSearch, error) {
+	if i, err := strconv.ParseInt(id, 10, 32); err == nil {
+		return connection.Client.GetScheduledSearch(int32(i))
+	}
+	if u, err := uuid.Parse(id); err == nil {
+		return connection.Client.GetScheduledSearch(u)
+	}
+	ss, found, err := Find(id)
+	if err != nil {
+		return ss, err
+	} else if !found {
+		return ss, errors.New("no scheduled search named " + id + " was found")
+	}
+	return ss, nil
+}
+
+// Find fetches the scheduled search with the given name, if there is one.
+func Find(name string) (ss types.ScheduledSearch, found bool, err error) {
+	list, err := connection.Client.GetScheduledSearchList()
+	if err != nil {
+		return ss, false, err
+	}
+	for _, s := range list {
+		if s.Name != name {
+			continue
+		} else if found {
+			return ss, false, fmt.Errorf("multiple scheduled searches are named %v; "+
+				"use its ID or GUID instead", name)
+		}
+		ss, found = s, true
+	}
+	return ss, found, nil
+}
+
+// Complete offers the names of the scheduled searches available to the user.
+var Complete = treeutils.CompleteFrom(func() ([]string, error) {
+	list, err := connection.Client.GetScheduledSearchList()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(list))
+	for i, s := range list {
+		names[i] = s.Name
+	}
+	return names, nil
+})
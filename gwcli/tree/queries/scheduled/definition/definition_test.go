@@ -0,0 +1,99 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package definition
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+func TestRoundTrip(t *testing.T) {
+	orig := types.ScheduledSearch{
+		ID:              5,
+		Owner:           2,
+		Name:            "nightly",
+		Description:     "counts things",
+		Labels:          []string{"a", "b"},
+		Schedule:        "0 1 * * *",
+		Timezone:        "America/Denver",
+		ScheduledType:   types.ScheduledTypeSearch,
+		SearchString:    "tag=gravwell count",
+		Duration:        -3600,
+		TimeframeOffset: -300,
+	}
+	d := FromSearch(orig)
+	if d.Duration != "1h0m0s" || d.Offset != "5m0s" {
+		t.Fatalf("bad lookback: %q %q", d.Duration, d.Offset)
+	}
+	for _, asJSON := range []bool{false, true} {
+		b, err := d.Marshal(asJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := Parse(b)
+		if err != nil {
+			t.Fatalf("failed to parse (json: %v): %v\n%s", asJSON, err, b)
+		} else if !reflect.DeepEqual(d, parsed) {
+			t.Fatalf("definition changed in transit (json: %v):\n%+v\n%+v", asJSON, d, parsed)
+		}
+		// apply onto a search of a different kind to ensure the old kind is cleared
+		ss := types.ScheduledSearch{ID: 5, Owner: 2, ScheduledType: types.ScheduledTypeScript, Script: "x"}
+		if err := parsed.Apply(&ss); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(orig, ss) {
+			t.Fatalf("bad search after apply:\n%+v\n%+v", orig, ss)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse([]byte("name: x\nschedule: '* * * * *'\nquerry: tag=foo\n")); err == nil {
+		t.Fatal("failed to reject unknown field")
+	}
+	d, err := Parse([]byte(`{"name": "x", "schedule": "@hourly", "script": "println(1)", "script_language": "go"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ss types.ScheduledSearch
+	if err := d.Apply(&ss); err != nil {
+		t.Fatal(err)
+	} else if ss.ScheduledType != types.ScheduledTypeScript || ss.ScriptLanguage != types.ScriptGo {
+		t.Fatalf("bad script search: %+v", ss)
+	}
+}
+
+func TestApplyInvalid(t *testing.T) {
+	base := Definition{Name: "x", Schedule: "* * * * *", Query: "tag=foo", Duration: "1h"}
+	tests := map[string]func(d *Definition){
+		"no name":       func(d *Definition) { d.Name = "" },
+		"no schedule":   func(d *Definition) { d.Schedule = "" },
+		"no kind":       func(d *Definition) { d.Query = "" },
+		"two kinds":     func(d *Definition) { d.Flow = "{}" },
+		"no duration":   func(d *Definition) { d.Duration = "" },
+		"bad duration":  func(d *Definition) { d.Duration = "yesterday" },
+		"tiny duration": func(d *Definition) { d.Duration = "10ms" },
+		"bad reference": func(d *Definition) { d.Query, d.Reference = "", "nope" },
+		"bad language":  func(d *Definition) { d.Query, d.Script, d.ScriptLanguage = "", "x", "cobol" },
+	}
+	for name, mod := range tests {
+		d := base
+		mod(&d)
+		if err := d.Apply(&types.ScheduledSearch{}); err == nil {
+			t.Errorf("%v: failed to catch invalid definition", name)
+		}
+	}
+	// since_last_run makes duration optional
+	d := base
+	d.Duration, d.SinceLastRun = "", true
+	if err := d.Apply(&types.ScheduledSearch{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -10,45 +10,46 @@ package delete
 
 import (
 	"fmt"
+
 	"github.com/gravwell/gravwell/v3/gwcli/action"
 	"github.com/gravwell/gravwell/v3/gwcli/connection"
-	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffolddelete"
-	"math"
-	"slices"
-	"strings"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/definition"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldconfirm"
+
+	"github.com/spf13/pflag"
+)
 
-	"github.com/gravwell/gravwell/v3/client/types"
+const (
+	use   string = "delete"
+	short string = "delete a scheduled query"
+	long  string = "Deletes a scheduled query, given its name, ID, or GUID.\n" +
+		"--yes skips confirmation and is required in script mode."
 )
 
+var aliases []string = []string{"del", "remove", "rm"}
+
 func NewQueriesScheduledDeleteAction() action.Pair {
-	return scaffolddelete.NewDeleteAction(
-		"query", "queries", del, func() ([]scaffolddelete.Item[int32], error) {
-			ss, err := connection.Client.GetScheduledSearchList()
-			if err != nil {
-				return nil, err
-			}
-			// sort the results on name
-			slices.SortFunc(ss, func(m1, m2 types.ScheduledSearch) int {
-				return strings.Compare(m1.Name, m2.Name)
-			})
-			var items = make([]scaffolddelete.Item[int32], len(ss))
-			for i, ssi := range ss {
-				items[i] = scaffolddelete.NewItem[int32](ssi.Name,
-					fmt.Sprintf("%v\n(looks %v seconds into the past)",
-						ssi.SearchString, math.Abs(float64(ssi.Duration))),
-					ssi.ID)
-			}
-			return items, nil
-		})
+	p := scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, nil)
+	p.Action.Example = "./gwcli queries scheduled delete \"nightly report\" --yes"
+	p.Action.ValidArgsFunction = definition.Complete
+	return p
 }
 
-// deletes a scheduled search
-func del(dryrun bool, id int32) error {
-	if dryrun {
-		_, err := connection.Client.GetScheduledSearch(id)
-		return err
+func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+	if fs.NArg() != 1 {
+		return p, "exactly one scheduled query name, ID, or GUID is required", nil
 	}
-
-	return connection.Client.DeleteScheduledSearch(id)
-
+	ss, err := definition.Lookup(fs.Arg(0))
+	if err != nil {
+		return p, "", err
+	}
+	p.Summary = fmt.Sprintf("Scheduled query %v (ID %v, schedule %q) will be deleted.\n",
+		ss.Name, ss.ID, ss.Schedule)
+	p.Commit = func() (string, error) {
+		if err := connection.Client.DeleteScheduledSearch(ss.ID); err != nil {
+			return "", fmt.Errorf("failed to delete %v: %w", ss.Name, err)
+		}
+		return fmt.Sprintf("Deleted scheduled query %v.", ss.Name), nil
+	}
+	return
 }
@@ -0,0 +1,84 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package get implements an action for exporting the definition of a scheduled search.
+package get
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/definition"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "get"
+	short string = "export the definition of a scheduled query"
+	long  string = "Exports the definition of a scheduled query, given its name, ID, or GUID, as YAML " +
+		"(or JSON, via --json).\n" +
+		"The definition can be kept under version control and re-imported via the import action."
+)
+
+var aliases []string = []string{"export"}
+
+const outPerm = 0644
+
+func NewQueriesScheduledGetAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli queries scheduled get \"nightly report\" -o nightly.yaml"
+	p.Action.ValidArgsFunction = definition.Complete
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.Bool(ft.Name.JSON, false, "export the definition as JSON instead of YAML.")
+	fs.StringP(ft.Name.Output, "o", "", "file to write the definition to.\nTruncates the file.")
+	return fs
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 1 {
+		return "exactly one scheduled query name, ID, or GUID is required", nil
+	}
+	ss, err := definition.Lookup(fs.Arg(0))
+	if err != nil {
+		return err.Error(), nil
+	}
+	js, err := fs.GetBool(ft.Name.JSON)
+	if err != nil {
+		clilog.LogFlagFailedGet(ft.Name.JSON, err)
+	}
+	b, err := definition.FromSearch(ss).Marshal(js)
+	if err != nil {
+		clilog.Writer.Errorf("Failed to marshal scheduled search definition: %v", err)
+		return err.Error(), nil
+	}
+	out, err := fs.GetString(ft.Name.Output)
+	if err != nil {
+		clilog.LogFlagFailedGet(ft.Name.Output, err)
+	} else if out != "" {
+		if js {
+			b = append(b, '\n')
+		}
+		if err := os.WriteFile(out, b, outPerm); err != nil {
+			return fmt.Sprintf("failed to write %v: %v", out, err), nil
+		}
+		return fmt.Sprintf("Wrote the definition of %v to %v.", ss.Name, out), nil
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
@@ -0,0 +1,117 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package importer implements an action for creating or updating a scheduled search from a
+// definition file.
+package importer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/definition"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "import"
+	short string = "create or update a scheduled query from a definition file"
+	long  string = "Creates a scheduled query from a YAML or JSON definition file, as exported by the " +
+		"get action.\n" +
+		"If a scheduled query with the definition's name already exists, import fails unless " +
+		"--update is given, in which case the existing query is updated to match the definition."
+)
+
+var aliases []string = []string{"apply"}
+
+const flagUpdate = "update"
+
+func NewQueriesScheduledImportAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli queries scheduled import nightly.yaml --update"
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.Bool(flagUpdate, false, "update the scheduled query of the same name, if one exists.")
+	fs.Bool(ft.Name.Dryrun, false, ft.Usage.Dryrun)
+	return fs
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 1 {
+		return "exactly one definition file is required", nil
+	}
+	var update, dryrun bool
+	var err error
+	if update, err = fs.GetBool(flagUpdate); err != nil {
+		clilog.LogFlagFailedGet(flagUpdate, err)
+	}
+	if dryrun, err = fs.GetBool(ft.Name.Dryrun); err != nil {
+		clilog.LogFlagFailedGet(ft.Name.Dryrun, err)
+	}
+	s, err := importFile(fs.Arg(0), update, dryrun)
+	if err != nil {
+		return err.Error(), nil
+	}
+	return s, nil
+}
+
+// importFile creates or updates the scheduled search defined in the given file
+func importFile(pth string, update, dryrun bool) (string, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return "", err
+	}
+	d, err := definition.Parse(b)
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", pth, err)
+	}
+	ss, found, err := definition.Find(d.Name)
+	if err != nil {
+		return "", err
+	} else if found && !update {
+		return "", fmt.Errorf("a scheduled query named %v already exists (ID %v); "+
+			"use --%v to replace it", d.Name, ss.ID, flagUpdate)
+	}
+	if !found {
+		// TODO provide a means of selecting groups/permissions
+		ss = types.ScheduledSearch{Groups: []int32{connection.MyInfo.DefaultGID}}
+	}
+	if err := d.Apply(&ss); err != nil {
+		return "", fmt.Errorf("%v: %w", pth, err)
+	}
+
+	if found {
+		if dryrun {
+			return fmt.Sprintf("DRYRUN: scheduled query %v (ID %v) would have been updated",
+				ss.Name, ss.ID), nil
+		} else if err := connection.Client.UpdateScheduledSearch(ss); err != nil {
+			return "", fmt.Errorf("failed to update %v: %w", ss.Name, err)
+		}
+		return fmt.Sprintf("Updated scheduled query %v (ID %v).", ss.Name, ss.ID), nil
+	}
+	if dryrun {
+		return fmt.Sprintf("DRYRUN: scheduled query %v would have been created", ss.Name), nil
+	}
+	id, err := connection.Client.CreateScheduledSearchFromObject(ss)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %v: %w", ss.Name, err)
+	}
+	return fmt.Sprintf("Created scheduled query %v (ID %v).", ss.Name, id), nil
+}
@@ -26,7 +26,7 @@ import (
 var (
 	short          string   = "list scheduled queries"
 	long           string   = "prints out all scheduled queries."
-	defaultColumns []string = []string{"ID", "Name", "Description", "Duration", "Schedule"}
+	defaultColumns []string = []string{"ID", "Name", "Schedule", "LastRun", "LastError", "Owner"}
 )
 
 func NewScheduledQueriesListAction() action.Pair {
@@ -50,8 +50,8 @@ func listScheduledSearch(c *grav.Client, fs *pflag.FlagSet) ([]types.ScheduledSe
 	} else if all {
 		return c.GetAllScheduledSearches()
 	}
-	if untypedID, err := fs.GetString(ft.Name.ListAll); err != nil {
-		clilog.LogFlagFailedGet(ft.Name.ListAll, err)
+	if untypedID, err := fs.GetString(ft.Name.ID); err != nil {
+		clilog.LogFlagFailedGet(ft.Name.ID, err)
 	} else if untypedID != "" {
 		// attempt to parse as UUID first
 		if uuid, err := uuid.Parse(untypedID); err == nil {
@@ -0,0 +1,62 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package runnow implements an action for running a scheduled search immediately.
+package runnow
+
+import (
+	"fmt"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/definition"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "run-now"
+	short string = "run a scheduled query immediately"
+	long  string = "Asks the search agent to run a scheduled query once, immediately, given its " +
+		"name, ID, or GUID.\n" +
+		"Its schedule is unaffected. Check the result via list once the run completes."
+)
+
+var aliases []string = []string{"run"}
+
+func NewQueriesScheduledRunNowAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli queries scheduled run-now \"nightly report\""
+	p.Action.ValidArgsFunction = definition.Complete
+	return p
+}
+
+// run-now has no flags of its own, but basic actions only parse arguments from Mother if they
+// have a flagset
+func flags() pflag.FlagSet {
+	return pflag.FlagSet{}
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 1 {
+		return "exactly one scheduled query name, ID, or GUID is required", nil
+	}
+	ss, err := definition.Lookup(fs.Arg(0))
+	if err != nil {
+		return err.Error(), nil
+	}
+	// the search agent fires one-shot searches on its next check-in and then clears the flag
+	ss.OneShot = true
+	if err := connection.Client.UpdateScheduledSearch(ss); err != nil {
+		return fmt.Sprintf("failed to run %v: %v", ss.Name, err), nil
+	}
+	return fmt.Sprintf("Scheduled query %v (ID %v) will run momentarily.", ss.Name, ss.ID), nil
+}
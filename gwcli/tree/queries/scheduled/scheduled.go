@@ -13,7 +13,10 @@ import (
 	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/create"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/delete"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/edit"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/get"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/importer"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/list"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/queries/scheduled/runnow"
 
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 
@@ -34,5 +37,8 @@ func NewScheduledNav() *cobra.Command {
 			list.NewScheduledQueriesListAction(),
 			delete.NewQueriesScheduledDeleteAction(),
 			edit.NewQueriesScheduledEditAction(),
+			get.NewQueriesScheduledGetAction(),
+			importer.NewQueriesScheduledImportAction(),
+			runnow.NewQueriesScheduledRunNowAction(),
 		})
 }
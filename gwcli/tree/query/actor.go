@@ -22,6 +22,7 @@ import (
 	"github.com/gravwell/gravwell/v3/gwcli/connection"
 	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/query/datascope"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/qhistory"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -71,11 +72,19 @@ type query struct {
 		schedule schedule
 	}
 
+	// flag options that affect the search itself, but are not in the modifier view
+	rangeModifiers struct {
+		start time.Time // zero if unset
+		end   time.Time // zero if unset
+		limit uint64
+	}
+
 	focusedEditor bool
 
-	curSearch   *grav.Search // nil or ongoing/recently-completed search
-	searchDone  atomic.Bool  // waiting thread has returned
-	searchError chan error   // result to be fetched after SearchDone
+	curSearch      *grav.Search  // nil or ongoing/recently-completed search
+	searchDone     atomic.Bool   // waiting thread has returned
+	searchError    chan error    // result to be fetched after SearchDone
+	searchProgress atomic.Uint64 // results available so far, updated by the waiting thread
 
 	spnr  spinner.Model // wait spinner
 	scope tea.Model     // interactively display data
@@ -136,6 +145,7 @@ func (q *query) Update(msg tea.Msg) tea.Cmd {
 		return cmd
 	case inactive: // if inactive, bootstrap
 		q.mode = prompting
+		q.editor.loadHistory()
 		q.editor.ta.Focus()
 		q.focusedEditor = true
 		return textarea.Blink
@@ -149,7 +159,7 @@ func (q *query) Update(msg tea.Msg) tea.Cmd {
 				return cmd
 			}
 
-			results, tableMode, err := fetchResults(q.curSearch)
+			results, tableMode, err := fetchResults(q.curSearch, q.rangeModifiers.limit)
 			if err != nil {
 				q.editor.err = err.Error()
 				q.mode = prompting
@@ -222,8 +232,8 @@ func (q *query) View() string {
 	}
 
 	var blankOrSpnr string
-	if q.mode == waiting { // if waiting, show a spinner instead of help
-		blankOrSpnr = q.spnr.View()
+	if q.mode == waiting { // if waiting, show a spinner and the results found so far
+		blankOrSpnr = fmt.Sprintf("%s %d results found", q.spnr.View(), q.searchProgress.Load())
 	} else {
 		blankOrSpnr = "\n"
 	}
@@ -269,6 +279,7 @@ func (q *query) Reset() error {
 	// clear query fields
 	q.curSearch = nil
 	q.searchDone.Store(false)
+	q.searchProgress.Store(0)
 	q.scope = nil
 
 	localFS = initialLocalFlagSet()
@@ -300,6 +311,9 @@ func (q *query) SetArgs(_ *pflag.FlagSet, tokens []string) (string, tea.Cmd, err
 	q.flagModifiers.outfn = flags.outfn
 	q.flagModifiers.append = flags.append
 	q.flagModifiers.schedule = flags.schedule
+	q.rangeModifiers.start = flags.start
+	q.rangeModifiers.end = flags.end
+	q.rangeModifiers.limit = flags.limit
 
 	// TODO pull qry from referenceID, if given
 
@@ -338,15 +352,25 @@ func (q *query) submitQuery() tea.Cmd {
 		duration = defaultDuration
 	}
 
-	s, err := connection.StartQuery(qry, -duration)
+	start, end, err := timeRange(q.rangeModifiers.start, q.rangeModifiers.end, duration, time.Now())
 	if err != nil {
 		q.editor.err = err.Error()
 		return nil
 	}
+	s, err := connection.StartQueryRange(qry, start, end)
+	if err != nil {
+		q.editor.err = err.Error()
+		return nil
+	}
+	// only the query text is recorded, never the flags
+	if err := qhistory.Add(qry); err != nil {
+		clilog.Writer.Warnf("failed to record query history: %v", err)
+	}
+	q.editor.loadHistory()
 
 	// spin up a goroutine to wait on the search while we show a spinner
 	go func() {
-		err := connection.Client.WaitForSearch(s)
+		err := waitWithProgress(connection.Client, s, q.searchProgress.Store)
 		// notify we are done and buffer the error for retrieval
 		q.searchDone.Store(true)
 		q.searchError <- err
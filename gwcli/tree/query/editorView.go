@@ -11,11 +11,14 @@ package query
 /**
  * This file defines the editor view, which contains the query editor users can enter their search
  * string into.
+ * Previously submitted queries can be recalled into the editor from the persistent query history.
  */
 
 import (
 	"fmt"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
 	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/qhistory"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
@@ -27,6 +30,10 @@ type editorView struct {
 	ta   textarea.Model
 	err  string
 	keys []key.Binding
+
+	hist   []qhistory.Record // persisted queries, oldest first
+	recall int               // index of the recalled query; len(hist) while editing the draft
+	draft  string            // editor contents prior to recalling a query
 }
 
 func initialEdiorView(height, width uint) editorView {
@@ -46,6 +53,10 @@ func initialEdiorView(height, width uint) editorView {
 		key.NewBinding(
 			key.WithKeys("alt+enter"),
 			key.WithHelp("alt+enter", "submit query"),
+		),
+		key.NewBinding( // 1: recall
+			key.WithKeys("up", "down"),
+			key.WithHelp(stylesheet.UpDown, "recall query"),
 		)}
 
 	return ev
@@ -64,6 +75,12 @@ func (ev *editorView) update(msg tea.Msg) (cmd tea.Cmd, submit bool) {
 			} else {
 				return nil, true
 			}
+		case key.Matches(msg, ev.keys[1]): // recall, only from the first or last line
+			if msg.Type == tea.KeyUp && ev.ta.Line() == 0 && ev.recallQuery(-1) {
+				return nil, false
+			} else if msg.Type == tea.KeyDown && ev.ta.Line() == ev.ta.LineCount()-1 && ev.recallQuery(1) {
+				return nil, false
+			}
 		}
 	}
 	var t tea.Cmd
@@ -71,6 +88,35 @@ func (ev *editorView) update(msg tea.Msg) (cmd tea.Cmd, submit bool) {
 	return t, false
 }
 
+// loadHistory fetches the persisted queries for recall, resetting recall to the draft
+func (ev *editorView) loadHistory() {
+	rs, err := qhistory.Load()
+	if err != nil {
+		clilog.Writer.Warnf("failed to load query history: %v", err)
+	}
+	ev.hist, ev.recall, ev.draft = rs, len(rs), ""
+}
+
+// recallQuery replaces the editor contents with an older (-1) or newer (+1) query from the
+// history, returning false if there is none in that direction.
+// Moving past the newest query restores the draft.
+func (ev *editorView) recallQuery(dir int) bool {
+	i := ev.recall + dir
+	if i < 0 || i > len(ev.hist) {
+		return false
+	}
+	if ev.recall == len(ev.hist) {
+		ev.draft = ev.ta.Value()
+	}
+	ev.recall = i
+	if i == len(ev.hist) {
+		ev.ta.SetValue(ev.draft)
+	} else {
+		ev.ta.SetValue(ev.hist[i].Query)
+	}
+	return true
+}
+
 func (va *editorView) view() string {
 	return fmt.Sprintf("%s\n%s\n%s",
 		stylesheet.Header1Style.Render("Query:"),
@@ -0,0 +1,60 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package query
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/qhistory"
+)
+
+func TestRecallQuery(t *testing.T) {
+	qhistory.Path = filepath.Join(t.TempDir(), "query_history.json")
+	for _, q := range []string{"tag=a", "tag=b"} {
+		if err := qhistory.Add(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ev := initialEdiorView(6, 40)
+	ev.loadHistory()
+	ev.ta.SetValue("draft")
+
+	up, down := tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyDown}
+	steps := []struct {
+		msg tea.KeyMsg
+		exp string
+	}{
+		{up, "tag=b"},
+		{up, "tag=a"},
+		{up, "tag=a"}, // oldest, nothing further
+		{down, "tag=b"},
+		{down, "draft"},
+		{down, "draft"}, // newest, nothing further
+	}
+	for i, s := range steps {
+		if _, submit := ev.update(s.msg); submit {
+			t.Fatalf("%d: recall submitted the query", i)
+		} else if v := ev.ta.Value(); v != s.exp {
+			t.Fatalf("%d: bad editor value %q != %q", i, v, s.exp)
+		}
+	}
+
+	// up only recalls from the first line of a multi-line query
+	ev.ta.SetValue("tag=c\nlimit 1")
+	ev.update(up)
+	if v := ev.ta.Value(); v != "tag=c\nlimit 1" {
+		t.Fatalf("recalled from a later line: %q", v)
+	}
+	ev.update(up)
+	if v := ev.ta.Value(); v != "tag=b" {
+		t.Fatalf("failed to recall from the first line: %q", v)
+	}
+}
@@ -9,7 +9,10 @@
 package query
 
 import (
+	"errors"
+	"fmt"
 	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/uniques"
 	"strings"
 	"time"
 
@@ -18,6 +21,9 @@ import (
 
 type queryflags struct {
 	duration time.Duration
+	start    time.Time // zero if not given
+	end      time.Time // zero if not given
+	limit    uint64    // 0 for no limit
 	script   bool
 	json     bool
 	csv      bool
@@ -41,6 +47,15 @@ func transmogrifyFlags(fs *pflag.FlagSet) (queryflags, error) {
 	if qf.duration, err = fs.GetDuration("duration"); err != nil {
 		return qf, err
 	}
+	if qf.start, err = getTime(fs, "start"); err != nil {
+		return qf, err
+	}
+	if qf.end, err = getTime(fs, "end"); err != nil {
+		return qf, err
+	}
+	if qf.limit, err = fs.GetUint64("limit"); err != nil {
+		return qf, err
+	}
 	if qf.script, err = fs.GetBool(ft.Name.Script); err != nil {
 		// this will fail if mother is running, it is okay to swallow
 		qf.script = false
@@ -80,3 +95,39 @@ func transmogrifyFlags(fs *pflag.FlagSet) (queryflags, error) {
 	return qf, nil
 
 }
+
+// getTime parses the named timestamp flag, returning the zero time if it was not given
+func getTime(fs *pflag.FlagSet, name string) (time.Time, error) {
+	v, err := fs.GetString(name)
+	if err != nil {
+		return time.Time{}, err
+	} else if v = strings.TrimSpace(v); v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(uniques.SearchTimeFormat, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s must be an RFC3339 timestamp (ex: %s)",
+			name, time.Now().Truncate(time.Second).Format(time.RFC3339))
+	}
+	return t, nil
+}
+
+// timeRange returns the bounds the query should search over.
+// --start and --end take precedence over --duration, which is only used to derive a missing start.
+// A missing end is now.
+func timeRange(start, end time.Time, duration time.Duration, now time.Time) (time.Time, time.Time, error) {
+	if end.IsZero() {
+		end = now
+	}
+	if start.IsZero() {
+		if duration <= 0 {
+			return start, end, errors.New("duration must be positive")
+		}
+		start = end.Add(-duration)
+	}
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("start (%v) must be before end (%v)",
+			start.Format(uniques.SearchTimeFormat), end.Format(uniques.SearchTimeFormat))
+	}
+	return start, end, nil
+}
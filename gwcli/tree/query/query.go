@@ -25,6 +25,7 @@ package query
  */
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gravwell/gravwell/v3/gwcli/action"
 	"github.com/gravwell/gravwell/v3/gwcli/busywait"
@@ -61,6 +62,10 @@ const (
 		"functionality for downloading the results to a file or scheduling this query to run in " +
 		"the future" +
 		"\n" +
+		"With --script and no output file, results are streamed to stdout as they arrive, as " +
+		"text, or as CSV or JSON (one object per line) if --csv or --json is given.\n" +
+		"--limit caps the number of results returned.\n" +
+		"\n" +
 		"If --json or --csv is not given when outputting to a file (`-o`), the results will be " +
 		"text (if able) or an archive binary blob (if unable), depending on the query's render " +
 		"module.\n" +
@@ -79,7 +84,10 @@ var localFS pflag.FlagSet
 func NewQueryAction() action.Pair {
 	cmd := treeutils.NewActionCommand("query", "submit a query",
 		helpDesc,
-		[]string{"q", "search"}, run)
+		[]string{"q", "search"}, nil)
+	cmd.RunE = run
+	// errors are printed as they occur
+	cmd.SilenceErrors = true
 
 	localFS = initialLocalFlagSet()
 
@@ -96,6 +104,11 @@ func initialLocalFlagSet() pflag.FlagSet {
 	fs.DurationP("duration", "t", time.Hour*1,
 		"the historical timeframe from now the query should pour over.\n"+
 			"Ex: '1h' = the past hour, '5s500ms'= the previous 5 and a half seconds")
+	fs.String("start", "", "RFC3339 timestamp the query should start from.\n"+
+		"Takes precedence over --duration.")
+	fs.String("end", "", "RFC3339 timestamp the query should end at. Defaults to now.\n"+
+		"If --start is not given, the query covers --duration prior to the end.")
+	fs.Uint64("limit", 0, "maximum number of results to return. 0 returns all results.")
 	fs.StringP(ft.Name.Output, "o", "", ft.Usage.Output)
 	fs.Bool(ft.Name.Append, false, ft.Name.Append)
 	fs.Bool(ft.Name.JSON, false, ft.Usage.JSON)
@@ -113,14 +126,15 @@ func initialLocalFlagSet() pflag.FlagSet {
 
 //#region cobra command
 
-func run(cmd *cobra.Command, args []string) {
+// Returns an error if the query failed, causing non-interactive invocations to exit non-zero.
+func run(cmd *cobra.Command, args []string) error {
 	var err error
 
 	// fetch flags
 	flags, err := transmogrifyFlags(cmd.Flags())
 	if err != nil {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-		return
+		return err
 	}
 
 	// TODO pull qry from referenceID, if given
@@ -130,28 +144,37 @@ func run(cmd *cobra.Command, args []string) {
 	if qry == "" { // superfluous query
 		if flags.script { // fail out
 			clilog.Tee(clilog.INFO, cmd.OutOrStdout(), "query is empty. Exitting...\n")
-			return
+			return nil
 		}
 
 		// spawn mother
 		if err := mother.Spawn(cmd.Root(), cmd, args); err != nil {
 			clilog.Tee(clilog.CRITICAL, cmd.ErrOrStderr(),
 				"failed to spawn a mother instance: "+err.Error()+"\n")
+			return err
 		}
-		return
+		return nil
 	}
 
 	// branch on script mode
 	if flags.script {
-		runNonInteractive(cmd, flags, qry)
-		return
+		return runNonInteractive(cmd, flags, qry)
+	}
+	return runInteractive(cmd, flags, qry)
+}
+
+// startQuery submits the query over the time range described by the flags
+func startQuery(flags queryflags, qry string) (grav.Search, error) {
+	start, end, err := timeRange(flags.start, flags.end, flags.duration, time.Now())
+	if err != nil {
+		return grav.Search{}, err
 	}
-	runInteractive(cmd, flags, qry)
+	return connection.StartQueryRange(qry, start, end)
 }
 
 // run function with --script given, making it entirely independent of user input.
-// Results will be output to a file (if given) or dumped into stdout.
-func runNonInteractive(cmd *cobra.Command, flags queryflags, qry string) {
+// Results will be output to a file (if given) or streamed into stdout.
+func runNonInteractive(cmd *cobra.Command, flags queryflags, qry string) error {
 	var err error
 
 	if flags.schedule.cronfreq != "" { // check if it is a scheduled query
@@ -187,28 +210,46 @@ func runNonInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 		)
 		if invalid != "" { // bad parameters
 			clilog.Tee(clilog.INFO, cmd.ErrOrStderr(), invalid)
-			return
+			return errors.New(strings.TrimSpace(invalid))
 		} else if err != nil {
 			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
+			return err
 		}
 		clilog.Tee(clilog.INFO, cmd.OutOrStdout(),
 			fmt.Sprintf("Successfully scheduled query '%v' (ID: %v)\n", flags.schedule.name, id))
-		return
+		return nil
 	}
 
 	// submit the immediate query
 	var search grav.Search
-	if s, err := connection.StartQuery(qry, -flags.duration); err != nil {
+	if s, err := startQuery(flags, qry); err != nil {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-		return
+		return err
 	} else {
 		search = s
 	}
 
+	// stream results to stdout as they arrive, if able
+	if flags.outfn == "" && streamable(search.RenderMod) {
+		format := outputFormat(flags.json, flags.csv)
+		n, err := streamResults(cmd.OutOrStdout(), connection.Client, search, format, flags.limit)
+		if err != nil {
+			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
+			return err
+		} else if n == 0 && format == formatText {
+			fmt.Fprintln(cmd.OutOrStdout(), "no results to display")
+		}
+		return nil
+	}
+	if flags.limit > 0 && clilog.Active(clilog.WARN) {
+		// downloads always contain the full results
+		fmt.Fprint(cmd.ErrOrStderr(), uniques.WarnFlagIgnore("limit", ft.Name.Output)+"\n")
+	}
+
 	// wait for query to complete
 	if err := waitForSearch(search, true); err != nil {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-		return
+		return err
 	}
 
 	// fetch the data from the search
@@ -222,7 +263,7 @@ func runNonInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(),
 			fmt.Sprintf("failed to retrieve results from search %s (format %v): %v\n",
 				search.ID, format, err.Error()))
-		return
+		return err
 	}
 	defer results.Close()
 
@@ -232,21 +273,21 @@ func runNonInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 		var of *os.File
 		if of, err = openFile(flags.outfn, flags.append); err != nil {
 			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-			return
+			return err
 		}
 		defer of.Close()
 
 		// consumes the results and spit them into the open file
 		if b, err := of.ReadFrom(results); err != nil {
 			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-			return
+			return err
 		} else {
 			clilog.Writer.Infof("Streamed %d bytes (format %v) into %s", b, format, of.Name())
 		}
 		// stdout output is acceptible as the user is redirecting actual results to a file.
 		fmt.Fprintln(cmd.OutOrStdout(),
 			connection.DownloadQuerySuccessfulString(of.Name(), flags.append, format))
-		return
+		return nil
 	} else if format == types.DownloadArchive { // check for binary output
 		fmt.Fprintf(cmd.OutOrStdout(), "refusing to dump binary blob (format %v) to stdout.\n"+
 			"If this is intentional, re-run with -o <FILENAME>.\n"+
@@ -255,7 +296,7 @@ func runNonInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 	} else { // text results, stdout
 		if r, err := io.ReadAll(results); err != nil {
 			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-			return
+			return err
 		} else {
 			if len(r) == 0 {
 				fmt.Fprintln(cmd.OutOrStdout(), "no results to display")
@@ -264,17 +305,17 @@ func runNonInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 			}
 		}
 	}
-
+	return nil
 }
 
 // run function without --script given, making it acceptable to rely on user input
 // NOTE: download and schedule flags are handled inside of datascope
-func runInteractive(cmd *cobra.Command, flags queryflags, qry string) {
+func runInteractive(cmd *cobra.Command, flags queryflags, qry string) error {
 	// submit the immediate query
 	var search grav.Search
-	if s, err := connection.StartQuery(qry, -flags.duration); err != nil {
+	if s, err := startQuery(flags, qry); err != nil {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-		return
+		return err
 	} else {
 		search = s
 	}
@@ -282,7 +323,7 @@ func runInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 	// wait for query to complete
 	if err := waitForSearch(search, false); err != nil {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-		return
+		return err
 	}
 
 	// get results to pass to data scope
@@ -290,13 +331,13 @@ func runInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 		results   []string
 		tableMode bool
 	)
-	results, tableMode, err := fetchResults(&search)
+	results, tableMode, err := fetchResults(&search, flags.limit)
 	if err != nil {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
-		return
+		return err
 	} else if results == nil {
 		fmt.Fprintln(cmd.OutOrStdout(), NoResultsText)
-		return
+		return nil
 	}
 
 	// pass results into datascope
@@ -307,14 +348,14 @@ func runInteractive(cmd *cobra.Command, flags queryflags, qry string) {
 		datascope.WithSchedule(flags.schedule.cronfreq, flags.schedule.name, flags.schedule.desc),
 	); err != nil {
 		clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error())
-		return
+		return err
 	} else {
 		if _, err := p.Run(); err != nil {
 			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error())
-			return
+			return err
 		}
 	}
-
+	return nil
 }
 
 // Stops execution and waits for the given search to complete.
@@ -327,10 +368,11 @@ func waitForSearch(s grav.Search, scriptMode bool) error {
 		}
 	} else {
 		// outside of script mode wait via goroutine so we can display a spinner
+		var searchErr error
 		spnrP := busywait.CobraNew()
 		go func() {
-			if err := connection.Client.WaitForSearch(s); err != nil {
-				clilog.Writer.Error(err.Error())
+			if searchErr = connection.Client.WaitForSearch(s); searchErr != nil {
+				clilog.Writer.Error(searchErr.Error())
 			}
 			spnrP.Quit()
 		}()
@@ -338,6 +380,7 @@ func waitForSearch(s grav.Search, scriptMode bool) error {
 		if _, err := spnrP.Run(); err != nil {
 			return err
 		}
+		return searchErr
 	}
 	return nil
 }
@@ -378,13 +421,13 @@ func openFile(path string, append bool) (*os.File, error) {
 }
 
 // Given an active search handle associated to a completed search,
-// fetchResults pulls back all available results, using the appropriate Get function based on the
-// search's renderer
-func fetchResults(search *grav.Search) (results []string, tableMode bool, err error) {
+// fetchResults pulls back all available results (or the first limit results, if limit > 0), using
+// the appropriate Get function based on the search's renderer
+func fetchResults(search *grav.Search, limit uint64) (results []string, tableMode bool, err error) {
 	clilog.Writer.Infof("fetching results of type %v", search.RenderMod)
 	switch search.RenderMod {
 	case types.RenderNameTable:
-		if columns, rows, err := fetchTableResults(search); err != nil {
+		if columns, rows, err := fetchTableResults(search, limit); err != nil {
 			return nil, false, err
 		} else if len(rows) != 0 {
 			// format the table for datascope
@@ -399,7 +442,7 @@ func fetchResults(search *grav.Search) (results []string, tableMode bool, err er
 		// no results
 		return nil, true, nil
 	case types.RenderNameRaw, types.RenderNameText, types.RenderNameHex:
-		if rawResults, err := fetchTextResults(search); err != nil {
+		if rawResults, err := fetchTextResults(search, limit); err != nil {
 			return nil, false, err
 		} else if len(rawResults) != 0 {
 			// format the data for datascope
@@ -418,8 +461,8 @@ func fetchResults(search *grav.Search) (results []string, tableMode bool, err er
 }
 
 // Fetches all text results related to the given search by continually re-fetching until no more
-// results remain
-func fetchTextResults(s *grav.Search) ([]types.SearchEntry, error) {
+// results remain or limit (if > 0) results have been fetched
+func fetchTextResults(s *grav.Search, limit uint64) ([]types.SearchEntry, error) {
 	// return results for output to terminal
 	// batch results until we have the last of them
 	var (
@@ -433,7 +476,10 @@ func fetchTextResults(s *grav.Search) ([]types.SearchEntry, error) {
 			return nil, err
 		}
 		results = append(results, r.Entries...)
-		if !r.AdditionalEntries { // all records obtained
+		if limit > 0 && uint64(len(results)) >= limit {
+			results = results[:limit]
+			break
+		} else if !r.AdditionalEntries { // all records obtained
 			break
 		}
 		// ! Get*Results is half-open [)
@@ -447,7 +493,7 @@ func fetchTextResults(s *grav.Search) ([]types.SearchEntry, error) {
 }
 
 // Sister subroutine to fetchTextResults()
-func fetchTableResults(s *grav.Search) (
+func fetchTableResults(s *grav.Search, limit uint64) (
 	columns []string, rows []types.TableRow, err error,
 ) {
 	// return results for output to terminal
@@ -464,7 +510,10 @@ func fetchTableResults(s *grav.Search) (
 			return nil, nil, err
 		}
 		rows = append(rows, r.Entries.Rows...)
-		if !r.AdditionalEntries { // all records obtained
+		if limit > 0 && uint64(len(rows)) >= limit {
+			rows = rows[:limit]
+			break
+		} else if !r.AdditionalEntries { // all records obtained
 			break
 		}
 		// ! Get*Results is half-open [)
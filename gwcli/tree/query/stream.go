@@ -0,0 +1,260 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package query
+
+/**
+ * This file contains the streaming output used by non-interactive queries writing to stdout.
+ * Results are written page by page as the search makes them available, rather than waiting for
+ * the search to complete and downloading the results in one go.
+ */
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	grav "github.com/gravwell/gravwell/v3/client"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+)
+
+// streamable output formats
+const (
+	formatText = "text"
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+// how often a running search is polled for new results
+var pollInterval = time.Second
+
+// resultSource is the subset of the client used to wait on and stream search results.
+// Satisfied by *grav.Client.
+type resultSource interface {
+	GetAvailableEntryCount(grav.Search) (uint64, bool, error)
+	GetEntries(s grav.Search, start, end uint64) ([]types.StringTagEntry, error)
+	WaitForSearch(grav.Search) error
+}
+
+// streamable returns whether results of the given renderer can be streamed to stdout
+func streamable(rndr string) bool {
+	switch rndr {
+	case types.RenderNameRaw, types.RenderNameText, types.RenderNameHex, types.RenderNameTable:
+		return true
+	}
+	return false
+}
+
+// waitWithProgress blocks until the given search completes, calling progress with the number of
+// results available each time the search is polled.
+// Returns the search's error, if it failed.
+func waitWithProgress(src resultSource, s grav.Search, progress func(uint64)) error {
+	for {
+		count, done, err := src.GetAvailableEntryCount(s)
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(count)
+		}
+		if done {
+			break
+		}
+		time.Sleep(pollInterval)
+	}
+	// the search is complete, this just fetches its final status
+	return src.WaitForSearch(s)
+}
+
+// streamResults writes the results of the given search into w in the given format as they become
+// available, returning once the search completes or limit results have been written.
+// A limit of 0 writes all results.
+//
+// Table results are aggregated by the search, so they are only written once the search completes.
+func streamResults(w io.Writer, src resultSource, s grav.Search, format string, limit uint64) (
+	written uint64, err error,
+) {
+	rw := newResultWriter(w, format, s.RenderMod == types.RenderNameTable)
+	for {
+		var (
+			count uint64
+			done  bool
+		)
+		if count, done, err = src.GetAvailableEntryCount(s); err != nil {
+			return
+		}
+		if limit > 0 && count > limit {
+			count = limit
+		}
+		if done || !rw.table {
+			// ! GetEntries is half-open [)
+			for written < count {
+				var ents []types.StringTagEntry
+				if ents, err = src.GetEntries(s, written, min(written+pageSize, count)); err != nil {
+					return
+				} else if len(ents) == 0 {
+					break
+				}
+				if err = rw.write(ents); err != nil {
+					return
+				}
+				written += uint64(len(ents))
+			}
+		}
+		if limit > 0 && written >= limit {
+			clilog.Writer.Infof("result limit %d reached, stopping", limit)
+			return written, rw.flush()
+		} else if done {
+			break
+		}
+		time.Sleep(pollInterval)
+	}
+	if err = rw.flush(); err != nil {
+		return
+	}
+	clilog.Writer.Infof("%d results streamed", written)
+	return written, src.WaitForSearch(s)
+}
+
+// resultWriter formats entries returned by GetEntries.
+type resultWriter struct {
+	w       io.Writer
+	format  string
+	table   bool // entries are table rows, with values in Enumerated
+	cw      *csv.Writer
+	enc     *json.Encoder
+	columns []string // header of CSV output, set on the first write
+}
+
+func newResultWriter(w io.Writer, format string, table bool) *resultWriter {
+	rw := &resultWriter{w: w, format: format, table: table}
+	switch format {
+	case formatCSV:
+		rw.cw = csv.NewWriter(w)
+	case formatJSON:
+		rw.enc = json.NewEncoder(w)
+	}
+	return rw
+}
+
+func (rw *resultWriter) write(ents []types.StringTagEntry) error {
+	switch rw.format {
+	case formatCSV:
+		return rw.writeCSV(ents)
+	case formatJSON:
+		return rw.writeJSON(ents)
+	}
+	return rw.writeText(ents)
+}
+
+// text results are written one per line, table rows are tab separated
+func (rw *resultWriter) writeText(ents []types.StringTagEntry) error {
+	var sb strings.Builder
+	for _, ent := range ents {
+		if rw.table {
+			for i, ev := range ent.Enumerated {
+				if i > 0 {
+					sb.WriteByte('\t')
+				}
+				sb.WriteString(ev.Value)
+			}
+		} else {
+			sb.Write(ent.Data)
+		}
+		sb.WriteByte('\n')
+	}
+	_, err := io.WriteString(rw.w, sb.String())
+	return err
+}
+
+func (rw *resultWriter) writeCSV(ents []types.StringTagEntry) error {
+	for _, ent := range ents {
+		if rw.columns == nil { // first record; write the header
+			if rw.table {
+				rw.columns = make([]string, len(ent.Enumerated))
+				for i, ev := range ent.Enumerated {
+					rw.columns[i] = ev.Name
+				}
+			} else {
+				rw.columns = []string{"timestamp", "source", "tag", "data"}
+			}
+			if err := rw.cw.Write(rw.columns); err != nil {
+				return err
+			}
+		}
+		var rec []string
+		if rw.table {
+			rec = make([]string, len(ent.Enumerated))
+			for i, ev := range ent.Enumerated {
+				rec[i] = ev.Value
+			}
+		} else {
+			rec = []string{ent.TS.Format(time.RFC3339Nano), ipString(ent), ent.Tag, string(ent.Data)}
+		}
+		if err := rw.cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	// flush each page so results are streamed as they arrive
+	rw.cw.Flush()
+	return rw.cw.Error()
+}
+
+// JSON results are written as one object per line
+func (rw *resultWriter) writeJSON(ents []types.StringTagEntry) error {
+	for _, ent := range ents {
+		var v any
+		if rw.table {
+			row := make(map[string]string, len(ent.Enumerated))
+			for _, ev := range ent.Enumerated {
+				row[ev.Name] = ev.Value
+			}
+			v = row
+		} else {
+			v = struct {
+				Timestamp time.Time `json:"timestamp"`
+				Source    string    `json:"source"`
+				Tag       string    `json:"tag"`
+				Data      string    `json:"data"`
+			}{ent.TS, ipString(ent), ent.Tag, string(ent.Data)}
+		}
+		if err := rw.enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rw *resultWriter) flush() error {
+	if rw.cw != nil {
+		rw.cw.Flush()
+		return rw.cw.Error()
+	}
+	return nil
+}
+
+func ipString(ent types.StringTagEntry) string {
+	if ent.SRC == nil {
+		return ""
+	}
+	return ent.SRC.String()
+}
+
+// outputFormat returns the streaming format selected by the --json and --csv flags.
+// As with downloads, JSON takes precedence over CSV.
+func outputFormat(useJSON, useCSV bool) string {
+	if useJSON {
+		return formatJSON
+	} else if useCSV {
+		return formatCSV
+	}
+	return formatText
+}
@@ -0,0 +1,192 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package query
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	grav "github.com/gravwell/gravwell/v3/client"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+)
+
+// fakeSource mocks a search that makes step more results available each time it is polled
+type fakeSource struct {
+	ents  []types.StringTagEntry
+	step  int
+	avail int
+	err   error // returned once the search completes
+}
+
+func (f *fakeSource) GetAvailableEntryCount(grav.Search) (uint64, bool, error) {
+	f.avail = min(f.avail+f.step, len(f.ents))
+	return uint64(f.avail), f.avail == len(f.ents), nil
+}
+
+func (f *fakeSource) GetEntries(_ grav.Search, start, end uint64) ([]types.StringTagEntry, error) {
+	if end > uint64(f.avail) {
+		return nil, errors.New("requested unavailable entries")
+	}
+	return f.ents[start:end], nil
+}
+
+func (f *fakeSource) WaitForSearch(grav.Search) error {
+	return f.err
+}
+
+func textEntries(n int) (ents []types.StringTagEntry) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		ents = append(ents, types.StringTagEntry{
+			TS:   ts.Add(time.Duration(i) * time.Second),
+			Tag:  "gravwell",
+			SRC:  net.ParseIP("10.0.0.1"),
+			Data: []byte("entry " + strconv.Itoa(i)),
+		})
+	}
+	return
+}
+
+func tableEntries() []types.StringTagEntry {
+	row := func(vals ...string) types.StringTagEntry {
+		var e types.StringTagEntry
+		for i, v := range vals {
+			e.Enumerated = append(e.Enumerated, types.EnumeratedPair{Name: []string{"host", "count"}[i], Value: v})
+		}
+		return e
+	}
+	return []types.StringTagEntry{row("a", "1"), row("b, c", "2")}
+}
+
+func TestStreamResults(t *testing.T) {
+	clilog.Init(path.Join(t.TempDir(), "gwcli.TestStreamResults.log"), "DEBUG")
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = time.Second }()
+
+	text := grav.Search{RenderMod: types.RenderNameText}
+	table := grav.Search{RenderMod: types.RenderNameTable}
+	tests := []struct {
+		name    string
+		search  grav.Search
+		src     *fakeSource
+		format  string
+		limit   uint64
+		want    string
+		wantErr bool
+	}{
+		{name: "text", search: text, src: &fakeSource{ents: textEntries(3), step: 1}, format: formatText,
+			want: "entry 0\nentry 1\nentry 2\n"},
+		{name: "text limit", search: text, src: &fakeSource{ents: textEntries(5), step: 2}, format: formatText,
+			limit: 3, want: "entry 0\nentry 1\nentry 2\n"},
+		{name: "csv", search: text, src: &fakeSource{ents: textEntries(2), step: 1}, format: formatCSV,
+			want: "timestamp,source,tag,data\n" +
+				"2026-01-02T03:04:05Z,10.0.0.1,gravwell,entry 0\n" +
+				"2026-01-02T03:04:06Z,10.0.0.1,gravwell,entry 1\n"},
+		{name: "json", search: text, src: &fakeSource{ents: textEntries(1), step: 1}, format: formatJSON,
+			want: `{"timestamp":"2026-01-02T03:04:05Z","source":"10.0.0.1","tag":"gravwell","data":"entry 0"}` + "\n"},
+		{name: "table text", search: table, src: &fakeSource{ents: tableEntries(), step: 1}, format: formatText,
+			want: "a\t1\nb, c\t2\n"},
+		{name: "table csv", search: table, src: &fakeSource{ents: tableEntries(), step: 1}, format: formatCSV,
+			want: "host,count\na,1\n\"b, c\",2\n"},
+		{name: "table json", search: table, src: &fakeSource{ents: tableEntries(), step: 1}, format: formatJSON,
+			want: `{"count":"1","host":"a"}` + "\n" + `{"count":"2","host":"b, c"}` + "\n"},
+		{name: "search error", search: text, src: &fakeSource{ents: textEntries(1), step: 1, err: errors.New("bad")},
+			format: formatText, want: "entry 0\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bb bytes.Buffer
+			n, err := streamResults(&bb, tt.src, tt.search, tt.format, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("streamResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if bb.String() != tt.want {
+				t.Errorf("bad output:\n%q\nwant:\n%q", bb.String(), tt.want)
+			}
+			if lines := strings.Count(tt.want, "\n"); tt.format != formatCSV && n != uint64(lines) {
+				t.Errorf("bad result count: %d != %d", n, lines)
+			}
+		})
+	}
+}
+
+func TestWaitWithProgress(t *testing.T) {
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = time.Second }()
+
+	var seen []uint64
+	src := &fakeSource{ents: textEntries(5), step: 2}
+	if err := waitWithProgress(src, grav.Search{}, func(n uint64) { seen = append(seen, n) }); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{2, 4, 5}; len(seen) != len(want) || seen[0] != 2 || seen[1] != 4 || seen[2] != 5 {
+		t.Fatalf("bad progress: %v != %v", seen, want)
+	}
+	src = &fakeSource{ents: textEntries(1), step: 1, err: errors.New("search failed")}
+	if err := waitWithProgress(src, grav.Search{}, nil); err == nil {
+		t.Fatal("search error was not returned")
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-2 * time.Hour)
+	end := now.Add(-time.Hour)
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		duration  time.Duration
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{name: "duration", duration: time.Hour, wantStart: now.Add(-time.Hour), wantEnd: now},
+		{name: "start", start: start, duration: time.Minute, wantStart: start, wantEnd: now},
+		{name: "end", end: end, duration: time.Hour, wantStart: end.Add(-time.Hour), wantEnd: end},
+		{name: "start and end", start: start, end: end, duration: time.Minute, wantStart: start, wantEnd: end},
+		{name: "inverted", start: end, end: start, wantErr: true},
+		{name: "zero duration", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, e, err := timeRange(tt.start, tt.end, tt.duration, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("timeRange() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil {
+				return
+			}
+			if !s.Equal(tt.wantStart) || !e.Equal(tt.wantEnd) {
+				t.Errorf("timeRange() = %v -> %v, want %v -> %v", s, e, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestRangeFlags(t *testing.T) {
+	cmd := generateCobraCommand([]string{"--start", "2026-01-02T03:04:05Z", "--limit", "10"})
+	flags, err := transmogrifyFlags(cmd.Flags())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !flags.start.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) || !flags.end.IsZero() || flags.limit != 10 {
+		t.Fatalf("bad flags: %+v", flags)
+	}
+	cmd = generateCobraCommand([]string{"--end", "yesterday"})
+	if _, err = transmogrifyFlags(cmd.Flags()); err == nil {
+		t.Fatal("invalid --end was accepted")
+	}
+}
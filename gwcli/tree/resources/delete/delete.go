@@ -0,0 +1,58 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package delete implements an action for deleting a resource by name.
+package delete
+
+import (
+	"fmt"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldconfirm"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "delete"
+	short string = "delete a resource"
+	long  string = "Deletes a resource, given its name or GUID.\n" +
+		"--yes skips confirmation and is required in script mode."
+)
+
+var aliases []string = []string{"remove", "rm"}
+
+func NewResourcesDeleteAction() action.Pair {
+	p := scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, nil)
+	p.Action.Example = "./gwcli resources delete iplookup --yes"
+	return p
+}
+
+func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+	if fs.NArg() != 1 {
+		return p, "exactly one resource name is required", nil
+	}
+	name := fs.Arg(0)
+	guid, err := connection.Client.LookupResourceGUID(name)
+	if err != nil {
+		return p, "", fmt.Errorf("failed to find resource %v: %w", name, err)
+	}
+	md, err := connection.Client.GetResourceMetadata(guid)
+	if err != nil {
+		return p, "", err
+	}
+	p.Summary = fmt.Sprintf("Resource %v (GUID %v, %d bytes) will be deleted.\n", md.ResourceName, md.GUID, md.Size)
+	p.Commit = func() (string, error) {
+		if err := connection.Client.DeleteResource(md.GUID); err != nil {
+			return "", fmt.Errorf("failed to delete resource %v: %w", name, err)
+		}
+		return fmt.Sprintf("Deleted resource %v.", md.ResourceName), nil
+	}
+	return
+}
@@ -0,0 +1,116 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package get implements an action for downloading the contents of a resource.
+package get
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "get"
+	short string = "download the contents of a resource"
+)
+
+var long string = "Downloads the contents of a resource, given its name or GUID, to stdout or --" +
+	ft.Name.Output + ".\n" +
+	"Resources owned by you take precedence over those shared with your groups, which take " +
+	"precedence over global resources of the same name."
+
+var aliases []string = []string{"download"}
+
+func NewResourcesGetAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli resources get iplookup --output iplookup.csv"
+	// stream directly to stdout rather than buffering the resource as a string
+	p.Action.Run = func(c *cobra.Command, _ []string) {
+		name, out, err := getArgs(c.Flags())
+		if err != nil {
+			fmt.Fprintln(c.ErrOrStderr(), err.Error())
+			return
+		} else if out != "" {
+			fmt.Fprintln(c.OutOrStdout(), toFile(name, out))
+			return
+		}
+		if _, err := fetch(name, c.OutOrStdout()); err != nil {
+			clilog.Tee(clilog.ERROR, c.ErrOrStderr(), err.Error()+"\n")
+		}
+	}
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.StringP(ft.Name.Output, "o", "", "file to write the resource to.\nTruncates the file if it exists.")
+	return fs
+}
+
+func getArgs(fs *pflag.FlagSet) (name, out string, err error) {
+	if fs.NArg() != 1 {
+		return "", "", fmt.Errorf("exactly one resource name is required")
+	}
+	if out, err = fs.GetString(ft.Name.Output); err != nil {
+		clilog.LogFlagFailedGet(ft.Name.Output, err)
+	}
+	return fs.Arg(0), out, nil
+}
+
+// run is only used interactively, where the contents must be printed in one piece
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	name, out, err := getArgs(fs)
+	if err != nil {
+		return err.Error(), nil
+	} else if out != "" {
+		return toFile(name, out), nil
+	}
+	b, err := connection.Client.GetResource(name)
+	if err != nil {
+		return err.Error(), nil
+	}
+	return string(b), nil
+}
+
+// toFile writes the resource to the given path, returning the text to display
+func toFile(name, path string) string {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Sprintf("failed to open %v: %v", path, err)
+	}
+	n, err := fetch(name, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		clilog.Writer.Errorf("failed to write resource %v to %v: %v", name, path, err)
+		return fmt.Sprintf("failed to write resource %v to %v: %v", name, path, err)
+	}
+	return fmt.Sprintf("Wrote %d bytes to %v", n, path)
+}
+
+// fetch streams the contents of the named resource to w
+func fetch(name string, w io.Writer) (int64, error) {
+	rdr, err := connection.Client.GetResourceReader(name)
+	if err != nil {
+		return 0, err
+	}
+	defer rdr.Close()
+	return io.Copy(w, rdr)
+}
@@ -26,7 +26,7 @@ const (
 )
 
 var (
-	defaultColumns []string = []string{"ID", "UID", "Name", "Description"}
+	defaultColumns []string = []string{"GUID", "ResourceName", "Description", "Size", "UID", "Global", "LastModified"}
 )
 
 func NewResourcesListAction() action.Pair {
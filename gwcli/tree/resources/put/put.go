@@ -0,0 +1,138 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package put implements an action for creating or updating a resource from a local file.
+package put
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gravwell/gravwell/v3/client"
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldconfirm"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "put"
+	short string = "create or update a resource from a file"
+	long  string = "Uploads a local file as the contents of the named resource, creating the resource " +
+		"if it does not exist and overwriting its contents if it does.\n" +
+		"--yes skips confirmation and is required in script mode."
+)
+
+const (
+	flagDescription = "description"
+	flagGlobal      = "global"
+)
+
+var aliases []string = []string{"upload"}
+
+func NewResourcesPutAction() action.Pair {
+	p := scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, flags)
+	p.Action.Example = "./gwcli resources put iplookup ./iplookup.csv --description \"IP owners\""
+	return p
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.StringP(flagDescription, "d", "", "description of the resource")
+	fs.Bool(flagGlobal, false, "make the resource readable by all users.\nRequires admin privileges.")
+	return fs
+}
+
+// plan determines whether the resource will be created or overwritten
+func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+	if fs.NArg() != 2 {
+		return p, "a resource name and a file are required", nil
+	}
+	name, path := fs.Arg(0), fs.Arg(1)
+	fi, err := os.Stat(path)
+	if err != nil {
+		return p, err.Error(), nil
+	} else if fi.IsDir() {
+		return p, path + " is a directory", nil
+	}
+	desc, err := fs.GetString(flagDescription)
+	if err != nil {
+		return
+	}
+	global, err := fs.GetBool(flagGlobal)
+	if err != nil {
+		return
+	}
+
+	existing, err := lookup(name)
+	if err != nil {
+		return p, "", err
+	}
+	p.Summary = summarize(name, path, fi.Size(), existing)
+	p.Commit = func() (string, error) {
+		md, err := existing, error(nil)
+		if md == nil {
+			if md, err = connection.Client.CreateResource(name, desc, global, nil); err != nil {
+				return "", fmt.Errorf("failed to create resource %v: %w", name, err)
+			}
+		} else if fs.Changed(flagDescription) || fs.Changed(flagGlobal) {
+			if fs.Changed(flagDescription) {
+				md.Description = desc
+			}
+			if fs.Changed(flagGlobal) {
+				md.Global = global
+			}
+			if err := connection.Client.UpdateMetadata(md.GUID, *md); err != nil {
+				return "", fmt.Errorf("failed to update resource %v: %w", name, err)
+			}
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		if err := connection.Client.PopulateResourceFromReader(md.GUID, f); err != nil {
+			return "", fmt.Errorf("failed to upload %v: %w", path, err)
+		}
+		return fmt.Sprintf("Uploaded %v to resource %v (GUID %v).", path, name, md.GUID), nil
+	}
+	return
+}
+
+// lookup returns the metadata of the named resource or nil if it does not exist
+func lookup(name string) (*types.ResourceMetadata, error) {
+	guid, err := connection.Client.LookupResourceGUID(name)
+	if notFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return connection.Client.GetResourceMetadata(guid)
+}
+
+// notFound returns whether the error is the client reporting a 404
+func notFound(err error) bool {
+	var cerr *client.ClientError
+	if errors.As(err, &cerr) {
+		return cerr.StatusCode == 404
+	}
+	return errors.Is(err, client.ErrNotFound)
+}
+
+// summarize describes what the upload will do, given the existing resource (if any)
+func summarize(name, path string, size int64, existing *types.ResourceMetadata) string {
+	if existing == nil {
+		return fmt.Sprintf("Resource %v does not exist and will be created from %v (%d bytes).\n",
+			name, path, size)
+	}
+	return fmt.Sprintf("Resource %v (GUID %v, %d bytes, last modified %v) will be overwritten by %v (%d bytes).\n",
+		name, existing.GUID, existing.Size, existing.LastModified.Format("2006-01-02 15:04:05 MST"), path, size)
+}
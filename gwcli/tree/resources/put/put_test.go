@@ -0,0 +1,51 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package put
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/client"
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+func TestNotFound(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{client.ErrNotFound, true},
+		{fmt.Errorf("lookup: %w", client.ErrNotFound), true},
+		{&client.ClientError{Status: "404 Not Found", StatusCode: 404}, true},
+		{&client.ClientError{Status: "500 Internal Server Error", StatusCode: 500}, false},
+		{errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		if got := notFound(tt.err); got != tt.want {
+			t.Errorf("notFound(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := summarize("iplookup", "ips.csv", 10, nil)
+	if !strings.Contains(s, "will be created") {
+		t.Errorf("expected creation summary, got %q", s)
+	}
+	md := &types.ResourceMetadata{GUID: "abc", Size: 20, LastModified: time.Now()}
+	s = summarize("iplookup", "ips.csv", 10, md)
+	if !strings.Contains(s, "will be overwritten") || !strings.Contains(s, "abc") {
+		t.Errorf("expected overwrite summary, got %q", s)
+	}
+}
@@ -10,7 +10,10 @@ package resources
 
 import (
 	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/resources/delete"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/resources/get"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/resources/list"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/resources/put"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 
 	"github.com/spf13/cobra"
@@ -30,5 +33,10 @@ var aliases []string = []string{}
 func NewResourcesNav() *cobra.Command {
 	return treeutils.GenerateNav(use, short, long, aliases,
 		[]*cobra.Command{},
-		[]action.Pair{list.NewResourcesListAction()})
+		[]action.Pair{
+			list.NewResourcesListAction(),
+			get.NewResourcesGetAction(),
+			put.NewResourcesPutAction(),
+			delete.NewResourcesDeleteAction(),
+		})
 }
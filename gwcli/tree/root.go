@@ -24,19 +24,28 @@ import (
 	"github.com/gravwell/gravwell/v3/gwcli/tree/extractors"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/kits"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/macros"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/profile"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/queries"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/query"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/resources"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/status"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/tags"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/tree"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/user"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/cfgdir"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/flagcfg"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/profiles"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/qhistory"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/usage"
+	"io"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // global PersistenPreRunE.
@@ -44,6 +53,11 @@ import (
 // Ensures the logger is set up and the user has logged into the gravwell instance,
 // completeing these actions if either is false.
 func ppre(cmd *cobra.Command, args []string) error {
+	// fill in the flags that were not given from the environment and config file
+	if err := applyConfig(cmd.Root(), cmd.Flags()); err != nil {
+		return err
+	}
+
 	// set up the logger, if it is not already initialized
 	if clilog.Writer == nil {
 		path, err := cmd.Flags().GetString("log")
@@ -56,9 +70,13 @@ func ppre(cmd *cobra.Command, args []string) error {
 		}
 		clilog.Init(path, lvl)
 	}
+	if size, err := cmd.Flags().GetUint("history-size"); err == nil {
+		qhistory.Size = size
+	}
 
 	// if this is a 'complete' request, do not enforce login
 	if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		completionLogin(cmd, args)
 		return nil
 	}
 
@@ -67,6 +85,17 @@ func ppre(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// if this action does not require a connection, do not enforce login
+	if _, ok := cmd.Annotations[treeutils.NoLoginAnnotation]; ok {
+		return nil
+	}
+
+	// if this action only uses an existing login, do not prompt for credentials
+	if _, ok := cmd.Annotations[treeutils.TokenLoginAnnotation]; ok {
+		tokenLogin(cmd.Flags(), cmd.ErrOrStderr())
+		return nil
+	}
+
 	return EnforceLogin(cmd, args)
 }
 
@@ -74,15 +103,7 @@ func ppre(cmd *cobra.Command, args []string) error {
 // Safe (ineffectual) to call if already logged in.
 func EnforceLogin(cmd *cobra.Command, args []string) error {
 	if connection.Client == nil { // if we just started, initialize connection
-		server, err := cmd.Flags().GetString("server")
-		if err != nil {
-			return err
-		}
-		insecure, err := cmd.Flags().GetBool("insecure")
-		if err != nil {
-			return err
-		}
-		if err = connection.Initialize(server, !insecure, insecure, ""); err != nil {
+		if err := initConnection(cmd.Flags(), cmd.ErrOrStderr()); err != nil {
 			return err
 		}
 	}
@@ -116,10 +137,137 @@ func EnforceLogin(cmd *cobra.Command, args []string) error {
 
 	clilog.Writer.Infof("Logged in successfully")
 
+	if notice := connection.ExpiryNotice(); notice != "" {
+		clilog.Tee(clilog.WARN, cmd.ErrOrStderr(), notice+"\n")
+	}
+
 	return nil
 
 }
 
+// completionLogin attempts to log in so dynamic completions can query the Gravwell instance.
+// Completion must never prompt, so only a saved login token is tried; on failure, dynamic
+// completions are simply not offered.
+func completionLogin(cmd *cobra.Command, args []string) {
+	// the request's arguments are the command line being completed, so pick out the global flags
+	fs := pflag.FlagSet{}
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.AddFlagSet(cmd.Root().PersistentFlags())
+	if err := fs.Parse(args); err != nil {
+		clilog.Writer.Debugf("failed to parse flags for completion: %v", err)
+	}
+	if err := applyConfig(cmd.Root(), &fs); err != nil {
+		clilog.Writer.Debugf("failed to apply config for completion: %v", err)
+	}
+	tokenLogin(&fs, io.Discard)
+}
+
+// tokenLogin logs in via the saved login token, if it is valid, and never prompts.
+// Failures are only logged, leaving the Client logged out.
+func tokenLogin(fs *pflag.FlagSet, errOut io.Writer) {
+	if err := initConnection(fs, errOut); err != nil {
+		clilog.Writer.Debugf("failed to connect: %v", err)
+		return
+	}
+	if err := connection.LoginViaToken(); err != nil {
+		clilog.Writer.Debugf("failed to login via JWT: %v", err)
+	}
+}
+
+// applyConfig sets the flags in fs that were not given on the command line from their GWCLI_*
+// environment variables or the config file (per --config, $GWCLI_CONFIG, or the default path).
+func applyConfig(root *cobra.Command, fs *pflag.FlagSet) error {
+	path, err := fs.GetString(flagcfg.FlagName)
+	if err != nil {
+		return err
+	}
+	explicit := fs.Changed(flagcfg.FlagName)
+	if v, ok := os.LookupEnv(flagcfg.EnvName(flagcfg.FlagName)); ok && !explicit {
+		path, explicit = v, true
+	}
+	cfg, err := flagcfg.Load(path, explicit, root)
+	if err != nil {
+		return err
+	}
+	return cfg.Apply(fs)
+}
+
+// initConnection initializes the connection to the Gravwell instance dictated by the --server
+// flag or the active profile.
+// Warnings about the security of the connection are written to errOut.
+func initConnection(fs *pflag.FlagSet, errOut io.Writer) error {
+	server, err := fs.GetString("server")
+	if err != nil {
+		return err
+	}
+	insecure, err := fs.GetBool("insecure")
+	if err != nil {
+		return err
+	}
+	if server, insecure, err = applyProfile(fs, server, insecure); err != nil {
+		return err
+	}
+	if err := configureTLS(fs, insecure, errOut); err != nil {
+		return err
+	}
+	return connection.Initialize(server, !insecure, insecure, "")
+}
+
+// configureTLS applies --insecure-skip-verify and --ca-file to the connection.
+// Both require HTTPS, so they are rejected if the connection is insecure.
+func configureTLS(fs *pflag.FlagSet, insecure bool, errOut io.Writer) error {
+	skipVerify, err := fs.GetBool("insecure-skip-verify")
+	if err != nil {
+		return err
+	}
+	caFile, err := fs.GetString("ca-file")
+	if err != nil {
+		return err
+	}
+	if insecure && (skipVerify || caFile != "") {
+		return errors.New("--insecure-skip-verify and --ca-file cannot be used with --insecure (HTTP)")
+	}
+	if skipVerify {
+		clilog.Tee(clilog.WARN, errOut, "WARNING: --insecure-skip-verify disables TLS certificate "+
+			"verification; the identity of the server is not checked.\n")
+	}
+	return connection.ConfigureTLS(skipVerify, caFile)
+}
+
+// applyProfile resolves the active profile (per --profile or the default profile) and directs
+// the connection to it.
+// Explicitly set --server and --insecure flags take precedence over the profile's settings.
+func applyProfile(fs *pflag.FlagSet, server string, insecure bool) (string, bool, error) {
+	name, err := fs.GetString("profile")
+	if err != nil {
+		return server, insecure, err
+	}
+	s, err := profiles.Load()
+	if err != nil {
+		if name != "" {
+			return server, insecure, err
+		}
+		// without an explicit profile, a broken profiles file should not prevent login
+		clilog.Writer.Warnf("failed to load profiles: %v", err)
+		return server, insecure, nil
+	}
+	name, p, ok, err := s.Active(name)
+	if err != nil {
+		return server, insecure, err
+	} else if !ok {
+		return server, insecure, nil
+	}
+	clilog.Writer.Infof("Using profile %v", name)
+	if !fs.Changed("server") {
+		server = p.Server
+	}
+	if !fs.Changed("insecure") {
+		insecure = p.Insecure
+	}
+	connection.UseProfile(name)
+	return server, insecure, nil
+}
+
 func ppost(cmd *cobra.Command, args []string) error {
 	return connection.End()
 }
@@ -133,12 +281,27 @@ func GenerateFlags(root *cobra.Command) {
 	root.PersistentFlags().StringP("username", "u", "", "login credential.")
 	root.PersistentFlags().String("password", "", "login credential.")
 	root.PersistentFlags().StringP("passfile", "p", "", "the path to a file containing your password")
-	root.PersistentFlags().Bool("no-color", false, "disables colourized output.")
+	root.PersistentFlags().Bool("no-color", false, "disables colourized output.\n"+
+		"Also disabled by setting NO_COLOR or when output is not a terminal.")
+	root.PersistentFlags().Bool("no-pager", false, "do not page output taller than the terminal through $PAGER.\n"+
+		"Paging is always disabled when output is not a terminal.")
 	root.PersistentFlags().String("server", "localhost:80", "<host>:<port> of instance to connect to.\n")
 	root.PersistentFlags().StringP("log", "l", cfgdir.DefaultStdLogPath, "log location for developer logs.\n")
 	root.PersistentFlags().String("loglevel", "DEBUG", "log level for developer logs (-l).\n"+
 		"Possible values: 'OFF', 'DEBUG', 'INFO', 'WARN', 'ERROR', 'CRITICAL', 'FATAL'.\n")
 	root.PersistentFlags().Bool("insecure", false, "do not use HTTPS and do not enforce certs.")
+	root.PersistentFlags().Bool("insecure-skip-verify", false, "use HTTPS, but do not verify the server's certificate.\n"+
+		"Prefer --ca-file for self-signed or internal CA certificates.")
+	root.PersistentFlags().String("ca-file", "", "path to a PEM bundle of CA certificates to trust when verifying the server,\n"+
+		"in place of the system CAs.")
+	root.PersistentFlags().String("profile", "", "name of the saved profile to connect with.\n"+
+		"Defaults to the profile selected by `profile use`, if any.\n"+
+		"--server and --insecure override the profile's settings.")
+	root.PersistentFlags().Uint("history-size", qhistory.DefaultSize,
+		"number of interactive queries retained in the query history. 0 disables it.")
+	root.PersistentFlags().String(flagcfg.FlagName, cfgdir.DefaultConfigPath, "path to a YAML file of default flag values, keyed by flag name.\n"+
+		"Flags given on the command line take precedence, followed by GWCLI_<FLAG> environment variables,\n"+
+		"then the file.")
 }
 
 const ( // usage
@@ -172,13 +335,16 @@ func Execute(args []string) int {
 			queries.NewQueriesNav(),
 			kits.NewKitsNav(),
 			user.NewUserNav(),
+			users.NewUsersNav(),
 			extractors.NewExtractorsNav(),
 			dashboards.NewDashboardNav(),
 			resources.NewResourcesNav(),
 			status.NewStatusNav(),
+			profile.NewProfileNav(),
 		},
 		[]action.Pair{
 			query.NewQueryAction(),
+			tags.NewTagsAction(),
 			tree.NewTreeAction(),
 		})
 	rootCmd.SilenceUsage = true
@@ -189,13 +355,20 @@ func Execute(args []string) int {
 	// associate flags
 	GenerateFlags(rootCmd)
 
+	// configuration the completion command as an action
+	rootCmd.SetCompletionCommandGroupID(group.ActionID)
+	// generate the completion command now, rather than on Execute, so it can skip login
+	rootCmd.InitDefaultCompletionCmd()
+	if c, _, err := rootCmd.Find([]string{"completion"}); err == nil {
+		for _, shell := range c.Commands() {
+			treeutils.SkipLogin(shell)
+		}
+	}
+
 	if !rootCmd.AllChildCommandsHaveGroup() {
 		panic("some children missing a group")
 	}
 
-	// configuration the completion command as an action
-	rootCmd.SetCompletionCommandGroupID(group.ActionID)
-
 	// configure Windows mouse trap
 	cobra.MousetrapHelpText = mousetrapText
 	cobra.MousetrapDisplayDuration = mousetrapDuration
@@ -213,6 +386,11 @@ func Execute(args []string) int {
 
 	err := rootCmd.Execute()
 	if err != nil {
+		// actions may dictate their own exit code (ex: monitoring checks)
+		var ec interface{ ExitCode() int }
+		if errors.As(err, &ec) {
+			return ec.ExitCode()
+		}
 		return 1
 	}
 
@@ -0,0 +1,295 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+Package check provides the --check flag shared by the indexer status actions, allowing them to be
+used as Nagios/Icinga-style monitoring checks.
+
+In check mode, the action prints its usual output, then evaluates the health of the indexers against
+the given thresholds and exits with the conventional monitoring code:
+
+	0 OK: all thresholds are satisfied
+	1 WARNING: a disk is at or above --warn-disk-percent, or an indexer is down
+	2 CRITICAL: a disk is at or above --max-disk-percent, or an indexer is down and --require-all-up
+	3 UNKNOWN: the status of the indexers could not be determined
+
+A one-line summary of the result is written to stderr so it does not pollute JSON/CSV output.
+
+Check mode is only available from Cobra; Mother has no exit code to signal with.
+*/
+package check
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/filter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagCheck        = "check"
+	flagWarnDisk     = "warn-disk-percent"
+	flagMaxDisk      = "max-disk-percent"
+	flagRequireAllUp = "require-all-up"
+)
+
+// Status is the result of a check, valued as its monitoring exit code.
+type Status int
+
+const (
+	OK Status = iota
+	Warning
+	Critical
+	Unknown
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "WARNING"
+	case Critical:
+		return "CRITICAL"
+	}
+	return "UNKNOWN"
+}
+
+// ExitError carries a non-OK check result out of Cobra so the process can exit with its code.
+type ExitError struct {
+	Status Status
+	Reason string
+}
+
+func (e *ExitError) Error() string {
+	return "indexer check " + e.Status.String() + ": " + e.Reason
+}
+
+// ExitCode returns the monitoring exit code of the check result.
+func (e *ExitError) ExitCode() int {
+	return int(e.Status)
+}
+
+// thresholds a check evaluates indexers against.
+// Disk thresholds of 0 are disabled.
+type thresholds struct {
+	warnDisk     float64
+	maxDisk      float64
+	requireAllUp bool
+}
+
+// addFlags attaches the check flags to the given flagset.
+func addFlags(fs *pflag.FlagSet) {
+	fs.Bool(flagCheck, false,
+		"evaluate indexer health after displaying results and exit with a monitoring status code:\n"+
+			"0 (OK) if all thresholds are satisfied,\n"+
+			"1 (WARNING) if a disk meets --"+flagWarnDisk+" or an indexer is down,\n"+
+			"2 (CRITICAL) if a disk meets --"+flagMaxDisk+" or an indexer is down with --"+flagRequireAllUp+",\n"+
+			"3 (UNKNOWN) if indexer status could not be retrieved or the action failed.\n"+
+			"Not available interactively.")
+	fs.Float64(flagWarnDisk, 0,
+		"with --"+flagCheck+", exit 1 (WARNING) if any indexer disk is at least this percent full.\n"+
+			"0 disables the threshold.")
+	fs.Float64(flagMaxDisk, 0,
+		"with --"+flagCheck+", exit 2 (CRITICAL) if any indexer disk is at least this percent full.\n"+
+			"0 disables the threshold.")
+	fs.Bool(flagRequireAllUp, false,
+		"with --"+flagCheck+", exit 2 (CRITICAL) rather than 1 (WARNING) if any indexer is down.")
+}
+
+// getThresholds returns whether --check was given and the thresholds to check against.
+func getThresholds(fs *pflag.FlagSet) (enabled bool, t thresholds, err error) {
+	if enabled, err = fs.GetBool(flagCheck); err != nil {
+		clilog.LogFlagFailedGet(flagCheck, err)
+		return
+	}
+	if t.warnDisk, err = fs.GetFloat64(flagWarnDisk); err != nil {
+		clilog.LogFlagFailedGet(flagWarnDisk, err)
+		return
+	}
+	if t.maxDisk, err = fs.GetFloat64(flagMaxDisk); err != nil {
+		clilog.LogFlagFailedGet(flagMaxDisk, err)
+		return
+	}
+	if t.requireAllUp, err = fs.GetBool(flagRequireAllUp); err != nil {
+		clilog.LogFlagFailedGet(flagRequireAllUp, err)
+		return
+	}
+	return enabled, t, t.validate()
+}
+
+func (t thresholds) validate() error {
+	if t.warnDisk < 0 || t.warnDisk > 100 {
+		return fmt.Errorf("--%s must be between 0 and 100", flagWarnDisk)
+	} else if t.maxDisk < 0 || t.maxDisk > 100 {
+		return fmt.Errorf("--%s must be between 0 and 100", flagMaxDisk)
+	} else if t.warnDisk > 0 && t.maxDisk > 0 && t.warnDisk > t.maxDisk {
+		return fmt.Errorf("--%s cannot be greater than --%s", flagWarnDisk, flagMaxDisk)
+	}
+	return nil
+}
+
+// evaluate checks the system stats of each indexer against the thresholds, returning the most
+// severe status and the reasons for it.
+func evaluate(stats map[string]types.SysStats, t thresholds) (Status, []string) {
+	if len(stats) == 0 {
+		return Unknown, []string{"no indexers reported status"}
+	}
+	indexers := make([]string, 0, len(stats))
+	for k := range stats {
+		indexers = append(indexers, k)
+	}
+	sort.Strings(indexers)
+
+	var (
+		status  = OK
+		reasons = map[Status][]string{}
+	)
+	raise := func(s Status, reason string) {
+		status = max(status, s)
+		reasons[s] = append(reasons[s], reason)
+	}
+	for _, idx := range indexers {
+		ss := stats[idx]
+		if ss.Error != "" || ss.Stats == nil {
+			reason := idx + " is down"
+			if ss.Error != "" {
+				reason += " (" + ss.Error + ")"
+			}
+			if t.requireAllUp {
+				raise(Critical, reason)
+			} else {
+				raise(Warning, reason)
+			}
+			continue
+		}
+		for _, d := range ss.Stats.Disks {
+			if d.Total == 0 {
+				continue
+			}
+			pct := float64(d.Used) / float64(d.Total) * 100
+			reason := fmt.Sprintf("%s disk %s is %.1f%% full", idx, d.Mount, pct)
+			if t.maxDisk > 0 && pct >= t.maxDisk {
+				raise(Critical, reason)
+			} else if t.warnDisk > 0 && pct >= t.warnDisk {
+				raise(Warning, reason)
+			}
+		}
+	}
+	if status == OK {
+		return OK, []string{fmt.Sprintf("%d indexer(s) healthy", len(stats))}
+	}
+	return status, reasons[status]
+}
+
+// Wrap attaches the check flags to the given action.
+// From Cobra, --check evaluates the indexers once the action has run and returns an ExitError if
+// they are not OK. From Mother, the check flags are rejected.
+// The action must have already attached the --indexer flag, which the check respects.
+func Wrap(p action.Pair) action.Pair {
+	addFlags(p.Action.Flags())
+
+	// failing to log in also leaves the indexers' status unknown.
+	// Cobra only runs the nearest persistent pre-run, so defer to root's from here.
+	p.Action.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		ppre := cmd.Root().PersistentPreRunE
+		if ppre == nil {
+			return nil
+		}
+		err := ppre(cmd, args)
+		if enabled, _, _ := getThresholds(cmd.Flags()); err != nil && enabled {
+			return report(cmd, Unknown, err.Error())
+		}
+		return err
+	}
+
+	preRunE := p.Action.PreRunE
+	p.Action.PreRunE = func(cmd *cobra.Command, args []string) error {
+		enabled, _, err := getThresholds(cmd.Flags())
+		if err != nil {
+			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
+		} else if preRunE != nil {
+			err = preRunE(cmd, args) // prints its own errors
+		}
+		if err != nil && enabled {
+			return report(cmd, Unknown, err.Error())
+		}
+		return err
+	}
+	p.Action.PostRunE = func(cmd *cobra.Command, _ []string) error {
+		enabled, t, err := getThresholds(cmd.Flags())
+		if !enabled || err != nil {
+			return nil
+		}
+		var (
+			status  = Unknown
+			reasons []string
+		)
+		if stats, err := connection.Client.GetSystemStats(); err != nil {
+			reasons = []string{"failed to fetch indexer stats: " + err.Error()}
+		} else {
+			status, reasons = evaluate(filter.Apply(cmd.Flags(), stats), t)
+		}
+		return report(cmd, status, strings.Join(reasons, "; "))
+	}
+	// errors are printed above
+	p.Action.SilenceErrors = true
+
+	p.Model = &model{Model: p.Model}
+	return p
+}
+
+// report writes the summary line of the check to stderr, returning an ExitError if it is not OK.
+func report(cmd *cobra.Command, status Status, reason string) error {
+	fmt.Fprintf(cmd.ErrOrStderr(), "INDEXERS %v - %s\n", status, reason)
+	if status == OK {
+		return nil
+	}
+	return &ExitError{Status: status, Reason: reason}
+}
+
+//#region interactive mode (model) implementation
+
+type model struct {
+	action.Model
+}
+
+func (m *model) SetArgs(inherited *pflag.FlagSet, tokens []string) (string, tea.Cmd, error) {
+	for _, t := range tokens {
+		if t == "--" {
+			break
+		}
+		for _, f := range []string{flagCheck, flagWarnDisk, flagMaxDisk, flagRequireAllUp} {
+			if t == "--"+f || strings.HasPrefix(t, "--"+f+"=") {
+				return "--" + f + " is only available non-interactively", nil, nil
+			}
+		}
+	}
+	return m.Model.SetArgs(inherited, tokens)
+}
+
+// Render passes through to the wrapped model, if it is capable.
+func (m *model) Render() (string, error) {
+	r, ok := m.Model.(interface{ Render() (string, error) })
+	if !ok {
+		return "", errors.New("action cannot be rendered")
+	}
+	return r.Render()
+}
+
+//#endregion interactive mode (model) implementation
@@ -0,0 +1,84 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package check
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+func disks(pcts ...uint64) types.SysStats {
+	hs := &types.HostSysStats{}
+	for _, p := range pcts {
+		hs.Disks = append(hs.Disks, types.DiskStats{Mount: "/opt", Total: 100, Used: p})
+	}
+	return types.SysStats{Stats: hs}
+}
+
+func TestEvaluate(t *testing.T) {
+	down := types.SysStats{Error: "connection refused"}
+	tests := []struct {
+		name    string
+		stats   map[string]types.SysStats
+		t       thresholds
+		want    Status
+		reasons int
+	}{
+		{name: "none", stats: map[string]types.SysStats{}, want: Unknown, reasons: 1},
+		{name: "no thresholds", stats: map[string]types.SysStats{"a": disks(99)}, want: OK, reasons: 1},
+		{name: "under", stats: map[string]types.SysStats{"a": disks(50)},
+			t: thresholds{warnDisk: 80, maxDisk: 90}, want: OK, reasons: 1},
+		{name: "warn", stats: map[string]types.SysStats{"a": disks(50, 80), "b": disks(85)},
+			t: thresholds{warnDisk: 80, maxDisk: 90}, want: Warning, reasons: 2},
+		{name: "crit", stats: map[string]types.SysStats{"a": disks(85), "b": disks(90)},
+			t: thresholds{warnDisk: 80, maxDisk: 90}, want: Critical, reasons: 1},
+		{name: "max only", stats: map[string]types.SysStats{"a": disks(85)},
+			t: thresholds{maxDisk: 90}, want: OK, reasons: 1},
+		{name: "down", stats: map[string]types.SysStats{"a": disks(10), "b": down},
+			want: Warning, reasons: 1},
+		{name: "down required", stats: map[string]types.SysStats{"a": disks(10), "b": down},
+			t: thresholds{requireAllUp: true}, want: Critical, reasons: 1},
+		{name: "no stats", stats: map[string]types.SysStats{"a": {}},
+			t: thresholds{requireAllUp: true}, want: Critical, reasons: 1},
+		{name: "empty disk", stats: map[string]types.SysStats{"a": {Stats: &types.HostSysStats{
+			Disks: []types.DiskStats{{Mount: "/"}}}}}, t: thresholds{maxDisk: 1}, want: OK, reasons: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r := evaluate(tt.stats, tt.t)
+			if s != tt.want {
+				t.Errorf("bad status: %v != %v (reasons: %v)", s, tt.want, r)
+			}
+			if len(r) != tt.reasons {
+				t.Errorf("bad reason count: %d != %d (%v)", len(r), tt.reasons, r)
+			}
+		})
+	}
+}
+
+func TestThresholdsValidate(t *testing.T) {
+	for _, tt := range []thresholds{{warnDisk: -1}, {maxDisk: 101}, {warnDisk: 90, maxDisk: 80}} {
+		if tt.validate() == nil {
+			t.Errorf("%+v was accepted", tt)
+		}
+	}
+	if err := (thresholds{warnDisk: 80}).validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExitError(t *testing.T) {
+	var err error = &ExitError{Status: Critical, Reason: "full"}
+	var ec interface{ ExitCode() int }
+	if !errors.As(err, &ec) || ec.ExitCode() != 2 {
+		t.Fatalf("bad exit code from %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+Package filter provides the --indexer flag shared by the indexer status actions.
+
+Patterns are matched case-insensitively against indexer names and may end in a '*' to match by
+prefix.
+If any pattern matches no indexer, the action fails, listing the indexers that are available.
+
+Actions add the flag via AddFlag, filter their results via Apply, and are wrapped via Wrap so
+unmatched patterns are rejected before the action runs, from Cobra or from Mother.
+Wrap also completes --indexer with the names of the available indexers.
+*/
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const flagName = "indexer"
+
+// AddFlag attaches the --indexer flag to the given flagset.
+func AddFlag(fs *pflag.FlagSet) {
+	fs.StringSlice(flagName, []string{},
+		"only display the named indexer(s). May be given multiple times.\n"+
+			"Case-insensitive; a trailing '*' matches any suffix.")
+}
+
+// Patterns returns the patterns passed to --indexer, if any.
+func Patterns(fs *pflag.FlagSet) []string {
+	p, err := fs.GetStringSlice(flagName)
+	if err != nil {
+		clilog.LogFlagFailedGet(flagName, err)
+		return nil
+	}
+	return p
+}
+
+// Match returns whether the given indexer name is matched by the pattern.
+func Match(pattern, name string) bool {
+	pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return pattern == name
+}
+
+// Apply returns the subset of m whose keys are matched by a pattern in --indexer.
+// If --indexer was not given, m is returned as is.
+func Apply[T any](fs *pflag.FlagSet, m map[string]T) map[string]T {
+	patterns := Patterns(fs)
+	if len(patterns) == 0 {
+		return m
+	}
+	res := make(map[string]T, len(m))
+	for k, v := range m {
+		for _, p := range patterns {
+			if Match(p, k) {
+				res[k] = v
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Check returns an error describing every pattern that matches none of the given names.
+func Check(patterns, names []string) error {
+	var unmatched []string
+	for _, p := range patterns {
+		var found bool
+		for _, n := range names {
+			if Match(p, n) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, p)
+		}
+	}
+	if len(unmatched) == 0 {
+		return nil
+	}
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return fmt.Errorf("no indexer matches %s. Available indexers: %s",
+		strings.Join(unmatched, ", "), strings.Join(sorted, ", "))
+}
+
+// NamesFunc fetches the names of the indexers an action can display.
+type NamesFunc func() ([]string, error)
+
+// check fetches the available names and validates the given patterns against them.
+func check(patterns []string, names NamesFunc) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+	n, err := names()
+	if err != nil {
+		return err
+	}
+	return Check(patterns, n)
+}
+
+// Wrap rejects unmatched --indexer patterns before the given action runs and completes --indexer
+// from the given names.
+// From Cobra, the command returns an error (and thus exits non-zero).
+// From Mother, the arguments are reported as invalid.
+// The action must have already attached the flag via AddFlag.
+func Wrap(p action.Pair, names NamesFunc) action.Pair {
+	p.Action.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		if err := check(Patterns(cmd.Flags()), names); err != nil {
+			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
+			return err
+		}
+		return nil
+	}
+	// the error was already printed
+	p.Action.SilenceErrors = true
+	if err := p.Action.RegisterFlagCompletionFunc(flagName, treeutils.CompleteFrom(names)); err != nil {
+		panic(err)
+	}
+
+	p.Model = &model{Model: p.Model, names: names}
+	return p
+}
+
+//#region interactive mode (model) implementation
+
+type model struct {
+	action.Model
+	names NamesFunc
+}
+
+func (m *model) SetArgs(inherited *pflag.FlagSet, tokens []string) (string, tea.Cmd, error) {
+	invalid, onStart, err := m.Model.SetArgs(inherited, tokens)
+	if err != nil || invalid != "" {
+		return invalid, onStart, err
+	}
+
+	// the wrapped model owns the flagset, so parse out --indexer separately
+	fs := pflag.FlagSet{}
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	AddFlag(&fs)
+	if err := fs.Parse(tokens); err != nil {
+		return err.Error(), nil, nil
+	}
+	if err := check(Patterns(&fs), m.names); err != nil {
+		return err.Error(), nil, nil
+	}
+	return "", onStart, nil
+}
+
+// Render passes through to the wrapped model, if it is capable.
+func (m *model) Render() (string, error) {
+	r, ok := m.Model.(interface{ Render() (string, error) })
+	if !ok {
+		return "", errors.New("action cannot be rendered")
+	}
+	return r.Render()
+}
+
+//#endregion interactive mode (model) implementation
@@ -0,0 +1,66 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"idx1", "idx1", true},
+		{"IDX1", "idx1", true},
+		{"idx1", "idx10", false},
+		{"idx*", "IDX10", true},
+		{"idx*", "webserver", false},
+		{"*", "anything", true},
+		{"id*x", "idx", false}, // only a trailing wildcard is special
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyAndCheck(t *testing.T) {
+	m := map[string]int{"east-1": 1, "east-2": 2, "west-1": 3}
+
+	fs := pflag.FlagSet{}
+	AddFlag(&fs)
+	if err := fs.Parse([]string{"--indexer", "EAST*", "--indexer=west-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := Apply(&fs, m); len(got) != 3 {
+		t.Fatalf("expected all indexers to match, got %v", got)
+	}
+
+	fs = pflag.FlagSet{}
+	AddFlag(&fs)
+	if got := Apply(&fs, m); len(got) != 3 {
+		t.Fatalf("expected no filtering without --indexer, got %v", got)
+	}
+
+	if err := Check([]string{"east-2", "west*"}, []string{"east-1", "east-2", "west-1"}); err != nil {
+		t.Fatal(err)
+	}
+	err := Check([]string{"east-1", "north*"}, []string{"west-1", "east-1"})
+	if err == nil {
+		t.Fatal("expected an error for an unmatched pattern")
+	}
+	if !strings.Contains(err.Error(), "north*") || !strings.Contains(err.Error(), "east-1, west-1") {
+		t.Fatalf("error does not describe the unmatched pattern and available indexers: %v", err)
+	}
+}
@@ -10,9 +10,12 @@ package indexers
 
 import (
 	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/check"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/stats"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/storage"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/wells"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/watch"
 
 	"github.com/spf13/cobra"
 )
@@ -29,7 +32,8 @@ func NewIndexersNav() *cobra.Command {
 	return treeutils.GenerateNav(use, short, long, aliases,
 		[]*cobra.Command{},
 		[]action.Pair{
-			storage.NewIndexerStorageAction(),
-			stats.NewStatsListAction(),
+			watch.Wrap(check.Wrap(storage.NewIndexerStorageAction())),
+			watch.Wrap(check.Wrap(stats.NewStatsListAction())),
+			watch.Wrap(wells.NewWellsListAction()),
 		})
 }
@@ -2,7 +2,9 @@ package stats
 
 import (
 	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
 	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/filter"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldlist"
 
 	grav "github.com/gravwell/gravwell/v3/client"
@@ -30,8 +32,27 @@ func NewStatsListAction() action.Pair {
 		panic(err)
 	}
 
-	return scaffoldlist.NewListAction(use, short, long, cols,
-		namedStats{}, list, nil)
+	return filter.Wrap(scaffoldlist.NewListAction(use, short, long, cols,
+		namedStats{}, list, flags), names)
+}
+
+func flags() pflag.FlagSet {
+	addtlFlags := pflag.FlagSet{}
+	filter.AddFlag(&addtlFlags)
+	return addtlFlags
+}
+
+// returns the name of every indexer reporting stats
+func names() ([]string, error) {
+	stats, err := connection.Client.GetSystemStats()
+	if err != nil {
+		return nil, err
+	}
+	n := make([]string, 0, len(stats))
+	for k := range stats {
+		n = append(n, k)
+	}
+	return n, nil
 }
 
 func list(c *grav.Client, fs *pflag.FlagSet) ([]namedStats, error) {
@@ -41,13 +62,16 @@ func list(c *grav.Client, fs *pflag.FlagSet) ([]namedStats, error) {
 	if err != nil {
 		return []namedStats{}, err
 	}
-	ns = make([]namedStats, len(stats))
+	stats = filter.Apply(fs, stats)
+	ns = make([]namedStats, 0, len(stats))
 
 	// wrap the results in namedStats
-	var i int = 0
 	for k, v := range stats {
-		ns[i] = namedStats{Indexer: k, Stats: *v.Stats}
-		i += 1
+		if v.Stats == nil { // indexer is down
+			clilog.Writer.Warnf("indexer %v did not report stats: %v", k, v.Error)
+			continue
+		}
+		ns = append(ns, namedStats{Indexer: k, Stats: *v.Stats})
 	}
 
 	return ns, nil
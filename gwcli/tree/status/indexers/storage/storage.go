@@ -18,12 +18,20 @@ import (
 	"github.com/gravwell/gravwell/v3/gwcli/connection"
 	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
 	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/filter"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
 	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/gravwell/gravwell/v3/utils/weave"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -36,13 +44,15 @@ const (
 
 const (
 	defaultPrecision = 2
+	// capacity used, as a percent, at which the summary is highlighted as a warning
+	highUsagePercent = 80
 )
 
 func NewIndexerStorageAction() action.Pair {
-	return scaffold.NewBasicAction(use, short, long, []string{},
-		func(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	return filter.Wrap(scaffold.NewBasicAction(use, short, long, []string{},
+		func(cmd *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
 			// check for mutually exclusive flags
-			var json, csv, table bool
+			var json, yaml, csv, table bool
 			var set int
 			var err error
 			if json, err = fs.GetBool(ft.Name.JSON); err != nil {
@@ -50,6 +60,11 @@ func NewIndexerStorageAction() action.Pair {
 			} else if json {
 				set += 1
 			}
+			if yaml, err = fs.GetBool(ft.Name.YAML); err != nil {
+				clilog.LogFlagFailedGet(ft.Name.YAML, err)
+			} else if yaml {
+				set += 1
+			}
 			if csv, err = fs.GetBool(ft.Name.CSV); err != nil {
 				clilog.LogFlagFailedGet(ft.Name.CSV, err)
 			} else if csv {
@@ -61,7 +76,7 @@ func NewIndexerStorageAction() action.Pair {
 				set += 1
 			}
 			if set > 1 { // too many were set
-				return "[json csv table] are mutually exclusive", nil
+				return "[json yaml csv table] are mutually exclusive", nil
 			} else if set == 0 { // none were set
 				table = true
 			}
@@ -70,6 +85,14 @@ func NewIndexerStorageAction() action.Pair {
 			if err != nil {
 				return err.Error(), nil
 			}
+			ss = filter.Apply(fs, ss)
+			// system stats are only used for capacity; the summary can survive without them
+			sys, err := connection.Client.GetSystemStats()
+			if err != nil {
+				clilog.Writer.Warnf("failed to fetch system stats for capacity: %v", err)
+				sys = nil
+			}
+			sum := summarize(ss, sys)
 
 			var precisionF string // precision format
 			// pull precision from flag
@@ -83,81 +106,214 @@ func NewIndexerStorageAction() action.Pair {
 			var res string
 			switch {
 			case json:
-				res = toJSON(ss, precisionF)
+				res = toJSON(ss, sum)
+			case yaml:
+				res = toYAML(ss, sum)
 			case csv:
-				res = toCSV(ss, precisionF)
+				res = toCSV(ss, sum, precisionF)
 			case table:
-				res = toTbls(ss, precisionF)
+				res = toTbls(ss, sum, precisionF, treeutils.ColorEnabled(fs, cmd.OutOrStdout()))
 			}
 
 			return res, nil
 
 		},
-		flags)
+		flags), names)
 }
 
-// reformat the results into a single json encoding
-func toJSON(ss map[string]types.StorageStats, precF string) string {
-	type indexer struct {
-		Kind         string
-		EntryCount   uint64
-		DataIngested string
-		DataStored   string
+// returns the name of every indexer reporting storage stats
+func names() ([]string, error) {
+	ss, err := connection.Client.GetStorageStats()
+	if err != nil {
+		return nil, err
 	}
+	return sortedIndexers(ss), nil
+}
 
-	var res []string
-	for k, v := range ss {
-		// encode the records as a map
-		var m map[string]any = map[string]any{
-			k: []indexer{
-				{
-					Kind:         "hot",
-					EntryCount:   v.EntryCountHot,
-					DataIngested: gb(v.DataIngestedHot, precF),
-					DataStored:   gb(v.DataStoredHot, precF),
-				},
-				{
-					Kind:         "cold",
-					EntryCount:   v.EntryCountCold,
-					DataIngested: gb(v.DataIngestedCold, precF),
-					DataStored:   gb(v.DataStoredCold, precF),
-				},
-			},
-		}
+// storage statistics for one tier of an indexer, with raw byte counts
+type tierStorage struct {
+	Entries       uint64 `json:"entries"`
+	IngestedBytes uint64 `json:"ingestedBytes"`
+	StoredBytes   uint64 `json:"storedBytes"`
+}
+
+// structured storage statistics for a single indexer
+type indexerStorage struct {
+	Indexer       string      `json:"indexer"`
+	CoverageStart time.Time   `json:"coverageStart"`
+	CoverageEnd   time.Time   `json:"coverageEnd"`
+	Hot           tierStorage `json:"hot"`
+	Cold          tierStorage `json:"cold"`
+}
+
+// aggregate storage statistics across every indexer that reported
+type storageSummary struct {
+	Indexers      int      `json:"indexers"`          // number of indexers included in the totals
+	Skipped       []string `json:"skipped,omitempty"` // indexers that failed to report
+	Entries       uint64   `json:"entries"`
+	IngestedBytes uint64   `json:"ingestedBytes"`
+	StoredBytes   uint64   `json:"storedBytes"`
+	// total disk space of the included indexers; 0 if unknown
+	CapacityBytes uint64 `json:"capacityBytes,omitempty"`
+	// StoredBytes as a percentage of CapacityBytes; nil if capacity is unknown
+	CapacityUsed *float64 `json:"capacityUsedPercent,omitempty"`
+}
+
+// top level document for structured (JSON and YAML) output
+type storageReport struct {
+	Indexers []indexerStorage `json:"indexers"`
+	Summary  storageSummary   `json:"summary"`
+}
 
-		b, err := json.Marshal(m)
-		if err != nil {
-			clilog.Writer.Errorf("Failed to marshal storage stats: %v", err)
+// Totals the storage statistics of every indexer.
+// If system stats are given, indexers that errored out are skipped (rather than counted as
+// zero) and the disks of the remainder are used to determine capacity.
+func summarize(ss map[string]types.StorageStats, sys map[string]types.SysStats) storageSummary {
+	var sum storageSummary
+	disks := map[string]bool{} // disk IDs already counted against capacity
+	for _, k := range sortedIndexers(ss) {
+		var host *types.HostSysStats
+		if sys != nil {
+			st, ok := sys[k]
+			if !ok || st.Error != "" || st.Stats == nil {
+				sum.Skipped = append(sum.Skipped, k)
+				continue
+			}
+			host = st.Stats
+		}
+		v := ss[k]
+		sum.Indexers += 1
+		sum.Entries += v.EntryCountHot + v.EntryCountCold
+		sum.IngestedBytes += v.DataIngestedHot + v.DataIngestedCold
+		sum.StoredBytes += v.DataStoredHot + v.DataStoredCold
+		if host == nil {
+			continue
+		}
+		for _, d := range host.Disks {
+			id := d.ID
+			if id == "" {
+				id = k + ":" + d.Mount + ":" + d.Partition
+			}
+			if !disks[id] {
+				disks[id] = true
+				sum.CapacityBytes += d.Total
+			}
 		}
-		res = append(res, (string(b)))
 	}
+	if sum.CapacityBytes > 0 {
+		pct := float64(sum.StoredBytes) / float64(sum.CapacityBytes) * 100
+		sum.CapacityUsed = &pct
+	}
+	return sum
+}
+
+// format the total capacity, if known
+func (sum storageSummary) capacity(precF string) string {
+	if sum.CapacityBytes == 0 {
+		return "unknown"
+	}
+	return gb(sum.CapacityBytes, precF)
+}
+
+// format the percentage of capacity used, if known
+func (sum storageSummary) capacityUsed(precF string) string {
+	if sum.CapacityUsed == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf(precF+"%%", *sum.CapacityUsed)
+}
+
+// build the structured report, ordered by indexer name
+func toReport(ss map[string]types.StorageStats, sum storageSummary) storageReport {
+	r := storageReport{Indexers: make([]indexerStorage, 0, len(ss)), Summary: sum}
+	for _, k := range sortedIndexers(ss) {
+		v := ss[k]
+		r.Indexers = append(r.Indexers, indexerStorage{
+			Indexer:       k,
+			CoverageStart: v.CoverageStart,
+			CoverageEnd:   v.CoverageEnd,
+			Hot: tierStorage{
+				Entries:       v.EntryCountHot,
+				IngestedBytes: v.DataIngestedHot,
+				StoredBytes:   v.DataStoredHot,
+			},
+			Cold: tierStorage{
+				Entries:       v.EntryCountCold,
+				IngestedBytes: v.DataIngestedCold,
+				StoredBytes:   v.DataStoredCold,
+			},
+		})
+	}
+	return r
+}
+
+// reformat the results into a single json encoding
+func toJSON(ss map[string]types.StorageStats, sum storageSummary) string {
+	b, err := json.Marshal(toReport(ss, sum))
+	if err != nil {
+		clilog.Writer.Errorf("Failed to marshal storage stats: %v", err)
+		return err.Error()
+	}
+	return string(b)
+}
 
-	return strings.Join(res, "\n")
+// reformat the results into a single yaml encoding
+func toYAML(ss map[string]types.StorageStats, sum storageSummary) string {
+	res, err := weave.JSONToYAML([]byte(toJSON(ss, sum)))
+	if err != nil {
+		clilog.Writer.Errorf("Failed to encode storage stats as YAML: %v", err)
+		return err.Error()
+	}
+	return res
+}
 
+// returns the indexer names in a stable order
+func sortedIndexers(ss map[string]types.StorageStats) []string {
+	names := make([]string, 0, len(ss))
+	for k := range ss {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // reformat the results into a single csv encoding
-func toCSV(ss map[string]types.StorageStats, precF string) string {
+// The final row totals all reporting indexers.
+func toCSV(ss map[string]types.StorageStats, sum storageSummary, precF string) string {
 	var buf bytes.Buffer
 	w := csv.NewWriter(&buf)
 
 	w.Write([]string{"indexer", "kind", "entries", "ingested", "stored"})
-	for k, v := range ss {
+	for _, k := range sortedIndexers(ss) {
+		v := ss[k]
 		w.Write([]string{k, "hot", strconv.FormatUint(v.EntryCountHot, 10), gb(v.DataIngestedHot, precF), gb(v.DataStoredHot, precF)})
 		w.Write([]string{k, "cold", strconv.FormatUint(v.EntryCountCold, 10), gb(v.DataIngestedCold, precF), gb(v.DataStoredCold, precF)})
 	}
+	w.Write([]string{"total", "all", strconv.FormatUint(sum.Entries, 10), gb(sum.IngestedBytes, precF), gb(sum.StoredBytes, precF)})
 
 	w.Flush()
 
 	return buf.String()
 }
 
-// reformat the results into one table per index
-func toTbls(ss map[string]types.StorageStats, precF string) string {
+// reformat the results into one table per index, followed by a summary table
+// If color is set, indexers that are down are highlighted as errors and high capacity usage as a
+// warning.
+func toTbls(ss map[string]types.StorageStats, sum storageSummary, precF string, color bool) string {
+	newTbl, errS, warnS := plainTable, lipgloss.NewStyle(), lipgloss.NewStyle()
+	if color {
+		newTbl, errS, warnS = stylesheet.Table, stylesheet.ErrStyle, stylesheet.WarnStyle
+	}
+
 	var sb strings.Builder
-	for k, v := range ss {
+	for _, k := range sortedIndexers(ss) {
+		v := ss[k]
+		if slices.Contains(sum.Skipped, k) {
+			sb.WriteString(errS.Render(k+": down") + "\n")
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("%v: %v -> %v\n", k, v.CoverageStart, v.CoverageEnd))
-		tbl := stylesheet.Table()
+		tbl := newTbl()
 		tbl.Headers("kind", "entries", "ingested", "stored")
 
 		tbl.Row(
@@ -175,9 +331,34 @@ func toTbls(ss map[string]types.StorageStats, precF string) string {
 		sb.WriteString(tbl.Render() + "\n")
 	}
 
+	sb.WriteString(fmt.Sprintf("total: %d indexer(s)", sum.Indexers))
+	if len(sum.Skipped) > 0 {
+		sb.WriteString(errS.Render(fmt.Sprintf(" (skipped %v)", strings.Join(sum.Skipped, ", "))))
+	}
+	sb.WriteString("\n")
+	used := sum.capacityUsed(precF)
+	if sum.CapacityUsed != nil && *sum.CapacityUsed >= highUsagePercent {
+		used = warnS.Render(used)
+	}
+	tbl := newTbl()
+	tbl.Headers("entries", "ingested", "stored", "capacity", "used")
+	tbl.Row(
+		strconv.FormatUint(sum.Entries, 10),
+		gb(sum.IngestedBytes, precF),
+		gb(sum.StoredBytes, precF),
+		sum.capacity(precF),
+		used,
+	)
+	sb.WriteString(tbl.Render() + "\n")
+
 	return sb.String()
 }
 
+// an unstyled table skeleton, for when color is disabled
+func plainTable() *table.Table {
+	return table.New().Border(lipgloss.NormalBorder()).BorderRow(true)
+}
+
 // Add additional flags for data representation, a la scaffoldlist.
 func flags() pflag.FlagSet {
 	fs := pflag.FlagSet{}
@@ -185,7 +366,9 @@ func flags() pflag.FlagSet {
 		"decimal precision when displaying floating point numbers")
 	fs.Bool(ft.Name.CSV, false, ft.Usage.CSV)
 	fs.Bool(ft.Name.JSON, false, ft.Usage.JSON)
+	fs.Bool(ft.Name.YAML, false, ft.Usage.YAML)
 	fs.Bool(ft.Name.Table, false, ft.Usage.Table)
+	filter.AddFlag(&fs)
 	return fs
 }
 
@@ -0,0 +1,51 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package storage
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+func TestSummarize(t *testing.T) {
+	ss := map[string]types.StorageStats{
+		"idx1": {EntryCountHot: 10, EntryCountCold: 5, DataStoredHot: 100, DataStoredCold: 50},
+		"idx2": {EntryCountHot: 1, DataStoredHot: 150},
+		"idx3": {}, // failed to report
+	}
+	sys := map[string]types.SysStats{
+		"idx1": {Stats: &types.HostSysStats{Disks: []types.DiskStats{
+			{ID: "a", Total: 500}, {ID: "b", Total: 500}, {ID: "a", Total: 500},
+		}}},
+		"idx2": {Stats: &types.HostSysStats{Disks: []types.DiskStats{{ID: "c", Total: 1000}}}},
+		"idx3": {Error: "indexer unreachable"},
+	}
+
+	sum := summarize(ss, sys)
+	if sum.Indexers != 2 || !slices.Equal(sum.Skipped, []string{"idx3"}) {
+		t.Fatalf("bad indexer accounting: %d included, skipped %v", sum.Indexers, sum.Skipped)
+	}
+	if sum.Entries != 16 || sum.StoredBytes != 300 {
+		t.Fatalf("bad totals: %d entries, %d bytes", sum.Entries, sum.StoredBytes)
+	}
+	if sum.CapacityBytes != 2000 {
+		t.Fatalf("expected duplicate disks to be counted once; got capacity %d", sum.CapacityBytes)
+	}
+	if sum.CapacityUsed == nil || *sum.CapacityUsed != 15 {
+		t.Fatalf("bad capacity used: %v", sum.CapacityUsed)
+	}
+
+	// without system stats, nothing can be identified as failed and capacity is unknown
+	sum = summarize(ss, nil)
+	if sum.Indexers != 3 || len(sum.Skipped) != 0 || sum.CapacityUsed != nil {
+		t.Fatalf("unexpected summary without system stats: %+v", sum)
+	}
+}
@@ -0,0 +1,159 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package ingesters implements an action for reviewing the ingesters attached to each indexer.
+package ingesters
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers/filter"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldlist"
+
+	grav "github.com/gravwell/gravwell/v3/client"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "ingesters"
+	short string = "review the ingesters connected to each indexer"
+	long  string = "Review the ingesters connected to each indexer, including those that have been seen" +
+		" before but are currently disconnected.\n" +
+		"An ingester connected to multiple indexers is listed once per indexer."
+)
+
+const (
+	nameFlag = "name"
+
+	stateConnected    = "connected"
+	stateDisconnected = "disconnected"
+)
+
+var (
+	aliases        []string = []string{"ingester", "ingest"}
+	defaultColumns []string = []string{"Name", "Indexer", "State", "Uptime", "Entries", "Tags"}
+)
+
+// a single ingester as seen by a single indexer
+type ingesterStatus struct {
+	Name          string
+	Indexer       string
+	State         string // connected or disconnected
+	Uptime        time.Duration
+	Entries       uint64
+	Bytes         uint64
+	Tags          []string // tags the ingester is authorized for
+	Version       string
+	UUID          string
+	Label         string
+	Hostname      string
+	RemoteAddress string
+	LastSeen      time.Time
+}
+
+func NewIngestersListAction() action.Pair {
+	p := scaffoldlist.NewListAction(use, short, long, defaultColumns,
+		ingesterStatus{}, list, flags)
+	p.Action.Aliases = aliases
+	return p
+}
+
+func flags() pflag.FlagSet {
+	addtlFlags := pflag.FlagSet{}
+	addtlFlags.StringSlice(nameFlag, []string{},
+		"only display the named ingester(s). May be given multiple times.\n"+
+			"Case-insensitive; a trailing '*' matches any suffix.")
+	return addtlFlags
+}
+
+func list(c *grav.Client, fs *pflag.FlagSet) ([]ingesterStatus, error) {
+	patterns, err := fs.GetStringSlice(nameFlag)
+	if err != nil {
+		clilog.LogFlagFailedGet(nameFlag, err)
+	}
+	stats, err := c.GetIngesterStats()
+	if err != nil {
+		return nil, err
+	}
+	return flatten(stats, patterns), nil
+}
+
+// flatten returns a row for every ingester on every indexer whose name is matched by a pattern,
+// or every ingester if there are no patterns. Rows are sorted by name, then indexer.
+func flatten(stats map[string]types.IngestStats, patterns []string) []ingesterStatus {
+	matched := func(name string) bool {
+		if len(patterns) == 0 {
+			return true
+		}
+		for _, p := range patterns {
+			if filter.Match(p, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var is []ingesterStatus
+	for idx, s := range stats {
+		for _, ig := range s.Ingesters {
+			name := ig.Name
+			if name == "" {
+				name = ig.State.Name
+			}
+			if !matched(name) {
+				continue
+			}
+			is = append(is, ingesterStatus{
+				Name:          name,
+				Indexer:       idx,
+				State:         stateConnected,
+				Uptime:        ig.Uptime.Truncate(time.Second),
+				Entries:       ig.Count,
+				Bytes:         ig.Size,
+				Tags:          ig.Tags,
+				Version:       ig.Version,
+				UUID:          ig.UUID,
+				Label:         ig.State.Label,
+				Hostname:      ig.State.Hostname,
+				RemoteAddress: ig.RemoteAddress,
+				LastSeen:      ig.State.LastSeen,
+			})
+		}
+		for _, st := range s.Missing {
+			if !matched(st.Name) {
+				continue
+			}
+			is = append(is, ingesterStatus{
+				Name:     st.Name,
+				Indexer:  idx,
+				State:    stateDisconnected,
+				Entries:  st.Entries,
+				Bytes:    st.Size,
+				Tags:     st.Tags,
+				Version:  st.Version,
+				UUID:     st.UUID,
+				Label:    st.Label,
+				Hostname: st.Hostname,
+				LastSeen: st.LastSeen,
+			})
+		}
+	}
+
+	// maps are unordered; keep the output stable
+	sort.Slice(is, func(i, j int) bool {
+		if is[i].Name != is[j].Name {
+			return is[i].Name < is[j].Name
+		}
+		return is[i].Indexer < is[j].Indexer
+	})
+	return is
+}
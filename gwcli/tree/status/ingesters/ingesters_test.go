@@ -0,0 +1,61 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingesters
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/gravwell/gravwell/v3/ingest"
+)
+
+func TestFlatten(t *testing.T) {
+	stats := map[string]types.IngestStats{
+		"idx2": {
+			Ingesters: []types.IngesterStats{
+				{Name: "SimpleRelay", Count: 5, Uptime: 90*time.Second + time.Millisecond, Tags: []string{"syslog"}},
+			},
+		},
+		"idx1": {
+			Ingesters: []types.IngesterStats{
+				{Name: "SimpleRelay", Count: 10, Size: 100, Tags: []string{"syslog", "default"}},
+				{State: ingest.IngesterState{Name: "File Follower"}, Count: 1},
+			},
+			Missing: []ingest.IngesterState{{Name: "Netflow", Entries: 7, Tags: []string{"netflow"}}},
+		},
+	}
+
+	is := flatten(stats, nil)
+	var got []string
+	for _, v := range is {
+		got = append(got, v.Name+"@"+v.Indexer+"="+v.State)
+	}
+	exp := []string{"File Follower@idx1=connected", "Netflow@idx1=disconnected",
+		"SimpleRelay@idx1=connected", "SimpleRelay@idx2=connected"}
+	if !slices.Equal(got, exp) {
+		t.Fatalf("bad rows:\n%v\n%v", got, exp)
+	}
+	if is[1].Entries != 7 || !slices.Equal(is[1].Tags, []string{"netflow"}) {
+		t.Fatalf("bad disconnected ingester: %+v", is[1])
+	} else if is[3].Entries != 5 || is[3].Uptime != 90*time.Second {
+		t.Fatalf("bad connected ingester: %+v", is[3])
+	}
+
+	is = flatten(stats, []string{"simple*", "NETFLOW"})
+	got = got[:0]
+	for _, v := range is {
+		got = append(got, v.Name+"@"+v.Indexer)
+	}
+	exp = []string{"Netflow@idx1", "SimpleRelay@idx1", "SimpleRelay@idx2"}
+	if !slices.Equal(got, exp) {
+		t.Fatalf("bad filtered rows:\n%v\n%v", got, exp)
+	}
+}
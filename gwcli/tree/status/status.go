@@ -11,7 +11,9 @@ package status
 import (
 	"github.com/gravwell/gravwell/v3/gwcli/action"
 	"github.com/gravwell/gravwell/v3/gwcli/tree/status/indexers"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/status/ingesters"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/watch"
 
 	"github.com/spf13/cobra"
 )
@@ -29,5 +31,7 @@ func NewStatusNav() *cobra.Command {
 		[]*cobra.Command{
 			indexers.NewIndexersNav(),
 		},
-		[]action.Pair{})
+		[]action.Pair{
+			watch.Wrap(ingesters.NewIngestersListAction()),
+		})
 }
@@ -0,0 +1,173 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package tags implements an action for listing the tags available to query.
+package tags
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldlist"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	grav "github.com/gravwell/gravwell/v3/client"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "tags"
+	short string = "list tags available to query"
+	long  string = "Lists the tags on the system.\n" +
+		"Bare arguments filter the list to tags containing any of the arguments " +
+		"(case-insensitive).\n" +
+		"--verbose also displays the wells and indexers each tag is stored in and the number of " +
+		"entries in those wells. Entry counts are tracked per well, so they include other tags " +
+		"sharing the same wells."
+)
+
+var (
+	aliases        []string = []string{"tag"}
+	defaultColumns []string = []string{"Name"}
+	verboseColumns []string = []string{"Name", "Entries", "Wells", "Indexers"}
+)
+
+// a single tag and where it is stored
+type tag struct {
+	Name     string
+	Entries  uint64 // entries across the wells holding the tag
+	Wells    []string
+	Indexers []string
+}
+
+func NewTagsAction() action.Pair {
+	p := scaffoldlist.NewListAction(use, short, long, defaultColumns,
+		tag{}, list, nil, scaffoldlist.WithVerboseColumns(verboseColumns))
+	p.Action.Aliases = aliases
+	p.Action.Example = "./gwcli tags --verbose syslog"
+	p.Action.ValidArgsFunction = treeutils.CompleteFrom(func() ([]string, error) {
+		return connection.Client.GetTags()
+	})
+	return p
+}
+
+func list(c *grav.Client, fs *pflag.FlagSet) ([]tag, error) {
+	names, err := c.GetTags()
+	if err != nil {
+		return nil, err
+	}
+	names = filter(names, fs.Args())
+
+	// well information is only fetched if it will be displayed
+	var v bool
+	if v, err = fs.GetBool("verbose"); err != nil {
+		clilog.LogFlagFailedGet("verbose", err)
+	}
+	if !v {
+		tags := make([]tag, len(names))
+		for i, n := range names {
+			tags[i] = tag{Name: n}
+		}
+		return tags, nil
+	}
+
+	// well data maps indexer names to their UUIDs, which the per-indexer storage call requires
+	wd, err := c.WellData()
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]map[string]types.PerWellStorageStats, len(wd))
+	for idx, iwd := range wd {
+		s, err := c.GetIndexerStorageStats(iwd.UUID)
+		if err != nil {
+			// do not allow a single unresponsive indexer to hide the rest
+			clilog.Writer.Warnf("failed to fetch storage stats for indexer %v: %v", idx, err)
+			continue
+		}
+		stats[idx] = s
+	}
+	return locate(names, stats), nil
+}
+
+// filter returns the names containing any of the given substrings, sorted.
+// All names are returned if no substrings are given.
+func filter(names []string, substrs []string) []string {
+	r := make([]string, 0, len(names))
+	for _, n := range names {
+		if len(substrs) == 0 {
+			r = append(r, n)
+			continue
+		}
+		for _, s := range substrs {
+			if strings.Contains(strings.ToLower(n), strings.ToLower(s)) {
+				r = append(r, n)
+				break
+			}
+		}
+	}
+	sort.Strings(r)
+	return r
+}
+
+// locate associates each tag to the wells and indexers it is stored in, given the per-well storage
+// stats of each indexer.
+// Tags not explicitly assigned to a well on an indexer are stored in that indexer's default well.
+func locate(names []string, stats map[string]map[string]types.PerWellStorageStats) []tag {
+	tags := make([]tag, len(names))
+	for i, n := range names {
+		t := tag{Name: n}
+		wells := map[string]bool{}
+		for idx, iws := range stats {
+			var def, found bool
+			var defName string
+			var defStats types.PerWellStorageStats
+			for name, s := range iws {
+				if s.WellName != "" {
+					name = s.WellName
+				}
+				if len(s.Tags) == 0 {
+					def, defName, defStats = true, name, s
+				} else if contains(s.Tags, n) {
+					found = true
+					t.add(idx, name, s, wells)
+				}
+			}
+			if !found && def {
+				t.add(idx, defName, defStats, wells)
+			}
+		}
+		for w := range wells {
+			t.Wells = append(t.Wells, w)
+		}
+		sort.Strings(t.Wells)
+		sort.Strings(t.Indexers)
+		tags[i] = t
+	}
+	return tags
+}
+
+func (t *tag) add(indexer, well string, s types.PerWellStorageStats, wells map[string]bool) {
+	t.Entries += s.EntryCountHot + s.EntryCountCold
+	wells[well] = true
+	if !contains(t.Indexers, indexer) {
+		t.Indexers = append(t.Indexers, indexer)
+	}
+}
+
+func contains(set []string, v string) bool {
+	for _, s := range set {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
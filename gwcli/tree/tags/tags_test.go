@@ -0,0 +1,66 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package tags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/client/types"
+)
+
+func TestFilter(t *testing.T) {
+	names := []string{"syslog", "gravwell", "Netflow", "winlog"}
+	tests := []struct {
+		name    string
+		substrs []string
+		want    []string
+	}{
+		{"none", nil, []string{"Netflow", "gravwell", "syslog", "winlog"}},
+		{"single", []string{"log"}, []string{"syslog", "winlog"}},
+		{"case-insensitive", []string{"netf"}, []string{"Netflow"}},
+		{"any", []string{"grav", "sys"}, []string{"gravwell", "syslog"}},
+		{"no match", []string{"pcap"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter(names, tt.substrs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocate(t *testing.T) {
+	well := func(name string, entries uint64, tags ...string) types.PerWellStorageStats {
+		var s types.PerWellStorageStats
+		s.WellName = name
+		s.EntryCountHot = entries
+		s.Tags = tags
+		return s
+	}
+	stats := map[string]map[string]types.PerWellStorageStats{
+		"idx1": {
+			"default": well("default", 5),
+			"syslog":  well("syslog", 10, "syslog", "kernel"),
+		},
+		"idx2": {
+			"default": well("default", 1),
+			"logs":    well("logs", 20, "syslog"),
+		},
+	}
+	got := locate([]string{"gravwell", "syslog"}, stats)
+	want := []tag{
+		{Name: "gravwell", Entries: 6, Wells: []string{"default"}, Indexers: []string{"idx1", "idx2"}},
+		{Name: "syslog", Entries: 30, Wells: []string{"logs", "syslog"}, Indexers: []string{"idx1", "idx2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("locate() = %+v, want %+v", got, want)
+	}
+}
@@ -11,8 +11,10 @@ package logout
 
 import (
 	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
 	"github.com/gravwell/gravwell/v3/gwcli/connection"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -22,18 +24,27 @@ import (
 const (
 	use   string = "logout"
 	short string = "logout and end the session"
-	long  string = "Ends your current session and invalids your login token, forcing the next" +
-		" login to request credentials."
+	long  string = "Ends your current session, revoking your login token, and deletes the token" +
+		" saved to your profile (or the shared token file), forcing the next login to request" +
+		" credentials.\n" +
+		"An expired token is deleted without contacting the server."
 )
 
 var aliases []string = []string{}
 
 func NewUserLogoutAction() action.Pair {
-	return scaffold.NewBasicAction(use, short, long, aliases,
+	p := scaffold.NewBasicAction(use, short, long, aliases,
 		func(*cobra.Command, *pflag.FlagSet) (string, tea.Cmd) {
-			connection.Client.Logout()
+			err := connection.Logout()
 			connection.End()
+			if err != nil {
+				clilog.Writer.Errorf("failed to log out: %v", err)
+				return "Logout incomplete: " + err.Error(), tea.Quit
+			}
 
 			return "Successfully logged out", tea.Quit
 		}, nil)
+	// an expired token cannot be used to log in, but its copy still needs deleting
+	treeutils.TokenLoginOnly(p.Action)
+	return p
 }
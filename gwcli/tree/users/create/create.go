@@ -0,0 +1,107 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package create implements an action for creating a new user.
+package create
+
+import (
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/gate"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldcreate"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/spf13/pflag"
+)
+
+const (
+	kuser  = "user"
+	kpass  = "pass"
+	kname  = "name"
+	kemail = "email"
+
+	flagAdmin = "admin"
+)
+
+func NewUsersCreateAction() action.Pair {
+	// --username and --password are taken by the login flags
+	fields := scaffoldcreate.Config{
+		kuser: scaffoldcreate.Field{
+			Required: true,
+			Title:    "username",
+			Usage:    "login name of the new user",
+			Type:     scaffoldcreate.Text,
+			FlagName: "user",
+			Order:    100,
+		},
+		kpass: scaffoldcreate.Field{
+			Required: true,
+			Title:    "password",
+			Usage:    "initial password of the new user",
+			Type:     scaffoldcreate.Text,
+			FlagName: "pass",
+			Order:    90,
+			CustomTIFuncInit: func() textinput.Model {
+				ti := stylesheet.NewTI("", false)
+				ti.EchoMode = textinput.EchoPassword
+				return ti
+			},
+		},
+		kname: scaffoldcreate.Field{
+			Required:      true,
+			Title:         "name",
+			Usage:         "real name of the new user",
+			Type:          scaffoldcreate.Text,
+			FlagName:      "name",
+			FlagShorthand: 'n',
+			Order:         80,
+		},
+		kemail: scaffoldcreate.Field{
+			Required:      true,
+			Title:         "email",
+			Usage:         "email address of the new user",
+			Type:          scaffoldcreate.Text,
+			FlagName:      "email",
+			FlagShorthand: 'e',
+			Order:         70,
+		},
+	}
+
+	p := scaffoldcreate.NewCreateAction("user", fields, create, flags)
+	p.Action.Long = "Creates a new user. Admin-only."
+	p.Action.Example = "./gwcli users create --user jsmith --pass hunter22 --name 'J Smith' " +
+		"--email jsmith@example.com"
+	return gate.Wrap(p)
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.Bool(flagAdmin, false, "grant the new user admin privileges.")
+	return fs
+}
+
+func create(_ scaffoldcreate.Config, vals scaffoldcreate.Values, fs *pflag.FlagSet) (any, string, error) {
+	admin, err := fs.GetBool(flagAdmin)
+	if err != nil {
+		clilog.LogFlagFailedGet(flagAdmin, err)
+		return nil, "", err
+	}
+	if err := connection.Client.AddUser(vals[kuser], vals[kpass], vals[kname], vals[kemail],
+		admin); err != nil {
+		return nil, "", err
+	}
+	// the server does not return the new user, so look up their UID
+	ud, err := connection.Client.LookupUser(vals[kuser])
+	if err != nil {
+		clilog.Writer.Warnf("failed to look up new user %v: %v", vals[kuser], err)
+		return vals[kuser], "", nil
+	}
+	return ud.UID, "", nil
+}
@@ -0,0 +1,117 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+Package gate restricts the user administration actions to admins and provides the user lookup they
+share.
+
+Actions are wrapped via Wrap, which rejects non-admins before the action runs, from Cobra or from
+Mother.
+Users are identified by username or UID, resolved via Lookup; Complete offers usernames as
+completions.
+*/
+package gate
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	grav "github.com/gravwell/gravwell/v3/client"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ErrNotAdmin is returned when a non-admin attempts to administer users.
+var ErrNotAdmin = errors.New("user administration requires an admin account; " +
+	"you are logged in as a non-admin user")
+
+// Check returns ErrNotAdmin if the logged in user is not an admin.
+func Check() error {
+	if !connection.MyInfo.Admin {
+		return ErrNotAdmin
+	}
+	return nil
+}
+
+// Lookup fetches a user by username or, failing that, by UID.
+func Lookup(id string) (types.UserDetails, error) {
+	ud, err := connection.Client.LookupUser(id)
+	if err == nil || !errors.Is(err, grav.ErrNotFound) {
+		return ud, err
+	}
+	if uid, perr := strconv.ParseInt(id, 10, 32); perr == nil {
+		return connection.Client.GetUserInfo(int32(uid))
+	}
+	return ud, errors.New("no user with username or UID " + id + " was found")
+}
+
+// Complete offers the usernames on the system.
+var Complete = treeutils.CompleteFrom(func() ([]string, error) {
+	uds, err := connection.Client.GetUserList()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(uds))
+	for i, ud := range uds {
+		names[i] = ud.User
+	}
+	return names, nil
+})
+
+// Wrap rejects non-admins before the given action runs.
+// From Cobra, the command returns an error (and thus exits non-zero).
+// From Mother, the error is reported in place of the action.
+func Wrap(p action.Pair) action.Pair {
+	preRunE := p.Action.PreRunE
+	p.Action.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := Check(); err != nil {
+			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
+			return err
+		}
+		if preRunE != nil {
+			return preRunE(cmd, args)
+		}
+		return nil
+	}
+	// the error was already printed
+	p.Action.SilenceErrors = true
+
+	p.Model = &model{Model: p.Model}
+	return p
+}
+
+//#region interactive mode (model) implementation
+
+type model struct {
+	action.Model
+}
+
+func (m *model) SetArgs(inherited *pflag.FlagSet, tokens []string) (string, tea.Cmd, error) {
+	if err := Check(); err != nil {
+		return err.Error(), nil, nil
+	}
+	return m.Model.SetArgs(inherited, tokens)
+}
+
+// Render passes through to the wrapped model, if it is capable.
+func (m *model) Render() (string, error) {
+	r, ok := m.Model.(interface{ Render() (string, error) })
+	if !ok {
+		return "", errors.New("action cannot be rendered")
+	}
+	return r.Render()
+}
+
+//#endregion interactive mode (model) implementation
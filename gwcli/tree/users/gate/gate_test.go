@@ -0,0 +1,64 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package gate
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestWrap(t *testing.T) {
+	clilog.Init(path.Join(t.TempDir(), "gwcli.TestWrap.log"), "DEBUG")
+	var ran bool
+	p := Wrap(scaffold.NewBasicAction("x", "x", "x", nil,
+		func(*cobra.Command, *pflag.FlagSet) (string, tea.Cmd) {
+			ran = true
+			return "", nil
+		}, nil))
+	defer func() { connection.MyInfo.Admin = false }()
+
+	// Cobra
+	var stderr bytes.Buffer
+	p.Action.SetErr(&stderr)
+	p.Action.SetArgs([]string{})
+	connection.MyInfo.Admin = false
+	if err := p.Action.Execute(); !errors.Is(err, ErrNotAdmin) {
+		t.Fatalf("non-admin was not rejected: %v", err)
+	} else if ran {
+		t.Fatal("action ran for a non-admin")
+	} else if stderr.Len() == 0 {
+		t.Fatal("rejection was not reported")
+	}
+	connection.MyInfo.Admin = true
+	if err := p.Action.Execute(); err != nil {
+		t.Fatal(err)
+	} else if !ran {
+		t.Fatal("action did not run for an admin")
+	}
+
+	// Mother
+	connection.MyInfo.Admin = false
+	if invalid, _, err := p.Model.SetArgs(nil, nil); err != nil || invalid != ErrNotAdmin.Error() {
+		t.Fatalf("non-admin was not rejected: %q %v", invalid, err)
+	}
+	connection.MyInfo.Admin = true
+	if invalid, _, err := p.Model.SetArgs(nil, nil); err != nil || invalid != "" {
+		t.Fatalf("admin was rejected: %q %v", invalid, err)
+	}
+}
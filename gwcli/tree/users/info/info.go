@@ -0,0 +1,98 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package info implements an action for displaying the details of a user.
+package info
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/gate"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gravwell/gravwell/v3/client/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "info"
+	short string = "display the details of a user"
+	long  string = "Displays the details of a user, given their username or UID. Admin-only."
+)
+
+var aliases []string = []string{"show"}
+
+func NewUsersInfoAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli users info jsmith --json"
+	p.Action.ValidArgsFunction = gate.Complete
+	return gate.Wrap(p)
+}
+
+func flags() pflag.FlagSet {
+	fs := pflag.FlagSet{}
+	fs.Bool(ft.Name.JSON, false, ft.Usage.JSON)
+	return fs
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 1 {
+		return "exactly one username or UID is required", nil
+	}
+	ud, err := gate.Lookup(fs.Arg(0))
+	if err != nil {
+		return err.Error(), nil
+	}
+	if js, err := fs.GetBool(ft.Name.JSON); err != nil {
+		clilog.LogFlagFailedGet(ft.Name.JSON, err)
+	} else if js {
+		b, err := json.Marshal(ud)
+		if err != nil {
+			clilog.Writer.Errorf("Failed to marshal user: %v", err)
+			return err.Error(), nil
+		}
+		return string(b), nil
+	}
+	return describe(ud), nil
+}
+
+// describe renders a user for human consumption
+func describe(ud types.UserDetails) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v (UID %v)\n", ud.User, ud.UID)
+	if ud.Name != "" {
+		fmt.Fprintf(&sb, "Name: %v\n", ud.Name)
+	}
+	if ud.Email != "" {
+		fmt.Fprintf(&sb, "Email: %v\n", ud.Email)
+	}
+	fmt.Fprintf(&sb, "Admin: %v\n", ud.Admin)
+	fmt.Fprintf(&sb, "Locked: %v\n", ud.Locked)
+	fmt.Fprintf(&sb, "SSO: %v\n", ud.SSOUser)
+	fmt.Fprintf(&sb, "MFA: %v\n", ud.MFA.MFAEnabled())
+	if ud.TS.IsZero() {
+		sb.WriteString("Last Login: never\n")
+	} else {
+		fmt.Fprintf(&sb, "Last Login: %v\n", ud.TS.Format("2006-01-02 15:04:05 MST"))
+	}
+	if len(ud.Groups) > 0 {
+		names := make([]string, len(ud.Groups))
+		for i, g := range ud.Groups {
+			names[i] = g.Name
+		}
+		fmt.Fprintf(&sb, "Groups: %v\n", strings.Join(names, ", "))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
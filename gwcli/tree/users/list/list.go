@@ -0,0 +1,74 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package list implements an action for listing the users on the system.
+package list
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/gate"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldlist"
+
+	grav "github.com/gravwell/gravwell/v3/client"
+	"github.com/spf13/pflag"
+)
+
+var (
+	short          string   = "list users"
+	long           string   = "Lists every user on the system. Admin-only."
+	defaultColumns []string = []string{"Username", "Admin", "Locked", "LastLogin"}
+	verboseColumns []string = []string{"UID", "Username", "Name", "Email", "Admin", "Locked", "SSO",
+		"LastLogin"}
+)
+
+// a single user, as displayed
+type user struct {
+	UID       int32
+	Username  string
+	Name      string
+	Email     string
+	Admin     bool
+	Locked    bool
+	SSO       bool
+	LastLogin time.Time
+}
+
+func NewUsersListAction() action.Pair {
+	p := scaffoldlist.NewListAction("", short, long, defaultColumns,
+		user{}, list, nil, scaffoldlist.WithVerboseColumns(verboseColumns))
+	p.Action.Example = "./gwcli users list --json"
+	return gate.Wrap(p)
+}
+
+func list(c *grav.Client, _ *pflag.FlagSet) ([]user, error) {
+	uds, err := c.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]user, len(uds))
+	for i, ud := range uds {
+		users[i] = user{
+			UID:       ud.UID,
+			Username:  ud.User,
+			Name:      ud.Name,
+			Email:     ud.Email,
+			Admin:     ud.Admin,
+			Locked:    ud.Locked,
+			SSO:       ud.SSOUser,
+			LastLogin: ud.TS,
+		}
+	}
+	slices.SortFunc(users, func(u1, u2 user) int {
+		return strings.Compare(u1.Username, u2.Username)
+	})
+	return users, nil
+}
@@ -0,0 +1,61 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package lock implements an action for locking a user out of their account.
+package lock
+
+import (
+	"fmt"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/gate"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldconfirm"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "lock"
+	short string = "lock a user's account"
+	long  string = "Locks a user's account, given their username or UID. Admin-only.\n" +
+		"The user is logged out of all sessions and cannot log in until unlocked.\n" +
+		"--yes skips confirmation and is required in script mode."
+)
+
+var aliases []string = []string{}
+
+func NewUsersLockAction() action.Pair {
+	p := scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, nil)
+	p.Action.Example = "./gwcli users lock jsmith --yes"
+	p.Action.ValidArgsFunction = gate.Complete
+	return gate.Wrap(p)
+}
+
+func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+	if fs.NArg() != 1 {
+		return p, "exactly one username or UID is required", nil
+	}
+	ud, err := gate.Lookup(fs.Arg(0))
+	if err != nil {
+		return p, "", err
+	} else if ud.Locked {
+		return p, fmt.Sprintf("%v is already locked", ud.User), nil
+	} else if ud.UID == connection.MyInfo.UID {
+		return p, "you cannot lock your own account", nil
+	}
+	p.Summary = fmt.Sprintf("User %v (UID %v) will be locked and logged out of all sessions.\n",
+		ud.User, ud.UID)
+	p.Commit = func() (string, error) {
+		if err := connection.Client.LockUserAccount(ud.UID); err != nil {
+			return "", fmt.Errorf("failed to lock %v: %w", ud.User, err)
+		}
+		return fmt.Sprintf("Locked %v.", ud.User), nil
+	}
+	return
+}
@@ -0,0 +1,69 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package setadmin implements an action for granting or revoking a user's admin privileges.
+package setadmin
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/gate"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold/scaffoldconfirm"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "set-admin"
+	short string = "grant or revoke a user's admin privileges"
+	long  string = "Grants (true) or revokes (false) a user's admin privileges, given their username " +
+		"or UID. Admin-only.\n" +
+		"--yes skips confirmation and is required in script mode."
+)
+
+var aliases []string = []string{"setadmin"}
+
+func NewUsersSetAdminAction() action.Pair {
+	p := scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, nil)
+	p.Action.Example = "./gwcli users set-admin jsmith true --yes"
+	p.Action.ValidArgsFunction = gate.Complete
+	return gate.Wrap(p)
+}
+
+func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+	if fs.NArg() != 2 {
+		return p, "a username or UID and true or false are required", nil
+	}
+	admin, err := strconv.ParseBool(fs.Arg(1))
+	if err != nil {
+		return p, fmt.Sprintf("%q is not true or false", fs.Arg(1)), nil
+	}
+	ud, err := gate.Lookup(fs.Arg(0))
+	if err != nil {
+		return p, "", err
+	} else if ud.Admin == admin {
+		return p, fmt.Sprintf("%v already has admin set to %v", ud.User, admin), nil
+	} else if !admin && ud.UID == connection.MyInfo.UID {
+		return p, "you cannot revoke your own admin privileges", nil
+	}
+	verb := "granted"
+	if !admin {
+		verb = "revoked"
+	}
+	p.Summary = fmt.Sprintf("User %v (UID %v) will have admin privileges %v.\n", ud.User, ud.UID, verb)
+	p.Commit = func() (string, error) {
+		if err := connection.Client.SetAdmin(ud.UID, admin); err != nil {
+			return "", fmt.Errorf("failed to set admin for %v: %w", ud.User, err)
+		}
+		return fmt.Sprintf("Admin privileges %v for %v.", verb, ud.User), nil
+	}
+	return
+}
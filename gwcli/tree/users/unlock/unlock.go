@@ -0,0 +1,61 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package unlock implements an action for restoring access to a locked account.
+package unlock
+
+import (
+	"fmt"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/gate"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/scaffold"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	use   string = "unlock"
+	short string = "unlock a user's account"
+	long  string = "Unlocks a user's account, given their username or UID, allowing them to log in. " +
+		"Admin-only."
+)
+
+var aliases []string = []string{}
+
+func NewUsersUnlockAction() action.Pair {
+	p := scaffold.NewBasicAction(use, short, long, aliases, run, flags)
+	p.Action.Example = "./gwcli users unlock jsmith"
+	p.Action.ValidArgsFunction = gate.Complete
+	return gate.Wrap(p)
+}
+
+// unlock has no flags of its own, but basic actions only parse arguments from Mother if they
+// have a flagset
+func flags() pflag.FlagSet {
+	return pflag.FlagSet{}
+}
+
+func run(_ *cobra.Command, fs *pflag.FlagSet) (string, tea.Cmd) {
+	if fs.NArg() != 1 {
+		return "exactly one username or UID is required", nil
+	}
+	ud, err := gate.Lookup(fs.Arg(0))
+	if err != nil {
+		return err.Error(), nil
+	} else if !ud.Locked {
+		return fmt.Sprintf("%v is not locked", ud.User), nil
+	}
+	if err := connection.Client.UnlockUserAccount(ud.UID); err != nil {
+		return fmt.Sprintf("failed to unlock %v: %v", ud.User, err), nil
+	}
+	return fmt.Sprintf("Unlocked %v.", ud.User), nil
+}
@@ -0,0 +1,44 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package users
+
+import (
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/create"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/info"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/list"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/lock"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/setadmin"
+	"github.com/gravwell/gravwell/v3/gwcli/tree/users/unlock"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	use   string = "users"
+	short string = "administer the users of this instance"
+	long  string = "ADMIN-ONLY. View, create, lock, and promote the users of this instance.\n" +
+		"To manage your own user, see the user nav instead."
+)
+
+var aliases []string = []string{"accounts"}
+
+func NewUsersNav() *cobra.Command {
+	return treeutils.GenerateNav(use, short, long, aliases,
+		[]*cobra.Command{},
+		[]action.Pair{
+			list.NewUsersListAction(),
+			info.NewUsersInfoAction(),
+			create.NewUsersCreateAction(),
+			lock.NewUsersLockAction(),
+			unlock.NewUsersUnlockAction(),
+			setadmin.NewUsersSetAdminAction(),
+		})
+}
@@ -16,18 +16,24 @@ import (
 
 // files within the config directory
 const (
-	tokenName   string = "token"
-	restLogName string = "rest.log"
-	stdLogName  string = "dev.log"
+	tokenName        string = "token"
+	restLogName      string = "rest.log"
+	stdLogName       string = "dev.log"
+	profilesName     string = "profiles.json"
+	queryHistoryName string = "query_history.json"
+	configName       string = "config.yaml"
 )
 
 // all persistent data is stored in $os.UserConfigDir/gwcli/
 // or local to the instantiation, if that fails
 var ( // set by init
-	cfgDir             string
-	DefaultRestLogPath string
-	DefaultStdLogPath  string
-	DefaultTokenPath   string
+	cfgDir                  string
+	DefaultRestLogPath      string
+	DefaultStdLogPath       string
+	DefaultTokenPath        string
+	DefaultProfilesPath     string
+	DefaultQueryHistoryPath string
+	DefaultConfigPath       string
 )
 
 // on startup, identify and cache the config directory
@@ -52,4 +58,7 @@ func init() {
 	DefaultRestLogPath = path.Join(cfgDir, restLogName)
 	DefaultStdLogPath = path.Join(cfgDir, stdLogName)
 	DefaultTokenPath = path.Join(cfgDir, tokenName)
+	DefaultProfilesPath = path.Join(cfgDir, profilesName)
+	DefaultQueryHistoryPath = path.Join(cfgDir, queryHistoryName)
+	DefaultConfigPath = path.Join(cfgDir, configName)
 }
@@ -0,0 +1,171 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+Flagcfg populates flag defaults from GWCLI_* environment variables and a YAML configuration file
+so frequently used flags (server, credentials, output format, ...) need not be given on every call.
+
+The file is a flat mapping of flag names to values, ex:
+
+	server: gravwell.example.com:443
+	username: admin
+	passfile: /home/admin/.gravwell_pass
+	indexer: [idx1, idx2]
+
+Precedence, from highest to lowest, is: flags given on the command line, environment variables,
+the configuration file, and the built-in defaults.
+
+gwcli only ever reads the configuration file.
+Secret values, such as the password, are applied but never displayed in help or errors.
+*/
+package flagcfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to the upper-cased flag name, with dashes replaced by underscores, to
+// form the environment variable of a flag. Ex: --ca-file is GWCLI_CA_FILE.
+const EnvPrefix = "GWCLI_"
+
+// FlagName is the name of the flag selecting the configuration file.
+// It can only be set via the command line or environment.
+const FlagName = "config"
+
+// flags whose values are applied but never displayed
+var secrets = map[string]bool{"password": true}
+
+// Config is a parsed configuration file.
+type Config struct {
+	Path   string
+	Values map[string][]string // flag name -> value(s); single values are stored as one element
+}
+
+// EnvName returns the environment variable corresponding to the given flag.
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// Load reads the configuration file at path.
+// A missing file is an empty Config unless explicit is set (the path was given by the user).
+// Keys must be names of flags in the command tree rooted at root; errors identify the offending
+// key.
+func Load(path string, explicit bool, root *cobra.Command) (c Config, err error) {
+	c = Config{Path: path, Values: map[string][]string{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return c, nil
+	} else if err != nil {
+		return c, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(b, &doc); err != nil {
+		return c, fmt.Errorf("failed to parse config file %v: %w", path, err)
+	} else if len(doc.Content) == 0 { // empty file
+		return c, nil
+	} else if doc.Content[0].Kind != yaml.MappingNode {
+		return c, fmt.Errorf("config file %v must be a mapping of flag names to values", path)
+	}
+	known := flagNames(root)
+	pairs := doc.Content[0].Content // alternating keys and values
+	for i := 0; i+1 < len(pairs); i += 2 {
+		k, n := pairs[i].Value, pairs[i+1]
+		if k == FlagName {
+			return c, fmt.Errorf("config file %v: key %q may not be set in the config file", path, k)
+		} else if !known[k] {
+			return c, fmt.Errorf("config file %v: unknown key %q (line %d)", path, k, pairs[i].Line)
+		} else if _, dup := c.Values[k]; dup {
+			return c, fmt.Errorf("config file %v: key %q (line %d) is given more than once", path, k, pairs[i].Line)
+		}
+		var vals []string
+		switch n.Kind {
+		case yaml.ScalarNode:
+			vals = []string{n.Value}
+		case yaml.SequenceNode:
+			for _, e := range n.Content {
+				if e.Kind != yaml.ScalarNode {
+					return c, fmt.Errorf("config file %v: key %q (line %d) must be a list of plain values", path, k, e.Line)
+				}
+				vals = append(vals, e.Value)
+			}
+		default:
+			return c, fmt.Errorf("config file %v: key %q (line %d) must be a value or a list of values", path, k, n.Line)
+		}
+		c.Values[k] = vals
+	}
+	return c, nil
+}
+
+// Apply sets every flag in fs that was not given on the command line from its environment
+// variable or, failing that, from the configuration file.
+// The values become the flags' defaults; Changed is not set.
+func (c Config) Apply(fs *pflag.FlagSet) (err error) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || f.Name == FlagName {
+			return
+		}
+		if v, ok := os.LookupEnv(EnvName(f.Name)); ok {
+			if serr := f.Value.Set(v); serr != nil {
+				err = fmt.Errorf("environment variable %v: %v", EnvName(f.Name), redact(f.Name, serr))
+				return
+			}
+		} else if vals, ok := c.Values[f.Name]; ok {
+			if serr := set(f, vals); serr != nil {
+				err = fmt.Errorf("config file %v: key %q: %v", c.Path, f.Name, redact(f.Name, serr))
+				return
+			}
+		} else {
+			return
+		}
+		if !secrets[f.Name] {
+			f.DefValue = f.Value.String()
+		}
+	})
+	return
+}
+
+// set replaces the value of f with vals
+func set(f *pflag.Flag, vals []string) error {
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		return sv.Replace(vals)
+	} else if len(vals) != 1 {
+		return errors.New("expects a single value, not a list")
+	}
+	return f.Value.Set(vals[0])
+}
+
+// redact hides the error detail of secret flags, as it may contain the value
+func redact(name string, err error) error {
+	if secrets[name] {
+		return errors.New("invalid value")
+	}
+	return err
+}
+
+// flagNames collects the name of every flag in the command tree
+func flagNames(root *cobra.Command) map[string]bool {
+	names := map[string]bool{}
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		add := func(f *pflag.Flag) { names[f.Name] = true }
+		c.PersistentFlags().VisitAll(add)
+		c.Flags().VisitAll(add)
+		for _, child := range c.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+	return names
+}
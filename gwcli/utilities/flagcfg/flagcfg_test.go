@@ -0,0 +1,129 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package flagcfg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// testTree returns a root with global flags and an action with local flags
+func testTree() (root, action *cobra.Command) {
+	root = &cobra.Command{Use: "gwcli"}
+	root.PersistentFlags().String("server", "localhost:80", "")
+	root.PersistentFlags().String("password", "", "")
+	root.PersistentFlags().Bool("insecure", false, "")
+	root.PersistentFlags().String(FlagName, "", "")
+	action = &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+	action.Flags().Bool("json", false, "")
+	action.Flags().StringSlice("indexer", nil, "")
+	root.AddCommand(action)
+	return
+}
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(p, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoad(t *testing.T) {
+	root, _ := testTree()
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	if c, err := Load(missing, false, root); err != nil || len(c.Values) != 0 {
+		t.Fatalf("missing default file was not empty: %v %v", c.Values, err)
+	} else if _, err = Load(missing, true, root); err == nil {
+		t.Fatal("missing explicit file was accepted")
+	}
+	if c, err := Load(writeConfig(t, ""), true, root); err != nil || len(c.Values) != 0 {
+		t.Fatalf("empty file was not empty: %v %v", c.Values, err)
+	}
+
+	// errors must name the offending key
+	bad := map[string]string{
+		"server: a\nbananas: 1\n":      `"bananas"`,
+		"server: a\nserver: b\n":       `"server"`,
+		"indexer:\n  - {a: b}\n":       `"indexer"`,
+		"json:\n  nested: true\n":      `"json"`,
+		"config: /tmp/other.yaml\n":    `"config"`,
+		"- server\n":                   "mapping",
+		"server: [unterminated\n":      "parse",
+		"insecure: true\njson: {x\n":   "parse",
+		"server: a\n\tpassword: b\n":   "parse",
+		"server: a\nnothere: [1, 2]\n": `"nothere"`,
+	}
+	for body, want := range bad {
+		if _, err := Load(writeConfig(t, body), true, root); err == nil {
+			t.Fatalf("accepted bad config %q", body)
+		} else if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error for %q does not mention %s: %v", body, want, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	root, action := testTree()
+	p := writeConfig(t, "server: file:443\npassword: hunter2\ninsecure: true\njson: true\nindexer: [idx1, idx2]\n")
+	c, err := Load(p, true, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// flags > env > file
+	t.Setenv(EnvName("insecure"), "false")
+	if err = root.ParseFlags([]string{"--server", "flag:443"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Apply(root.PersistentFlags()); err != nil {
+		t.Fatal(err)
+	} else if err = c.Apply(action.Flags()); err != nil {
+		t.Fatal(err)
+	}
+	fs := root.PersistentFlags()
+	if v, _ := fs.GetString("server"); v != "flag:443" {
+		t.Fatalf("flag did not take precedence: %v", v)
+	} else if v, _ := fs.GetBool("insecure"); v {
+		t.Fatal("environment did not take precedence over the file")
+	} else if v, _ := fs.GetString("password"); v != "hunter2" {
+		t.Fatalf("bad password %q", v)
+	} else if fs.Lookup("password").DefValue != "" {
+		t.Fatal("secret was exposed as the default value")
+	} else if fs.Changed("password") {
+		t.Fatal("file values must not mark the flag as changed")
+	}
+	if v, _ := action.Flags().GetBool("json"); !v {
+		t.Fatal("local flag was not set")
+	} else if v, _ := action.Flags().GetStringSlice("indexer"); len(v) != 2 || v[0] != "idx1" || v[1] != "idx2" {
+		t.Fatalf("bad list value %v", v)
+	}
+
+	// bad values name the key, but not secret values
+	t.Setenv(EnvName("insecure"), "bananas")
+	if err = c.Apply(root.PersistentFlags()); err == nil || !strings.Contains(err.Error(), "GWCLI_INSECURE") {
+		t.Fatalf("bad environment value was not reported: %v", err)
+	}
+	os.Unsetenv(EnvName("insecure")) // restored by t.Setenv
+	root, _ = testTree()
+	c = Config{Path: p, Values: map[string][]string{"server": {"a", "b"}}}
+	if err = c.Apply(root.PersistentFlags()); err == nil || !strings.Contains(err.Error(), `"server"`) {
+		t.Fatalf("list for a single value flag was not reported: %v", err)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if n := EnvName("insecure-skip-verify"); n != "GWCLI_INSECURE_SKIP_VERIFY" {
+		t.Fatalf("bad environment name %v", n)
+	}
+}
@@ -0,0 +1,189 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+Profiles persists named connection settings (server and login token) so users can switch between
+Gravwell instances without re-passing connection flags.
+
+All profiles are stored in a single file in the config directory, readable only by the user.
+Passwords are never stored.
+*/
+package profiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/cfgdir"
+)
+
+const filePerm = 0600
+
+var (
+	ErrNotFound = errors.New("profile not found")
+	ErrNoServer = errors.New("profile must specify a server")
+)
+
+// Path is the location of the profiles file
+var Path = cfgdir.DefaultProfilesPath
+
+// Profile contains the information required to connect to a single Gravwell instance.
+type Profile struct {
+	Server   string
+	Insecure bool   `json:",omitempty"`
+	Token    string `json:",omitempty"` // exported login token, populated on login
+}
+
+// Store is the full set of saved profiles.
+type Store struct {
+	Default  string             `json:",omitempty"` // profile used if --profile is not given
+	Profiles map[string]Profile `json:",omitempty"`
+}
+
+// Load reads the profiles file. A missing file is an empty store.
+func Load() (s Store, err error) {
+	b, err := os.ReadFile(Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Store{}, nil
+	} else if err != nil {
+		return
+	}
+	if err = json.Unmarshal(b, &s); err != nil {
+		err = fmt.Errorf("failed to parse profiles file %v: %w", Path, err)
+	}
+	return
+}
+
+// Save writes the store to the profiles file, replacing it atomically.
+func (s Store) Save() error {
+	b, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(Path), ".profiles-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // ineffectual once renamed
+	if err = f.Chmod(filePerm); err == nil {
+		if _, err = f.Write(b); err == nil {
+			err = f.Sync()
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), Path)
+}
+
+// Names returns the names of all profiles, sorted.
+func (s Store) Names() []string {
+	names := make([]string, 0, len(s.Profiles))
+	for n := range s.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the named profile.
+func (s Store) Get(name string) (Profile, error) {
+	p, ok := s.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	return p, nil
+}
+
+// Active resolves the profile to connect with: the given name if not empty, the default otherwise.
+// ok is false if no name was given and there is no default.
+func (s Store) Active(name string) (_ string, _ Profile, ok bool, err error) {
+	if name == "" {
+		if name = s.Default; name == "" {
+			return
+		}
+	}
+	p, err := s.Get(name)
+	if err != nil {
+		return
+	}
+	return name, p, true, nil
+}
+
+// Set adds or replaces the named profile.
+func (s *Store) Set(name string, p Profile) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	} else if p.Server = strings.TrimSpace(p.Server); p.Server == "" {
+		return ErrNoServer
+	}
+	if s.Profiles == nil {
+		s.Profiles = make(map[string]Profile)
+	}
+	s.Profiles[name] = p
+	return nil
+}
+
+// SetToken replaces the login token of an existing profile.
+func (s *Store) SetToken(name, token string) error {
+	p, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	p.Token = token
+	s.Profiles[name] = p
+	return nil
+}
+
+// Use sets the default profile.
+func (s *Store) Use(name string) error {
+	if _, err := s.Get(name); err != nil {
+		return err
+	}
+	s.Default = name
+	return nil
+}
+
+// Remove deletes the named profile, clearing the default if it was the default.
+func (s *Store) Remove(name string) error {
+	if _, err := s.Get(name); err != nil {
+		return err
+	}
+	delete(s.Profiles, name)
+	if s.Default == name {
+		s.Default = ""
+	}
+	return nil
+}
+
+// ValidateName returns an error if the name is not usable as a profile name.
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("profile name cannot be empty")
+	} else if strings.ContainsFunc(name, func(r rune) bool { return r <= ' ' }) {
+		return fmt.Errorf("profile name %q cannot contain whitespace or control characters", name)
+	}
+	return nil
+}
+
+// NameFromArgs returns the profile name given as the single bare argument of an action.
+func NameFromArgs(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("exactly one profile name must be given")
+	}
+	name := strings.TrimSpace(args[0])
+	return name, ValidateName(name)
+}
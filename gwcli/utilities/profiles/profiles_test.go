@@ -0,0 +1,91 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package profiles
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	Path = filepath.Join(t.TempDir(), "profiles.json")
+
+	// a missing file is an empty store
+	s, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(s.Profiles) != 0 {
+		t.Fatalf("unexpected profiles: %v", s.Profiles)
+	}
+	if _, _, ok, err := s.Active(""); ok || err != nil {
+		t.Fatalf("empty store resolved a profile (ok %v, err %v)", ok, err)
+	}
+
+	if err = s.Set("staging", Profile{Server: " stage:443 "}); err != nil {
+		t.Fatal(err)
+	} else if err = s.Set("prod", Profile{Server: "prod:443", Insecure: true}); err != nil {
+		t.Fatal(err)
+	} else if err = s.Set("bad name", Profile{Server: "x:80"}); err == nil {
+		t.Fatal("name with whitespace was accepted")
+	} else if err = s.Set("noserver", Profile{}); !errors.Is(err, ErrNoServer) {
+		t.Fatalf("profile without server was accepted: %v", err)
+	}
+	if err = s.SetToken("staging", "tkn"); err != nil {
+		t.Fatal(err)
+	} else if err = s.Use("prod"); err != nil {
+		t.Fatal(err)
+	} else if err = s.Use("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing profile was made default: %v", err)
+	}
+	if err = s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if fi, err := os.Stat(Path); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && fi.Mode().Perm() != filePerm {
+		t.Fatalf("bad permissions: %v", fi.Mode().Perm())
+	}
+
+	if s, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if name, p, ok, err := s.Active(""); err != nil || !ok || name != "prod" || p.Server != "prod:443" || !p.Insecure {
+		t.Fatalf("bad default profile: %v %+v %v %v", name, p, ok, err)
+	}
+	if name, p, ok, err := s.Active("staging"); err != nil || !ok || name != "staging" || p.Server != "stage:443" || p.Token != "tkn" {
+		t.Fatalf("bad named profile: %v %+v %v %v", name, p, ok, err)
+	}
+	if _, _, _, err := s.Active("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing profile resolved: %v", err)
+	}
+
+	// removing the default clears it
+	if err = s.Remove("prod"); err != nil {
+		t.Fatal(err)
+	} else if s.Default != "" {
+		t.Fatalf("default was not cleared: %v", s.Default)
+	} else if names := s.Names(); len(names) != 1 || names[0] != "staging" {
+		t.Fatalf("bad names: %v", names)
+	}
+}
+
+func TestNameFromArgs(t *testing.T) {
+	if n, err := NameFromArgs([]string{" prod "}); err != nil || n != "prod" {
+		t.Fatalf("bad name: %q %v", n, err)
+	}
+	for _, args := range [][]string{nil, {"a", "b"}, {" "}, {"a\tb"}} {
+		if _, err := NameFromArgs(args); err == nil {
+			t.Fatalf("%q was accepted", args)
+		}
+	}
+}
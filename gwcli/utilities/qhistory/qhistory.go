@@ -0,0 +1,123 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package qhistory persists the queries submitted from the interactive query editor so that they
+// can be recalled in later sessions.
+// Only the query text and the time it was submitted are stored; flags and credentials never are.
+package qhistory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/cfgdir"
+)
+
+const (
+	filePerm = 0600
+
+	DefaultSize uint = 500
+)
+
+// Path is the location of the query history file
+var Path = cfgdir.DefaultQueryHistoryPath
+
+// Size is the maximum number of queries retained, the oldest are dropped first.
+// A size of 0 disables the history.
+var Size = DefaultSize
+
+// Record is a single historical query.
+type Record struct {
+	Query     string
+	Timestamp time.Time
+}
+
+// Load reads the history file, ordered from oldest to newest. A missing file is an empty history.
+func Load() (rs []Record, err error) {
+	if Size == 0 {
+		return nil, nil
+	}
+	b, err := os.ReadFile(Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return
+	}
+	if err = json.Unmarshal(b, &rs); err != nil {
+		return nil, fmt.Errorf("failed to parse query history file %v: %w", Path, err)
+	}
+	return clip(rs), nil
+}
+
+// Add appends a query to the history, dropping the oldest records beyond Size.
+// Resubmitting the newest query only refreshes its timestamp.
+func Add(qry string) error {
+	if qry = strings.TrimSpace(qry); qry == "" || Size == 0 {
+		return nil
+	}
+	rs, err := Load()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if l := len(rs); l > 0 && rs[l-1].Query == qry {
+		rs[l-1].Timestamp = now
+	} else {
+		rs = append(rs, Record{Query: qry, Timestamp: now})
+	}
+	return save(clip(rs))
+}
+
+// Get returns the record at the given index, as displayed by the history builtin (1 is the oldest).
+func Get(idx int) (Record, error) {
+	rs, err := Load()
+	if err != nil {
+		return Record{}, err
+	} else if idx < 1 || idx > len(rs) {
+		return Record{}, fmt.Errorf("no query at index %d (history holds %d)", idx, len(rs))
+	}
+	return rs[idx-1], nil
+}
+
+// clip drops the oldest records beyond Size
+func clip(rs []Record) []Record {
+	if uint(len(rs)) > Size {
+		rs = rs[uint(len(rs))-Size:]
+	}
+	return rs
+}
+
+// save writes the history file, replacing it atomically.
+func save(rs []Record) error {
+	b, err := json.Marshal(rs)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(Path), ".query_history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // ineffectual once renamed
+	if err = f.Chmod(filePerm); err == nil {
+		if _, err = f.Write(b); err == nil {
+			err = f.Sync()
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), Path)
+}
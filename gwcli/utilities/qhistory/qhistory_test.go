@@ -0,0 +1,69 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package qhistory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	Path = filepath.Join(t.TempDir(), "query_history.json")
+	Size = 3
+	defer func() { Size = DefaultSize }()
+
+	if rs, err := Load(); err != nil || len(rs) != 0 {
+		t.Fatalf("missing file is not an empty history: %v %v", rs, err)
+	}
+	for _, q := range []string{"tag=a", " ", "tag=b", "tag=c", "tag=c", "tag=d"} {
+		if err := Add(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rs, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"tag=b", "tag=c", "tag=d"}
+	if len(rs) != len(exp) {
+		t.Fatalf("bad history: %+v", rs)
+	}
+	for i := range exp {
+		if rs[i].Query != exp[i] || rs[i].Timestamp.IsZero() {
+			t.Fatalf("bad record %d: %+v", i, rs[i])
+		}
+	}
+	if fi, err := os.Stat(Path); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != filePerm {
+		t.Fatalf("bad file permissions %v", fi.Mode().Perm())
+	}
+
+	if r, err := Get(1); err != nil || r.Query != "tag=b" {
+		t.Fatalf("bad record at 1: %+v %v", r, err)
+	}
+	for _, idx := range []int{0, 4} {
+		if _, err := Get(idx); err == nil {
+			t.Fatalf("failed to catch bad index %d", idx)
+		}
+	}
+
+	// shrinking the history only shows the newest records, disabling it hides everything
+	Size = 1
+	if rs, err = Load(); err != nil || len(rs) != 1 || rs[0].Query != "tag=d" {
+		t.Fatalf("bad clipped history: %+v %v", rs, err)
+	}
+	Size = 0
+	if err = Add("tag=e"); err != nil {
+		t.Fatal(err)
+	} else if rs, err = Load(); err != nil || len(rs) != 0 {
+		t.Fatalf("disabled history returned records: %+v %v", rs, err)
+	}
+}
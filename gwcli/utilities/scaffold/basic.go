@@ -31,7 +31,6 @@ Implementations will probably look a lot like:
 package scaffold
 
 import (
-	"fmt"
 	"github.com/gravwell/gravwell/v3/gwcli/action"
 	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
 
@@ -55,7 +54,7 @@ func NewBasicAction(use, short, long string, aliases []string,
 		aliases,
 		func(c *cobra.Command, _ []string) {
 			s, _ := act(c, c.Flags())
-			fmt.Fprintf(c.OutOrStdout(), "%v\n", s)
+			treeutils.Print(c, s)
 		})
 
 	if flagFunc != nil {
@@ -94,6 +93,13 @@ func (ba *BasicAction) Update(msg tea.Msg) tea.Cmd {
 	return tea.Sequence(tea.Println(s), cmd)
 }
 
+// Render performs the action and returns its result without printing it.
+// The tea.Cmd returned by the action func is discarded.
+func (ba *BasicAction) Render() (string, error) {
+	s, _ := ba.fn(ba.cmd, &ba.fs)
+	return s, nil
+}
+
 func (*BasicAction) View() string {
 	return ""
 }
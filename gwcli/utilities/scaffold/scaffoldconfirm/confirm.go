@@ -0,0 +1,253 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+A confirm action plans an operation from its flags and arguments, describes the plan to the user,
+and only carries it out once the user confirms by typing 'yes'.
+
+Confirm actions have the --yes default flag, which skips confirmation. --yes is required in script
+mode, as there is no one to confirm.
+
+Implementations will probably look a lot like:
+
+	func New[parentpkg][pkg]Action() action.Pair {
+		return scaffoldconfirm.NewConfirmAction(use, short, long, aliases, plan, flags)
+	}
+
+	func plan(fs *pflag.FlagSet) (p scaffoldconfirm.Plan, invalid string, err error) {
+		if fs.NArg() != 1 {
+			return p, "exactly one [X] is required", nil
+		}
+		p.Summary = "[X] will be ..."
+		p.Commit = func() (string, error) {
+			return "[X] done", connection.Client.[Y](fs.Arg(0))
+		}
+		return
+	}
+*/
+package scaffoldconfirm
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+	"github.com/gravwell/gravwell/v3/gwcli/utilities/treeutils"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagYes = "yes"
+
+	confirmPhrase = "yes"
+	confirmTitle  = "Type '" + confirmPhrase + "' to confirm: "
+	abortedText   = "Abstained."
+)
+
+// Plan is an operation awaiting confirmation.
+type Plan struct {
+	// Summary describes what Commit will do; it is displayed above the confirmation prompt.
+	Summary string
+	// Commit performs the operation, returning the text to display on success.
+	Commit func() (string, error)
+	// Abort, if set, undoes any work done while planning, returning the text to display.
+	Abort func() string
+}
+
+// A function that builds a plan from the parsed flags and arguments.
+// Invalid is set if the arguments are unusable; err is set if planning failed.
+type planFunc func(fs *pflag.FlagSet) (p Plan, invalid string, err error)
+
+// NewConfirmAction creates and returns a cobra.Command suitable for use as a confirm action.
+// Base flags:
+//
+//	--yes (skip confirmation)
+//
+// Plan is called each time the action is invoked. Its Commit is only called once the user confirms
+// or if --yes was given; otherwise its Abort is called.
+//
+// flagFunc provides any additional flags and may be nil.
+func NewConfirmAction(use, short, long string, aliases []string,
+	plan planFunc, flagFunc func() pflag.FlagSet) action.Pair {
+	fsFunc := func() pflag.FlagSet {
+		fs := pflag.FlagSet{}
+		if flagFunc != nil {
+			fs = flagFunc()
+		}
+		fs.BoolP(FlagYes, "y", false, "skip confirmation.\nRequired in script mode.")
+		return fs
+	}
+
+	cmd := treeutils.NewActionCommand(use, short, long, aliases,
+		func(c *cobra.Command, _ []string) {
+			yes, err := c.Flags().GetBool(FlagYes)
+			if err != nil {
+				clilog.LogFlagFailedGet(FlagYes, err)
+				return
+			}
+			if !yes {
+				if script, err := c.Flags().GetBool(ft.Name.Script); err != nil {
+					clilog.LogFlagFailedGet(ft.Name.Script, err)
+				} else if script {
+					fmt.Fprintln(c.ErrOrStderr(), "--"+FlagYes+" is required in script mode")
+					return
+				}
+			}
+			p, invalid, err := plan(c.Flags())
+			if err != nil {
+				clilog.Tee(clilog.ERROR, c.ErrOrStderr(), err.Error()+"\n")
+				return
+			} else if invalid != "" {
+				fmt.Fprintln(c.ErrOrStderr(), invalid)
+				return
+			}
+			if !yes {
+				fmt.Fprint(c.OutOrStdout(), p.Summary+confirmTitle)
+				resp, _ := bufio.NewReader(c.InOrStdin()).ReadString('\n')
+				if !confirmed(resp) {
+					fmt.Fprintln(c.OutOrStdout(), p.abort())
+					return
+				}
+			}
+			s, err := p.Commit()
+			if err != nil {
+				clilog.Tee(clilog.ERROR, c.ErrOrStderr(), err.Error()+"\n")
+				return
+			}
+			fmt.Fprintln(c.OutOrStdout(), s)
+		})
+	fs := fsFunc()
+	cmd.Flags().AddFlagSet(&fs)
+
+	return treeutils.GenerateAction(cmd, newConfirmModel(plan, fsFunc))
+}
+
+func confirmed(resp string) bool {
+	return strings.TrimSpace(strings.ToLower(resp)) == confirmPhrase
+}
+
+// abort calls the Abort function, if there is one
+func (p Plan) abort() string {
+	if p.Abort == nil {
+		return abortedText
+	}
+	return p.Abort()
+}
+
+//#region interactive mode (model) implementation
+
+type confirmModel struct {
+	width  int
+	done   bool
+	plan   planFunc
+	fsFunc func() pflag.FlagSet
+	fs     pflag.FlagSet
+	p      Plan
+	confTI textinput.Model
+}
+
+var _ action.Model = &confirmModel{}
+
+func newConfirmModel(plan planFunc, fsFunc func() pflag.FlagSet) *confirmModel {
+	m := &confirmModel{
+		plan:   plan,
+		fsFunc: fsFunc,
+		fs:     fsFunc(),
+		confTI: stylesheet.NewTI("", false),
+	}
+	m.confTI.Focus()
+	return m
+}
+
+func (m *confirmModel) Update(msg tea.Msg) tea.Cmd {
+	if m.done {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyEnter {
+			m.done = true
+			if !confirmed(m.confTI.Value()) {
+				return tea.Println(m.p.abort())
+			}
+			return m.commit()
+		}
+	}
+	var cmd tea.Cmd
+	m.confTI, cmd = m.confTI.Update(msg)
+	return cmd
+}
+
+func (m *confirmModel) commit() tea.Cmd {
+	s, err := m.p.Commit()
+	if err != nil {
+		clilog.Writer.Error(err.Error())
+		return tea.Println(err.Error())
+	}
+	return tea.Println(s)
+}
+
+func (m *confirmModel) View() string {
+	if m.done {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(m.p.Summary)
+	sb.WriteString(confirmTitle)
+	tiView := m.confTI.View()
+	// if the line would be too long, bump the ti to a newline
+	if lipgloss.Width(confirmTitle)+lipgloss.Width(tiView) > m.width+1 { // 1 cell pad
+		sb.WriteString("\n")
+	}
+	sb.WriteString(tiView)
+	return sb.String()
+}
+
+func (m *confirmModel) Done() bool {
+	return m.done
+}
+
+func (m *confirmModel) Reset() error {
+	m.done = false
+	m.fs = m.fsFunc()
+	m.p = Plan{}
+	m.confTI.Reset()
+	return nil
+}
+
+func (m *confirmModel) SetArgs(_ *pflag.FlagSet, tokens []string) (invalid string, onStart tea.Cmd, err error) {
+	if err := m.fs.Parse(tokens); err != nil {
+		return err.Error(), nil, nil
+	}
+	yes, err := m.fs.GetBool(FlagYes)
+	if err != nil {
+		return "", nil, err
+	}
+	if m.p, invalid, err = m.plan(&m.fs); err != nil || invalid != "" {
+		return invalid, nil, err
+	}
+	if yes {
+		m.done = true
+		return "", m.commit(), nil
+	}
+	return "", textinput.Blink, nil
+}
+
+//#endregion interactive mode (model) implementation
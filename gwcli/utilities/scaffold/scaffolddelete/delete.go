@@ -91,7 +91,7 @@ const (
 //
 //	--dryrun (SELECT, as a mock deletion),
 //
-//	--id (immediately attempt deletion on the given id)
+//	--id (immediately attempt deletion on the given id, which may also be given as a bare argument)
 //
 // You must provide two functions to instantiate a generic delete:
 //
@@ -170,7 +170,10 @@ func flags() pflag.FlagSet {
 func fetchFlagValues[I scaffold.Id_t](fs *pflag.FlagSet) (id I, dryrun bool, _ error) {
 	if strid, err := fs.GetString(ft.Name.ID); err != nil {
 		return id, false, err
-	} else if strid != "" {
+	} else if strid != "" || fs.NArg() > 0 {
+		if strid == "" { // fall back to a bare ID argument
+			strid = fs.Arg(0)
+		}
 		id, err = scaffold.FromString[I](strid)
 		if err != nil {
 			return id, dryrun, err
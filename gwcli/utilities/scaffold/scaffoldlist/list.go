@@ -12,7 +12,7 @@ The results are sent to weave and packaged in a way that can be listed for the u
 
 This provides a consistent interface for actions that list arbitrary data.
 
-List actions have the --output, --append, --json, --table, --CSV, and --show-columns default flags.
+List actions have the --output, --append, --json, --yaml, --table, --CSV, and --show-columns default flags.
 
 Example implementation:
 
@@ -84,6 +84,7 @@ const (
 	json outputFormat = iota
 	csv
 	table
+	yaml
 	unknown
 )
 
@@ -95,6 +96,8 @@ func (f outputFormat) String() string {
 		return "CSV"
 	case table:
 		return "table"
+	case yaml:
+		return "YAML"
 	}
 	return fmt.Sprintf("unknown format (%d)", f)
 }
@@ -107,11 +110,26 @@ const outFilePerm = 0644
 type dataFunction[Any any] func(*grav.Client, *pflag.FlagSet) ([]Any, error)
 type addtlFlagFunction func() pflag.FlagSet
 
+// Option modifies the optional behaviour of a list action.
+type Option func(*options)
+
+type options struct {
+	verboseColumns []string
+}
+
+// WithVerboseColumns adds a --verbose flag that displays the given columns in place of the default
+// columns. --columns still takes precedence.
+func WithVerboseColumns(columns []string) Option {
+	return func(o *options) {
+		o.verboseColumns = columns
+	}
+}
+
 // NewListAction creates and returns a cobra.Command suitable for use as a list
 // action, complete with common flags and a generic run function operating off
 // the given dataFunction.
 //
-// Flags: {--csv|--json|--table} [--columns ...]
+// Flags: {--csv|--json|--yaml|--table} [--columns ...]
 //
 // If no output module is given, defaults to --table.
 //
@@ -128,12 +146,21 @@ type addtlFlagFunction func() pflag.FlagSet
 //
 // Go's Generics are a godsend.
 func NewListAction[Any any](use, short, long string, defaultColumns []string,
-	dataStruct Any, dataFn dataFunction[Any], addtlFlagsFunc addtlFlagFunction) action.Pair {
+	dataStruct Any, dataFn dataFunction[Any], addtlFlagsFunc addtlFlagFunction,
+	opts ...Option) action.Pair {
 	// assert developer provided a usable data struct
 	if reflect.TypeOf(dataStruct).Kind() != reflect.Struct {
 		panic("dataStruct must be a struct") // developer error
 	}
 
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.verboseColumns != nil {
+		addtlFlagsFunc = withVerboseFlag(addtlFlagsFunc)
+	}
+
 	// the function to run if called from the shell/non-interactively
 	runFunc := func(cmd *cobra.Command, _ []string) {
 		// check for --show-columns
@@ -153,33 +180,18 @@ func NewListAction[Any any](use, short, long string, defaultColumns []string,
 		}
 
 		var (
-			script  bool
-			noColor bool
 			columns []string
 			err     error
 		)
 
-		// check for script mode
-		script, err = cmd.Flags().GetBool(ft.Name.Script)
-		if err != nil {
-			clilog.LogFlagFailedGet(ft.Name.Script, err)
-		}
-
 		// fetch columns
 		if columns, err = cmd.Flags().GetStringSlice("columns"); err != nil {
 			clilog.LogFlagFailedGet("columns", err)
 			// will fall back to default columns
 		} else if len(columns) == 0 {
 			columns = defaultColumns
-		}
-
-		// check for --no-color
-		if script { // script mode implies noColor
-			noColor = true
-		} else {
-			noColor, err = cmd.Flags().GetBool("no-color")
-			if err != nil {
-				clilog.LogFlagFailedGet("no-color", err)
+			if verbose(cmd.Flags(), o.verboseColumns) {
+				columns = o.verboseColumns
 			}
 		}
 
@@ -192,7 +204,9 @@ func NewListAction[Any any](use, short, long string, defaultColumns []string,
 			defer outFile.Close()
 		}
 
-		s, err := listOutput(cmd.Flags(), columns, !noColor, dataFn)
+		// --no-color, --script, NO_COLOR, and redirected output all disable color
+		color := outFile == nil && treeutils.ColorEnabled(cmd.Flags(), cmd.OutOrStdout())
+		s, err := listOutput(cmd.Flags(), columns, color, dataFn)
 		if err != nil {
 			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error())
 			return
@@ -209,7 +223,7 @@ func NewListAction[Any any](use, short, long string, defaultColumns []string,
 		if outFile != nil {
 			fmt.Fprintln(outFile, s)
 		} else {
-			fmt.Fprintln(cmd.OutOrStdout(), s)
+			treeutils.Print(cmd, s)
 		}
 
 	}
@@ -231,10 +245,11 @@ func NewListAction[Any any](use, short, long string, defaultColumns []string,
 	}
 
 	cmd.Flags().SortFlags = false // does not seem to be respected
-	cmd.MarkFlagsMutuallyExclusive(ft.Name.CSV, ft.Name.JSON, ft.Name.Table)
+	cmd.MarkFlagsMutuallyExclusive(ft.Name.CSV, ft.Name.JSON, ft.Name.YAML, ft.Name.Table)
 
 	// spin up a list action for interactive use
 	la := newListAction(defaultColumns, dataStruct, dataFn, addtlFlagsFunc)
+	la.verboseColumns = o.verboseColumns
 
 	return treeutils.GenerateAction(cmd, &la)
 }
@@ -244,6 +259,7 @@ func listStarterFlags() pflag.FlagSet {
 	fs := pflag.FlagSet{}
 	fs.Bool(ft.Name.CSV, false, ft.Usage.CSV)
 	fs.Bool(ft.Name.JSON, false, ft.Usage.JSON)
+	fs.Bool(ft.Name.YAML, false, ft.Usage.YAML)
 	fs.Bool(ft.Name.Table, true, ft.Usage.Table) // default
 	fs.StringSlice("columns", []string{},
 		"comma-seperated list of columns to include in the results."+
@@ -254,6 +270,31 @@ func listStarterFlags() pflag.FlagSet {
 	return fs
 }
 
+// wraps the given additional flags function to also add --verbose
+func withVerboseFlag(addtlFlagsFunc addtlFlagFunction) addtlFlagFunction {
+	return func() pflag.FlagSet {
+		var fs pflag.FlagSet
+		if addtlFlagsFunc != nil {
+			fs = addtlFlagsFunc()
+		}
+		fs.Bool("verbose", false, "display additional columns.")
+		return fs
+	}
+}
+
+// Returns whether the verbose columns should be displayed, per the --verbose flag
+func verbose(fs *pflag.FlagSet, verboseColumns []string) bool {
+	if verboseColumns == nil {
+		return false
+	}
+	v, err := fs.GetBool("verbose")
+	if err != nil {
+		clilog.LogFlagFailedGet("verbose", err)
+		return false
+	}
+	return v
+}
+
 // Opens a file, per the given --output and --append flags in the flagset, and returns its handle.
 // Returns nil if the flags do not call for a file.
 func initOutFile(fs *pflag.FlagSet) (*os.File, error) {
@@ -289,6 +330,10 @@ func determineFormat(fs *pflag.FlagSet) outputFormat {
 			clilog.LogFlagFailedGet(ft.Name.JSON, err)
 		} else if format_json {
 			format = json
+		} else if format_yaml, err := fs.GetBool(ft.Name.YAML); err != nil {
+			clilog.LogFlagFailedGet(ft.Name.YAML, err)
+		} else if format_yaml {
+			format = yaml
 		} else {
 			format = table
 		}
@@ -317,6 +362,8 @@ func listOutput[Any any](fs *pflag.FlagSet, columns []string, color bool,
 		toRet = weave.ToCSV(data, columns)
 	case json:
 		toRet, err = weave.ToJSON(data, columns)
+	case yaml:
+		toRet, err = weave.ToYAML(data, columns)
 	case table:
 		if color {
 			toRet = weave.ToTable(data, columns, stylesheet.Table)
@@ -344,6 +391,7 @@ type ListAction[Any any] struct {
 	// data shielded from .Reset()
 	DefaultFormat  outputFormat
 	DefaultColumns []string          // columns to output if unspecified
+	verboseColumns []string          // columns to output if unspecified and --verbose is given
 	afsFunc        addtlFlagFunction // the additional flagset to add to the starter when restoring
 	color          bool              // inferred from the global "--no-color" flag
 
@@ -417,6 +465,12 @@ func (la *ListAction[T]) Update(msg tea.Msg) tea.Cmd {
 	return tea.Println(s)
 }
 
+// Render fetches and formats the list data per the current flagset without printing it.
+// Does not write to the output file, if one was given.
+func (la *ListAction[T]) Render() (string, error) {
+	return listOutput(&la.fs, la.columns, la.color, la.dataFunc)
+}
+
 func (la *ListAction[T]) View() string {
 	return ""
 }
@@ -469,15 +523,12 @@ func (la *ListAction[T]) SetArgs(
 			return "", nil, err
 		} else if len(cols) > 0 {
 			la.columns = cols
+		} else if verbose(&fs, la.verboseColumns) {
+			la.columns = la.verboseColumns
 		} // else: defaults to DefaultColumns
 	}
 
-	nc, err := inherited.GetBool("no-color")
-	if err != nil {
-		la.color = false
-		clilog.Writer.Warnf("Failed to fetch no-color from inherited: %v", err)
-	}
-	la.color = !nc
+	la.color = treeutils.ColorEnabled(inherited, os.Stdout)
 
 	if f, err := initOutFile(&fs); err != nil {
 		return "", nil, err
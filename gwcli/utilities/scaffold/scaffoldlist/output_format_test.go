@@ -21,6 +21,7 @@ func Test_format_String(t *testing.T) {
 		{"JSON", json, "JSON"},
 		{"CSV", csv, "CSV"},
 		{"table", table, "table"},
+		{"YAML", yaml, "YAML"},
 		{"unknown", 5, "unknown format (5)"},
 	}
 	for _, tt := range tests {
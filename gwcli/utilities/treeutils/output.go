@@ -0,0 +1,133 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package treeutils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultPager = "less"
+	// less flags used if the user has not set $LESS: pass colors through, quit if the output fits
+	// on one screen, and leave the output on screen after quitting
+	defaultLess = "FRX"
+)
+
+// terminal returns the file descriptor of w if it is a terminal.
+func terminal(w io.Writer) (fd uintptr, ok bool) {
+	f, isFile := w.(*os.File)
+	if !isFile || f == nil {
+		return 0, false
+	}
+	return f.Fd(), term.IsTerminal(f.Fd())
+}
+
+// flagSet returns whether the given bool flag exists and is set.
+// Missing flags (ex: persistent flags that were not merged in interactive mode) are unset.
+func flagSet(fs *pflag.FlagSet, name string) bool {
+	if fs == nil || fs.Lookup(name) == nil {
+		return false
+	}
+	v, err := fs.GetBool(name)
+	if err != nil {
+		clilog.LogFlagFailedGet(name, err)
+		return false
+	}
+	return v
+}
+
+// ColorEnabled returns whether output written to out may be colorized.
+// Color is disabled by --no-color, by --script, by a non-empty NO_COLOR environment variable, or if
+// out is not a terminal (ex: it has been redirected to a file or pipe).
+func ColorEnabled(fs *pflag.FlagSet, out io.Writer) bool {
+	if flagSet(fs, "no-color") || flagSet(fs, ft.Name.Script) || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	_, ok := terminal(out)
+	return ok
+}
+
+// Print writes s, followed by a newline, to the command's output.
+// If the output is a terminal and s is taller than it, s is paged through $PAGER (or less).
+// Paging is disabled by --no-pager, by --script, or by --watch. If the pager cannot be started, s
+// is printed as normal.
+func Print(cmd *cobra.Command, s string) {
+	out := cmd.OutOrStdout()
+	if pageable(cmd.Flags(), out, s) {
+		if err := page(cmd, out, s); err == nil {
+			return
+		} else {
+			clilog.Writer.Warnf("failed to page output: %v", err)
+		}
+	}
+	fmt.Fprintln(out, s)
+}
+
+// pageable returns whether s should be paged rather than printed to out.
+func pageable(fs *pflag.FlagSet, out io.Writer, s string) bool {
+	if flagSet(fs, "no-pager") || flagSet(fs, ft.Name.Script) {
+		return false
+	}
+	if f := fs.Lookup(ft.Name.Watch); f != nil && f.Changed {
+		return false
+	}
+	fd, ok := terminal(out)
+	if !ok {
+		return false
+	}
+	_, height, err := term.GetSize(fd)
+	if err != nil {
+		return false
+	}
+	return exceedsHeight(s, height)
+}
+
+// exceedsHeight returns whether s, plus the shell prompt following it, is taller than height lines.
+func exceedsHeight(s string, height int) bool {
+	return height > 0 && strings.Count(s, "\n")+2 > height
+}
+
+// pagerCommand returns the pager to use and its arguments, per $PAGER.
+func pagerCommand() []string {
+	if p := strings.Fields(os.Getenv("PAGER")); len(p) > 0 {
+		return p
+	}
+	return []string{defaultPager}
+}
+
+// page runs the pager over s, attached to out.
+// Only a failure to start the pager is returned; once it has started, the output is the pager's.
+func page(cmd *cobra.Command, out io.Writer, s string) error {
+	args := pagerCommand()
+	pgr := exec.Command(args[0], args[1:]...)
+	pgr.Stdin = strings.NewReader(s + "\n")
+	pgr.Stdout = out
+	pgr.Stderr = cmd.ErrOrStderr()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		pgr.Env = append(os.Environ(), "LESS="+defaultLess)
+	}
+	if err := pgr.Start(); err != nil {
+		return err
+	}
+	if err := pgr.Wait(); err != nil {
+		clilog.Writer.Infof("pager %v exited: %v", args[0], err)
+	}
+	return nil
+}
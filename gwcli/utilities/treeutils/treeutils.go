@@ -13,6 +13,7 @@ package treeutils
 import (
 	"github.com/gravwell/gravwell/v3/gwcli/action"
 	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
 	"github.com/gravwell/gravwell/v3/gwcli/group"
 	"github.com/gravwell/gravwell/v3/gwcli/mother"
 	"strings"
@@ -20,6 +21,63 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// NoLoginAnnotation marks actions that can run without logging into the Gravwell instance.
+const NoLoginAnnotation = "gwcli/nologin"
+
+// SkipLogin annotates the given action such that it can be run without logging in.
+// Only applies to non-interactive invocations; Mother always logs in before she starts.
+func SkipLogin(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[NoLoginAnnotation] = "true"
+}
+
+// TokenLoginAnnotation marks actions that use the saved login token if it is valid, but that can
+// run without logging in and never prompt for credentials.
+const TokenLoginAnnotation = "gwcli/tokenlogin"
+
+// TokenLoginOnly annotates the given action such that it only logs in via the saved login token.
+// Only applies to non-interactive invocations; Mother always logs in before she starts.
+func TokenLoginOnly(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[TokenLoginAnnotation] = "true"
+}
+
+// CompletionFunc is the signature of Cobra's dynamic completion functions
+// (ValidArgsFunction and RegisterFlagCompletionFunc).
+type CompletionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// CompleteFrom returns a completion function that offers the values returned by fetch.
+// Completion never prompts for credentials, so fetch is only called if a session was established
+// from a saved login token; otherwise no values are offered.
+func CompleteFrom(fetch func() ([]string, error)) CompletionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if connection.Client == nil || !connection.Client.LoggedIn() {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		vals, err := fetch()
+		if err != nil {
+			clilog.Writer.Warnf("failed to fetch completions: %v", err)
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return filterPrefix(vals, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// filterPrefix returns the values that begin with the given prefix
+func filterPrefix(vals []string, prefix string) []string {
+	res := make([]string, 0, len(vals))
+	for _, v := range vals {
+		if strings.HasPrefix(v, prefix) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 // Creates and returns a Nav (tree node) that can now be assigned subcommands
 func GenerateNav(use, short, long string, aliases []string,
 	navCmds []*cobra.Command, actionCmds []action.Pair) *cobra.Command {
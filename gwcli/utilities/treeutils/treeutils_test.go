@@ -0,0 +1,74 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package treeutils
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/gwcli/connection"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestFilterPrefix(t *testing.T) {
+	vals := []string{"syslog", "sysmon", "netflow", "zeek"}
+	if got := filterPrefix(vals, "sys"); !slices.Equal(got, []string{"syslog", "sysmon"}) {
+		t.Errorf("unexpected values for prefix 'sys': %v", got)
+	}
+	if got := filterPrefix(vals, ""); !slices.Equal(got, vals) {
+		t.Errorf("an empty prefix should match every value: %v", got)
+	}
+	if got := filterPrefix(vals, "x"); len(got) != 0 {
+		t.Errorf("expected no values, got %v", got)
+	}
+}
+
+func TestCompleteFromNotLoggedIn(t *testing.T) {
+	connection.Client = nil
+	var called bool
+	f := CompleteFrom(func() ([]string, error) {
+		called = true
+		return []string{"a"}, nil
+	})
+	vals, directive := f(&cobra.Command{}, nil, "")
+	if called {
+		t.Error("values were fetched without a session")
+	}
+	if len(vals) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("expected no completions, got %v (directive %v)", vals, directive)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Bool("no-color", false, "")
+	var buf bytes.Buffer
+	if ColorEnabled(fs, &buf) {
+		t.Error("color should be disabled when output is not a terminal")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(fs, os.Stdout) {
+		t.Error("color should be disabled by NO_COLOR")
+	}
+}
+
+func TestExceedsHeight(t *testing.T) {
+	if exceedsHeight("a\nb", 5) {
+		t.Error("two lines should fit in a five line terminal")
+	}
+	if !exceedsHeight("a\nb\nc\nd\ne", 5) {
+		t.Error("five lines, plus the prompt, should not fit in a five line terminal")
+	}
+	if exceedsHeight("a\nb\nc\nd\ne", 0) {
+		t.Error("an unknown height should never page")
+	}
+}
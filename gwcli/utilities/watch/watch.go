@@ -0,0 +1,241 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+/*
+Package watch bolts a --watch flag onto an existing action, causing it to re-query and redraw its
+results on an interval until interrupted, similar to the Unix `watch` command.
+
+In a Cobra context, the screen is cleared and the action's Run func is re-invoked each interval.
+
+In a Mother context, the action's model must implement Renderer so its output can be drawn in
+place by View, rather than printed above the prompt.
+Actions built from scaffold.NewBasicAction and scaffoldlist.NewListAction implement Renderer.
+*/
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/gwcli/action"
+	"github.com/gravwell/gravwell/v3/gwcli/clilog"
+	"github.com/gravwell/gravwell/v3/gwcli/stylesheet"
+	ft "github.com/gravwell/gravwell/v3/gwcli/stylesheet/flagtext"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// MinInterval is the shortest refresh interval --watch will accept.
+const MinInterval = time.Second
+
+// ANSI sequence to home the cursor and clear the screen
+const clearScreen = "\033[H\033[2J"
+
+// Renderer is implemented by action models that can produce their output on demand, without
+// printing it.
+type Renderer interface {
+	Render() (string, error)
+}
+
+// Wrap attaches the --watch flag to the given action.
+// If the action's model does not implement Renderer, --watch is only available non-interactively.
+func Wrap(p action.Pair) action.Pair {
+	p.Action.Flags().Duration(ft.Name.Watch, 0, ft.Usage.Watch)
+
+	run := p.Action.Run
+	p.Action.Run = func(cmd *cobra.Command, args []string) {
+		interval, err := cmd.Flags().GetDuration(ft.Name.Watch)
+		if err != nil {
+			clilog.LogFlagFailedGet(ft.Name.Watch, err)
+		}
+		if interval == 0 {
+			run(cmd, args)
+			return
+		} else if err := validate(interval); err != nil {
+			clilog.Tee(clilog.ERROR, cmd.ErrOrStderr(), err.Error()+"\n")
+			return
+		}
+
+		// catch ctrl+c so we can break out of the loop cleanly
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		loop(ctx, cmd.OutOrStdout(), interval, cmd.CommandPath(), func() { run(cmd, args) })
+	}
+
+	if r, ok := p.Model.(Renderer); ok {
+		p.Model = &model{inner: p.Model, render: r.Render}
+	}
+
+	return p
+}
+
+// validate ensures the given (non-zero) interval is usable.
+func validate(interval time.Duration) error {
+	if interval < MinInterval {
+		return fmt.Errorf("--%s interval must be at least %v (given %v)",
+			ft.Name.Watch, MinInterval, interval)
+	}
+	return nil
+}
+
+// loop clears the screen and invokes fn every interval until ctx is cancelled.
+func loop(ctx context.Context, out io.Writer, interval time.Duration, name string, fn func()) {
+	tkr := time.NewTicker(interval)
+	defer tkr.Stop()
+	for {
+		fmt.Fprint(out, clearScreen)
+		fmt.Fprintf(out, "Every %v: %s\t%s\n\n", interval, name, time.Now().Format(time.DateTime))
+		fn()
+		select {
+		case <-ctx.Done():
+			return
+		case <-tkr.C:
+		}
+	}
+}
+
+// splitWatch pulls the --watch flag (and its value) out of the given tokens so the remainder can be
+// handed to the wrapped model.
+// Returns a zero interval if --watch was not given.
+func splitWatch(tokens []string) (interval time.Duration, rest []string, err error) {
+	var (
+		val   string
+		found bool
+	)
+	rest = make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t == "--":
+			rest = append(rest, tokens[i:]...)
+			i = len(tokens)
+		case t == "--"+ft.Name.Watch:
+			if i+1 >= len(tokens) {
+				return 0, nil, errors.New("flag needs an argument: --" + ft.Name.Watch)
+			}
+			val, found = tokens[i+1], true
+			i++
+		case strings.HasPrefix(t, "--"+ft.Name.Watch+"="):
+			val, found = strings.TrimPrefix(t, "--"+ft.Name.Watch+"="), true
+		default:
+			rest = append(rest, t)
+		}
+	}
+	if !found {
+		return 0, rest, nil
+	}
+	if interval, err = time.ParseDuration(val); err != nil {
+		return 0, nil, err
+	} else if interval == 0 {
+		return 0, rest, nil
+	}
+	return interval, rest, validate(interval)
+}
+
+//#region interactive mode (model) implementation
+
+// tick prompts the model to redraw.
+// gen allows ticks from a previous invocation to be discarded.
+type tick struct {
+	gen uint
+}
+
+type model struct {
+	inner  action.Model
+	render func() (string, error)
+
+	// data cleared by .Reset()
+	interval time.Duration // 0 if not watching
+	out      string        // most recent output
+	last     time.Time     // time of the most recent render
+
+	gen uint // bumped on every reset; never cleared
+}
+
+var _ action.Model = &model{}
+
+func (m *model) Update(msg tea.Msg) tea.Cmd {
+	if m.interval == 0 {
+		return m.inner.Update(msg)
+	}
+	if t, ok := msg.(tick); !ok || t.gen != m.gen {
+		return nil
+	}
+	if s, err := m.render(); err != nil {
+		clilog.Writer.Error(err.Error())
+		m.out = "An error has occurred: " + err.Error()
+	} else if s == "" {
+		m.out = "no data found"
+	} else {
+		m.out = s
+	}
+	m.last = time.Now()
+	return m.next()
+}
+
+// next returns a command that will deliver the next tick for this invocation.
+func (m *model) next() tea.Cmd {
+	gen := m.gen
+	return tea.Tick(m.interval, func(time.Time) tea.Msg { return tick{gen: gen} })
+}
+
+func (m *model) View() string {
+	if m.interval == 0 {
+		return m.inner.View()
+	}
+	if m.last.IsZero() {
+		return "loading..."
+	}
+	return m.out + "\n" + stylesheet.GreyedOutStyle.Render(
+		fmt.Sprintf("Every %v, last refreshed %s. Press esc or ctrl+c to stop.",
+			m.interval, m.last.Format(time.TimeOnly)))
+}
+
+// Done is never true while watching; the user must kill the action.
+func (m *model) Done() bool {
+	if m.interval == 0 {
+		return m.inner.Done()
+	}
+	return false
+}
+
+func (m *model) Reset() error {
+	m.gen++
+	m.interval = 0
+	m.out = ""
+	m.last = time.Time{}
+	return m.inner.Reset()
+}
+
+func (m *model) SetArgs(inherited *pflag.FlagSet, tokens []string) (string, tea.Cmd, error) {
+	interval, rest, err := splitWatch(tokens)
+	if err != nil {
+		return err.Error(), nil, nil
+	}
+	invalid, onStart, err := m.inner.SetArgs(inherited, rest)
+	if err != nil || invalid != "" {
+		return invalid, onStart, err
+	}
+	m.interval = interval
+	if m.interval == 0 {
+		return "", onStart, nil
+	}
+	// draw immediately rather than waiting out the first interval
+	gen := m.gen
+	return "", tea.Batch(onStart, func() tea.Msg { return tick{gen: gen} }), nil
+}
+
+//#endregion interactive mode (model) implementation
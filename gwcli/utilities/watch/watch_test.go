@@ -0,0 +1,111 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package watch
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/pflag"
+)
+
+func TestSplitWatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokens   []string
+		interval time.Duration
+		rest     []string
+		wantErr  bool
+	}{
+		{"absent", []string{"--json"}, 0, []string{"--json"}, false},
+		{"separate value", []string{"--watch", "5s", "--json"}, 5 * time.Second, []string{"--json"}, false},
+		{"equals value", []string{"--csv", "--watch=1m"}, time.Minute, []string{"--csv"}, false},
+		{"zero disables", []string{"--watch=0s"}, 0, []string{}, false},
+		{"after terminator", []string{"--", "--watch", "5s"}, 0, []string{"--", "--watch", "5s"}, false},
+		{"too short", []string{"--watch", "500ms"}, 0, nil, true},
+		{"negative", []string{"--watch=-5s"}, 0, nil, true},
+		{"bad syntax", []string{"--watch", "5"}, 0, nil, true},
+		{"missing value", []string{"--watch"}, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval, rest, err := splitWatch(tt.tokens)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			} else if tt.wantErr {
+				return
+			}
+			if interval != tt.interval {
+				t.Errorf("interval: expected %v, got %v", tt.interval, interval)
+			}
+			if !slices.Equal(rest, tt.rest) {
+				t.Errorf("remaining tokens: expected %v, got %v", tt.rest, rest)
+			}
+		})
+	}
+}
+
+// stub model to wrap
+type stub struct {
+	renders int
+}
+
+func (s *stub) Update(tea.Msg) tea.Cmd                                    { return nil }
+func (s *stub) View() string                                              { return "" }
+func (s *stub) Done() bool                                                { return true }
+func (s *stub) Reset() error                                              { return nil }
+func (s *stub) SetArgs(*pflag.FlagSet, []string) (string, tea.Cmd, error) { return "", nil, nil }
+func (s *stub) Render() (string, error) {
+	s.renders++
+	return "rendered", nil
+}
+
+func TestModelStaleTicks(t *testing.T) {
+	s := &stub{}
+	m := &model{inner: s, render: s.Render}
+
+	if _, _, err := m.SetArgs(nil, []string{"--watch", "2s"}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Done() {
+		t.Fatal("watching model should not be done")
+	}
+	first := tick{gen: m.gen}
+	if cmd := m.Update(first); cmd == nil {
+		t.Fatal("expected the next tick to be scheduled")
+	}
+	if s.renders != 1 || m.out != "rendered" {
+		t.Fatalf("expected a single render, got %d (%q)", s.renders, m.out)
+	}
+
+	// a tick from before the reset must not trigger a render
+	if err := m.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.SetArgs(nil, []string{"--watch", "2s"}); err != nil {
+		t.Fatal(err)
+	}
+	if cmd := m.Update(first); cmd != nil {
+		t.Fatal("stale tick should have been discarded")
+	}
+	if s.renders != 1 {
+		t.Fatalf("stale tick caused a render")
+	}
+
+	// without --watch, the inner model is in control
+	m.Reset()
+	if _, _, err := m.SetArgs(nil, []string{}); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Done() {
+		t.Fatal("expected inner model's Done")
+	}
+}
@@ -0,0 +1,55 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// tagAllowlist restricts the tags a muxer will negotiate to those matching one of a set
+// of glob patterns. A nil tagAllowlist allows every tag.
+type tagAllowlist []glob.Glob
+
+func newTagAllowlist(patterns []string) (tal tagAllowlist, err error) {
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p == `` {
+			continue
+		}
+		var g glob.Glob
+		if g, err = glob.Compile(p); err != nil {
+			return nil, fmt.Errorf("Invalid tag allowlist pattern %q: %w", p, err)
+		}
+		tal = append(tal, g)
+	}
+	return
+}
+
+// allowed returns whether the tag name may be negotiated
+func (tal tagAllowlist) allowed(name string) bool {
+	if tal == nil {
+		return true
+	}
+	for _, g := range tal {
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns an ErrTagNotAllowed error if the tag name may not be negotiated
+func (tal tagAllowlist) check(name string) error {
+	if !tal.allowed(name) {
+		return fmt.Errorf("%w: %q", ErrTagNotAllowed, name)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTagAllowlist(t *testing.T) {
+	if tal, err := newTagAllowlist(nil); err != nil || tal != nil || !tal.allowed(`anything`) {
+		t.Fatal("empty allowlist restricts tags")
+	}
+	if _, err := newTagAllowlist([]string{`zeek[`}); err == nil {
+		t.Fatal("bad pattern was accepted")
+	}
+	tal, err := newTagAllowlist([]string{`zeek*`, ` syslog `, ``})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tn := range []string{`zeekconn`, `zeek`, `syslog`} {
+		if err = tal.check(tn); err != nil {
+			t.Fatalf("%s rejected: %v", tn, err)
+		}
+	}
+	for _, tn := range []string{`corelight`, `syslog2`, `xzeek`} {
+		if err = tal.check(tn); !errors.Is(err, ErrTagNotAllowed) {
+			t.Fatalf("%s allowed: %v", tn, err)
+		}
+	}
+}
+
+func TestMuxerTagAllowlist(t *testing.T) {
+	c := MuxerConfig{
+		Destinations: []Target{{Address: `tcp://127.0.0.1:4023`}},
+		Tags:         []string{`zeekconn`},
+		TagAllowlist: []string{`zeek*`},
+	}
+	im, err := newIngestMuxer(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = im.NegotiateTag(`zeekdns`); err != nil {
+		t.Fatal(err)
+	} else if _, err = im.NegotiateTag(`syslog`); !errors.Is(err, ErrTagNotAllowed) {
+		t.Fatalf("tag outside the allowlist was negotiated: %v", err)
+	} else if _, ok := im.LookupTag(0); !ok {
+		t.Fatal("configured tag is missing")
+	}
+
+	c.Tags = append(c.Tags, `syslog`)
+	if _, err = newIngestMuxer(c); !errors.Is(err, ErrTagNotAllowed) {
+		t.Fatalf("configured tag outside the allowlist was accepted: %v", err)
+	}
+}
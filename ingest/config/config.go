@@ -49,6 +49,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gobwas/glob"
 	"github.com/google/uuid"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/log/rotate"
@@ -68,6 +69,7 @@ const (
 	envPipeTarget        string = `GRAVWELL_PIPE_TARGETS`
 	envCompressionTarget string = `GRAVWELL_ENABLE_COMPRESSION`
 	envCacheMode         string = `GRAVWELL_CACHE_MODE`
+	envCacheEviction     string = `GRAVWELL_CACHE_EVICTION_POLICY`
 	envCachePath         string = `GRAVWELL_CACHE_PATH`
 	envMaxCache          string = `GRAVWELL_CACHE_SIZE`
 	envDisableSelfIngest string = `GRAVWELL_DISABLE_SELF_INGEST`
@@ -83,6 +85,16 @@ const (
 	CACHE_MODE_DEFAULT  = "always"
 	CACHE_DEPTH_DEFAULT = 128
 	CACHE_SIZE_DEFAULT  = 1000
+
+	CACHE_EVICTION_BLOCK       = "block"
+	CACHE_EVICTION_DROP_OLDEST = "drop-oldest"
+	CACHE_EVICTION_DROP_NEW    = "drop-new"
+	CACHE_EVICTION_DEFAULT     = CACHE_EVICTION_BLOCK
+
+	INGEST_DISTRIBUTION_ROUND_ROBIN = "round-robin"
+	INGEST_DISTRIBUTION_HASH_TAG    = "hash-tag"
+	INGEST_DISTRIBUTION_HASH_SRC    = "hash-src"
+	INGEST_DISTRIBUTION_DEFAULT     = INGEST_DISTRIBUTION_ROUND_ROBIN
 )
 
 var (
@@ -90,6 +102,8 @@ var (
 	ErrMissingIngestSecret        = errors.New("Ingest-Secret value missing")
 	ErrInvalidLogLevel            = errors.New("Invalid Log Level")
 	ErrInvalidConnectionTimeout   = errors.New("Invalid connection timeout")
+	ErrInvalidFailoverInterval    = errors.New("Invalid Failover-Check-Interval")
+	ErrInvalidTagAllowlist        = errors.New("Invalid Tag-Allowlist pattern")
 	ErrGlobalSectionNotFound      = errors.New("Global config section not found")
 	ErrInvalidLineLocation        = errors.New("Invalid line location")
 	ErrInvalidUpdateLineParameter = errors.New("Update line location does not contain the specified paramter")
@@ -116,10 +130,15 @@ type IngestConfig struct {
 	Cache_Mode                 string   `json:",omitempty"`
 	Ingest_Cache_Path          string   `json:",omitempty"`
 	Max_Ingest_Cache           int      `json:",omitempty"`
+	Cache_Eviction_Policy      string   `json:",omitempty"` // what to do when the cache reaches Max-Ingest-Cache
 	Log_Source_Override        string   `json:",omitempty"` // override log messages only
 	Label                      string   `json:",omitempty"` //arbitrary label that can be attached to an ingester
 	Disable_Multithreading     bool     //basically set GOMAXPROCS(1)
 	Stats_Sample_Interval      string   `json:",omitempty"` // if set to > 0 duration then we periodically throw stats
+	Ingest_Failover_Targets    []string `json:",omitempty"` // ordered targets only used while all other targets are down
+	Failover_Check_Interval    string   `json:",omitempty"` // how often target health is checked for failover and failback
+	Tag_Allowlist              []string `json:",omitempty"` // glob patterns restricting the tags the ingester may negotiate
+	Ingest_Distribution        string   `json:",omitempty"` // how entries are spread across targets, round-robin or hashed by tag or source
 }
 
 type IngestStreamConfig struct {
@@ -167,6 +186,9 @@ func (ic *IngestConfig) loadDefaults() error {
 	if err := LoadEnvVar(&ic.Cache_Mode, envCacheMode, nil); err != nil {
 		return err
 	}
+	if err := LoadEnvVar(&ic.Cache_Eviction_Policy, envCacheEviction, nil); err != nil {
+		return err
+	}
 	if err := LoadEnvVar(&ic.Ingest_Cache_Path, envCachePath, nil); err != nil {
 		return err
 	}
@@ -219,6 +241,23 @@ func (ic *IngestConfig) Verify() error {
 		return ErrNoConnections
 	}
 
+	if _, err := ic.FailoverTargets(); err != nil {
+		return err
+	}
+	if ic.Failover_Check_Interval != `` {
+		if d, err := time.ParseDuration(ic.Failover_Check_Interval); err != nil {
+			return fmt.Errorf("%w %q %v", ErrInvalidFailoverInterval, ic.Failover_Check_Interval, err)
+		} else if d <= 0 {
+			return fmt.Errorf("%w %q", ErrInvalidFailoverInterval, ic.Failover_Check_Interval)
+		}
+	}
+
+	for _, v := range ic.Tag_Allowlist {
+		if _, err := glob.Compile(strings.TrimSpace(v)); err != nil {
+			return fmt.Errorf("%w %q %v", ErrInvalidTagAllowlist, v, err)
+		}
+	}
+
 	//normalize the log level and check it
 	if err := ic.checkLogLevel(); err != nil {
 		return err
@@ -263,6 +302,20 @@ func (ic *IngestConfig) Verify() error {
 	if ic.Cache_Depth == 0 {
 		ic.Cache_Depth = CACHE_DEPTH_DEFAULT
 	}
+	switch ic.Cache_Eviction_Policy = strings.ToLower(strings.TrimSpace(ic.Cache_Eviction_Policy)); ic.Cache_Eviction_Policy {
+	case "":
+		ic.Cache_Eviction_Policy = CACHE_EVICTION_DEFAULT
+	case CACHE_EVICTION_BLOCK, CACHE_EVICTION_DROP_OLDEST, CACHE_EVICTION_DROP_NEW:
+	default:
+		return errors.New("Cache-Eviction-Policy must be [block,drop-oldest,drop-new]")
+	}
+	switch ic.Ingest_Distribution = strings.ToLower(strings.TrimSpace(ic.Ingest_Distribution)); ic.Ingest_Distribution {
+	case "":
+		ic.Ingest_Distribution = INGEST_DISTRIBUTION_DEFAULT
+	case INGEST_DISTRIBUTION_ROUND_ROBIN, INGEST_DISTRIBUTION_HASH_TAG, INGEST_DISTRIBUTION_HASH_SRC:
+	default:
+		return errors.New("Ingest-Distribution must be [round-robin,hash-tag,hash-src]")
+	}
 	// there are no defaults for the cache_size.
 
 	//if Stats_Sample_Interval is populated, check that we can parse as a duration
@@ -296,6 +349,47 @@ func (ic *IngestConfig) Targets() ([]string, error) {
 	return conns, nil
 }
 
+// FailoverTargets returns the ordered list of Ingest-Failover-Targets, formatted as in Targets.
+// Failover targets specify their connection type, e.g. tls://10.0.0.2; targets without one are
+// treated as cleartext.
+func (ic *IngestConfig) FailoverTargets() (conns []string, err error) {
+	for _, v := range ic.Ingest_Failover_Targets {
+		v = strings.TrimSpace(v)
+		scheme, addr, ok := strings.Cut(v, "://")
+		if !ok {
+			scheme, addr = "tcp", v
+		}
+		if addr == `` {
+			return nil, fmt.Errorf("Invalid Ingest-Failover-Targets value %q, missing address", v)
+		}
+		switch scheme {
+		case "tcp":
+			conns = append(conns, "tcp://"+AppendDefaultPort(addr, DefaultCleartextPort))
+		case "tls":
+			conns = append(conns, "tls://"+AppendDefaultPort(addr, DefaultTLSPort))
+		case "pipe":
+			conns = append(conns, "pipe://"+addr)
+		default:
+			return nil, fmt.Errorf("Invalid Ingest-Failover-Targets value %q, unknown connection type", v)
+		}
+	}
+	return
+}
+
+// FailoverCheckInterval returns how often target health is checked to decide whether to fail over
+// to, or back from, the failover targets. Zero selects the muxer default.
+func (ic *IngestConfig) FailoverCheckInterval() (dur time.Duration) {
+	if ic == nil || ic.Failover_Check_Interval == `` {
+		return
+	}
+	var err error
+	if dur, err = time.ParseDuration(ic.Failover_Check_Interval); err != nil {
+		// bad parses are just zero, validate should prevent this though
+		dur = 0
+	}
+	return
+}
+
 // InsecureSkipTLSVerification returns true if the Insecure-Skip-TLS-Verify
 // config parameter was set.
 func (ic *IngestConfig) InsecureSkipTLSVerification() bool {
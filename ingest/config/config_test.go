@@ -9,8 +9,11 @@
 package config
 
 import (
+	"errors"
 	"net"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestParseSourceIP(t *testing.T) {
@@ -53,3 +56,75 @@ func TestParseSourceIntegter(t *testing.T) {
 		}
 	}
 }
+
+func TestFailoverTargets(t *testing.T) {
+	ic := IngestConfig{
+		Ingest_Failover_Targets: []string{`10.0.0.2`, `tcp://10.0.0.3:5555`, `tls://idx.example.com`, `pipe:///opt/gravwell/comms/pipe`},
+	}
+	conns, err := ic.FailoverTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`tcp://10.0.0.2:4023`, `tcp://10.0.0.3:5555`, `tls://idx.example.com:4024`, `pipe:///opt/gravwell/comms/pipe`}
+	if len(conns) != len(want) {
+		t.Fatalf("bad targets: %v", conns)
+	}
+	for i := range want {
+		if conns[i] != want[i] {
+			t.Fatalf("bad target %d: %s != %s", i, conns[i], want[i])
+		}
+	}
+	for _, bad := range []string{`udp://10.0.0.2`, `tls://`, ``} {
+		ic.Ingest_Failover_Targets = []string{bad}
+		if _, err = ic.FailoverTargets(); err == nil {
+			t.Fatalf("%q was accepted", bad)
+		}
+	}
+
+	if d := ic.FailoverCheckInterval(); d != 0 {
+		t.Fatalf("unset interval is not zero: %v", d)
+	}
+	ic.Failover_Check_Interval = `30s`
+	if d := ic.FailoverCheckInterval(); d != 30*time.Second {
+		t.Fatalf("bad interval: %v", d)
+	}
+}
+
+func TestTagAllowlistVerify(t *testing.T) {
+	ic := IngestConfig{
+		Ingest_Secret:            `secret`,
+		Cleartext_Backend_Target: []string{`10.0.0.1`},
+		Log_File:                 filepath.Join(t.TempDir(), `ingester.log`),
+		Tag_Allowlist:            []string{`zeek*`, `syslog`},
+	}
+	if err := ic.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	ic.Tag_Allowlist = append(ic.Tag_Allowlist, `zeek[`)
+	if err := ic.Verify(); !errors.Is(err, ErrInvalidTagAllowlist) {
+		t.Fatalf("bad pattern was accepted: %v", err)
+	}
+}
+
+func TestIngestDistributionVerify(t *testing.T) {
+	ic := IngestConfig{
+		Ingest_Secret:            `secret`,
+		Cleartext_Backend_Target: []string{`10.0.0.1`, `10.0.0.2`},
+		Log_File:                 filepath.Join(t.TempDir(), `ingester.log`),
+	}
+	if err := ic.Verify(); err != nil {
+		t.Fatal(err)
+	} else if ic.Ingest_Distribution != INGEST_DISTRIBUTION_DEFAULT {
+		t.Fatalf("bad default distribution %q", ic.Ingest_Distribution)
+	}
+	ic.Ingest_Distribution = ` Hash-Tag `
+	if err := ic.Verify(); err != nil {
+		t.Fatal(err)
+	} else if ic.Ingest_Distribution != INGEST_DISTRIBUTION_HASH_TAG {
+		t.Fatalf("distribution not normalized %q", ic.Ingest_Distribution)
+	}
+	ic.Ingest_Distribution = `random`
+	if err := ic.Verify(); err == nil {
+		t.Fatal("bad distribution was accepted")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -39,6 +40,7 @@ var (
 	ErrBadMap                 = errors.New("VariableConfig has not be initialized")
 	ErrNotFound               = errors.New("not found")
 	ErrIsNotDirectory         = errors.New("path is not a directory")
+	ErrUnknownVariable        = errors.New("unknown variable")
 )
 
 type VariableConfig struct {
@@ -158,6 +160,37 @@ func (vc VariableConfig) MapTo(v interface{}) (err error) {
 	return
 }
 
+// MapToStrict behaves like MapTo, but also returns an ErrUnknownVariable error naming every
+// variable in the section which does not map to an exported field of v or to one of the
+// extra names, so that misspelled keys are not silently ignored.
+// Extra names are field style names such as Flush_Interval, for variables handled elsewhere.
+func (vc VariableConfig) MapToStrict(v interface{}, extra ...string) (err error) {
+	if err = vc.MapTo(v); err != nil {
+		return
+	}
+	known := make(map[string]bool, len(extra))
+	for _, n := range extra {
+		known[strings.ToLower(nameMapper(n))] = true
+	}
+	tp := reflect.TypeOf(v).Elem()
+	for i := 0; i < tp.NumField(); i++ {
+		if fld := tp.Field(i); fld.IsExported() {
+			known[strings.ToLower(nameMapper(fld.Name))] = true
+		}
+	}
+	var unknown []string
+	for _, n := range vc.Names() {
+		if !known[strings.ToLower(n)] {
+			unknown = append(unknown, strconv.Quote(n))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		err = fmt.Errorf("%w %s", ErrUnknownVariable, strings.Join(unknown, ", "))
+	}
+	return
+}
+
 func (vc VariableConfig) get(name string) (v string, ok bool) {
 	var temp *[]string
 	if temp = vc.Vals[vc.Idx(name)]; temp != nil {
@@ -191,6 +224,9 @@ func (vc VariableConfig) mapStruct(v interface{}) error {
 	}
 	typeOf := rv.Type()
 	for i := 0; i < rv.NumField(); i++ {
+		if !typeOf.Field(i).IsExported() {
+			continue //unexported fields cannot be set and are not config variables
+		}
 		if err := vc.setField(typeOf.Field(i).Name, rv.Field(i)); err != nil {
 			return err
 		}
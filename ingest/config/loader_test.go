@@ -9,6 +9,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -284,3 +285,56 @@ func TestLoadBadKey(t *testing.T) {
 		t.Fatalf("Faild to call out Key name Foobar in error %q", err)
 	}
 }
+
+func TestMapToStrict(t *testing.T) {
+	b := []byte(`
+	[preprocessor "good"]
+		type = corelight
+		prefix = zeek
+		Flush-Interval = 1s
+		Float-Precision = 3
+
+	[preprocessor "typo"]
+		type = corelight
+		Prefex = zeek
+		Float-Precison = 3
+	`)
+	var v testStruct
+	if err := LoadConfigBytes(&v, b); err != nil {
+		t.Fatal(err)
+	}
+	type target struct {
+		Type            string
+		Prefix          string
+		Float_Precision int
+		hidden          string
+	}
+	var tgt target
+	if err := v.Preprocessor[`good`].MapToStrict(&tgt, `Flush_Interval`); err != nil {
+		t.Fatal(err)
+	} else if tgt.Prefix != `zeek` || tgt.Float_Precision != 3 {
+		t.Fatalf("bad mapping: %+v", tgt)
+	}
+	// without the extra name Flush-Interval is unknown
+	if err := v.Preprocessor[`good`].MapToStrict(&tgt); !errors.Is(err, ErrUnknownVariable) {
+		t.Fatalf("failed to catch unknown variable: %v", err)
+	}
+	err := v.Preprocessor[`typo`].MapToStrict(&target{})
+	if !errors.Is(err, ErrUnknownVariable) {
+		t.Fatalf("failed to catch unknown variables: %v", err)
+	} else if !strings.Contains(err.Error(), `"Prefex"`) || !strings.Contains(err.Error(), `"Float-Precison"`) {
+		t.Fatalf("error does not name the unknown variables: %v", err)
+	}
+	// unexported fields are not valid variables
+	b = []byte(`
+	[preprocessor "hidden"]
+		type = corelight
+		hidden = x
+	`)
+	v = testStruct{}
+	if err := LoadConfigBytes(&v, b); err != nil {
+		t.Fatal(err)
+	} else if err = v.Preprocessor[`hidden`].MapToStrict(&target{}); !errors.Is(err, ErrUnknownVariable) {
+		t.Fatalf("failed to catch unexported field: %v", err)
+	}
+}
@@ -33,16 +33,28 @@ var (
 // the point of this function is to make it easy for ingester writers to just hand in their GetConfig function
 // and the two paths and get a "go/no go" on the configurations.
 func ValidateConfig(fnc interface{}, pth, confdPath string) {
-	validateConfig(fnc, pth, confdPath, false) // this is used by NOT ingesters
+	validateConfig(fnc, pth, confdPath, false, true) // this is used by NOT ingesters
 }
 
 // ValidateIngesterConfig behaves same as ValidateConfig but also asserts that the provided config
 // can return an IngestBaseConfig object.
 func ValidateIngesterConfig(fnc interface{}, pth, confdPath string) {
-	validateConfig(fnc, pth, confdPath, true) // this is used by ingesters
+	validateConfig(fnc, pth, confdPath, true, true) // this is used by ingesters
 }
 
-func validateConfig(fnc interface{}, pth, confdPath string, assertIngester bool) {
+// CheckIngesterConfig behaves as ValidateIngesterConfig but returns, rather than exiting, once the
+// configuration is found to be valid. It is used by ingesters which go on to check more of their
+// setup when the validate flag is set, see Requested.
+func CheckIngesterConfig(fnc interface{}, pth, confdPath string) {
+	validateConfig(fnc, pth, confdPath, true, false)
+}
+
+// Requested returns whether the validate flag is set.
+func Requested() bool {
+	return *vflag
+}
+
+func validateConfig(fnc interface{}, pth, confdPath string, assertIngester, exit bool) {
 	if !*vflag {
 		return
 	}
@@ -105,7 +117,9 @@ func validateConfig(fnc interface{}, pth, confdPath string, assertIngester bool)
 	} else {
 		fmt.Println(pth, "is valid")
 	}
-	os.Exit(0) //all good
+	if exit {
+		os.Exit(0) //all good
+	}
 }
 
 type validator interface {
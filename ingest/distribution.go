@@ -0,0 +1,291 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"errors"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	DistributionRoundRobin = `round-robin`
+	DistributionHashTag    = `hash-tag`
+	DistributionHashSrc    = `hash-src`
+
+	routeDepth int           = 64                    // entries and batches buffered per destination
+	routeRetry time.Duration = 10 * time.Millisecond // how long to wait on a full or missing destination
+)
+
+var (
+	ErrInvalidDistribution = errors.New("Invalid ingest distribution")
+)
+
+// distributor routes entries to the primary destinations of a muxer by a hash of their tag name or
+// source rather than letting every connection pull from the shared queues.
+// Owners are chosen by rendezvous hashing over the destination addresses, so a key always lands on
+// the same destination for a given set of targets regardless of start order, and only the keys owned
+// by a destination move when it goes down. Failover destinations are not part of the hash, they pull
+// from the shared queues as usual while every primary is down.
+type distributor struct {
+	mode     string
+	dests    []uint64           // hash of each primary destination address
+	eRoute   []chan interface{} // per destination entry queues
+	bRoute   []chan interface{} // per destination batch queues
+	tagNames map[entry.EntryTag]uint64
+}
+
+// newDistributor returns a distributor for the given mode, a nil distributor means round-robin
+func newDistributor(mode string, dests []Target) (*distributor, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case ``, DistributionRoundRobin:
+		return nil, nil
+	case DistributionHashTag, DistributionHashSrc:
+	default:
+		return nil, ErrInvalidDistribution
+	}
+	d := &distributor{
+		mode:     mode,
+		dests:    make([]uint64, len(dests)),
+		eRoute:   make([]chan interface{}, len(dests)),
+		bRoute:   make([]chan interface{}, len(dests)),
+		tagNames: make(map[entry.EntryTag]uint64),
+	}
+	for i := range dests {
+		d.dests[i] = hashString(dests[i].Address)
+		d.eRoute[i] = make(chan interface{}, routeDepth)
+		d.bRoute[i] = make(chan interface{}, routeDepth)
+	}
+	return d, nil
+}
+
+// routes returns the entry and batch queues for the destination at igIdx, failover destinations
+// and round-robin muxers get nil.
+func (d *distributor) routes(igIdx int) (eC, bC chan interface{}) {
+	if d == nil || igIdx >= len(d.dests) {
+		return
+	}
+	return d.eRoute[igIdx], d.bRoute[igIdx]
+}
+
+// key returns the hash key of an entry, entries without a SRC are keyed by tag when hashing by source.
+// lookup resolves tag names so that keys are stable across restarts, it must not be called with the
+// muxer lock held.
+func (d *distributor) key(e *entry.Entry, lookup func(entry.EntryTag) (string, bool)) uint64 {
+	if d.mode == DistributionHashSrc && len(e.SRC) > 0 {
+		h := fnv.New64a()
+		h.Write(e.SRC.To16())
+		return h.Sum64()
+	}
+	k, ok := d.tagNames[e.Tag]
+	if !ok {
+		name, found := lookup(e.Tag)
+		if !found {
+			//unknown tags are dropped by the relay, just don't remember them
+			return uint64(e.Tag)
+		}
+		k = hashString(name)
+		d.tagNames[e.Tag] = k
+	}
+	return k
+}
+
+// owner returns the index of the destination which owns key amongst those that are up,
+// -1 if none are up.
+func (d *distributor) owner(key uint64, up func(int) bool) (idx int) {
+	var best uint64
+	idx = -1
+	for i, dh := range d.dests {
+		if !up(i) {
+			continue
+		}
+		if score := mix64(key ^ dh); idx < 0 || score > best {
+			best = score
+			idx = i
+		}
+	}
+	return
+}
+
+func hashString(v string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(v))
+	return h.Sum64()
+}
+
+// mix64 is the splitmix64 finalizer, it spreads the combined key and destination hashes
+func mix64(v uint64) uint64 {
+	v ^= v >> 30
+	v *= 0xbf58476d1ce4e5b9
+	v ^= v >> 27
+	v *= 0x94d049bb133111eb
+	v ^= v >> 31
+	return v
+}
+
+// primaryUp reports whether the primary destination at igIdx is connected, the muxer lock must be held
+func (im *IngestMuxer) primaryUp(igIdx int) bool {
+	return igIdx < len(im.igst) && im.igst[igIdx] != nil
+}
+
+// anyPrimaryUp reports whether at least one primary destination is connected
+func (im *IngestMuxer) anyPrimaryUp() (up bool) {
+	im.mtx.RLock()
+	for i := range im.dist.dests {
+		if up = im.primaryUp(i); up {
+			break
+		}
+	}
+	im.mtx.RUnlock()
+	return
+}
+
+// distributeRoutine pulls entries off the shared queues and hands them to the destination that
+// owns them. Nothing is pulled while every primary is down so the failover destinations and the
+// cache can take over.
+func (im *IngestMuxer) distributeRoutine() {
+	defer im.wg.Done()
+	for {
+		if !im.anyPrimaryUp() {
+			if im.quitableSleep(routeRetry, nil) {
+				return
+			}
+			continue
+		}
+		select {
+		case <-im.dieChan:
+			return
+		case ee, ok := <-im.eChanOut:
+			if !ok {
+				return
+			}
+			if e, _ := ee.(*entry.Entry); e != nil {
+				im.distribute([]*entry.Entry{e}, false)
+			}
+		case bb, ok := <-im.bChanOut:
+			if !ok {
+				return
+			}
+			if b, _ := bb.([]*entry.Entry); len(b) > 0 {
+				im.distribute(b, true)
+			}
+		}
+	}
+}
+
+// distribute splits ents by owner and pushes each set onto its destination's queue, as a batch if
+// batch is set. Entries that cannot be placed because every primary is down or the muxer is closing
+// go to the emergency queue.
+func (im *IngestMuxer) distribute(ents []*entry.Entry, batch bool) {
+	keys := make([]uint64, 0, len(ents))
+	live := ents[:0]
+	for _, e := range ents {
+		if e != nil {
+			live = append(live, e)
+			keys = append(keys, im.dist.key(e, im.LookupTag))
+		}
+	}
+	ents = live
+	up := im.primaryUp
+	for len(ents) > 0 {
+		im.mtx.RLock()
+		idx := im.dist.owner(keys[0], up)
+		if idx < 0 {
+			im.mtx.RUnlock()
+			im.eq.push(nil, ents)
+			return
+		}
+		var mine, rest []*entry.Entry
+		var restKeys []uint64
+		for i := range ents {
+			if i == 0 || im.dist.owner(keys[i], up) == idx {
+				mine = append(mine, ents[i])
+			} else {
+				rest = append(rest, ents[i])
+				restKeys = append(restKeys, keys[i])
+			}
+		}
+		var v interface{}
+		ch := im.dist.eRoute[idx]
+		if batch {
+			v, ch = mine, im.dist.bRoute[idx]
+		} else {
+			v = mine[0]
+		}
+		//push while holding the lock so a destination that is released cannot pick up new entries
+		select {
+		case ch <- v:
+			im.mtx.RUnlock()
+			ents, keys = rest, restKeys
+			continue
+		default:
+		}
+		im.mtx.RUnlock()
+		if im.quitableSleep(routeRetry, nil) {
+			im.eq.push(nil, ents)
+			return
+		}
+	}
+}
+
+// drainRoutes recycles anything queued for the destination at igIdx so it can be redistributed,
+// the destination must already be released.
+func (im *IngestMuxer) drainRoutes(igIdx int) {
+	eC, bC := im.dist.routes(igIdx)
+	if eC == nil {
+		return
+	}
+	for {
+		select {
+		case v := <-eC:
+			if e, _ := v.(*entry.Entry); e != nil {
+				im.recycleEntry(e)
+			}
+		case v := <-bC:
+			if b, _ := v.([]*entry.Entry); len(b) > 0 {
+				im.recycleEntryBatch(b)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// flushRoutes hands anything still queued for a destination back to the shared queues when the
+// muxer closes so that it can be cached.
+func (im *IngestMuxer) flushRoutes() {
+	if im.dist == nil {
+		return
+	}
+	for i := range im.dist.dests {
+		for len(im.dist.eRoute[i]) > 0 {
+			v := <-im.dist.eRoute[i]
+			select {
+			case im.eChan <- v:
+			default:
+				if e, _ := v.(*entry.Entry); e != nil {
+					im.eq.push(e, nil)
+				}
+			}
+		}
+		for len(im.dist.bRoute[i]) > 0 {
+			v := <-im.dist.bRoute[i]
+			select {
+			case im.bChan <- v:
+			default:
+				if b, _ := v.([]*entry.Entry); len(b) > 0 {
+					im.eq.push(nil, b)
+				}
+			}
+		}
+	}
+}
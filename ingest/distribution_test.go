@@ -0,0 +1,123 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func allUp(int) bool { return true }
+
+// tagOwners returns the address of the destination each tag is routed to
+func tagOwners(t *testing.T, c MuxerConfig, up func(int) bool) map[string]string {
+	im, err := newIngestMuxer(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owners := make(map[string]string)
+	for _, tn := range c.Tags {
+		tg, err := im.GetTag(tn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		idx := im.dist.owner(im.dist.key(&entry.Entry{Tag: tg}, im.LookupTag), up)
+		if idx < 0 {
+			t.Fatalf("no owner for %s", tn)
+		}
+		owners[tn] = c.Destinations[idx].Address
+	}
+	return owners
+}
+
+func TestDistributionStable(t *testing.T) {
+	c := MuxerConfig{
+		Destinations:       []Target{{Address: `tcp://10.0.0.1:4023`}, {Address: `tcp://10.0.0.2:4023`}, {Address: `tcp://10.0.0.3:4023`}},
+		IngestDistribution: DistributionHashTag,
+	}
+	for i := 0; i < 64; i++ {
+		c.Tags = append(c.Tags, fmt.Sprintf("tag%d", i))
+	}
+	first := tagOwners(t, c, allUp)
+	used := make(map[string]bool)
+	for _, v := range first {
+		used[v] = true
+	}
+	if len(used) != len(c.Destinations) {
+		t.Fatalf("tags were not spread across destinations: %v", used)
+	}
+
+	//restart with the tags negotiated and the targets listed in a different order
+	for i, j := 0, len(c.Tags)-1; i < j; i, j = i+1, j-1 {
+		c.Tags[i], c.Tags[j] = c.Tags[j], c.Tags[i]
+	}
+	c.Destinations = []Target{c.Destinations[2], c.Destinations[0], c.Destinations[1]}
+	for tn, addr := range tagOwners(t, c, allUp) {
+		if first[tn] != addr {
+			t.Fatalf("tag %s moved from %s to %s", tn, first[tn], addr)
+		}
+	}
+
+	//only the tags owned by a down destination move
+	down := c.Destinations[0].Address
+	for tn, addr := range tagOwners(t, c, func(i int) bool { return i != 0 }) {
+		if addr == down {
+			t.Fatalf("tag %s routed to down destination", tn)
+		} else if first[tn] != down && first[tn] != addr {
+			t.Fatalf("tag %s moved from %s to %s", tn, first[tn], addr)
+		}
+	}
+	if idx := newDistributorOrFail(t, c).owner(1, func(int) bool { return false }); idx != -1 {
+		t.Fatalf("owner found with every destination down: %d", idx)
+	}
+}
+
+func TestDistributionSrc(t *testing.T) {
+	d := newDistributorOrFail(t, MuxerConfig{
+		Destinations:       []Target{{Address: `10.0.0.1`}, {Address: `10.0.0.2`}},
+		IngestDistribution: DistributionHashSrc,
+	})
+	lookup := func(tg entry.EntryTag) (string, bool) { return `default`, true }
+	a := &entry.Entry{Tag: 1, SRC: net.ParseIP(`192.168.1.1`)}
+	b := &entry.Entry{Tag: 2, SRC: net.ParseIP(`192.168.1.1`).To4()}
+	if d.key(a, lookup) != d.key(b, lookup) {
+		t.Fatal("same source produced different keys")
+	}
+	//entries without a source fall back to the tag
+	if d.key(&entry.Entry{Tag: 1}, lookup) != hashString(`default`) {
+		t.Fatal("entry without a source was not keyed by tag")
+	}
+}
+
+func TestDistributionConfig(t *testing.T) {
+	dests := []Target{{Address: `10.0.0.1`}}
+	for _, v := range []string{``, DistributionRoundRobin, `Round-Robin`} {
+		if d, err := newDistributor(v, dests); err != nil || d != nil {
+			t.Fatalf("%q did not select round-robin: %v", v, err)
+		}
+	}
+	if d, err := newDistributor(`HASH-TAG`, dests); err != nil || d == nil || d.mode != DistributionHashTag {
+		t.Fatalf("failed to select hash-tag: %v", err)
+	}
+	if _, err := newDistributor(`hash-dst`, dests); !errors.Is(err, ErrInvalidDistribution) {
+		t.Fatalf("bad distribution accepted: %v", err)
+	}
+}
+
+func newDistributorOrFail(t *testing.T, c MuxerConfig) *distributor {
+	d, err := newDistributor(c.IngestDistribution, c.Destinations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
@@ -0,0 +1,215 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+const (
+	defaultFailoverCheckInterval time.Duration = 10 * time.Second
+)
+
+// failoverSet manages the failover destinations of a muxer.
+// Failover destinations follow the primary destinations in IngestMuxer.dests and sit idle until
+// every primary is found down by a health check. They are then activated one per check, in order,
+// until one connects; lower ordered failovers that are still trying keep trying and take over when
+// they come up. Once a primary recovers every failover is synced and disconnected, with any
+// unconfirmed entries handed back to the primaries.
+type failoverSet struct {
+	sync.Mutex
+	first    int // index of the first failover destination in IngestMuxer.dests
+	interval time.Duration
+	start    []chan struct{} // signals an idle failover routine to connect
+	stop     []chan struct{} // closed to disconnect a failover, nil while inactive
+}
+
+func newFailoverSet(primaries, failovers int, interval time.Duration) *failoverSet {
+	if failovers == 0 {
+		return nil
+	}
+	if interval <= 0 {
+		interval = defaultFailoverCheckInterval
+	}
+	fs := &failoverSet{
+		first:    primaries,
+		interval: interval,
+		start:    make([]chan struct{}, failovers),
+		stop:     make([]chan struct{}, failovers),
+	}
+	for i := range fs.start {
+		fs.start[i] = make(chan struct{}, 1)
+	}
+	return fs
+}
+
+// isFailover returns whether the destination at igIdx is a failover destination
+func (fs *failoverSet) isFailover(igIdx int) bool {
+	return fs != nil && igIdx >= fs.first
+}
+
+// failoverPlan decides which failover destinations should be active, given whether any primary
+// destination is connected and which failovers are currently active and connected.
+func failoverPlan(primaryHot bool, active, hot []bool) (want []bool) {
+	want = make([]bool, len(active))
+	if primaryHot {
+		return //fail back
+	}
+	for i := range active {
+		if active[i] && hot[i] {
+			//prefer the lowest ordered connected failover, let anything before it keep trying
+			copy(want, active[:i])
+			want[i] = true
+			return
+		}
+	}
+	//nothing is connected, escalate to the next failover in line
+	copy(want, active)
+	for i := range want {
+		if !want[i] {
+			want[i] = true
+			break
+		}
+	}
+	return
+}
+
+// failoverRoutine waits for the failover destination at igIdx to be activated and runs a connection
+// session until it is deactivated, repeating until the muxer closes or the destination fails.
+func (im *IngestMuxer) failoverRoutine(igIdx int) {
+	defer im.wg.Done()
+	fs := im.failover
+	i := igIdx - fs.first
+	for {
+		select {
+		case _ = <-im.dieChan:
+			return
+		case <-fs.start[i]:
+		}
+		fs.Lock()
+		stop := fs.stop[i]
+		fs.Unlock()
+		if stop == nil {
+			continue //deactivated before we got going
+		}
+		if im.connSession(igIdx, stop) {
+			return
+		}
+	}
+}
+
+// failoverSupervisor periodically checks the health of the destinations and activates or
+// deactivates failover destinations accordingly.
+func (im *IngestMuxer) failoverSupervisor() {
+	defer im.wg.Done()
+	tkr := time.NewTicker(im.failover.interval)
+	defer tkr.Stop()
+	for {
+		select {
+		case _ = <-im.dieChan:
+			return
+		case <-tkr.C:
+			im.checkFailover()
+		}
+	}
+}
+
+func (im *IngestMuxer) checkFailover() {
+	fs := im.failover
+	var primaryHot bool
+	hot := make([]bool, len(im.dests)-fs.first)
+	im.mtx.RLock()
+	for i, ig := range im.igst {
+		if ig == nil {
+			continue
+		} else if i < fs.first {
+			primaryHot = true
+		} else {
+			hot[i-fs.first] = true
+		}
+	}
+	im.mtx.RUnlock()
+
+	fs.Lock()
+	defer fs.Unlock()
+	active := make([]bool, len(fs.stop))
+	var anyActive bool
+	for i := range fs.stop {
+		active[i] = fs.stop[i] != nil
+		anyActive = anyActive || active[i]
+	}
+	if primaryHot && anyActive {
+		im.Info("primary indexer connection recovered, failing back", log.KV("ingester", im.name), log.KV("ingesteruuid", im.uuid))
+	} else if !primaryHot && !anyActive {
+		im.Warn("all primary indexer connections are down, failing over", log.KV("ingester", im.name), log.KV("ingesteruuid", im.uuid))
+	}
+	want := failoverPlan(primaryHot, active, hot)
+	for i := range want {
+		if want[i] == active[i] {
+			continue
+		}
+		dst := im.dests[fs.first+i].Address
+		if want[i] {
+			fs.stop[i] = make(chan struct{})
+			select {
+			case fs.start[i] <- struct{}{}:
+			default:
+			}
+			im.Info("activating failover indexer", log.KV("indexer", dst), log.KV("ingester", im.name), log.KV("ingesteruuid", im.uuid))
+		} else {
+			close(fs.stop[i])
+			fs.stop[i] = nil
+			im.Info("deactivating failover indexer", log.KV("indexer", dst), log.KV("ingester", im.name), log.KV("ingesteruuid", im.uuid))
+		}
+	}
+}
+
+// isStopped returns whether the given stop channel has been closed, a nil channel is never stopped
+func isStopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+	}
+	return false
+}
+
+// ActiveTargets returns the addresses of the destinations that currently have a live connection,
+// including any failover destinations carrying entries while the primaries are down.
+func (im *IngestMuxer) ActiveTargets() (tgts []string, err error) {
+	im.mtx.RLock()
+	defer im.mtx.RUnlock()
+	if im.state != running {
+		return nil, ErrNotRunning
+	}
+	for i, ig := range im.igst {
+		if ig != nil {
+			tgts = append(tgts, im.dests[i].Address)
+		}
+	}
+	return
+}
+
+// FailoverActive returns true if a failover destination is connected.
+func (im *IngestMuxer) FailoverActive() (bool, error) {
+	im.mtx.RLock()
+	defer im.mtx.RUnlock()
+	if im.state != running {
+		return false, ErrNotRunning
+	}
+	for i, ig := range im.igst {
+		if ig != nil && im.failover.isFailover(i) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
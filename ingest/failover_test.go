@@ -0,0 +1,61 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailoverPlan(t *testing.T) {
+	tests := []struct {
+		name       string
+		primaryHot bool
+		active     []bool
+		hot        []bool
+		want       []bool
+	}{
+		{name: "primary up", primaryHot: true, active: []bool{false, false}, hot: []bool{false, false}, want: []bool{false, false}},
+		{name: "fail back", primaryHot: true, active: []bool{true, true}, hot: []bool{false, true}, want: []bool{false, false}},
+		{name: "fail over", active: []bool{false, false, false}, hot: []bool{false, false, false}, want: []bool{true, false, false}},
+		{name: "escalate", active: []bool{true, false, false}, hot: []bool{false, false, false}, want: []bool{true, true, false}},
+		{name: "all trying", active: []bool{true, true}, hot: []bool{false, false}, want: []bool{true, true}},
+		{name: "settle", active: []bool{true, true, true}, hot: []bool{false, true, true}, want: []bool{true, true, false}},
+		{name: "preferred recovers", active: []bool{true, true}, hot: []bool{true, true}, want: []bool{true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failoverPlan(tt.primaryHot, tt.active, tt.hot)
+			if len(got) != len(tt.want) {
+				t.Fatalf("bad plan length: %v", got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("bad plan: %v != %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFailoverSet(t *testing.T) {
+	if fs := newFailoverSet(2, 0, 0); fs != nil || fs.isFailover(3) {
+		t.Fatal("failover set created without failover destinations")
+	}
+	fs := newFailoverSet(2, 2, 0)
+	if fs.interval != defaultFailoverCheckInterval {
+		t.Fatalf("bad default interval: %v", fs.interval)
+	}
+	if fs.isFailover(1) || !fs.isFailover(2) || !fs.isFailover(3) {
+		t.Fatal("bad failover index")
+	}
+	if fs = newFailoverSet(1, 1, time.Second); fs.interval != time.Second {
+		t.Fatalf("bad interval: %v", fs.interval)
+	}
+}
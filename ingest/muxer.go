@@ -38,6 +38,10 @@ import (
 const (
 	CacheModeAlways = `always`
 	CacheModeFail   = `fail`
+
+	CacheEvictionBlock      = `block`
+	CacheEvictionDropOldest = `drop-oldest`
+	CacheEvictionDropNew    = `drop-new`
 )
 
 var (
@@ -54,6 +58,7 @@ var (
 	ErrTimeout               = errors.New("Timed out waiting for ingesters")
 	ErrWriteTimeout          = errors.New("Timed out waiting to write entry")
 	ErrInvalidEntry          = errors.New("Invalid entry value")
+	ErrTagNotAllowed         = errors.New("Tag is not in the tag allowlist")
 
 	errNotImp = errors.New("Not implemented yet")
 )
@@ -136,6 +141,9 @@ type IngestMuxer struct {
 	start                time.Time    // when the muxer was started
 	attacher             *attach.Attacher
 	attachActive         bool
+	failover             *failoverSet // nil if there are no failover destinations
+	allowlist            tagAllowlist // nil if every tag may be negotiated
+	dist                 *distributor // nil for round-robin distribution
 }
 
 type UniformMuxerConfig struct {
@@ -160,6 +168,15 @@ type UniformMuxerConfig struct {
 	RateLimitBps      int64
 	LogSourceOverride net.IP
 	Attach            attach.AttachConfig
+	// CacheEvictionPolicy controls what happens once the cache reaches CacheSize, defaults to block
+	CacheEvictionPolicy string
+	// FailoverDestinations are only connected while every destination is down, in order of preference
+	FailoverDestinations  []string
+	FailoverCheckInterval time.Duration // how often destination health is evaluated, defaults to 10s
+	// TagAllowlist restricts negotiated tags to those matching one of these glob patterns, empty allows all
+	TagAllowlist []string
+	// IngestDistribution selects how entries are spread across destinations, defaults to round-robin
+	IngestDistribution string
 }
 
 type MuxerConfig struct {
@@ -182,6 +199,15 @@ type MuxerConfig struct {
 	RateLimitBps      int64
 	LogSourceOverride net.IP
 	Attach            attach.AttachConfig
+	// CacheEvictionPolicy controls what happens once the cache reaches CacheSize, defaults to block
+	CacheEvictionPolicy string
+	// FailoverDestinations are only connected while every destination is down, in order of preference
+	FailoverDestinations  []Target
+	FailoverCheckInterval time.Duration // how often destination health is evaluated, defaults to 10s
+	// TagAllowlist restricts negotiated tags to those matching one of these glob patterns, empty allows all
+	TagAllowlist []string
+	// IngestDistribution selects how entries are spread across destinations, defaults to round-robin
+	IngestDistribution string
 }
 
 func NewUniformMuxer(c UniformMuxerConfig) (*IngestMuxer, error) {
@@ -222,6 +248,12 @@ func newUniformIngestMuxerEx(c UniformMuxerConfig) (*IngestMuxer, error) {
 	if len(destinations) == 0 {
 		return nil, ErrNoTargets
 	}
+	failovers := make([]Target, len(c.FailoverDestinations))
+	for i := range c.FailoverDestinations {
+		failovers[i].Address = c.FailoverDestinations[i]
+		failovers[i].Secret = c.Auth
+		failovers[i].Tenant = c.Tenant
+	}
 	cfg := MuxerConfig{
 		IngestStreamConfig: c.IngestStreamConfig,
 		Destinations:       destinations,
@@ -242,6 +274,12 @@ func newUniformIngestMuxerEx(c UniformMuxerConfig) (*IngestMuxer, error) {
 		Logger:             c.Logger,
 		LogSourceOverride:  c.LogSourceOverride,
 		Attach:             c.Attach,
+
+		CacheEvictionPolicy:   c.CacheEvictionPolicy,
+		FailoverDestinations:  failovers,
+		FailoverCheckInterval: c.FailoverCheckInterval,
+		TagAllowlist:          c.TagAllowlist,
+		IngestDistribution:    c.IngestDistribution,
 	}
 	return newIngestMuxer(cfg)
 }
@@ -262,11 +300,34 @@ func NewIngestMuxerExt(dests []Target, tags []string, pubKey, privKey string, ca
 	return newIngestMuxer(c)
 }
 
+// cacheOverflowPolicy maps a cache eviction policy name to the chancacher policy.
+func cacheOverflowPolicy(v string) (chancacher.OverflowPolicy, error) {
+	switch strings.ToLower(v) {
+	case ``, CacheEvictionBlock:
+		return chancacher.OverflowBlock, nil
+	case CacheEvictionDropOldest:
+		return chancacher.OverflowDropOldest, nil
+	case CacheEvictionDropNew:
+		return chancacher.OverflowDropNew, nil
+	}
+	return 0, fmt.Errorf("Invalid cache eviction policy %q", v)
+}
+
 func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
+	allowlist, err := newTagAllowlist(c.TagAllowlist)
+	if err != nil {
+		return nil, err
+	}
+	dist, err := newDistributor(c.IngestDistribution, c.Destinations)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", err, c.IngestDistribution)
+	}
 	localTags := make([]string, 0, len(c.Tags))
 	for i := range c.Tags {
 		if err := CheckTag(c.Tags[i]); err != nil {
 			return nil, fmt.Errorf("Invalid tag %q %v", c.Tags[i], err)
+		} else if err = allowlist.check(c.Tags[i]); err != nil {
+			return nil, err
 		}
 		localTags = append(localTags, c.Tags[i])
 	}
@@ -278,13 +339,16 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 	var cache *chancacher.ChanCacher
 	var bcache *chancacher.ChanCacher
 
-	var err error
 	if c.CachePath != "" {
-		cache, err = chancacher.NewChanCacher(c.CacheDepth, filepath.Join(c.CachePath, "e"), mb*c.CacheSize)
+		var policy chancacher.OverflowPolicy
+		if policy, err = cacheOverflowPolicy(c.CacheEvictionPolicy); err != nil {
+			return nil, err
+		}
+		cache, err = chancacher.NewChanCacherEx(c.CacheDepth, filepath.Join(c.CachePath, "e"), mb*c.CacheSize, policy)
 		if err != nil {
 			return nil, err
 		}
-		bcache, err = chancacher.NewChanCacher(c.CacheDepth, filepath.Join(c.CachePath, "b"), mb*c.CacheSize)
+		bcache, err = chancacher.NewChanCacherEx(c.CacheDepth, filepath.Join(c.CachePath, "b"), mb*c.CacheSize, policy)
 		if err != nil {
 			return nil, err
 		}
@@ -390,9 +454,12 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 		buff: make([]entry.Entry, 4096),
 	}
 
+	// failover destinations follow the primaries so they share the connection machinery
+	dests := append(append([]Target{}, c.Destinations...), c.FailoverDestinations...)
+
 	return &IngestMuxer{
 		cfg:               getStreamConfig(c.IngestStreamConfig),
-		dests:             c.Destinations,
+		dests:             dests,
 		tags:              taglist,
 		tagMap:            tagMap,
 		pubKey:            c.PublicKey,
@@ -409,9 +476,9 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 		bChan:             bcache.In,
 		bChanOut:          bcache.Out,
 		eq:                newEmergencyQueue(),
-		dieChan:           make(chan bool, len(c.Destinations)),
+		dieChan:           make(chan bool, len(dests)),
 		upChan:            make(chan bool, 1),
-		errChan:           make(chan error, len(c.Destinations)),
+		errChan:           make(chan error, len(dests)),
 		cache:             cache,
 		bcache:            bcache,
 		cacheEnabled:      c.CachePath != "",
@@ -427,6 +494,9 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 		logbuff:           logbuff,
 		attacher:          atch,
 		attachActive:      atch.Active(),
+		failover:          newFailoverSet(len(c.Destinations), len(c.FailoverDestinations), c.FailoverCheckInterval),
+		allowlist:         allowlist,
+		dist:              dist,
 	}, nil
 }
 
@@ -486,7 +556,19 @@ func (im *IngestMuxer) Start() error {
 	im.wg.Add(len(im.dests))
 	im.connDead = int32(len(im.dests))
 	for i := 0; i < len(im.dests); i++ {
-		go im.connRoutine(i)
+		if im.failover.isFailover(i) {
+			go im.failoverRoutine(i)
+		} else {
+			go im.connRoutine(i)
+		}
+	}
+	if im.failover != nil {
+		im.wg.Add(1)
+		go im.failoverSupervisor()
+	}
+	if im.dist != nil {
+		im.wg.Add(1)
+		go im.distributeRoutine()
 	}
 	im.start = time.Now()
 	im.state = running
@@ -522,6 +604,7 @@ func (im *IngestMuxer) Close() error {
 	im.mtx.Lock()
 	defer im.mtx.Unlock()
 
+	im.flushRoutes()
 	close(im.eChan)
 	close(im.bChan)
 
@@ -696,9 +779,12 @@ func (im *IngestMuxer) KnownTags() (tgs []string) {
 // NegotiateTag will attempt to lookup a tag name in the negotiated set
 // The the tag name has not already been negotiated, the muxer will contact
 // each indexer and negotiate it.  This call can potentially block and fail
+// If a tag allowlist is configured, tags that do not match it fail with ErrTagNotAllowed.
 func (im *IngestMuxer) NegotiateTag(name string) (tg entry.EntryTag, err error) {
 	if err = CheckTag(name); err != nil {
 		return
+	} else if err = im.allowlist.check(name); err != nil {
+		return
 	}
 
 	im.mtx.Lock()
@@ -1199,7 +1285,7 @@ func (im *IngestMuxer) shouldSched() bool {
 	return len(im.igst) > 1 && im.cache.BufferSize() == 0 && im.bcache.BufferSize() == 0
 }
 
-func (im *IngestMuxer) writeRelayRoutine(csc chan connSet, connFailure chan bool) {
+func (im *IngestMuxer) writeRelayRoutine(igIdx int, csc chan connSet, connFailure chan bool) {
 	tmr := time.NewTimer(tickerInterval())
 	defer tmr.Stop()
 	defer close(connFailure)
@@ -1216,6 +1302,10 @@ func (im *IngestMuxer) writeRelayRoutine(csc chan connSet, connFailure chan bool
 
 	eC := im.eChanOut
 	bC := im.bChanOut
+	if rE, rB := im.dist.routes(igIdx); rE != nil {
+		//hashed distribution, only take what is routed to us
+		eC, bC = rE, rB
+	}
 
 inputLoop:
 	for {
@@ -1357,18 +1447,26 @@ inputLoop:
 
 // the routine that manages
 func (im *IngestMuxer) connRoutine(igIdx int) {
-	var src net.IP
 	defer im.wg.Done()
+	im.connSession(igIdx, nil)
+}
+
+// connSession connects to the destination at igIdx and relays entries to it, reconnecting as needed.
+// If stop is closed the connection is synced and shut down, and any entries that were not
+// confirmed are recycled to the other connections; a nil stop runs until the muxer closes.
+// Returns true if the destination failed or the muxer is closing.
+func (im *IngestMuxer) connSession(igIdx int, stop <-chan struct{}) (done bool) {
+	var src net.IP
 	if igIdx >= len(im.igst) || igIdx >= len(im.dests) {
 		//this SHOULD NEVER HAPPEN.  Bail
 		im.connFailed(unknownAddr, errors.New("Invalid ingester index on muxer"))
-		return
+		return true
 	}
 	dst := im.dests[igIdx]
 	if im.igst[igIdx] != nil {
 		//this SHOULD NEVER HAPPEN.  Bail
 		im.connFailed(dst.Address, errors.New("Ingester already populated for destination in muxer"))
-		return
+		return true
 	}
 
 	var igst *IngestConnection
@@ -1376,60 +1474,72 @@ func (im *IngestMuxer) connRoutine(igIdx int) {
 	var err error
 	connErrNotif := make(chan bool, 1)
 	ncc := make(chan connSet, 1)
-	defer close(ncc)
 
-	go im.writeRelayRoutine(ncc, connErrNotif)
+	go im.writeRelayRoutine(igIdx, ncc, connErrNotif)
 
 	connErrNotif <- true
 
+	// stopSession shuts down the relay, which syncs and closes the connection, and releases it
+	stopSession := func() {
+		close(ncc)
+		for range connErrNotif {
+		}
+		if igst != nil {
+			im.goDead()
+			im.releaseConn(igIdx, igst, tt)
+		}
+	}
+
 	//loop, trying to grab entries, or dying
 	for {
 		select {
+		case <-stop:
+			stopSession()
+			return false
 		case _, ok := <-connErrNotif:
 			if !ok {
 				//this means that the relay function bailed
+				close(ncc)
 				if igst != nil {
 					igst.Close()
 				}
 				im.goDead()
 				im.connFailed(dst.Address, errors.New("Closed"))
-				return
+				return true
 			}
 
 			if igst != nil {
 				im.Warn("reconnecting", log.KV("indexer", dst.Address), log.KV("ingester", im.name), log.KV("ingesteruuid", im.uuid))
 				igst.Close()
 				im.goDead() //let the world know of our failures
-				im.igst[igIdx] = nil
-				im.tagTranslators[igIdx] = nil
-
-				//pull any entries out of the ingest connection and put them into the emergency queue
-				ents := igst.outstandingEntries()
-				for i := range ents {
-					if ents[i] != nil {
-						ents[i].Tag = tt.Reverse(ents[i].Tag)
-					}
-				}
-				im.recycleEntryBatch(ents)
+				im.releaseConn(igIdx, igst, tt)
+				igst = nil
 			}
 
 			//attempt to get the connection rolling again
-			igst, tt, err = im.getConnection(dst)
+			igst, tt, err = im.getConnection(dst, stop)
 			if err != nil {
+				if isStopped(stop) {
+					stopSession()
+					return false
+				}
+				close(ncc)
 				im.connFailed(dst.Address, err)
-				return //we are done
+				return true //we are done
 			}
 			if igst == nil {
+				close(ncc)
 				im.connFailed(dst.Address, errors.New("Nil connection"))
-				return
+				return true
 			}
 
 			//get the source fired back up
 			src, err = igst.Source()
 			if err != nil {
 				igst.Close()
+				close(ncc)
 				im.connFailed(dst.Address, err)
-				return
+				return true
 			}
 
 			im.mtx.Lock()
@@ -1448,6 +1558,24 @@ func (im *IngestMuxer) connRoutine(igIdx int) {
 	}
 }
 
+// releaseConn clears the connection at igIdx, pulling any entries out of the ingest connection and
+// putting them back into the queues for the remaining connections
+func (im *IngestMuxer) releaseConn(igIdx int, igst *IngestConnection, tt tagTrans) {
+	im.mtx.Lock()
+	im.igst[igIdx] = nil
+	im.tagTranslators[igIdx] = nil
+	im.mtx.Unlock()
+	im.drainRoutes(igIdx)
+
+	ents := igst.outstandingEntries()
+	for i := range ents {
+		if ents[i] != nil {
+			ents[i].Tag = tt.Reverse(ents[i].Tag)
+		}
+	}
+	im.recycleEntryBatch(ents)
+}
+
 func (im *IngestMuxer) recycleEntryBatch(ents []*entry.Entry) {
 	if len(ents) == 0 {
 		return
@@ -1515,11 +1643,14 @@ func isFatalConnError(err error) bool {
 	return false
 }
 
-func (im *IngestMuxer) quitableSleep(dur time.Duration) (quit bool) {
+// quitableSleep sleeps for dur, returning early if the muxer is closing or stop is closed
+func (im *IngestMuxer) quitableSleep(dur time.Duration, stop <-chan struct{}) (quit bool) {
 	select {
 	case _ = <-time.After(dur):
 	case _ = <-im.dieChan:
 		quit = true
+	case <-stop:
+		quit = true
 	}
 	return
 }
@@ -1534,7 +1665,9 @@ func backoff(curr, max time.Duration) time.Duration {
 	return curr
 }
 
-func (im *IngestMuxer) getConnection(tgt Target) (ig *IngestConnection, tt tagTrans, err error) {
+// getConnection connects to the target, retrying until it succeeds, hits a fatal error, the muxer
+// closes, or stop is closed.
+func (im *IngestMuxer) getConnection(tgt Target, stop <-chan struct{}) (ig *IngestConnection, tt tagTrans, err error) {
 	//initialize our retryDuration to zero, first call will set it to the default and then start backing off
 	var retryDuration time.Duration
 loop:
@@ -1565,7 +1698,7 @@ loop:
 				log.KVErr(err))
 			//non-fatal, sleep and continue
 			retryDuration = backoff(retryDuration, maxRetryTime)
-			if im.quitableSleep(retryDuration) {
+			if im.quitableSleep(retryDuration, stop) {
 				//told to exit, just bail
 				return nil, nil, errors.New("Muxer closing")
 			}
@@ -1595,7 +1728,7 @@ loop:
 				log.KVErr(err))
 			//non-fatal, sleep and continue
 			retryDuration = backoff(retryDuration, maxRetryTime)
-			if im.quitableSleep(retryDuration) {
+			if im.quitableSleep(retryDuration, stop) {
 				//told to exit, just bail
 				return nil, nil, errors.New("Muxer closing")
 			}
@@ -1613,7 +1746,7 @@ loop:
 				log.KVErr(lerr))
 			//non-fatal, sleep and continue
 			retryDuration = backoff(retryDuration, maxRetryTime)
-			if im.quitableSleep(retryDuration) {
+			if im.quitableSleep(retryDuration, stop) {
 				//told to exit, just bail
 				return nil, nil, errors.New("Muxer closing")
 			}
@@ -1624,6 +1757,9 @@ loop:
 			select {
 			case _ = <-im.dieChan:
 				return
+			case <-stop:
+				ig.Close()
+				return nil, nil, errors.New("Connection stopped")
 			default:
 			}
 			ok, lerr := ig.IngestOK()
@@ -1637,7 +1773,7 @@ loop:
 				ig.Close()
 				//non-fatal, sleep and continue
 				retryDuration = backoff(retryDuration, maxRetryTime)
-				if im.quitableSleep(retryDuration) {
+				if im.quitableSleep(retryDuration, stop) {
 					//told to exit, just bail
 					return nil, nil, errors.New("Muxer closing")
 				}
@@ -1651,7 +1787,7 @@ loop:
 				log.KV("ingester", im.name),
 				log.KV("version", version.GetVersion()),
 				log.KV("ingesteruuid", im.uuid))
-			im.quitableSleep(10 * time.Second)
+			im.quitableSleep(10*time.Second, stop)
 		}
 
 		if lerr := ig.ew.ConfigureStream(im.cfg); lerr != nil {
@@ -1664,7 +1800,7 @@ loop:
 			ig.Close()
 			//non-fatal, sleep and continue
 			retryDuration = backoff(retryDuration, maxRetryTime)
-			if im.quitableSleep(retryDuration) {
+			if im.quitableSleep(retryDuration, stop) {
 				//told to exit, just bail
 				return nil, nil, errors.New("Muxer closing")
 			}
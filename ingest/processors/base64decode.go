@@ -0,0 +1,171 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	Base64DecodeProcessor string = `base64decode`
+
+	base64Standard string = `standard`
+	base64URL      string = `url`
+)
+
+type Base64DecodeConfig struct {
+	// Encoding selects the alphabet, either "standard" (the default) or "url" for the URL
+	// and filename safe alphabet. Padding is optional for both.
+	Encoding string
+
+	// Regex optionally limits decoding to part of the entry, it must contain exactly one
+	// capture group. The group of every match is decoded and spliced back in place, e.g.:
+	//	Regex=`"data":"([^"]+)"`
+	// Entries which do not match are left unchanged.
+	Regex string
+
+	// On_Error selects what happens to entries which are not valid base64, either
+	// "passthrough" (the default) which leaves them unchanged, or "drop".
+	On_Error string
+}
+
+func Base64DecodeLoadConfig(vc *config.VariableConfig) (c Base64DecodeConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		_, _, err = c.validate()
+	}
+	return
+}
+
+func (c *Base64DecodeConfig) validate() (enc *base64.Encoding, rx *regexp.Regexp, err error) {
+	switch c.Encoding = strings.ToLower(strings.TrimSpace(c.Encoding)); c.Encoding {
+	case ``:
+		c.Encoding = base64Standard
+		fallthrough
+	case base64Standard:
+		enc = base64.RawStdEncoding
+	case base64URL:
+		enc = base64.RawURLEncoding
+	default:
+		err = fmt.Errorf("Encoding %q is invalid, must be %q or %q", c.Encoding, base64Standard, base64URL)
+		return
+	}
+	switch c.On_Error = strings.ToLower(strings.TrimSpace(c.On_Error)); c.On_Error {
+	case ``:
+		c.On_Error = onErrorPassthrough
+	case onErrorPassthrough, onErrorDrop:
+	default:
+		err = fmt.Errorf("Unknown On-Error action %q", c.On_Error)
+		return
+	}
+	if c.Regex != `` {
+		if rx, err = regexp.Compile(c.Regex); err != nil {
+			err = fmt.Errorf("Regex %q is invalid %w", c.Regex, err)
+		} else if rx.NumSubexp() != 1 {
+			err = fmt.Errorf("Regex %q must contain exactly one capture group, found %d", c.Regex, rx.NumSubexp())
+		}
+	}
+	return
+}
+
+// Base64Decoder replaces base64 encoded entry data, or the encoded parts of it selected
+// by a regular expression, with the decoded bytes.
+type Base64Decoder struct {
+	nocloser
+	Base64DecodeConfig
+	enc *base64.Encoding
+	rx  *regexp.Regexp // nil unless Regex is set
+}
+
+func NewBase64Decoder(cfg Base64DecodeConfig) (*Base64Decoder, error) {
+	bd := &Base64Decoder{}
+	if err := bd.Config(cfg); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (bd *Base64Decoder) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(Base64DecodeConfig); ok {
+		var enc *base64.Encoding
+		var rx *regexp.Regexp
+		if enc, rx, err = cfg.validate(); err == nil {
+			bd.Base64DecodeConfig, bd.enc, bd.rx = cfg, enc, rx
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (bd *Base64Decoder) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	if len(ents) == 0 {
+		return nil, nil
+	}
+	rset := ents[:0]
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		var data []byte
+		var err error
+		if bd.rx == nil {
+			data, err = bd.decode(ent.Data)
+		} else {
+			data, err = bd.splice(ent.Data)
+		}
+		if err == nil {
+			ent.Data = data
+		} else if bd.On_Error == onErrorDrop {
+			continue
+		}
+		rset = append(rset, ent)
+	}
+	return rset, nil
+}
+
+// decode decodes a complete base64 value, surrounding whitespace and padding are ignored
+func (bd *Base64Decoder) decode(v []byte) ([]byte, error) {
+	v = bytes.TrimRight(bytes.TrimSpace(v), `=`)
+	r := make([]byte, bd.enc.DecodedLen(len(v)))
+	n, err := bd.enc.Decode(r, v)
+	return r[:n], err
+}
+
+// splice decodes the capture group of every match in place, if any match fails to decode
+// the data is left unchanged
+func (bd *Base64Decoder) splice(data []byte) ([]byte, error) {
+	idxs := bd.rx.FindAllSubmatchIndex(data, -1)
+	if len(idxs) == 0 {
+		return data, nil
+	}
+	r := make([]byte, 0, len(data))
+	var last int
+	for _, idx := range idxs {
+		if idx[2] < 0 {
+			continue //the group did not participate in the match
+		}
+		dec, err := bd.decode(data[idx[2]:idx[3]])
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, data[last:idx[2]]...)
+		r = append(r, dec...)
+		last = idx[3]
+	}
+	return append(r, data[last:]...), nil
+}
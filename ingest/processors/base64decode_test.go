@@ -0,0 +1,101 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestBase64DecodeConfig(t *testing.T) {
+	b := `
+	[preprocessor "b64"]
+		type = base64decode
+		Encoding = URL
+		Regex = "data=(\\S+)"
+		On-Error = drop
+	`
+	p, err := testLoadPreprocessor(b, `b64`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bd, ok := p.(*Base64Decoder)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Base64Decoder", p)
+	}
+	if bd.Encoding != base64URL || bd.On_Error != onErrorDrop || bd.rx == nil {
+		t.Fatalf("bad config: %+v", bd.Base64DecodeConfig)
+	}
+
+	for _, bad := range []string{`Encoding = hex`, `On-Error = explode`, `Regex = "data=\\S+"`, `Regex = "(a)(b)"`, `Regex = "(["`} {
+		b = `
+	[preprocessor "b64"]
+		type = base64decode
+		` + bad + `
+	`
+		if _, err = testLoadPreprocessor(b, `b64`); err == nil {
+			t.Fatalf("failed to catch bad config %q", bad)
+		}
+	}
+}
+
+func TestBase64Decode(t *testing.T) {
+	payload := []byte("{\"msg\":\"hello\xff\xfe\"}")
+	tests := []struct {
+		cfg  Base64DecodeConfig
+		in   string
+		out  string
+		drop bool
+	}{
+		{cfg: Base64DecodeConfig{}, in: base64.StdEncoding.EncodeToString(payload), out: string(payload)},
+		// padding, surrounding whitespace, and wrapped lines are ignored
+		{cfg: Base64DecodeConfig{}, in: " " + base64.RawStdEncoding.EncodeToString(payload) + "\n", out: string(payload)},
+		{cfg: Base64DecodeConfig{}, in: "aGVs\nbG8=\r\n", out: "hello"},
+		{cfg: Base64DecodeConfig{Encoding: `url`}, in: base64.URLEncoding.EncodeToString(payload), out: string(payload)},
+		// the standard alphabet rejects URL safe data and vice versa
+		{cfg: Base64DecodeConfig{}, in: base64.URLEncoding.EncodeToString(payload), out: base64.URLEncoding.EncodeToString(payload)},
+		{cfg: Base64DecodeConfig{On_Error: `drop`}, in: `not base64!`, drop: true},
+		{cfg: Base64DecodeConfig{Encoding: `url`, On_Error: `drop`}, in: base64.StdEncoding.EncodeToString(payload), drop: true},
+		// regex captures are decoded in place
+		{
+			cfg: Base64DecodeConfig{Regex: `"data":"([^"]*)"`},
+			in:  `{"records":[{"data":"aGVsbG8="},{"data":"d29ybGQ"}]}`,
+			out: `{"records":[{"data":"hello"},{"data":"world"}]}`,
+		},
+		{cfg: Base64DecodeConfig{Regex: `data=(\S+)`, On_Error: `drop`}, in: `no match here`, out: `no match here`},
+		// a single bad capture leaves the entry unchanged
+		{
+			cfg: Base64DecodeConfig{Regex: `data=(\S+)`},
+			in:  `data=aGVsbG8= data=!!!`,
+			out: `data=aGVsbG8= data=!!!`,
+		},
+		{cfg: Base64DecodeConfig{Regex: `data=(\S+)`, On_Error: `drop`}, in: `data=aGVsbG8= data=!!!`, drop: true},
+	}
+	for i, tt := range tests {
+		bd, err := NewBase64Decoder(tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ents, err := bd.Process([]*entry.Entry{{Data: []byte(tt.in)}})
+		if err != nil {
+			t.Fatal(err)
+		} else if tt.drop {
+			if len(ents) != 0 {
+				t.Fatalf("%d: entry was not dropped: %q", i, ents[0].Data)
+			}
+			continue
+		} else if len(ents) != 1 {
+			t.Fatalf("%d: bad count %d", i, len(ents))
+		} else if string(ents[0].Data) != tt.out {
+			t.Fatalf("%d: bad output %q != %q", i, ents[0].Data, tt.out)
+		}
+	}
+}
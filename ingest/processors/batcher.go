@@ -0,0 +1,217 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	defaultBatchMaxCount   = 512
+	defaultBatchMaxLatency = time.Second
+)
+
+var (
+	ErrBatcherClosed = errors.New("Batcher is closed")
+	ErrNilBatchSink  = errors.New("Batcher requires a sink")
+)
+
+// BatchSink consumes a batch of entries emitted by a Batcher.
+// ProcessorSet.ProcessBatch and the WriteBatch method of an ingest muxer are both usable as a
+// BatchSink. The sink owns the batch once called.
+type BatchSink func([]*entry.Entry) error
+
+// BatcherConfig controls when a Batcher flushes.
+// A zero Max-Count or an empty Max-Latency selects the default.
+type BatcherConfig struct {
+	Max_Count   int    // flush once this many entries are buffered
+	Max_Latency string // flush once the oldest buffered entry has waited this long
+}
+
+func (c BatcherConfig) maxCount() (int, error) {
+	if c.Max_Count == 0 {
+		return defaultBatchMaxCount, nil
+	} else if c.Max_Count < 0 {
+		return 0, fmt.Errorf("Invalid Max-Count %d", c.Max_Count)
+	}
+	return c.Max_Count, nil
+}
+
+func (c BatcherConfig) maxLatency() (d time.Duration, err error) {
+	if c.Max_Latency == `` {
+		return defaultBatchMaxLatency, nil
+	}
+	if d, err = time.ParseDuration(c.Max_Latency); err != nil {
+		err = fmt.Errorf("Invalid Max-Latency %q: %v", c.Max_Latency, err)
+	} else if d <= 0 {
+		err = fmt.Errorf("Invalid Max-Latency %q: must be positive", c.Max_Latency)
+	}
+	return
+}
+
+// Batcher accumulates entries and hands them to a sink in batches, flushing whenever Max-Count
+// entries are buffered or the oldest buffered entry is Max-Latency old.
+// The sink is called synchronously, so a slow sink blocks the producer rather than letting the
+// buffer grow without bound.
+//
+// Add and AddBatch are intended for a single producer goroutine; the latency timer flushes
+// concurrently but is synchronized with the producer. Errors from timer-driven flushes are
+// returned by the next call to Add, AddBatch, or Flush.
+type Batcher struct {
+	mtx        sync.Mutex
+	sink       BatchSink
+	maxCount   int
+	maxLatency time.Duration
+	buff       []*entry.Entry
+	tmr        *time.Timer // armed while the buffer is not empty
+	err        error       // error from the last timer-driven flush
+	closed     bool
+}
+
+// NewBatcher creates a Batcher that delivers batches to the given sink.
+func NewBatcher(cfg BatcherConfig, sink BatchSink) (*Batcher, error) {
+	if sink == nil {
+		return nil, ErrNilBatchSink
+	}
+	mc, err := cfg.maxCount()
+	if err != nil {
+		return nil, err
+	}
+	ml, err := cfg.maxLatency()
+	if err != nil {
+		return nil, err
+	}
+	return &Batcher{
+		sink:       sink,
+		maxCount:   mc,
+		maxLatency: ml,
+		buff:       make([]*entry.Entry, 0, mc),
+	}, nil
+}
+
+// ProcessorSink returns a BatchSink that runs batches through the given processor and passes the
+// results on to the next sink.
+func ProcessorSink(p Processor, next BatchSink) BatchSink {
+	return func(ents []*entry.Entry) error {
+		set, err := p.Process(ents)
+		if err != nil {
+			return err
+		} else if len(set) == 0 {
+			return nil
+		}
+		return next(set)
+	}
+}
+
+// Add buffers a single entry, flushing if the buffer is full.
+func (b *Batcher) Add(ent *entry.Entry) error {
+	if ent == nil {
+		return ErrInvalidEntry
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if err := b.ready(); err != nil {
+		return err
+	}
+	return b.add(ent)
+}
+
+// AddBatch buffers a set of entries, flushing each time the buffer fills.
+func (b *Batcher) AddBatch(ents []*entry.Entry) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if err := b.ready(); err != nil {
+		return err
+	}
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		if err := b.add(ent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush immediately delivers any buffered entries to the sink.
+func (b *Batcher) Flush() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	err := b.err
+	b.err = nil
+	return addError(b.flush(), err)
+}
+
+// Close flushes any buffered entries and stops the latency timer.
+// Subsequent calls to Add or AddBatch return ErrBatcherClosed.
+func (b *Batcher) Close() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	err := b.err
+	b.err = nil
+	return addError(b.flush(), err)
+}
+
+// ready returns an error if entries cannot be added, the caller must hold the lock
+func (b *Batcher) ready() (err error) {
+	if b.closed {
+		return ErrBatcherClosed
+	}
+	err, b.err = b.err, nil
+	return
+}
+
+// add appends an entry, arming the timer on the first entry and flushing when full.
+// The caller must hold the lock.
+func (b *Batcher) add(ent *entry.Entry) error {
+	b.buff = append(b.buff, ent)
+	if len(b.buff) >= b.maxCount {
+		return b.flush()
+	} else if len(b.buff) == 1 {
+		if b.tmr == nil {
+			b.tmr = time.AfterFunc(b.maxLatency, b.timedFlush)
+		} else {
+			b.tmr.Reset(b.maxLatency)
+		}
+	}
+	return nil
+}
+
+// flush hands the buffer to the sink, the caller must hold the lock
+func (b *Batcher) flush() error {
+	if b.tmr != nil {
+		b.tmr.Stop()
+	}
+	if len(b.buff) == 0 {
+		return nil
+	}
+	set := b.buff
+	b.buff = make([]*entry.Entry, 0, b.maxCount)
+	return b.sink(set)
+}
+
+// timedFlush is fired by the latency timer
+func (b *Batcher) timedFlush() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	// a timer firing as the buffer is flushed elsewhere may find nothing to do
+	if err := b.flush(); err != nil {
+		b.err = addError(err, b.err)
+	}
+}
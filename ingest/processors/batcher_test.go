@@ -0,0 +1,146 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+type batchCollector struct {
+	sync.Mutex
+	batches [][]*entry.Entry
+	err     error
+}
+
+func (bc *batchCollector) sink(ents []*entry.Entry) error {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.batches = append(bc.batches, ents)
+	return bc.err
+}
+
+func (bc *batchCollector) sizes() (r []int) {
+	bc.Lock()
+	defer bc.Unlock()
+	for _, b := range bc.batches {
+		r = append(r, len(b))
+	}
+	return
+}
+
+func TestBatcherConfig(t *testing.T) {
+	var bc batchCollector
+	b, err := NewBatcher(BatcherConfig{}, bc.sink)
+	if err != nil {
+		t.Fatal(err)
+	} else if b.maxCount != defaultBatchMaxCount || b.maxLatency != defaultBatchMaxLatency {
+		t.Fatalf("bad defaults: %d %v", b.maxCount, b.maxLatency)
+	}
+	for _, bad := range []BatcherConfig{{Max_Count: -1}, {Max_Latency: `foo`}, {Max_Latency: `-1s`}} {
+		if _, err = NewBatcher(bad, bc.sink); err == nil {
+			t.Fatalf("%+v was accepted", bad)
+		}
+	}
+	if _, err = NewBatcher(BatcherConfig{}, nil); err != ErrNilBatchSink {
+		t.Fatalf("nil sink was accepted: %v", err)
+	}
+}
+
+func TestBatcherMaxCount(t *testing.T) {
+	var bc batchCollector
+	b, err := NewBatcher(BatcherConfig{Max_Count: 3, Max_Latency: `1h`}, bc.sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if err = b.Add(&entry.Entry{Data: []byte("foo")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = b.AddBatch([]*entry.Entry{{}, {}, {}}); err != nil {
+		t.Fatal(err)
+	}
+	if s := bc.sizes(); len(s) != 2 || s[0] != 3 || s[1] != 3 {
+		t.Fatalf("bad batches: %v", s)
+	}
+	if err = b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if s := bc.sizes(); len(s) != 3 || s[2] != 1 {
+		t.Fatalf("bad batches after flush: %v", s)
+	}
+	// flushing nothing does not call the sink
+	if err = b.Close(); err != nil {
+		t.Fatal(err)
+	} else if s := bc.sizes(); len(s) != 3 {
+		t.Fatalf("empty flush reached the sink: %v", s)
+	}
+	if err = b.Add(&entry.Entry{}); err != ErrBatcherClosed {
+		t.Fatalf("add after close: %v", err)
+	}
+}
+
+func TestBatcherMaxLatency(t *testing.T) {
+	var bc batchCollector
+	b, err := NewBatcher(BatcherConfig{Max_Count: 100, Max_Latency: `20ms`}, bc.sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if err = b.Add(&entry.Entry{}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(bc.sizes()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered entry was never flushed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s := bc.sizes(); len(s) != 1 || s[0] != 1 {
+		t.Fatalf("bad batches: %v", s)
+	}
+}
+
+func TestBatcherTimedError(t *testing.T) {
+	bc := batchCollector{err: errors.New("sink failed")}
+	b, err := NewBatcher(BatcherConfig{Max_Count: 100, Max_Latency: `10ms`}, bc.sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Add(&entry.Entry{}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	// the error from the timed flush is handed to the producer exactly once
+	if err = b.Flush(); err == nil {
+		t.Fatal("timed flush error was lost")
+	} else if err = b.Flush(); err != nil {
+		t.Fatalf("error was reported twice: %v", err)
+	}
+}
+
+func TestProcessorSink(t *testing.T) {
+	var bc batchCollector
+	d, err := NewDrop(DropConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := ProcessorSink(d, bc.sink)
+	if err = sink([]*entry.Entry{{}}); err != nil {
+		t.Fatal(err)
+	} else if s := bc.sizes(); len(s) != 0 {
+		t.Fatalf("dropped entries reached the sink: %v", s)
+	}
+}
@@ -14,22 +14,44 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gravwell/gravwell/v3/ingest"
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
-	"github.com/gravwell/gravwell/v3/timegrinder"
+	"github.com/gravwell/jsonparser"
 )
 
 const (
 	CorelightProcessor = `corelight`
-)
 
-var (
-	defaultTag    string
-	defaultPrefix = "zeek"
+	corelightFormatTSV  = `tsv`
+	corelightFormatJSON = `json`
+
+	// noTag is returned by the conversion functions when an entry could not be converted
+	noTag = ``
+
+	// defaults are constant so that every processor instance is independent, per-instance
+	// state belongs in CorelightConfig or Corelight
+	defaultPrefix           = "zeek"
+	defaultFieldSeparator   = "\t"
+	defaultEmptyFieldMarker = "-"
+	defaultSetSeparator     = ","
+	defaultFloatPrecision   = 5
+	defaultTSPrecision      = 6
+	defaultPathField        = "_path"
+	defaultTSField          = "ts"
+	corelightWriteTSField   = "_write_ts"
+	maxFloatPrecision       = 15
+
+	// CorelightNoDecimals is the Float_Precision of a config built in code which emits fractional
+	// numbers without decimals, an unset Float_Precision of zero gets the default instead.
+	CorelightNoDecimals = -1
 )
 
 type CorelightConfig struct {
@@ -41,21 +63,224 @@ type CorelightConfig struct {
 
 	// Custom_Format specifies a custom override for a path value and headers, there can be many
 	Custom_Format []string
+
+	// Field_Separator specifies the separator placed between TSV fields, it defaults
+	// to a tab. Occurrences of the separator within field values are replaced with a space.
+	Field_Separator string
+
+	// Empty_Field_Marker specifies the value emitted for missing fields, it defaults to "-".
+	Empty_Field_Marker string
+
+	// Set_Separator specifies the separator placed between elements of set and vector
+	// fields such as DNS answers, it defaults to ",".
+	Set_Separator string
+
+	// Format specifies the output format, either "tsv" (the default) or "json".
+	// In JSON mode entries are still tagged and timestamped by log type but
+	// the original JSON is left untouched, unless Inject_Logtype_Field is set.
+	Format string
+
+	// Append_Unknown_Fields appends a final column containing any fields that are
+	// not part of the log type's headers as sorted, space delimited key=value pairs.
+	// Nested objects are written as compact JSON, and values which are empty or contain
+	// spaces, equals signs, or quotes are double quoted so the pairs can be split again.
+	Append_Unknown_Fields bool
+
+	// Overflow_Column appends a final column holding every field that is not part of the
+	// log type's headers as a single compact JSON object, so that nothing is lost while the
+	// column count stays fixed. TSV format only, it requires a Field-Separator made of control
+	// characters such as the default tab and may not be combined with Append_Unknown_Fields.
+	Overflow_Column bool
+
+	// Path_Field specifies the field containing the log type, it defaults to "_path".
+	// Nested fields may be specified using dotted notation, e.g. "@metadata.path".
+	Path_Field string
+
+	// Normalize_Path lowercases and trims the log type before it is prefixed, so that
+	// "SSL" and "ssl" both resolve to the same tag. Custom format names are normalized
+	// the same way. Normalized log types must still match a known format.
+	Normalize_Path bool
+
+	// TS_Field specifies the field containing the timestamp, it defaults to "ts".
+	TS_Field string
+
+	// Case_Insensitive_Fields matches record keys against the headers, Path_Field, TS_Field,
+	// and Source_From_Field regardless of case, for intermediaries that rewrite keys such as
+	// "Ts" or "Id.orig_h". When keys differ only by case the first one in the record wins.
+	// Records are always fully decoded in this mode, which is slower.
+	Case_Insensitive_Fields bool
+
+	// Float_Precision specifies the number of decimal digits used when emitting
+	// fractional numbers, it defaults to 5. See CorelightNoDecimals.
+	Float_Precision int
+
+	// Field_Float_Precision overrides Float_Precision for specific fields, there can be many, e.g.:
+	//	Field-Float-Precision="remote_location.destination_latitude:6"
+	Field_Float_Precision []string
+
+	// Timestamp_Precision specifies the number of decimal digits of the leading epoch timestamp
+	// column: 3 (milliseconds), 6 (microseconds, the default), or 9 (nanoseconds).
+	Timestamp_Precision int
+
+	// Default_Tag optionally specifies a tag applied to entries that could not be
+	// converted, such as non-Zeek or unparseable records. By default they keep the
+	// tag assigned by the ingester.
+	Default_Tag string
+
+	// Error_Tag is the original name for Default_Tag, only one of the two may be set.
+	Error_Tag string
+
+	// Inject_Logtype_Field optionally names a key which is added to each entry holding
+	// the log type (the _path value), so searches can facet on it. JSON format only.
+	// The key is not added if the entry already contains it.
+	Inject_Logtype_Field string
+
+	// Tag_Remap renames resolved tags before they are negotiated, there can be many, e.g.:
+	//	Tag-Remap="zeekconn=myconn"
+	// Multiple tags may be remapped to the same name to collapse them.
+	Tag_Remap []string
+
+	// GeoIP_Database optionally specifies the path to a MaxMind mmdb database. When set,
+	// log types carrying id.orig_h and id.resp_h get orig_cc, resp_cc, orig_asn, and
+	// resp_asn columns appended. TSV format only.
+	GeoIP_Database string
+
+	// Enable_Logtypes optionally restricts the processor to the listed log types, e.g.:
+	//	Enable-Logtypes=conn
+	//	Enable-Logtypes=dns
+	// Only the tags for enabled log types are negotiated; entries of any other log type
+	// pass through unconverted, receiving Default_Tag if it is set. Disable_Logtypes instead
+	// excludes the listed log types.
+	// The two are mutually exclusive and may name built-in or custom log types.
+	Enable_Logtypes  []string
+	Disable_Logtypes []string
+
+	// Max_Entry_Size optionally specifies the largest entry, in bytes, which will be parsed.
+	// Larger entries bypass parsing entirely and pass through unconverted, receiving Default_Tag
+	// if it is set. Zero disables the limit.
+	Max_Entry_Size uint64
+
+	// Source_From_Field optionally names a field identifying the sensor which produced the
+	// record, e.g. "_system_name". If the value is an IP address it becomes the entry source,
+	// otherwise it is attached to the entry as an enumerated value of the same name.
+	// Records without the field keep their existing source.
+	Source_From_Field string
+
+	// Use_Write_TS timestamps entries with the sensor's "_write_ts" field rather than TS_Field.
+	// The TSV ts column is unchanged, and records without a valid _write_ts fall back to TS_Field.
+	Use_Write_TS bool
+
+	// Max_Timestamp_Skew optionally bounds how far an entry timestamp may be from the current
+	// time, e.g. "24h". Entries outside the bound are handled according to On_Skew.
+	Max_Timestamp_Skew string
+
+	// On_Skew selects what happens to entries outside Max_Timestamp_Skew: "clamp" (the default)
+	// timestamps them with the current time, "drop" removes them.
+	On_Skew string
+
+	// Validate_Schema checks each emitted TSV line against its log type, the column count must
+	// match the headers and the uid and id.orig_h columns must not be empty where the log type
+	// has them. Records failing validation keep their original data and are ingested under
+	// Quarantine_Tag, which is required. TSV format only.
+	Validate_Schema bool
+	Quarantine_Tag  string
+}
+
+// CorelightStats contains counters of the entries handled by a Corelight processor.
+type CorelightStats struct {
+	Processed uint64 // total entries handed to the processor
+	Converted uint64 // entries that were successfully retagged
+	Failed    uint64 // entries that could not be converted
+	Disabled  uint64 // entries of a disabled log type, which pass through unconverted
+	Oversized uint64 // entries larger than Max_Entry_Size, which pass through unconverted
+	Clamped   uint64 // entries whose timestamp was outside Max_Timestamp_Skew and set to now
+	Dropped   uint64 // entries whose timestamp was outside Max_Timestamp_Skew and were dropped
+
+	Quarantined uint64                          // entries which failed Validate_Schema
+	Schema      map[string]CorelightSchemaStats // Validate_Schema results by log type, only log types seen are present
+
+	Tags map[string]CorelightTagStats // converted entries by the tag they were ingested under, only tags seen are present
+}
+
+// CorelightTagStats counts the converted entries sent to a single tag.
+type CorelightTagStats struct {
+	Entries uint64
+	Bytes   uint64 // size of the entry data after conversion
+}
+
+// corelightCounter counts the converted entries of a tag, log types remapped to the
+// same tag share a counter
+type corelightCounter struct {
+	name    string // tag name after Tag_Remap
+	entries atomic.Uint64
+	bytes   atomic.Uint64
+}
+
+// CorelightSchemaStats counts the Validate_Schema results of a single log type.
+type CorelightSchemaStats struct {
+	Validated   uint64 // entries which passed validation
+	Quarantined uint64 // entries which failed validation and were sent to Quarantine_Tag
 }
 
 // A Corelight processor takes JSON-formatted Corelight logs and reformats
-// them as TSV, matching the standard Zeek log types.
+// them as TSV, matching the standard Zeek log types. When configured with
+// the JSON format it only retags and timestamps the entries.
 type Corelight struct {
 	nocloser
-	timegrind *timegrinder.TimeGrinder
+	te        *TimeExtractor
 	tg        Tagger
 	tagFields map[string][]string
 	tags      map[string]entry.EntryTag
+	defTag    entry.EntryTag // applied to unconverted entries when retag is set
+	retag     bool
+	fieldPrec map[string]int
+	geo       geoLookup // nil unless GeoIP_Database is set
+	processed atomic.Uint64
+	converted atomic.Uint64
+	failed    atomic.Uint64
+	disabled  atomic.Uint64
+	oversized atomic.Uint64
+	skip      map[string]bool             // resolved tag names of disabled log types
+	ciNames   map[string]string           // lowercased to canonical field names, nil unless Case_Insensitive_Fields is set
+	schema    map[string]*corelightSchema // resolved tag names to their expected layout, nil unless Validate_Schema is set
+	quarTag   entry.EntryTag
+	counters  map[string]*corelightCounter // resolved tag names to the counter of their final tag
+
+	// streaming conversion, see initStream
+	stream      bool
+	fieldSep    []byte
+	metaPaths   [][]string
+	streamPaths map[string][][]string
+	writeTSIdx  int // index of the _write_ts path in metaPaths, -1 if unused
+	srcIdx      int // index of the Source_From_Field path in metaPaths, -1 if unused
+
 	CorelightConfig
 }
 
 func CorelightLoadConfig(vc *config.VariableConfig) (c CorelightConfig, err error) {
-	if err = vc.MapTo(&c); err != nil {
+	c.Field_Separator = defaultFieldSeparator
+	c.Empty_Field_Marker = defaultEmptyFieldMarker
+	c.Set_Separator = defaultSetSeparator
+	c.Timestamp_Precision = defaultTSPrecision
+	if err = mapToStrict(vc, &c); err != nil {
+		return
+	}
+	//an explicit zero must not be mistaken for an unset precision by Validate
+	if c.Float_Precision < 0 {
+		err = fmt.Errorf("Float-Precision %d is invalid, must be between 0 and %d", c.Float_Precision, maxFloatPrecision)
+		return
+	}
+	for _, n := range vc.Names() {
+		if strings.EqualFold(n, `Float-Precision`) && c.Float_Precision == 0 {
+			c.Float_Precision = CorelightNoDecimals
+		}
+	}
+	//the defaults were set above, so an empty separator here was given explicitly
+	if c.Field_Separator == `` {
+		err = errors.New("Field-Separator may not be empty")
+		return
+	} else if c.Set_Separator == `` {
+		err = errors.New("Set-Separator may not be empty")
 		return
 	}
 	err = c.Validate()
@@ -63,13 +288,7 @@ func CorelightLoadConfig(vc *config.VariableConfig) (c CorelightConfig, err erro
 }
 
 func NewCorelight(cfg CorelightConfig, tagger Tagger) (*Corelight, error) {
-	tcfg := timegrinder.Config{}
-	timegrind, err := timegrinder.NewTimeGrinder(tcfg)
-	if err != nil {
-		return nil, err
-	}
 	rr := &Corelight{
-		timegrind:       timegrind,
 		CorelightConfig: cfg,
 		tg:              tagger,
 	}
@@ -106,63 +325,327 @@ func (c *Corelight) init(cfg CorelightConfig, tagger Tagger) (err error) {
 	if err = cfg.Validate(); err != nil {
 		return
 	}
+	c.CorelightConfig = cfg
 	if s, err := loadCustomFormats(cfg.Custom_Format); err != nil {
 		return err
 	} else {
 		specs = append(specs, s...)
 	}
+	var remap map[string]string
+	if remap, err = loadTagRemap(cfg.Tag_Remap); err != nil {
+		return
+	}
+	var enabled func(string) bool
+	if enabled, err = loadLogtypeFilter(cfg.Enable_Logtypes, cfg.Disable_Logtypes, specs); err != nil {
+		return
+	}
 	c.tagFields = make(map[string][]string, len(tagHeaders))
 	c.tags = make(map[string]entry.EntryTag)
+	c.skip = make(map[string]bool)
+	c.counters = make(map[string]*corelightCounter)
+	byName := map[string]*corelightCounter{}
 	for _, spec := range specs {
-		tagName := c.Prefix + spec.prefix
+		tagName := c.pathTag(spec.prefix)
+		if !enabled(spec.prefix) {
+			c.skip[tagName] = true
+			continue
+		}
+		if _, ok := c.tagFields[tagName]; ok {
+			c.warnf("corelight custom format %q overrides built-in format", spec.prefix)
+		}
+		// entries are still looked up by the resolved tag, but negotiated under the remapped name
+		finalName := tagName
+		if v, ok := remap[tagName]; ok {
+			finalName = v
+		}
 		var tv entry.EntryTag
-		if tv, err = c.tg.NegotiateTag(tagName); err != nil {
+		if tv, err = c.tg.NegotiateTag(finalName); err != nil {
 			return
 		}
 		c.tags[tagName] = tv
 		c.tagFields[tagName] = spec.headers
+		if cnt, ok := byName[finalName]; ok {
+			c.counters[tagName] = cnt
+		} else {
+			c.counters[tagName] = &corelightCounter{name: finalName}
+			byName[finalName] = c.counters[tagName]
+		}
+	}
+	for k := range remap {
+		if _, ok := c.tags[k]; !ok && !c.skip[k] {
+			c.warnf("corelight Tag-Remap %q does not match any log type", k)
+		}
+	}
+	if c.fieldPrec, err = loadFloatPrecisions(cfg.Field_Float_Precision); err != nil {
+		return
+	}
+	c.ciNames = nil
+	if cfg.Case_Insensitive_Fields {
+		c.ciNames = caseIndex(specs, cfg.Path_Field, cfg.TS_Field, corelightWriteTSField, cfg.Source_From_Field)
+	}
+	c.retag = false
+	if name := cfg.defaultTag(); name != `` {
+		if c.defTag, err = c.tg.NegotiateTag(name); err != nil {
+			return
+		}
+		c.retag = true
+	}
+	c.geo = nil
+	if cfg.GeoIP_Database != `` {
+		if c.geo, err = openGeoDB(cfg.GeoIP_Database); err != nil {
+			return
+		}
+	}
+	if c.te, err = NewTimeExtractor(TimeExtractorConfig{
+		Field:   cfg.TS_Field,
+		MaxSkew: cfg.Max_Timestamp_Skew,
+		OnSkew:  cfg.On_Skew,
+	}); err != nil {
+		return
+	}
+	c.schema = nil
+	if cfg.Validate_Schema {
+		if c.quarTag, err = c.tg.NegotiateTag(cfg.Quarantine_Tag); err != nil {
+			return
+		}
+		c.initSchema()
+	}
+	c.initStream()
+
+	return
+}
+
+// Stats returns the current entry counters for the processor, it is safe to call
+// concurrently with Process.
+func (c *Corelight) Stats() CorelightStats {
+	clamped, dropped := c.te.Stats()
+	quarantined, schema := c.schemaStats()
+	return CorelightStats{
+		Processed: c.processed.Load(),
+		Converted: c.converted.Load(),
+		Failed:    c.failed.Load(),
+		Disabled:  c.disabled.Load(),
+		Oversized: c.oversized.Load(),
+		Clamped:   clamped,
+		Dropped:   dropped,
+
+		Quarantined: quarantined,
+		Schema:      schema,
+
+		Tags: c.tagStats(),
 	}
+}
+
+// corelightSchema is the layout Validate_Schema expects of a log type's TSV lines
+type corelightSchema struct {
+	columns     int
+	required    []int // indexes of the columns which may not be empty
+	validated   atomic.Uint64
+	quarantined atomic.Uint64
+}
 
+// corelightRequiredFields are the key fields which must be populated when a log type has them
+var corelightRequiredFields = []string{`uid`, corelightOrigAddr}
+
+// initSchema computes the expected layout of every enabled log type
+func (c *Corelight) initSchema() {
+	c.schema = make(map[string]*corelightSchema, len(c.tagFields))
+	for tag, headers := range c.tagFields {
+		cs := &corelightSchema{columns: len(headers)}
+		if c.geo != nil && hasGeoFields(headers) {
+			cs.columns += 4
+		}
+		if c.Append_Unknown_Fields || c.Overflow_Column {
+			cs.columns++
+		}
+		for i, h := range headers {
+			for _, r := range corelightRequiredFields {
+				if h == r {
+					cs.required = append(cs.required, i)
+				}
+			}
+		}
+		c.schema[tag] = cs
+	}
+}
+
+// validSchema reports whether an emitted line matches the layout of its log type, updating its counters
+func (c *Corelight) validSchema(tag string, line []byte) bool {
+	cs, ok := c.schema[tag]
+	if !ok {
+		return true
+	} else if c.matchSchema(cs, line) {
+		cs.validated.Add(1)
+		return true
+	}
+	cs.quarantined.Add(1)
+	return false
+}
+
+func (c *Corelight) matchSchema(cs *corelightSchema, line []byte) bool {
+	cols := bytes.Split(line, []byte(c.Field_Separator))
+	if len(cols) != cs.columns {
+		return false
+	}
+	for _, i := range cs.required {
+		if v := bytes.TrimSpace(cols[i]); len(v) == 0 || string(v) == c.Empty_Field_Marker {
+			return false
+		}
+	}
+	return true
+}
+
+// schemaStats returns the Validate_Schema counters of every log type which has been seen
+func (c *Corelight) schemaStats() (total uint64, mp map[string]CorelightSchemaStats) {
+	if c.schema == nil {
+		return
+	}
+	mp = map[string]CorelightSchemaStats{}
+	for tag, cs := range c.schema {
+		ss := CorelightSchemaStats{Validated: cs.validated.Load(), Quarantined: cs.quarantined.Load()}
+		if ss.Validated > 0 || ss.Quarantined > 0 {
+			mp[strings.TrimPrefix(tag, c.Prefix)] = ss
+			total += ss.Quarantined
+		}
+	}
 	return
 }
 
+// tagStats collects the per tag counters, tags without any entries are omitted
+func (c *Corelight) tagStats() map[string]CorelightTagStats {
+	mp := map[string]CorelightTagStats{}
+	for _, cnt := range c.counters {
+		if ts := (CorelightTagStats{Entries: cnt.entries.Load(), Bytes: cnt.bytes.Load()}); ts.Entries > 0 {
+			mp[cnt.name] = ts // shared counters are visited once per log type, with the same values
+		}
+	}
+	return mp
+}
+
+// warnf logs a warning if the tagger we were handed is also capable of logging,
+// which is the case when the tagger is an ingest muxer.
+func (c *Corelight) warnf(format string, args ...interface{}) {
+	if lg, ok := c.tg.(ingest.IngestLogger); ok && lg != nil {
+		lg.Warnf(format, args...)
+	}
+}
+
 func (c *Corelight) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
 	if len(ents) == 0 {
 		return ents, nil
 	}
+	// entries dropped for timestamp skew are compacted out in place
+	out := ents[:0]
 	for _, ent := range ents {
 		if ent == nil || len(ent.Data) == 0 {
+			out = append(out, ent)
 			continue
-		} else if tag, ts, line := c.processLine(ent.Data); tag != defaultTag {
+		}
+		c.processed.Add(1)
+		if c.Max_Entry_Size > 0 && uint64(len(ent.Data)) > c.Max_Entry_Size {
+			c.oversized.Add(1)
+			out = append(out, c.passThrough(ent))
+			continue
+		}
+		if tag, ts, line, meta := c.processLine(ent.Data); tag != noTag {
+			if c.skip[tag] {
+				c.disabled.Add(1)
+				out = append(out, c.passThrough(ent))
+				continue
+			}
 			// If processLine comes up with a different tag, it means it parsed JSON into
 			// TSV, so let's rewrite the entry.
 			if tv, ok := c.tags[tag]; ok {
-				ent.Tag = tv
+				// the skew bound applies to the timestamp the entry is finally given
+				if !meta.writeTs.IsZero() {
+					ts = meta.writeTs
+				}
+				var keep bool
+				if ts, keep = c.te.Check(ts); !keep {
+					continue
+				}
 				ent.TS = entry.FromStandard(ts)
-				ent.Data = line
+				c.annotate(ent, meta)
+				if c.schema != nil && !c.validSchema(tag, line) {
+					// quarantined records keep their original data so the drift can be inspected
+					ent.Tag = c.quarTag
+					out = append(out, ent)
+					continue
+				}
+				ent.Tag = tv
+				if c.Format != corelightFormatJSON || c.Inject_Logtype_Field != `` {
+					ent.Data = line
+				}
+				c.converted.Add(1)
+				if cnt := c.counters[tag]; cnt != nil {
+					cnt.entries.Add(1)
+					cnt.bytes.Add(uint64(len(ent.Data)))
+				}
+				out = append(out, ent)
+				continue
 			}
 		}
+		c.failed.Add(1)
+		out = append(out, c.passThrough(ent))
+	}
+	return out, nil
+}
+
+// passThrough readies an entry that is not converted, whether it is oversized, of a disabled
+// log type, or could not be parsed, by applying the default tag if one is set
+func (c *Corelight) passThrough(ent *entry.Entry) *entry.Entry {
+	if c.retag {
+		ent.Tag = c.defTag
+	}
+	return ent
+}
+
+// corelightMeta is the sensor attribution pulled out of a record alongside its log type
+type corelightMeta struct {
+	writeTs time.Time // zero unless Use_Write_TS is set and the record has a valid _write_ts
+	src     string    // value of Source_From_Field, empty if absent
+}
+
+// annotate applies the sensor attribution of a converted record to its entry
+func (c *Corelight) annotate(ent *entry.Entry, meta corelightMeta) {
+	if meta.src == `` {
+		return
+	} else if ip := net.ParseIP(meta.src); ip != nil {
+		ent.SRC = ip
+	} else if err := ent.AddEnumeratedValueEx(c.Source_From_Field, meta.src); err != nil {
+		c.warnf("corelight failed to attach %s: %v", c.Source_From_Field, err)
 	}
-	return ents, nil
 }
 
 // processLine attempts to parse out the corelight JSON, figure out
 // the log type (conn, dns, dhcp, weird, etc.), and convert the entry to TSV format.
-// If it succeeds, it returns the destination tag, a new timestamp, and the log entry in TSV format
-func (c *Corelight) processLine(s []byte) (tag string, ts time.Time, line []byte) {
-	mp := map[string]interface{}{}
+// If it succeeds, it returns the destination tag, a new timestamp, the log entry in TSV format,
+// and any sensor attribution for the entry.
+func (c *Corelight) processLine(s []byte) (tag string, ts time.Time, line []byte, meta corelightMeta) {
 	line = s
 	if idx := bytes.IndexByte(line, '{'); idx == -1 {
-		tag = defaultTag
+		tag = noTag
 		return
 	} else {
 		line = line[idx:]
 	}
+	if c.stream && c.geo == nil {
+		if tag, ts, sl, meta, ok := c.processStream(line); ok {
+			return tag, ts, sl, meta
+		}
+	}
+	mp := map[string]interface{}{}
 	if err := json.Unmarshal(line, &mp); err != nil {
-		tag = defaultTag
+		tag = noTag
 		return
 	}
+	if c.ciNames != nil {
+		mp = c.foldKeys(mp, line)
+	}
 	tag, ts, line = c.process(mp, line)
+	if tag != noTag {
+		meta = c.getMeta(mp)
+	}
 	return
 }
 
@@ -170,80 +653,393 @@ func (c *Corelight) process(mp map[string]interface{}, og []byte) (tag string, t
 	var ok bool
 	var headers []string
 	if len(mp) == 0 {
-		tag = defaultTag
+		tag = noTag
 		line = og
 	} else if tag, ts, ok = c.getTagTs(mp); !ok {
-		tag = defaultTag
+		tag = noTag
 		line = og
+	} else if c.skip[tag] {
+		line = og // disabled log types are left as is
 	} else if headers, ok = c.tagFields[tag]; !ok {
-		tag = defaultTag
+		tag = noTag
 		line = og
-	} else if line, ok = emitLine(ts, headers, mp); !ok {
-		tag = defaultTag
+	} else if c.Format == corelightFormatJSON {
+		line = c.injectLogtype(mp, og, strings.TrimPrefix(tag, c.Prefix))
+	} else if line, ok = c.emitLine(ts, headers, mp); !ok {
+		tag = noTag
 		line = og
 	}
 
 	return
 }
 
+// injectLogtype adds the log type to the original JSON under the Inject_Logtype_Field key.
+// The original is re-decoded preserving numbers as is, and re-encoded with sorted keys
+// so the output is deterministic. If the key is already present the original is returned.
+func (c *Corelight) injectLogtype(mp map[string]interface{}, og []byte, logtype string) []byte {
+	if c.Inject_Logtype_Field == `` {
+		return og
+	} else if _, ok := mp[c.Inject_Logtype_Field]; ok {
+		return og
+	}
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(og))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return og
+	}
+	obj[c.Inject_Logtype_Field] = logtype
+	bb := bytes.NewBuffer(make([]byte, 0, len(og)+len(c.Inject_Logtype_Field)+len(logtype)+6))
+	enc := json.NewEncoder(bb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obj); err != nil {
+		return og
+	}
+	return bytes.TrimRight(bb.Bytes(), "\n")
+}
+
 func (c *Corelight) getTagTs(mp map[string]interface{}) (tag string, ts time.Time, ok bool) {
 	var tagv interface{}
-	var tsv interface{}
-	var tss string
 	var tagval string
-	var err error
-	if tagv, ok = mp["_path"]; !ok {
-		return
-	} else if tsv, ok = mp["ts"]; !ok {
+	if tagv, ok = lookupField(mp, c.Path_Field); !ok {
 		return
 	} else if tagval, ok = tagv.(string); !ok {
 		return
-	} else if tss, ok = tsv.(string); !ok {
-		return
-	} else if ts, ok, err = c.timegrind.Extract([]byte(tss)); err != nil {
-		ok = false
-	} else {
-		tag = c.Prefix + tagval
+	} else if ts, ok = c.te.ExtractField(mp); ok {
+		tag = c.pathTag(tagval)
 	}
 	return
 }
 
-func tabReplace(v rune) rune {
-	if v == '\t' {
-		return ' '
+// pathTag returns the tag name for a log type
+func (c *Corelight) pathTag(path string) string {
+	if c.Normalize_Path {
+		path = strings.ToLower(strings.TrimSpace(path))
 	}
-	return v
+	return c.Prefix + path
 }
 
-func emitLine(ts time.Time, headers []string, mp map[string]interface{}) (line []byte, ok bool) {
-	bb := bytes.NewBuffer(nil)
-	var f64 float64
-	var s string
-	var bts []byte
-	fmt.Fprintf(bb, "%.6f", float64(ts.UnixNano())/1000000000.0)
-	for _, h := range headers[1:] { //always skip the TS
-		if v, ok := mp[h]; ok {
-			if f64, ok = v.(float64); ok {
-				if _, fractional := math.Modf(f64); fractional == 0 {
-					fmt.Fprintf(bb, "\t%d", int(f64))
-				} else {
-					fmt.Fprintf(bb, "\t%.5f", f64)
+// getMeta pulls the sensor attribution out of a decoded record
+func (c *Corelight) getMeta(mp map[string]interface{}) (meta corelightMeta) {
+	if c.Use_Write_TS {
+		if v, ok := mp[corelightWriteTSField]; ok {
+			if ts, ok := c.parseTs(v); ok {
+				meta.writeTs = ts
+			}
+		}
+	}
+	if c.Source_From_Field != `` {
+		if v, ok := lookupField(mp, c.Source_From_Field); ok {
+			meta.src, _ = v.(string)
+		}
+	}
+	return
+}
+
+// lookupField finds a field by name, if the name is not present as a key and contains
+// dots we walk down into nested objects, so "@metadata.path" will resolve to the
+// "path" member of the "@metadata" object.
+func lookupField(mp map[string]interface{}, name string) (v interface{}, ok bool) {
+	if v, ok = mp[name]; ok {
+		return
+	}
+	for {
+		idx := strings.IndexByte(name, '.')
+		if idx == -1 {
+			return
+		}
+		if v, ok = mp[name[:idx]]; !ok {
+			return
+		} else if mp, ok = v.(map[string]interface{}); !ok {
+			return
+		}
+		name = name[idx+1:]
+		if v, ok = mp[name]; ok {
+			return
+		}
+	}
+}
+
+// caseIndex maps the lowercased form of every field name, and of each of its dotted
+// segments so nested objects can be matched, to the name as it is configured.
+// Earlier names win when two differ only by case.
+func caseIndex(specs []corelightSpec, names ...string) map[string]string {
+	idx := map[string]string{}
+	add := func(name string) {
+		for _, v := range append([]string{name}, strings.Split(name, ".")...) {
+			if lv := strings.ToLower(v); lv != `` {
+				if _, ok := idx[lv]; !ok {
+					idx[lv] = v
 				}
-			} else if s, ok = v.(string); ok {
-				fmt.Fprintf(bb, "\t%s", strings.Map(tabReplace, s))
-			} else if bts, ok = v.([]byte); ok {
-				fmt.Fprintf(bb, "\t%s", string(bytes.Map(tabReplace, bts)))
-			} else {
-				fmt.Fprintf(bb, "\t%v", v)
 			}
+		}
+	}
+	for _, n := range names {
+		add(n)
+	}
+	for _, spec := range specs {
+		for _, h := range spec.headers {
+			add(h)
+		}
+	}
+	return idx
+}
+
+// foldKeys renames the keys of a decoded record, and of its nested objects, to the configured
+// field names they match regardless of case. The original record is walked so that the first
+// of several keys differing only by case wins, keys matching no field keep their case.
+func (c *Corelight) foldKeys(mp map[string]interface{}, og []byte) map[string]interface{} {
+	out := make(map[string]interface{}, len(mp))
+	add := func(k string, raw []byte) {
+		v, ok := mp[k]
+		if !ok {
+			return
+		}
+		ck := k
+		if n, ok := c.ciNames[strings.ToLower(k)]; ok {
+			ck = n
+		}
+		if _, ok := out[ck]; ok {
+			return // first seen wins
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = c.foldKeys(nested, raw)
+		}
+		out[ck] = v
+	}
+	jsonparser.ObjectEach(og, func(key, value []byte, _ jsonparser.ValueType, _ int) error {
+		add(string(key), value)
+		return nil
+	})
+	//keys with escapes do not match their raw form, so pick up anything that was missed
+	if len(out) < len(mp) {
+		rest := make([]string, 0, len(mp))
+		for k := range mp {
+			rest = append(rest, k)
+		}
+		sort.Strings(rest)
+		for _, k := range rest {
+			add(k, nil)
+		}
+	}
+	return out
+}
+
+// parseTs handles both RFC3339 string timestamps and numeric epoch timestamps
+// which some Zeek JSON exporters emit, see TimeExtractor.ExtractValue.
+func (c *Corelight) parseTs(v interface{}) (ts time.Time, ok bool) {
+	return c.te.ExtractValue(v)
+}
+
+func (c *Corelight) emitLine(ts time.Time, headers []string, mp map[string]interface{}) (line []byte, ok bool) {
+	bb := bytes.NewBuffer(nil)
+	c.writeEpoch(bb, ts)
+	for _, h := range headers[1:] { //always skip the TS
+		bb.WriteString(c.Field_Separator)
+		//Corelight flattens nested records into dotted keys, upstream Zeek keeps them as objects
+		if v, ok := lookupField(mp, h); ok {
+			c.writeValue(bb, v, c.precision(h))
 		} else {
-			fmt.Fprintf(bb, "\t-")
+			bb.WriteString(c.Empty_Field_Marker)
 		}
 	}
+	if c.geo != nil && hasGeoFields(headers) {
+		c.writeGeo(bb, mp)
+	}
+	if c.Append_Unknown_Fields {
+		bb.WriteString(c.Field_Separator)
+		c.writeUnknown(bb, headers, mp)
+	} else if c.Overflow_Column {
+		bb.WriteString(c.Field_Separator)
+		c.writeOverflow(bb, headers, mp)
+	}
 	line, ok = bb.Bytes(), true
 	return
 }
 
+// writeUnknown writes any fields not present in the headers as key=value pairs
+// sorted by key so that the output is stable.
+func (c *Corelight) writeUnknown(bb *bytes.Buffer, headers []string, mp map[string]interface{}) {
+	//members of nested records such as "id" are removed individually, see writeOverflow
+	rest := make(map[string]interface{}, len(mp))
+	for k, v := range mp {
+		rest[k] = v
+	}
+	dropField(rest, c.Path_Field)
+	dropField(rest, c.TS_Field)
+	for _, h := range headers {
+		dropField(rest, h)
+	}
+	keys := make([]string, 0, len(rest))
+	for k := range rest {
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		bb.WriteString(c.Empty_Field_Marker)
+		return
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i > 0 {
+			bb.WriteByte(' ')
+		}
+		bb.WriteString(k)
+		bb.WriteByte('=')
+		c.writeUnknownValue(bb, rest[k], c.precision(k))
+	}
+}
+
+// writeUnknownValue writes the value of a key=value pair appended by Append_Unknown_Fields
+func (c *Corelight) writeUnknownValue(bb *bytes.Buffer, v interface{}, prec int) {
+	var vb bytes.Buffer
+	if nestedValue(v) {
+		enc := json.NewEncoder(&vb)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err != nil {
+			bb.WriteString(c.Empty_Field_Marker)
+			return
+		}
+		vb.Truncate(vb.Len() - 1) //Encode always adds a newline
+	} else {
+		c.writeValue(&vb, v, prec)
+	}
+	val := bytes.ReplaceAll(vb.Bytes(), []byte(c.Field_Separator), []byte(" "))
+	if len(val) == 0 || bytes.ContainsAny(val, " =\"") {
+		//quoting escapes control characters, so the value still cannot contain a tab separator
+		bb.WriteString(strconv.Quote(string(val)))
+		return
+	}
+	bb.Write(val)
+}
+
+// nestedValue returns whether a decoded value is an object or a set containing objects or sets,
+// which writeValue cannot represent
+func nestedValue(v interface{}) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		for _, vv := range t {
+			switch vv.(type) {
+			case map[string]interface{}, []interface{}:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeOverflow writes every field not emitted by the headers as a compact JSON object.
+// Members of nested objects emitted through dotted headers are removed individually, so
+// an "id" object only keeps the members which are not headers.
+func (c *Corelight) writeOverflow(bb *bytes.Buffer, headers []string, mp map[string]interface{}) {
+	rest := make(map[string]interface{}, len(mp))
+	for k, v := range mp {
+		rest[k] = v
+	}
+	dropField(rest, c.Path_Field)
+	dropField(rest, c.TS_Field)
+	for _, h := range headers {
+		dropField(rest, h)
+	}
+	if len(rest) == 0 {
+		bb.WriteString(c.Empty_Field_Marker)
+		return
+	}
+	//JSON escapes control characters so the output can never contain the field separator
+	enc := json.NewEncoder(bb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(rest); err != nil {
+		bb.WriteString(c.Empty_Field_Marker)
+		return
+	}
+	bb.Truncate(bb.Len() - 1) //Encode always adds a newline
+}
+
+// dropField removes a field the same way lookupField resolves it, nested objects
+// are copied before they are modified and removed entirely once empty.
+func dropField(mp map[string]interface{}, name string) {
+	if _, ok := mp[name]; ok {
+		delete(mp, name)
+		return
+	}
+	idx := strings.IndexByte(name, '.')
+	if idx == -1 {
+		return
+	}
+	nested, ok := mp[name[:idx]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	cp := make(map[string]interface{}, len(nested))
+	for k, v := range nested {
+		cp[k] = v
+	}
+	if dropField(cp, name[idx+1:]); len(cp) == 0 {
+		delete(mp, name[:idx])
+	} else {
+		mp[name[:idx]] = cp
+	}
+}
+
+// writeEpoch writes the timestamp as epoch seconds rounded to Timestamp_Precision decimal digits.
+// Integer math is used as a float64 cannot hold nanosecond epochs exactly.
+func (c *Corelight) writeEpoch(bb *bytes.Buffer, ts time.Time) {
+	unit := time.Second
+	for i := 0; i < c.Timestamp_Precision; i++ {
+		unit /= 10
+	}
+	ts = ts.Round(unit)
+	sec, frac := ts.Unix(), int64(ts.Nanosecond())/int64(unit)
+	if sec < 0 && frac > 0 {
+		//Unix floors, so count the fraction towards zero instead
+		bb.WriteByte('-')
+		sec, frac = -sec-1, int64(time.Second/unit)-frac
+	}
+	fmt.Fprintf(bb, "%d.%0*d", sec, c.Timestamp_Precision, frac)
+}
+
+// precision returns the float precision for the given field
+func (c *Corelight) precision(field string) int {
+	if p, ok := c.fieldPrec[field]; ok {
+		return p
+	}
+	if c.Float_Precision == CorelightNoDecimals {
+		return 0
+	}
+	return c.Float_Precision
+}
+
+// writeValue formats a single decoded JSON value, sets and vectors are joined
+// using the set separator the same way Zeek does in its TSV output.
+func (c *Corelight) writeValue(bb *bytes.Buffer, v interface{}, prec int) {
+	switch t := v.(type) {
+	case float64:
+		if _, fractional := math.Modf(t); fractional == 0 {
+			fmt.Fprintf(bb, "%d", int(t))
+		} else {
+			bb.WriteString(strconv.FormatFloat(t, 'f', prec, 64))
+		}
+	case string:
+		bb.WriteString(strings.ReplaceAll(t, c.Field_Separator, " "))
+	case []byte:
+		bb.Write(bytes.ReplaceAll(t, []byte(c.Field_Separator), []byte(" ")))
+	case []interface{}:
+		if len(t) == 0 {
+			bb.WriteString(c.Empty_Field_Marker)
+			return
+		}
+		for i, vv := range t {
+			if i > 0 {
+				bb.WriteString(c.Set_Separator)
+			}
+			c.writeValue(bb, vv, prec)
+		}
+	default:
+		fmt.Fprintf(bb, "%v", v)
+	}
+}
+
 func (cl *CorelightConfig) Validate() (err error) {
 	if cl.Prefix == `` {
 		cl.Prefix = defaultPrefix
@@ -252,7 +1048,113 @@ func (cl *CorelightConfig) Validate() (err error) {
 		err = fmt.Errorf("prefix %q is invalid %w", cl.Prefix, err)
 		return
 	}
-	_, err = loadCustomFormats(cl.Custom_Format)
+	if cl.Path_Field = strings.TrimSpace(cl.Path_Field); cl.Path_Field == `` {
+		cl.Path_Field = defaultPathField
+	}
+	if cl.TS_Field = strings.TrimSpace(cl.TS_Field); cl.TS_Field == `` {
+		cl.TS_Field = defaultTSField
+	}
+	cl.Source_From_Field = strings.TrimSpace(cl.Source_From_Field)
+	if _, err = NewTimestampClamp(cl.Max_Timestamp_Skew, cl.On_Skew); err != nil {
+		return
+	}
+	switch cl.Format = strings.ToLower(strings.TrimSpace(cl.Format)); cl.Format {
+	case ``:
+		cl.Format = corelightFormatTSV
+	case corelightFormatTSV, corelightFormatJSON:
+	default:
+		err = fmt.Errorf("Format %q is invalid, must be %q or %q", cl.Format, corelightFormatTSV, corelightFormatJSON)
+		return
+	}
+	if cl.Float_Precision == 0 {
+		cl.Float_Precision = defaultFloatPrecision
+	}
+	if cl.Float_Precision != CorelightNoDecimals && (cl.Float_Precision < 0 || cl.Float_Precision > maxFloatPrecision) {
+		err = fmt.Errorf("Float-Precision %d is invalid, must be between 0 and %d", cl.Float_Precision, maxFloatPrecision)
+		return
+	} else if _, err = loadFloatPrecisions(cl.Field_Float_Precision); err != nil {
+		return
+	} else if _, err = loadTagRemap(cl.Tag_Remap); err != nil {
+		return
+	}
+	switch cl.Timestamp_Precision {
+	case 0:
+		cl.Timestamp_Precision = defaultTSPrecision
+	case 3, 6, 9:
+	default:
+		err = fmt.Errorf("Timestamp-Precision %d is invalid, must be 3, 6, or 9", cl.Timestamp_Precision)
+		return
+	}
+	if cl.Default_Tag != `` && cl.Error_Tag != `` {
+		err = errors.New("Default-Tag and Error-Tag are mutually exclusive")
+		return
+	} else if cl.Default_Tag != `` {
+		if err = ingest.CheckTag(cl.Default_Tag); err != nil {
+			err = fmt.Errorf("Default-Tag %q is invalid %w", cl.Default_Tag, err)
+			return
+		}
+	} else if cl.Error_Tag != `` {
+		if err = ingest.CheckTag(cl.Error_Tag); err != nil {
+			err = fmt.Errorf("Error-Tag %q is invalid %w", cl.Error_Tag, err)
+			return
+		}
+	}
+	if cl.Inject_Logtype_Field = strings.TrimSpace(cl.Inject_Logtype_Field); cl.Inject_Logtype_Field != `` && cl.Format != corelightFormatJSON {
+		err = errors.New("Inject-Logtype-Field requires the json format")
+		return
+	}
+	if cl.GeoIP_Database = strings.TrimSpace(cl.GeoIP_Database); cl.GeoIP_Database != `` && cl.Format == corelightFormatJSON {
+		err = errors.New("GeoIP-Database is not compatible with the json format")
+		return
+	}
+	if cl.Field_Separator == `` {
+		cl.Field_Separator = defaultFieldSeparator
+	}
+	if cl.Set_Separator == `` {
+		cl.Set_Separator = defaultSetSeparator
+	}
+	if cl.Empty_Field_Marker == `` {
+		cl.Empty_Field_Marker = defaultEmptyFieldMarker
+	}
+	if strings.ContainsAny(cl.Field_Separator, "\r\n") {
+		err = fmt.Errorf("Field-Separator %q may not contain newlines", cl.Field_Separator)
+		return
+	}
+	if cl.Overflow_Column {
+		if cl.Format == corelightFormatJSON {
+			err = errors.New("Overflow-Column is not compatible with the json format")
+			return
+		} else if cl.Append_Unknown_Fields {
+			err = errors.New("Overflow-Column and Append-Unknown-Fields are mutually exclusive")
+			return
+		}
+		for _, r := range cl.Field_Separator {
+			if r >= 0x20 {
+				err = fmt.Errorf("Overflow-Column requires a Field-Separator of control characters such as a tab, %q may appear in JSON", cl.Field_Separator)
+				return
+			}
+		}
+	}
+	if cl.Quarantine_Tag = strings.TrimSpace(cl.Quarantine_Tag); cl.Validate_Schema {
+		if cl.Format == corelightFormatJSON {
+			err = errors.New("Validate-Schema is not compatible with the json format")
+			return
+		} else if cl.Quarantine_Tag == `` {
+			err = errors.New("Validate-Schema requires a Quarantine-Tag")
+			return
+		} else if err = ingest.CheckTag(cl.Quarantine_Tag); err != nil {
+			err = fmt.Errorf("Quarantine-Tag %q is invalid %w", cl.Quarantine_Tag, err)
+			return
+		}
+	} else if cl.Quarantine_Tag != `` {
+		err = errors.New("Quarantine-Tag requires Validate-Schema")
+		return
+	}
+	var specs []corelightSpec
+	if specs, err = loadCustomFormats(cl.Custom_Format); err != nil {
+		return
+	}
+	_, err = loadLogtypeFilter(cl.Enable_Logtypes, cl.Disable_Logtypes, append(defaultSpecs(), specs...))
 	return
 }
 
@@ -302,6 +1204,84 @@ func loadCustomFormats(strs []string) (specs []corelightSpec, err error) {
 	return
 }
 
+// defaultTag returns the tag name applied to unconverted entries, if any
+func (cl *CorelightConfig) defaultTag() string {
+	if cl.Default_Tag != `` {
+		return strings.TrimSpace(cl.Default_Tag)
+	}
+	return strings.TrimSpace(cl.Error_Tag)
+}
+
+func loadFloatPrecisions(strs []string) (mp map[string]int, err error) {
+	mp = make(map[string]int, len(strs))
+	for _, v := range strs {
+		v = strings.TrimSpace(v)
+		idx := strings.LastIndexByte(v, ':')
+		if idx <= 0 {
+			err = fmt.Errorf("%q field float precision is invalid", v)
+			return
+		}
+		var p int
+		if p, err = strconv.Atoi(strings.TrimSpace(v[idx+1:])); err != nil || p < 0 || p > maxFloatPrecision {
+			err = fmt.Errorf("%q field float precision is invalid, precision must be between 0 and %d", v, maxFloatPrecision)
+			return
+		}
+		mp[strings.TrimSpace(v[:idx])] = p
+	}
+	return
+}
+
+func loadTagRemap(strs []string) (mp map[string]string, err error) {
+	mp = make(map[string]string, len(strs))
+	for _, v := range strs {
+		bits := strings.SplitN(v, "=", 2)
+		if len(bits) != 2 {
+			err = fmt.Errorf("%q tag remap is invalid, must be of the form source=destination", v)
+			return
+		}
+		src, dst := strings.TrimSpace(bits[0]), strings.TrimSpace(bits[1])
+		if src == `` {
+			err = fmt.Errorf("%q tag remap is invalid, missing source tag", v)
+			return
+		} else if err = ingest.CheckTag(dst); err != nil {
+			err = fmt.Errorf("%q tag remap is invalid %w", v, err)
+			return
+		} else if _, ok := mp[src]; ok {
+			err = fmt.Errorf("%q tag remap is invalid, %q is remapped more than once", v, src)
+			return
+		}
+		mp[src] = dst
+	}
+	return
+}
+
+// loadLogtypeFilter validates the enabled and disabled log types against the known specs,
+// returning a func reporting whether a log type is enabled.
+func loadLogtypeFilter(enable, disable []string, specs []corelightSpec) (enabled func(string) bool, err error) {
+	if len(enable) > 0 && len(disable) > 0 {
+		err = errors.New("Enable-Logtypes and Disable-Logtypes are mutually exclusive")
+		return
+	}
+	known := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		known[spec.prefix] = true
+	}
+	list := make(map[string]bool, len(enable)+len(disable))
+	for _, v := range append(enable, disable...) {
+		if v = strings.TrimSpace(v); !known[v] {
+			err = fmt.Errorf("%q is not a known log type", v)
+			return
+		}
+		list[v] = true
+	}
+	if len(enable) > 0 {
+		enabled = func(lt string) bool { return list[lt] }
+	} else {
+		enabled = func(lt string) bool { return !list[lt] }
+	}
+	return
+}
+
 func loadHeaders(v string) (hdrs []string, err error) {
 	v = strings.TrimSpace(v)
 	if hdrs = cleanHeaders(strings.Split(v, ",")); len(hdrs) == 0 {
@@ -314,6 +1294,7 @@ var tagHeaders = map[string]string{
 	"bacnet":             "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,bvlc_function,bvlc_len,apdu_type,service_choice,data",
 	"conn_long":          "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,proto,service,duration,orig_bytes,resp_bytes,conn_state,local_orig,local_resp,missed_bytes,history,orig_pkts,orig_ip_bytes,resp_pkts,resp_ip_bytes,corelight_shunted",
 	"conn":               "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,proto,service,duration,orig_bytes,resp_bytes,conn_state,local_orig,local_resp,missed_bytes,history,orig_pkts,orig_ip_bytes,resp_pkts,resp_ip_bytes,tunnel_parents,vlan",
+	"dce_rpc":            "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,rtt,named_pipe,endpoint,operation",
 	"dhcp":               "ts,uids,client_addr,server_addr,mac,host_name,client_fqdn,domain,requested_addr,assigned_addr,lease_time,client_message,server_message,msg_types,duration",
 	"dns":                "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,proto,trans_id,rtt,query,qclass,qclass_name,qtype,qtype_name,rcode,rcode_name,AA,TC,RD,RA,Z,answers,TTLs,rejected",
 	"dpd":                "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,proto,analyzer,failure_reason,packet_segment",
@@ -328,6 +1309,7 @@ var tagHeaders = map[string]string{
 	"modbus":             "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,func,exception",
 	"mysql":              "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,cmd,arg,success,rows,response",
 	"notice":             "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,fuid,file_mime_type,file_desc,proto,note,msg,sub,src,dst,p,n,peer_descr,actions,suppress_for,remote_location.destination_country_code,remote_location.destination_region,remote_location.destination_city,remote_location.destination_latitude,remote_location.destination_longitude,dropped",
+	"ntlm":               "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,username,hostname,domainname,server_nb_computer_name,server_dns_computer_name,server_tree_name,success",
 	"ntp":                "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,version,mode,stratum,poll,precision,root_delay,root_disp,ref_id,ref_time,org_time,rec_time,xmt_time,num_exts",
 	"pe":                 "ts,id,machine,compile_ts,os,subsystem,is_exe,is_64bit,uses_aslr,uses_dep,uses_code_integrity,uses_seh,has_import_table,has_cert_table,has_debug_data,section_names",
 	"radius":             "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,username,mac,framed_addr,tunnel_client,connect_info,reply_msg,result,ttl,logged",
@@ -336,6 +1318,7 @@ var tagHeaders = map[string]string{
 	"rfb":                "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,client_major_version,client_minor_version,server_major_version,server_minor_version,authentication_method,auth,share_flag,desktop_name,width,height",
 	"signature":          "ts,uid,src_addr,src_port,dst_addr,dst_port,note,sig_id,event_msg,sub_msg,sig_count,host_count",
 	"sip":                "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,trans_depth,method,uri,date,request_from,request_to,response_from,response_to,reply_to,call_id,seq,subject,request_path,response_path,user_agent,status_code,status_msg,warning,request_body_len,response_body_len,content_type",
+	"smb_cmd":            "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,command,sub_command,argument,status,rtt,version,username,tree,tree_service,referenced_file.ts,referenced_file.uid,referenced_file.id.orig_h,referenced_file.id.orig_p,referenced_file.id.resp_h,referenced_file.id.resp_p,referenced_file.fuid,referenced_file.action,referenced_file.path,referenced_file.name,referenced_file.size,referenced_file.prev_name,referenced_file.times.modified,referenced_file.times.accessed,referenced_file.times.created,referenced_file.times.changed",
 	"smb_files":          "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,fuid,action,path,name,size,prev_name,times.modified,times.accessed,times.created,times.changed",
 	"smb_mapping":        "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,path,service,native_file_system,share_type",
 	"smtp_links":         "ts,uid,id.orig_h,id.orig_p,id.resp_h,id.resp_p,fuid,link",
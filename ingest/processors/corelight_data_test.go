@@ -90,7 +90,7 @@ const dns1_in = `{
   "rejected": false
 }`
 
-const dns2_out = `1597559163.553287	CMdzit1AMNsmfAIiQc	192.168.4.76	36844	192.168.4.1	53	udp	19671	0.06685	testmyids.com	1	C_INTERNET	1	A	0	NOERROR	false	false	true	true	0	[31.3.245.133]	[3600]	false`
+const dns2_out = `1597559163.553287	CMdzit1AMNsmfAIiQc	192.168.4.76	36844	192.168.4.1	53	udp	19671	0.06685	testmyids.com	1	C_INTERNET	1	A	0	NOERROR	false	false	true	true	0	31.3.245.133	3600	false`
 const dns2_in = `{
   "_path": "dns",
   "_system_name": "ds61",
@@ -126,7 +126,7 @@ const dns2_in = `{
   "rejected": false
 }`
 
-const dhcp1_out = `1597559163.553287	[COoA8M1gbTowuPlVT CapFoX32zVg3R6TATc]	192.168.4.152	192.168.4.1	3c:58:c2:2f:91:21	3071N0098017422	3071N0098017422.fcps.edu	localdomain	192.168.4.152	192.168.4.152	86400	-	-	[DISCOVER OFFER REQUEST ACK]	0.41635`
+const dhcp1_out = `1597559163.553287	COoA8M1gbTowuPlVT,CapFoX32zVg3R6TATc	192.168.4.152	192.168.4.1	3c:58:c2:2f:91:21	3071N0098017422	3071N0098017422.fcps.edu	localdomain	192.168.4.152	192.168.4.152	86400	-	-	DISCOVER,OFFER,REQUEST,ACK	0.41635`
 const dhcp1_in = `{
   "_path": "dhcp",
   "_system_name": "ds61",
@@ -303,7 +303,7 @@ const ssh4_in = `{
   "hasshServerAlgorithms": "curve25519-sha256@libssh.org,ecdh-sha2-nistp256,ecdh-sha2-nistp384,ecdh-sha2-nistp521,diffie-hellman-group-exchange-sha256,diffie-hellman-group-exchange-sha1,diffie-hellman-group14-sha1,diffie-hellman-group1-sha1;chacha20-poly1305@openssh.com,aes128-ctr,aes192-ctr,aes256-ctr,aes128-gcm@openssh.com,aes256-gcm@openssh.com;hmac-md5-etm@openssh.com,hmac-sha1-etm@openssh.com,umac-64-etm@openssh.com,umac-128-etm@openssh.com,hmac-sha2-256-etm@openssh.com,hmac-sha2-512-etm@openssh.com,hmac-ripemd160-etm@openssh.com,hmac-sha1-96-etm@openssh.com,hmac-md5-96-etm@openssh.com,hmac-md5,hmac-sha1,umac-64@openssh.com,umac-128@openssh.com,hmac-sha2-256,hmac-sha2-512,hmac-ripemd160,hmac-ripemd160@openssh.com,hmac-sha1-96,hmac-md5-96;none,zlib@openssh.com"
 }`
 
-const http1_out = "1600266221.005323	C5bLoe2Mvxqhawzqqd	192.168.4.76	46378	31.3.245.133	80	1	GET	testmyids.com	/	-	1.1	curl/7.47.0	-	0	39	200	OK	-	-	[]	-	-	-	-	-	-	[FEEsZS1w0Z0VJIb5x4]	-	[text/plain]"
+const http1_out = "1600266221.005323	C5bLoe2Mvxqhawzqqd	192.168.4.76	46378	31.3.245.133	80	1	GET	testmyids.com	/	-	1.1	curl/7.47.0	-	0	39	200	OK	-	-	-	-	-	-	-	-	-	FEEsZS1w0Z0VJIb5x4	-	text/plain"
 const http1_in = `{
   "_path": "http",
   "ts": "2020-09-16T14:23:41.005323Z",
@@ -331,7 +331,7 @@ const http1_in = `{
   ]
 }`
 
-const files1_out = "1600266221.005323	FBbQxG1GXLXgmWhbk9	[23.195.64.241]	[192.168.4.37]	[CzoFRWTQ6YIzfFXHk]	HTTP	0	[EXTRACT PE]	application/x-dosexec	-	0.01550	-	false	179272	179272	0	0	false	-	-	-	-	HTTP-FBbQxG1GXLXgmWhbk9.exe	false	-"
+const files1_out = "1600266221.005323	FBbQxG1GXLXgmWhbk9	23.195.64.241	192.168.4.37	CzoFRWTQ6YIzfFXHk	HTTP	0	EXTRACT,PE	application/x-dosexec	-	0.01550	-	false	179272	179272	0	0	false	-	-	-	-	HTTP-FBbQxG1GXLXgmWhbk9.exe	false	-"
 const files1_in = `{
   "_path": "files",
   "ts": "2020-09-16T14:23:41.005323Z",
@@ -363,7 +363,7 @@ const files1_in = `{
   "extracted_cutoff": false
 }`
 
-const ssl1_out = "1600266221.005323	CsukF91Bx9mrqdEaH9	192.168.4.49	56718	13.32.202.10	443	TLSv12	TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256	secp256r1	www.taosecurity.com	false	-	h2	true	[F2XEvj1CahhdhtfvT4 FZ7ygD3ERPfEVVohG9 F7vklpOKI4yX9wmvh FAnbnR32nIIr2j9XV]	[]	CN=www.taosecurity.com	CN=Amazon,OU=Server CA 1B,O=Amazon,C=US	-	-	-"
+const ssl1_out = "1600266221.005323	CsukF91Bx9mrqdEaH9	192.168.4.49	56718	13.32.202.10	443	TLSv12	TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256	secp256r1	www.taosecurity.com	false	-	h2	true	F2XEvj1CahhdhtfvT4,FZ7ygD3ERPfEVVohG9,F7vklpOKI4yX9wmvh,FAnbnR32nIIr2j9XV	-	CN=www.taosecurity.com	CN=Amazon,OU=Server CA 1B,O=Amazon,C=US	-	-	-"
 const ssl1_in = `{
   "_path": "ssl",
   "ts": "2020-09-16T14:23:41.005323Z",
@@ -407,7 +407,7 @@ const ssl2_in = `{
   "established": true
 }`
 
-const x5091_out = "1600266220.005323	F2XEvj1CahhdhtfvT4	3	0B58BC3898391F36592BA1BE1F6B03EF	CN=www.taosecurity.com	CN=Amazon,OU=Server CA 1B,O=Amazon,C=US	1590969600	1625140800	rsaEncryption	sha256WithRSAEncryption	rsa	2048	65537	-	[www.taosecurity.com taosecurity.com *.taosecurity.com]	-	-	-	false	-"
+const x5091_out = "1600266220.005323	F2XEvj1CahhdhtfvT4	3	0B58BC3898391F36592BA1BE1F6B03EF	CN=www.taosecurity.com	CN=Amazon,OU=Server CA 1B,O=Amazon,C=US	1590969600	1625140800	rsaEncryption	sha256WithRSAEncryption	rsa	2048	65537	-	www.taosecurity.com,taosecurity.com,*.taosecurity.com	-	-	-	false	-"
 const x5091_in = `{
   "_path": "x509",
   "ts": "1600266220.005323",
@@ -431,7 +431,7 @@ const x5091_in = `{
   "basic_constraints.ca": false
 }`
 
-const smtp1_out = `1254722768.219663	C1qe8w3QHRF2N5tVV5	10.10.1.4	1470	74.53.140.153	25	1	GP	gurpartap@patriots.in	[raj_deol2002in@yahoo.co.in]	Mon, 5 Oct 2009 11:36:07 +0530	"Gurpartap Singh" <gurpartap@patriots.in>	[<raj_deol2002in@yahoo.co.in>]	-	-	<000301ca4581$ef9e57f0$cedb07d0$@in>	-	SMTP	-	-	-	250 OK id=1Mugho-0003Dg-Un	[74.53.140.153 10.10.1.4]	Microsoft Office Outlook 12.0	false`
+const smtp1_out = `1254722768.219663	C1qe8w3QHRF2N5tVV5	10.10.1.4	1470	74.53.140.153	25	1	GP	gurpartap@patriots.in	raj_deol2002in@yahoo.co.in	Mon, 5 Oct 2009 11:36:07 +0530	"Gurpartap Singh" <gurpartap@patriots.in>	<raj_deol2002in@yahoo.co.in>	-	-	<000301ca4581$ef9e57f0$cedb07d0$@in>	-	SMTP	-	-	-	250 OK id=1Mugho-0003Dg-Un	74.53.140.153,10.10.1.4	Microsoft Office Outlook 12.0	false`
 const smtp1_in = `{
   "_path": "smtp",
   "ts": "1254722768.219663",
@@ -468,7 +468,7 @@ const smtp1_in = `{
 }`
 
 // only difference is we are testing a tab in the encoded JSON gets turned into a space
-const smtp2_out = `1254722768.219663	C1qe8w3QHRF2N5tVV5	10.10.1.4	1470	74.53.140.153	25	1	GP	gurpartap@patriots.in	[raj_deol2002in@yahoo.co.in]	Mon, 5 Oct 2009 11:36:07 +0530	"Gurpartap Singh" <gurpartap@patriots.in>	[<raj_deol2002in@yahoo.co.in>]	-	-	<000301ca4581$ef9e57f0$cedb07d0$@in>	-	SMTP	-	-	-	250 OK id=1Mugho-0003Dg-Un	[74.53.140.153 10.10.1.4]	Microsoft Office Outlook 12.0	false`
+const smtp2_out = `1254722768.219663	C1qe8w3QHRF2N5tVV5	10.10.1.4	1470	74.53.140.153	25	1	GP	gurpartap@patriots.in	raj_deol2002in@yahoo.co.in	Mon, 5 Oct 2009 11:36:07 +0530	"Gurpartap Singh" <gurpartap@patriots.in>	<raj_deol2002in@yahoo.co.in>	-	-	<000301ca4581$ef9e57f0$cedb07d0$@in>	-	SMTP	-	-	-	250 OK id=1Mugho-0003Dg-Un	74.53.140.153,10.10.1.4	Microsoft Office Outlook 12.0	false`
 const smtp2_in = `{
   "_path": "smtp",
   "ts": "1254722768.219663",
@@ -504,7 +504,7 @@ const smtp2_in = `{
   ]
 }`
 
-const pe1_out = `1600820676.395445	FGYKX64SkXc4OcvlFf	AMD64	2020-09-19T00:10:08.000000Z	Windows XP x64 or Server 2003	WINDOWS_GUI	true	true	true	true	false	true	true	true	true	[.text .rdata .data .pdata .00cfg .rsrc .reloc]`
+const pe1_out = `1600820676.395445	FGYKX64SkXc4OcvlFf	AMD64	2020-09-19T00:10:08.000000Z	Windows XP x64 or Server 2003	WINDOWS_GUI	true	true	true	true	false	true	true	true	true	.text,.rdata,.data,.pdata,.00cfg,.rsrc,.reloc`
 const pe1_in = `{
   "_path": "pe",
   "ts": "2020-09-23T00:24:36.395445Z",
@@ -582,7 +582,7 @@ const ntp2_in = `{
   "num_exts": 0
 }`
 
-const notice1_out = `1600820676.395445	CxdbSa2KGTlMl3PPB2	192.168.4.129	51020	40.71.25.43	8080	FtEE2txjFBxLDbffi	-	-	tcp	SSL::Invalid_Server_Cert	SSL certificate validation failed with (unable to get local issuer certificate)	CN=*.cloudapp.net,OU=Smart Controller Development,O=GTO Access Systems\, LLC,DC=smartcontroller,DC=local	192.168.4.129	40.71.25.43	8080	-	so16-enp0s8-1	[Notice::ACTION_LOG]	3600	-	-	-	-	-	-`
+const notice1_out = `1600820676.395445	CxdbSa2KGTlMl3PPB2	192.168.4.129	51020	40.71.25.43	8080	FtEE2txjFBxLDbffi	-	-	tcp	SSL::Invalid_Server_Cert	SSL certificate validation failed with (unable to get local issuer certificate)	CN=*.cloudapp.net,OU=Smart Controller Development,O=GTO Access Systems\, LLC,DC=smartcontroller,DC=local	192.168.4.129	40.71.25.43	8080	-	so16-enp0s8-1	Notice::ACTION_LOG	3600	-	-	-	-	-	-`
 const notice1_in = `{
   "_path": "notice",
   "ts": "2020-09-23T00:24:36.395445Z",
@@ -606,7 +606,7 @@ const notice1_in = `{
   "suppress_for": 3600
 }`
 
-const notice2_out = `1600820676.395445	-	-	-	-	-	-	-	-	-	ATTACK::Discovery	Detected activity from host 192.168.10.31, total attempts 5 within timeframe 5.0 mins	-	-	-	-	-	-	[Notice::ACTION_LOG]	3600	-	-	-	-	-	-`
+const notice2_out = `1600820676.395445	-	-	-	-	-	-	-	-	-	ATTACK::Discovery	Detected activity from host 192.168.10.31, total attempts 5 within timeframe 5.0 mins	-	-	-	-	-	-	Notice::ACTION_LOG	3600	-	-	-	-	-	-`
 const notice2_in = `{
   "_path": "notice",
   "ts": "2020-09-23T00:24:36.395445Z",
@@ -618,7 +618,7 @@ const notice2_in = `{
   "suppress_for": 3600
 }`
 
-const notice3_out = `1600820676.395445	CR7Vww4LuLkMzi4jMd	192.168.10.31	49238	192.168.10.30	445	FwVZpk12AKBjE11UNg	application/x-dosexec	temp	tcp	ATTACK::Lateral_Movement_Extracted_File	Saved a copy of the file written to SMB admin file share	CR7Vww4LuLkMzi4jMd_FwVZpk12AKBjE11UNg__admin-pc_c$temp_mimikatz.exe	192.168.10.31	192.168.10.30	445	-	-	[Notice::ACTION_LOG]	3600	-	-	-	-	-	-`
+const notice3_out = `1600820676.395445	CR7Vww4LuLkMzi4jMd	192.168.10.31	49238	192.168.10.30	445	FwVZpk12AKBjE11UNg	application/x-dosexec	temp	tcp	ATTACK::Lateral_Movement_Extracted_File	Saved a copy of the file written to SMB admin file share	CR7Vww4LuLkMzi4jMd_FwVZpk12AKBjE11UNg__admin-pc_c$temp_mimikatz.exe	192.168.10.31	192.168.10.30	445	-	-	Notice::ACTION_LOG	3600	-	-	-	-	-	-`
 const notice3_in = `{
   "_path": "notice",
   "ts": "2020-09-23T00:24:36.395445Z",
@@ -780,3 +780,79 @@ const software1_in = `{
   "version.addl": "Client",
   "unparsed_version": "Windows-Update-Agent/10.0.10011.16384 Client-Protocol/2.0"
 }`
+
+const dce_rpc1_out = `1600822437.651208	CnrZPn1sGC0ohSyx6l	192.168.4.50	49702	192.168.4.12	135	0.00036	135	epmapper	ept_map`
+const dce_rpc1_in = `{
+  "_path": "dce_rpc",
+  "_system_name": "ds61",
+  "_write_ts": "2020-09-23T00:53:57.651568Z",
+  "_node": "worker-01",
+  "ts": "2020-09-23T00:53:57.651208Z",
+  "uid": "CnrZPn1sGC0ohSyx6l",
+  "id.orig_h": "192.168.4.50",
+  "id.orig_p": 49702,
+  "id.resp_h": "192.168.4.12",
+  "id.resp_p": 135,
+  "rtt": 0.00036,
+  "named_pipe": "135",
+  "endpoint": "epmapper",
+  "operation": "ept_map"
+}`
+
+const ntlm1_out = `1600822437.693424	CBQ4MM3Mt3RYNJ8Tu9	192.168.4.50	49703	192.168.4.12	445	jdoe	WORKSTATION1	CORP	DC01	dc01.corp.local	corp.local	true`
+const ntlm1_in = `{
+  "_path": "ntlm",
+  "_system_name": "ds61",
+  "_write_ts": "2020-09-23T00:53:57.693611Z",
+  "_node": "worker-01",
+  "ts": "2020-09-23T00:53:57.693424Z",
+  "uid": "CBQ4MM3Mt3RYNJ8Tu9",
+  "id.orig_h": "192.168.4.50",
+  "id.orig_p": 49703,
+  "id.resp_h": "192.168.4.12",
+  "id.resp_p": 445,
+  "username": "jdoe",
+  "hostname": "WORKSTATION1",
+  "domainname": "CORP",
+  "server_nb_computer_name": "DC01",
+  "server_dns_computer_name": "dc01.corp.local",
+  "server_tree_name": "corp.local",
+  "success": true
+}`
+
+const smb_cmd1_out = `1600822437.701234	CBQ4MM3Mt3RYNJ8Tu9	192.168.4.50	49703	192.168.4.12	445	SMB2::CREATE	-	PSEXESVC.exe	SUCCESS	0.00052	SMB2	jdoe	\\dc01\ADMIN$	DISK	1600822437.70091	CBQ4MM3Mt3RYNJ8Tu9	192.168.4.50	49703	192.168.4.12	445	FHkQ2n3NkvLlGbSy8b	SMB::FILE_OPEN	\\dc01\ADMIN$	PSEXESVC.exe	0	-	1507565599.60778	1507565599.60778	1507565599.60778	1507565599.60778`
+const smb_cmd1_in = `{
+  "_path": "smb_cmd",
+  "_system_name": "ds61",
+  "_write_ts": "2020-09-23T00:53:57.701500Z",
+  "_node": "worker-01",
+  "ts": "2020-09-23T00:53:57.701234Z",
+  "uid": "CBQ4MM3Mt3RYNJ8Tu9",
+  "id.orig_h": "192.168.4.50",
+  "id.orig_p": 49703,
+  "id.resp_h": "192.168.4.12",
+  "id.resp_p": 445,
+  "command": "SMB2::CREATE",
+  "argument": "PSEXESVC.exe",
+  "status": "SUCCESS",
+  "rtt": 0.00052,
+  "version": "SMB2",
+  "username": "jdoe",
+  "tree": "\\\\dc01\\ADMIN$",
+  "tree_service": "DISK",
+  "referenced_file.ts": 1600822437.700913,
+  "referenced_file.uid": "CBQ4MM3Mt3RYNJ8Tu9",
+  "referenced_file.id.orig_h": "192.168.4.50",
+  "referenced_file.id.orig_p": 49703,
+  "referenced_file.id.resp_h": "192.168.4.12",
+  "referenced_file.id.resp_p": 445,
+  "referenced_file.fuid": "FHkQ2n3NkvLlGbSy8b",
+  "referenced_file.action": "SMB::FILE_OPEN",
+  "referenced_file.path": "\\\\dc01\\ADMIN$",
+  "referenced_file.name": "PSEXESVC.exe",
+  "referenced_file.size": 0,
+  "referenced_file.times.modified": 1507565599.607777,
+  "referenced_file.times.accessed": 1507565599.607777,
+  "referenced_file.times.created": 1507565599.607777,
+  "referenced_file.times.changed": 1507565599.607777
+}`
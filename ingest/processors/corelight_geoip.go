@@ -0,0 +1,125 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+const (
+	corelightOrigAddr = `id.orig_h`
+	corelightRespAddr = `id.resp_h`
+)
+
+var (
+	// GeoIP databases are memory mapped once and shared by every corelight processor
+	geoDBLock sync.Mutex
+	geoDBs    = map[string]*maxminddb.Reader{}
+)
+
+// geoLookup resolves an address to a country code and autonomous system number
+type geoLookup interface {
+	lookup(ip net.IP) (cc string, asn uint, ok bool)
+}
+
+// geoRecord holds the subset of a MaxMind record we annotate with, City/Country
+// databases populate the country and ASN databases populate the AS number.
+type geoRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	ASN uint `maxminddb:"autonomous_system_number"`
+}
+
+type mmdbLookup struct {
+	rdr *maxminddb.Reader
+}
+
+func (m mmdbLookup) lookup(ip net.IP) (cc string, asn uint, ok bool) {
+	var rec geoRecord
+	if err := m.rdr.Lookup(ip, &rec); err != nil {
+		return
+	}
+	return rec.Country.ISOCode, rec.ASN, rec.Country.ISOCode != `` || rec.ASN != 0
+}
+
+// openGeoDB returns the shared reader for the database at pth, opening it on first use
+func openGeoDB(pth string) (geoLookup, error) {
+	if abs, err := filepath.Abs(pth); err == nil {
+		pth = abs
+	}
+	geoDBLock.Lock()
+	defer geoDBLock.Unlock()
+	if rdr, ok := geoDBs[pth]; ok {
+		return mmdbLookup{rdr: rdr}, nil
+	}
+	rdr, err := maxminddb.Open(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP-Database %q: %w", pth, err)
+	}
+	geoDBs[pth] = rdr
+	return mmdbLookup{rdr: rdr}, nil
+}
+
+// hasGeoFields returns whether a log type carries both the originator and responder addresses
+func hasGeoFields(headers []string) bool {
+	var orig, resp bool
+	for _, h := range headers {
+		switch h {
+		case corelightOrigAddr:
+			orig = true
+		case corelightRespAddr:
+			resp = true
+		}
+	}
+	return orig && resp
+}
+
+// writeGeo appends the orig_cc, resp_cc, orig_asn, and resp_asn columns, addresses
+// which are missing, invalid, private, or unknown to the database get the empty field marker.
+func (c *Corelight) writeGeo(bb *bytes.Buffer, mp map[string]interface{}) {
+	occ, oasn := c.geoFields(mp, corelightOrigAddr)
+	rcc, rasn := c.geoFields(mp, corelightRespAddr)
+	for _, v := range []string{occ, rcc, oasn, rasn} {
+		bb.WriteString(c.Field_Separator)
+		bb.WriteString(v)
+	}
+}
+
+func (c *Corelight) geoFields(mp map[string]interface{}, field string) (cc, asn string) {
+	cc, asn = c.Empty_Field_Marker, c.Empty_Field_Marker
+	v, ok := lookupField(mp, field)
+	if !ok {
+		return
+	}
+	s, ok := v.(string)
+	if !ok {
+		return
+	}
+	ip := net.ParseIP(s)
+	if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return
+	}
+	if ccv, asnv, ok := c.geo.lookup(ip); ok {
+		if ccv != `` {
+			cc = ccv
+		}
+		if asnv != 0 {
+			asn = strconv.FormatUint(uint64(asnv), 10)
+		}
+	}
+	return
+}
@@ -0,0 +1,111 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const maxLintLineSize = 16 * 1024 * 1024
+
+// CorelightLint reports how a Corelight processor would handle a sample of records,
+// so that a configuration can be checked against real data before it is deployed.
+type CorelightLint struct {
+	Records     uint64            // total records examined
+	Tags        map[string]uint64 // converted records by resolved tag name, after Tag_Remap
+	Disabled    map[string]uint64 // records of a disabled log type by log type, which pass through unchanged
+	Unmapped    map[string]uint64 // records whose log type has no format by Path_Field value
+	Quarantined map[string]uint64 // records which failed Validate_Schema by log type
+	Failed      uint64            // records which could not be converted for any other reason
+	Oversized   uint64            // records larger than Max_Entry_Size, which pass through unchanged
+	DefaultTag  string            // tag applied to unmapped and failed records, empty if they keep the ingester's tag
+}
+
+// Lint runs the processor over newline delimited records read from r without emitting
+// entries or updating the processor's stats, and reports the log types it encountered.
+// Blank lines are skipped.
+func (c *Corelight) Lint(r io.Reader) (cl CorelightLint, err error) {
+	cl = CorelightLint{
+		Tags:        map[string]uint64{},
+		Disabled:    map[string]uint64{},
+		Unmapped:    map[string]uint64{},
+		Quarantined: map[string]uint64{},
+		DefaultTag:  c.defaultTag(),
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxLintLineSize)
+	for sc.Scan() {
+		if ln := bytes.TrimSpace(sc.Bytes()); len(ln) > 0 {
+			c.lintLine(&cl, ln)
+		}
+	}
+	err = sc.Err()
+	return
+}
+
+// lintLine classifies a single record the same way Process would
+func (c *Corelight) lintLine(cl *CorelightLint, ln []byte) {
+	cl.Records++
+	if c.Max_Entry_Size > 0 && uint64(len(ln)) > c.Max_Entry_Size {
+		cl.Oversized++
+		return
+	}
+	if tag, _, line, _ := c.processLine(ln); tag != noTag {
+		if c.skip[tag] {
+			cl.Disabled[strings.TrimPrefix(tag, c.Prefix)]++
+			return
+		} else if tv, ok := c.tags[tag]; ok {
+			if cs, ok := c.schema[tag]; ok && !c.matchSchema(cs, line) {
+				cl.Quarantined[strings.TrimPrefix(tag, c.Prefix)]++
+				return
+			}
+			if name, ok := c.tg.LookupTag(tv); ok {
+				tag = name
+			}
+			cl.Tags[tag]++
+			return
+		}
+	}
+	if path, ok := c.unmappedPath(ln); ok {
+		cl.Unmapped[path]++
+	} else {
+		cl.Failed++
+	}
+}
+
+// unmappedPath returns the log type of a record if it names one which has no format
+func (c *Corelight) unmappedPath(ln []byte) (path string, ok bool) {
+	if idx := bytes.IndexByte(ln, '{'); idx == -1 {
+		return
+	} else {
+		ln = ln[idx:]
+	}
+	mp := map[string]interface{}{}
+	if err := json.Unmarshal(ln, &mp); err != nil {
+		return
+	}
+	if c.ciNames != nil {
+		mp = c.foldKeys(mp, ln)
+	}
+	var v interface{}
+	if v, ok = lookupField(mp, c.Path_Field); !ok {
+		return
+	} else if path, ok = v.(string); !ok {
+		return
+	}
+	tag := c.pathTag(path)
+	if _, known := c.tagFields[tag]; known || c.skip[tag] {
+		ok = false
+	}
+	return
+}
@@ -0,0 +1,275 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gravwell/jsonparser"
+)
+
+const (
+	// integers with no more digits than this are exact as a float64, so the
+	// streaming path can write them as is and match the decoded output
+	maxExactIntDigits = 15
+	unescapeBufSize   = 256
+)
+
+// errNotStreamable stops the scan of a record that must be converted by the map path
+var errNotStreamable = errors.New("record cannot be streamed")
+
+// corelightValue is a raw JSON value pulled out of a record by the streaming path
+type corelightValue struct {
+	raw []byte
+	vt  jsonparser.ValueType
+}
+
+// initStream decides whether records can be converted without decoding them into a map and
+// precomputes the key paths to extract for each log type. Anything that needs the whole
+// record (appended unknown fields, overflow columns, logtype injection, case-insensitive fields) or nested
+// Path/TS fields is left to the map path, as are GeoIP annotation and records with nested objects,
+// which are checked per record.
+func (c *Corelight) initStream() {
+	c.stream = false
+	c.streamPaths = nil
+	if c.Append_Unknown_Fields || c.Overflow_Column || c.Inject_Logtype_Field != `` || c.Case_Insensitive_Fields {
+		return
+	} else if strings.Contains(c.Path_Field, ".") || strings.Contains(c.TS_Field, ".") ||
+		strings.Contains(c.Source_From_Field, ".") {
+		return
+	}
+	c.stream = true
+	c.fieldSep = []byte(c.Field_Separator)
+	c.metaPaths = [][]string{{c.Path_Field}, {c.TS_Field}}
+	c.writeTSIdx, c.srcIdx = -1, -1
+	if c.Use_Write_TS {
+		c.writeTSIdx = len(c.metaPaths)
+		c.metaPaths = append(c.metaPaths, []string{corelightWriteTSField})
+	}
+	if c.Source_From_Field != `` {
+		c.srcIdx = len(c.metaPaths)
+		c.metaPaths = append(c.metaPaths, []string{c.Source_From_Field})
+	}
+	c.streamPaths = make(map[string][][]string, len(c.tagFields))
+	for tag, headers := range c.tagFields {
+		paths := make([][]string, 0, len(headers)-1)
+		for _, h := range headers[1:] { //the TS is written from the parsed timestamp
+			paths = append(paths, []string{h})
+		}
+		c.streamPaths[tag] = paths
+	}
+}
+
+// processStream converts a record by extracting just the keys the log type needs rather than
+// decoding the whole record. If the record contains anything the streaming path cannot render
+// exactly as the map path would, ok is false and the caller must fall back to the map path.
+func (c *Corelight) processStream(og []byte) (tag string, ts time.Time, line []byte, cm corelightMeta, ok bool) {
+	if !json.Valid(og) {
+		return //let the map path reject it
+	} else if !streamable(og) {
+		return
+	}
+	var meta [4]corelightValue
+	jsonparser.EachKey(og, func(idx int, v []byte, vt jsonparser.ValueType, err error) {
+		if err == nil {
+			meta[idx] = corelightValue{raw: v, vt: vt}
+		}
+	}, c.metaPaths...)
+	if meta[0].vt != jsonparser.String || !plainString(meta[0].raw) {
+		return
+	} else if ts, ok = c.parseRawTs(meta[1]); !ok {
+		return
+	} else if cm, ok = c.rawMeta(meta); !ok {
+		return
+	}
+	tag = c.pathTag(string(meta[0].raw))
+	var headers []string
+	if c.skip[tag] {
+		line = og // disabled log types are left as is
+	} else if headers, ok = c.tagFields[tag]; !ok {
+		tag = noTag
+		line, ok = og, true
+	} else if c.Format == corelightFormatJSON {
+		line = og
+	} else {
+		line, ok = c.emitStream(ts, headers, c.streamPaths[tag], og)
+	}
+	return
+}
+
+// rawMeta is the streaming equivalent of getMeta, ok is false if a value needs the map path to decode it
+func (c *Corelight) rawMeta(meta [4]corelightValue) (cm corelightMeta, ok bool) {
+	if c.writeTSIdx != -1 {
+		v := meta[c.writeTSIdx]
+		if v.vt == jsonparser.String && !plainString(v.raw) {
+			return
+		}
+		if ts, tok := c.parseRawTs(v); tok {
+			cm.writeTs = ts
+		}
+	}
+	if c.srcIdx != -1 {
+		if v := meta[c.srcIdx]; v.vt == jsonparser.String {
+			if !plainString(v.raw) {
+				return
+			}
+			cm.src = string(v.raw)
+		}
+	}
+	ok = true
+	return
+}
+
+// parseRawTs is the streaming equivalent of parseTs
+func (c *Corelight) parseRawTs(v corelightValue) (ts time.Time, ok bool) {
+	return c.te.ExtractRaw(v.raw, v.vt)
+}
+
+func (c *Corelight) emitStream(ts time.Time, headers []string, paths [][]string, og []byte) (line []byte, ok bool) {
+	vals := make([]corelightValue, len(paths))
+	jsonparser.EachKey(og, func(idx int, v []byte, vt jsonparser.ValueType, err error) {
+		if err == nil {
+			vals[idx] = corelightValue{raw: v, vt: vt}
+		}
+	}, paths...)
+	bb := bytes.NewBuffer(make([]byte, 0, len(og)))
+	c.writeEpoch(bb, ts)
+	for i, h := range headers[1:] {
+		bb.WriteString(c.Field_Separator)
+		if vals[i].vt == jsonparser.NotExist {
+			bb.WriteString(c.Empty_Field_Marker)
+		} else if !c.writeRawValue(bb, vals[i].raw, vals[i].vt, c.precision(h)) {
+			return
+		}
+	}
+	line, ok = bb.Bytes(), true
+	return
+}
+
+// writeRawValue formats a raw JSON value exactly as writeValue formats its decoded form,
+// returning false for values that it cannot, such as nulls and objects.
+func (c *Corelight) writeRawValue(bb *bytes.Buffer, raw []byte, vt jsonparser.ValueType, prec int) (ok bool) {
+	switch vt {
+	case jsonparser.Number:
+		if exactInt(raw) {
+			bb.Write(raw)
+		} else if f, err := strconv.ParseFloat(string(raw), 64); err == nil {
+			c.writeValue(bb, f, prec)
+		} else {
+			return false
+		}
+	case jsonparser.String:
+		if bytes.IndexByte(raw, '\\') != -1 {
+			if bytes.Contains(raw, []byte(`\u`)) {
+				return false //leave surrogates and replacement characters to encoding/json
+			}
+			var buf [unescapeBufSize]byte
+			var err error
+			if raw, err = jsonparser.Unescape(raw, buf[:]); err != nil {
+				return false
+			}
+		}
+		if !utf8.Valid(raw) {
+			return false
+		}
+		c.writeRawString(bb, raw)
+	case jsonparser.Boolean:
+		bb.Write(raw)
+	case jsonparser.Array:
+		var n int
+		ok = true
+		jsonparser.ArrayEach(raw, func(v []byte, evt jsonparser.ValueType, _ int, err error) {
+			if !ok {
+				return
+			} else if err != nil {
+				ok = false
+				return
+			}
+			if n > 0 {
+				bb.WriteString(c.Set_Separator)
+			}
+			n++
+			ok = c.writeRawValue(bb, v, evt, prec)
+		})
+		if ok && n == 0 {
+			bb.WriteString(c.Empty_Field_Marker)
+		}
+		return
+	default:
+		return false
+	}
+	return true
+}
+
+// writeRawString writes a string value, replacing the field separator so it cannot split the field
+func (c *Corelight) writeRawString(bb *bytes.Buffer, v []byte) {
+	for len(c.fieldSep) > 0 {
+		idx := bytes.Index(v, c.fieldSep)
+		if idx == -1 {
+			break
+		}
+		bb.Write(v[:idx])
+		bb.WriteByte(' ')
+		v = v[idx+len(c.fieldSep):]
+	}
+	bb.Write(v)
+}
+
+// streamable returns whether the top level keys of a record can be extracted by the streaming path.
+// Nested objects are left to the map path so that dotted headers resolve into them through lookupField,
+// as are duplicate keys so that the last occurrence wins just as it does when decoding into a map.
+// Escaped keys are compared after unescaping by the decoder, so they are left to the map path too.
+func streamable(og []byte) bool {
+	//records have few enough keys that a linear scan beats hashing them
+	keys := make([][]byte, 0, 64)
+	err := jsonparser.ObjectEach(og, func(k, _ []byte, vt jsonparser.ValueType, _ int) error {
+		if vt == jsonparser.Object || bytes.IndexByte(k, '\\') != -1 {
+			return errNotStreamable
+		}
+		for _, prev := range keys {
+			if bytes.Equal(prev, k) {
+				return errNotStreamable
+			}
+		}
+		keys = append(keys, k)
+		return nil
+	})
+	return err == nil
+}
+
+// plainString returns whether a raw JSON string needs no unescaping or UTF-8 replacement
+func plainString(v []byte) bool {
+	return bytes.IndexByte(v, '\\') == -1 && utf8.Valid(v)
+}
+
+// exactInt returns whether a raw JSON number is an integer that prints the same after
+// a round trip through a float64
+func exactInt(v []byte) bool {
+	digits := v
+	if len(digits) > 0 && digits[0] == '-' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || len(digits) > maxExactIntDigits {
+		return false
+	} else if len(v) != len(digits) && len(digits) == 1 && digits[0] == '0' {
+		return false // -0 prints as 0
+	}
+	for _, b := range digits {
+		if b < '0' || b > '9' {
+			return false
+		}
+	}
+	return true
+}
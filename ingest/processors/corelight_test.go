@@ -1,8 +1,18 @@
 package processors
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/crewjam/rfc5424"
+	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 )
 
@@ -167,6 +177,9 @@ var corelightTestData = []testCheck{
 	testCheck{tag: `zeeksmb_files`, input: smb_files1_in, output: smb_files1_out},
 	testCheck{tag: `zeektunnel`, input: tunnel1_in, output: tunnel1_out},
 	testCheck{tag: `zeeksoftware`, input: software1_in, output: software1_out},
+	testCheck{tag: `zeekdce_rpc`, input: dce_rpc1_in, output: dce_rpc1_out},
+	testCheck{tag: `zeekntlm`, input: ntlm1_in, output: ntlm1_out},
+	testCheck{tag: `zeeksmb_cmd`, input: smb_cmd1_in, output: smb_cmd1_out},
 }
 
 // try overriding the x509 parser, make sure overrides work
@@ -202,34 +215,1785 @@ func TestCorelightOverride(t *testing.T) {
 	}
 }
 
-func BenchmarkCorelightDecode(b *testing.B) {
-	str := `
+func TestCorelightSeparators(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Field-Separator=""
+	`
+	if _, err := testLoadPreprocessor(b, `corelight`); err == nil {
+		t.Fatal("failed to catch empty field separator")
+	}
+
+	b = `
 	[preprocessor "corelight"]
 		type = corelight
 		Custom-Format="foobar:ts,this,that,the,other"
-		Custom-Format="barbaz:just, one,more , thing "
+		Field-Separator="|"
+		Empty-Field-Marker="(empty)"
 	`
-	p, err := testLoadPreprocessor(str, `corelight`)
+	p, err := testLoadPreprocessor(b, `corelight`)
 	if err != nil {
-		b.Fatal(err)
+		t.Fatal(err)
 	}
-	//cast to the corelight processor
 	c, ok := p.(*Corelight)
 	if !ok {
-		b.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	output := "1600266221.005323|hello|m y|3.14000|(empty)"
+	ent := entry.Entry{
+		Data: []byte(strings.Replace(foobar1_in, `"my"`, `"m|y"`, 1)),
+	}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	} else if string(ents[0].Data) != output {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), output)
 	}
-	b.ResetTimer()
 
-	for i := 0; i < b.N; {
-		for j, v := range corelightTestData {
-			var ent entry.Entry
-			ent.Data = []byte(v.input)
-			if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
-				b.Fatalf("failed to process %d: %v\n", j, err)
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Set-Separator=";"
+	`
+	if p, err = testLoadPreprocessor(b, `corelight`); err != nil {
+		t.Fatal(err)
+	} else if c, ok = p.(*Corelight); !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	output = strings.Replace(smtp1_out, "74.53.140.153,10.10.1.4", "74.53.140.153;10.10.1.4", 1)
+	ent = entry.Entry{
+		Data: []byte(smtp1_in),
+	}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	} else if string(ents[0].Data) != output {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), output)
+	}
+}
+
+func TestNewCorelightDefaults(t *testing.T) {
+	// configs built in code get the same defaults as a loaded config
+	var tt testTagger
+	c, err := NewCorelight(CorelightConfig{}, &tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Field_Separator != defaultFieldSeparator || c.Set_Separator != defaultSetSeparator ||
+		c.Empty_Field_Marker != defaultEmptyFieldMarker || c.Prefix != defaultPrefix {
+		t.Fatalf("defaults were not applied: %+v", c.CorelightConfig)
+	}
+	ents, err := c.Process([]*entry.Entry{{Data: []byte(smtp1_in)}})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	} else if string(ents[0].Data) != smtp1_out {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", ents[0].Data, smtp1_out)
+	}
+}
+
+func TestCorelightReconfigure(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this,that,the,other"
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	cfg := c.CorelightConfig
+	cfg.Field_Separator = "|"
+	cfg.Empty_Field_Marker = "(empty)"
+	if err = c.Config(cfg, c.tg); err != nil {
+		t.Fatal(err)
+	} else if c.Field_Separator != "|" {
+		t.Fatalf("reconfigured separator was not applied: %q", c.Field_Separator)
+	}
+	output := "1600266221.005323|hello|my|3.14000|(empty)"
+	for _, stream := range []bool{true, false} {
+		c.stream = stream
+		ents, err := c.Process([]*entry.Entry{{Data: []byte(foobar1_in)}})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatal(`too many entries came out`)
+		} else if string(ents[0].Data) != output {
+			t.Fatalf("Output mismatch (stream %v):\n%s\n%s\n", stream, string(ents[0].Data), output)
+		}
+	}
+}
+
+func TestCorelightJSONFormat(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Format=JSON
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	var ent entry.Entry
+	for i, v := range corelightTestData {
+		if v.tag == `zeekfoobar` {
+			continue //custom format not loaded
+		}
+		ent.Data = []byte(v.input)
+		ent.TS = entry.Timestamp{}
+		if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatalf("failed to process %d: %v\n", i, err)
+		} else if len(ents) != 1 {
+			t.Fatal(`too many entries came out`)
+		} else if string(ents[0].Data) != v.input {
+			t.Fatalf("JSON was modified %d:\n%s\n%s\n", i, string(ents[0].Data), v.input)
+		} else if tn, ok := c.tg.LookupTag(ents[0].Tag); !ok {
+			t.Fatal("failed to lookup tag")
+		} else if tn != v.tag {
+			t.Fatalf("invalid tag: %v != %v", tn, v.tag)
+		} else if ts := strings.SplitN(v.output, "\t", 2)[0]; ts != fmt.Sprintf("%.6f", float64(ents[0].TS.StandardTime().UnixNano())/1000000000.0) {
+			t.Fatalf("invalid timestamp %d: %v != %v", i, ents[0].TS, ts)
+		}
+	}
+
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Format=xml
+	`
+	if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+		t.Fatal("failed to catch bad format")
+	}
+}
+
+func TestCorelightEpochTimestamp(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	ent := entry.Entry{
+		Data: []byte(strings.Replace(conn1_in, `"2020-08-16T06:26:03.553287Z"`, `1597559163.553287`, 1)),
+	}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	} else if string(ents[0].Data) != conn1_out {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), conn1_out)
+	} else if ts := ents[0].TS.StandardTime(); !ts.Equal(time.Date(2020, 8, 16, 6, 26, 3, 553287000, time.UTC)) {
+		t.Fatalf("invalid timestamp: %v", ts)
+	}
+}
+
+// zeekConnColumns are the columns of Zeek's conn.log, followed by the vlan column Corelight adds
+var zeekConnColumns = []string{
+	"ts", "uid", "id.orig_h", "id.orig_p", "id.resp_h", "id.resp_p", "proto", "service", "duration",
+	"orig_bytes", "resp_bytes", "conn_state", "local_orig", "local_resp", "missed_bytes", "history",
+	"orig_pkts", "orig_ip_bytes", "resp_pkts", "resp_ip_bytes", "tunnel_parents", "vlan",
+}
+
+func TestCorelightConnColumns(t *testing.T) {
+	if hdr := tagHeaders["conn"]; hdr != strings.Join(zeekConnColumns, ",") {
+		t.Fatalf("conn header does not match Zeek's conn.log:\n%s\n%s", hdr, strings.Join(zeekConnColumns, ","))
+	}
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	ent := entry.Entry{
+		Data: []byte(strings.Replace(conn2_in, `"resp_ip_bytes": 511`,
+			`"resp_ip_bytes": 511, "local_orig": true, "local_resp": false, "tunnel_parents": ["CHhAvVGS1DHFjwGM9"], "vlan": 10`, 1)),
+	}
+	ents, err := c.Process([]*entry.Entry{&ent})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	}
+	fields := strings.Split(string(ents[0].Data), "\t")
+	if len(fields) != len(zeekConnColumns) {
+		t.Fatalf("conn record has %d columns, expected %d: %q", len(fields), len(zeekConnColumns), ents[0].Data)
+	}
+	want := map[string]string{
+		"orig_bytes": "77", "resp_bytes": "295",
+		"orig_pkts": "6", "orig_ip_bytes": "397", "resp_pkts": "4", "resp_ip_bytes": "511",
+		"tunnel_parents": "CHhAvVGS1DHFjwGM9", "vlan": "10",
+	}
+	for i, col := range zeekConnColumns {
+		if v, ok := want[col]; ok && fields[i] != v {
+			t.Errorf("bad %s: %q != %q", col, fields[i], v)
+		}
+	}
+}
+
+// zeekSIPColumns are the columns of Zeek's sip.log
+var zeekSIPColumns = []string{
+	"ts", "uid", "id.orig_h", "id.orig_p", "id.resp_h", "id.resp_p", "trans_depth", "method", "uri",
+	"date", "request_from", "request_to", "response_from", "response_to", "reply_to", "call_id", "seq",
+	"subject", "request_path", "response_path", "user_agent", "status_code", "status_msg", "warning",
+	"request_body_len", "response_body_len", "content_type",
+}
+
+const sip1_in = `{"_path":"sip","ts":"2020-08-16T06:26:03.553287Z","uid":"CxBNaD2Oz1D5jWX4Xb",` +
+	`"id.orig_h":"192.168.4.76","id.orig_p":5060,"id.resp_h":"192.168.4.1","id.resp_p":5060,` +
+	`"trans_depth":0,"method":"INVITE","uri":"sip:bob@example.com",` +
+	`"request_from":"\"Alice\" <sip:alice@example.com>","request_to":"<sip:bob@example.com>",` +
+	`"response_from":"\"Alice\" <sip:alice@example.com>","response_to":"<sip:bob@example.com>;tag=1",` +
+	`"call_id":"a84b4c76e66710","seq":"314159 INVITE","request_path":["SIP/2.0/UDP 192.168.4.76:5060"],` +
+	`"response_path":["SIP/2.0/UDP 192.168.4.76:5060"],"user_agent":"softphone","status_code":200,` +
+	`"status_msg":"OK","request_body_len":142,"response_body_len":131,"content_type":"application/sdp"}`
+
+func TestCorelightSIPColumns(t *testing.T) {
+	if hdr := tagHeaders["sip"]; hdr != strings.Join(zeekSIPColumns, ",") {
+		t.Fatalf("sip header does not match Zeek's sip.log:\n%s\n%s", hdr, strings.Join(zeekSIPColumns, ","))
+	}
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	ents, err := c.Process([]*entry.Entry{{Data: []byte(sip1_in)}})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	}
+	fields := strings.Split(string(ents[0].Data), "\t")
+	if len(fields) != len(zeekSIPColumns) {
+		t.Fatalf("sip record has %d columns, expected %d: %q", len(fields), len(zeekSIPColumns), ents[0].Data)
+	}
+	want := map[string]string{
+		"method": "INVITE", "date": "-", "request_from": `"Alice" <sip:alice@example.com>`,
+		"request_to": "<sip:bob@example.com>", "response_to": "<sip:bob@example.com>;tag=1", "reply_to": "-",
+		"call_id": "a84b4c76e66710", "status_code": "200", "content_type": "application/sdp",
+	}
+	for i, col := range zeekSIPColumns {
+		if v, ok := want[col]; ok && fields[i] != v {
+			t.Errorf("bad %s: %q != %q", col, fields[i], v)
+		}
+	}
+}
+
+func TestCorelightNestedFields(t *testing.T) {
+	// upstream Zeek keeps the connection tuple as a nested "id" object
+	nested := strings.Replace(sip1_in,
+		`"id.orig_h":"192.168.4.76","id.orig_p":5060,"id.resp_h":"192.168.4.1","id.resp_p":5060,`,
+		`"id":{"orig_h":"192.168.4.76","orig_p":5060,"resp_h":"192.168.4.1","resp_p":5060},`, 1)
+	if nested == sip1_in {
+		t.Fatal("failed to build nested input")
+	}
+	want := map[string]string{
+		"id.orig_h": "192.168.4.76", "id.orig_p": "5060", "id.resp_h": "192.168.4.1", "id.resp_p": "5060",
+	}
+	// the default configuration streams records, appending unknown fields decodes them
+	for _, opt := range []string{``, `Append-Unknown-Fields=true`} {
+		b := `
+		[preprocessor "corelight"]
+			type = corelight
+			` + opt + `
+		`
+		p, err := testLoadPreprocessor(b, `corelight`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, ok := p.(*Corelight)
+		if !ok {
+			t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+		} else if c.stream != (opt == ``) {
+			t.Fatalf("bad streaming state with %q: %v", opt, c.stream)
+		}
+		var out [2][]byte
+		for i, in := range []string{sip1_in, nested} {
+			ents, err := c.Process([]*entry.Entry{{Data: []byte(in)}})
+			if err != nil {
+				t.Fatal(err)
 			} else if len(ents) != 1 {
-				b.Fatal(`too many entries came out`)
+				t.Fatal(`too many entries came out`)
 			}
-			i += 1
+			out[i] = ents[0].Data
+		}
+		// appending unknown fields adds a trailing column
+		cols := len(zeekSIPColumns)
+		if opt != `` {
+			cols++
+		}
+		fields := strings.Split(string(out[1]), "\t")
+		if len(fields) != cols {
+			t.Fatalf("sip record has %d columns, expected %d: %q", len(fields), cols, out[1])
+		}
+		for i, col := range zeekSIPColumns {
+			if v, ok := want[col]; ok && fields[i] != v {
+				t.Errorf("%q: bad %s: %q != %q", opt, col, fields[i], v)
+			}
+		}
+		// the nested object is consumed by the headers rather than appended as unknown
+		if string(out[0]) != string(out[1]) {
+			t.Fatalf("%q: nested and flattened output differ:\n%s\n%s", opt, out[0], out[1])
+		}
+	}
+
+	// members of a nested object which are not headers are still appended
+	p, err := testLoadPreprocessor(`
+	[preprocessor "corelight"]
+		type = corelight
+		Append-Unknown-Fields=true
+	`, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vlan := strings.Replace(nested, `"resp_p":5060}`, `"resp_p":5060,"vlan":7}`, 1)
+	ents, err := p.Process([]*entry.Entry{{Data: []byte(vlan)}})
+	if err != nil {
+		t.Fatal(err)
+	} else if fields := strings.Split(string(ents[0].Data), "\t"); fields[len(fields)-1] != `id="{\"vlan\":7}"` {
+		t.Fatalf("nested member was not appended: %q", fields[len(fields)-1])
+	}
+}
+
+func TestCorelightAppendUnknown(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this,that"
+		Custom-Format="barbaz:ts,this,that,the"
+		Append-Unknown-Fields=true
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{input: foobar1_in, output: "1600266221.005323\thello\tmy\tthe=3.14000"},
+		{input: strings.Replace(foobar1_in, `"foobar"`, `"barbaz"`, 1), output: "1600266221.005323\thello\tmy\t3.14000\t-"},
+		{input: conn1_in, output: conn1_out + "\t_node=worker-01 _system_name=ds61 _write_ts=2020-08-16T06:26:04.077276Z"},
+		// nested objects are compact JSON, values with spaces or equals signs are quoted
+		{
+			input:  strings.Replace(foobar1_in, `"the": 3.14`, `"extra": {"a": 1, "b": "x y"}, "note": "two words", "kv": "a=b", "none": ""`, 1),
+			output: "1600266221.005323\thello\tmy\t" + `extra="{\"a\":1,\"b\":\"x y\"}" kv="a=b" none="" note="two words"`,
+		},
+		{
+			input:  strings.Replace(foobar1_in, `"the": 3.14`, `"list": [{"a": 1}], "flat": [1, 2]`, 1),
+			output: "1600266221.005323\thello\tmy\t" + `flat=1,2 list="[{\"a\":1}]"`,
+		},
+	}
+	for i, tst := range tests {
+		ent := entry.Entry{
+			Data: []byte(tst.input),
+		}
+		if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatal(`too many entries came out`)
+		} else if string(ents[0].Data) != tst.output {
+			t.Fatalf("Output mismatch %d:\n%s\n%s\n", i, string(ents[0].Data), tst.output)
+		}
+	}
+}
+
+func TestCorelightOverflowColumn(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this,that"
+		Custom-Format="barbaz:ts,this,that,the"
+		Overflow-Column=true
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	} else if c.stream {
+		t.Fatal("overflow column must use the map path")
+	}
+	meta := `"_node":"worker-01","_system_name":"ds61","_write_ts":"2020-08-16T06:26:04.077276Z"`
+	// members of a nested object that are not headers are kept in the overflow
+	nested := strings.Replace(conn1_in,
+		`"id.orig_h": "192.168.4.76",`,
+		`"id": {"orig_h": "192.168.4.76", "vlan": 5}, "note": "<a&b>",`, 1)
+	if nested == conn1_in {
+		t.Fatal("failed to build nested input")
+	}
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{input: foobar1_in, output: "1600266221.005323\thello\tmy\t{\"the\":3.14}"},
+		{input: strings.Replace(foobar1_in, `"foobar"`, `"barbaz"`, 1), output: "1600266221.005323\thello\tmy\t3.14000\t-"},
+		{input: conn1_in, output: conn1_out + "\t{" + meta + "}"},
+		{input: nested, output: conn1_out + "\t{" + meta + `,"id":{"vlan":5},"note":"<a&b>"}`},
+	}
+	for i, tst := range tests {
+		ent := entry.Entry{
+			Data: []byte(tst.input),
+		}
+		if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatal(`too many entries came out`)
+		} else if string(ents[0].Data) != tst.output {
+			t.Fatalf("Output mismatch %d:\n%s\n%s\n", i, string(ents[0].Data), tst.output)
+		}
+	}
+
+	bad := []string{
+		`Format=json`,
+		`Append-Unknown-Fields=true`,
+		`Field-Separator="|"`,
+	}
+	for _, v := range bad {
+		b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Overflow-Column=true
+		` + v + "\n"
+		if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad overflow config %s", v)
+		}
+	}
+}
+
+func TestCorelightStatsErrorTag(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Error-Tag=zeekerror
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	inputs := []string{
+		conn1_in,
+		`not json at all`,
+		`{"broken": `,
+		strings.Replace(conn1_in, `"conn"`, `"unknownlog"`, 1),
+		dns1_in,
+	}
+	ents := make([]*entry.Entry, 0, len(inputs))
+	for _, v := range inputs {
+		ents = append(ents, &entry.Entry{Data: []byte(v)})
+	}
+	if ents, err = c.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	st := c.Stats()
+	if st.Processed != 5 || st.Converted != 2 || st.Failed != 3 {
+		t.Fatalf("invalid stats: %+v", st)
+	}
+	for i, ent := range ents[1:4] {
+		if tn, ok := c.tg.LookupTag(ent.Tag); !ok || tn != `zeekerror` {
+			t.Fatalf("entry %d not retagged to error tag: %v", i+1, tn)
+		}
+	}
+
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Error-Tag="bad tag"
+	`
+	if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+		t.Fatal("failed to catch bad error tag")
+	}
+}
+
+func TestCorelightDefaultTag(t *testing.T) {
+	// two instances share a tagger but only one applies a default tag
+	b := `
+	[preprocessor "with"]
+		type = corelight
+		Default-Tag=zeekunknown
+	[preprocessor "without"]
+		type = corelight
+	`
+	var tc testConfigStruct
+	if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	var tt testTagger
+	orig, _ := tt.NegotiateTag(`syslog`)
+	p, err := tc.Preprocessor.getProcessor(`with`, &tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withDefault := p.(*Corelight)
+	if p, err = tc.Preprocessor.getProcessor(`without`, &tt); err != nil {
+		t.Fatal(err)
+	}
+	without := p.(*Corelight)
+	unknown, ok := tt.mp[`zeekunknown`]
+	if !ok {
+		t.Fatal("default tag was not negotiated")
+	}
+	inputs := []string{
+		`not json at all`,
+		strings.Replace(conn1_in, `"conn"`, `"unknownlog"`, 1),
+		conn1_in,
+	}
+	for _, tst := range []struct {
+		c    *Corelight
+		tags []entry.EntryTag
+	}{
+		{withDefault, []entry.EntryTag{unknown, unknown, tt.mp[`zeekconn`]}},
+		{without, []entry.EntryTag{orig, orig, tt.mp[`zeekconn`]}},
+	} {
+		var ents []*entry.Entry
+		for _, v := range inputs {
+			ents = append(ents, &entry.Entry{Tag: orig, Data: []byte(v)})
+		}
+		if ents, err = tst.c.Process(ents); err != nil {
+			t.Fatal(err)
+		}
+		for i, ent := range ents {
+			if ent.Tag != tst.tags[i] {
+				t.Fatalf("entry %d has bad tag %d != %d", i, ent.Tag, tst.tags[i])
+			}
+		}
+	}
+
+	// reconfiguring without a default tag leaves unconverted entries alone
+	cfg := without.CorelightConfig
+	if err = withDefault.Config(cfg, &tt); err != nil {
+		t.Fatal(err)
+	}
+	ents := []*entry.Entry{{Tag: orig, Data: []byte(`not json at all`)}}
+	if ents, err = withDefault.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if ents[0].Tag != orig {
+		t.Fatalf("entry was retagged after reconfigure: %d", ents[0].Tag)
+	}
+
+	for _, v := range []string{`Default-Tag="bad tag"`, "Default-Tag=zeekunknown\nError-Tag=zeekerror"} {
+		b = `
+		[preprocessor "corelight"]
+			type = corelight
+			` + v + `
+		`
+		if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad config %q", v)
+		}
+	}
+}
+
+func TestCorelightIndependentPrefixes(t *testing.T) {
+	b := `
+	[preprocessor "zeek"]
+		type = corelight
+	[preprocessor "sensor2"]
+		type = corelight
+		Prefix=corelight
+		Default-Tag=sensor2unknown
+	`
+	var tc testConfigStruct
+	if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	var tt testTagger
+	var procs []*Corelight
+	for _, name := range []string{`zeek`, `sensor2`} {
+		p, err := tc.Preprocessor.getProcessor(name, &tt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		procs = append(procs, p.(*Corelight))
+	}
+	if procs[0].Prefix != defaultPrefix || procs[1].Prefix != `corelight` {
+		t.Fatalf("bad prefixes %q %q", procs[0].Prefix, procs[1].Prefix)
+	}
+	expect := [][2]string{
+		{`zeekconn`, `zeekdns`},
+		{`corelightconn`, `corelightdns`},
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(procs))
+	for i, c := range procs {
+		wg.Add(1)
+		go func(c *Corelight, exp [2]string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ents := []*entry.Entry{{Data: []byte(conn1_in)}, {Data: []byte(dns1_in)}, {Data: []byte(`not json`)}}
+				ents, err := c.Process(ents)
+				if err != nil {
+					errs <- err
+					return
+				}
+				for k, tn := range exp {
+					if name, _ := tt.LookupTag(ents[k].Tag); name != tn {
+						errs <- fmt.Errorf("bad tag %q != %q", name, tn)
+						return
+					}
+				}
+			}
+		}(c, expect[i])
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	// only the second instance has a default tag
+	if _, ok := tt.mp[`sensor2unknown`]; !ok {
+		t.Fatal("default tag was not negotiated")
+	} else if procs[0].retag || !procs[1].retag {
+		t.Fatal("default tag leaked between instances")
+	}
+}
+
+func TestCorelightFloatPrecision(t *testing.T) {
+	input := strings.Replace(foobar1_in, `"the": 3.14`, `"the": 3.14159265, "other": 2.718281828`, 1)
+	tests := []struct {
+		cfg    string
+		output string
+	}{
+		{cfg: ``, output: "1600266221.005323\thello\tmy\t3.14159\t2.71828"},
+		{cfg: `Float-Precision=2`, output: "1600266221.005323\thello\tmy\t3.14\t2.72"},
+		{cfg: `Float-Precision=0`, output: "1600266221.005323\thello\tmy\t3\t3"},
+		{cfg: "Float-Precision=2\nField-Float-Precision=\"the:7\"", output: "1600266221.005323\thello\tmy\t3.1415927\t2.72"},
+	}
+	for i, tst := range tests {
+		b := `
+		[preprocessor "corelight"]
+			type = corelight
+			Custom-Format="foobar:ts,this,that,the,other"
+			` + tst.cfg
+		p, err := testLoadPreprocessor(b, `corelight`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, ok := p.(*Corelight)
+		if !ok {
+			t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+		}
+		ent := entry.Entry{
+			Data: []byte(input),
+		}
+		if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatal(`too many entries came out`)
+		} else if string(ents[0].Data) != tst.output {
+			t.Fatalf("Output mismatch %d:\n%s\n%s\n", i, string(ents[0].Data), tst.output)
+		}
+	}
+
+	// configs built in code default to 5 decimals unless CorelightNoDecimals is given
+	var tt testTagger
+	for prec, output := range map[int]string{
+		0:                   tests[0].output,
+		CorelightNoDecimals: tests[2].output,
+	} {
+		c, err := NewCorelight(CorelightConfig{Custom_Format: []string{`foobar:ts,this,that,the,other`}, Float_Precision: prec}, &tt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ents, err := c.Process([]*entry.Entry{{Data: []byte(input)}})
+		if err != nil {
+			t.Fatal(err)
+		} else if string(ents[0].Data) != output {
+			t.Fatalf("Output mismatch with precision %d:\n%s\n%s\n", prec, ents[0].Data, output)
+		}
+	}
+
+	//check bad precision values
+	for _, v := range []string{`Float-Precision=-1`, `Float-Precision=100`, `Field-Float-Precision="the"`, `Field-Float-Precision="the:x"`} {
+		b := `
+		[preprocessor "corelight"]
+			type = corelight
+			` + v
+		if _, err := testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad precision %q", v)
+		}
+	}
+}
+
+func TestCorelightTimestampPrecision(t *testing.T) {
+	input := strings.Replace(foobar1_in, `41.005323Z`, `41.005323789Z`, 1)
+	tests := []struct {
+		cfg    string
+		output string
+	}{
+		{cfg: ``, output: "1600266221.005324\thello\tmy\t3.14000"},
+		{cfg: `Timestamp-Precision=3`, output: "1600266221.005\thello\tmy\t3.14000"},
+		{cfg: `Timestamp-Precision=9`, output: "1600266221.005323789\thello\tmy\t3.14000"},
+	}
+	for i, tst := range tests {
+		//records are streamed unless Case-Insensitive-Fields forces them to be decoded
+		for _, decode := range []bool{false, true} {
+			b := `
+		[preprocessor "corelight"]
+			type = corelight
+			Custom-Format="foobar:ts,this,that,the"
+			` + tst.cfg
+			if decode {
+				b += "\nCase-Insensitive-Fields=true"
+			}
+			p, err := testLoadPreprocessor(b, `corelight`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ents, err := p.Process([]*entry.Entry{{Data: []byte(input)}})
+			if err != nil {
+				t.Fatal(err)
+			} else if len(ents) != 1 {
+				t.Fatal(`too many entries came out`)
+			} else if string(ents[0].Data) != tst.output {
+				t.Fatalf("Output mismatch %d (decode %v):\n%s\n%s\n", i, decode, string(ents[0].Data), tst.output)
+			}
+		}
+	}
+
+	//the fraction of a pre-epoch timestamp counts towards zero
+	c := &Corelight{CorelightConfig: CorelightConfig{Timestamp_Precision: 3}}
+	bb := bytes.NewBuffer(nil)
+	if c.writeEpoch(bb, time.Unix(-2, 250*int64(time.Millisecond))); bb.String() != `-1.750` {
+		t.Fatalf("bad pre-epoch timestamp %q", bb.String())
+	}
+
+	for _, v := range []string{`Timestamp-Precision=-1`, `Timestamp-Precision=4`, `Timestamp-Precision=12`} {
+		b := `
+		[preprocessor "corelight"]
+			type = corelight
+			` + v
+		if _, err := testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad precision %q", v)
+		}
+	}
+}
+
+func TestCorelightPathTSFields(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Path-Field="@metadata.path"
+		TS-Field="timestamp"
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	input := strings.Replace(conn1_in, `"_path": "conn",`, `"@metadata": {"path": "conn"},`, 1)
+	input = strings.Replace(input, `"ts":`, `"timestamp":`, 1)
+	ent := entry.Entry{
+		Data: []byte(input),
+	}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	} else if string(ents[0].Data) != conn1_out {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), conn1_out)
+	} else if tn, ok := c.tg.LookupTag(ents[0].Tag); !ok || tn != `zeekconn` {
+		t.Fatalf("invalid tag: %v", tn)
+	}
+
+	// the path and timestamp fields are not unknown fields
+	if p, err = testLoadPreprocessor(b+"\t\tAppend-Unknown-Fields=true\n", `corelight`); err != nil {
+		t.Fatal(err)
+	}
+	output := conn1_out + "\t_node=worker-01 _system_name=ds61 _write_ts=2020-08-16T06:26:04.077276Z"
+	if ents, err := p.Process([]*entry.Entry{{Data: []byte(input)}}); err != nil {
+		t.Fatal(err)
+	} else if string(ents[0].Data) != output {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), output)
+	}
+}
+
+// make sure that overriding a built-in format is logged when the tagger can log
+func TestCorelightOverrideLogged(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="x509:ts,certificate.version,certificate.subject"
+		Custom-Format="mylog:ts,uid,field1,field2"
+	`
+	var tc testConfigStruct
+	if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	var lt testLoggingTagger
+	if _, err := tc.Preprocessor.getProcessor(`corelight`, &lt); err != nil {
+		t.Fatal(err)
+	}
+	if len(lt.warns) != 1 {
+		t.Fatalf("invalid override warning count: %d %v", len(lt.warns), lt.warns)
+	} else if !strings.Contains(lt.warns[0], `x509`) {
+		t.Fatalf("invalid override warning: %q", lt.warns[0])
+	}
+}
+
+// testLoggingTagger is a testTagger that also implements ingest.IngestLogger
+type testLoggingTagger struct {
+	testTagger
+	warns []string
+}
+
+func (lt *testLoggingTagger) Errorf(f string, args ...interface{}) error { return nil }
+func (lt *testLoggingTagger) Infof(f string, args ...interface{}) error  { return nil }
+func (lt *testLoggingTagger) Warnf(f string, args ...interface{}) error {
+	lt.warns = append(lt.warns, fmt.Sprintf(f, args...))
+	return nil
+}
+func (lt *testLoggingTagger) Error(m string, args ...rfc5424.SDParam) error { return nil }
+func (lt *testLoggingTagger) Info(m string, args ...rfc5424.SDParam) error  { return nil }
+func (lt *testLoggingTagger) Warn(m string, args ...rfc5424.SDParam) error {
+	lt.warns = append(lt.warns, m)
+	return nil
+}
+
+func BenchmarkCorelightDecode(b *testing.B) {
+	str := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this,that,the,other"
+		Custom-Format="barbaz:just, one,more , thing "
+	`
+	p, err := testLoadPreprocessor(str, `corelight`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	//cast to the corelight processor
+	c, ok := p.(*Corelight)
+	if !ok {
+		b.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; {
+		for j, v := range corelightTestData {
+			var ent entry.Entry
+			ent.Data = []byte(v.input)
+			if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+				b.Fatalf("failed to process %d: %v\n", j, err)
+			} else if len(ents) != 1 {
+				b.Fatal(`too many entries came out`)
+			}
+			i += 1
+		}
+	}
+}
+
+type fakeGeo map[string]geoRecord
+
+func (f fakeGeo) lookup(ip net.IP) (cc string, asn uint, ok bool) {
+	var rec geoRecord
+	if rec, ok = f[ip.String()]; ok {
+		cc, asn = rec.Country.ISOCode, rec.ASN
+	}
+	return
+}
+
+func TestCorelightStream(t *testing.T) {
+	str := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this,that,the,other"
+	`
+	p, err := testLoadPreprocessor(str, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	} else if !c.stream {
+		t.Fatal("streaming conversion not enabled")
+	}
+	inputs := []string{
+		`{"_path":"foobar","ts":1600266221.005323,"this":"a\tb","that":"x\"y\\z","the":-0,"other":true}`,
+		`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":[],"that":[1,2.5,"c"],"the":1e3,"other":[[1],[]]}`,
+		`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":12345678901234567,"that":-42,"the":2.50,"other":false}`,
+		`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":"caf\u00e9","that":"tab	in value"}`,
+		`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":null,"that":{"a":1}}`,
+		`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":"truncated"`,
+		`{"_path":"nope","ts":"2020-09-16T14:23:41.005323Z"}`,
+		`{"_path":"foobar","ts":"not a timestamp"}`,
+		`{"_path":7,"ts":"2020-09-16T14:23:41.005323Z"}`,
+		`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":"C1","this":"C2"}`,
+		`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":"C1","th\u0069s":"C2"}`,
+		`{"_path":"nope","ts":"2020-09-16T14:23:41.005323Z","_path":"foobar"}`,
+	}
+	for _, v := range corelightTestData {
+		inputs = append(inputs, v.input)
+	}
+	for i, in := range inputs {
+		c.stream = true
+		stag, sts, sline, _ := c.processLine([]byte(in))
+		c.stream = false
+		mtag, mts, mline, _ := c.processLine([]byte(in))
+		if stag != mtag || !sts.Equal(mts) || string(sline) != string(mline) {
+			t.Fatalf("streaming mismatch %d:\n%s %v %q\n%s %v %q", i, stag, sts, sline, mtag, mts, mline)
+		}
+	}
+
+	// the last of several duplicate keys wins on both paths
+	for _, stream := range []bool{true, false} {
+		c.stream = stream
+		if _, _, line, _ := c.processLine([]byte(`{"_path":"foobar","ts":1600266221,"this":"C1","this":"C2"}`)); !strings.Contains(string(line), "\tC2\t") {
+			t.Fatalf("duplicate key (stream %v) did not keep the last value: %q", stream, line)
+		}
+	}
+
+	// options that need the whole record disable streaming
+	for _, opt := range []string{`Append-Unknown-Fields=true`, "Format=json\n\t\tInject-Logtype-Field=_log", `Path-Field="@metadata.path"`, `Source-From-Field="sensor.name"`} {
+		if p, err = testLoadPreprocessor(str+"\t\t"+opt+"\n", `corelight`); err != nil {
+			t.Fatal(err)
+		} else if p.(*Corelight).stream {
+			t.Fatalf("streaming conversion enabled with %s", opt)
+		}
+	}
+}
+
+func benchmarkCorelight(b *testing.B, stream bool) {
+	p, err := testLoadPreprocessor(`
+	[preprocessor "corelight"]
+		type = corelight
+	`, `corelight`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	c := p.(*Corelight)
+	c.stream = stream
+	data := [][]byte{[]byte(conn1_in), []byte(dns1_in), []byte(http1_in), []byte(ssl1_in)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if tag, _, _, _ := c.processLine(data[i%len(data)]); tag == noTag {
+			b.Fatal("failed to convert")
+		}
+	}
+}
+
+func BenchmarkCorelightMap(b *testing.B) {
+	benchmarkCorelight(b, false)
+}
+
+func BenchmarkCorelightStream(b *testing.B) {
+	benchmarkCorelight(b, true)
+}
+
+func TestCorelightGeoIP(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this,that"
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	var google geoRecord
+	google.Country.ISOCode = "US"
+	google.ASN = 15169
+	c.geo = fakeGeo{"8.8.8.8": google, "1.1.1.1": geoRecord{}}
+
+	public := strings.Replace(conn1_in, `"192.168.4.1"`, `"8.8.8.8"`, 1)
+	unknown := strings.Replace(conn1_in, `"192.168.4.1"`, `"1.1.1.1"`, 1)
+	tests := []struct {
+		input  string
+		output string
+	}{
+		// private addresses get the empty field marker
+		{input: conn1_in, output: conn1_out + "\t-\t-\t-\t-"},
+		{input: public, output: strings.Replace(conn1_out, "192.168.4.1", "8.8.8.8", 1) + "\t-\tUS\t-\t15169"},
+		{input: unknown, output: strings.Replace(conn1_out, "192.168.4.1", "1.1.1.1", 1) + "\t-\t-\t-\t-"},
+		// log types without addresses are untouched
+		{input: foobar1_in, output: "1600266221.005323\thello\tmy"},
+	}
+	for i, tst := range tests {
+		ent := entry.Entry{
+			Data: []byte(tst.input),
+		}
+		if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatal(`too many entries came out`)
+		} else if string(ents[0].Data) != tst.output {
+			t.Fatalf("Output mismatch %d:\n%s\n%s\n", i, string(ents[0].Data), tst.output)
+		}
+	}
+
+	// a missing database and the JSON format are both rejected
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		GeoIP-Database="/does/not/exist.mmdb"
+	`
+	if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+		t.Fatal("failed to catch missing GeoIP database")
+	}
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Format=json
+		GeoIP-Database="/does/not/exist.mmdb"
+	`
+	if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+		t.Fatal("failed to catch GeoIP database with JSON format")
+	}
+}
+
+func TestCorelightTagRemap(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Tag-Remap="zeekconn=zeek"
+		Tag-Remap=" zeekdns = zeek "
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	for _, tg := range c.tg.KnownTags() {
+		if tg == `zeekconn` || tg == `zeekdns` {
+			t.Fatalf("remapped tag %q was negotiated", tg)
+		}
+	}
+	ent := entry.Entry{
+		Data: []byte(conn1_in),
+	}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatal(`too many entries came out`)
+	} else if name, ok := c.tg.LookupTag(ents[0].Tag); !ok || name != `zeek` {
+		t.Fatalf("entry was not remapped: %q", name)
+	} else if string(ents[0].Data) != conn1_out {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), conn1_out)
+	}
+
+	bad := []string{
+		`Tag-Remap="zeekconn"`,
+		`Tag-Remap="=zeek"`,
+		`Tag-Remap="zeekconn=bad tag"`,
+		`Tag-Remap="zeekconn=zeek"
+		Tag-Remap="zeekconn=other"`,
+	}
+	for _, v := range bad {
+		b = `
+	[preprocessor "corelight"]
+		type = corelight
+		` + v + "\n"
+		if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad tag remap %s", v)
+		}
+	}
+}
+
+func TestCorelightNormalizePath(t *testing.T) {
+	var tt testTagger
+	load := func(normalize bool) *Corelight {
+		var tc testConfigStruct
+		b := `
+		[preprocessor "corelight"]
+			type = corelight
+			Default-Tag=zeekunknown
+			Custom-Format="FooBar:ts,this,that"
+		`
+		if normalize {
+			b += "\tNormalize-Path=true\n"
+		}
+		if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+			t.Fatal(err)
+		}
+		p, err := tc.Preprocessor.getProcessor(`corelight`, &tt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, ok := p.(*Corelight)
+		if !ok {
+			t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+		}
+		return c
+	}
+	paths := []string{`conn`, `CONN`, `Conn`, ` conn `}
+
+	c := load(true)
+	conn, unknown := tt.mp[`zeekconn`], tt.mp[`zeekunknown`]
+	if _, ok := tt.mp[`zeekfoobar`]; !ok {
+		t.Fatal("custom format name was not normalized")
+	}
+	for _, stream := range []bool{true, false} {
+		c.stream = stream
+		for _, path := range append(paths, `Bogus`) {
+			in := strings.Replace(conn1_in, `"_path": "conn",`, `"_path": "`+path+`",`, 1)
+			ents, err := c.Process([]*entry.Entry{{Data: []byte(in)}})
+			if err != nil {
+				t.Fatal(err)
+			} else if len(ents) != 1 {
+				t.Fatal(`too many entries came out`)
+			}
+			if path == `Bogus` {
+				if ents[0].Tag != unknown || string(ents[0].Data) != in {
+					t.Fatalf("unknown path %q was not given the default tag", path)
+				}
+			} else if ents[0].Tag != conn || string(ents[0].Data) != conn1_out {
+				t.Fatalf("path %q was not normalized (stream %v): %d\n%s", path, stream, ents[0].Tag, ents[0].Data)
+			}
+		}
+	}
+
+	// without normalization only the exact log type matches
+	c = load(false)
+	for _, path := range paths[1:] {
+		in := strings.Replace(conn1_in, `"_path": "conn",`, `"_path": "`+path+`",`, 1)
+		if ents, err := c.Process([]*entry.Entry{{Data: []byte(in)}}); err != nil {
+			t.Fatal(err)
+		} else if ents[0].Tag != unknown {
+			t.Fatalf("path %q matched without normalization", path)
+		}
+	}
+}
+
+func TestCorelightInjectLogtype(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Format=json
+		Inject-Logtype-Field=gravwell_logtype
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	in := `{"_path":"conn","ts":"2020-08-16T06:26:03.553287Z","uid":"C1","orig_bytes":12345678901234567890,"note":"<a&b>"}`
+	out := `{"_path":"conn","gravwell_logtype":"conn","note":"<a&b>","orig_bytes":12345678901234567890,"ts":"2020-08-16T06:26:03.553287Z","uid":"C1"}`
+	ent := entry.Entry{Data: []byte(in)}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if string(ents[0].Data) != out {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), out)
+	}
+
+	// existing keys are never clobbered
+	in = `{"_path":"conn","ts":"2020-08-16T06:26:03.553287Z","gravwell_logtype":"real data"}`
+	ent = entry.Entry{Data: []byte(in)}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if string(ents[0].Data) != in {
+		t.Fatalf("existing key was clobbered:\n%s\n%s\n", string(ents[0].Data), in)
+	}
+
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Inject-Logtype-Field=gravwell_logtype
+	`
+	if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+		t.Fatal("failed to catch Inject-Logtype-Field with TSV format")
+	}
+}
+
+func TestCorelightLogtypes(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Error-Tag=zeekerr
+		Custom-Format="mylog:ts,uid"
+		Enable-Logtypes=dns
+		Enable-Logtypes=" mylog "
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	tags := c.tg.KnownTags()
+	sort.Strings(tags)
+	if strings.Join(tags, ",") != `zeekdns,zeekerr,zeekmylog` {
+		t.Fatalf("unexpected tags negotiated: %v", tags)
+	}
+
+	// disabled log types are not converted, only retagged, and are not counted as failures
+	errTag, err := c.tg.NegotiateTag(`zeekerr`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent := entry.Entry{Tag: 0, Data: []byte(conn1_in)}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if ents[0].Tag != errTag || string(ents[0].Data) != conn1_in {
+		t.Fatalf("disabled log type was modified: %d %s", ents[0].Tag, ents[0].Data)
+	} else if st := c.Stats(); st.Disabled != 1 || st.Failed != 0 {
+		t.Fatalf("bad stats: %+v", st)
+	}
+
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Disable-Logtypes=conn
+	`
+	if p, err = testLoadPreprocessor(b, `corelight`); err != nil {
+		t.Fatal(err)
+	}
+	for _, tg := range p.(*Corelight).tg.KnownTags() {
+		if tg == `zeekconn` {
+			t.Fatal("disabled log type was negotiated")
+		}
+	}
+
+	// explicitly disabled log types are retagged the same way
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Default-Tag=zeekunknown
+		Disable-Logtypes=conn
+	`
+	if p, err = testLoadPreprocessor(b, `corelight`); err != nil {
+		t.Fatal(err)
+	}
+	c = p.(*Corelight)
+	unknown, err := c.tg.NegotiateTag(`zeekunknown`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent = entry.Entry{Tag: 0, Data: []byte(conn1_in)}
+	if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if ents[0].Tag != unknown || string(ents[0].Data) != conn1_in {
+		t.Fatalf("disabled log type was not sent to the default tag: %d %s", ents[0].Tag, ents[0].Data)
+	} else if st := c.Stats(); st.Disabled != 1 || st.Failed != 0 {
+		t.Fatalf("bad stats: %+v", st)
+	}
+
+	bad := []string{
+		`Enable-Logtypes=nope`,
+		`Disable-Logtypes=nope`,
+		`Enable-Logtypes=conn
+		Disable-Logtypes=dns`,
+	}
+	for _, v := range bad {
+		b = `
+	[preprocessor "corelight"]
+		type = corelight
+		` + v + "\n"
+		if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad log type list %s", v)
+		}
+	}
+}
+
+func TestCorelightMaxEntrySize(t *testing.T) {
+	b := fmt.Sprintf(`
+	[preprocessor "corelight"]
+		type = corelight
+		Error-Tag=zeekerr
+		Max-Entry-Size=%d
+	`, len(conn1_in))
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	big := strings.Replace(conn1_in, `"conn"`, `"conn", "extracted":"`+strings.Repeat("A", 4096)+`"`, 1)
+	ents := []*entry.Entry{
+		{Data: []byte(conn1_in)},
+		{Data: []byte(big)},
+	}
+	if ents, err = c.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	if string(ents[0].Data) != conn1_out {
+		t.Fatalf("Output mismatch:\n%s\n%s\n", string(ents[0].Data), conn1_out)
+	} else if errTag, _ := c.tg.NegotiateTag(`zeekerr`); ents[1].Tag != errTag || string(ents[1].Data) != big {
+		t.Fatalf("oversized entry was not passed through to the default tag: %d", ents[1].Tag)
+	}
+	if st := c.Stats(); st.Oversized != 1 || st.Converted != 1 || st.Failed != 0 {
+		t.Fatalf("bad stats: %+v", st)
+	}
+
+}
+
+func TestCorelightSensorAttribution(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this"
+		Source-From-Field=_system_name
+		Use-Write-TS=true
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	} else if !c.stream {
+		t.Fatal("streaming conversion not enabled")
+	}
+	ts := time.Date(2020, 9, 16, 14, 23, 41, 5323000, time.UTC)
+	wts := time.Date(2020, 9, 16, 14, 23, 42, 0, time.UTC)
+	orig := net.ParseIP("192.168.1.1")
+	tests := []struct {
+		in   string
+		ts   time.Time
+		src  net.IP
+		name string // expected _system_name enumerated value
+	}{
+		{`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","_write_ts":"2020-09-16T14:23:42Z","_system_name":"sensor01","this":"a"}`, wts, orig, `sensor01`},
+		{`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","_write_ts":1600266222,"_system_name":"10.0.0.5","this":"a"}`, wts, net.ParseIP("10.0.0.5"), ``},
+		{`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","this":"a"}`, ts, orig, ``},
+		{`{"_path":"foobar","ts":"2020-09-16T14:23:41.005323Z","_write_ts":"garbage","_system_name":7,"this":"a"}`, ts, orig, ``},
+	}
+	for _, stream := range []bool{true, false} {
+		c.stream = stream
+		for i, tt := range tests {
+			ents, err := c.Process([]*entry.Entry{{SRC: orig, Data: []byte(tt.in)}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ent := ents[0]
+			if want := "1600266221.005323\ta"; string(ent.Data) != want {
+				t.Fatalf("%d: bad data %q != %q", i, ent.Data, want)
+			} else if !ent.TS.StandardTime().Equal(tt.ts) {
+				t.Fatalf("%d (stream %v): bad timestamp %v != %v", i, stream, ent.TS.StandardTime(), tt.ts)
+			} else if !ent.SRC.Equal(tt.src) {
+				t.Fatalf("%d (stream %v): bad source %v != %v", i, stream, ent.SRC, tt.src)
+			}
+			v, ok := ent.GetEnumeratedValue(`_system_name`)
+			if tt.name == `` && ok {
+				t.Fatalf("%d (stream %v): unexpected enumerated value %v", i, stream, v)
+			} else if tt.name != `` && v != tt.name {
+				t.Fatalf("%d (stream %v): bad enumerated value %v != %v", i, stream, v, tt.name)
+			}
+		}
+	}
+}
+
+func TestCorelightTimestampSkew(t *testing.T) {
+	now := time.Now().UTC()
+	good := fmt.Sprintf(`{"_path":"foobar","ts":%d,"this":"a"}`, now.Unix())
+	future := `{"_path":"foobar","ts":"2120-09-16T14:23:41Z","this":"a"}`
+	epoch := `{"_path":"foobar","ts":0,"this":"a"}`
+	mk := func() []*entry.Entry {
+		return []*entry.Entry{{Data: []byte(good)}, {Data: []byte(future)}, {Data: []byte(epoch)}}
+	}
+
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this"
+		Max-Timestamp-Skew=24h
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := p.(*Corelight)
+	ents, err := c.Process(mk())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 3 {
+		t.Fatalf("clamp dropped entries: %d", len(ents))
+	}
+	for i, ent := range ents {
+		if d := ent.TS.StandardTime().Sub(now); d > time.Minute || d < -time.Minute {
+			t.Fatalf("%d: timestamp %v not clamped", i, ent.TS)
+		}
+	}
+	if st := c.Stats(); st.Clamped != 2 || st.Dropped != 0 || st.Converted != 3 {
+		t.Fatalf("bad stats: %+v", st)
+	}
+
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this"
+		Max-Timestamp-Skew=24h
+		On-Skew=drop
+	`
+	if p, err = testLoadPreprocessor(b, `corelight`); err != nil {
+		t.Fatal(err)
+	}
+	c = p.(*Corelight)
+	if ents, err = c.Process(mk()); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 || string(ents[0].Data) != fmt.Sprintf("%d.000000\ta", now.Unix()) {
+		t.Fatalf("bad entries after drop: %d", len(ents))
+	}
+	if st := c.Stats(); st.Clamped != 0 || st.Dropped != 2 || st.Converted != 1 {
+		t.Fatalf("bad stats: %+v", st)
+	}
+
+	// a _write_ts used as the entry timestamp is bound the same way
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="foobar:ts,this"
+		Max-Timestamp-Skew=1h
+		Use-Write-TS=true
+	`
+	if p, err = testLoadPreprocessor(b, `corelight`); err != nil {
+		t.Fatal(err)
+	}
+	c = p.(*Corelight)
+	stale := fmt.Sprintf(`{"_path":"foobar","ts":%d,"_write_ts":"1990-01-01T00:00:00Z","this":"a"}`, now.Unix())
+	for _, stream := range []bool{true, false} {
+		c.stream = stream
+		if ents, err = c.Process([]*entry.Entry{{Data: []byte(stale)}}); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatalf("clamp dropped entries: %d", len(ents))
+		} else if d := ents[0].TS.StandardTime().Sub(now); d > time.Minute || d < -time.Minute {
+			t.Fatalf("write timestamp %v not clamped (stream %v)", ents[0].TS, stream)
+		}
+	}
+	if st := c.Stats(); st.Clamped != 2 {
+		t.Fatalf("bad stats: %+v", st)
+	}
+
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		On-Skew=drop
+	`
+	if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+		t.Fatal("On-Skew without Max-Timestamp-Skew was accepted")
+	}
+}
+
+func TestCorelightUnknownKey(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Prefex="foobar"
+	`
+	if _, err := testLoadPreprocessor(b, `corelight`); !errors.Is(err, config.ErrUnknownVariable) {
+		t.Fatalf("failed to catch misspelled key: %v", err)
+	} else if !strings.Contains(err.Error(), `Prefex`) {
+		t.Fatalf("error does not name the misspelled key: %v", err)
+	}
+
+	// the settings common to all preprocessors are allowed
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Flush-Interval=1s
+		Prefix="foobar"
+	`
+	if _, err := testLoadPreprocessor(b, `corelight`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCorelightLint(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Default-Tag=zeekunknown
+		Disable-Logtypes=dns
+		Tag-Remap="zeekconn=myconn"
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	conn := `{"_path":"conn","ts":"2020-08-16T06:26:03.553287Z","uid":"C1"}`
+	sample := strings.Join([]string{
+		conn,
+		conn,
+		`{"_path":"dns","ts":"2019-04-24T19:09:39.000000Z","uid":"Cx"}`,
+		``,
+		`{"_path":"mycustom","ts":"2019-04-24T19:09:39.000000Z","uid":"Cx"}`,
+		`not a zeek record`,
+	}, "\n")
+	cl, err := c.Lint(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl.Records != 5 || cl.Failed != 1 || cl.DefaultTag != `zeekunknown` {
+		t.Fatalf("bad lint summary: %+v", cl)
+	} else if len(cl.Tags) != 1 || cl.Tags[`myconn`] != 2 {
+		t.Fatalf("bad tag counts: %v", cl.Tags)
+	} else if len(cl.Disabled) != 1 || cl.Disabled[`dns`] != 1 {
+		t.Fatalf("bad disabled counts: %v", cl.Disabled)
+	} else if len(cl.Unmapped) != 1 || cl.Unmapped[`mycustom`] != 1 {
+		t.Fatalf("bad unmapped counts: %v", cl.Unmapped)
+	}
+	if st := c.Stats(); st.Processed != 0 {
+		t.Fatalf("lint updated the processor stats: %+v", st)
+	}
+}
+
+func TestCorelightCaseInsensitiveFields(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="mylog:ts,uid,id.orig_h,id.resp_h"
+		Case-Insensitive-Fields=true
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	tests := []struct {
+		in  string
+		out string
+	}{
+		// title cased flat and nested keys
+		{
+			in:  `{"_Path":"mylog","Ts":"2020-08-16T06:26:03.553287Z","UID":"C1","Id.orig_h":"1.1.1.1","Id.Resp_h":"2.2.2.2"}`,
+			out: "1597559163.553287\tC1\t1.1.1.1\t2.2.2.2",
+		},
+		{
+			in:  `{"_path":"mylog","TS":"2020-08-16T06:26:03.553287Z","Uid":"C1","Id":{"Orig_H":"1.1.1.1","resp_h":"2.2.2.2"}}`,
+			out: "1597559163.553287\tC1\t1.1.1.1\t2.2.2.2",
+		},
+		// the first of several keys differing only by case wins
+		{
+			in:  `{"_path":"mylog","ts":"2020-08-16T06:26:03.553287Z","Uid":"first","uid":"second","UID":"third"}`,
+			out: "1597559163.553287\tfirst\t-\t-",
+		},
+	}
+	for i, tt := range tests {
+		ent := entry.Entry{Data: []byte(tt.in)}
+		if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatalf("%d: bad count: %d", i, len(ents))
+		} else if string(ents[0].Data) != tt.out {
+			t.Fatalf("%d: Output mismatch:\n%q\n%q\n", i, string(ents[0].Data), tt.out)
+		}
+	}
+
+	// without the option, title cased keys do not populate columns
+	b = `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="mylog:ts,uid,id.orig_h,id.resp_h"
+	`
+	if p, err = testLoadPreprocessor(b, `corelight`); err != nil {
+		t.Fatal(err)
+	}
+	ent := entry.Entry{Data: []byte(`{"_path":"mylog","ts":"2020-08-16T06:26:03.553287Z","UID":"C1","Id.orig_h":"1.1.1.1"}`)}
+	if ents, err := p.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if out := "1597559163.553287\t-\t-\t-"; string(ents[0].Data) != out {
+		t.Fatalf("Output mismatch:\n%q\n%q\n", string(ents[0].Data), out)
+	}
+}
+
+func TestCorelightTagStats(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Tag-Remap="zeekconn=zeeknet"
+		Tag-Remap="zeekdns=zeeknet"
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	if st := c.Stats(); len(st.Tags) != 0 {
+		t.Fatalf("tag stats before processing: %+v", st.Tags)
+	}
+	inputs := []string{
+		conn1_in, conn2_in, dns1_in, dns2_in, dns1_in, http1_in,
+		strings.Replace(conn1_in, `"conn"`, `"unknownlog"`, 1),
+		`not json at all`,
+	}
+	ents := make([]*entry.Entry, 0, len(inputs))
+	for _, v := range inputs {
+		ents = append(ents, &entry.Entry{Data: []byte(v)})
+	}
+	if ents, err = c.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]CorelightTagStats{}
+	for _, ent := range ents[:6] {
+		tn, ok := c.tg.LookupTag(ent.Tag)
+		if !ok {
+			t.Fatalf("unknown tag %d", ent.Tag)
+		}
+		ts := exp[tn]
+		ts.Entries++
+		ts.Bytes += uint64(len(ent.Data))
+		exp[tn] = ts
+	}
+	st := c.Stats()
+	if len(st.Tags) != 2 || st.Tags[`zeeknet`].Entries != 5 || st.Tags[`zeekhttp`].Entries != 1 {
+		t.Fatalf("bad tag stats: %+v", st.Tags)
+	}
+	for k, v := range exp {
+		if st.Tags[k] != v {
+			t.Fatalf("bad %s stats: %+v != %+v", k, st.Tags[k], v)
+		}
+	}
+	if st.Converted != 6 || st.Failed != 2 {
+		t.Fatalf("invalid stats: %+v", st)
+	}
+}
+
+func TestCorelightValidateSchema(t *testing.T) {
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Custom-Format="mylog:ts,uid,id.orig_h,note"
+		Custom-Format="other:ts,note"
+		Validate-Schema=true
+		Quarantine-Tag=zeekbad
+	`
+	p, err := testLoadPreprocessor(b, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Corelight)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+	goodTag, otherTag, badTag := c.tags[`zeekmylog`], c.tags[`zeekother`], c.quarTag
+	if goodTag == badTag || otherTag == badTag {
+		t.Fatal("quarantine tag was not negotiated")
+	}
+	tests := []struct {
+		in  string
+		tag entry.EntryTag
+		out string
+	}{
+		{
+			in:  `{"_path":"mylog","ts":"2020-08-16T06:26:03.553287Z","uid":"C1","id.orig_h":"1.1.1.1","note":"a"}`,
+			tag: goodTag,
+			out: "1597559163.553287\tC1\t1.1.1.1\ta",
+		},
+		// log types without key fields only need the right column count
+		{
+			in:  `{"_path":"other","ts":"2020-08-16T06:26:03.553287Z"}`,
+			tag: otherTag,
+			out: "1597559163.553287\t-",
+		},
+		// missing uid
+		{
+			in:  `{"_path":"mylog","ts":"2020-08-16T06:26:03.553287Z","id.orig_h":"1.1.1.1","note":"a"}`,
+			tag: badTag,
+		},
+		// empty id.orig_h
+		{
+			in:  `{"_path":"mylog","ts":"2020-08-16T06:26:03.553287Z","uid":"C1","id":{"orig_h":""}}`,
+			tag: badTag,
+		},
+	}
+	for i, tt := range tests {
+		ent := entry.Entry{Data: []byte(tt.in)}
+		if ents, err := c.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatalf("%d: bad count: %d", i, len(ents))
+		} else if ents[0].Tag != tt.tag {
+			t.Fatalf("%d: bad tag %d != %d", i, ents[0].Tag, tt.tag)
+		} else if exp := tt.out; exp == `` && string(ents[0].Data) != tt.in {
+			t.Fatalf("%d: quarantined record was modified:\n%q", i, ents[0].Data)
+		} else if exp != `` && string(ents[0].Data) != exp {
+			t.Fatalf("%d: Output mismatch:\n%q\n%q\n", i, ents[0].Data, exp)
+		}
+	}
+	st := c.Stats()
+	if st.Converted != 2 || st.Quarantined != 2 {
+		t.Fatalf("bad stats: %+v", st)
+	} else if st.Schema[`mylog`] != (CorelightSchemaStats{Validated: 1, Quarantined: 2}) {
+		t.Fatalf("bad mylog schema stats: %+v", st.Schema)
+	} else if st.Schema[`other`] != (CorelightSchemaStats{Validated: 1}) || len(st.Schema) != 2 {
+		t.Fatalf("bad schema stats: %+v", st.Schema)
+	}
+
+	bad := []string{
+		`Validate-Schema=true`, // no quarantine tag
+		`Quarantine-Tag=zeekbad`,
+		`Validate-Schema=true
+		Quarantine-Tag="bad tag"`,
+		`Validate-Schema=true
+		Quarantine-Tag=zeekbad
+		Format=json`,
+	}
+	for _, v := range bad {
+		b = `
+		[preprocessor "corelight"]
+			type = corelight
+			` + v + `
+		`
+		if _, err = testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad config %q", v)
 		}
 	}
 }
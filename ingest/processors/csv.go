@@ -0,0 +1,269 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	CSVProcessor = `csv`
+)
+
+// CSVConfig controls how CSV entries are normalized into TSV.
+type CSVConfig struct {
+	// Headers specifies the comma separated column names of the incoming CSV.
+	// If empty, the first record handed to the processor is consumed as the header row.
+	Headers string
+
+	// Output_Order optionally specifies the comma separated columns to emit and their order,
+	// it defaults to the order of Headers. Columns missing from a record are emitted as
+	// the Empty_Field_Marker.
+	Output_Order string
+
+	// Delimiter specifies the single character separating incoming CSV fields, it defaults to ",".
+	Delimiter string
+
+	// Field_Separator specifies the separator placed between TSV fields, it defaults
+	// to a tab. Occurrences of the separator or newlines within field values are replaced with a space.
+	Field_Separator string
+
+	// Empty_Field_Marker specifies the value emitted for missing or empty fields, it defaults to "-".
+	Empty_Field_Marker string
+
+	// Timestamp_Column optionally names the column the entry timestamp is extracted from.
+	// Numeric values are parsed as epochs, whose unit is detected from their magnitude.
+	Timestamp_Column          string
+	Timestamp_Format_Override string
+	Timezone_Override         string
+	Assume_Local_Timezone     bool
+
+	delim      rune
+	headerList []string
+	orderList  []string
+}
+
+func CSVLoadConfig(vc *config.VariableConfig) (c CSVConfig, err error) {
+	c.Delimiter = `,`
+	c.Field_Separator = defaultFieldSeparator
+	c.Empty_Field_Marker = defaultEmptyFieldMarker
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *CSVConfig) validate() (err error) {
+	if c.Delimiter == `` {
+		c.Delimiter = `,`
+	}
+	if utf8.RuneCountInString(c.Delimiter) != 1 {
+		return fmt.Errorf("Delimiter %q must be a single character", c.Delimiter)
+	} else if c.delim, _ = utf8.DecodeRuneInString(c.Delimiter); c.delim == '"' || c.delim == '\r' || c.delim == '\n' {
+		return fmt.Errorf("Invalid Delimiter %q", c.Delimiter)
+	}
+	if c.Field_Separator == `` {
+		c.Field_Separator = defaultFieldSeparator
+	} else if strings.ContainsAny(c.Field_Separator, "\r\n") {
+		return fmt.Errorf("Field-Separator %q may not contain newlines", c.Field_Separator)
+	}
+	if c.Empty_Field_Marker == `` {
+		c.Empty_Field_Marker = defaultEmptyFieldMarker
+	}
+	c.headerList, c.orderList = nil, nil
+	if c.Headers != `` {
+		if c.headerList, err = loadHeaders(c.Headers); err != nil {
+			return fmt.Errorf("Invalid Headers: %w", err)
+		}
+	}
+	if c.Output_Order != `` {
+		if c.orderList, err = loadHeaders(c.Output_Order); err != nil {
+			return fmt.Errorf("Invalid Output-Order: %w", err)
+		}
+		if c.headerList != nil {
+			for _, o := range c.orderList {
+				if !inSet(o, c.headerList) {
+					return fmt.Errorf("Output-Order column %q is not in Headers", o)
+				}
+			}
+		}
+	}
+	if c.Timestamp_Column != `` && c.headerList != nil && !inSet(c.Timestamp_Column, c.headerList) {
+		return fmt.Errorf("Timestamp-Column %q is not in Headers", c.Timestamp_Column)
+	} else if c.Timestamp_Column == `` && (c.Timestamp_Format_Override != `` || c.Timezone_Override != `` || c.Assume_Local_Timezone) {
+		return errors.New("Timestamp options require a Timestamp-Column")
+	}
+	return c.timeConfig().Validate()
+}
+
+// timeConfig returns the settings used to parse the Timestamp_Column
+func (c CSVConfig) timeConfig() TimeExtractorConfig {
+	return TimeExtractorConfig{
+		FormatOverride:      c.Timestamp_Format_Override,
+		TimezoneOverride:    c.Timezone_Override,
+		AssumeLocalTimezone: c.Assume_Local_Timezone,
+	}
+}
+
+func NewCSV(cfg CSVConfig) (*CSV, error) {
+	c := &CSV{}
+	if err := c.init(cfg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// A CSV processor parses CSV entries, honoring quoting, and re-emits the fields as TSV in a
+// known column order. Entries containing multiple records are split into an entry per record.
+// Entries which cannot be parsed pass through unchanged.
+type CSV struct {
+	nocloser
+	CSVConfig
+	te    *TimeExtractor // nil unless Timestamp_Column is set
+	index map[string]int // column name to record index
+}
+
+func (c *CSV) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(CSVConfig); ok {
+		err = c.init(cfg)
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (c *CSV) init(cfg CSVConfig) (err error) {
+	if err = cfg.validate(); err != nil {
+		return
+	}
+	var te *TimeExtractor
+	if cfg.Timestamp_Column != `` {
+		if te, err = NewTimeExtractor(cfg.timeConfig()); err != nil {
+			return
+		}
+	}
+	c.CSVConfig, c.te = cfg, te
+	c.index = nil
+	if cfg.headerList != nil {
+		c.setHeaders(cfg.headerList)
+	}
+	return
+}
+
+func (c *CSV) setHeaders(hdrs []string) {
+	c.headerList = hdrs
+	c.index = make(map[string]int, len(hdrs))
+	for i, h := range hdrs {
+		c.index[h] = i
+	}
+	if c.orderList == nil {
+		c.orderList = hdrs
+	}
+}
+
+func (c *CSV) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	rset = make([]*entry.Entry, 0, len(ents))
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		rset = append(rset, c.processItem(ent)...)
+	}
+	return
+}
+
+func (c *CSV) processItem(ent *entry.Entry) (rset []*entry.Entry) {
+	rdr := csv.NewReader(bytes.NewReader(ent.Data))
+	rdr.Comma = c.delim
+	rdr.FieldsPerRecord = -1
+	var records [][]string
+	for {
+		rec, err := rdr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return []*entry.Entry{ent}
+		}
+		records = append(records, rec)
+	}
+	if c.index == nil && len(records) > 0 {
+		c.setHeaders(cleanHeaders(records[0]))
+		records = records[1:]
+	}
+	for i, rec := range records {
+		r := ent
+		if i > 0 {
+			r = &entry.Entry{
+				Tag: ent.Tag,
+				SRC: ent.SRC,
+				TS:  ent.TS,
+			}
+			r.CopyEnumeratedBlock(ent)
+		}
+		r.Data = c.emitLine(rec)
+		if c.te != nil {
+			if v, ok := c.field(rec, c.Timestamp_Column); ok {
+				if ts, ok := c.te.Extract([]byte(v)); ok {
+					r.TS = entry.FromStandard(ts)
+				}
+			}
+		}
+		rset = append(rset, r)
+	}
+	return
+}
+
+// field returns the value of the named column in the record
+func (c *CSV) field(rec []string, name string) (v string, ok bool) {
+	var idx int
+	if idx, ok = c.index[name]; ok && idx < len(rec) {
+		v = rec[idx]
+	} else {
+		ok = false
+	}
+	return
+}
+
+func (c *CSV) emitLine(rec []string) []byte {
+	bb := bytes.NewBuffer(nil)
+	for i, h := range c.orderList {
+		if i > 0 {
+			bb.WriteString(c.Field_Separator)
+		}
+		if v, ok := c.field(rec, h); ok && v != `` {
+			bb.WriteString(c.cleanValue(v))
+		} else {
+			bb.WriteString(c.Empty_Field_Marker)
+		}
+	}
+	return bb.Bytes()
+}
+
+// cleanValue replaces the field separator and newlines within a value with spaces
+func (c *CSV) cleanValue(v string) string {
+	v = strings.ReplaceAll(v, c.Field_Separator, " ")
+	if strings.ContainsAny(v, "\r\n") {
+		v = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(v)
+	}
+	return v
+}
@@ -0,0 +1,139 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestCSVConfig(t *testing.T) {
+	b := `
+	[preprocessor "csv"]
+		type = csv
+		Headers = "time, src, dst, msg"
+		Output-Order = "src,dst,time"
+		Timestamp-Column = time
+	`
+	p, err := testLoadPreprocessor(b, `csv`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*CSV)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *CSV", p)
+	}
+	if len(c.headerList) != 4 || len(c.orderList) != 3 || c.te == nil || c.Field_Separator != "\t" || c.Empty_Field_Marker != "-" {
+		t.Fatalf("bad config: %+v", c.CSVConfig)
+	}
+
+	bads := []string{
+		`Delimiter = ";;"`,
+		`Delimiter = "\""`,
+		"Headers = \"a,b\"\n\t\tOutput-Order = \"a,c\"",
+		"Headers = \"a,b\"\n\t\tTimestamp-Column = c",
+		`Assume-Local-Timezone = true`,
+		`Timezone-Override = UTC`,
+		"Timestamp-Column = time\n\t\tTimezone-Override = Nowhere/Special",
+	}
+	for _, bad := range bads {
+		b = `
+	[preprocessor "csv"]
+		type = csv
+		` + bad + `
+	`
+		if _, err = testLoadPreprocessor(b, `csv`); err == nil {
+			t.Fatalf("failed to catch bad config %q", bad)
+		}
+	}
+}
+
+func TestCSVProcess(t *testing.T) {
+	c, err := NewCSV(CSVConfig{
+		Headers:          `time,src,dst,msg`,
+		Output_Order:     `msg,src,dst`,
+		Timestamp_Column: `time`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := makeEntry([]byte("2026-01-02T03:04:05Z,10.0.0.1,,\"hello, \"\"world\"\"\tagain\nand again\"\n"+
+		"2026-01-02T03:04:06Z,10.0.0.2"), 0)
+	set, err := c.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 2 {
+		t.Fatalf("bad output count: %d", len(set))
+	}
+	if err = checkEntryEVs(set); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []struct {
+		line string
+		ts   time.Time
+	}{
+		{"hello, \"world\" again and again\t10.0.0.1\t-", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"-\t10.0.0.2\t-", time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC)},
+	} {
+		if string(set[i].Data) != want.line {
+			t.Fatalf("bad line %d: %q != %q", i, set[i].Data, want.line)
+		}
+		if !set[i].TS.StandardTime().Equal(want.ts) {
+			t.Fatalf("bad timestamp %d: %v != %v", i, set[i].TS, want.ts)
+		}
+	}
+
+	//epochs are detected and the timezone override applies to timestamps without a timezone
+	if err = c.Config(CSVConfig{Timestamp_Column: `time`, Timezone_Override: `America/New_York`}); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if set, err = c.Process(makeEntry([]byte("time\n1767323045\n\"2026-01-01 22:04:05\""), 0)); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 2 {
+		t.Fatalf("bad output count: %d", len(set))
+	}
+	for i, ent := range set {
+		if !ent.TS.StandardTime().Equal(want) {
+			t.Fatalf("bad timestamp %d: %v != %v", i, ent.TS, want)
+		}
+	}
+
+	//malformed entries pass through untouched
+	bad := []byte("a,\"unterminated\nb")
+	if set, err = c.Process(makeEntry(bad, 0)); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 1 || string(set[0].Data) != string(bad) {
+		t.Fatalf("malformed entry was modified: %q", set[0].Data)
+	}
+}
+
+func TestCSVHeaderLine(t *testing.T) {
+	c, err := NewCSV(CSVConfig{Delimiter: `;`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := c.Process([]*entry.Entry{
+		makeEntry([]byte("name; count"), 0)[0],
+		makeEntry([]byte("foo;1"), 0)[0],
+		makeEntry([]byte("bar;"), 0)[0],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 2 {
+		t.Fatalf("header line was not consumed: %d", len(set))
+	}
+	if string(set[0].Data) != "foo\t1" || string(set[1].Data) != "bar\t-" {
+		t.Fatalf("bad output: %q %q", set[0].Data, set[1].Data)
+	}
+}
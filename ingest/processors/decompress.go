@@ -0,0 +1,202 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zlib"
+)
+
+const (
+	DecompressProcessor string = `decompress`
+
+	compressionAuto   string = `auto`
+	compressionGzip   string = `gzip`
+	compressionZlib   string = `zlib`
+	compressionSnappy string = `snappy`
+
+	onErrorPassthrough string = `passthrough`
+	onErrorDrop        string = `drop`
+
+	defaultDecompressMaxSize = 32 * mb
+)
+
+var (
+	ErrDecompressTooLarge = errors.New("Decompressed data exceeds Max-Size")
+
+	// magic for the snappy framing format stream identifier chunk
+	snappyStreamMagic = []byte("\xff\x06\x00\x00sNaPpY")
+)
+
+type DecompressConfig struct {
+	Compression string // auto, gzip, zlib, or snappy; auto cannot detect unframed snappy blocks
+	Max_Size    uint64 // maximum decompressed size in bytes, default is 32MB
+	On_Error    string // passthrough or drop entries which fail to decompress, default is passthrough
+}
+
+func DecompressLoadConfig(vc *config.VariableConfig) (c DecompressConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *DecompressConfig) validate() error {
+	switch c.Compression = strings.ToLower(strings.TrimSpace(c.Compression)); c.Compression {
+	case ``:
+		c.Compression = compressionAuto
+	case compressionAuto, compressionGzip, compressionZlib, compressionSnappy:
+	default:
+		return fmt.Errorf("Unknown Compression %q", c.Compression)
+	}
+	switch c.On_Error = strings.ToLower(strings.TrimSpace(c.On_Error)); c.On_Error {
+	case ``:
+		c.On_Error = onErrorPassthrough
+	case onErrorPassthrough, onErrorDrop:
+	default:
+		return fmt.Errorf("Unknown On-Error action %q", c.On_Error)
+	}
+	if c.Max_Size == 0 {
+		c.Max_Size = defaultDecompressMaxSize
+	}
+	return nil
+}
+
+func NewDecompressor(cfg DecompressConfig) (*Decompressor, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Decompressor{
+		DecompressConfig: cfg,
+		rdr:              bytes.NewReader(nil),
+		bb:               bytes.NewBuffer(nil),
+	}, nil
+}
+
+// Decompressor replaces compressed entry data with the decompressed bytes.
+// In auto mode, data which is not recognizably compressed is left untouched.
+type Decompressor struct {
+	nocloser
+	DecompressConfig
+	rdr *bytes.Reader
+	bb  *bytes.Buffer
+}
+
+func (d *Decompressor) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(DecompressConfig); ok {
+		if err = cfg.validate(); err == nil {
+			d.DecompressConfig = cfg
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (d *Decompressor) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	if len(ents) == 0 {
+		return nil, nil
+	}
+	rset := ents[:0]
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		if data, err := d.decompress(ent.Data); err == nil {
+			ent.Data = data
+		} else if d.On_Error == onErrorDrop {
+			continue
+		}
+		rset = append(rset, ent)
+	}
+	if d.bb.Cap() > defaultMaxBuff {
+		d.bb = bytes.NewBuffer(nil)
+	}
+	return rset, nil
+}
+
+// decompress returns the decompressed data, or the original data if it is not compressed
+func (d *Decompressor) decompress(data []byte) (r []byte, err error) {
+	var zr io.ReadCloser
+	kind := d.detect(data)
+	if kind == compressionZlib && d.Compression == compressionAuto {
+		//the zlib header is only two bytes and plain text can look like one, so
+		//detected zlib that fails to decompress is treated as uncompressed
+		defer func() {
+			if err != nil && err != ErrDecompressTooLarge {
+				r, err = data, nil
+			}
+		}()
+	}
+	d.rdr.Reset(data)
+	switch kind {
+	case compressionGzip:
+		zr, err = gzip.NewReader(d.rdr)
+	case compressionZlib:
+		zr, err = zlib.NewReader(d.rdr)
+	case compressionSnappy:
+		if !bytes.HasPrefix(data, snappyStreamMagic) {
+			return d.snappyBlock(data)
+		}
+		zr = io.NopCloser(snappy.NewReader(d.rdr))
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return
+	}
+	defer zr.Close()
+	d.bb.Reset()
+	//read one byte past the limit so we can tell if it was exceeded
+	var n int64
+	if n, err = io.Copy(d.bb, io.LimitReader(zr, int64(d.Max_Size)+1)); err != nil {
+		return
+	} else if uint64(n) > d.Max_Size {
+		return nil, ErrDecompressTooLarge
+	}
+	r = append(nb, d.bb.Bytes()...)
+	return
+}
+
+// snappyBlock decodes the unframed snappy block format, which carries its decoded length
+func (d *Decompressor) snappyBlock(data []byte) ([]byte, error) {
+	if sz, err := snappy.DecodedLen(data); err != nil {
+		return nil, err
+	} else if uint64(sz) > d.Max_Size {
+		return nil, ErrDecompressTooLarge
+	}
+	return snappy.Decode(nil, data)
+}
+
+// detect returns the compression in use, or an empty string if the data is not recognized
+func (d *Decompressor) detect(data []byte) string {
+	if d.Compression != compressionAuto {
+		return d.Compression
+	}
+	switch {
+	case len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b:
+		return compressionGzip
+	case len(data) > 2 && data[0]&0x0f == 8 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0:
+		return compressionZlib
+	case bytes.HasPrefix(data, snappyStreamMagic):
+		return compressionSnappy
+	}
+	return ``
+}
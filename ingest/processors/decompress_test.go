@@ -0,0 +1,145 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zlib"
+)
+
+func TestDecompressConfig(t *testing.T) {
+	b := `
+	[preprocessor "dc"]
+		type = decompress
+		Compression = ZLIB
+		Max-Size = 1024
+		On-Error = drop
+	`
+	p, err := testLoadPreprocessor(b, `dc`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, ok := p.(*Decompressor)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Decompressor", p)
+	}
+	if d.Compression != compressionZlib || d.Max_Size != 1024 || d.On_Error != onErrorDrop {
+		t.Fatalf("bad config: %+v", d.DecompressConfig)
+	}
+
+	for _, bad := range []string{`Compression = lzma`, `On-Error = explode`} {
+		b = `
+	[preprocessor "dc"]
+		type = decompress
+		` + bad + `
+	`
+		if _, err = testLoadPreprocessor(b, `dc`); err == nil {
+			t.Fatalf("failed to catch bad config %q", bad)
+		}
+	}
+}
+
+func compressWith(t *testing.T, kind string, data []byte) []byte {
+	t.Helper()
+	var wtr io.WriteCloser
+	bb := bytes.NewBuffer(nil)
+	switch kind {
+	case compressionGzip:
+		wtr = gzip.NewWriter(bb)
+	case compressionZlib:
+		wtr = zlib.NewWriter(bb)
+	case compressionSnappy:
+		wtr = snappy.NewBufferedWriter(bb)
+	default:
+		t.Fatalf("unknown compression %s", kind)
+	}
+	if _, err := wtr.Write(data); err != nil {
+		t.Fatal(err)
+	} else if err = wtr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bb.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	payload := bytes.Repeat([]byte("compressed zeek export "), 100)
+	for _, kind := range []string{compressionGzip, compressionZlib, compressionSnappy} {
+		for _, mode := range []string{compressionAuto, kind} {
+			d, err := NewDecompressor(DecompressConfig{Compression: mode})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ents := makeEntry(compressWith(t, kind, payload), 0)
+			ents = append(ents, makeEntry([]byte("x^ plain text"), 0)...)
+			set, err := d.Process(ents)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(set) != 2 || !bytes.Equal(set[0].Data, payload) {
+				t.Fatalf("%s/%s: failed to decompress", kind, mode)
+			}
+			if err = checkEntryEVs(set); err != nil {
+				t.Fatal(err)
+			}
+			if mode == compressionAuto && string(set[1].Data) != "x^ plain text" {
+				t.Fatalf("%s/%s: uncompressed data was modified: %q", kind, mode, set[1].Data)
+			}
+		}
+	}
+
+	//unframed snappy blocks must be requested explicitly
+	d, err := NewDecompressor(DecompressConfig{Compression: compressionSnappy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := d.Process(makeEntry(snappy.Encode(nil, payload), 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(set) != 1 || !bytes.Equal(set[0].Data, payload) {
+		t.Fatal("failed to decompress snappy block")
+	}
+}
+
+func TestDecompressErrors(t *testing.T) {
+	payload := bytes.Repeat([]byte{0}, 4096)
+	for _, kind := range []string{compressionGzip, compressionZlib, compressionSnappy} {
+		d, err := NewDecompressor(DecompressConfig{Compression: kind, Max_Size: 1024, On_Error: onErrorDrop})
+		if err != nil {
+			t.Fatal(err)
+		}
+		set, err := d.Process(makeEntry(compressWith(t, kind, payload), 0))
+		if err != nil {
+			t.Fatal(err)
+		} else if len(set) != 0 {
+			t.Fatalf("%s: oversized entry was not dropped", kind)
+		}
+		if set, err = d.Process(makeEntry([]byte("not compressed"), 0)); err != nil {
+			t.Fatal(err)
+		} else if len(set) != 0 {
+			t.Fatalf("%s: corrupt entry was not dropped", kind)
+		}
+	}
+
+	d, err := NewDecompressor(DecompressConfig{Compression: compressionSnappy, Max_Size: 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bomb := snappy.Encode(nil, payload)
+	set, err := d.Process(makeEntry(bomb, 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(set) != 1 || !bytes.Equal(set[0].Data, bomb) {
+		t.Fatal("failed entry was not passed through untouched")
+	}
+}
@@ -0,0 +1,204 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	DedupProcessor string = `dedup`
+
+	defaultDedupWindow = 10 * time.Second
+	dedupCountEV       = `repeat_count`
+)
+
+var (
+	ErrInvalidMaxSuppress = errors.New("Max-Suppress cannot be negative")
+)
+
+type DedupConfig struct {
+	Window                 string // maximum span of a run of duplicates, default is 10s
+	Max_Suppress           int    // maximum number of duplicates collapsed into a single entry, zero is unlimited
+	Per_Tag                bool   // track runs independently for each tag
+	Compare_Post_Timestamp bool   // only compare the data following the timestamp
+	windowDur              time.Duration
+}
+
+func DedupLoadConfig(vc *config.VariableConfig) (c DedupConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *DedupConfig) validate() (err error) {
+	c.windowDur = defaultDedupWindow
+	if c.Window != `` {
+		if c.windowDur, err = time.ParseDuration(c.Window); err != nil {
+			return fmt.Errorf("Invalid Window %q: %v", c.Window, err)
+		} else if c.windowDur <= 0 {
+			return fmt.Errorf("Invalid Window %q: must be positive", c.Window)
+		}
+	}
+	if c.Max_Suppress < 0 {
+		return ErrInvalidMaxSuppress
+	}
+	return
+}
+
+func NewDedup(cfg DedupConfig) (*Dedup, error) {
+	d := &Dedup{
+		runs: map[entry.EntryTag]*dedupRun{},
+	}
+	if err := d.Config(cfg); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// build validates the config and constructs the time extractor it requires, which is nil unless
+// Compare_Post_Timestamp is set
+func (c *DedupConfig) build() (te *TimeExtractor, err error) {
+	if err = c.validate(); err != nil || !c.Compare_Post_Timestamp {
+		return
+	}
+	return NewTimeExtractor(TimeExtractorConfig{})
+}
+
+// Dedup collapses runs of identical consecutive entries into the first entry of the run,
+// annotated with the number of duplicates that were suppressed.
+// The first entry of a run is held until the run ends, which happens when a differing entry
+// arrives, the run spans more than the Window, Max-Suppress duplicates have been collapsed,
+// or the processor is flushed. Unless a Flush-Interval is given the processor is flushed every
+// Window, so the last run of a source that goes quiet is not held indefinitely.
+type Dedup struct {
+	nocloser
+	DedupConfig
+	te   *TimeExtractor
+	runs map[entry.EntryTag]*dedupRun // keyed by tag when Per_Tag is set, otherwise a single run at tag 0
+}
+
+type dedupRun struct {
+	ent   *entry.Entry
+	hash  uint64
+	count uint64 // number of suppressed duplicates
+}
+
+func (d *Dedup) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(DedupConfig); ok {
+		var te *TimeExtractor
+		if te, err = cfg.build(); err == nil {
+			d.DedupConfig, d.te = cfg, te
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (d *Dedup) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return ents, nil
+	}
+	rset = make([]*entry.Entry, 0, len(ents))
+	var latest entry.Timestamp
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		if ent.TS.After(latest) {
+			latest = ent.TS
+		}
+		var key entry.EntryTag
+		if d.Per_Tag {
+			key = ent.Tag
+		}
+		h := d.hash(ent)
+		if run, ok := d.runs[key]; ok {
+			if d.duplicate(run, ent, h) {
+				run.count++
+				if d.Max_Suppress > 0 && run.count >= uint64(d.Max_Suppress) {
+					rset = append(rset, run.emit())
+					delete(d.runs, key)
+				}
+				continue
+			}
+			rset = append(rset, run.emit())
+		}
+		d.runs[key] = &dedupRun{ent: ent, hash: h}
+	}
+	// release any held runs that have gone quiet for longer than the window
+	for k, run := range d.runs {
+		if latest.Sub(run.ent.TS) > d.windowDur {
+			rset = append(rset, run.emit())
+			delete(d.runs, k)
+		}
+	}
+	return
+}
+
+// defaultFlushInterval is the Window, see Dedup
+func (d *Dedup) defaultFlushInterval() time.Duration {
+	return d.windowDur
+}
+
+// Flush releases every held run
+func (d *Dedup) Flush() (rset []*entry.Entry) {
+	for k, run := range d.runs {
+		rset = append(rset, run.emit())
+		delete(d.runs, k)
+	}
+	return
+}
+
+// duplicate returns whether ent continues the run
+func (d *Dedup) duplicate(run *dedupRun, ent *entry.Entry, h uint64) bool {
+	if h != run.hash || ent.Tag != run.ent.Tag {
+		return false
+	}
+	if dlt := ent.TS.Sub(run.ent.TS); dlt > d.windowDur || dlt < -d.windowDur {
+		return false
+	}
+	//guard against hash collisions
+	return bytes.Equal(d.compared(ent), d.compared(run.ent))
+}
+
+// compared returns the portion of the entry data used for comparison
+func (d *Dedup) compared(ent *entry.Entry) []byte {
+	if d.te != nil {
+		if _, end, ok := d.te.TimeGrinder().Match(ent.Data); ok {
+			return ent.Data[end:]
+		}
+	}
+	return ent.Data
+}
+
+func (d *Dedup) hash(ent *entry.Entry) uint64 {
+	h := fnv.New64a()
+	h.Write(d.compared(ent))
+	return h.Sum64()
+}
+
+// emit returns the held entry, annotated with the repeat count if anything was suppressed
+func (r *dedupRun) emit() *entry.Entry {
+	if r.count > 0 {
+		r.ent.AddEnumeratedValueEx(dedupCountEV, r.count)
+	}
+	return r.ent
+}
@@ -0,0 +1,282 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestDedupConfig(t *testing.T) {
+	b := `
+	[preprocessor "dd"]
+		type = dedup
+		Window = "5s"
+		Max-Suppress = 100
+		Per-Tag = true
+		Compare-Post-Timestamp = true
+	`
+	p, err := testLoadPreprocessor(b, `dd`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, ok := p.(*Dedup)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Dedup", p)
+	}
+	if d.windowDur != 5*time.Second || d.Max_Suppress != 100 || !d.Per_Tag || d.te == nil {
+		t.Fatalf("bad config: %+v", d.DedupConfig)
+	}
+
+	for _, bad := range []string{`Window = foo`, `Window = "-1s"`, `Max-Suppress = -1`} {
+		b = `
+	[preprocessor "dd"]
+		type = dedup
+		` + bad + `
+	`
+		if _, err = testLoadPreprocessor(b, `dd`); err == nil {
+			t.Fatalf("failed to catch bad config %q", bad)
+		}
+	}
+}
+
+func dedupEnt(data string, tag entry.EntryTag, ts entry.Timestamp) *entry.Entry {
+	return &entry.Entry{TS: ts, Tag: tag, SRC: testSrc, Data: []byte(data)}
+}
+
+func checkRepeat(t *testing.T, ent *entry.Entry, data string, count uint64) {
+	t.Helper()
+	if string(ent.Data) != data {
+		t.Fatalf("bad data: %q != %q", ent.Data, data)
+	}
+	v, ok := ent.GetEnumeratedValue(dedupCountEV)
+	if count == 0 {
+		if ok {
+			t.Fatalf("%q has an unexpected repeat count %v", data, v)
+		}
+		return
+	}
+	if !ok || v != count {
+		t.Fatalf("%q has a bad repeat count: %v != %d", data, v, count)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	d, err := NewDedup(DedupConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set, err := d.Process(nil); err != nil || len(set) != 0 {
+		t.Fatalf("empty batch was not a no-op: %v %v", set, err)
+	}
+
+	ts := entry.Now()
+	set, err := d.Process([]*entry.Entry{
+		dedupEnt("foo", 0, ts),
+		dedupEnt("foo", 0, ts.Add(time.Second)),
+		dedupEnt("foo", 0, ts.Add(2*time.Second)),
+		dedupEnt("bar", 0, ts.Add(3*time.Second)),
+		dedupEnt("bar", 1, ts.Add(3*time.Second)),  // different tag is not a duplicate
+		dedupEnt("bar", 1, ts.Add(30*time.Second)), // outside the window
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 3 {
+		t.Fatalf("bad output count: %d", len(set))
+	}
+	checkRepeat(t, set[0], "foo", 2)
+	checkRepeat(t, set[1], "bar", 0)
+	checkRepeat(t, set[2], "bar", 0)
+
+	// the last run continues across batches and is released by a flush
+	if set, err = d.Process([]*entry.Entry{dedupEnt("bar", 1, ts.Add(31*time.Second))}); err != nil || len(set) != 0 {
+		t.Fatalf("duplicate was not held: %v %v", set, err)
+	}
+	if set = d.Flush(); len(set) != 1 {
+		t.Fatalf("bad flush count: %d", len(set))
+	}
+	checkRepeat(t, set[0], "bar", 1)
+	if set = d.Flush(); len(set) != 0 {
+		t.Fatalf("second flush produced %d entries", len(set))
+	}
+}
+
+func TestDedupMaxSuppress(t *testing.T) {
+	d, err := NewDedup(DedupConfig{Max_Suppress: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := entry.Now()
+	var ents []*entry.Entry
+	for i := 0; i < 7; i++ {
+		ents = append(ents, dedupEnt("foo", 0, ts))
+	}
+	set, err := d.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 2 {
+		t.Fatalf("bad output count: %d", len(set))
+	}
+	checkRepeat(t, set[0], "foo", 2)
+	checkRepeat(t, set[1], "foo", 2)
+	if set = d.Flush(); len(set) != 1 {
+		t.Fatalf("bad flush count: %d", len(set))
+	}
+	checkRepeat(t, set[0], "foo", 0)
+}
+
+func TestDedupPerTag(t *testing.T) {
+	d, err := NewDedup(DedupConfig{Per_Tag: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := entry.Now()
+	set, err := d.Process([]*entry.Entry{
+		dedupEnt("foo", 0, ts),
+		dedupEnt("bar", 1, ts),
+		dedupEnt("foo", 0, ts),
+		dedupEnt("bar", 1, ts),
+		dedupEnt("foo", 0, ts),
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(set) != 0 {
+		t.Fatalf("interleaved duplicates were not held: %d", len(set))
+	}
+	set = d.Flush()
+	if len(set) != 2 {
+		t.Fatalf("bad flush count: %d", len(set))
+	}
+	for _, ent := range set {
+		if ent.Tag == 0 {
+			checkRepeat(t, ent, "foo", 2)
+		} else {
+			checkRepeat(t, ent, "bar", 1)
+		}
+	}
+}
+
+func TestDedupPostTimestamp(t *testing.T) {
+	lines := []string{
+		"2026-01-02T03:04:05Z host sshd: connection reset",
+		"2026-01-02T03:04:06Z host sshd: connection reset",
+		"2026-01-02T03:04:07Z host sshd: connection reset",
+	}
+	ts := entry.Now()
+	run := func(cfg DedupConfig) (set []*entry.Entry) {
+		d, err := NewDedup(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, l := range lines {
+			s, err := d.Process([]*entry.Entry{dedupEnt(l, 0, ts)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			set = append(set, s...)
+		}
+		return append(set, d.Flush()...)
+	}
+
+	if set := run(DedupConfig{}); len(set) != len(lines) {
+		t.Fatalf("lines with differing timestamps were collapsed: %d", len(set))
+	}
+	set := run(DedupConfig{Compare_Post_Timestamp: true})
+	if len(set) != 1 {
+		t.Fatalf("bad output count: %d", len(set))
+	}
+	checkRepeat(t, set[0], lines[0], 2)
+}
+
+func TestDedupReconfigure(t *testing.T) {
+	d, err := NewDedup(DedupConfig{})
+	if err != nil {
+		t.Fatal(err)
+	} else if d.te != nil {
+		t.Fatal("time extractor built without Compare-Post-Timestamp")
+	}
+	if err = d.Config(DedupConfig{Compare_Post_Timestamp: true}); err != nil {
+		t.Fatal(err)
+	} else if d.te == nil {
+		t.Fatal("time extractor was not built on reconfigure")
+	}
+	if err = d.Config(DedupConfig{Window: "-1s"}); err == nil {
+		t.Fatal("failed to catch bad config")
+	} else if d.te == nil || !d.Compare_Post_Timestamp {
+		t.Fatal("bad config was partially applied")
+	}
+	if err = d.Config(DedupConfig{}); err != nil {
+		t.Fatal(err)
+	} else if d.te != nil {
+		t.Fatal("time extractor was not cleared on reconfigure")
+	}
+}
+
+func TestDedupDefaultFlush(t *testing.T) {
+	// a gate must not hide the default flush of the dedup it wraps
+	for _, gate := range []string{``, `Gate-Data-Regex=hello`} {
+		b := `
+		[preprocessor "dd"]
+			type = dedup
+			Window = "20ms"
+			` + gate + `
+		`
+		var tc testConfigStruct
+		if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+			t.Fatal(err)
+		}
+		var tw testTagWriter
+		ps, err := tc.Preprocessor.ProcessorSet(&tw, []string{`dd`})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ps.ProcessBatch(makeEntry([]byte("hello"), 0)); err != nil {
+			t.Fatal(err)
+		}
+
+		// a quiet source is released after the window without a Flush-Interval
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			ps.Lock()
+			n := len(tw.ents)
+			ps.Unlock()
+			if n == 1 {
+				break
+			} else if time.Now().After(deadline) {
+				t.Fatalf("held entry was not released after the window with %q", gate)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		ps.Close()
+	}
+}
+
+func TestDedupUnknownKey(t *testing.T) {
+	b := `
+	[preprocessor "dd"]
+		type = dedup
+		Windw = "5s"
+	`
+	if _, err := testLoadPreprocessor(b, `dd`); !errors.Is(err, config.ErrUnknownVariable) {
+		t.Fatalf("failed to catch misspelled key: %v", err)
+	}
+	var tc testConfigStruct
+	if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	} else if err = tc.Preprocessor.Validate(); err == nil || !strings.Contains(err.Error(), `Windw`) {
+		t.Fatalf("validation did not catch misspelled key: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	FilterProcessor string = `filter`
+)
+
+var (
+	ErrNoFilterRules = errors.New("At least one Drop-Regex, Keep-Regex, Drop-Tag, or Keep-Tag must be specified")
+)
+
+// FilterConfig describes which entries are discarded by the filter processor.
+// Keep rules always take precedence over drop rules; an entry that matches any Keep-Regex
+// or Keep-Tag is kept even if it also matches a drop rule.
+// An entry that matches a Drop-Regex or Drop-Tag (and no keep rule) is dropped.
+// An entry that matches no rule at all is kept, unless only keep rules are configured, in which
+// case the keep rules act as an allow list and unmatched entries are dropped.
+type FilterConfig struct {
+	Drop_Regex []string // drop entries whose data matches any of these
+	Keep_Regex []string // always keep entries whose data matches any of these
+	Drop_Tag   []string // drop entries with any of these tags
+	Keep_Tag   []string // always keep entries with any of these tags
+	dropRes    []*regexp.Regexp
+	keepRes    []*regexp.Regexp
+}
+
+func FilterLoadConfig(vc *config.VariableConfig) (c FilterConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *FilterConfig) validate() (err error) {
+	if len(c.Drop_Regex) == 0 && len(c.Keep_Regex) == 0 && len(c.Drop_Tag) == 0 && len(c.Keep_Tag) == 0 {
+		return ErrNoFilterRules
+	}
+	if c.dropRes, err = compileFilterRegexes(`Drop-Regex`, c.Drop_Regex); err != nil {
+		return
+	}
+	if c.keepRes, err = compileFilterRegexes(`Keep-Regex`, c.Keep_Regex); err != nil {
+		return
+	}
+	for _, tags := range [][]string{c.Drop_Tag, c.Keep_Tag} {
+		for _, tag := range tags {
+			if err = ingest.CheckTag(tag); err != nil {
+				return fmt.Errorf("Invalid tag %q: %w", tag, err)
+			}
+		}
+	}
+	return
+}
+
+func compileFilterRegexes(name string, exprs []string) (res []*regexp.Regexp, err error) {
+	for _, v := range exprs {
+		var re *regexp.Regexp
+		if re, err = regexp.Compile(v); err != nil {
+			return nil, fmt.Errorf("Invalid %s %q: %w", name, v, err)
+		}
+		res = append(res, re)
+	}
+	return
+}
+
+func NewFilter(cfg FilterConfig, tgr Tagger) (*Filter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if tgr == nil && (len(cfg.Drop_Tag) > 0 || len(cfg.Keep_Tag) > 0) {
+		return nil, errors.New("A tagger is required to filter by tag")
+	}
+	return &Filter{
+		FilterConfig: cfg,
+		tgr:          tgr,
+		tags:         map[entry.EntryTag]filterAction{},
+	}, nil
+}
+
+type filterAction int
+
+const (
+	filterNone filterAction = iota
+	filterKeep
+	filterDrop
+)
+
+// Filter discards entries according to the precedence described on FilterConfig
+type Filter struct {
+	nocloser
+	FilterConfig
+	tgr  Tagger
+	tags map[entry.EntryTag]filterAction // cache of resolved tag actions
+}
+
+func (f *Filter) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(FilterConfig); ok {
+		if err = cfg.validate(); err == nil {
+			f.FilterConfig = cfg
+			f.tags = map[entry.EntryTag]filterAction{}
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (f *Filter) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	rset = ents[:0]
+	for _, ent := range ents {
+		if ent != nil && f.keep(ent) {
+			rset = append(rset, ent)
+		}
+	}
+	return
+}
+
+func (f *Filter) keep(ent *entry.Entry) bool {
+	tagAction := f.tagAction(ent.Tag)
+	if tagAction == filterKeep || matchAny(f.keepRes, ent.Data) {
+		return true
+	}
+	if tagAction == filterDrop || matchAny(f.dropRes, ent.Data) {
+		return false
+	}
+	//unmatched entries are only dropped when the keep rules are an allow list
+	return len(f.Drop_Regex) > 0 || len(f.Drop_Tag) > 0
+}
+
+func (f *Filter) tagAction(tag entry.EntryTag) (act filterAction) {
+	if len(f.Drop_Tag) == 0 && len(f.Keep_Tag) == 0 {
+		return
+	}
+	var ok bool
+	if act, ok = f.tags[tag]; ok {
+		return
+	}
+	if name, ok := f.tgr.LookupTag(tag); ok {
+		if inSet(name, f.Keep_Tag) {
+			act = filterKeep
+		} else if inSet(name, f.Drop_Tag) {
+			act = filterDrop
+		}
+	}
+	f.tags[tag] = act
+	return
+}
+
+func matchAny(res []*regexp.Regexp, data []byte) bool {
+	for _, re := range res {
+		if re.Match(data) {
+			return true
+		}
+	}
+	return false
+}
+
+func inSet(v string, set []string) bool {
+	for _, s := range set {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
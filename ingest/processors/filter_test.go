@@ -0,0 +1,115 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestFilterConfig(t *testing.T) {
+	b := `
+	[preprocessor "flt"]
+		type = filter
+		Drop-Regex = "DEBUG"
+		Drop-Regex = "^\\s*$"
+		Keep-Tag = audit
+	`
+	p, err := testLoadPreprocessor(b, `flt`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, ok := p.(*Filter)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Filter", p)
+	}
+	if len(f.dropRes) != 2 || len(f.Keep_Tag) != 1 {
+		t.Fatalf("bad config: %+v", f.FilterConfig)
+	}
+
+	for _, bad := range []string{``, `Drop-Regex = "[a-"`, `Keep-Regex = "(foo"`, `Drop-Tag = "bad tag"`} {
+		b = `
+	[preprocessor "flt"]
+		type = filter
+		` + bad + `
+	`
+		if _, err = testLoadPreprocessor(b, `flt`); err == nil {
+			t.Fatalf("failed to catch bad config %q", bad)
+		}
+	}
+}
+
+func runFilter(t *testing.T, cfg FilterConfig, tt *testTagger, ents []*entry.Entry) (r []string) {
+	t.Helper()
+	f, err := NewFilter(cfg, tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := f.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range set {
+		r = append(r, string(ent.Data))
+	}
+	return
+}
+
+func TestFilterPrecedence(t *testing.T) {
+	var tt testTagger
+	def, _ := tt.NegotiateTag(`default`)
+	audit, _ := tt.NegotiateTag(`audit`)
+	noisy, _ := tt.NegotiateTag(`noisy`)
+	batch := func() (r []*entry.Entry) {
+		for _, v := range []struct {
+			data string
+			tag  entry.EntryTag
+		}{
+			{"DEBUG chatter", def},
+			{"DEBUG but audited", audit},
+			{"INFO normal", def},
+			{"ERROR in noise", noisy},
+			{"INFO in noise", noisy},
+		} {
+			r = append(r, makeEntry([]byte(v.data), v.tag)...)
+		}
+		return
+	}
+
+	tests := []struct {
+		name string
+		cfg  FilterConfig
+		want []string
+	}{
+		{"drop regex", FilterConfig{Drop_Regex: []string{`DEBUG`}},
+			[]string{"INFO normal", "ERROR in noise", "INFO in noise"}},
+		{"keep tag beats drop regex", FilterConfig{Drop_Regex: []string{`DEBUG`}, Keep_Tag: []string{`audit`}},
+			[]string{"DEBUG but audited", "INFO normal", "ERROR in noise", "INFO in noise"}},
+		{"keep regex beats drop tag", FilterConfig{Drop_Tag: []string{`noisy`}, Keep_Regex: []string{`ERROR`}},
+			[]string{"DEBUG chatter", "DEBUG but audited", "INFO normal", "ERROR in noise"}},
+		{"keep only is an allow list", FilterConfig{Keep_Regex: []string{`INFO`}},
+			[]string{"INFO normal", "INFO in noise"}},
+		{"drop everything", FilterConfig{Drop_Regex: []string{`.`}},
+			nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := runFilter(t, tc.cfg, &tt, batch())
+			if len(got) != len(tc.want) {
+				t.Fatalf("bad survivors: %v != %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("bad survivors: %v != %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
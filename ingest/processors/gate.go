@@ -0,0 +1,134 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+// gate restricts a preprocessor to the entries which plausibly match it, it is built from
+// the Gate-Tag-Match and Gate-Data-Regex settings available on every preprocessor.
+// An entry passes the gate if its tag is one of the listed tags and its data matches the
+// regular expression, an unset condition always passes.
+type gate struct {
+	tags   map[string]bool
+	rx     *regexp.Regexp
+	tagger Tagger
+
+	mtx   sync.Mutex
+	cache map[entry.EntryTag]bool // resolved tag matches
+}
+
+// gate returns the gate described by the preprocessor settings, nil if there is none
+func (pb preprocessorBase) gate() (g *gate, err error) {
+	if len(pb.Gate_Tag_Match) == 0 && pb.Gate_Data_Regex == `` {
+		return
+	}
+	g = &gate{}
+	for _, v := range pb.Gate_Tag_Match {
+		v = strings.TrimSpace(v)
+		if err = ingest.CheckTag(v); err != nil {
+			err = fmt.Errorf("Gate-Tag-Match %q is invalid %w", v, err)
+			return
+		} else if g.tags == nil {
+			g.tags = map[string]bool{}
+		}
+		g.tags[v] = true
+	}
+	if pb.Gate_Data_Regex != `` {
+		if g.rx, err = regexp.Compile(pb.Gate_Data_Regex); err != nil {
+			err = fmt.Errorf("Gate-Data-Regex %q is invalid %w", pb.Gate_Data_Regex, err)
+		}
+	}
+	return
+}
+
+// applyGate wraps the processor in the gate described by the preprocessor settings, if any
+func (pb preprocessorBase) applyGate(p Processor, tgr Tagger) (Processor, error) {
+	g, err := pb.gate()
+	if err != nil || g == nil {
+		return p, err
+	}
+	g.tagger = tgr
+	g.cache = map[entry.EntryTag]bool{}
+	return &gatedProcessor{Processor: p, g: g}, nil
+}
+
+func (g *gate) pass(ent *entry.Entry) bool {
+	if ent == nil {
+		return false
+	} else if g.tags != nil && !g.tagMatch(ent.Tag) {
+		return false
+	}
+	return g.rx == nil || g.rx.Match(ent.Data)
+}
+
+func (g *gate) tagMatch(tg entry.EntryTag) (ok bool) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if ok, cached := g.cache[tg]; cached {
+		return ok
+	}
+	if name, found := g.tagger.LookupTag(tg); found {
+		ok = g.tags[name]
+	}
+	g.cache[tg] = ok
+	return
+}
+
+// gatedProcessor hands a preprocessor only the entries which pass its gate, the rest skip it
+// untouched. Entry order is preserved by processing consecutive runs of passing entries.
+type gatedProcessor struct {
+	Processor
+	g *gate
+}
+
+// defaultFlushInterval passes through to the gated processor so it is still flushed when quiet
+func (gp *gatedProcessor) defaultFlushInterval() time.Duration {
+	if df, ok := gp.Processor.(defaultFlusher); ok {
+		return df.defaultFlushInterval()
+	}
+	return 0
+}
+
+func (gp *gatedProcessor) Process(ents []*entry.Entry) (out []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return ents, nil
+	}
+	// processors may compact their input in place, so runs are always handed over in a new slice
+	var run []*entry.Entry
+	flush := func() {
+		if len(run) == 0 || err != nil {
+			return
+		}
+		var res []*entry.Entry
+		if res, err = gp.Processor.Process(run); err == nil {
+			out = append(out, res...)
+		}
+		run = nil
+	}
+	out = make([]*entry.Entry, 0, len(ents))
+	for _, ent := range ents {
+		if gp.g.pass(ent) {
+			run = append(run, ent)
+			continue
+		}
+		flush()
+		out = append(out, ent)
+	}
+	flush()
+	return
+}
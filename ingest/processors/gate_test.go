@@ -0,0 +1,101 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestGateConfig(t *testing.T) {
+	bad := []string{
+		`Gate-Tag-Match="bad tag"`,
+		`Gate-Data-Regex="_path("`,
+	}
+	for _, v := range bad {
+		b := `
+		[preprocessor "corelight"]
+			type = corelight
+			` + v + `
+		`
+		if _, err := testLoadPreprocessor(b, `corelight`); err == nil {
+			t.Fatalf("failed to catch bad config %q", v)
+		}
+	}
+
+	// ungated processors are not wrapped
+	p, err := testLoadPreprocessor(`
+	[preprocessor "corelight"]
+		type = corelight
+	`, `corelight`)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, ok := p.(*Corelight); !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Corelight", p)
+	}
+}
+
+func TestGate(t *testing.T) {
+	var tt testTagger
+	var tc testConfigStruct
+	b := `
+	[preprocessor "corelight"]
+		type = corelight
+		Gate-Tag-Match=zeekraw
+		Gate-Tag-Match=zeekother
+		Gate-Data-Regex="\"_path\""
+	`
+	if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	p, err := tc.Preprocessor.getProcessor(`corelight`, &tt)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, ok := p.(*gatedProcessor); !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *gatedProcessor", p)
+	}
+	raw, _ := tt.NegotiateTag(`zeekraw`)
+	other, _ := tt.NegotiateTag(`syslog`)
+	conn := tt.mp[`zeekconn`]
+
+	in := []*entry.Entry{
+		{Tag: raw, Data: []byte(conn1_in)},
+		{Tag: other, Data: []byte(conn1_in)}, // wrong tag
+		{Tag: raw, Data: []byte(`hello`)},    // data does not match
+		nil,
+		{Tag: raw, Data: []byte(conn1_in)},
+		{Tag: raw, Data: []byte(conn1_in)},
+	}
+	orig := append([]*entry.Entry(nil), in...)
+	ents, err := p.Process(in)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ents) != len(orig) {
+		t.Fatalf("bad entry count %d != %d", len(ents), len(orig))
+	}
+	for i, ent := range ents {
+		if ent != orig[i] {
+			t.Fatalf("entry %d is out of order", i)
+		}
+	}
+	for _, i := range []int{0, 4, 5} {
+		if ents[i].Tag != conn {
+			t.Fatalf("gated entry %d was not processed: %d != %d", i, ents[i].Tag, conn)
+		} else if string(ents[i].Data) == conn1_in {
+			t.Fatalf("gated entry %d data was not converted", i)
+		}
+	}
+	if ents[1].Tag != other || string(ents[1].Data) != conn1_in {
+		t.Fatal("entry with a non-matching tag was modified")
+	} else if ents[2].Tag != raw || string(ents[2].Data) != `hello` {
+		t.Fatal("entry with non-matching data was modified")
+	}
+}
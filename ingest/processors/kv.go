@@ -0,0 +1,334 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	KVProcessor string = `kv`
+
+	kvFormatJSON = `json`
+	kvFormatTSV  = `tsv`
+
+	defaultKVPairSeparator = " "
+	defaultKVSeparator     = "="
+)
+
+type KVConfig struct {
+	// Pair_Separator separates key/value pairs, it defaults to a space.
+	// Runs of the separator are treated as one.
+	Pair_Separator string
+
+	// KV_Separator separates a key from its value, it defaults to "=".
+	KV_Separator string
+
+	// Format specifies the output format, either "json" (the default) or "tsv".
+	Format string
+
+	// Columns specifies the keys emitted, in order, by the tsv format, there can be many, e.g.:
+	//	Columns=level
+	//	Columns=msg
+	Columns []string
+
+	// Field_Separator specifies the separator placed between TSV columns, it defaults to a tab.
+	// Occurrences of the separator within values are replaced with a space.
+	Field_Separator string
+
+	// Empty_Field_Marker specifies the TSV value emitted for missing keys, it defaults to "-".
+	Empty_Field_Marker string
+
+	// Timestamp_Key optionally names the key holding the entry timestamp.
+	Timestamp_Key string
+
+	// Timestamp_Override optionally forces the format used to parse Timestamp_Key.
+	// Numeric values are always parsed as epochs, whose unit is detected from their magnitude.
+	Timestamp_Override string
+
+	// Timezone_Override optionally specifies the location of timestamps without a timezone.
+	Timezone_Override string
+
+	// Assume_Local_Timezone interprets timestamps without a timezone as local time.
+	Assume_Local_Timezone bool
+}
+
+func KVLoadConfig(vc *config.VariableConfig) (c KVConfig, err error) {
+	c.Pair_Separator = defaultKVPairSeparator
+	c.KV_Separator = defaultKVSeparator
+	c.Field_Separator = defaultFieldSeparator
+	c.Empty_Field_Marker = defaultEmptyFieldMarker
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *KVConfig) validate() (err error) {
+	if c.Pair_Separator == `` {
+		return errors.New("Pair-Separator may not be empty")
+	} else if c.KV_Separator == `` {
+		return errors.New("KV-Separator may not be empty")
+	} else if c.Pair_Separator == c.KV_Separator {
+		return errors.New("Pair-Separator and KV-Separator must differ")
+	} else if strings.Contains(c.Pair_Separator, `"`) || strings.Contains(c.KV_Separator, `"`) {
+		return errors.New("Pair-Separator and KV-Separator may not contain quotes")
+	}
+	switch c.Format = strings.ToLower(strings.TrimSpace(c.Format)); c.Format {
+	case ``:
+		c.Format = kvFormatJSON
+	case kvFormatJSON, kvFormatTSV:
+	default:
+		return fmt.Errorf("Format %q is invalid, must be %q or %q", c.Format, kvFormatJSON, kvFormatTSV)
+	}
+	c.Columns = cleanHeaders(c.Columns)
+	if c.Format == kvFormatTSV {
+		if len(c.Columns) == 0 {
+			return errors.New("the tsv format requires at least one Columns value")
+		} else if c.Field_Separator == `` {
+			return errors.New("Field-Separator may not be empty")
+		} else if strings.ContainsAny(c.Field_Separator, "\r\n") {
+			return fmt.Errorf("Field-Separator %q may not contain newlines", c.Field_Separator)
+		}
+	} else if len(c.Columns) > 0 {
+		return errors.New("Columns requires the tsv format")
+	}
+	c.Timestamp_Key = strings.TrimSpace(c.Timestamp_Key)
+	if c.Timestamp_Key == `` && (strings.TrimSpace(c.Timestamp_Override) != `` || c.Timezone_Override != ``) {
+		return errors.New("Timestamp-Override and Timezone-Override require Timestamp-Key")
+	}
+	return c.timeConfig().Validate()
+}
+
+// timeConfig returns the settings used to parse the value of Timestamp_Key
+func (c KVConfig) timeConfig() TimeExtractorConfig {
+	return TimeExtractorConfig{
+		FormatOverride:      c.Timestamp_Override,
+		TimezoneOverride:    c.Timezone_Override,
+		AssumeLocalTimezone: c.Assume_Local_Timezone,
+	}
+}
+
+// KV parses logfmt style key=value entries, e.g.:
+//
+//	level=info msg="user logged in" user=bob
+//
+// and rewrites them as JSON objects or as TSV with a fixed column order.
+// Entries that are not logfmt pass through unchanged.
+type KV struct {
+	nocloser
+	KVConfig
+	te *TimeExtractor // nil unless Timestamp_Key is set
+}
+
+func NewKV(cfg KVConfig) (*KV, error) {
+	kv := &KV{}
+	if err := kv.Config(cfg); err != nil {
+		return nil, err
+	}
+	return kv, nil
+}
+
+func (kv *KV) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(KVConfig); ok {
+		if err = cfg.validate(); err != nil {
+			return
+		}
+		var te *TimeExtractor
+		if cfg.Timestamp_Key != `` {
+			if te, err = NewTimeExtractor(cfg.timeConfig()); err != nil {
+				return
+			}
+		}
+		kv.KVConfig, kv.te = cfg, te
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (kv *KV) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	for _, ent := range ents {
+		if ent == nil || len(ent.Data) == 0 {
+			continue
+		}
+		pairs := kv.parse(ent.Data)
+		if len(pairs) == 0 {
+			continue
+		}
+		if kv.te != nil {
+			if v, ok := pairs.get(kv.Timestamp_Key); ok {
+				if ts, ok := kv.te.Extract([]byte(v)); ok {
+					ent.TS = entry.FromStandard(ts)
+				}
+			}
+		}
+		if kv.Format == kvFormatTSV {
+			ent.Data = kv.emitLine(pairs)
+		} else {
+			ent.Data = kv.emitJSON(pairs)
+		}
+	}
+	return ents, nil
+}
+
+type kvPair struct {
+	key, val string
+}
+
+// kvPairs are the pairs of an entry in the order they first appeared, a repeated key
+// replaces the value of the earlier pair.
+type kvPairs []kvPair
+
+func (p kvPairs) get(key string) (string, bool) {
+	for _, v := range p {
+		if v.key == key {
+			return v.val, true
+		}
+	}
+	return ``, false
+}
+
+func (p kvPairs) set(key, val string) kvPairs {
+	for i := range p {
+		if p[i].key == key {
+			p[i].val = val
+			return p
+		}
+	}
+	return append(p, kvPair{key: key, val: val})
+}
+
+// parse splits logfmt data into pairs. Values may be double quoted, in which case they
+// can contain either separator and Go style escapes such as \" and \n. A key with no
+// separator has an empty value. Nil is returned for malformed quoting or if there is
+// no key with a separator, so that free text is not mistaken for a list of bare keys.
+func (kv *KV) parse(data []byte) (pairs kvPairs) {
+	psep, kvsep := []byte(kv.Pair_Separator), []byte(kv.KV_Separator)
+	var found bool
+	for {
+		for bytes.HasPrefix(data, psep) {
+			data = data[len(psep):]
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			if !found {
+				pairs = nil
+			}
+			return
+		}
+		end := bytes.Index(data, psep)
+		if end == -1 {
+			end = len(data)
+		}
+		idx := bytes.Index(data[:end], kvsep)
+		if idx == -1 {
+			//bare key
+			if key := string(bytes.TrimSpace(data[:end])); key != `` {
+				pairs = pairs.set(key, ``)
+			}
+			data = data[end:]
+			continue
+		}
+		key := string(bytes.TrimSpace(data[:idx]))
+		found = found || key != ``
+		data = data[idx+len(kvsep):]
+		var val string
+		if len(data) > 0 && data[0] == '"' {
+			n := quotedLen(data)
+			if n == -1 {
+				return nil
+			}
+			var err error
+			if val, err = strconv.Unquote(string(data[:n])); err != nil {
+				return nil
+			}
+			data = data[n:]
+		} else {
+			if end = bytes.Index(data, psep); end == -1 {
+				end = len(data)
+			}
+			val = string(data[:end])
+			data = data[end:]
+		}
+		if key != `` {
+			pairs = pairs.set(key, val)
+		}
+	}
+}
+
+// quotedLen returns the length of the double quoted string at the start of data,
+// including the quotes, or -1 if it is not terminated
+func quotedLen(data []byte) int {
+	for i := 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// emitLine writes the configured columns as a TSV line
+func (kv *KV) emitLine(pairs kvPairs) []byte {
+	return pairs.emitLine(kv.Columns, kv.Field_Separator, kv.Empty_Field_Marker)
+}
+
+// emitJSON writes the pairs as a JSON object with string values, preserving key order
+func (kv *KV) emitJSON(pairs kvPairs) []byte {
+	return pairs.emitJSON()
+}
+
+// emitLine writes the values of cols, in order, as a line delimited by sep. Missing and empty
+// values are written as empty, occurrences of sep within values are replaced with a space.
+func (p kvPairs) emitLine(cols []string, sep, empty string) []byte {
+	bb := bytes.NewBuffer(nil)
+	for i, col := range cols {
+		if i > 0 {
+			bb.WriteString(sep)
+		}
+		if v, ok := p.get(col); ok && v != `` {
+			bb.WriteString(strings.ReplaceAll(v, sep, " "))
+		} else {
+			bb.WriteString(empty)
+		}
+	}
+	return bb.Bytes()
+}
+
+// emitJSON writes the pairs as a JSON object with string values, preserving key order
+func (p kvPairs) emitJSON() []byte {
+	bb := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(bb)
+	enc.SetEscapeHTML(false)
+	bb.WriteByte('{')
+	for i, v := range p {
+		if i > 0 {
+			bb.WriteByte(',')
+		}
+		enc.Encode(v.key)
+		bb.Truncate(bb.Len() - 1) //Encode appends a newline
+		bb.WriteByte(':')
+		enc.Encode(v.val)
+		bb.Truncate(bb.Len() - 1)
+	}
+	bb.WriteByte('}')
+	return bb.Bytes()
+}
@@ -0,0 +1,169 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestKVConfig(t *testing.T) {
+	b := `
+	[preprocessor "kv"]
+		type = kv
+	`
+	p, err := testLoadPreprocessor(b, `kv`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv, ok := p.(*KV)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *KV", p)
+	} else if kv.Format != kvFormatJSON || kv.Pair_Separator != ` ` || kv.KV_Separator != `=` {
+		t.Fatalf("bad defaults: %+v", kv.KVConfig)
+	}
+
+	bad := []string{
+		`Format=xml`,
+		`Format=tsv`,                 // no columns
+		`Columns=level`,              // columns without tsv
+		`KV-Separator=" "`,           // same as the pair separator
+		`Pair-Separator="\""`,        // quotes
+		`Timestamp-Override=RFC3339`, // no key
+		`Timezone-Override=UTC`,      // no key
+		`Timestamp-Key=ts
+		Timestamp-Override=NotAFormat`, // bad override
+		`Timestamp-Key=ts
+		Timezone-Override=Nowhere/Special`, // bad timezone
+		`Timestamp-Key=ts
+		Timezone-Override=UTC
+		Assume-Local-Timezone=true`, // conflicting timezones
+	}
+	for _, v := range bad {
+		b = `
+		[preprocessor "kv"]
+			type = kv
+			` + v + `
+		`
+		if _, err = testLoadPreprocessor(b, `kv`); err == nil {
+			t.Fatalf("failed to catch bad config %q", v)
+		}
+	}
+}
+
+func TestKVParse(t *testing.T) {
+	kv, err := NewKV(KVConfig{Pair_Separator: ` `, KV_Separator: `=`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in  string
+		out kvPairs
+	}{
+		{`a=1 b=2`, kvPairs{{`a`, `1`}, {`b`, `2`}}},
+		{`  a=1    b=  c=3 `, kvPairs{{`a`, `1`}, {`b`, ``}, {`c`, `3`}}},
+		{`msg="hello world" level=info`, kvPairs{{`msg`, `hello world`}, {`level`, `info`}}},
+		{`msg="say \"hi\"\n" x=a=b`, kvPairs{{`msg`, "say \"hi\"\n"}, {`x`, `a=b`}}},
+		{`debug a=1 a=2`, kvPairs{{`debug`, ``}, {`a`, `2`}}},
+		{`just some text`, nil},
+		{`msg="unterminated a=1`, nil},
+		{`msg="bad \q escape"`, nil},
+		{``, nil},
+	}
+	for i, tt := range tests {
+		pairs := kv.parse([]byte(tt.in))
+		if len(pairs) != len(tt.out) {
+			t.Fatalf("%d: bad pairs %v != %v", i, pairs, tt.out)
+		}
+		for j := range pairs {
+			if pairs[j] != tt.out[j] {
+				t.Fatalf("%d: bad pair %d %v != %v", i, j, pairs[j], tt.out[j])
+			}
+		}
+	}
+
+	// alternate separators
+	if kv, err = NewKV(KVConfig{Pair_Separator: `|`, KV_Separator: `:`}); err != nil {
+		t.Fatal(err)
+	}
+	if pairs := kv.parse([]byte(`a:x y|b:"1|2"||c:3`)); len(pairs) != 3 || pairs[0].val != `x y` || pairs[1].val != `1|2` || pairs[2].val != `3` {
+		t.Fatalf("bad pairs %v", pairs)
+	}
+}
+
+func TestKVProcess(t *testing.T) {
+	b := `
+	[preprocessor "kv"]
+		type = kv
+		Timestamp-Key=ts
+	`
+	p, err := testLoadPreprocessor(b, `kv`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	ents := []*entry.Entry{
+		{Data: []byte(`ts=2024-03-01T12:30:00Z level=info msg="a <b> & \"c\""`)},
+		{Data: []byte(`not logfmt`)},
+	}
+	if ents, err = p.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"ts":"2024-03-01T12:30:00Z","level":"info","msg":"a <b> & \"c\""}`; string(ents[0].Data) != exp {
+		t.Fatalf("bad JSON:\n%s\n%s", ents[0].Data, exp)
+	} else if !ents[0].TS.StandardTime().Equal(want) {
+		t.Fatalf("bad timestamp %v != %v", ents[0].TS, want)
+	} else if string(ents[1].Data) != `not logfmt` {
+		t.Fatalf("non-logfmt entry was modified: %s", ents[1].Data)
+	}
+
+	// epochs are detected, and the timezone override applies to timestamps without one
+	b = `
+	[preprocessor "kv"]
+		type = kv
+		Timestamp-Key=ts
+		Timezone-Override=America/New_York
+	`
+	if p, err = testLoadPreprocessor(b, `kv`); err != nil {
+		t.Fatal(err)
+	}
+	ents = []*entry.Entry{
+		{Data: []byte(`ts=1709296200000 level=info`)},
+		{Data: []byte(`ts="2024-03-01 07:30:00" level=info`)},
+	}
+	if ents, err = p.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	for i, ent := range ents {
+		if !ent.TS.StandardTime().Equal(want) {
+			t.Fatalf("entry %d has a bad timestamp %v != %v", i, ent.TS, want)
+		}
+	}
+
+	b = `
+	[preprocessor "kv"]
+		type = kv
+		Format=tsv
+		Columns=level
+		Columns=msg
+		Columns=user
+	`
+	if p, err = testLoadPreprocessor(b, `kv`); err != nil {
+		t.Fatal(err)
+	}
+	ents = []*entry.Entry{{Data: []byte(`user=bob extra=1 msg="tab	inside" level=warn`)}, {Data: []byte(`level=info`)}}
+	if ents, err = p.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if exp := "warn\ttab inside\tbob"; string(ents[0].Data) != exp {
+		t.Fatalf("bad TSV %q != %q", ents[0].Data, exp)
+	} else if exp = "info\t-\t-"; string(ents[1].Data) != exp {
+		t.Fatalf("bad TSV %q != %q", ents[1].Data, exp)
+	}
+}
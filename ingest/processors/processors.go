@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
@@ -35,31 +36,47 @@ var (
 	ErrNotFound         = errors.New("Processor not found")
 	ErrNotReady         = errors.New("ProcessorSet not ready")
 	ErrInvalidEntry     = errors.New("ErrInvalidEntry")
+	ErrInvalidFlush     = errors.New("Flush-Interval must be positive")
 
 	emptyStruct = []byte(`{}`)
 )
 
 type ProcessorSet struct {
 	sync.Mutex
-	wtr entWriter
-	set []Processor
+	wtr      entWriter
+	set      []Processor
+	flushWg  sync.WaitGroup
+	flushDie chan struct{} // nil until a periodic flush is started
 }
 
 type ProcessorConfig map[string]*config.VariableConfig
 
 // Processor is an interface that takes an entry and processes it, returning a new block
+// Processors which buffer entries emit them from Flush, which is called when the ProcessorSet
+// is closed and periodically if the preprocessor specifies a Flush-Interval.
+// Stateless processors embed nocloser for no-op Flush and Close methods.
 type Processor interface {
 	Process([]*entry.Entry) ([]*entry.Entry, error) //process an data item potentially setting a tag
-	Flush() []*entry.Entry
-	Close() error //give the processor a chance to tidy up
+	Flush() []*entry.Entry                          //emit any buffered entries
+	Close() error                                   //give the processor a chance to tidy up
+}
+
+// defaultFlusher is implemented by processors which must be flushed periodically even when no
+// Flush-Interval is specified, such as those holding entries until a later one arrives.
+type defaultFlusher interface {
+	defaultFlushInterval() time.Duration
 }
 
 func CheckProcessor(id string) error {
 	id = strings.TrimSpace(strings.ToLower(id))
 	switch id {
+	case CSVProcessor:
 	case CSVRouterProcessor:
 	case CiscoISEProcessor:
+	case DecompressProcessor:
+	case DedupProcessor:
 	case DropProcessor:
+	case FilterProcessor:
 	case ForwarderProcessor:
 	case GravwellForwarderProcessor:
 	case GzipProcessor:
@@ -71,10 +88,17 @@ func CheckProcessor(id string) error {
 	case RegexExtractProcessor:
 	case RegexRouterProcessor:
 	case RegexTimestampProcessor:
+	case SamplerProcessor:
+	case SplitProcessor:
 	case SrcRouterProcessor:
 	case VpcProcessor:
 	case CorelightProcessor:
 	case SyslogRouterProcessor:
+	case KVProcessor:
+	case TapProcessor:
+	case RenameProcessor:
+	case WinlogProcessor:
+	case Base64DecodeProcessor:
 	default:
 		return checkProcessorOS(id)
 	}
@@ -95,15 +119,43 @@ type entWriter interface {
 }
 
 type preprocessorBase struct {
-	Type string
+	Type            string
+	Flush_Interval  string   // optional interval at which the preprocessor is flushed
+	Gate_Tag_Match  []string // optional tags the preprocessor is restricted to, others skip it
+	Gate_Data_Regex string   // optional regex entry data must match for the preprocessor to see it
+}
+
+// mapToStrict maps a preprocessor config block into v, returning an error for any key which is
+// neither a field of v nor one of the preprocessorBase settings common to every preprocessor
+func mapToStrict(vc *config.VariableConfig, v interface{}) error {
+	return vc.MapToStrict(v, `Type`, `Flush_Interval`, `Gate_Tag_Match`, `Gate_Data_Regex`)
+}
+
+// flushInterval returns the periodic flush interval, zero if none was specified
+func (pb preprocessorBase) flushInterval() (d time.Duration, err error) {
+	if pb.Flush_Interval == `` {
+		return
+	}
+	if d, err = time.ParseDuration(pb.Flush_Interval); err != nil {
+		err = fmt.Errorf("Invalid Flush-Interval %q: %v", pb.Flush_Interval, err)
+	} else if d <= 0 {
+		err = ErrInvalidFlush
+	}
+	return
 }
 
 func ProcessorLoadConfig(vc *config.VariableConfig) (cfg interface{}, err error) {
 	var pb preprocessorBase
 	if err = vc.MapTo(&pb); err != nil {
 		return
+	} else if _, err = pb.flushInterval(); err != nil {
+		return
+	} else if _, err = pb.gate(); err != nil {
+		return
 	}
 	switch strings.TrimSpace(strings.ToLower(pb.Type)) {
+	case DedupProcessor:
+		cfg, err = DedupLoadConfig(vc)
 	case DropProcessor:
 		cfg, err = DropLoadConfig(vc)
 	case GzipProcessor:
@@ -118,6 +170,16 @@ func ProcessorLoadConfig(vc *config.VariableConfig) (cfg interface{}, err error)
 		cfg, err = JsonTimestampLoadConfig(vc)
 	case RegexTimestampProcessor:
 		cfg, err = RegexTimestampLoadConfig(vc)
+	case SamplerProcessor:
+		cfg, err = SamplerLoadConfig(vc)
+	case SplitProcessor:
+		cfg, err = SplitLoadConfig(vc)
+	case FilterProcessor:
+		cfg, err = FilterLoadConfig(vc)
+	case DecompressProcessor:
+		cfg, err = DecompressLoadConfig(vc)
+	case CSVProcessor:
+		cfg, err = CSVLoadConfig(vc)
 	case RegexExtractProcessor:
 		cfg, err = RegexExtractLoadConfig(vc)
 	case RegexRouterProcessor:
@@ -140,6 +202,16 @@ func ProcessorLoadConfig(vc *config.VariableConfig) (cfg interface{}, err error)
 		cfg, err = CorelightLoadConfig(vc)
 	case SyslogRouterProcessor:
 		cfg, err = SyslogRouterLoadConfig(vc)
+	case KVProcessor:
+		cfg, err = KVLoadConfig(vc)
+	case TapProcessor:
+		cfg, err = TapLoadConfig(vc)
+	case RenameProcessor:
+		cfg, err = RenameLoadConfig(vc)
+	case WinlogProcessor:
+		cfg, err = WinlogLoadConfig(vc)
+	case Base64DecodeProcessor:
+		cfg, err = Base64DecodeLoadConfig(vc)
 	default:
 		cfg, err = processorLoadConfigOS(vc)
 	}
@@ -188,6 +260,12 @@ func newProcessor(vc *config.VariableConfig, tgr Tagger) (p Processor, err error
 	}
 	id := strings.TrimSpace(strings.ToLower(pb.Type))
 	switch id {
+	case DedupProcessor:
+		var cfg DedupConfig
+		if cfg, err = DedupLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewDedup(cfg)
 	case DropProcessor:
 		var cfg DropConfig
 		if err = vc.MapTo(&cfg); err != nil {
@@ -238,7 +316,7 @@ func newProcessor(vc *config.VariableConfig, tgr Tagger) (p Processor, err error
 		p, err = NewRegexExtractor(cfg)
 	case RegexRouterProcessor:
 		var cfg RegexRouteConfig
-		if err = vc.MapTo(&cfg); err != nil {
+		if cfg, err = RegexRouteLoadConfig(vc); err != nil {
 			return
 		}
 		p, err = NewRegexRouter(cfg, tgr)
@@ -297,9 +375,72 @@ func newProcessor(vc *config.VariableConfig, tgr Tagger) (p Processor, err error
 			return
 		}
 		p, err = NewSyslogRouter(cfg, tgr)
+	case KVProcessor:
+		var cfg KVConfig
+		if cfg, err = KVLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewKV(cfg)
+	case TapProcessor:
+		var cfg TapConfig
+		if cfg, err = TapLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewTap(cfg, tgr)
+	case RenameProcessor:
+		var cfg RenameConfig
+		if cfg, err = RenameLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewRename(cfg, tgr)
+	case WinlogProcessor:
+		var cfg WinlogConfig
+		if cfg, err = WinlogLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewWinlog(cfg, tgr)
+	case SamplerProcessor:
+		var cfg SamplerConfig
+		if cfg, err = SamplerLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewSampler(cfg)
+	case SplitProcessor:
+		var cfg SplitConfig
+		if cfg, err = SplitLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewSplit(cfg)
+	case FilterProcessor:
+		var cfg FilterConfig
+		if cfg, err = FilterLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewFilter(cfg, tgr)
+	case DecompressProcessor:
+		var cfg DecompressConfig
+		if cfg, err = DecompressLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewDecompressor(cfg)
+	case Base64DecodeProcessor:
+		var cfg Base64DecodeConfig
+		if cfg, err = Base64DecodeLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewBase64Decoder(cfg)
+	case CSVProcessor:
+		var cfg CSVConfig
+		if cfg, err = CSVLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewCSV(cfg)
 	default:
 		p, err = newProcessorOS(vc, tgr)
 	}
+	if err == nil {
+		p, err = pb.applyGate(p, tgr)
+	}
 	return
 }
 
@@ -402,14 +543,18 @@ func (pr *ProcessorSet) ProcessBatchContext(ents []*entry.Entry, ctx context.Con
 }
 
 func (pr *ProcessorSet) writeSet(ents []*entry.Entry) error {
-	if len(ents) == 1 {
+	if len(ents) == 0 {
+		return nil //everything was dropped or buffered
+	} else if len(ents) == 1 {
 		return pr.wtr.WriteEntry(ents[0])
 	}
 	return pr.wtr.WriteBatch(ents)
 }
 
 func (pr *ProcessorSet) writeSetContext(ents []*entry.Entry, ctx context.Context) error {
-	if len(ents) == 1 {
+	if len(ents) == 0 {
+		return nil
+	} else if len(ents) == 1 {
 		return pr.wtr.WriteEntryContext(ctx, ents[0])
 	}
 	return pr.wtr.WriteBatchContext(ctx, ents)
@@ -444,21 +589,78 @@ func (pr *ProcessorSet) processItemsOnFlush(prs []Processor, ents []*entry.Entry
 	return
 }
 
+// Flush flushes every preprocessor in the set, pushing any buffered entries through the
+// downstream preprocessors and out to the writer.
+func (pr *ProcessorSet) Flush() (err error) {
+	pr.Lock()
+	defer pr.Unlock()
+	if pr.wtr == nil {
+		return ErrNotReady
+	}
+	for i := range pr.set {
+		err = addError(pr.flushProcessor(i), err)
+	}
+	return
+}
+
+// flushProcessor flushes the preprocessor at index i, the caller must hold the lock
+func (pr *ProcessorSet) flushProcessor(i int) (err error) {
+	v := pr.set[i]
+	if v == nil {
+		return
+	}
+	if ents := v.Flush(); len(ents) > 0 {
+		if ents, err = pr.processItemsOnFlush(pr.set[i+1:], ents); err == nil && len(ents) > 0 {
+			err = pr.writeSet(ents)
+		}
+	}
+	return
+}
+
+// flushEvery periodically flushes the preprocessor at index i until the set is closed
+func (pr *ProcessorSet) flushEvery(i int, d time.Duration) {
+	pr.Lock()
+	if pr.flushDie == nil {
+		pr.flushDie = make(chan struct{})
+	}
+	die := pr.flushDie
+	pr.Unlock()
+	pr.flushWg.Add(1)
+	go func() {
+		defer pr.flushWg.Done()
+		tkr := time.NewTicker(d)
+		defer tkr.Stop()
+		for {
+			select {
+			case <-die:
+				return
+			case <-tkr.C:
+				pr.Lock()
+				if pr.wtr != nil {
+					pr.flushProcessor(i)
+				}
+				pr.Unlock()
+			}
+		}
+	}()
+}
+
 // Close will close the underlying preprocessors within the set.
+// Periodic flushing is stopped and each preprocessor is flushed a final time before being closed.
 // This function DOES NOT close the ingest muxer handle.
 // It is ONLY for shutting down preprocessors
 func (pr *ProcessorSet) Close() (err error) {
+	pr.Lock()
+	die := pr.flushDie
+	pr.flushDie = nil
+	pr.Unlock()
+	if die != nil {
+		close(die)
+		pr.flushWg.Wait()
+	}
 	for i, v := range pr.set {
 		if v != nil {
-			if ents := v.Flush(); len(ents) > 0 {
-				if ents, lerr := pr.processItemsOnFlush(pr.set[i+1:], ents); lerr != nil {
-					err = addError(lerr, err)
-				} else if len(ents) > 0 {
-					if lerr := pr.writeSet(ents); lerr != nil {
-						err = addError(lerr, err)
-					}
-				}
-			}
+			err = addError(pr.flushProcessor(i), err)
 			if lerr := v.Close(); lerr != nil {
 				err = addError(lerr, err)
 			}
@@ -488,13 +690,30 @@ func (pc ProcessorConfig) ProcessorSet(t tagWriter, names []string) (pr *Process
 	}
 	pr = NewProcessorSet(t)
 	var p Processor
-	for _, n := range names {
+	intervals := make([]time.Duration, len(names))
+	for i, n := range names {
+		var pb preprocessorBase
 		if p, err = pc.getProcessor(n, t); err != nil {
 			err = fmt.Errorf("%s %v", n, err)
 			return
+		} else if err = pc[n].MapTo(&pb); err != nil {
+			err = fmt.Errorf("%s %v", n, err)
+			return
+		} else if intervals[i], err = pb.flushInterval(); err != nil {
+			err = fmt.Errorf("%s %v", n, err)
+			return
+		}
+		if df, ok := p.(defaultFlusher); ok && intervals[i] == 0 {
+			intervals[i] = df.defaultFlushInterval()
 		}
 		pr.AddProcessor(p)
 	}
+	//only start flushing once the entire set is built
+	for i, d := range intervals {
+		if d > 0 {
+			pr.flushEvery(i, d)
+		}
+	}
 	return
 }
 
@@ -18,6 +18,7 @@ import (
 	"net"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
@@ -163,6 +164,83 @@ func TestMultiProcessorSet(t *testing.T) {
 	return
 }
 
+type testTagWriter struct {
+	testWriter
+	testTagger
+}
+
+func TestProcessorSetFlush(t *testing.T) {
+	b := `
+	[preprocessor "dd"]
+		type = dedup
+		Flush-Interval = "20ms"
+	[preprocessor "dd2"]
+		type = dedup
+		Window = "1h"
+	`
+	var tc testConfigStruct
+	if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	var tw testTagWriter
+	ps, err := tc.Preprocessor.ProcessorSet(&tw, []string{`dd`, `dd2`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err = ps.ProcessBatch(makeEntry([]byte("hello"), 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	//the first dedup is flushed periodically, but the second holds the entry for its window
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ps.Lock()
+		flushed := ps.set[0].(*Dedup).runs[0] == nil
+		ps.Unlock()
+		if flushed {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("preprocessor was not flushed on the interval")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ps.Lock()
+	n := len(tw.ents)
+	ps.Unlock()
+	if n != 0 {
+		t.Fatalf("entry escaped the downstream preprocessor: %d", n)
+	}
+
+	if err = ps.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tw.ents) != 1 {
+		t.Fatalf("entry was not flushed on close: %d", len(tw.ents))
+	}
+	if v, ok := tw.ents[0].GetEnumeratedValue(dedupCountEV); !ok || v != uint64(2) {
+		t.Fatalf("bad repeat count: %v", v)
+	}
+
+	b = `
+	[preprocessor "dd"]
+		type = dedup
+		Flush-Interval = "-1s"
+	`
+	if _, err = testLoadPreprocessor(b, `dd`); err != nil {
+		t.Fatal(err) //the interval belongs to the set, not the preprocessor
+	}
+	tc = testConfigStruct{}
+	if err = config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	} else if err = tc.Preprocessor.Validate(); err == nil {
+		t.Fatal("failed to catch bad Flush-Interval")
+	} else if _, err = tc.Preprocessor.ProcessorSet(&tw, []string{`dd`}); err == nil {
+		t.Fatal("failed to catch bad Flush-Interval")
+	}
+}
+
 func gzipCompressVal(x string) (r []byte, err error) {
 	bwtr := bytes.NewBuffer(nil)
 	gzw := gzip.NewWriter(bwtr)
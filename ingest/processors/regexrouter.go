@@ -14,6 +14,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/gravwell/gravwell/v3/ingest"
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 )
@@ -29,14 +30,32 @@ var (
 	ErrMissingRouteExtraction = errors.New("Missing route extraction name")
 	ErrMissingRoutes          = errors.New("Missing route specifications")
 	ErrMissingExtractNames    = errors.New("Regular expression does not extract any names")
+	ErrRuleAndRegex           = errors.New("Rule cannot be combined with Regex, Route-Extraction, or Route")
+	ErrDefaultAndDropMisses   = errors.New("Default-Tag cannot be combined with Drop-Misses")
+	ErrMissingAllowedTags     = errors.New("Rules with a dynamic tag require Allowed-Tags")
+	ErrUnusedAllowedTags      = errors.New("Allowed-Tags requires a Rule with a dynamic tag")
 )
 var empty struct{}
 
+// tagTemplateRefs matches the $name and ${name} references of a dynamic tag
+var tagTemplateRefs = regexp.MustCompile(`\$(?:\{(\w+)\}|(\w+))`)
+
 type RegexRouteConfig struct {
 	Regex            string
 	Route_Extraction string
 	Route            []string
 	Drop_Misses      bool
+
+	// Rule is an ordered list of regex=tag rules evaluated against the entry data, the
+	// first matching rule wins. The tag may reference named groups of its regex to build
+	// the tag dynamically, e.g. Rule="^(?P<app>\w+)\[=app-${app}", dynamic tags must be
+	// listed in Allowed_Tags. Rule is an alternative to Regex, Route_Extraction, and Route.
+	Rule []string
+	// Allowed_Tags lists every tag a dynamic rule may produce, entries whose dynamic
+	// tag is not listed are handled as if they matched no rule.
+	Allowed_Tags []string
+	// Default_Tag optionally retags entries which are not routed, by default they keep their tag.
+	Default_Tag string
 }
 
 type route struct {
@@ -45,6 +64,13 @@ type route struct {
 	drop bool
 }
 
+// regexRule is a single ordered Rule, tmpl is empty unless the tag is built from named groups
+type regexRule struct {
+	rxp  *regexp.Regexp
+	tag  string
+	tmpl []byte
+}
+
 type RegexRouter struct {
 	nocloser
 	RegexRouteConfig
@@ -52,10 +78,15 @@ type RegexRouter struct {
 	drops    map[string]struct{}
 	matchIdx int
 	rxp      *regexp.Regexp
+	rules    []regexRule
+	ruleTags []entry.EntryTag          // resolved tags of static rules, indexed as rules
+	allowed  map[string]entry.EntryTag // resolved Allowed_Tags
+	defTag   entry.EntryTag
+	retag    bool // set if Default_Tag is in use
 }
 
 func RegexRouteLoadConfig(vc *config.VariableConfig) (c RegexRouteConfig, err error) {
-	err = vc.MapTo(&c)
+	err = mapToStrict(vc, &c)
 	return
 }
 
@@ -80,12 +111,47 @@ func (rr *RegexRouter) Config(v interface{}, tagger Tagger) (err error) {
 
 func (rr *RegexRouter) init(cfg RegexRouteConfig, tagger Tagger) (err error) {
 	var rts []route
-	if rr.rxp, rts, rr.matchIdx, err = cfg.validate(); err != nil {
+	var rules []regexRule
+	if len(cfg.Rule) > 0 {
+		if rules, err = cfg.validateRules(); err != nil {
+			return
+		}
+	} else if rr.rxp, rts, rr.matchIdx, err = cfg.validate(); err != nil {
+		return
+	}
+	if err = cfg.validateDefault(); err != nil {
 		return
 	}
 	rr.RegexRouteConfig = cfg
 	rr.routes = make(map[string]entry.EntryTag)
 	rr.drops = make(map[string]struct{})
+	rr.rules = rules
+	rr.ruleTags = make([]entry.EntryTag, len(rules))
+	rr.allowed = make(map[string]entry.EntryTag, len(cfg.Allowed_Tags))
+	rr.retag = false
+	//negotiate every tag we could route to up front
+	for i, r := range rules {
+		if r.tmpl != nil {
+			continue
+		} else if rr.ruleTags[i], err = tagger.NegotiateTag(r.tag); err != nil {
+			err = fmt.Errorf("Failed to get tag %s for rule %s: %v", r.tag, r.rxp, err)
+			return
+		}
+	}
+	for _, v := range cfg.Allowed_Tags {
+		v = strings.TrimSpace(v)
+		if rr.allowed[v], err = tagger.NegotiateTag(v); err != nil {
+			err = fmt.Errorf("Failed to get allowed tag %s: %v", v, err)
+			return
+		}
+	}
+	if cfg.Default_Tag != `` {
+		if rr.defTag, err = tagger.NegotiateTag(strings.TrimSpace(cfg.Default_Tag)); err != nil {
+			err = fmt.Errorf("Failed to get default tag %s: %v", cfg.Default_Tag, err)
+			return
+		}
+		rr.retag = true
+	}
 	for _, r := range rts {
 		if r.drop {
 			rr.drops[r.val] = empty
@@ -117,14 +183,46 @@ func (rr *RegexRouter) Process(ents []*entry.Entry) (rset []*entry.Entry, err er
 }
 
 func (rr *RegexRouter) processItem(ent *entry.Entry) *entry.Entry {
+	if len(rr.rules) > 0 {
+		return rr.processRules(ent)
+	}
 	if mtchs := rr.rxp.FindSubmatch(ent.Data); rr.matchIdx < len(mtchs) {
 		if tag, drop, ok := rr.handleExtract(mtchs[rr.matchIdx]); drop {
 			return nil
 		} else if ok {
 			ent.Tag = tag
+		} else if rr.retag {
+			ent.Tag = rr.defTag
 		}
 	} else if rr.Drop_Misses {
 		return nil
+	} else if rr.retag {
+		ent.Tag = rr.defTag
+	}
+	return ent
+}
+
+// processRules routes an entry by the first matching Rule
+func (rr *RegexRouter) processRules(ent *entry.Entry) *entry.Entry {
+	for i, r := range rr.rules {
+		idx := r.rxp.FindSubmatchIndex(ent.Data)
+		if idx == nil {
+			continue
+		} else if r.tmpl == nil {
+			ent.Tag = rr.ruleTags[i]
+			return ent
+		}
+		name := string(r.rxp.Expand(nil, r.tmpl, ent.Data, idx))
+		if tag, ok := rr.allowed[name]; ok {
+			ent.Tag = tag
+			return ent
+		}
+		break // first match wins, even if its tag is not allowed
+	}
+	if rr.Drop_Misses {
+		return nil
+	} else if rr.retag {
+		ent.Tag = rr.defTag
 	}
 	return ent
 }
@@ -185,6 +283,85 @@ func (rrc RegexRouteConfig) validate() (rxp *regexp.Regexp, rts []route, idx int
 	return
 }
 
+// validateRules parses the ordered Rule list, dynamic tags may only reference named groups of their regex
+func (rrc RegexRouteConfig) validateRules() (rules []regexRule, err error) {
+	if rrc.Regex != `` || rrc.Route_Extraction != `` || len(rrc.Route) > 0 {
+		err = ErrRuleAndRegex
+		return
+	}
+	var dynamic bool
+	for _, v := range rrc.Rule {
+		idx := strings.LastIndexByte(v, '=')
+		if idx == -1 {
+			err = fmt.Errorf("Rule %q is invalid, must be regex=tag", v)
+			return
+		}
+		r := regexRule{tag: strings.TrimSpace(v[idx+1:])}
+		pattern := strings.TrimSpace(v[:idx])
+		if pattern == `` {
+			err = fmt.Errorf("Rule %q is missing a regular expression", v)
+			return
+		} else if r.rxp, err = regexp.Compile(pattern); err != nil {
+			err = fmt.Errorf("Rule %q is invalid: %w", v, err)
+			return
+		}
+		if strings.IndexByte(r.tag, '$') == -1 {
+			if err = ingest.CheckTag(r.tag); err != nil {
+				err = fmt.Errorf("Rule %q has an invalid tag: %w", v, err)
+				return
+			}
+		} else if err = checkTagTemplate(r.rxp, r.tag); err != nil {
+			err = fmt.Errorf("Rule %q has an invalid dynamic tag: %w", v, err)
+			return
+		} else {
+			r.tmpl = []byte(r.tag)
+			dynamic = true
+		}
+		rules = append(rules, r)
+	}
+	if dynamic && len(rrc.Allowed_Tags) == 0 {
+		err = ErrMissingAllowedTags
+	} else if !dynamic && len(rrc.Allowed_Tags) > 0 {
+		err = ErrUnusedAllowedTags
+	}
+	for _, v := range rrc.Allowed_Tags {
+		if err == nil {
+			if err = ingest.CheckTag(strings.TrimSpace(v)); err != nil {
+				err = fmt.Errorf("Allowed-Tags %q is invalid: %w", v, err)
+			}
+		}
+	}
+	return
+}
+
+// validateDefault checks the Default_Tag, which applies to both routing modes
+func (rrc RegexRouteConfig) validateDefault() (err error) {
+	if rrc.Default_Tag == `` {
+		return
+	} else if rrc.Drop_Misses {
+		return ErrDefaultAndDropMisses
+	} else if err = ingest.CheckTag(strings.TrimSpace(rrc.Default_Tag)); err != nil {
+		err = fmt.Errorf("Default-Tag %q is invalid: %w", rrc.Default_Tag, err)
+	}
+	return
+}
+
+// checkTagTemplate ensures every group a dynamic tag references is a named group of the regex
+func checkTagTemplate(rxp *regexp.Regexp, tmpl string) error {
+	names := map[string]bool{}
+	for _, n := range rxp.SubexpNames() {
+		if n != `` {
+			names[n] = true
+		}
+	}
+	for _, m := range tagTemplateRefs.FindAllStringSubmatch(tmpl, -1) {
+		if name := m[1] + m[2]; !names[name] {
+			return fmt.Errorf("regular expression does not provide %s", name)
+		}
+	}
+	return nil
+}
+
 func getRoute(v string) (a, b string, err error) {
 	bits := strings.Split(v, splitChar)
 	if len(bits) < 2 {
@@ -9,9 +9,11 @@
 package processors
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 )
 
@@ -137,3 +139,104 @@ func makeTestEntry(df string) *entry.Entry {
 		Data: []byte(fmt.Sprintf(`foo bar %s and some other things`, df)),
 	}
 }
+
+func TestRegexRouterRules(t *testing.T) {
+	var tagger testTagger
+	rc := RegexRouteConfig{
+		Rule: []string{
+			`sshd\[=auth`,
+			`^(?P<app>\w+)\[\d+\]:=app-${app}`,
+			`kernel=kern`, // never reached for entries matching the dynamic rule
+		},
+		Allowed_Tags: []string{`app-cron`, ` app-nginx `},
+		Default_Tag:  `other`,
+	}
+	rr, err := NewRegexRouter(rc, &tagger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{`auth`, `app-cron`, `app-nginx`, `kern`, `other`} {
+		if _, ok := tagger.mp[v]; !ok {
+			t.Fatalf("tag %s was not negotiated: %+v", v, tagger.mp)
+		}
+	}
+	for _, v := range []struct {
+		data string
+		tag  string
+	}{
+		{`cron[12]: sshd[5] mentioned`, `auth`}, // first match wins
+		{`cron[12]: job ran`, `app-cron`},
+		{`nginx[1]: GET /`, `app-nginx`},
+		{`postfix[3]: kernel`, `other`}, // dynamic tag is not allowed
+		{`kernel: oops`, `kern`},
+		{`nothing to see`, `other`},
+	} {
+		ent := &entry.Entry{Data: []byte(v.data)}
+		if set, err := rr.Process([]*entry.Entry{ent}); err != nil {
+			t.Fatal(err)
+		} else if len(set) != 1 {
+			t.Fatalf("entry %q was dropped", v.data)
+		} else if set[0].Tag != tagger.mp[v.tag] {
+			t.Fatalf("entry %q was routed to %d, expected %s", v.data, set[0].Tag, v.tag)
+		}
+	}
+
+	//dropping misses also drops dynamic tags that are not allowed
+	rc.Default_Tag, rc.Drop_Misses = ``, true
+	if rr, err = NewRegexRouter(rc, &tagger); err != nil {
+		t.Fatal(err)
+	}
+	ents := []*entry.Entry{{Data: []byte(`postfix[3]: hi`)}, {Data: []byte(`nope`)}, {Data: []byte(`cron[1]: hi`)}}
+	if set, err := rr.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 1 || set[0].Tag != tagger.mp[`app-cron`] {
+		t.Fatalf("bad drop results: %+v", set)
+	}
+
+	bad := []RegexRouteConfig{
+		{Rule: []string{`foo`}},
+		{Rule: []string{`=foo`}},
+		{Rule: []string{`foo(=tag`}},
+		{Rule: []string{`foo=bad tag`}},
+		{Rule: []string{`(?P<a>foo)=x-${b}`}, Allowed_Tags: []string{`x-foo`}},
+		{Rule: []string{`(?P<a>foo)=x-${a}`}},
+		{Rule: []string{`foo=tag`}, Allowed_Tags: []string{`tag`}},
+		{Rule: []string{`foo=tag`}, Regex: `foo`},
+		{Rule: []string{`foo=tag`}, Default_Tag: `other`, Drop_Misses: true},
+		{Rule: []string{`foo=tag`}, Default_Tag: `bad tag`},
+	}
+	for _, v := range bad {
+		if _, err = NewRegexRouter(v, &tagger); err == nil {
+			t.Fatalf("failed to catch bad config %+v", v)
+		}
+	}
+}
+
+func TestRegexRouterDefaultTag(t *testing.T) {
+	var tagger testTagger
+	rc := rrc
+	rc.Default_Tag = `other`
+	rr, err := NewRegexRouter(rc, &tagger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//both an unrouted extraction and a regex miss get the default tag
+	ents := []*entry.Entry{makeTestEntry(`UncleEddy`), {Data: []byte(`nope`)}}
+	if set, err := rr.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 2 || set[0].Tag != tagger.mp[`other`] || set[1].Tag != tagger.mp[`other`] {
+		t.Fatalf("default tag was not applied: %+v", set)
+	}
+}
+
+func TestRegexRouterUnknownKey(t *testing.T) {
+	b := `
+	[preprocessor "rr"]
+		type = regexrouter
+		Regex="(?P<app>[a-z]+)"
+		Route-Extration=app
+	`
+	if _, err := testLoadPreprocessor(b, `rr`); !errors.Is(err, config.ErrUnknownVariable) {
+		t.Fatalf("failed to catch misspelled key: %v", err)
+	}
+}
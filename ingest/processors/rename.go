@@ -0,0 +1,210 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	RenameProcessor = `rename`
+)
+
+var (
+	ErrMissingRenames = errors.New("rename requires at least one Rename or Rename-Regex")
+)
+
+type RenameConfig struct {
+	// Rename maps an existing tag to a new one, there can be many, e.g.:
+	//	Rename="apache=web"
+	//	Rename="nginx=web"
+	Rename []string
+
+	// Rename_Regex renames tags whose name matches a regular expression, the new name is the
+	// expansion of the text after the last '=' and may reference submatches, e.g.:
+	//	Rename-Regex="^fw-(.+)$=firewall-$1"
+	// Regexes are tried in order after the Rename mappings. Tags whose expansion is not a
+	// valid tag name are left untouched.
+	Rename_Regex []string
+}
+
+type renameRegex struct {
+	rx   *regexp.Regexp
+	tmpl string
+}
+
+// Rename retags entries according to a set of tag name mappings, entries whose tags do not
+// match are left untouched.
+type Rename struct {
+	nocloser
+	RenameConfig
+	tagger  Tagger
+	mp      map[string]string
+	regexes []renameRegex
+
+	mtx   sync.Mutex
+	tags  map[string]entry.EntryTag         // negotiated destination tags
+	cache map[entry.EntryTag]entry.EntryTag // resolved source tags, unmatched tags map to themselves
+}
+
+func RenameLoadConfig(vc *config.VariableConfig) (c RenameConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		_, _, err = c.validate()
+	}
+	return
+}
+
+func (c RenameConfig) validate() (mp map[string]string, regexes []renameRegex, err error) {
+	if len(c.Rename) == 0 && len(c.Rename_Regex) == 0 {
+		err = ErrMissingRenames
+		return
+	} else if mp, err = loadTagRemap(c.Rename); err != nil {
+		return
+	}
+	for _, v := range c.Rename_Regex {
+		idx := strings.LastIndex(v, "=")
+		if idx == -1 {
+			err = fmt.Errorf("%q rename regex is invalid, must be of the form regex=replacement", v)
+			return
+		}
+		r := renameRegex{
+			tmpl: strings.TrimSpace(v[idx+1:]),
+		}
+		if r.tmpl == `` {
+			err = fmt.Errorf("%q rename regex is invalid, missing replacement", v)
+			return
+		} else if r.rx, err = regexp.Compile(strings.TrimSpace(v[:idx])); err != nil {
+			err = fmt.Errorf("%q rename regex is invalid %w", v, err)
+			return
+		}
+		regexes = append(regexes, r)
+	}
+	return
+}
+
+func NewRename(cfg RenameConfig, tagger Tagger) (*Rename, error) {
+	r := &Rename{}
+	if err := r.init(cfg, tagger); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rename) Config(v interface{}, tagger Tagger) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(RenameConfig); ok {
+		err = r.init(cfg, tagger)
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (r *Rename) init(cfg RenameConfig, tagger Tagger) (err error) {
+	var mp map[string]string
+	var regexes []renameRegex
+	if mp, regexes, err = cfg.validate(); err != nil {
+		return
+	}
+	// the explicit destinations are negotiated up front so the muxer knows about them,
+	// regex destinations can only be negotiated as they are discovered
+	tags := make(map[string]entry.EntryTag, len(mp))
+	for _, dst := range mp {
+		if _, ok := tags[dst]; ok {
+			continue
+		}
+		var tg entry.EntryTag
+		if tg, err = tagger.NegotiateTag(dst); err != nil {
+			err = fmt.Errorf("Failed to negotiate rename tag %s: %w", dst, err)
+			return
+		}
+		tags[dst] = tg
+	}
+	r.mtx.Lock()
+	r.RenameConfig = cfg
+	r.tagger = tagger
+	r.mp = mp
+	r.regexes = regexes
+	r.tags = tags
+	r.cache = map[entry.EntryTag]entry.EntryTag{}
+	r.mtx.Unlock()
+	return
+}
+
+func (r *Rename) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	if len(ents) == 0 {
+		return ents, nil
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for _, ent := range ents {
+		if ent != nil {
+			ent.Tag = r.resolve(ent.Tag)
+		}
+	}
+	return ents, nil
+}
+
+// resolve returns the tag an entry with the given tag should carry, the caller must hold the lock
+func (r *Rename) resolve(tg entry.EntryTag) entry.EntryTag {
+	if v, ok := r.cache[tg]; ok {
+		return v
+	}
+	res := tg
+	if name, ok := r.tagger.LookupTag(tg); ok {
+		if dst, ok := r.rename(name); ok {
+			res = r.negotiate(dst, tg)
+		}
+	}
+	r.cache[tg] = res
+	return res
+}
+
+// rename returns the new name for a tag, if any
+func (r *Rename) rename(name string) (string, bool) {
+	if dst, ok := r.mp[name]; ok {
+		return dst, true
+	}
+	for _, v := range r.regexes {
+		if m := v.rx.FindStringSubmatchIndex(name); m != nil {
+			dst := string(v.rx.ExpandString(nil, v.tmpl, name, m))
+			if ingest.CheckTag(dst) != nil {
+				return ``, false
+			}
+			return dst, true
+		}
+	}
+	return ``, false
+}
+
+// negotiate resolves a destination tag exactly once, falling back to the original tag on failure
+func (r *Rename) negotiate(dst string, orig entry.EntryTag) entry.EntryTag {
+	if tg, ok := r.tags[dst]; ok {
+		return tg
+	}
+	tg, err := r.tagger.NegotiateTag(dst)
+	if err != nil {
+		return orig
+	}
+	r.tags[dst] = tg
+	return tg
+}
+
+func (r *Rename) Flush() []*entry.Entry {
+	return nil
+}
@@ -0,0 +1,111 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestRenameConfig(t *testing.T) {
+	b := `
+	[preprocessor "rn"]
+		type = rename
+		Rename="apache=web"
+		Rename-Regex="^fw-(.+)$=firewall-$1"
+	`
+	p, err := testLoadPreprocessor(b, `rn`)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, ok := p.(*Rename); !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Rename", p)
+	}
+
+	bad := []string{
+		``,
+		`Rename=apache`,
+		`Rename="=web"`,
+		`Rename="apache=bad tag"`,
+		`Rename="apache=web"
+		Rename="apache=www"`,
+		`Rename-Regex="^fw-(.+)$"`,
+		`Rename-Regex="^fw-(.+$=firewall"`,
+		`Rename-Regex="^fw-(.+)$="`,
+	}
+	for _, v := range bad {
+		b = `
+		[preprocessor "rn"]
+			type = rename
+			` + v + `
+		`
+		if _, err = testLoadPreprocessor(b, `rn`); err == nil {
+			t.Fatalf("failed to catch bad config %q", v)
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	var tt testTagger
+	apache, _ := tt.NegotiateTag(`apache`)
+	nginx, _ := tt.NegotiateTag(`nginx`)
+	fwasa, _ := tt.NegotiateTag(`fw-asa`)
+	odd, _ := tt.NegotiateTag(`odd-x`)
+	other, _ := tt.NegotiateTag(`syslog`)
+	cfg := RenameConfig{
+		Rename:       []string{`apache=web`, `nginx=web`},
+		Rename_Regex: []string{`^fw-(.+)$=firewall-$1`, `^fw-(.+)$=never`, `^odd-(.+)$=$2`},
+	}
+	r, err := NewRename(cfg, &tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web, ok := tt.mp[`web`]
+	if !ok {
+		t.Fatal("rename tag was not negotiated at init")
+	} else if len(tt.mp) != 6 {
+		t.Fatalf("bad tag negotiations: %+v", tt.mp)
+	}
+
+	var ents []*entry.Entry
+	for _, tg := range []entry.EntryTag{apache, nginx, fwasa, odd, other, apache, fwasa} {
+		ents = append(ents, &entry.Entry{Tag: tg})
+	}
+	if ents, err = r.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 7 {
+		t.Fatalf("bad entry count %d", len(ents))
+	}
+	fw, ok := tt.mp[`firewall-asa`]
+	if !ok {
+		t.Fatal("regex rename tag was not negotiated")
+	} else if _, ok = tt.mp[`never`]; ok {
+		t.Fatal("regexes were not applied in order")
+	}
+	for i, tg := range []entry.EntryTag{web, web, fw, odd, other, web, fw} {
+		if ents[i].Tag != tg {
+			t.Fatalf("entry %d has bad tag %d != %d", i, ents[i].Tag, tg)
+		}
+	}
+	// odd-x expands to an empty tag and is left untouched
+	if len(tt.mp) != 7 {
+		t.Fatalf("bad tag negotiations: %+v", tt.mp)
+	}
+
+	// reconfiguring drops the old mappings
+	if err = r.Config(RenameConfig{Rename: []string{`syslog=web`}}, &tt); err != nil {
+		t.Fatal(err)
+	}
+	ents = []*entry.Entry{{Tag: apache}, {Tag: other}}
+	if ents, err = r.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if ents[0].Tag != apache || ents[1].Tag != web {
+		t.Fatalf("bad tags after reconfigure: %d %d", ents[0].Tag, ents[1].Tag)
+	}
+}
@@ -0,0 +1,141 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	SamplerProcessor string = `sampler`
+
+	samplerKeySrc string = `src`
+	samplerKeyTag string = `tag`
+)
+
+var (
+	ErrInvalidSampleRate = errors.New("Rate must be at least 1")
+)
+
+type SamplerConfig struct {
+	Rate       uint64   // keep 1 in Rate entries
+	Key        string   // src, tag, or the name of an enumerated value to sample by consistently
+	Keep_Regex []string // entries whose data matches any of these are always kept
+	keepRes    []*regexp.Regexp
+}
+
+func SamplerLoadConfig(vc *config.VariableConfig) (c SamplerConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *SamplerConfig) validate() (err error) {
+	if c.Rate == 0 {
+		return ErrInvalidSampleRate
+	}
+	c.Key = strings.TrimSpace(c.Key)
+	c.keepRes = nil
+	for _, v := range c.Keep_Regex {
+		var re *regexp.Regexp
+		if re, err = regexp.Compile(v); err != nil {
+			return fmt.Errorf("Invalid Keep-Regex %q: %w", v, err)
+		}
+		c.keepRes = append(c.keepRes, re)
+	}
+	return
+}
+
+func NewSampler(cfg SamplerConfig) (*Sampler, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Sampler{
+		SamplerConfig: cfg,
+	}, nil
+}
+
+// Sampler keeps 1 in Rate entries, exempting entries that match a Keep-Regex.
+// Without a Key every Rate-th entry is kept; with a Key the decision is made by hashing
+// the key value, so all entries sharing a key are kept or dropped together.
+type Sampler struct {
+	nocloser
+	SamplerConfig
+	count uint64
+}
+
+func (s *Sampler) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(SamplerConfig); ok {
+		if err = cfg.validate(); err == nil {
+			s.SamplerConfig = cfg
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (s *Sampler) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 || s.Rate == 1 {
+		return ents, nil
+	}
+	rset = ents[:0]
+	for _, ent := range ents {
+		if ent != nil && s.keep(ent) {
+			rset = append(rset, ent)
+		}
+	}
+	return
+}
+
+func (s *Sampler) keep(ent *entry.Entry) bool {
+	for _, re := range s.keepRes {
+		if re.Match(ent.Data) {
+			return true
+		}
+	}
+	if key, ok := s.key(ent); ok {
+		h := fnv.New64a()
+		h.Write(key)
+		return h.Sum64()%s.Rate == 0
+	}
+	//no key, just take every Rate-th entry
+	keep := s.count%s.Rate == 0
+	s.count++
+	return keep
+}
+
+// key returns the value an entry is sampled by, entries missing the key fall back to counting
+func (s *Sampler) key(ent *entry.Entry) ([]byte, bool) {
+	switch strings.ToLower(s.Key) {
+	case ``:
+		return nil, false
+	case samplerKeySrc:
+		if ent.SRC == nil {
+			return nil, false
+		}
+		return []byte(ent.SRC.String()), true
+	case samplerKeyTag:
+		return []byte{byte(ent.Tag), byte(ent.Tag >> 8)}, true
+	}
+	if v, ok := ent.GetEnumeratedValue(s.Key); ok && v != nil {
+		return []byte(fmt.Sprint(v)), true
+	}
+	return nil, false
+}
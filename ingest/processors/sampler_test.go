@@ -0,0 +1,126 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestSamplerConfig(t *testing.T) {
+	b := `
+	[preprocessor "samp"]
+		type = sampler
+		Rate = 10
+		Key = src
+		Keep-Regex = "ERROR"
+		Keep-Regex = "panic:"
+	`
+	p, err := testLoadPreprocessor(b, `samp`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := p.(*Sampler)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Sampler", p)
+	}
+	if s.Rate != 10 || s.Key != `src` || len(s.keepRes) != 2 {
+		t.Fatalf("bad config: %+v", s.SamplerConfig)
+	}
+
+	for _, bad := range []string{`Rate = 0`, "Rate = 2\n\t\tKeep-Regex = \"[a-\""} {
+		b = `
+	[preprocessor "samp"]
+		type = sampler
+		` + bad + `
+	`
+		if _, err = testLoadPreprocessor(b, `samp`); err == nil {
+			t.Fatalf("failed to catch bad config %q", bad)
+		}
+	}
+}
+
+func TestSamplerCount(t *testing.T) {
+	s, err := NewSampler(SamplerConfig{Rate: 4, Keep_Regex: []string{`ERROR`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ents []*entry.Entry
+	for i := 0; i < 16; i++ {
+		ents = append(ents, makeEntry([]byte(fmt.Sprintf("INFO %d", i)), 0)...)
+	}
+	ents = append(ents, makeEntry([]byte("ERROR always kept"), 0)...)
+	set, err := s.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 5 {
+		t.Fatalf("bad output count: %d", len(set))
+	}
+	for i, v := range []string{"INFO 0", "INFO 4", "INFO 8", "INFO 12", "ERROR always kept"} {
+		if string(set[i].Data) != v {
+			t.Fatalf("bad entry %d: %q != %q", i, set[i].Data, v)
+		}
+	}
+	if set, err = s.Process(nil); err != nil || len(set) != 0 {
+		t.Fatalf("empty batch was not a no-op: %v %v", set, err)
+	}
+}
+
+func TestSamplerKeyDeterministic(t *testing.T) {
+	const rate = 3
+	keeps := func(key string) map[string]bool {
+		s, err := NewSampler(SamplerConfig{Rate: rate, Key: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := map[string]bool{}
+		for pass := 0; pass < 3; pass++ {
+			for i := 0; i < 64; i++ {
+				src := fmt.Sprintf("10.0.0.%d", i)
+				ent := &entry.Entry{SRC: net.ParseIP(src), TS: entry.Now(), Data: []byte("hello")}
+				ent.AddEnumeratedValueEx(`host`, src)
+				set, err := s.Process([]*entry.Entry{ent})
+				if err != nil {
+					t.Fatal(err)
+				}
+				kept := len(set) == 1
+				if prev, ok := r[src]; ok && prev != kept {
+					t.Fatalf("key %s: inconsistent decision on pass %d", src, pass)
+				}
+				r[src] = kept
+			}
+		}
+		return r
+	}
+
+	bySrc := keeps(`src`)
+	var kept int
+	for _, v := range bySrc {
+		if v {
+			kept++
+		}
+	}
+	if kept == 0 || kept == len(bySrc) {
+		t.Fatalf("sampling by key kept %d of %d", kept, len(bySrc))
+	}
+
+	// a fresh sampler makes the same decisions, as does keying by an enumerated value
+	for _, k := range []string{`SRC`, `host`} {
+		again := keeps(k)
+		for src, v := range bySrc {
+			if again[src] != v {
+				t.Fatalf("key %s: decision for %s changed", k, src)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	SkewPolicyClamp = `clamp`
+	SkewPolicyDrop  = `drop`
+)
+
+// TimestampClamp guards against sources emitting wildly wrong timestamps, such as dates years
+// in the future or at epoch zero. Timestamps further than the configured skew from the current
+// time are either clamped to now or flagged for dropping. A nil TimestampClamp accepts every
+// timestamp unchanged.
+type TimestampClamp struct {
+	max     time.Duration
+	drop    bool
+	clamped atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// NewTimestampClamp builds a TimestampClamp from a Max-Timestamp-Skew duration (e.g. "24h") and
+// an On-Skew policy, which is either "clamp" (the default) or "drop".
+// An empty maxSkew disables the check and returns a nil TimestampClamp.
+func NewTimestampClamp(maxSkew, policy string) (tc *TimestampClamp, err error) {
+	var drop bool
+	switch policy = strings.ToLower(strings.TrimSpace(policy)); policy {
+	case ``, SkewPolicyClamp:
+	case SkewPolicyDrop:
+		drop = true
+	default:
+		err = fmt.Errorf("On-Skew %q is invalid, must be %q or %q", policy, SkewPolicyClamp, SkewPolicyDrop)
+		return
+	}
+	if maxSkew = strings.TrimSpace(maxSkew); maxSkew == `` {
+		if policy != `` {
+			err = errors.New("On-Skew requires Max-Timestamp-Skew")
+		}
+		return
+	}
+	var d time.Duration
+	if d, err = time.ParseDuration(maxSkew); err != nil {
+		err = fmt.Errorf("Max-Timestamp-Skew %q is invalid %w", maxSkew, err)
+		return
+	} else if d <= 0 {
+		err = fmt.Errorf("Max-Timestamp-Skew %q must be positive", maxSkew)
+		return
+	}
+	tc = &TimestampClamp{
+		max:  d,
+		drop: drop,
+	}
+	return
+}
+
+// Check returns the timestamp to use in place of ts and whether the entry should be kept.
+// Timestamps within the allowed skew are returned unchanged.
+func (tc *TimestampClamp) Check(ts time.Time) (time.Time, bool) {
+	if tc == nil {
+		return ts, true
+	}
+	now := time.Now()
+	if d := ts.Sub(now); d <= tc.max && d >= -tc.max {
+		return ts, true
+	}
+	if tc.drop {
+		tc.dropped.Add(1)
+		return ts, false
+	}
+	tc.clamped.Add(1)
+	return now, true
+}
+
+// Stats returns the number of timestamps clamped and entries dropped, it is safe to call
+// concurrently with Check.
+func (tc *TimestampClamp) Stats() (clamped, dropped uint64) {
+	if tc == nil {
+		return
+	}
+	return tc.clamped.Load(), tc.dropped.Load()
+}
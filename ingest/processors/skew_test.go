@@ -0,0 +1,60 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestampClamp(t *testing.T) {
+	var nilClamp *TimestampClamp
+	old := time.Unix(0, 0)
+	if ts, ok := nilClamp.Check(old); !ok || !ts.Equal(old) {
+		t.Fatal("nil clamp modified timestamp")
+	}
+
+	tc, err := NewTimestampClamp(``, ``)
+	if err != nil || tc != nil {
+		t.Fatalf("empty skew should disable the clamp: %v %v", tc, err)
+	}
+	for _, bad := range [][2]string{{`1h`, `ignore`}, {`bananas`, ``}, {`-1h`, ``}, {`0s`, ``}, {``, `drop`}} {
+		if _, err = NewTimestampClamp(bad[0], bad[1]); err == nil {
+			t.Fatalf("accepted bad config %v", bad)
+		}
+	}
+
+	if tc, err = NewTimestampClamp(`1h`, ``); err != nil {
+		t.Fatal(err)
+	}
+	ok := time.Now().Add(-30 * time.Minute)
+	if ts, keep := tc.Check(ok); !keep || !ts.Equal(ok) {
+		t.Fatal("in bounds timestamp was modified")
+	}
+	for _, ts := range []time.Time{old, time.Now().Add(48 * time.Hour)} {
+		if nts, keep := tc.Check(ts); !keep || time.Since(nts) > time.Minute {
+			t.Fatalf("timestamp %v was not clamped: %v", ts, nts)
+		}
+	}
+	if c, d := tc.Stats(); c != 2 || d != 0 {
+		t.Fatalf("bad stats %d %d", c, d)
+	}
+
+	if tc, err = NewTimestampClamp(`1h`, `DROP`); err != nil {
+		t.Fatal(err)
+	}
+	if _, keep := tc.Check(old); keep {
+		t.Fatal("out of bounds timestamp was kept")
+	} else if _, keep = tc.Check(ok); !keep {
+		t.Fatal("in bounds timestamp was dropped")
+	}
+	if c, d := tc.Stats(); c != 0 || d != 1 {
+		t.Fatalf("bad stats %d %d", c, d)
+	}
+}
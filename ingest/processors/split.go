@@ -0,0 +1,196 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/jsonparser"
+)
+
+const (
+	SplitProcessor string = `split`
+)
+
+var (
+	ErrSplitMode = errors.New("Exactly one of Delimiter or JSON-Array must be specified")
+)
+
+type SplitConfig struct {
+	Delimiter                 string // literal delimiter, or a regular expression if Delimiter-Regex is set
+	Delimiter_Regex           bool
+	JSON_Array                bool // emit one entry per element of a JSON array
+	Extract_Timestamps        bool // re-extract timestamps from each emitted entry
+	Timestamp_Format_Override string
+	Timezone_Override         string
+	Assume_Local_Timezone     bool
+}
+
+func SplitLoadConfig(vc *config.VariableConfig) (c SplitConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *SplitConfig) validate() (err error) {
+	if c.JSON_Array == (c.Delimiter != ``) {
+		return ErrSplitMode
+	}
+	if c.Delimiter_Regex {
+		if c.JSON_Array {
+			return errors.New("Delimiter-Regex is not compatible with JSON-Array")
+		} else if _, err = regexp.Compile(c.Delimiter); err != nil {
+			return fmt.Errorf("Invalid Delimiter regular expression %q: %w", c.Delimiter, err)
+		}
+	}
+	if c.Extract_Timestamps {
+		err = c.timeConfig().Validate()
+	} else if c.Timestamp_Format_Override != `` || c.Timezone_Override != `` || c.Assume_Local_Timezone {
+		return errors.New("Timestamp options require Extract-Timestamps")
+	}
+	return
+}
+
+// timeConfig returns the settings used to extract the timestamps of records
+func (c SplitConfig) timeConfig() TimeExtractorConfig {
+	return TimeExtractorConfig{
+		FormatOverride:      c.Timestamp_Format_Override,
+		TimezoneOverride:    c.Timezone_Override,
+		AssumeLocalTimezone: c.Assume_Local_Timezone,
+	}
+}
+
+func NewSplit(cfg SplitConfig) (*Split, error) {
+	s := &Split{}
+	if err := s.Config(cfg); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// build validates the config and constructs the delimiter, regex, and time extractor it requires
+func (cfg SplitConfig) build() (delim []byte, re *regexp.Regexp, te *TimeExtractor, err error) {
+	if err = cfg.validate(); err != nil {
+		return
+	}
+	delim = []byte(cfg.Delimiter)
+	if cfg.Delimiter_Regex {
+		re = regexp.MustCompile(cfg.Delimiter)
+	}
+	if cfg.Extract_Timestamps {
+		te, err = NewTimeExtractor(cfg.timeConfig())
+	}
+	return
+}
+
+// Split fans a single entry out into one entry per record.
+// Records inherit the tag, source, timestamp, and enumerated values of the original entry,
+// empty records are discarded. Entries that are not JSON arrays are passed through
+// untouched in JSON-Array mode.
+type Split struct {
+	nocloser
+	SplitConfig
+	delim []byte
+	re    *regexp.Regexp
+	te    *TimeExtractor // nil unless Extract_Timestamps is set
+}
+
+func (s *Split) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(SplitConfig); ok {
+		var delim []byte
+		var re *regexp.Regexp
+		var te *TimeExtractor
+		if delim, re, te, err = cfg.build(); err == nil {
+			s.SplitConfig, s.delim, s.re, s.te = cfg, delim, re, te
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (s *Split) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	rset = make([]*entry.Entry, 0, len(ents))
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		rset = append(rset, s.processItem(ent)...)
+	}
+	return
+}
+
+func (s *Split) processItem(ent *entry.Entry) (rset []*entry.Entry) {
+	var records [][]byte
+	if s.JSON_Array {
+		var ok bool
+		if records, ok = splitJSONArray(ent.Data); !ok {
+			return []*entry.Entry{ent}
+		}
+	} else if s.re != nil {
+		records = regexSplit(s.re, ent.Data)
+	} else {
+		records = bytes.Split(ent.Data, s.delim)
+	}
+	for _, rec := range records {
+		if len(rec) == 0 {
+			continue
+		}
+		r := &entry.Entry{
+			Tag:  ent.Tag,
+			SRC:  ent.SRC,
+			TS:   ent.TS,
+			Data: rec,
+		}
+		r.CopyEnumeratedBlock(ent)
+		// records are free form, so a bare number is not taken to be an epoch
+		if s.te != nil {
+			if ts, ok, err := s.te.TimeGrinder().Extract(rec); err == nil && ok {
+				r.TS = entry.FromStandard(ts)
+			}
+		}
+		rset = append(rset, r)
+	}
+	return
+}
+
+// splitJSONArray returns the elements of a top level JSON array
+func splitJSONArray(data []byte) (elems [][]byte, ok bool) {
+	if v, dt, _, err := jsonparser.Get(data); err != nil || dt != jsonparser.Array || len(v) == 0 {
+		return
+	}
+	_, err := jsonparser.ArrayEach(data, func(v []byte, dt jsonparser.ValueType, off int, lerr error) {
+		if lerr == nil {
+			elems = append(elems, v)
+		}
+	})
+	ok = err == nil
+	return
+}
+
+// regexSplit slices data into the records between matches of re
+func regexSplit(re *regexp.Regexp, data []byte) (records [][]byte) {
+	var last int
+	for _, m := range re.FindAllIndex(data, -1) {
+		records = append(records, data[last:m[0]])
+		last = m[1]
+	}
+	return append(records, data[last:])
+}
@@ -0,0 +1,175 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestSplitConfig(t *testing.T) {
+	b := `
+	[preprocessor "spl"]
+		type = split
+		Delimiter = "\n"
+	`
+	p, err := testLoadPreprocessor(b, `spl`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := p.(*Split)
+	if !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Split", p)
+	}
+	if s.Delimiter != "\n" {
+		t.Fatalf("bad delimiter: %q", s.Delimiter)
+	}
+
+	bads := []string{
+		``,
+		"Delimiter = \",\"\n\t\tJSON-Array = true",
+		"Delimiter = \"[a-\"\n\t\tDelimiter-Regex = true",
+		"JSON-Array = true\n\t\tDelimiter-Regex = true",
+		"JSON-Array = true\n\t\tAssume-Local-Timezone = true",
+	}
+	for _, bad := range bads {
+		b = `
+	[preprocessor "spl"]
+		type = split
+		` + bad + `
+	`
+		if _, err = testLoadPreprocessor(b, `spl`); err == nil {
+			t.Fatalf("failed to catch bad config %q", bad)
+		}
+	}
+}
+
+func checkSplit(t *testing.T, set []*entry.Entry, parent *entry.Entry, want ...string) {
+	t.Helper()
+	if len(set) != len(want) {
+		t.Fatalf("bad output count: %d != %d", len(set), len(want))
+	}
+	for i, ent := range set {
+		if string(ent.Data) != want[i] {
+			t.Fatalf("bad record %d: %q != %q", i, ent.Data, want[i])
+		}
+		if ent.Tag != parent.Tag || !ent.SRC.Equal(parent.SRC) {
+			t.Fatalf("record %d did not inherit tag and source", i)
+		}
+		if err := checkEntryEVs([]*entry.Entry{ent}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSplitDelimiter(t *testing.T) {
+	s, err := NewSplit(SplitConfig{Delimiter: "\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := makeEntry([]byte("foo\nbar\n\nbaz\n"), 3)
+	parent := *ents[0]
+	set, err := s.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSplit(t, set, &parent, "foo", "bar", "baz")
+	for _, ent := range set {
+		if ent.TS != parent.TS {
+			t.Fatal("record did not inherit the timestamp")
+		}
+	}
+
+	if s, err = NewSplit(SplitConfig{Delimiter: `\s*[,;]\s*`, Delimiter_Regex: true}); err != nil {
+		t.Fatal(err)
+	}
+	ents = makeEntry([]byte("foo , bar;baz"), 1)
+	parent = *ents[0]
+	if set, err = s.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	checkSplit(t, set, &parent, "foo", "bar", "baz")
+
+	if set, err = s.Process(nil); err != nil || len(set) != 0 {
+		t.Fatalf("empty batch was not a no-op: %v %v", set, err)
+	}
+}
+
+func TestSplitReconfigure(t *testing.T) {
+	s, err := NewSplit(SplitConfig{Delimiter: `\s*;\s*`, Delimiter_Regex: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Config(SplitConfig{Delimiter: ","}); err != nil {
+		t.Fatal(err)
+	}
+	ents := makeEntry([]byte("foo,bar ; baz"), 1)
+	parent := *ents[0]
+	set, err := s.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSplit(t, set, &parent, "foo", "bar ; baz")
+
+	//a rejected config leaves the processor as it was
+	if err = s.Config(SplitConfig{Delimiter: "[a-", Delimiter_Regex: true}); err == nil {
+		t.Fatal("failed to catch bad config")
+	}
+	ents = makeEntry([]byte("a,b"), 1)
+	parent = *ents[0]
+	if set, err = s.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	checkSplit(t, set, &parent, "a", "b")
+}
+
+func TestSplitJSONArray(t *testing.T) {
+	s, err := NewSplit(SplitConfig{JSON_Array: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := makeEntry([]byte(`[{"a":1}, {"b":[2,3]} ,"str", 4]`), 2)
+	parent := *ents[0]
+	notArray := makeEntry([]byte(`{"a":[1,2]}`), 2)
+	set, err := s.Process(append(ents, notArray...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 5 || set[4] != notArray[0] {
+		t.Fatalf("non-array entry was not passed through: %d", len(set))
+	}
+	checkSplit(t, set[:4], &parent, `{"a":1}`, `{"b":[2,3]}`, `str`, `4`)
+}
+
+func TestSplitTimestamps(t *testing.T) {
+	s, err := NewSplit(SplitConfig{JSON_Array: true, Extract_Timestamps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := makeEntry([]byte(`[{"ts":"2026-01-02T03:04:05Z"},{"ts":"2026-02-03T04:05:06Z"},{"no":"time"}]`), 0)
+	parent := *ents[0]
+	set, err := s.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 3 {
+		t.Fatalf("bad output count: %d", len(set))
+	}
+	for i, want := range []time.Time{
+		time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC),
+		parent.TS.StandardTime(),
+	} {
+		if !set[i].TS.StandardTime().Equal(want) {
+			t.Fatalf("bad timestamp on record %d: %v != %v", i, set[i].TS, want)
+		}
+	}
+}
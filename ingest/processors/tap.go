@@ -0,0 +1,209 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	TapProcessor string = `tap`
+
+	tapTagEV   = `tap_tag`
+	tapLabelEV = `tap_label`
+)
+
+type TapConfig struct {
+	// File optionally names a file to which a JSON line describing each tapped entry is appended.
+	File string
+
+	// Tag optionally names a tag under which copies of tapped entries are emitted. The copies
+	// keep the timestamp and source of the original and carry its tag name in the "tap_tag"
+	// enumerated value. Copies continue through any processors after the tap.
+	Tag string
+
+	// Sample_Rate taps 1 in Sample_Rate entries, zero or one taps every entry.
+	Sample_Rate uint64
+
+	// Label optionally identifies the tap in its output, useful when a pipeline has several.
+	Label string
+}
+
+func TapLoadConfig(vc *config.VariableConfig) (c TapConfig, err error) {
+	if err = mapToStrict(vc, &c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c *TapConfig) validate() (err error) {
+	c.File = strings.TrimSpace(c.File)
+	c.Tag = strings.TrimSpace(c.Tag)
+	if c.File == `` && c.Tag == `` {
+		return errors.New("tap requires a File or Tag")
+	} else if c.Tag != `` {
+		if err = ingest.CheckTag(c.Tag); err != nil {
+			return fmt.Errorf("Tag %q is invalid %w", c.Tag, err)
+		}
+	}
+	if c.Sample_Rate == 0 {
+		c.Sample_Rate = 1
+	}
+	return
+}
+
+// Tap is a debugging aid which records a copy of the entries passing through it, optionally
+// sampled, to a file and/or a separate tag. Entries themselves are passed through unchanged,
+// so a tap is safe to leave in a pipeline.
+type Tap struct {
+	TapConfig
+	tagger Tagger
+	tag    entry.EntryTag
+	count  uint64
+
+	mtx  sync.Mutex
+	fout *os.File
+	bw   *bufio.Writer
+}
+
+// tapRecord is the line written to the tap file for each tapped entry
+type tapRecord struct {
+	Label string `json:",omitempty"`
+	Tag   string
+	TS    time.Time
+	SRC   string
+	Data  string
+}
+
+func NewTap(cfg TapConfig, tagger Tagger) (*Tap, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	t := &Tap{
+		TapConfig: cfg,
+		tagger:    tagger,
+	}
+	if cfg.Tag != `` {
+		var err error
+		if t.tag, err = tagger.NegotiateTag(cfg.Tag); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.File != `` {
+		fout, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tap File %q %w", cfg.File, err)
+		}
+		t.fout, t.bw = fout, bufio.NewWriter(fout)
+	}
+	return t, nil
+}
+
+func (t *Tap) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(TapConfig); ok {
+		if err = cfg.validate(); err != nil {
+			return
+		} else if cfg.File != t.File || cfg.Tag != t.Tag {
+			err = errors.New("tap File and Tag cannot be changed")
+		} else {
+			t.TapConfig = cfg
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (t *Tap) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	if len(ents) == 0 {
+		return ents, nil
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var copies []*entry.Entry
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		keep := t.count%t.Sample_Rate == 0
+		t.count++
+		if !keep {
+			continue
+		}
+		tagName, ok := t.tagger.LookupTag(ent.Tag)
+		if !ok {
+			tagName = fmt.Sprintf("%d", ent.Tag)
+		}
+		if t.bw != nil {
+			t.write(ent, tagName)
+		}
+		if t.Tag != `` {
+			c := ent.DeepCopy()
+			c.Tag = t.tag
+			c.AddEnumeratedValueEx(tapTagEV, tagName)
+			if t.Label != `` {
+				c.AddEnumeratedValueEx(tapLabelEV, t.Label)
+			}
+			copies = append(copies, &c)
+		}
+	}
+	if t.bw != nil {
+		t.bw.Flush()
+	}
+	return append(ents, copies...), nil
+}
+
+// write appends the JSON record of an entry to the tap file, a debugging aid must never
+// interrupt the pipeline so errors are ignored
+func (t *Tap) write(ent *entry.Entry, tagName string) {
+	rec := tapRecord{
+		Label: t.Label,
+		Tag:   tagName,
+		TS:    ent.TS.StandardTime(),
+		Data:  string(ent.Data),
+	}
+	if ent.SRC != nil {
+		rec.SRC = ent.SRC.String()
+	}
+	if b, err := json.Marshal(rec); err == nil {
+		t.bw.Write(b)
+		t.bw.WriteByte('\n')
+	}
+}
+
+func (t *Tap) Flush() []*entry.Entry {
+	return nil
+}
+
+func (t *Tap) Close() (err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.fout != nil {
+		if err = t.bw.Flush(); err == nil {
+			err = t.fout.Close()
+		} else {
+			t.fout.Close()
+		}
+		t.fout, t.bw = nil, nil
+	}
+	return
+}
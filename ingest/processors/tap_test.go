@@ -0,0 +1,124 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestTapConfig(t *testing.T) {
+	for _, v := range []string{``, `Sample-Rate=10`, `Tag="bad tag"`} {
+		b := `
+		[preprocessor "tap"]
+			type = tap
+			` + v + `
+		`
+		if _, err := testLoadPreprocessor(b, `tap`); err == nil {
+			t.Fatalf("failed to catch bad config %q", v)
+		}
+	}
+}
+
+func TestTapFile(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `tap.json`)
+	b := `
+	[preprocessor "tap"]
+		type = tap
+		File="` + pth + `"
+		Sample-Rate=2
+		Label=post-corelight
+	`
+	p, err := testLoadPreprocessor(b, `tap`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := net.ParseIP("10.0.0.1")
+	ts := entry.UnixTime(1700000000, 0)
+	var ents []*entry.Entry
+	for i := 0; i < 5; i++ {
+		ents = append(ents, &entry.Entry{TS: ts, SRC: src, Tag: 7, Data: []byte(fmt.Sprintf("entry %d", i))})
+	}
+	out, err := p.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(out) != 5 {
+		t.Fatalf("tap changed the entry count: %d", len(out))
+	}
+	for i, ent := range out {
+		if ent != ents[i] || string(ent.Data) != fmt.Sprintf("entry %d", i) || ent.Tag != 7 {
+			t.Fatalf("tap modified entry %d", i)
+		}
+	}
+	if err = p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fin, err := os.Open(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fin.Close()
+	var recs []tapRecord
+	for s := bufio.NewScanner(fin); s.Scan(); {
+		var rec tapRecord
+		if err = json.Unmarshal(s.Bytes(), &rec); err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 sampled records, got %d", len(recs))
+	}
+	for i, rec := range recs {
+		if rec.Data != fmt.Sprintf("entry %d", i*2) || rec.Label != `post-corelight` || rec.SRC != `10.0.0.1` ||
+			rec.Tag != `7` || !rec.TS.Equal(ts.StandardTime()) {
+			t.Fatalf("bad record %d: %+v", i, rec)
+		}
+	}
+}
+
+func TestTapTag(t *testing.T) {
+	var tt testTagger
+	orig, _ := tt.NegotiateTag(`zeekconn`)
+	tap, err := NewTap(TapConfig{Tag: `debug`, Label: `a`}, &tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug, _ := tt.NegotiateTag(`debug`)
+	ent := &entry.Entry{Tag: orig, TS: entry.Now(), SRC: net.ParseIP("10.0.0.1"), Data: []byte(`hello`)}
+	ents, err := tap.Process([]*entry.Entry{ent})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 2 || ents[0] != ent || ent.Tag != orig {
+		t.Fatalf("bad entries: %d", len(ents))
+	}
+	c := ents[1]
+	if c.Tag != debug || c.TS != ent.TS || !c.SRC.Equal(ent.SRC) || string(c.Data) != `hello` {
+		t.Fatalf("bad copy %+v", c)
+	} else if v, ok := c.GetEnumeratedValue(tapTagEV); !ok || v != `zeekconn` {
+		t.Fatalf("bad %s: %v", tapTagEV, v)
+	} else if v, ok = c.GetEnumeratedValue(tapLabelEV); !ok || v != `a` {
+		t.Fatalf("bad %s: %v", tapLabelEV, v)
+	} else if ent.EVCount() != 0 {
+		t.Fatal("original entry was annotated")
+	}
+	// the copy must not share data with the original
+	c.Data[0] = 'j'
+	if string(ent.Data) != `hello` {
+		t.Fatal("copy shares data with the original")
+	}
+}
@@ -0,0 +1,225 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/timegrinder"
+	"github.com/gravwell/jsonparser"
+)
+
+// epochs larger than these magnitudes are taken to be in milliseconds, microseconds,
+// and nanoseconds respectively, seconds cover dates up to the year 5138
+const (
+	epochMilliMin = 1e11
+	epochMicroMin = 1e14
+	epochNanoMin  = 1e17
+)
+
+// TimeExtractorConfig describes how timestamps are located and parsed. It is shared by the
+// ingesters and preprocessors so that they accept the same formats and options, each of them
+// fills it in from its own configuration keys.
+type TimeExtractorConfig struct {
+	Formats             config.CustomTimeFormat // custom formats, loaded ahead of the built-in ones
+	FormatOverride      string                  // name of a built-in or custom format which is tried first
+	TimezoneOverride    string                  // location applied to timestamps without a timezone
+	AssumeLocalTimezone bool                    // apply the local timezone to timestamps without one
+	EnableLeftMostSeed  bool                    // see timegrinder.Config
+	Field               string                  // optional dotted path of the timestamp within JSON records
+	MaxSkew             string                  // optional Max-Timestamp-Skew, see NewTimestampClamp
+	OnSkew              string                  // optional On-Skew policy, see NewTimestampClamp
+}
+
+// Validate checks the configuration without building an extractor.
+func (tc TimeExtractorConfig) Validate() (err error) {
+	if tc.TimezoneOverride != `` {
+		if tc.AssumeLocalTimezone {
+			return errors.New("Cannot specify Assume-Local-Timezone and Timezone-Override in the same config")
+		} else if _, err = time.LoadLocation(tc.TimezoneOverride); err != nil {
+			return fmt.Errorf("Invalid timezone override %q: %w", tc.TimezoneOverride, err)
+		}
+	}
+	if err = tc.Formats.Validate(); err != nil {
+		return
+	} else if ovr := strings.TrimSpace(tc.FormatOverride); ovr != `` {
+		if _, ok := tc.Formats[ovr]; !ok {
+			if err = timegrinder.ValidateFormatOverride(ovr); err != nil {
+				return
+			}
+		}
+	}
+	_, err = NewTimestampClamp(tc.MaxSkew, tc.OnSkew)
+	return
+}
+
+// TimeExtractor parses timestamps from raw data, decoded JSON values, and JSON records, and
+// optionally bounds them with a TimestampClamp. Numeric epochs are accepted in seconds,
+// milliseconds, microseconds, or nanoseconds, the unit is detected from their magnitude.
+// A TimeExtractor is not safe for concurrent use, except for Check and Stats.
+type TimeExtractor struct {
+	cfg   TimeExtractorConfig
+	tg    *timegrinder.TimeGrinder
+	keys  []string // split Field, nil if unset
+	clamp *TimestampClamp
+}
+
+// NewTimeExtractor validates the configuration and builds a TimeExtractor from it.
+func NewTimeExtractor(cfg TimeExtractorConfig) (te *TimeExtractor, err error) {
+	if err = cfg.Validate(); err != nil {
+		return
+	}
+	te = &TimeExtractor{cfg: cfg}
+	if te.tg, err = timegrinder.NewTimeGrinder(timegrinder.Config{EnableLeftMostSeed: cfg.EnableLeftMostSeed}); err != nil {
+		return nil, err
+	} else if err = cfg.Formats.LoadFormats(te.tg); err != nil {
+		return nil, fmt.Errorf("Failed to load custom time formats: %w", err)
+	}
+	if cfg.AssumeLocalTimezone {
+		te.tg.SetLocalTime()
+	} else if cfg.TimezoneOverride != `` {
+		if err = te.tg.SetTimezone(cfg.TimezoneOverride); err != nil {
+			return nil, err
+		}
+	}
+	// the override is set after the custom formats are loaded so it may name one of them
+	if ovr := strings.TrimSpace(cfg.FormatOverride); ovr != `` {
+		if err = te.tg.SetFormatOverride(ovr); err != nil {
+			return nil, fmt.Errorf("Failed to load format override %q: %w", ovr, err)
+		}
+	}
+	if cfg.Field = strings.TrimSpace(cfg.Field); cfg.Field != `` {
+		te.keys = unquoteFields(splitRespectQuotes(cfg.Field, dotSplitter))
+	}
+	if te.clamp, err = NewTimestampClamp(cfg.MaxSkew, cfg.OnSkew); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// TimeGrinder returns the underlying timegrinder, for callers which match timestamps
+// within free form data themselves.
+func (te *TimeExtractor) TimeGrinder() *timegrinder.TimeGrinder {
+	return te.tg
+}
+
+// Extract parses a timestamp from raw data, if the data is entirely a number it is
+// treated as an epoch.
+func (te *TimeExtractor) Extract(b []byte) (ts time.Time, ok bool) {
+	if ts, ok = parseEpoch(strings.TrimSpace(string(b))); ok {
+		return
+	}
+	var err error
+	if ts, ok, err = te.tg.Extract(b); err != nil {
+		ok = false
+	}
+	return
+}
+
+// ExtractValue parses a timestamp from a value decoded by encoding/json, strings are handled
+// as by Extract and numbers are epochs.
+func (te *TimeExtractor) ExtractValue(v interface{}) (ts time.Time, ok bool) {
+	switch t := v.(type) {
+	case string:
+		return te.Extract([]byte(t))
+	case float64:
+		return epochTime(t)
+	case json.Number:
+		return parseEpoch(t.String())
+	}
+	return
+}
+
+// ExtractRaw parses a timestamp from a raw value located by jsonparser. Strings containing
+// escapes are not unescaped and are rejected.
+func (te *TimeExtractor) ExtractRaw(raw []byte, vt jsonparser.ValueType) (ts time.Time, ok bool) {
+	switch vt {
+	case jsonparser.String:
+		if plainString(raw) {
+			return te.Extract(raw)
+		}
+	case jsonparser.Number:
+		return parseEpoch(string(raw))
+	}
+	return
+}
+
+// ExtractField parses the timestamp at Field in a decoded JSON record.
+func (te *TimeExtractor) ExtractField(mp map[string]interface{}) (ts time.Time, ok bool) {
+	if te.keys == nil {
+		return
+	}
+	var v interface{}
+	if v, ok = lookupField(mp, te.cfg.Field); ok {
+		ts, ok = te.ExtractValue(v)
+	}
+	return
+}
+
+// ExtractJSON parses the timestamp at Field in a JSON record.
+func (te *TimeExtractor) ExtractJSON(data []byte) (ts time.Time, ok bool) {
+	if te.keys == nil {
+		return
+	}
+	if v, vt, _, err := jsonparser.Get(data, te.keys...); err == nil {
+		ts, ok = te.ExtractRaw(v, vt)
+	}
+	return
+}
+
+// Check applies the Max-Timestamp-Skew bound, see TimestampClamp.Check.
+func (te *TimeExtractor) Check(ts time.Time) (time.Time, bool) {
+	return te.clamp.Check(ts)
+}
+
+// Stats returns the skew counters, see TimestampClamp.Stats.
+func (te *TimeExtractor) Stats() (clamped, dropped uint64) {
+	return te.clamp.Stats()
+}
+
+// parseEpoch converts a numeric string, integers are converted exactly so that nanosecond
+// epochs keep their precision.
+func parseEpoch(s string) (ts time.Time, ok bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if a := math.Abs(float64(i)); a >= epochNanoMin {
+			return time.Unix(0, i).UTC(), true
+		}
+		return epochTime(float64(i))
+	} else if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return epochTime(f)
+	}
+	return
+}
+
+// epochTime converts a numeric epoch, detecting its unit from its magnitude. Fractional
+// seconds are kept to the microsecond, which is the precision Zeek and most JSON sources use.
+func epochTime(f float64) (ts time.Time, ok bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return
+	}
+	switch a := math.Abs(f); {
+	case a >= epochNanoMin:
+		ts = time.Unix(0, int64(f))
+	case a >= epochMicroMin:
+		ts = time.UnixMicro(int64(f))
+	case a >= epochMilliMin:
+		ts = time.UnixMilli(int64(f))
+	default:
+		sec, frac := math.Modf(f)
+		ts = time.Unix(int64(sec), int64(math.Round(frac*1e6))*1000)
+	}
+	return ts.UTC(), true
+}
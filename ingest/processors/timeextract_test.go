@@ -0,0 +1,109 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+)
+
+func TestTimeExtractorConfig(t *testing.T) {
+	bad := []TimeExtractorConfig{
+		{TimezoneOverride: `Not/AZone`},
+		{TimezoneOverride: `America/Denver`, AssumeLocalTimezone: true},
+		{FormatOverride: `bananas`},
+		{MaxSkew: `1h`, OnSkew: `ignore`},
+	}
+	for i, tc := range bad {
+		if err := tc.Validate(); err == nil {
+			t.Fatalf("%d: accepted bad config %+v", i, tc)
+		} else if _, err = NewTimeExtractor(tc); err == nil {
+			t.Fatalf("%d: built an extractor from bad config %+v", i, tc)
+		}
+	}
+	// the override may name a custom format
+	tc := TimeExtractorConfig{
+		Formats: config.CustomTimeFormat{
+			`foo`: &config.TimeFormat{Format: `2006-01-02 15.04.05`, Regex: `\d{4}-\d{2}-\d{2} \d{2}\.\d{2}\.\d{2}`},
+		},
+		FormatOverride:   `foo`,
+		TimezoneOverride: `America/Denver`,
+	}
+	te, err := NewTimeExtractor(tc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, ok := te.Extract([]byte(`at 2024-02-03 04.05.06 things happened`))
+	if !ok {
+		t.Fatal("failed to extract custom format")
+	} else if _, off := ts.Zone(); off != -7*3600 {
+		t.Fatalf("timezone override not applied: %v", ts)
+	}
+}
+
+func TestTimeExtractor(t *testing.T) {
+	te, err := NewTimeExtractor(TimeExtractorConfig{Field: `meta.ts`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 2, 3, 4, 5, 6, 123456000, time.UTC)
+	for _, v := range []interface{}{
+		`2024-02-03T04:05:06.123456Z`,
+		`1706933106.123456`,
+		1706933106.123456,
+		json.Number(`1706933106123`),
+		float64(1706933106123456),
+	} {
+		ts, ok := te.ExtractValue(v)
+		if !ok {
+			t.Fatalf("failed to extract %v", v)
+		}
+		// millisecond epochs lose the microseconds
+		if d := ts.Sub(want); d < -time.Millisecond || d > time.Millisecond {
+			t.Fatalf("bad timestamp from %v: %v != %v", v, ts, want)
+		}
+	}
+	if ts, ok := te.ExtractValue(json.Number(`1706933106123456789`)); !ok || ts.UnixNano() != 1706933106123456789 {
+		t.Fatalf("bad nanosecond epoch %v", ts)
+	}
+	for _, v := range []interface{}{`bananas`, true, nil, map[string]interface{}{}} {
+		if _, ok := te.ExtractValue(v); ok {
+			t.Fatalf("extracted a timestamp from %v", v)
+		}
+	}
+
+	rec := []byte(`{"meta":{"ts":1706933106.123456},"msg":"hi"}`)
+	if ts, ok := te.ExtractJSON(rec); !ok || !ts.Equal(want) {
+		t.Fatalf("bad field timestamp %v", ts)
+	}
+	var mp map[string]interface{}
+	if err = json.Unmarshal(rec, &mp); err != nil {
+		t.Fatal(err)
+	} else if ts, ok := te.ExtractField(mp); !ok || !ts.Equal(want) {
+		t.Fatalf("bad field timestamp %v", ts)
+	}
+	if _, ok := te.ExtractJSON([]byte(`{"ts":1706933106}`)); ok {
+		t.Fatal("extracted a missing field")
+	}
+
+	// the clamp is disabled without Max_Skew
+	if ts, ok := te.Check(time.Unix(0, 0)); !ok || ts.Unix() != 0 {
+		t.Fatal("clamp applied without a skew")
+	}
+	if te, err = NewTimeExtractor(TimeExtractorConfig{MaxSkew: `1h`, OnSkew: `drop`}); err != nil {
+		t.Fatal(err)
+	} else if _, ok := te.Check(want); ok {
+		t.Fatal("out of bounds timestamp was kept")
+	} else if c, d := te.Stats(); c != 0 || d != 1 {
+		t.Fatalf("bad stats %d %d", c, d)
+	}
+}
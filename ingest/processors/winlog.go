@@ -0,0 +1,430 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/jsonparser"
+)
+
+const (
+	WinlogProcessor string = `winlog`
+
+	winlogFormatJSON = `json`
+	winlogFormatTSV  = `tsv`
+)
+
+var (
+	errNotWinlog = errors.New("not a windows event")
+
+	xmlEventStart = []byte(`<Event`)
+)
+
+// winlogSystem are the fields of the EventLog System element, in the order they are emitted.
+// Event data fields which collide with them are dropped.
+var winlogSystem = []string{
+	`EventID`, `Provider`, `Channel`, `Computer`, `TimeCreated`, `EventRecordID`, `Level`,
+	`Task`, `Opcode`, `Keywords`, `Version`, `ProcessID`, `ThreadID`, `UserID`,
+}
+
+// winlogJSONNames maps the NXLog im_msvistalog JSON field names to their EventLog names
+var winlogJSONNames = map[string]string{
+	`SourceName`:   `Provider`,
+	`ProviderName`: `Provider`,
+	`Hostname`:     `Computer`,
+	`EventTime`:    `TimeCreated`,
+	`RecordNumber`: `EventRecordID`,
+}
+
+type WinlogConfig struct {
+	// Format specifies the output format, either "json" (the default) or "tsv".
+	Format string
+
+	// Columns specifies the fields emitted, in order, by the tsv format, there can be many, e.g.:
+	//	Columns=TimeCreated
+	//	Columns=EventID
+	//	Columns=TargetUserName
+	Columns []string
+
+	// Field_Separator specifies the separator placed between TSV columns, it defaults to a tab.
+	// Occurrences of the separator within values are replaced with a space.
+	Field_Separator string
+
+	// Empty_Field_Marker specifies the TSV value emitted for missing fields, it defaults to "-".
+	Empty_Field_Marker string
+
+	// Channel_Tag retags events by their channel, matched regardless of case, there can be many, e.g.:
+	//	Channel-Tag="Security=winsec"
+	//	Channel-Tag="System=winsys"
+	// Events from other channels keep their tag.
+	Channel_Tag []string
+
+	// Timezone_Override optionally specifies the location of timestamps without a timezone,
+	// such as the NXLog EventTime field.
+	Timezone_Override string
+
+	// Assume_Local_Timezone interprets timestamps without a timezone as local time.
+	Assume_Local_Timezone bool
+}
+
+func WinlogLoadConfig(vc *config.VariableConfig) (c WinlogConfig, err error) {
+	c.Field_Separator = defaultFieldSeparator
+	c.Empty_Field_Marker = defaultEmptyFieldMarker
+	if err = mapToStrict(vc, &c); err == nil {
+		_, err = c.validate()
+	}
+	return
+}
+
+func (c *WinlogConfig) validate() (channels map[string]string, err error) {
+	switch c.Format = strings.ToLower(strings.TrimSpace(c.Format)); c.Format {
+	case ``:
+		c.Format = winlogFormatJSON
+	case winlogFormatJSON, winlogFormatTSV:
+	default:
+		err = fmt.Errorf("Format %q is invalid, must be %q or %q", c.Format, winlogFormatJSON, winlogFormatTSV)
+		return
+	}
+	c.Columns = cleanHeaders(c.Columns)
+	if c.Format == winlogFormatTSV {
+		if len(c.Columns) == 0 {
+			err = errors.New("the tsv format requires at least one Columns value")
+		} else if c.Field_Separator == `` {
+			err = errors.New("Field-Separator may not be empty")
+		} else if strings.ContainsAny(c.Field_Separator, "\r\n") {
+			err = fmt.Errorf("Field-Separator %q may not contain newlines", c.Field_Separator)
+		}
+	} else if len(c.Columns) > 0 {
+		err = errors.New("Columns requires the tsv format")
+	}
+	if err != nil {
+		return
+	} else if err = c.timeConfig().Validate(); err != nil {
+		return
+	}
+	var mp map[string]string
+	if mp, err = loadTagRemap(c.Channel_Tag); err != nil {
+		return
+	}
+	channels = make(map[string]string, len(mp))
+	for k, v := range mp {
+		lk := strings.ToLower(k)
+		if _, ok := channels[lk]; ok {
+			err = fmt.Errorf("Channel-Tag %q is specified more than once", k)
+			return
+		}
+		channels[lk] = v
+	}
+	return
+}
+
+// timeConfig returns the settings used to parse TimeCreated
+func (c WinlogConfig) timeConfig() TimeExtractorConfig {
+	return TimeExtractorConfig{
+		TimezoneOverride:    c.Timezone_Override,
+		AssumeLocalTimezone: c.Assume_Local_Timezone,
+	}
+}
+
+// Winlog flattens Windows events into a single level of fields, either from the EventLog XML
+// schema, e.g.:
+//
+//	<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+//	  <System><Provider Name="Microsoft-Windows-Security-Auditing"/><EventID>4624</EventID>...</System>
+//	  <EventData><Data Name="TargetUserName">bob</Data>...</EventData>
+//	</Event>
+//
+// or from the JSON written by the NXLog im_msvistalog module. The System fields are emitted
+// first, followed by the EventData or UserData values, and the entry timestamp is set from
+// TimeCreated. Entries which are not windows events pass through unchanged.
+type Winlog struct {
+	nocloser
+	WinlogConfig
+	te       *TimeExtractor
+	channels map[string]entry.EntryTag // lowercased channel to tag
+}
+
+func NewWinlog(cfg WinlogConfig, tagger Tagger) (*Winlog, error) {
+	wl := &Winlog{}
+	if err := wl.Config(cfg, tagger); err != nil {
+		return nil, err
+	}
+	return wl, nil
+}
+
+func (wl *Winlog) Config(v interface{}, tagger Tagger) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(WinlogConfig); ok {
+		var mp map[string]string
+		if mp, err = cfg.validate(); err != nil {
+			return
+		}
+		channels := make(map[string]entry.EntryTag, len(mp))
+		for k, name := range mp {
+			if channels[k], err = tagger.NegotiateTag(name); err != nil {
+				err = fmt.Errorf("Failed to negotiate channel tag %s: %w", name, err)
+				return
+			}
+		}
+		var te *TimeExtractor
+		if te, err = NewTimeExtractor(cfg.timeConfig()); err != nil {
+			return
+		}
+		wl.WinlogConfig, wl.te, wl.channels = cfg, te, channels
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (wl *Winlog) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	for _, ent := range ents {
+		if ent == nil || len(ent.Data) == 0 {
+			continue
+		}
+		pairs, err := parseWinlog(ent.Data)
+		if err != nil {
+			continue
+		}
+		if v, ok := pairs.get(`TimeCreated`); ok {
+			if ts, ok := wl.parseTime(v); ok {
+				ent.TS = entry.FromStandard(ts)
+			}
+		}
+		if v, ok := pairs.get(`Channel`); ok {
+			if tag, ok := wl.channels[strings.ToLower(v)]; ok {
+				ent.Tag = tag
+			}
+		}
+		if wl.Format == winlogFormatTSV {
+			ent.Data = pairs.emitLine(wl.Columns, wl.Field_Separator, wl.Empty_Field_Marker)
+		} else {
+			ent.Data = pairs.emitJSON()
+		}
+	}
+	return ents, nil
+}
+
+// parseTime handles the RFC3339 SystemTime attribute directly, which carries more precision
+// than the timegrinder expects, and falls back to the time extractor for anything else.
+func (wl *Winlog) parseTime(v string) (ts time.Time, ok bool) {
+	var err error
+	if ts, err = time.Parse(time.RFC3339Nano, v); err == nil {
+		return ts, true
+	}
+	return wl.te.Extract([]byte(v))
+}
+
+// parseWinlog flattens an XML or JSON windows event, leading data such as a syslog header is skipped
+func parseWinlog(data []byte) (pairs kvPairs, err error) {
+	xidx := bytes.Index(data, xmlEventStart)
+	jidx := bytes.IndexByte(data, '{')
+	if xidx != -1 && (jidx == -1 || xidx < jidx) {
+		return parseWinlogXML(data[xidx:])
+	} else if jidx != -1 {
+		return parseWinlogJSON(data[jidx:])
+	}
+	return nil, errNotWinlog
+}
+
+// add appends a pair unless the key is already present, so that System fields always win
+func (p kvPairs) add(key, val string) kvPairs {
+	if _, ok := p.get(key); ok || key == `` {
+		return p
+	}
+	return append(p, kvPair{key: key, val: val})
+}
+
+// winlogEvent collects the flattened fields of an XML event as it is decoded
+type winlogEvent struct {
+	system  map[string]string
+	data    kvPairs
+	message string
+	unnamed int
+}
+
+func parseWinlogXML(data []byte) (pairs kvPairs, err error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	ev := winlogEvent{system: map[string]string{}}
+	var path []string // local names of the open elements
+	var text strings.Builder
+	var dataName string
+	var tok xml.Token
+	for {
+		if tok, err = dec.Token(); err != nil {
+			return nil, errNotWinlog
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(path) == 0 && t.Name.Local != `Event` {
+				return nil, errNotWinlog
+			}
+			path = append(path, t.Name.Local)
+			text.Reset()
+			if len(path) == 3 && path[1] == `System` {
+				ev.systemAttrs(t)
+			} else if len(path) == 3 && path[1] == `EventData` {
+				dataName = xmlAttr(t, `Name`)
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(path) == 0 {
+				return nil, errNotWinlog
+			}
+			ev.closed(path, strings.TrimSpace(text.String()), dataName)
+			text.Reset()
+			if path = path[:len(path)-1]; len(path) == 0 {
+				if _, ok := ev.system[`EventID`]; !ok {
+					return nil, errNotWinlog
+				}
+				return ev.pairs(), nil
+			}
+		}
+	}
+}
+
+// systemAttrs picks up the System fields which are carried as attributes
+func (ev *winlogEvent) systemAttrs(t xml.StartElement) {
+	switch t.Name.Local {
+	case `Provider`:
+		ev.system[`Provider`] = xmlAttr(t, `Name`)
+	case `TimeCreated`:
+		ev.system[`TimeCreated`] = xmlAttr(t, `SystemTime`)
+	case `Execution`:
+		ev.system[`ProcessID`] = xmlAttr(t, `ProcessID`)
+		ev.system[`ThreadID`] = xmlAttr(t, `ThreadID`)
+	case `Security`:
+		ev.system[`UserID`] = xmlAttr(t, `UserID`)
+	}
+}
+
+// closed records the text of an element as it closes, path still includes the element
+func (ev *winlogEvent) closed(path []string, text, dataName string) {
+	if len(path) < 3 {
+		return
+	}
+	switch path[1] {
+	case `System`:
+		if len(path) == 3 && text != `` {
+			if _, ok := ev.system[path[2]]; !ok {
+				ev.system[path[2]] = text
+			}
+		}
+	case `EventData`:
+		//classic events carry unnamed Data elements, they are numbered in order
+		if len(path) == 3 && path[2] == `Data` {
+			if dataName == `` {
+				dataName = `Data` + strconv.Itoa(ev.unnamed)
+				ev.unnamed++
+			}
+			ev.data = ev.data.add(dataName, text)
+		}
+	case `UserData`:
+		//UserData wraps its values in a single provider defined element, nested values use dotted names
+		if len(path) > 3 && text != `` {
+			ev.data = ev.data.add(strings.Join(path[3:], `.`), text)
+		}
+	case `RenderingInfo`:
+		if len(path) == 3 && path[2] == `Message` {
+			ev.message = text
+		}
+	}
+}
+
+func (ev *winlogEvent) pairs() (pairs kvPairs) {
+	for _, k := range winlogSystem {
+		if v, ok := ev.system[k]; ok && v != `` {
+			pairs = append(pairs, kvPair{key: k, val: v})
+		}
+	}
+	for _, v := range ev.data {
+		pairs = pairs.add(v.key, v.val)
+	}
+	if ev.message != `` {
+		pairs = pairs.add(`Message`, ev.message)
+	}
+	return
+}
+
+func xmlAttr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ``
+}
+
+// parseWinlogJSON flattens an NXLog im_msvistalog record, the known fields are renamed to
+// their EventLog names and everything else is treated as event data. Records without an
+// EventID are not windows events.
+func parseWinlogJSON(data []byte) (pairs kvPairs, err error) {
+	system := map[string]string{}
+	var rest kvPairs
+	if err = jsonparser.ObjectEach(data, func(key, value []byte, vt jsonparser.ValueType, _ int) error {
+		k := string(key)
+		var v string
+		switch vt {
+		case jsonparser.String:
+			s, err := jsonparser.ParseString(value)
+			if err != nil {
+				return err
+			}
+			v = s
+		case jsonparser.Null:
+			return nil
+		default:
+			v = string(value)
+		}
+		if n, ok := winlogJSONNames[k]; ok {
+			k = n
+		}
+		if isWinlogSystem(k) {
+			if _, ok := system[k]; !ok {
+				system[k] = v
+			}
+		} else {
+			rest = rest.add(k, v)
+		}
+		return nil
+	}); err != nil {
+		return nil, errNotWinlog
+	} else if _, ok := system[`EventID`]; !ok {
+		return nil, errNotWinlog
+	}
+	for _, k := range winlogSystem {
+		if v, ok := system[k]; ok && v != `` {
+			pairs = append(pairs, kvPair{key: k, val: v})
+		}
+	}
+	for _, v := range rest {
+		pairs = pairs.add(v.key, v.val)
+	}
+	return
+}
+
+func isWinlogSystem(k string) bool {
+	for _, v := range winlogSystem {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const testWinlogXML = `<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">` +
+	`<System><Provider Name="Microsoft-Windows-Security-Auditing" Guid="{54849625-5478-4994-a5ba-3e3b0328c30d}"/>` +
+	`<EventID>4624</EventID><Version>2</Version><Level>0</Level><Task>12544</Task><Opcode>0</Opcode>` +
+	`<Keywords>0x8020000000000000</Keywords><TimeCreated SystemTime="2024-03-05T10:11:12.1234567Z"/>` +
+	`<EventRecordID>98765</EventRecordID><Correlation/><Execution ProcessID="636" ThreadID="700"/>` +
+	`<Channel>Security</Channel><Computer>dc01.example.com</Computer><Security/></System>` +
+	`<EventData><Data Name="SubjectUserSid">S-1-5-18</Data><Data Name="TargetUserName">bob</Data>` +
+	`<Data Name="Channel">spoofed</Data><Data Name="IpAddress">10.0.0.1</Data></EventData></Event>`
+
+func TestWinlogConfig(t *testing.T) {
+	b := `
+	[preprocessor "winlog"]
+		type = winlog
+	`
+	p, err := testLoadPreprocessor(b, `winlog`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wl, ok := p.(*Winlog); !ok {
+		t.Fatalf("preprocessor is the wrong type: %T != *Winlog", p)
+	} else if wl.Format != winlogFormatJSON {
+		t.Fatalf("bad defaults: %+v", wl.WinlogConfig)
+	}
+
+	bad := []string{
+		`Format=xml`,
+		`Format=tsv`,      // no columns
+		`Columns=EventID`, // columns without tsv
+		`Channel-Tag=Security`,
+		`Channel-Tag="Security=bad tag"`,
+		`Channel-Tag="Security=winsec"
+		Channel-Tag="security=other"`, // duplicate channel
+		`Timezone-Override=Nowhere/Special`,
+		`Timezone-Override=UTC
+		Assume-Local-Timezone=true`, // conflicting timezones
+	}
+	for _, v := range bad {
+		b = `
+		[preprocessor "winlog"]
+			type = winlog
+			` + v + `
+		`
+		if _, err = testLoadPreprocessor(b, `winlog`); err == nil {
+			t.Fatalf("failed to catch bad config %q", v)
+		}
+	}
+}
+
+func TestWinlogXML(t *testing.T) {
+	tg := &testTagger{}
+	defTag, err := tg.NegotiateTag(`default`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wl, err := NewWinlog(WinlogConfig{Channel_Tag: []string{`security=winsec`}}, tg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secTag, err := tg.NegotiateTag(`winsec`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent := entry.Entry{Tag: defTag, Data: []byte(`<14>Mar  5 10:11:12 dc01 ` + testWinlogXML)}
+	if _, err = wl.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	}
+	exp := `{"EventID":"4624","Provider":"Microsoft-Windows-Security-Auditing","Channel":"Security",` +
+		`"Computer":"dc01.example.com","TimeCreated":"2024-03-05T10:11:12.1234567Z","EventRecordID":"98765",` +
+		`"Level":"0","Task":"12544","Opcode":"0","Keywords":"0x8020000000000000","Version":"2","ProcessID":"636",` +
+		`"ThreadID":"700","SubjectUserSid":"S-1-5-18","TargetUserName":"bob","IpAddress":"10.0.0.1"}`
+	if string(ent.Data) != exp {
+		t.Fatalf("Output mismatch:\n%s\n%s", ent.Data, exp)
+	} else if ent.Tag != secTag {
+		t.Fatalf("bad tag %d != %d", ent.Tag, secTag)
+	} else if ts := time.Date(2024, 3, 5, 10, 11, 12, 123456700, time.UTC); !ent.TS.StandardTime().Equal(ts) {
+		t.Fatalf("bad timestamp %v != %v", ent.TS.StandardTime(), ts)
+	}
+
+	// UserData and unnamed Data values
+	in := `<Event><System><Provider Name="Microsoft-Windows-Eventlog"/><EventID>1102</EventID>` +
+		`<TimeCreated SystemTime="2024-03-05T10:11:12Z"/><Channel>System</Channel></System>` +
+		`<UserData><LogFileCleared xmlns="http://manifests.microsoft.com/win/2004/08/windows/eventlog">` +
+		`<SubjectUserName>alice</SubjectUserName><Detail><Reason>manual</Reason></Detail></LogFileCleared></UserData></Event>`
+	ent = entry.Entry{Tag: defTag, Data: []byte(in)}
+	if _, err = wl.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	}
+	exp = `{"EventID":"1102","Provider":"Microsoft-Windows-Eventlog","Channel":"System",` +
+		`"TimeCreated":"2024-03-05T10:11:12Z","SubjectUserName":"alice","Detail.Reason":"manual"}`
+	if string(ent.Data) != exp {
+		t.Fatalf("Output mismatch:\n%s\n%s", ent.Data, exp)
+	} else if ent.Tag != defTag {
+		t.Fatalf("unmapped channel was retagged to %d", ent.Tag)
+	}
+
+	in = `<Event><System><EventID Qualifiers="0">7036</EventID></System>` +
+		`<EventData><Data>Print Spooler</Data><Data>running</Data></EventData></Event>`
+	ent = entry.Entry{Data: []byte(in)}
+	if _, err = wl.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if exp = `{"EventID":"7036","Data0":"Print Spooler","Data1":"running"}`; string(ent.Data) != exp {
+		t.Fatalf("Output mismatch:\n%s\n%s", ent.Data, exp)
+	}
+}
+
+func TestWinlogJSON(t *testing.T) {
+	wl, err := NewWinlog(WinlogConfig{
+		Format:             winlogFormatTSV,
+		Columns:            []string{`TimeCreated`, `EventID`, `Provider`, `Computer`, `TargetUserName`, `LogonType`},
+		Field_Separator:    "\t",
+		Empty_Field_Marker: "-",
+	}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := `{"EventTime":"2024-03-05 10:11:12","Hostname":"dc01","EventID":4624,"SourceName":"Microsoft-Windows-Security-Auditing",` +
+		`"Channel":"Security","TargetUserName":"bob\tsmith","LogonType":null}`
+	ent := entry.Entry{Data: []byte(in)}
+	if _, err = wl.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	}
+	exp := "2024-03-05 10:11:12\t4624\tMicrosoft-Windows-Security-Auditing\tdc01\tbob smith\t-"
+	if string(ent.Data) != exp {
+		t.Fatalf("Output mismatch:\n%q\n%q", ent.Data, exp)
+	} else if ts := time.Date(2024, 3, 5, 10, 11, 12, 0, time.UTC); !ent.TS.StandardTime().Equal(ts) {
+		t.Fatalf("bad timestamp %v != %v", ent.TS.StandardTime(), ts)
+	}
+
+	// the timezone override applies to the zoneless EventTime
+	if err = wl.Config(WinlogConfig{Timezone_Override: `America/New_York`}, &testTagger{}); err != nil {
+		t.Fatal(err)
+	}
+	ent = entry.Entry{Data: []byte(in)}
+	if _, err = wl.Process([]*entry.Entry{&ent}); err != nil {
+		t.Fatal(err)
+	} else if ts := time.Date(2024, 3, 5, 15, 11, 12, 0, time.UTC); !ent.TS.StandardTime().Equal(ts) {
+		t.Fatalf("bad timestamp %v != %v", ent.TS.StandardTime(), ts)
+	}
+}
+
+func TestWinlogPassthrough(t *testing.T) {
+	wl, err := NewWinlog(WinlogConfig{}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := entry.Now()
+	for _, v := range []string{
+		`just some text`,
+		`{"not":"an event"}`,
+		`{"EventID":4624`,
+		`<Event><System><EventID>4624</EventID></System>`, // unterminated
+		`<Events><Event/></Events>`,
+		`<Event><System><Channel>Security</Channel></System></Event>`, // no EventID
+	} {
+		ent := entry.Entry{TS: ts, Tag: 7, Data: []byte(v)}
+		if _, err = wl.Process([]*entry.Entry{&ent}); err != nil {
+			t.Fatal(err)
+		} else if string(ent.Data) != v || ent.Tag != 7 || ent.TS != ts {
+			t.Fatalf("malformed record %q was modified: %q", v, ent.Data)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+	"github.com/gravwell/gravwell/v3/timegrinder"
+)
+
+const (
+	cefHeaderFields  = 7 // version through severity
+	leef1HeaderField = 5 // version through event ID
+	leef2HeaderField = 6 // LEEF 2.0 adds the delimiter
+
+	cefTimeField  = `rt`
+	leefTimeField = `devTime`
+
+	defaultMaxVendorTags = 64
+)
+
+var (
+	cefPrefix  = []byte("CEF:")
+	leefPrefix = []byte("LEEF:")
+)
+
+type tagNegotiator interface {
+	NegotiateTag(string) (entry.EntryTag, error)
+}
+
+// eventRecord is the parsed form of a CEF or LEEF record
+type eventRecord struct {
+	vendor  string
+	product string
+	ext     map[string]string
+}
+
+// parseCEF parses a CEF record, any syslog header in front of the CEF: prefix is ignored
+func parseCEF(b []byte) (rec eventRecord, ok bool) {
+	idx := bytes.Index(b, cefPrefix)
+	if idx < 0 {
+		return
+	}
+	flds := splitHeader(string(b[idx+len(cefPrefix):]), cefHeaderFields)
+	if len(flds) != cefHeaderFields+1 {
+		return
+	}
+	rec = eventRecord{
+		vendor:  flds[1],
+		product: flds[2],
+		ext:     parseCEFExtension(flds[cefHeaderFields]),
+	}
+	ok = true
+	return
+}
+
+// parseLEEF parses a LEEF 1.0 or 2.0 record, any syslog header in front of the LEEF: prefix is ignored
+func parseLEEF(b []byte) (rec eventRecord, ok bool) {
+	idx := bytes.Index(b, leefPrefix)
+	if idx < 0 {
+		return
+	}
+	s := string(b[idx+len(leefPrefix):])
+	cnt := leef1HeaderField
+	if strings.HasPrefix(s, `2.`) {
+		cnt = leef2HeaderField
+	}
+	flds := splitHeader(s, cnt)
+	if len(flds) != cnt+1 {
+		return
+	}
+	delim := "\t"
+	if cnt == leef2HeaderField {
+		if delim, ok = leefDelimiter(flds[5]); !ok {
+			return
+		}
+	}
+	rec = eventRecord{
+		vendor:  flds[1],
+		product: flds[2],
+		ext:     parseLEEFExtension(flds[cnt], delim),
+	}
+	ok = true
+	return
+}
+
+// splitHeader splits cnt pipe delimited header fields off of s, honoring escaped pipes.
+// The remainder of s is returned as the last element.
+func splitHeader(s string, cnt int) (flds []string) {
+	var sb strings.Builder
+	var escaped bool
+	for i, r := range s {
+		if len(flds) == cnt {
+			flds = append(flds, s[i:])
+			return
+		}
+		if escaped {
+			sb.WriteRune(r)
+			escaped = false
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = true
+		case '|':
+			flds = append(flds, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if len(flds) == cnt {
+		flds = append(flds, ``) //empty extension
+	}
+	return
+}
+
+// parseCEFExtension parses space separated key=value pairs, values may contain spaces
+// so a value runs until the key of the next pair
+func parseCEFExtension(s string) map[string]string {
+	mp := map[string]string{}
+	var key string
+	var valStart int
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++ //skip the escaped character
+			continue
+		} else if s[i] != '=' {
+			continue
+		}
+		//find the start of this key
+		ks := strings.LastIndexByte(s[:i], ' ') + 1
+		if ks <= valStart && key != `` {
+			continue //an unescaped = inside a value with no preceding space, treat as part of the value
+		}
+		if key != `` {
+			mp[key] = unescapeCEF(strings.TrimSpace(s[valStart:ks]))
+		}
+		key = s[ks:i]
+		valStart = i + 1
+	}
+	if key != `` {
+		mp[key] = unescapeCEF(strings.TrimSpace(s[valStart:]))
+	}
+	return mp
+}
+
+func unescapeCEF(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\=`, `=`, `\\`, `\`, `\n`, "\n", `\r`, "\r").Replace(s)
+}
+
+func parseLEEFExtension(s, delim string) map[string]string {
+	mp := map[string]string{}
+	for _, kv := range strings.Split(s, delim) {
+		if k, v, ok := strings.Cut(kv, `=`); ok {
+			mp[strings.TrimSpace(k)] = v
+		}
+	}
+	return mp
+}
+
+// leefDelimiter decodes the LEEF 2.0 delimiter field which is a single character
+// or a hex value such as x09 or 0x09, an empty field means tab
+func leefDelimiter(s string) (string, bool) {
+	if s == `` {
+		return "\t", true
+	} else if len(s) == 1 {
+		return s, true
+	}
+	h := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(s), `0`), `x`)
+	if v, err := strconv.ParseUint(h, 16, 16); err == nil && v > 0 {
+		return string(rune(v)), true
+	}
+	return ``, false
+}
+
+// eventTime extracts a timestamp from a CEF rt or LEEF devTime value, values may be
+// epoch milliseconds or any format the timegrinder understands
+func eventTime(v string, tg *timegrinder.TimeGrinder) (ts time.Time, ok bool) {
+	if v = strings.TrimSpace(v); v == `` {
+		return
+	}
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if len(v) <= 10 {
+			ts = time.Unix(n, 0).UTC() //epoch seconds
+		} else {
+			ts = time.UnixMilli(n).UTC()
+		}
+		ok = true
+		return
+	}
+	if tg != nil {
+		var err error
+		if ts, ok, err = tg.Extract([]byte(v)); err != nil {
+			ok = false
+		}
+	}
+	return
+}
+
+// vendorTags caches the tags derived from event vendors and products for a listener. The names come
+// from the unauthenticated records themselves, so only Allowed-Vendor-Tags names are negotiated when
+// that list is set and at most Max-Vendor-Tags names are negotiated otherwise.
+type vendorTags struct {
+	sync.Mutex
+	name    string // listener name
+	tagger  tagNegotiator
+	allowed map[string]bool // nil allows any name up to max
+	max     int
+	full    bool // the limit was hit and logged
+	tags    map[string]entry.EntryTag
+}
+
+func newVendorTags(name string, tn tagNegotiator, allowed []string, max int) *vendorTags {
+	vt := &vendorTags{
+		name:   name,
+		tagger: tn,
+		max:    max,
+		tags:   map[string]entry.EntryTag{},
+	}
+	if vt.max == 0 {
+		vt.max = defaultMaxVendorTags
+	}
+	if len(allowed) > 0 {
+		vt.allowed = make(map[string]bool, len(allowed))
+		for _, v := range allowed {
+			vt.allowed[strings.TrimSpace(v)] = true
+		}
+	}
+	return vt
+}
+
+// lookup returns the tag for a derived name, ok is false if the name is not allowed,
+// the limit has been reached, or the tag cannot be negotiated
+func (vt *vendorTags) lookup(name string) (tag entry.EntryTag, ok bool) {
+	vt.Lock()
+	defer vt.Unlock()
+	if tag, ok = vt.tags[name]; ok {
+		return
+	} else if vt.allowed != nil && !vt.allowed[name] {
+		return
+	} else if vt.allowed == nil && len(vt.tags) >= vt.max {
+		if !vt.full {
+			vt.full = true
+			lg.Warn("vendor tag limit reached, new vendors will use the listener tag",
+				log.KV("listener", vt.name), log.KV("limit", vt.max))
+		}
+		return
+	}
+	var err error
+	if tag, err = vt.tagger.NegotiateTag(name); err != nil {
+		return
+	}
+	vt.tags[name] = tag
+	ok = true
+	return
+}
+
+// vendorTag derives a tag from the vendor and product of an event, falling back to the listener
+// tag if the name is not a valid tag, is not allowed, or cannot be negotiated
+func (hc handlerConfig) vendorTag(rec eventRecord) entry.EntryTag {
+	if hc.vendorTags == nil || (rec.vendor == `` && rec.product == ``) {
+		return hc.defaultTag()
+	}
+	name, err := ingest.RemapTag(strings.Trim(rec.vendor+`_`+rec.product, `_`), '_')
+	if err != nil {
+		return hc.defaultTag()
+	}
+	if tag, ok := hc.vendorTags.lookup(name); ok {
+		return tag
+	}
+	return hc.defaultTag()
+}
+
+// handleEventLog handles CEF and LEEF records, the raw record is always preserved as the entry data.
+// Records that cannot be parsed are handled exactly like the line reader.
+func (hc handlerConfig) handleEventLog(b []byte, ip net.IP, tg *timegrinder.TimeGrinder) (*entry.Entry, error) {
+	var rec eventRecord
+	var ok bool
+	var tsKey string
+	if hc.lrt == cefReader {
+		rec, ok = parseCEF(b)
+		tsKey = cefTimeField
+	} else {
+		rec, ok = parseLEEF(b)
+		tsKey = leefTimeField
+	}
+	if !ok {
+		hc.stats.parseError()
+		return handleLog(b, ip, hc.ignoreTimestamps, hc.lineTag(b), tg)
+	}
+	//Tag-Router rules take precedence over the vendor tag
+	tag, routed := hc.routeTag(b)
+	if !routed {
+		if tag = hc.defaultTag(); hc.tagFromVendor {
+			tag = hc.vendorTag(rec)
+		}
+	}
+	if !hc.ignoreTimestamps {
+		if ts, ok := eventTime(rec.ext[tsKey], tg); ok {
+			return &entry.Entry{
+				SRC:  ip,
+				TS:   entry.FromStandard(ts),
+				Tag:  tag,
+				Data: b,
+			}, nil
+		}
+	}
+	return handleLog(b, ip, hc.ignoreTimestamps, tag, tg)
+}
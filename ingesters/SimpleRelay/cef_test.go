@@ -0,0 +1,150 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+	"github.com/gravwell/gravwell/v3/timegrinder"
+)
+
+const (
+	testCEF   = `<134>Feb 14 19:04:54 host CEF:0|Security|threat\|manager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 msg=Detected a threat\=bad. No action needed rt=1700000000123`
+	testLEEF  = "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tdevTime=2023-11-14T22:13:20Z"
+	testLEEF2 = `LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^devTime=1700000000`
+)
+
+type testTagNegotiator map[string]entry.EntryTag
+
+func (t testTagNegotiator) NegotiateTag(name string) (entry.EntryTag, error) {
+	if tg, ok := t[name]; ok {
+		return tg, nil
+	}
+	return 0, errors.New("unknown tag")
+}
+
+func TestParseCEF(t *testing.T) {
+	rec, ok := parseCEF([]byte(testCEF))
+	if !ok {
+		t.Fatal("failed to parse CEF")
+	}
+	if rec.vendor != `Security` || rec.product != `threat|manager` {
+		t.Fatalf("bad header: %+v", rec)
+	}
+	exp := map[string]string{
+		`src`: `10.0.0.1`,
+		`dst`: `2.1.2.2`,
+		`msg`: `Detected a threat=bad. No action needed`,
+		`rt`:  `1700000000123`,
+	}
+	for k, v := range exp {
+		if rec.ext[k] != v {
+			t.Fatalf("bad extension value for %s: %q != %q", k, rec.ext[k], v)
+		}
+	}
+	if _, ok = parseCEF([]byte(`CEF:0|missing|fields`)); ok {
+		t.Fatal("failed to reject truncated CEF header")
+	}
+}
+
+func TestParseLEEF(t *testing.T) {
+	rec, ok := parseLEEF([]byte(testLEEF))
+	if !ok {
+		t.Fatal("failed to parse LEEF 1.0")
+	} else if rec.vendor != `Microsoft` || rec.ext[`dst`] != `172.50.123.1` {
+		t.Fatalf("bad LEEF 1.0 record: %+v", rec)
+	}
+	if rec, ok = parseLEEF([]byte(testLEEF2)); !ok {
+		t.Fatal("failed to parse LEEF 2.0")
+	} else if rec.product != `StealthWatch` || rec.ext[`src`] != `10.0.1.8` || rec.ext[`devTime`] != `1700000000` {
+		t.Fatalf("bad LEEF 2.0 record: %+v", rec)
+	}
+	if d, ok := leefDelimiter(`x09`); !ok || d != "\t" {
+		t.Fatalf("bad hex delimiter %q", d)
+	}
+}
+
+func TestHandleEventLog(t *testing.T) {
+	tg, err := timegrinder.NewTimeGrinder(timegrinder.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ip := net.ParseIP("192.168.1.1")
+	hc := handlerConfig{
+		lrt:           cefReader,
+		tags:          testTags(1),
+		tagFromVendor: true,
+		vendorTags:    newVendorTags(`cef`, testTagNegotiator{`Security_threat_manager`: 7}, nil, 0),
+	}
+	ent, err := hc.handleEventLog([]byte(testCEF), ip, tg)
+	if err != nil {
+		t.Fatal(err)
+	} else if ent.Tag != 7 {
+		t.Fatalf("tag was not derived from vendor: %d", ent.Tag)
+	} else if !ent.TS.StandardTime().Equal(time.UnixMilli(1700000000123)) {
+		t.Fatalf("bad rt timestamp: %v", ent.TS)
+	} else if string(ent.Data) != testCEF {
+		t.Fatalf("raw record was not preserved: %s", ent.Data)
+	}
+
+	hc.lrt = leefReader
+	if ent, err = hc.handleEventLog([]byte(testLEEF), ip, tg); err != nil {
+		t.Fatal(err)
+	} else if ent.Tag != 1 {
+		t.Fatalf("unknown vendor did not fall back to listener tag: %d", ent.Tag)
+	} else if !ent.TS.StandardTime().Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("bad devTime timestamp: %v", ent.TS)
+	}
+
+	//malformed records are treated as plain lines
+	bad := []byte(`2023-11-14T22:13:20Z not an event at all`)
+	if ent, err = hc.handleEventLog(bad, ip, tg); err != nil {
+		t.Fatal(err)
+	} else if ent.Tag != 1 || !ent.TS.StandardTime().Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("malformed record not handled as a line: %+v", ent)
+	}
+}
+
+func TestVendorTags(t *testing.T) {
+	if lg == nil {
+		lg = log.NewDiscardLogger()
+	}
+	tn := testTagNegotiator{`a_one`: 2, `b_two`: 3, `c_three`: 4}
+	hc := handlerConfig{
+		tags:          testTags(1),
+		tagFromVendor: true,
+		vendorTags:    newVendorTags(`cef`, tn, nil, 2),
+	}
+	rec := func(vendor, product string) eventRecord {
+		return eventRecord{vendor: vendor, product: product}
+	}
+	//derived tags beyond the limit fall back to the listener tag, cached tags keep working
+	if tag := hc.vendorTag(rec(`a`, `one`)); tag != 2 {
+		t.Fatalf("bad vendor tag: %d", tag)
+	} else if tag = hc.vendorTag(rec(`b`, `two`)); tag != 3 {
+		t.Fatalf("bad vendor tag: %d", tag)
+	} else if tag = hc.vendorTag(rec(`c`, `three`)); tag != 1 {
+		t.Fatalf("vendor tag beyond the limit was negotiated: %d", tag)
+	} else if tag = hc.vendorTag(rec(`a`, `one`)); tag != 2 {
+		t.Fatalf("cached vendor tag was lost: %d", tag)
+	}
+
+	//only allowed names are negotiated
+	hc.vendorTags = newVendorTags(`cef`, tn, []string{`c_three`}, 0)
+	if tag := hc.vendorTag(rec(`a`, `one`)); tag != 1 {
+		t.Fatalf("vendor tag outside the allowed list was negotiated: %d", tag)
+	} else if tag = hc.vendorTag(rec(`c`, `three`)); tag != 4 {
+		t.Fatalf("allowed vendor tag was not negotiated: %d", tag)
+	}
+}
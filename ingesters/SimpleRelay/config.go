@@ -9,9 +9,17 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,7 +27,9 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/attach"
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
+	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
 const (
@@ -29,55 +39,128 @@ const (
 	tcp6            bindType = iota
 	udp6            bindType = iota
 	TLS             bindType = iota
+	unix            bindType = iota
+	unixgram        bindType = iota
 
 	lineReader    readerType = iota
 	rfc5424Reader readerType = iota
 	rfc6587Reader readerType = iota
+	jsonReader    readerType = iota
+	cefReader     readerType = iota
+	leefReader    readerType = iota
+	rawReader     readerType = iota
+
+	lfFraming    framingType = iota
+	octetFraming framingType = iota
+	autoFraming  framingType = iota
 )
 
 var ()
 
 type bindType int
 type readerType int
+type framingType int
 
+// listener is a Listener section. The settings which only apply to some reader types may also
+// be given in a Reader-Options section of the same name, see readerOptions.go.
 type listener struct {
 	baseConfig
-	Reader_Type   string
-	Drop_Priority bool // remove the <nnn> priority value at the start of the log message, useful for things like fortinet
-	Keep_Priority bool `json:"-"` //NOTE DEPRECATED AND UNUSED.  Left so that config parsing doesn't break
+	Reader_Type      string
+	Drop_Priority    bool   // remove the <nnn> priority value at the start of the log message, useful for things like fortinet
+	Keep_Priority    bool   `json:"-"` //NOTE DEPRECATED AND UNUSED.  Left so that config parsing doesn't break
+	Timestamp_Field  string // JSON reader only, dotted path to the field containing the timestamp
+	Timestamp_Format string // JSON reader only, timestamp format override applied to the Timestamp-Field
+
+	Source_From_Header bool // RFC5424 and RFC6587 readers only, use the syslog HOSTNAME as the source when it is an IP
+
+	RFC6587_Framing string // RFC5424 reader only, TCP framing of lf (default), octet, or auto
+
+	Unix_Socket_Permissions string // octal file mode applied to unix and unixgram sockets, e.g. 0660
+
+	Max_Lines_Per_Second int // per-connection entry rate limit, zero is unlimited
+	Max_Bytes_Per_Second int // per-connection byte rate limit, zero is unlimited
+
+	Line_Continuation_Regex string // line reader only, matching lines are appended to the previous entry
+	Max_Multiline_Bytes     int    // cap on a joined multiline entry, defaults to 1MB
+	Multiline_Flush_Timeout string // pending multiline entry is emitted once the connection is idle this long, defaults to 2s
+
+	Max_Line_Length int    // line, JSON, CEF, and LEEF readers only, longest line buffered while waiting for a newline, defaults to 4MB
+	On_Oversize     string // truncate (default) emits the first Max-Line-Length bytes of a long line, drop discards it
+	Line_Delimiter  string // line, JSON, CEF, and LEEF readers only, record delimiter with escapes such as \0, \r\n, or \x1e, defaults to \n
+
+	Tag_From_Vendor     bool     // CEF and LEEF readers only, tag entries with the device vendor and product
+	Allowed_Vendor_Tags []string // CEF and LEEF readers only, vendor_product tags Tag-From-Vendor may use, others get Tag-Name
+	Max_Vendor_Tags     int      // CEF and LEEF readers only, distinct vendor tags negotiated without Allowed-Vendor-Tags, defaults to 64
+
+	Max_Connections int    // maximum concurrent connections for stream listeners, zero is unlimited
+	Idle_Timeout    string // duration after which a connection with no data is closed, e.g. 5m
+
+	Compression string // stream listeners only, none (default) or gzip
+
+	UDP_Datagram_Per_Entry bool // datagram listeners only, each datagram is one entry regardless of embedded newlines or headers
+
+	Allow_Remote []string // CIDRs or addresses allowed to deliver data, empty allows all
+	Deny_Remote  []string // CIDRs or addresses refused, deny rules win over Allow-Remote
+
+	Log_Level string // minimum level of connection events logged for the listener, e.g. WARN or OFF, default is the global Log-Level
+
+	Tag_Router []string // ordered regex=tag rules evaluated against each entry, first match wins and Tag-Name is the fallback
+
+	Frame_Length         int    // raw reader only, fixed size in bytes of every binary frame
+	Length_Prefix_Bytes  int    // raw reader only, size of the length prefix on each variable sized frame: 1, 2, 4, or 8
+	Length_Prefix_Endian string // raw reader only, byte order of the length prefix: big (default) or little
+
+	Proxy_Protocol bool // tcp binds only, require a PROXY protocol v1 or v2 header and use the client address it carries as the source
+
+	Enrichment []string // static key=value enumerated values attached to every entry before preprocessing, e.g. datacenter=us-east-1
+
+	Dedup_Window int // number of recent entries remembered to drop exact duplicates by timestamp, source, and data, zero disables
+
+	Strip_BOM bool // line, JSON, CEF, LEEF, and rfc5424 readers only, remove a UTF-8 byte order mark from the start of each connection or datagram
+	Trim_CR   bool // line, JSON, CEF, LEEF, and rfc5424 readers only, remove trailing carriage returns from each entry
 }
 
 type baseConfig struct {
 	Tag_Name                  string
-	Bind_String               string //IP port pair 127.0.0.1:1234
-	Ignore_Timestamps         bool   //Just apply the current timestamp to lines as we get them
+	Bind_String               []string //IP port pair 127.0.0.1:1234, may be specified multiple times
+	Ignore_Timestamps         bool     //Just apply the current timestamp to lines as we get them
 	Assume_Local_Timezone     bool
 	Timezone_Override         string
 	Source_Override           string
 	Timestamp_Format_Override string //override the timestamp format
 	Cert_File                 string
 	Key_File                  string
+	Client_CA_File            string //optional CA bundle used to require and verify client certificates on TLS listeners
 	Preprocessor              []string
+	Max_Timestamp_Skew        string //optional bound on how far entry timestamps may be from now, e.g. 24h
+	On_Skew                   string //clamp (default) sets out of bound timestamps to now, drop discards the entry
+}
+
+type global struct {
+	config.IngestConfig
+	Metrics_Bind string // optional address serving Prometheus metrics and a health check over HTTP, off when empty
 }
 
 type cfgReadType struct {
-	Global        config.IngestConfig
-	Attach        attach.AttachConfig
-	Listener      map[string]*listener
-	JSONListener  map[string]*jsonListener
-	RegexListener map[string]*regexListener
-	Preprocessor  processors.ProcessorConfig
-	TimeFormat    config.CustomTimeFormat
+	Global         global
+	Attach         attach.AttachConfig
+	Listener       map[string]*listener
+	JSONListener   map[string]*jsonListener
+	RegexListener  map[string]*regexListener
+	Preprocessor   processors.ProcessorConfig
+	TimeFormat     config.CustomTimeFormat
+	Reader_Options map[string]*config.VariableConfig // keyed by Listener name
 }
 
 type cfgType struct {
-	config.IngestConfig
-	Attach        attach.AttachConfig
-	Listener      map[string]*listener
-	JSONListener  map[string]*jsonListener
-	RegexListener map[string]*regexListener
-	Preprocessor  processors.ProcessorConfig
-	TimeFormat    config.CustomTimeFormat
+	global
+	Attach         attach.AttachConfig
+	Listener       map[string]*listener
+	JSONListener   map[string]*jsonListener
+	RegexListener  map[string]*regexListener
+	Preprocessor   processors.ProcessorConfig
+	TimeFormat     config.CustomTimeFormat
+	Reader_Options map[string]*config.VariableConfig // keyed by Listener name
 }
 
 func GetConfig(path, overlayPath string) (*cfgType, error) {
@@ -89,13 +172,14 @@ func GetConfig(path, overlayPath string) (*cfgType, error) {
 		return nil, err
 	}
 	c := &cfgType{
-		IngestConfig:  cr.Global,
-		Attach:        cr.Attach,
-		Listener:      cr.Listener,
-		RegexListener: cr.RegexListener,
-		JSONListener:  cr.JSONListener,
-		Preprocessor:  cr.Preprocessor,
-		TimeFormat:    cr.TimeFormat,
+		global:         cr.Global,
+		Attach:         cr.Attach,
+		Listener:       cr.Listener,
+		RegexListener:  cr.RegexListener,
+		JSONListener:   cr.JSONListener,
+		Preprocessor:   cr.Preprocessor,
+		TimeFormat:     cr.TimeFormat,
+		Reader_Options: cr.Reader_Options,
 	}
 
 	if err := c.Verify(); err != nil {
@@ -114,11 +198,23 @@ func (c *cfgType) Verify() error {
 	if len(c.Listener) == 0 && len(c.RegexListener) == 0 && len(c.JSONListener) == 0 {
 		return errors.New("No listeners specified")
 	}
+	if c.Metrics_Bind != `` {
+		if _, _, err := net.SplitHostPort(c.Metrics_Bind); err != nil {
+			return fmt.Errorf("Metrics-Bind %q is invalid: %v", c.Metrics_Bind, err)
+		}
+	}
 	if err := c.Preprocessor.Validate(); err != nil {
 		return err
 	} else if err = c.TimeFormat.Validate(); err != nil {
 		return err
 	}
+	for k, vc := range c.Reader_Options {
+		if v, ok := c.Listener[k]; !ok {
+			return fmt.Errorf("Reader-Options %q does not match a Listener", k)
+		} else if err := v.applyReaderOptions(vc); err != nil {
+			return fmt.Errorf("Reader-Options %q is invalid: %v", k, err)
+		}
+	}
 	bindMp := make(map[string]string, 1)
 	for k, v := range c.Listener {
 		if err := v.baseConfig.Validate(); err != nil {
@@ -130,22 +226,19 @@ func (c *cfgType) Verify() error {
 		if ingest.CheckTag(v.Tag_Name) != nil {
 			return errors.New("Invalid characters in the Tag-Name for " + k)
 		}
-		if v.Timezone_Override != "" {
-			if v.Assume_Local_Timezone {
-				// cannot do both
-				return fmt.Errorf("Cannot specify Assume-Local-Timezone and Timezone-Override in the same listener %v", k)
-			}
-			if _, err := time.LoadLocation(v.Timezone_Override); err != nil {
-				return fmt.Errorf("Invalid timezone override %v in listener %v: %v", v.Timezone_Override, k, err)
-			}
+		if err := c.checkFormatOverride(&v.baseConfig); err != nil {
+			return fmt.Errorf("Invalid timestamp format override %q in listener %v: %v", v.Timestamp_Format_Override, k, err)
+		} else if err = v.timeConfig(c.TimeFormat).Validate(); err != nil {
+			return fmt.Errorf("Invalid timestamp settings in listener %v: %v", k, err)
 		}
 		if err := checkListenerSettings(v); err != nil {
 			return fmt.Errorf("Listener %q is invalid: %v", k, err)
+		} else if _, err = v.enrichment(c.Attach.Names()); err != nil {
+			return fmt.Errorf("Listener %q is invalid: %v", k, err)
 		}
-		if n, ok := bindMp[v.Bind_String]; ok {
-			return errors.New("Bind-String for " + k + " already in use by " + n)
+		if err := checkBindCollisions(bindMp, k, v.Bind_String); err != nil {
+			return err
 		}
-		bindMp[v.Bind_String] = k
 		if err := c.Preprocessor.CheckProcessors(v.Preprocessor); err != nil {
 			return fmt.Errorf("Listener %s preprocessor invalid: %v", k, err)
 		}
@@ -160,19 +253,14 @@ func (c *cfgType) Verify() error {
 		if ingest.CheckTag(v.Tag_Name) != nil {
 			return errors.New("Invalid characters in the Tag-Name for " + k)
 		}
-		if v.Timezone_Override != "" {
-			if v.Assume_Local_Timezone {
-				// cannot do both
-				return fmt.Errorf("Cannot specify Assume-Local-Timezone and Timezone-Override in the same listener %v", k)
-			}
-			if _, err := time.LoadLocation(v.Timezone_Override); err != nil {
-				return fmt.Errorf("Invalid timezone override %v in listener %v: %v", v.Timezone_Override, k, err)
-			}
+		if err := c.checkFormatOverride(&v.baseConfig); err != nil {
+			return fmt.Errorf("Invalid timestamp format override %q in listener %v: %v", v.Timestamp_Format_Override, k, err)
+		} else if err = v.timeConfig(c.TimeFormat).Validate(); err != nil {
+			return fmt.Errorf("Invalid timestamp settings in listener %v: %v", k, err)
 		}
-		if n, ok := bindMp[v.Bind_String]; ok {
-			return errors.New("Bind-String for " + k + " already in use by " + n)
+		if err := checkBindCollisions(bindMp, k, v.Bind_String); err != nil {
+			return err
 		}
-		bindMp[v.Bind_String] = k
 		if err := c.Preprocessor.CheckProcessors(v.Preprocessor); err != nil {
 			return fmt.Errorf("Listener %s preprocessor invalid: %v", k, err)
 		}
@@ -196,19 +284,14 @@ func (c *cfgType) Verify() error {
 				return errors.New("Invalid characters in Tag-Match tag " + t.Tag + " for " + k)
 			}
 		}
-		if v.Timezone_Override != "" {
-			if v.Assume_Local_Timezone {
-				// cannot do both
-				return fmt.Errorf("Cannot specify Assume-Local-Timezone and Timezone-Override in the same listener %v", k)
-			}
-			if _, err := time.LoadLocation(v.Timezone_Override); err != nil {
-				return fmt.Errorf("Invalid timezone override %v in listener %v: %v", v.Timezone_Override, k, err)
-			}
+		if err := c.checkFormatOverride(&v.baseConfig); err != nil {
+			return fmt.Errorf("Invalid timestamp format override %q in listener %v: %v", v.Timestamp_Format_Override, k, err)
+		} else if err = v.timeConfig(c.TimeFormat).Validate(); err != nil {
+			return fmt.Errorf("Invalid timestamp settings in listener %v: %v", k, err)
 		}
-		if n, ok := bindMp[v.Bind_String]; ok {
-			return errors.New("Bind-String for " + k + " already in use by " + n)
+		if err := checkBindCollisions(bindMp, k, v.Bind_String); err != nil {
+			return err
 		}
-		bindMp[v.Bind_String] = k
 		if err := c.Preprocessor.CheckProcessors(v.Preprocessor); err != nil {
 			return fmt.Errorf("Listener %s preprocessor invalid: %v", k, err)
 		}
@@ -231,6 +314,17 @@ func (c *cfgType) Tags() ([]string, error) {
 			tags = append(tags, v.Tag_Name)
 			tagMp[v.Tag_Name] = true
 		}
+		//every router target must be negotiated up front
+		rts, err := v.tagRoutes()
+		if err != nil {
+			return nil, err
+		}
+		for _, rt := range rts {
+			if _, ok := tagMp[rt.tag]; !ok {
+				tags = append(tags, rt.tag)
+				tagMp[rt.tag] = true
+			}
+		}
 	}
 
 	for _, v := range c.RegexListener {
@@ -274,34 +368,601 @@ func (c *cfgType) AttachConfig() attach.AttachConfig {
 
 func checkListenerSettings(l *listener) (err error) {
 	var lt readerType
-	var bt bindType
 	if l == nil {
 		return errors.New("nil listener")
 	}
 	if lt, err = translateReaderType(l.Reader_Type); err != nil {
 		return
 	}
-	if bt, _, err = translateBindType(l.Bind_String); err != nil {
-		return
-	}
 	if l.Drop_Priority && !(lt == rfc5424Reader || lt == rfc6587Reader) {
 		err = fmt.Errorf("Drop-Priority is not compatible with reader type %s", lt)
 		return
 	}
-	if lt == rfc6587Reader && bt.UDP() {
-		err = fmt.Errorf("RFC6587 reader type is not compatible with a UDP bind string")
+	var hasUnix, hasDgram bool
+	for _, bstr := range l.Bind_String {
+		if err = checkListenerBind(l, lt, bstr); err != nil {
+			return
+		}
+		bt, _, _ := translateBindType(bstr)
+		hasUnix = hasUnix || bt.Unix()
+		hasDgram = hasDgram || bt.UDP() || bt == unixgram
+	}
+	if l.UDP_Datagram_Per_Entry && !hasDgram {
+		err = errors.New("UDP-Datagram-Per-Entry requires a udp or unixgram bind string")
+		return
+	}
+	if hasUnix {
+		if _, err = l.socketPermissions(); err != nil {
+			return
+		}
+	} else if l.Unix_Socket_Permissions != `` {
+		err = errors.New("Unix-Socket-Permissions requires a unix or unixgram bind string")
+		return
+	}
+	if l.Max_Lines_Per_Second < 0 {
+		err = fmt.Errorf("Max-Lines-Per-Second %d is invalid, must be non-negative", l.Max_Lines_Per_Second)
+		return
+	} else if l.Max_Bytes_Per_Second < 0 {
+		err = fmt.Errorf("Max-Bytes-Per-Second %d is invalid, must be non-negative", l.Max_Bytes_Per_Second)
+		return
+	}
+	if l.Line_Continuation_Regex != `` {
+		if lt != lineReader {
+			err = fmt.Errorf("Line-Continuation-Regex is not compatible with reader type %s", lt)
+			return
+		} else if _, err = regexp.Compile(l.Line_Continuation_Regex); err != nil {
+			err = fmt.Errorf("Line-Continuation-Regex %q is invalid: %w", l.Line_Continuation_Regex, err)
+			return
+		}
+	}
+	if l.Max_Multiline_Bytes < 0 {
+		err = fmt.Errorf("Max-Multiline-Bytes %d is invalid, must be non-negative", l.Max_Multiline_Bytes)
+		return
+	} else if l.Max_Multiline_Bytes > 0 && l.Line_Continuation_Regex == `` {
+		err = errors.New("Max-Multiline-Bytes requires Line-Continuation-Regex")
+		return
+	} else if l.Multiline_Flush_Timeout != `` && l.Line_Continuation_Regex == `` {
+		err = errors.New("Multiline-Flush-Timeout requires Line-Continuation-Regex")
+		return
+	} else if _, err = l.multilineFlush(); err != nil {
+		return
+	}
+	if l.Strip_BOM || l.Trim_CR {
+		switch lt {
+		case lineReader, jsonReader, cefReader, leefReader, rfc5424Reader:
+		default:
+			err = fmt.Errorf("Strip-BOM and Trim-CR are not compatible with reader type %s", lt)
+			return
+		}
+	}
+	switch lt {
+	case lineReader, jsonReader, cefReader, leefReader:
+		if _, _, err = l.maxLineLength(); err != nil {
+			return
+		} else if _, err = l.lineDelimiter(); err != nil {
+			return
+		}
+	default:
+		if l.Max_Line_Length != 0 || l.On_Oversize != `` {
+			err = fmt.Errorf("Max-Line-Length and On-Oversize are not compatible with reader type %s", lt)
+			return
+		} else if l.Line_Delimiter != `` {
+			err = fmt.Errorf("Line-Delimiter is not compatible with reader type %s", lt)
+			return
+		}
+	}
+	if l.Max_Connections < 0 {
+		err = fmt.Errorf("Max-Connections %d is invalid, must be non-negative", l.Max_Connections)
+		return
+	} else if l.Dedup_Window < 0 || l.Dedup_Window > maxDedupWindow {
+		err = fmt.Errorf("Dedup-Window %d is invalid, must be between 0 and %d", l.Dedup_Window, maxDedupWindow)
+		return
+	} else if _, err = l.idleTimeout(); err != nil {
+		return
+	}
+	if _, err = l.gzipCompression(); err != nil {
+		return
+	} else if _, err = l.tagRoutes(); err != nil {
+		return
+	} else if _, err = l.logLevel(); err != nil {
+		return
+	} else if _, err = newRemoteFilter(``, l.Allow_Remote, l.Deny_Remote); err != nil {
+		return
+	}
+	if l.Tag_From_Vendor && !(lt == cefReader || lt == leefReader) {
+		err = fmt.Errorf("Tag-From-Vendor is not compatible with reader type %s", lt)
+		return
+	}
+	if (len(l.Allowed_Vendor_Tags) > 0 || l.Max_Vendor_Tags != 0) && !l.Tag_From_Vendor {
+		err = errors.New("Allowed-Vendor-Tags and Max-Vendor-Tags require Tag-From-Vendor")
+		return
+	} else if l.Max_Vendor_Tags < 0 {
+		err = fmt.Errorf("Max-Vendor-Tags %d is invalid, must be non-negative", l.Max_Vendor_Tags)
+		return
+	}
+	for _, v := range l.Allowed_Vendor_Tags {
+		if err = ingest.CheckTag(v); err != nil {
+			err = fmt.Errorf("Allowed-Vendor-Tags %q is invalid: %w", v, err)
+			return
+		}
+	}
+	if ft, ferr := translateFramingType(l.RFC6587_Framing); ferr != nil {
+		err = ferr
+		return
+	} else if ft != lfFraming && lt != rfc5424Reader {
+		err = fmt.Errorf("RFC6587-Framing is not compatible with reader type %s", lt)
+		return
+	}
+	if l.Source_From_Header && !(lt == rfc5424Reader || lt == rfc6587Reader) {
+		err = fmt.Errorf("Source-From-Header is not compatible with reader type %s", lt)
+		return
+	}
+	if lt == rawReader {
+		if _, err = l.rawFraming(); err != nil {
+			return
+		} else if l.Timestamp_Format_Override != `` {
+			err = fmt.Errorf("Timestamp-Format-Override is not compatible with reader type %s", lt)
+			return
+		}
+	} else if l.Frame_Length != 0 || l.Length_Prefix_Bytes != 0 || l.Length_Prefix_Endian != `` {
+		err = fmt.Errorf("Frame-Length, Length-Prefix-Bytes, and Length-Prefix-Endian are not compatible with reader type %s", lt)
+		return
+	}
+	if lt != jsonReader {
+		if l.Timestamp_Field != `` || l.Timestamp_Format != `` {
+			err = fmt.Errorf("Timestamp-Field and Timestamp-Format are not compatible with reader type %s", lt)
+			return
+		}
+	} else {
+		if l.Timestamp_Field != `` {
+			if _, err = getJsonFields(l.Timestamp_Field); err != nil {
+				err = fmt.Errorf("Timestamp-Field %q is invalid: %w", l.Timestamp_Field, err)
+				return
+			}
+		}
+		if l.Timestamp_Format != `` {
+			if l.Timestamp_Format_Override != `` {
+				err = errors.New("Timestamp-Format and Timestamp-Format-Override cannot both be specified")
+				return
+			} else if err = timegrinder.ValidateFormatOverride(l.Timestamp_Format); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// checkFormatOverride ensures the Timestamp-Format-Override is either a named timegrinder
+// format, a custom TimeFormat, or a Go reference time layout such as "02/01/2006 15:04:05".
+// Layouts are registered as custom time formats so handlers can load them by name.
+func (c *cfgType) checkFormatOverride(l *baseConfig) error {
+	ovr := l.Timestamp_Format_Override
+	if ovr == `` || timegrinder.ValidateFormatOverride(ovr) == nil {
+		return nil
+	} else if _, ok := c.TimeFormat[ovr]; ok {
+		return nil
+	}
+	rx, err := timegrinder.LayoutRegex(ovr)
+	if err != nil {
+		return err
+	}
+	//check that the layout can format and then parse the reference time
+	cf := timegrinder.CustomFormat{
+		Name:   ovr,
+		Regex:  rx,
+		Format: ovr,
+	}
+	if err = cf.Validate(); err != nil {
+		return err
+	}
+	if c.TimeFormat == nil {
+		c.TimeFormat = config.CustomTimeFormat{}
+	}
+	c.TimeFormat[ovr] = &config.TimeFormat{
+		Format: ovr,
+		Regex:  rx,
+	}
+	return nil
+}
+
+// checkListenerBind validates the settings of a listener that depend on the type of a single bind string
+func checkListenerBind(l *listener, lt readerType, bstr string) (err error) {
+	var bt bindType
+	if bt, _, err = translateBindType(bstr); err != nil {
+		return
+	}
+	if bt.UDP() || bt == unixgram {
+		if lt == rfc6587Reader || lt == rawReader {
+			err = fmt.Errorf("%s reader type is not compatible with a %s bind string", lt, bt)
+		} else if l.Line_Continuation_Regex != `` {
+			err = fmt.Errorf("Line-Continuation-Regex is not compatible with a %s bind string", bt)
+		} else if l.Max_Connections > 0 {
+			err = fmt.Errorf("Max-Connections is not compatible with a %s bind string", bt)
+		} else if l.Idle_Timeout != `` {
+			err = fmt.Errorf("Idle-Timeout is not compatible with a %s bind string", bt)
+		} else if ft, _ := translateFramingType(l.RFC6587_Framing); ft != lfFraming {
+			err = fmt.Errorf("RFC6587-Framing is not compatible with a %s bind string", bt)
+		} else if gz, _ := l.gzipCompression(); gz {
+			err = fmt.Errorf("Compression is not compatible with a %s bind string", bt)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if l.Proxy_Protocol && !bt.TCP() {
+		err = fmt.Errorf("Proxy-Protocol is not compatible with a %s bind string", bt)
+		return
+	}
+	if bt.Unix() {
+		if len(l.Allow_Remote) > 0 || len(l.Deny_Remote) > 0 {
+			err = fmt.Errorf("Allow-Remote and Deny-Remote are not compatible with a %s bind string", bt)
+			return
+		}
+		err = checkSocketDir(bstr)
+	}
+	return
+}
+
+// checkBindCollisions ensures that none of the bind strings for a listener are already claimed by another listener
+func checkBindCollisions(bindMp map[string]string, name string, binds []string) error {
+	for _, bstr := range binds {
+		key := bindKey(bstr)
+		if n, ok := bindMp[key]; ok {
+			return fmt.Errorf("Bind-String %q for %s already in use by %s", bstr, name, n)
+		}
+		bindMp[key] = name
+	}
+	return nil
+}
+
+// bindKey normalizes a bind string so that equivalent binds collide,
+// e.g. 0.0.0.0:601 and tcp://0.0.0.0:601
+func bindKey(bstr string) string {
+	bt, addr, err := translateBindType(bstr)
+	if err != nil {
+		return bstr
+	}
+	switch {
+	case bt.TCP() || bt.TLS():
+		return `stream://` + addr
+	case bt.UDP():
+		return `dgram://` + addr
+	}
+	return `unix://` + addr
+}
+
+// socketPermissions returns the file mode for unix sockets, zero means leave the default
+func (l *listener) socketPermissions() (mode os.FileMode, err error) {
+	if l.Unix_Socket_Permissions == `` {
+		return
+	}
+	var v uint64
+	if v, err = strconv.ParseUint(strings.TrimSpace(l.Unix_Socket_Permissions), 8, 32); err != nil || v > 0777 {
+		err = fmt.Errorf("Unix-Socket-Permissions %q is not a valid octal file mode", l.Unix_Socket_Permissions)
+		return
+	}
+	mode = os.FileMode(v)
+	return
+}
+
+// idleTimeout returns the parsed Idle-Timeout, zero means connections are never reaped
+func (l *listener) idleTimeout() (to time.Duration, err error) {
+	if l.Idle_Timeout == `` {
+		return
+	}
+	if to, err = time.ParseDuration(strings.TrimSpace(l.Idle_Timeout)); err != nil {
+		err = fmt.Errorf("Idle-Timeout %q is invalid: %w", l.Idle_Timeout, err)
+	} else if to <= 0 {
+		err = fmt.Errorf("Idle-Timeout %q is invalid, must be positive", l.Idle_Timeout)
+	}
+	return
+}
+
+// multilineFlush returns how long a connection may be idle before its pending multiline entry is emitted
+func (l *listener) multilineFlush() (to time.Duration, err error) {
+	if l.Multiline_Flush_Timeout == `` {
+		return defaultMultilineFlush, nil
+	}
+	if to, err = time.ParseDuration(strings.TrimSpace(l.Multiline_Flush_Timeout)); err != nil {
+		err = fmt.Errorf("Multiline-Flush-Timeout %q is invalid: %w", l.Multiline_Flush_Timeout, err)
+	} else if to <= 0 {
+		err = fmt.Errorf("Multiline-Flush-Timeout %q is invalid, must be positive", l.Multiline_Flush_Timeout)
+	}
+	return
+}
+
+// maxLineLength returns the longest line a line based reader buffers and whether longer
+// lines are dropped rather than truncated
+func (l *listener) maxLineLength() (max int, drop bool, err error) {
+	if l.Max_Line_Length < 0 || l.Max_Line_Length > maxDataSize {
+		err = fmt.Errorf("Max-Line-Length %d is invalid, must be between 1 and %d", l.Max_Line_Length, maxDataSize)
+		return
+	} else if max = l.Max_Line_Length; max == 0 {
+		max = defaultMaxLineLength
+	}
+	switch strings.ToLower(strings.TrimSpace(l.On_Oversize)) {
+	case ``, `truncate`:
+	case `drop`:
+		drop = true
+	default:
+		err = fmt.Errorf("On-Oversize %q is invalid, must be truncate or drop", l.On_Oversize)
+	}
+	return
+}
+
+// lineDelimiter returns the record delimiter of a line based reader, a newline if unset.
+// The backslash escapes \0, \n, \r, \t, \\, and \xHH are supported so that unprintable
+// delimiters can be configured, any other character stands for itself.
+func (l *listener) lineDelimiter() (delim []byte, err error) {
+	if l.Line_Delimiter == `` {
+		return newline, nil
+	}
+	s := l.Line_Delimiter
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			delim = append(delim, s[i])
+			continue
+		} else if i++; i == len(s) {
+			err = fmt.Errorf("Line-Delimiter %q ends with an incomplete escape", l.Line_Delimiter)
+			return
+		}
+		switch s[i] {
+		case '0':
+			delim = append(delim, 0)
+		case 'n':
+			delim = append(delim, '\n')
+		case 'r':
+			delim = append(delim, '\r')
+		case 't':
+			delim = append(delim, '\t')
+		case '\\':
+			delim = append(delim, '\\')
+		case 'x':
+			var v uint64
+			if i+2 >= len(s) {
+				err = fmt.Errorf("Line-Delimiter %q has an incomplete \\x escape", l.Line_Delimiter)
+				return
+			} else if v, err = strconv.ParseUint(s[i+1:i+3], 16, 8); err != nil {
+				err = fmt.Errorf("Line-Delimiter %q has an invalid \\x escape", l.Line_Delimiter)
+				return
+			}
+			delim = append(delim, byte(v))
+			i += 2
+		default:
+			err = fmt.Errorf("Line-Delimiter %q has an unknown escape \\%c", l.Line_Delimiter, s[i])
+			return
+		}
+	}
+	if len(delim) > maxLineDelimiter {
+		err = fmt.Errorf("Line-Delimiter %q is invalid, may be at most %d bytes", l.Line_Delimiter, maxLineDelimiter)
+	}
+	return
+}
+
+// enrichment parses the Enrichment settings into the enumerated values attached to every entry.
+// Each is split on the first '=' so that values may contain an equals sign, names must be unique
+// and may not shadow the reserved names, which are the keys of the global Attach section.
+func (l *listener) enrichment(reserved []string) (evs []entry.EnumeratedValue, err error) {
+	names := make(map[string]bool, len(l.Enrichment))
+	for _, v := range l.Enrichment {
+		idx := strings.IndexByte(v, '=')
+		if idx == -1 {
+			err = fmt.Errorf("Enrichment %q is invalid, must be name=value", v)
+			return
+		}
+		name, val := strings.TrimSpace(v[:idx]), strings.TrimSpace(v[idx+1:])
+		if name == `` || len(name) > entry.MaxEvNameLength {
+			err = fmt.Errorf("Enrichment %q has an invalid name", v)
+			return
+		} else if val == `` {
+			err = fmt.Errorf("Enrichment %q is missing a value", v)
+			return
+		} else if names[strings.ToLower(name)] {
+			err = fmt.Errorf("Enrichment name %q is duplicated", name)
+			return
+		}
+		for _, r := range reserved {
+			if strings.EqualFold(name, r) {
+				err = fmt.Errorf("Enrichment name %q is reserved by the Attach configuration", name)
+				return
+			}
+		}
+		names[strings.ToLower(name)] = true
+		evs = append(evs, entry.EnumeratedValue{Name: name, Value: entry.StringEnumData(val)})
+	}
+	return
+}
+
+// logLevel returns the minimum level of connection events logged for the listener,
+// an empty Log-Level passes every event on to the ingester logger
+func (l *listener) logLevel() (lvl log.Level, err error) {
+	if l.Log_Level == `` {
+		return log.DEBUG, nil
+	}
+	if lvl, err = log.LevelFromString(strings.TrimSpace(l.Log_Level)); err != nil {
+		err = fmt.Errorf("Log-Level %q is invalid: %w", l.Log_Level, err)
+	}
+	return
+}
+
+// rawFraming returns how the raw reader splits a stream into frames, exactly one of
+// Frame-Length and Length-Prefix-Bytes must be set
+func (l *listener) rawFraming() (rf rawFraming, err error) {
+	if l.Frame_Length < 0 || l.Frame_Length > maxDataSize {
+		err = fmt.Errorf("Frame-Length %d is invalid, must be between 1 and %d", l.Frame_Length, maxDataSize)
+		return
+	} else if (l.Frame_Length == 0) == (l.Length_Prefix_Bytes == 0) {
+		err = errors.New("raw reader requires exactly one of Frame-Length or Length-Prefix-Bytes")
+		return
+	}
+	rf.length = l.Frame_Length
+	if l.Length_Prefix_Bytes == 0 {
+		if l.Length_Prefix_Endian != `` {
+			err = errors.New("Length-Prefix-Endian requires Length-Prefix-Bytes")
+		}
+		return
+	}
+	switch l.Length_Prefix_Bytes {
+	case 1, 2, 4, 8:
+		rf.prefix = l.Length_Prefix_Bytes
+	default:
+		err = fmt.Errorf("Length-Prefix-Bytes %d is invalid, must be 1, 2, 4, or 8", l.Length_Prefix_Bytes)
 		return
 	}
+	switch strings.ToLower(strings.TrimSpace(l.Length_Prefix_Endian)) {
+	case ``, `big`:
+		rf.order = binary.BigEndian
+	case `little`:
+		rf.order = binary.LittleEndian
+	default:
+		err = fmt.Errorf("Length-Prefix-Endian %q is invalid, must be big or little", l.Length_Prefix_Endian)
+	}
 	return
 }
 
+// gzipCompression reports whether stream connections must be decompressed with gzip
+func (l *listener) gzipCompression() (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(l.Compression)) {
+	case ``, `none`:
+		return false, nil
+	case `gzip`:
+		return true, nil
+	}
+	return false, fmt.Errorf("Compression %q is invalid, must be none or gzip", l.Compression)
+}
+
+// tagRoute is a single Tag-Router rule
+type tagRoute struct {
+	re  *regexp.Regexp
+	tag string
+}
+
+// tagRoutes parses the Tag-Router rules in order, each rule is split on the last '='
+// so that the regular expression may contain an equals sign
+func (l *listener) tagRoutes() (rts []tagRoute, err error) {
+	for _, v := range l.Tag_Router {
+		idx := strings.LastIndexByte(v, '=')
+		if idx == -1 {
+			err = fmt.Errorf("Tag-Router rule %q is invalid, must be regex=tag", v)
+			return
+		}
+		pattern, tag := strings.TrimSpace(v[:idx]), strings.TrimSpace(v[idx+1:])
+		if pattern == `` {
+			err = fmt.Errorf("Tag-Router rule %q is missing a regular expression", v)
+			return
+		} else if err = ingest.CheckTag(tag); err != nil {
+			err = fmt.Errorf("Tag-Router rule %q has an invalid tag: %w", v, err)
+			return
+		}
+		var re *regexp.Regexp
+		if re, err = regexp.Compile(pattern); err != nil {
+			err = fmt.Errorf("Tag-Router rule %q is invalid: %w", v, err)
+			return
+		}
+		rts = append(rts, tagRoute{re: re, tag: tag})
+	}
+	return
+}
+
+// checkSocketDir ensures that the directory which will hold a unix socket exists and is writable
+func checkSocketDir(bstr string) error {
+	_, pth, err := translateBindType(bstr)
+	if err != nil {
+		return err
+	} else if pth == `` {
+		return errors.New("missing unix socket path")
+	}
+	dir := filepath.Dir(pth)
+	fout, err := os.CreateTemp(dir, `.simplerelay`)
+	if err != nil {
+		return fmt.Errorf("unix socket directory %q is not writable: %w", dir, err)
+	}
+	fout.Close()
+	return os.Remove(fout.Name())
+}
+
 func (l baseConfig) Validate() error {
 	if len(l.Bind_String) == 0 {
 		return errors.New("No Bind-String provided")
 	}
+	var hasTLS bool
+	for _, bstr := range l.Bind_String {
+		bt, _, err := translateBindType(bstr)
+		if err != nil {
+			return err
+		}
+		hasTLS = hasTLS || bt.TLS()
+	}
+	if l.Source_Override != `` && net.ParseIP(l.Source_Override) == nil {
+		return fmt.Errorf("Source-Override %q is not a valid IP", l.Source_Override)
+	}
+	if hasTLS {
+		if l.Cert_File == `` || l.Key_File == `` {
+			return errors.New("TLS listeners require a Cert-File and Key-File")
+		}
+	} else if l.Cert_File != `` || l.Key_File != `` || l.Client_CA_File != `` {
+		return errors.New("Cert-File, Key-File, and Client-CA-File require a TLS Bind-String")
+	}
+	if _, err := l.timestampClamp(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// timestampClamp returns the clamp applied to entry timestamps, nil if Max-Timestamp-Skew is unset
+func (l baseConfig) timestampClamp() (*processors.TimestampClamp, error) {
+	return processors.NewTimestampClamp(l.Max_Timestamp_Skew, l.On_Skew)
+}
+
+// timeConfig returns the timestamp parsing settings of the listener, formats are the global
+// TimeFormat definitions. Skew is handled by the listener writer rather than the timegrinder.
+func (l baseConfig) timeConfig(formats config.CustomTimeFormat) processors.TimeExtractorConfig {
+	return processors.TimeExtractorConfig{
+		Formats:             formats,
+		FormatOverride:      l.Timestamp_Format_Override,
+		TimezoneOverride:    l.Timezone_Override,
+		AssumeLocalTimezone: l.Assume_Local_Timezone,
+		EnableLeftMostSeed:  true,
+	}
+}
+
+// checkNoUnix rejects unix socket bind strings for listener types that do not support them
+func (l baseConfig) checkNoUnix() error {
+	for _, bstr := range l.Bind_String {
+		if bt, _, err := translateBindType(bstr); err != nil {
+			return err
+		} else if bt.Unix() {
+			return fmt.Errorf("%s bind strings are only supported on Listener blocks", bt)
+		}
+	}
 	return nil
 }
 
+// tlsConfig builds a TLS configuration from the certificate, key, and optional client CA bundle
+func (l baseConfig) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(l.Cert_File, l.Key_File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if l.Client_CA_File != `` {
+		bts, err := os.ReadFile(l.Client_CA_File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Client-CA-File: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bts) {
+			return nil, fmt.Errorf("no valid certificates found in Client-CA-File %q", l.Client_CA_File)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
 func translateBindType(bstr string) (bindType, string, error) {
 	bits := strings.SplitN(bstr, "://", 2)
 	//if nothing specified, just return the tcp type
@@ -320,6 +981,10 @@ func translateBindType(bstr string) (bindType, string, error) {
 		return udp6, bits[1], nil
 	case "tls":
 		return TLS, bits[1], nil
+	case "unix":
+		return unix, bits[1], nil
+	case "unixgram":
+		return unixgram, bits[1], nil
 	default:
 	}
 	return -1, "", errors.New("invalid bind protocol specifier of " + id)
@@ -343,6 +1008,10 @@ func (bt bindType) TLS() bool {
 	return bt == TLS
 }
 
+func (bt bindType) Unix() bool {
+	return bt == unix || bt == unixgram
+}
+
 func (bt bindType) String() string {
 	switch bt {
 	case tcp:
@@ -355,6 +1024,10 @@ func (bt bindType) String() string {
 		return "udp6"
 	case TLS:
 		return "tls"
+	case unix:
+		return "unix"
+	case unixgram:
+		return "unixgram"
 	}
 	return "unknown"
 }
@@ -368,12 +1041,45 @@ func translateReaderType(s string) (readerType, error) {
 		return rfc5424Reader, nil
 	case `rfc6587`:
 		return rfc6587Reader, nil
+	case `json`:
+		return jsonReader, nil
+	case `cef`:
+		return cefReader, nil
+	case `leef`:
+		return leefReader, nil
+	case `raw`:
+		return rawReader, nil
 	case ``:
 		return lineReader, nil
 	}
 	return -1, errors.New("invalid reader type")
 }
 
+func translateFramingType(s string) (framingType, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case `lf`, ``:
+		return lfFraming, nil
+	case `octet`:
+		return octetFraming, nil
+	case `auto`:
+		return autoFraming, nil
+	}
+	return -1, fmt.Errorf("invalid RFC6587-Framing %q, must be lf, octet, or auto", s)
+}
+
+func (ft framingType) String() string {
+	switch ft {
+	case lfFraming:
+		return `lf`
+	case octetFraming:
+		return `octet`
+	case autoFraming:
+		return `auto`
+	}
+	return "unknown"
+}
+
 func (rt readerType) String() string {
 	switch rt {
 	case lineReader:
@@ -382,6 +1088,14 @@ func (rt readerType) String() string {
 		return `RFC5424`
 	case rfc6587Reader:
 		return `RFC6587`
+	case jsonReader:
+		return `JSON`
+	case cefReader:
+		return `CEF`
+	case leefReader:
+		return `LEEF`
+	case rawReader:
+		return `RAW`
 	}
 	return "UNKNOWN"
 }
@@ -9,11 +9,17 @@
 package main
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
 var (
@@ -65,12 +71,229 @@ func TestBasicConfig(t *testing.T) {
 	}
 }
 
+func TestMultiBindConfig(t *testing.T) {
+	cfgPath, err := dropConfig(multiBindConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := GetConfig(cfgPath, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, ok := cfg.Listener[`syslog`]
+	if !ok {
+		t.Fatal("missing syslog listener")
+	} else if len(l.Bind_String) != 3 {
+		t.Fatalf("invalid bind count: %d != 3", len(l.Bind_String))
+	}
+}
+
+func TestLayoutFormatOverride(t *testing.T) {
+	cfgPath, err := dropConfig(layoutOverrideConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := GetConfig(cfgPath, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := cfg.Listener[`ddmm`]
+	tg, err := timegrinder.NewTimeGrinder(timegrinder.Config{})
+	if err != nil {
+		t.Fatal(err)
+	} else if err = cfg.TimeFormat.LoadFormats(tg); err != nil {
+		t.Fatal(err)
+	} else if err = tg.SetFormatOverride(l.Timestamp_Format_Override); err != nil {
+		t.Fatal(err)
+	} else if err = tg.SetTimezone(l.Timezone_Override); err != nil {
+		t.Fatal(err)
+	}
+	ts, ok, err := tg.Extract([]byte(`04/03/2021 10:00:00 login failed`))
+	if err != nil || !ok {
+		t.Fatalf("failed to extract timestamp: %v %v", ok, err)
+	}
+	loc, err := time.LoadLocation(`America/Denver`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2021, 3, 4, 10, 0, 0, 0, loc); !ts.Equal(exp) {
+		t.Fatalf("bad timestamp: %v != %v", ts, exp)
+	}
+}
+
+func TestTagRouterConfig(t *testing.T) {
+	cfgPath, err := dropConfig(tagRouterConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := GetConfig(cfgPath, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := cfg.Tags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{`auth`, `kernel`, `syslog`}; len(tags) != len(exp) {
+		t.Fatalf("bad tags: %v != %v", tags, exp)
+	} else {
+		for i := range exp {
+			if tags[i] != exp[i] {
+				t.Fatalf("bad tags: %v != %v", tags, exp)
+			}
+		}
+	}
+	rts, err := cfg.Listener[`syslog`].tagRoutes()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rts) != 3 || rts[0].tag != `auth` || rts[2].re.String() != `a=b` {
+		t.Fatalf("bad tag routes: %+v", rts)
+	}
+}
+
+func TestRawFraming(t *testing.T) {
+	good := []struct {
+		l  listener
+		rf rawFraming
+	}{
+		{listener{Frame_Length: 64}, rawFraming{length: 64}},
+		{listener{Length_Prefix_Bytes: 2}, rawFraming{prefix: 2, order: binary.BigEndian}},
+		{listener{Length_Prefix_Bytes: 4, Length_Prefix_Endian: `Little`}, rawFraming{prefix: 4, order: binary.LittleEndian}},
+	}
+	for _, v := range good {
+		if rf, err := v.l.rawFraming(); err != nil {
+			t.Fatalf("%+v: %v", v.l, err)
+		} else if rf != v.rf {
+			t.Fatalf("%+v: bad framing %+v != %+v", v.l, rf, v.rf)
+		}
+	}
+	bad := []listener{
+		{},
+		{Frame_Length: -1},
+		{Frame_Length: maxDataSize + 1},
+		{Frame_Length: 64, Length_Prefix_Bytes: 4},
+		{Frame_Length: 64, Length_Prefix_Endian: `little`},
+		{Length_Prefix_Bytes: 3},
+		{Length_Prefix_Bytes: 4, Length_Prefix_Endian: `middle`},
+	}
+	for _, v := range bad {
+		if _, err := v.rawFraming(); err == nil {
+			t.Fatalf("failed to catch bad framing %+v", v)
+		}
+	}
+}
+
+func TestEnrichment(t *testing.T) {
+	l := listener{Enrichment: []string{`datacenter=us-east-1`, ` collector = relay07 `, `query=a=b`}}
+	evs, err := l.enrichment([]string{`host`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]string{`datacenter`: `us-east-1`, `collector`: `relay07`, `query`: `a=b`}
+	if len(evs) != len(exp) {
+		t.Fatalf("bad enrichment: %+v", evs)
+	}
+	for _, ev := range evs {
+		if v, ok := exp[ev.Name]; !ok || ev.Value.String() != v {
+			t.Fatalf("bad enrichment value %v", ev)
+		}
+	}
+
+	bad := [][]string{
+		{`datacenter`},
+		{`=us-east-1`},
+		{`datacenter=`},
+		{`datacenter=a`, `Datacenter=b`},
+		{`HOST=relay07`},
+		{strings.Repeat(`x`, entry.MaxEvNameLength+1) + `=a`},
+	}
+	for _, v := range bad {
+		l = listener{Enrichment: v}
+		if _, err = l.enrichment([]string{`host`}); err == nil {
+			t.Fatalf("failed to catch bad enrichment %q", v)
+		}
+	}
+}
+
+func TestReaderOptions(t *testing.T) {
+	cfgPath, err := dropConfig(readerOptionsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := GetConfig(cfgPath, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := cfg.Listener[`syslog`]
+	if ft, _ := translateFramingType(l.RFC6587_Framing); ft != autoFraming || !l.Drop_Priority || !l.Strip_BOM {
+		t.Fatalf("reader options not applied: %+v", l)
+	} else if dep := l.deprecatedOptions(cfg.Reader_Options[`syslog`]); len(dep) != 1 || dep[0] != `Trim-CR` {
+		t.Fatalf("bad deprecated settings: %v", dep)
+	}
+	//verifying again must not see the applied options as conflicts
+	if err = cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if l = cfg.Listener[`json`]; l.Timestamp_Field != `event.created` || l.Max_Line_Length != 1024 {
+		t.Fatalf("reader options not applied: %+v", l)
+	} else if dep := l.deprecatedOptions(cfg.Reader_Options[`json`]); len(dep) != 0 {
+		t.Fatalf("bad deprecated settings: %v", dep)
+	}
+}
+
 func TestBadConfig(t *testing.T) {
 	cfgs := []string{
 		badConfigNoListener,
 		badConfigWrongListener,
 		badConfigDropPriority,
 		badConfigReaderBind,
+		badConfigTLSNoCert,
+		badConfigTimestampField,
+		badConfigSourceOverride,
+		badConfigUnixgramRFC6587,
+		badConfigUnixPermissions,
+		badConfigUnixJSON,
+		badConfigRateLimit,
+		badConfigMultilineReader,
+		badConfigMultilineFlush,
+		badConfigMultilineFlushValue,
+		badConfigTagFromVendor,
+		badConfigVendorTagsNoVendor,
+		badConfigVendorTagsInvalid,
+		badConfigIdleTimeout,
+		badConfigBindCollision,
+		badConfigFormatOverride,
+		badConfigFramingReader,
+		badConfigFramingUDP,
+		badConfigFramingValue,
+		badConfigCompressionUDP,
+		badConfigCompressionValue,
+		badConfigTagRouterTag,
+		badConfigTagRouterRegex,
+		badConfigAllowRemote,
+		badConfigRemoteUnix,
+		badConfigDatagramTCP,
+		badConfigLogLevel,
+		badConfigMetricsBind,
+		badConfigRawUDP,
+		badConfigRawFraming,
+		badConfigFrameLengthReader,
+		badConfigTimestampSkew,
+		badConfigSkewPolicy,
+		badConfigProxyProtocolTLS,
+		badConfigMaxLineLength,
+		badConfigOversizeReader,
+		badConfigOversizePolicy,
+		badConfigEnrichment,
+		badConfigEnrichmentReserved,
+		badConfigDedupWindow,
+		badConfigDelimiterReader,
+		badConfigDelimiterEscape,
+		badConfigStripBOMReader,
+		badConfigReaderOptionsReader,
+		badConfigReaderOptionsListener,
+		badConfigReaderOptionsConflict,
+		badConfigReaderOptionsValue,
 	}
 
 	for _, v := range cfgs {
@@ -224,4 +447,675 @@ Log-File=/tmp/simple_relay.log
 	Drop-Priority=true
 	Reader-Type=rfc6587
 `
+
+	badConfigTLSNoCert string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "tlssyslog"]
+	Bind-String="tls://0.0.0.0:6514"
+	Reader-Type=rfc5424
+	Key-File=/tmp/key.pem
+`
+
+	badConfigTimestampField string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "events"]
+	Bind-String="0.0.0.0:7777"
+	Reader-Type=line
+	Timestamp-Field=event.created
+`
+
+	badConfigSourceOverride string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Source-Override="not an ip"
+`
+
+	badConfigUnixgramRFC6587 string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="unixgram:///tmp/simple_relay.sock"
+	Reader-Type=rfc6587
+`
+
+	badConfigUnixPermissions string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="unix:///tmp/simple_relay.sock"
+	Unix-Socket-Permissions=0999
+`
+
+	badConfigUnixJSON string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[JSONListener "json"]
+	Bind-String="unix:///tmp/simple_relay.sock"
+	Extractor="field1"
+	Default-Tag=json
+	Tag-Match=XXX:Xtag
+`
+
+	badConfigRateLimit string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Max-Lines-Per-Second=-1
+`
+
+	badConfigMultilineReader string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Reader-Type=rfc5424
+	Line-Continuation-Regex="^\\s"
+`
+
+	badConfigMultilineFlush string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Multiline-Flush-Timeout=5s
+`
+
+	badConfigMultilineFlushValue string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Line-Continuation-Regex="^\\s"
+	Multiline-Flush-Timeout=-1s
+`
+
+	badConfigTagFromVendor string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Reader-Type=line
+	Tag-From-Vendor=true
+`
+
+	badConfigVendorTagsNoVendor string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Reader-Type=cef
+	Max-Vendor-Tags=10
+`
+
+	badConfigVendorTagsInvalid string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Reader-Type=cef
+	Tag-From-Vendor=true
+	Allowed-Vendor-Tags="bad tag"
+`
+
+	badConfigIdleTimeout string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Max-Connections=10
+	Idle-Timeout="ten minutes"
+`
+
+	multiBindConfig string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "syslog"]
+	Bind-String="10.0.0.1:601"
+	Bind-String="192.168.1.1:601"
+	Bind-String="udp://192.168.1.1:514"
+	Reader-Type=rfc5424
+	Tag-Name=syslog
+`
+
+	badConfigBindCollision string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "syslog"]
+	Bind-String="10.0.0.1:601"
+	Bind-String="192.168.1.1:601"
+	Reader-Type=rfc5424
+
+[Listener "other"]
+	Bind-String="tcp://192.168.1.1:601"
+`
+
+	layoutOverrideConfig string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "ddmm"]
+	Bind-String="0.0.0.0:7777"
+	Timestamp-Format-Override="02/01/2006 15:04:05"
+	Timezone-Override="America/Denver"
+`
+
+	badConfigFormatOverride string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Timestamp-Format-Override="not a format"
+`
+
+	badConfigFramingReader string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Reader-Type=rfc6587
+	RFC6587-Framing=octet
+`
+
+	badConfigFramingUDP string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="udp://0.0.0.0:7777"
+	Reader-Type=rfc5424
+	RFC6587-Framing=auto
+`
+
+	badConfigFramingValue string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Reader-Type=rfc5424
+	RFC6587-Framing=length
+`
+
+	badConfigCompressionUDP string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="udp://0.0.0.0:7777"
+	Compression=gzip
+`
+
+	badConfigCompressionValue string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Compression=zstd
+`
+	tagRouterConfig string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "syslog"]
+	Bind-String="udp://0.0.0.0:514"
+	Reader-Type=rfc5424
+	Tag-Name=syslog
+	Tag-Router="sshd|sudo=auth"
+	Tag-Router="kernel: = kernel"
+	Tag-Router="a=b=auth"
+`
+
+	badConfigTagRouterTag string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Tag-Router="sshd=bad tag"
+`
+
+	badConfigTagRouterRegex string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="0.0.0.0:7777"
+	Tag-Router="(sshd=auth"
+`
+	badConfigAllowRemote string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="udp://0.0.0.0:7777"
+	Allow-Remote=10.0.0.0/8
+	Allow-Remote=10.0.0.300/32
+`
+
+	badConfigRemoteUnix string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="unix:///tmp/simplerelay.sock"
+	Deny-Remote=10.0.0.0/8
+`
+	badConfigRawUDP string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="udp://0.0.0.0:7777"
+	Reader-Type=raw
+	Frame-Length=64
+`
+	badConfigRawFraming string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=raw
+	Frame-Length=64
+	Length-Prefix-Bytes=4
+`
+	badConfigFrameLengthReader string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=line
+	Frame-Length=64
+`
+	badConfigDatagramTCP string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	UDP-Datagram-Per-Entry=true
+`
+	badConfigLogLevel string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Log-Level=chatty
+`
+
+	badConfigMetricsBind string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+Metrics-Bind=9100
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+`
+
+	badConfigTimestampSkew string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Max-Timestamp-Skew=-24h
+`
+
+	badConfigSkewPolicy string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[JSONListener "json"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Max-Timestamp-Skew=24h
+	On-Skew=ignore
+`
+
+	badConfigProxyProtocolTLS string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tls://0.0.0.0:7777"
+	Cert-File=/tmp/cert.pem
+	Key-File=/tmp/key.pem
+	Proxy-Protocol=true
+`
+
+	badConfigMaxLineLength string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Max-Line-Length=-1
+`
+
+	badConfigOversizeReader string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=rfc5424
+	Max-Line-Length=65536
+`
+
+	badConfigOversizePolicy string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=json
+	On-Oversize=split
+`
+
+	badConfigEnrichment string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Enrichment="datacenter"
+`
+
+	badConfigEnrichmentReserved string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Attach]
+	collector=$HOSTNAME
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Enrichment="collector=relay07"
+`
+
+	badConfigDelimiterReader string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=rfc5424
+	Line-Delimiter="\\0"
+`
+
+	badConfigDelimiterEscape string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Line-Delimiter="\\q"
+`
+
+	badConfigStripBOMReader string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=rfc6587
+	Strip-BOM=true
+`
+
+	badConfigDedupWindow string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="udp://0.0.0.0:7777"
+	Dedup-Window=-1
+`
+
+	readerOptionsConfig string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "syslog"]
+	Bind-String="tcp://0.0.0.0:601"
+	Reader-Type=rfc5424
+	Drop-Priority=true
+	Trim-CR=true
+
+[Reader-Options "syslog"]
+	RFC6587-Framing=auto
+	Drop-Priority=true
+	Strip-BOM=true
+
+[Listener "json"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=json
+
+[Reader-Options "json"]
+	Timestamp-Field=event.created
+	Max-Line-Length=1024
+`
+
+	badConfigReaderOptionsReader string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+
+[Reader-Options "relay"]
+	RFC6587-Framing=auto
+`
+
+	badConfigReaderOptionsListener string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+
+[Reader-Options "other"]
+	Strip-BOM=true
+`
+
+	badConfigReaderOptionsConflict string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Max-Line-Length=1024
+
+[Reader-Options "relay"]
+	Max-Line-Length=2048
+`
+
+	badConfigReaderOptionsValue string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+	Reader-Type=raw
+
+[Reader-Options "relay"]
+	Length-Prefix-Bytes=3
+`
 )
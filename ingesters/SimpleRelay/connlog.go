@@ -0,0 +1,128 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/crewjam/rfc5424"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+const (
+	// offending lines are truncated to this many bytes when logged
+	maxLoggedLine = 256
+
+	// waits longer than this on the rate limiter are counted as throttling
+	throttleThreshold = time.Millisecond
+)
+
+// logEvent emits a structured event carrying the listener and reader type, events below
+// the Log-Level of the listener are dropped before they reach the ingester logger
+func (hc handlerConfig) logEvent(lvl log.Level, msg string, sds ...rfc5424.SDParam) {
+	if hc.logLevel == log.OFF || lvl < hc.logLevel {
+		return
+	}
+	sds = append([]rfc5424.SDParam{log.KV("listener", hc.name), log.KV("reader_type", hc.lrt)}, sds...)
+	switch lvl {
+	case log.DEBUG:
+		lg.DebugWithDepth(log.DEFAULT_DEPTH+1, msg, sds...)
+	case log.INFO:
+		lg.InfoWithDepth(log.DEFAULT_DEPTH+1, msg, sds...)
+	case log.WARN:
+		lg.WarnWithDepth(log.DEFAULT_DEPTH+1, msg, sds...)
+	default:
+		lg.ErrorWithDepth(log.DEFAULT_DEPTH+1, msg, sds...)
+	}
+}
+
+// connMeter rate limits and counts the entries of a single connection or datagram socket.
+// It is only used by the goroutine that owns the connection.
+type connMeter struct {
+	hc        handlerConfig
+	lim       *rateLimiter
+	addr      net.Addr
+	bytes     uint64
+	lines     uint64
+	throttled uint64
+	invalid   uint64
+}
+
+// meter returns a new connMeter for a connection, datagram handlers update addr per packet
+func (hc handlerConfig) meter(addr net.Addr) *connMeter {
+	return &connMeter{
+		hc:   hc,
+		lim:  hc.limiter(),
+		addr: addr,
+	}
+}
+
+// wait blocks until an entry of sz bytes is allowed through the rate limiter and counts it
+func (cm *connMeter) wait(ctx context.Context, sz int) (err error) {
+	if cm.lim != nil {
+		start := time.Now()
+		if err = cm.lim.wait(ctx, sz); err != nil {
+			return
+		}
+		if time.Since(start) > throttleThreshold {
+			cm.hc.stats.rateLimited()
+			if cm.throttled++; cm.throttled == 1 || cm.throttled%logSampleInterval == 0 {
+				cm.hc.logEvent(log.INFO, "rate limit reached", log.KV("remote_addr", cm.addr),
+					log.KV("max_lines_per_second", cm.hc.maxLPS), log.KV("max_bytes_per_second", cm.hc.maxBPS), log.KV("throttled", cm.throttled))
+			}
+		}
+	}
+	cm.bytes += uint64(sz)
+	cm.lines++
+	return
+}
+
+// checkHeader logs syslog records that do not start with a <PRI> header along with
+// the offending line so that the sender can be fixed, the record is still ingested
+func (cm *connMeter) checkHeader(b []byte) {
+	if len(b) == 0 || validPriority(b) {
+		return
+	}
+	cm.hc.stats.parseError()
+	if cm.invalid++; cm.invalid == 1 || cm.invalid%logSampleInterval == 0 {
+		cm.hc.logEvent(log.WARN, "invalid syslog header", log.KV("remote_addr", cm.addr),
+			log.KV("line", truncateLine(b)), log.KV("invalid", cm.invalid))
+	}
+}
+
+// closed logs the totals for a connection that is going away
+func (cm *connMeter) closed() {
+	cm.hc.logEvent(log.INFO, "connection closed", log.KV("remote_addr", cm.addr), log.KV("bytes", cm.bytes),
+		log.KV("lines", cm.lines), log.KV("throttled", cm.throttled), log.KV("invalid", cm.invalid))
+}
+
+// validPriority reports whether b starts with a syslog <PRI> header
+func validPriority(b []byte) bool {
+	if len(b) < 3 || b[0] != '<' {
+		return false
+	}
+	for i := 1; i < len(b) && i <= 4; i++ {
+		if b[i] == '>' {
+			return i > 1
+		} else if b[i] < '0' || b[i] > '9' {
+			return false
+		}
+	}
+	return false
+}
+
+// truncateLine shortens a line for logging
+func truncateLine(b []byte) string {
+	if len(b) > maxLoggedLine {
+		return string(b[:maxLoggedLine]) + `...`
+	}
+	return string(b)
+}
@@ -0,0 +1,78 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"container/list"
+	"encoding/binary"
+	"hash/fnv"
+	"sync"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const maxDedupWindow = 1024 * 1024
+
+// dedupCache remembers the most recent entries of a listener so that exact duplicates,
+// such as the tail of a buffer replayed by a reconnecting relay, can be dropped.
+// Entries are identified by a hash of their timestamp, source, and data, and the cache
+// holds at most size hashes, evicting the least recently seen. A nil dedupCache never
+// reports a duplicate.
+type dedupCache struct {
+	sync.Mutex
+	size  int
+	order *list.List // of uint64 hashes, most recently seen at the front
+	seen  map[uint64]*list.Element
+}
+
+func newDedupCache(size int) *dedupCache {
+	if size <= 0 {
+		return nil
+	}
+	return &dedupCache{
+		size:  size,
+		order: list.New(),
+		seen:  make(map[uint64]*list.Element, size),
+	}
+}
+
+// duplicate returns true if the entry was seen within the window, either way the
+// entry becomes the most recently seen.
+func (dc *dedupCache) duplicate(ent *entry.Entry) bool {
+	if dc == nil || ent == nil {
+		return false
+	}
+	h := dedupHash(ent)
+	dc.Lock()
+	defer dc.Unlock()
+	if el, ok := dc.seen[h]; ok {
+		dc.order.MoveToFront(el)
+		return true
+	}
+	if dc.order.Len() >= dc.size {
+		if el := dc.order.Back(); el != nil {
+			delete(dc.seen, dc.order.Remove(el).(uint64))
+		}
+	}
+	dc.seen[h] = dc.order.PushFront(h)
+	return false
+}
+
+// dedupHash identifies an entry by its timestamp, source, and data
+func dedupHash(ent *entry.Entry) uint64 {
+	var ts [12]byte
+	binary.LittleEndian.PutUint64(ts[:8], uint64(ent.TS.Sec))
+	binary.LittleEndian.PutUint32(ts[8:], uint32(ent.TS.Nsec))
+	h := fnv.New64a()
+	h.Write(ts[:])
+	h.Write(ent.SRC)
+	h.Write([]byte{0}) // separate the source from the data
+	h.Write(ent.Data)
+	return h.Sum64()
+}
@@ -22,7 +22,6 @@ import (
 	"sync"
 
 	"github.com/gravwell/gravwell/v3/ingest"
-	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
@@ -37,15 +36,12 @@ type jsonHandlerConfig struct {
 	defTag           entry.EntryTag
 	tags             map[string]entry.EntryTag
 	ignoreTimestamps bool
-	setLocalTime     bool
-	timezoneOverride string
 	src              net.IP
 	wg               *sync.WaitGroup
-	formatOverride   string
 	flds             []string
 	proc             *processors.ProcessorSet
 	ctx              context.Context
-	timeFormats      config.CustomTimeFormat
+	timeCfg          processors.TimeExtractorConfig
 	maxObjectSize    int64
 	disableCompact   bool
 }
@@ -66,15 +62,17 @@ func startJSONListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGro
 			wg:               wg,
 			tags:             map[string]entry.EntryTag{},
 			ignoreTimestamps: v.Ignore_Timestamps,
-			setLocalTime:     v.Assume_Local_Timezone,
-			timezoneOverride: v.Timezone_Override,
 			ctx:              ctx,
-			formatOverride:   v.Timestamp_Format_Override,
-			timeFormats:      cfg.TimeFormat,
+			timeCfg:          v.timeConfig(cfg.TimeFormat),
 			maxObjectSize:    int64(v.Max_Object_Size),
 			disableCompact:   v.Disable_Compact,
 		}
-		if jhc.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
+		stats := relayStats.listener(k)
+		mw := meteredWriter{IngestMuxer: igst, stats: stats}
+		if mw.skew, err = v.timestampClamp(); err != nil {
+			return err
+		}
+		if jhc.proc, err = cfg.Preprocessor.ProcessorSet(mw, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor error", log.KVErr(err))
 		}
 		f.Add(jhc.proc)
@@ -111,63 +109,59 @@ func startJSONListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGro
 			jhc.tags[tm.Value] = tg
 		}
 
-		tp, str, err := translateBindType(v.Bind_String)
-		if err != nil {
-			lg.FatalCode(0, "invalid bind", log.KV("bindstring", v.Bind_String), log.KVErr(err))
-		}
-
-		if tp.TCP() {
-			//get the socket
-			addr, err := net.ResolveTCPAddr("tcp", str)
+		for _, bstr := range v.Bind_String {
+			tp, str, err := translateBindType(bstr)
 			if err != nil {
-				return fmt.Errorf("%s Bind-String \"%s\" is invalid: %v\n", k, v.Bind_String, err)
-			}
-			l, err := net.ListenTCP("tcp", addr)
-			if err != nil {
-				return fmt.Errorf("%s Failed to listen on \"%s\": %v\n", k, addr, err)
-			}
-			connID := addConn(l)
-			//start the acceptor
-			wg.Add(1)
-			go jsonAcceptor(l, connID, igst, jhc, tp)
-		} else if tp.TLS() {
-			config := &tls.Config{
-				MinVersion: tls.VersionTLS12,
+				lg.FatalCode(0, "invalid bind", log.KV("bindstring", bstr), log.KVErr(err))
 			}
+			if tp.TCP() {
+				//get the socket
+				addr, err := net.ResolveTCPAddr("tcp", str)
+				if err != nil {
+					return fmt.Errorf("%s Bind-String \"%s\" is invalid: %v\n", k, bstr, err)
+				}
+				l, err := net.ListenTCP("tcp", addr)
+				if err != nil {
+					return fmt.Errorf("%s Failed to listen on \"%s\": %v\n", k, addr, err)
+				}
+				connID := addConn(l)
+				//start the acceptor
+				wg.Add(1)
+				go jsonAcceptor(l, connID, igst, jhc, tp)
+			} else if tp.TLS() {
+				config, err := v.tlsConfig()
+				if err != nil {
+					lg.FatalCode(0, "failed to load TLS configuration", log.KV("certfile", v.Cert_File), log.KV("keyfile", v.Key_File), log.KV("clientcafile", v.Client_CA_File), log.KV("jsonlistener", k), log.KVErr(err))
+				}
+				//get the socket
+				addr, err := net.ResolveTCPAddr("tcp", str)
+				if err != nil {
+					lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", bstr), log.KV("jsonlistener", k), log.KVErr(err))
+				}
+				l, err := tls.Listen("tcp", addr.String(), config)
+				if err != nil {
+					lg.FatalCode(0, "failed to listen via TLS", log.KV("address", addr), log.KV("jsonlistener", k), log.KVErr(err))
+				}
+				connID := addConn(l)
+				//start the acceptor
+				wg.Add(1)
+				go jsonAcceptor(l, connID, igst, jhc, tp)
+			} else if tp.UDP() {
+				addr, err := net.ResolveUDPAddr(tp.String(), str)
+				if err != nil {
+					lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", bstr), log.KV("listener", k), log.KVErr(err))
+				}
+				l, err := net.ListenUDP(tp.String(), addr)
+				if err != nil {
+					lg.FatalCode(0, "failed to listen via udp", log.KV("address", addr), log.KV("listener", k), log.KVErr(err))
+				}
+				connID := addConn(l)
+				wg.Add(1)
+				go jsonAcceptorUDP(l, connID, igst, jhc)
 
-			config.Certificates = make([]tls.Certificate, 1)
-			config.Certificates[0], err = tls.LoadX509KeyPair(v.Cert_File, v.Key_File)
-			if err != nil {
-				lg.Fatal("failed to load certificate", log.KV("certfile", v.Cert_File), log.KV("keyfile", v.Key_File), log.KVErr(err))
-			}
-			//get the socket
-			addr, err := net.ResolveTCPAddr("tcp", str)
-			if err != nil {
-				lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", v.Bind_String), log.KV("jsonlistener", k), log.KVErr(err))
-			}
-			l, err := tls.Listen("tcp", addr.String(), config)
-			if err != nil {
-				lg.FatalCode(0, "failed to listen via TLS", log.KV("address", addr), log.KV("jsonlistener", k), log.KVErr(err))
-			}
-			connID := addConn(l)
-			//start the acceptor
-			wg.Add(1)
-			go jsonAcceptor(l, connID, igst, jhc, tp)
-		} else if tp.UDP() {
-			addr, err := net.ResolveUDPAddr(tp.String(), str)
-			if err != nil {
-				lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", v.Bind_String), log.KV("listener", k), log.KVErr(err))
 			}
-			l, err := net.ListenUDP(tp.String(), addr)
-			if err != nil {
-				lg.FatalCode(0, "failed to listen via udp", log.KV("address", addr), log.KV("listener", k), log.KVErr(err))
-			}
-			connID := addConn(l)
-			wg.Add(1)
-			go jsonAcceptorUDP(l, connID, igst, jhc)
-
 		}
-
+		stats.setBound(true)
 	}
 	debugout("Started %d json listeners\n", len(cfg.JSONListener))
 	return nil
@@ -206,32 +200,10 @@ func jsonAcceptorUDP(conn *net.UDPConn, id int, igst *ingest.IngestMuxer, cfg js
 	defer conn.Close()
 
 	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
-	tcfg := timegrinder.Config{
-		EnableLeftMostSeed: true,
-	}
-	tg, err := timegrinder.NewTimeGrinder(tcfg)
+	tg, err := newTimegrinder(cfg.timeCfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get a handle on the timegrinder: %v\n", err)
+		lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
 		return
-	} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load custom time formats: %v\n", err)
-		return
-	}
-	if cfg.setLocalTime {
-		tg.SetLocalTime()
-	}
-	if cfg.timezoneOverride != `` {
-		err = tg.SetTimezone(cfg.timezoneOverride)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to set timezone to %v: %v\n", cfg.timezoneOverride, err)
-			return
-		}
-	}
-	if cfg.formatOverride != `` {
-		if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-			lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-			return
-		}
 	}
 	ll := log.NewLoggerWithKV(lg, log.KV("json-listener", cfg.name))
 	for {
@@ -266,6 +238,9 @@ func jsonConnHandler(c net.Conn, cfg jsonHandlerConfig, igst *ingest.IngestMuxer
 	defer cfg.wg.Done()
 	defer delConn(id)
 	defer c.Close()
+	if !tlsHandshake(c, cfg.name, nil) {
+		return
+	}
 	var rip net.IP
 	var lip net.IP // just used for logging
 	var tg *timegrinder.TimeGrinder
@@ -288,31 +263,9 @@ func jsonConnHandler(c net.Conn, cfg jsonHandlerConfig, igst *ingest.IngestMuxer
 
 	if !cfg.ignoreTimestamps {
 		var err error
-		tcfg := timegrinder.Config{
-			EnableLeftMostSeed: true,
-		}
-		tg, err = timegrinder.NewTimeGrinder(tcfg)
-		if err != nil {
-			ll.Error("failed to get a handle on the timegrinder", log.KVErr(err))
+		if tg, err = newTimegrinder(cfg.timeCfg); err != nil {
+			ll.Error("failed to load the timegrinder", log.KVErr(err))
 			return
-		} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-			ll.Error("failed to load custom time formats", log.KVErr(err))
-			return
-		}
-		if cfg.setLocalTime {
-			tg.SetLocalTime()
-		}
-		if cfg.timezoneOverride != `` {
-			if err = tg.SetTimezone(cfg.timezoneOverride); err != nil {
-				ll.Error("failed to set timezone", log.KV("timezone", cfg.timezoneOverride), log.KVErr(err))
-				return
-			}
-		}
-		if cfg.formatOverride != `` {
-			if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-				ll.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-				return
-			}
 		}
 	}
 
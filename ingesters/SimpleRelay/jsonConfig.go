@@ -44,6 +44,8 @@ type jsonListener struct {
 func (jl *jsonListener) Validate() error {
 	if err := jl.baseConfig.Validate(); err != nil {
 		return err
+	} else if err = jl.baseConfig.checkNoUnix(); err != nil {
+		return err
 	}
 	jl.initDefaultTag() //make sure we resolve the Tag-Name and Default-Tag configs to do the right thing
 	//process the default tag
@@ -15,6 +15,9 @@ import (
 	"io"
 	"net"
 	"os"
+	"regexp"
+	"sync"
+	"time"
 
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/timegrinder"
@@ -26,6 +29,9 @@ func lineConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 	defer cfg.wg.Done()
 	defer delConn(id)
 	defer c.Close()
+	if !tlsHandshake(c, cfg.name, cfg.stats) {
+		return
+	}
 	var rip net.IP
 
 	if cfg.src == nil {
@@ -45,43 +51,79 @@ func lineConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 	var tg *timegrinder.TimeGrinder
 	if !cfg.ignoreTimestamps {
 		var err error
-		tcfg := timegrinder.Config{
-			EnableLeftMostSeed: true,
-		}
-		tg, err = timegrinder.NewTimeGrinder(tcfg)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get a handle on the timegrinder: %v\n", err)
-			return
-		} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to load custom time formats: %v\n", err)
+		if tg, err = newTimegrinder(cfg.timeCfg); err != nil {
+			lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
 			return
 		}
-		if cfg.setLocalTime {
-			tg.SetLocalTime()
+	}
+	lim := cfg.meter(c.RemoteAddr())
+	defer lim.closed()
+	emit := func(data []byte) error {
+		data = bytes.Trim(cfg.cleanRecord(data), "\n\r\t ")
+		if len(data) == 0 {
+			return nil
 		}
-		if cfg.timezoneOverride != `` {
-			err = tg.SetTimezone(cfg.timezoneOverride)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to set timezone to %v: %v\n", cfg.timezoneOverride, err)
-				return
-			}
+		if err := lim.wait(cfg.ctx, len(data)); err != nil {
+			return err
+		} else if ent, err := cfg.handleLine(data, rip, tg); err != nil {
+			return err
+		} else if err = cfg.process(ent); err != nil {
+			return err
 		}
-		if cfg.formatOverride != `` {
-			if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-				lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-				return
+		return nil
+	}
+	ml := cfg.multiline()
+	//the pending multiline record is pushed once the connection goes quiet, so the last
+	//record before an idle period is not held until the next line arrives
+	var mtx sync.Mutex
+	var idle *time.Timer
+	if ml != nil && cfg.multilineFlush > 0 {
+		var closed bool
+		idle = time.AfterFunc(cfg.multilineFlush, func() {
+			mtx.Lock()
+			defer mtx.Unlock()
+			if !closed {
+				emit(ml.flush())
 			}
-		}
+		})
+		idle.Stop()
+		defer func() {
+			idle.Stop()
+			mtx.Lock()
+			closed = true
+			mtx.Unlock()
+		}()
+	}
+	lr := &boundedLineReader{br: bufio.NewReader(c), max: cfg.maxLine, delim: cfg.delim}
+	if cfg.stripBOM {
+		skipBOM(lr.br)
 	}
-	bio := bufio.NewReader(c)
 	for {
-		data, err := bio.ReadBytes('\n')
-		data = bytes.Trim(data, "\n\r\t ")
-
-		if len(data) > 0 {
-			if ent, err := handleLog(data, rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
-				return
-			} else if err = cfg.proc.ProcessContext(ent, cfg.ctx); err != nil {
+		data, oversize, err := lr.readLine()
+		if oversize {
+			cfg.stats.oversizeLine()
+		}
+		keep := !oversize || !cfg.dropOversize
+		if ml == nil {
+			if keep {
+				if lerr := emit(data); lerr != nil {
+					return
+				}
+			}
+		} else {
+			mtx.Lock()
+			var lerr error
+			if keep {
+				lerr = emit(ml.add(bytes.TrimRight(data, "\n\r")))
+			}
+			if err != nil {
+				//connection is going away, push whatever record is pending
+				emit(ml.flush())
+			} else if idle != nil {
+				idle.Reset(cfg.multilineFlush)
+			}
+			mtx.Unlock()
+			if lerr != nil {
 				return
 			}
 		}
@@ -98,71 +140,129 @@ func lineConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 	}
 }
 
-func lineConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
-	sp := []byte("\n")
+func lineConnHandlerUDP(c net.PacketConn, cfg handlerConfig) {
 	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
-	tcfg := timegrinder.Config{
-		EnableLeftMostSeed: true,
-	}
-	tg, err := timegrinder.NewTimeGrinder(tcfg)
+	tg, err := newTimegrinder(cfg.timeCfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get a handle on the timegrinder: %v\n", err)
-		return
-	} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load custom time formats: %v\n", err)
+		lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
 		return
 	}
-	if cfg.setLocalTime {
-		tg.SetLocalTime()
-	}
-	if cfg.timezoneOverride != `` {
-		err = tg.SetTimezone(cfg.timezoneOverride)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to set timezone to %v: %v\n", cfg.timezoneOverride, err)
-			return
-		}
-	}
-	if cfg.formatOverride != `` {
-		if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-			lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-			return
-		}
-	}
 
+	//blocking a datagram reader pushes the backlog into the socket buffer
+	lim := cfg.meter(nil)
 	for {
 		var rip net.IP
-		n, raddr, err := c.ReadFromUDP(buff)
+		n, raddr, err := c.ReadFrom(buff)
 		if err != nil {
 			break
 		}
-		if n == 0 {
+		lim.addr = raddr
+		if n == 0 || !cfg.remotes.allowed(raddr) {
 			continue
 		}
-		if raddr == nil {
+		if rip = packetSource(raddr, cfg.src); rip == nil {
 			continue
 		}
 		if n > len(buff) {
 			continue
 		}
-		if cfg.src == nil {
-			rip = raddr.IP
-		} else {
-			rip = cfg.src
-		}
 
-		lns := bytes.Split(buff[:n], sp)
-		for _, ln := range lns {
-			ln = bytes.Trim(ln, "\n\r\t ")
+		for _, ln := range cfg.datagramRecords(cfg.cleanDatagram(buff[:n])) {
+			ln = bytes.Trim(cfg.cleanRecord(ln), "\n\r\t ")
 			if len(ln) == 0 {
 				continue
 			}
+			if err := lim.wait(cfg.ctx, len(ln)); err != nil {
+				return
+			}
 			//because we are using and reusing a local buffer, we have to copy the bytes when handing in
-			if ent, err := handleLog(append([]byte(nil), ln...), rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
+			if ent, err := cfg.handleLine(append([]byte(nil), ln...), rip, tg); err != nil {
 				return
-			} else if err = cfg.proc.ProcessContext(ent, cfg.ctx); err != nil {
+			} else if err = cfg.process(ent); err != nil {
 				return
 			}
 		}
 	}
 
 }
+
+// multilineBuffer joins continuation lines onto the record that precedes them
+type multilineBuffer struct {
+	re   *regexp.Regexp
+	max  int
+	buff []byte
+}
+
+// add appends ln to the pending record if it is a continuation, otherwise the
+// pending record is returned and ln starts a new one.
+func (m *multilineBuffer) add(ln []byte) (rec []byte) {
+	if len(m.buff) > 0 && m.re.Match(ln) && (len(m.buff)+1+len(ln)) <= m.max {
+		m.buff = append(m.buff, '\n')
+		m.buff = append(m.buff, ln...)
+		return
+	}
+	rec = m.buff
+	m.buff = append([]byte(nil), ln...)
+	return
+}
+
+// flush returns the pending record and resets the buffer
+func (m *multilineBuffer) flush() (rec []byte) {
+	rec = m.buff
+	m.buff = nil
+	return
+}
+
+// boundedLineReader reads delimited lines without buffering more than max bytes of
+// any one line, so a sender that never sends a delimiter cannot exhaust memory.
+// The delimiter defaults to a newline and may span multiple bytes.
+type boundedLineReader struct {
+	br    *bufio.Reader
+	max   int
+	delim []byte
+}
+
+// readLine returns the next line without its delimiter. Lines longer than max bytes are
+// cut to max bytes and the remainder is discarded up to the next delimiter, so the reader
+// resynchronizes on the following line. The delimiter does not count towards max.
+func (lr *boundedLineReader) readLine() (ln []byte, oversize bool, err error) {
+	delim := lr.delim
+	if len(delim) == 0 {
+		delim = newline
+	}
+	var tail []byte // last bytes read, a multibyte delimiter may span fragments
+	for {
+		var frag []byte
+		frag, err = lr.br.ReadSlice(delim[len(delim)-1])
+		if !oversize {
+			//hold on to enough to strip the delimiter, anything beyond that is oversize
+			if room := lr.max + len(delim) - len(ln); len(frag) > room {
+				ln = append(ln, frag[:room]...)
+				ln, oversize = ln[:lr.max], true
+			} else {
+				ln = append(ln, frag...)
+			}
+		}
+		if len(delim) > 1 {
+			if f := frag; len(f) > len(delim) {
+				tail = append(tail[:0], f[len(f)-len(delim):]...)
+			} else if tail = append(tail, f...); len(tail) > len(delim) {
+				tail = append(tail[:0], tail[len(tail)-len(delim):]...)
+			}
+		} else {
+			tail = frag
+		}
+		if err == nil && !bytes.HasSuffix(tail, delim) {
+			continue //the final delimiter byte on its own
+		} else if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == nil && !oversize {
+			ln = ln[:len(ln)-len(delim)]
+		}
+		if len(ln) > lr.max {
+			ln, oversize = ln[:lr.max], true
+		}
+		return
+	}
+}
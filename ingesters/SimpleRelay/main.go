@@ -47,6 +47,7 @@ func main() {
 		DefaultConfigLocation:        defaultConfigLoc,
 		DefaultConfigOverlayLocation: defaultConfigDLoc,
 		GetConfigFunc:                GetConfig,
+		ExtendedValidate:             true,
 	}
 	ib, err := base.Init(ibc)
 	if err != nil {
@@ -58,6 +59,18 @@ func main() {
 	}
 	debugOn = ib.Verbose
 	lg = ib.Logger
+	if validateRequested() {
+		mcfg, err := ib.MuxerConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid ingest configuration %v\n", err)
+			os.Exit(255)
+		} else if !selfTest(os.Stdout, cfg, mcfg) {
+			fmt.Fprintln(os.Stderr, "\nConfiguration validation failed")
+			os.Exit(255)
+		}
+		fmt.Println("\nConfiguration validation succeeded")
+		os.Exit(0)
+	}
 	id, ok := cfg.IngesterUUID()
 	if !ok {
 		ib.Logger.FatalCode(0, "could not read ingester UUID")
@@ -80,6 +93,10 @@ func main() {
 		debugout("missing capability NET_BIND_SERVICE, may not be able to bind to service ports")
 	}
 
+	//the counters are always kept so they can be logged on SIGUSR1, Metrics-Bind only controls the endpoint
+	relayStats = newRelayMetrics(igst)
+	go handleStatsSignal(relayStats)
+
 	wg := &sync.WaitGroup{}
 
 	var flshr flusher
@@ -87,7 +104,8 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	//fire off our simple listeners
-	if err := startSimpleListeners(cfg, igst, wg, &flshr, ctx); err != nil {
+	sl, err := startSimpleListeners(cfg, igst, wg, &flshr, ctx)
+	if err != nil {
 		lg.FatalCode(0, "Failed to start simple listeners", log.KV("ingesteruuid", id), log.KVErr(err))
 		return
 	}
@@ -102,10 +120,23 @@ func main() {
 		return
 	}
 
+	//the health check is only truthful once every listener has had a chance to bind
+	if cfg.Metrics_Bind != `` {
+		srv, err := relayStats.serve(cfg.Metrics_Bind)
+		if err != nil {
+			lg.FatalCode(0, "Failed to start metrics endpoint", log.KV("ingesteruuid", id), log.KVErr(err))
+			return
+		}
+		defer srv.Close()
+		lg.Info("serving metrics", log.KV("address", cfg.Metrics_Bind))
+	}
+
 	lg.Info("Ingester running")
 
-	//listen for signals so we can close gracefully
-	utils.WaitForQuit()
+	//listen for signals so we can close gracefully, SIGHUP reloads the listeners
+	utils.WaitForQuitOrReload(func() {
+		reloadConfig(sl, ib.ConfigLocation, ib.ConfigOverlayLocation)
+	})
 	ib.AnnounceShutdown()
 	debugout("Closing %d connections\n", connCount())
 	lg.Info("Closing active connections", log.KV("ingesteruuid", id), log.KV("active", connCount()))
@@ -164,6 +195,18 @@ func (f *flusher) Add(c io.Closer) {
 	f.Unlock()
 }
 
+// Remove drops a closer that is closed by its owner, such as the preprocessors of a stopped listener
+func (f *flusher) Remove(c io.Closer) {
+	f.Lock()
+	for i, v := range f.set {
+		if v == c {
+			f.set = append(f.set[:i], f.set[i+1:]...)
+			break
+		}
+	}
+	f.Unlock()
+}
+
 func (f *flusher) Close() (err error) {
 	f.Lock()
 	for _, v := range f.set {
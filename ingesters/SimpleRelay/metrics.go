@@ -0,0 +1,458 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/crewjam/rfc5424"
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+	"github.com/gravwell/gravwell/v3/ingest/processors"
+)
+
+const (
+	metricsPath = `/metrics`
+	healthPath  = `/healthz`
+
+	metricsHeaderTimeout = 10 * time.Second
+	metricsContentType   = `text/plain; version=0.0.4; charset=utf-8`
+)
+
+// relayStats is set at startup, listeners started while it is nil are not metered
+var relayStats *relayMetrics
+
+// relayMetrics collects the per-listener counters served on the Metrics-Bind endpoint
+// and logged on SIGUSR1. A nil relayMetrics collects nothing.
+type relayMetrics struct {
+	sync.Mutex
+	igst      muxerState
+	listeners map[string]*listenerStats
+}
+
+// muxerState is the view of the ingest muxer needed to render metrics and health
+type muxerState interface {
+	Hot() (int, error)
+	LookupTag(entry.EntryTag) (string, bool)
+}
+
+// listenerStats are the counters of a single listener, they survive the listener being
+// restarted by a reload so that they remain monotonic. A nil listenerStats counts nothing.
+type listenerStats struct {
+	bound      atomic.Bool
+	active     atomic.Int64
+	conns      atomic.Uint64
+	connErrs   atomic.Uint64
+	lastErr    atomic.Pointer[statsError]
+	parseErrs  atomic.Uint64
+	rateLimits atomic.Uint64
+	clamped    atomic.Uint64
+	dropped    atomic.Uint64
+	oversize   atomic.Uint64
+	duplicates atomic.Uint64
+	tags       sync.Map // entry.EntryTag -> *tagStats
+}
+
+// statsError is the most recent connection error of a listener
+type statsError struct {
+	when time.Time
+	msg  string
+}
+
+// tagStats count the entries and bytes a listener delivered under a single tag
+type tagStats struct {
+	entries atomic.Uint64
+	bytes   atomic.Uint64
+}
+
+func newRelayMetrics(igst muxerState) *relayMetrics {
+	return &relayMetrics{
+		igst:      igst,
+		listeners: map[string]*listenerStats{},
+	}
+}
+
+// listener returns the counters for the named listener, creating them if needed
+func (rm *relayMetrics) listener(name string) *listenerStats {
+	if rm == nil {
+		return nil
+	}
+	rm.Lock()
+	defer rm.Unlock()
+	ls, ok := rm.listeners[name]
+	if !ok {
+		ls = &listenerStats{}
+		rm.listeners[name] = ls
+	}
+	return ls
+}
+
+// remove drops a listener that is no longer configured so it does not hold the health check down
+func (rm *relayMetrics) remove(name string) {
+	if rm == nil {
+		return
+	}
+	rm.Lock()
+	delete(rm.listeners, name)
+	rm.Unlock()
+}
+
+func (ls *listenerStats) setBound(v bool) {
+	if ls != nil {
+		ls.bound.Store(v)
+	}
+}
+
+func (ls *listenerStats) connOpened() {
+	if ls != nil {
+		ls.active.Add(1)
+		ls.conns.Add(1)
+	}
+}
+
+func (ls *listenerStats) connClosed() {
+	if ls != nil {
+		ls.active.Add(-1)
+	}
+}
+
+// connError counts a connection that failed to be accepted or set up and remembers the error
+func (ls *listenerStats) connError(err error) {
+	if ls == nil || err == nil {
+		return
+	}
+	ls.connErrs.Add(1)
+	ls.lastErr.Store(&statsError{when: time.Now(), msg: err.Error()})
+}
+
+func (ls *listenerStats) parseError() {
+	if ls != nil {
+		ls.parseErrs.Add(1)
+	}
+}
+
+func (ls *listenerStats) rateLimited() {
+	if ls != nil {
+		ls.rateLimits.Add(1)
+	}
+}
+
+func (ls *listenerStats) skewClamped() {
+	if ls != nil {
+		ls.clamped.Add(1)
+	}
+}
+
+func (ls *listenerStats) skewDropped() {
+	if ls != nil {
+		ls.dropped.Add(1)
+	}
+}
+
+func (ls *listenerStats) oversizeLine() {
+	if ls != nil {
+		ls.oversize.Add(1)
+	}
+}
+
+func (ls *listenerStats) duplicateDropped() {
+	if ls != nil {
+		ls.duplicates.Add(1)
+	}
+}
+
+// wrote counts an entry that was handed to the muxer
+func (ls *listenerStats) wrote(ent *entry.Entry) {
+	if ls == nil || ent == nil {
+		return
+	}
+	v, ok := ls.tags.Load(ent.Tag)
+	if !ok {
+		v, _ = ls.tags.LoadOrStore(ent.Tag, &tagStats{})
+	}
+	ts := v.(*tagStats)
+	ts.entries.Add(1)
+	ts.bytes.Add(uint64(len(ent.Data)))
+}
+
+// meteredWriter sits between the preprocessors of a listener and the muxer, counting
+// the entries that are actually handed off for ingest. Entry timestamps are checked
+// against Max-Timestamp-Skew here so that timestamps set by preprocessors are covered too.
+type meteredWriter struct {
+	*ingest.IngestMuxer
+	stats *listenerStats
+	skew  *processors.TimestampClamp
+}
+
+// checkSkew clamps the entry timestamp if it is out of bounds, returning false if the entry should be dropped
+func (mw meteredWriter) checkSkew(ent *entry.Entry) bool {
+	if mw.skew == nil || ent == nil {
+		return true
+	}
+	ts := ent.TS.StandardTime()
+	nts, keep := mw.skew.Check(ts)
+	if !keep {
+		mw.stats.skewDropped()
+		return false
+	} else if !nts.Equal(ts) {
+		ent.TS = entry.FromStandard(nts)
+		mw.stats.skewClamped()
+	}
+	return true
+}
+
+// checkSkewBatch applies checkSkew to a batch, returning the entries to keep
+func (mw meteredWriter) checkSkewBatch(ents []*entry.Entry) []*entry.Entry {
+	if mw.skew == nil {
+		return ents
+	}
+	keep := make([]*entry.Entry, 0, len(ents))
+	for _, ent := range ents {
+		if mw.checkSkew(ent) {
+			keep = append(keep, ent)
+		}
+	}
+	return keep
+}
+
+func (mw meteredWriter) WriteEntry(ent *entry.Entry) (err error) {
+	if !mw.checkSkew(ent) {
+		return
+	}
+	if err = mw.IngestMuxer.WriteEntry(ent); err == nil {
+		mw.stats.wrote(ent)
+	}
+	return
+}
+
+func (mw meteredWriter) WriteEntryContext(ctx context.Context, ent *entry.Entry) (err error) {
+	if !mw.checkSkew(ent) {
+		return
+	}
+	if err = mw.IngestMuxer.WriteEntryContext(ctx, ent); err == nil {
+		mw.stats.wrote(ent)
+	}
+	return
+}
+
+func (mw meteredWriter) WriteBatch(ents []*entry.Entry) (err error) {
+	ents = mw.checkSkewBatch(ents)
+	if err = mw.IngestMuxer.WriteBatch(ents); err == nil {
+		for _, ent := range ents {
+			mw.stats.wrote(ent)
+		}
+	}
+	return
+}
+
+func (mw meteredWriter) WriteBatchContext(ctx context.Context, ents []*entry.Entry) (err error) {
+	ents = mw.checkSkewBatch(ents)
+	if err = mw.IngestMuxer.WriteBatchContext(ctx, ents); err == nil {
+		for _, ent := range ents {
+			mw.stats.wrote(ent)
+		}
+	}
+	return
+}
+
+// serve binds the metrics endpoint and serves it in the background
+func (rm *relayMetrics) serve(bind string) (*http.Server, error) {
+	l, err := net.Listen("tcp", bind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on Metrics-Bind %q: %w", bind, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, rm.serveMetrics)
+	mux.HandleFunc(healthPath, rm.serveHealth)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: metricsHeaderTimeout,
+	}
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			lg.Error("metrics endpoint failed", log.KV("address", bind), log.KVErr(err))
+		}
+	}()
+	return srv, nil
+}
+
+// healthy reports whether every listener is bound and at least one indexer connection is up
+func (rm *relayMetrics) healthy() (ok bool, reason string) {
+	rm.Lock()
+	for name, ls := range rm.listeners {
+		if !ls.bound.Load() {
+			rm.Unlock()
+			return false, "listener " + name + " is not bound"
+		}
+	}
+	rm.Unlock()
+	if n, err := rm.igst.Hot(); err != nil {
+		return false, "ingest connection " + err.Error()
+	} else if n == 0 {
+		return false, "no ingest connections are up"
+	}
+	return true, "ok"
+}
+
+func (rm *relayMetrics) serveHealth(w http.ResponseWriter, r *http.Request) {
+	ok, reason := rm.healthy()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprintln(w, reason)
+}
+
+func (rm *relayMetrics) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+	bw := bufio.NewWriter(w)
+	rm.writeMetrics(bw)
+	bw.Flush()
+}
+
+// tagSample is a single tag labeled counter value
+type tagSample struct {
+	listener string
+	tag      string
+	entries  uint64
+	bytes    uint64
+}
+
+// sorted returns the listener names and their counters, sorted by name
+func (rm *relayMetrics) sorted() (names []string, set []*listenerStats) {
+	rm.Lock()
+	defer rm.Unlock()
+	names = make([]string, 0, len(rm.listeners))
+	set = make([]*listenerStats, 0, len(rm.listeners))
+	for name := range rm.listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		set = append(set, rm.listeners[name])
+	}
+	return
+}
+
+// tagSamples returns the per tag counters of a listener, sorted by tag
+func (rm *relayMetrics) tagSamples(name string, ls *listenerStats) (samples []tagSample) {
+	ls.tags.Range(func(k, v any) bool {
+		ts := v.(*tagStats)
+		tag, ok := rm.igst.LookupTag(k.(entry.EntryTag))
+		if !ok {
+			tag = fmt.Sprintf("%d", k.(entry.EntryTag))
+		}
+		samples = append(samples, tagSample{listener: name, tag: tag, entries: ts.entries.Load(), bytes: ts.bytes.Load()})
+		return true
+	})
+	sort.Slice(samples, func(a, b int) bool { return samples[a].tag < samples[b].tag })
+	return
+}
+
+// logSnapshot logs a human readable summary of every listener's counters, one line per listener
+func (rm *relayMetrics) logSnapshot(l *log.Logger) {
+	if rm == nil {
+		return
+	}
+	names, set := rm.sorted()
+	l.Info("listener stats snapshot", log.KV("listeners", len(set)))
+	for i, ls := range set {
+		var entries, bytes uint64
+		for _, s := range rm.tagSamples(names[i], ls) {
+			entries += s.entries
+			bytes += s.bytes
+		}
+		kvs := []rfc5424.SDParam{
+			log.KV("listener", names[i]),
+			log.KV("bound", ls.bound.Load()),
+			log.KV("active_connections", ls.active.Load()),
+			log.KV("connections", ls.conns.Load()),
+			log.KV("entries", entries),
+			log.KV("bytes", bytes),
+			log.KV("connection_errors", ls.connErrs.Load()),
+			log.KV("parse_errors", ls.parseErrs.Load()),
+			log.KV("duplicates_dropped", ls.duplicates.Load()),
+			log.KV("timestamps_dropped", ls.dropped.Load()),
+		}
+		if le := ls.lastErr.Load(); le != nil {
+			kvs = append(kvs, log.KV("last_error", le.msg), log.KV("last_error_time", le.when.UTC().Format(time.RFC3339)))
+		}
+		l.Info("listener stats", kvs...)
+	}
+}
+
+// writeMetrics writes every counter in the Prometheus text exposition format, sorted by listener and tag
+func (rm *relayMetrics) writeMetrics(w *bufio.Writer) {
+	names, set := rm.sorted()
+	var samples []tagSample
+	for i, ls := range set {
+		samples = append(samples, rm.tagSamples(names[i], ls)...)
+	}
+
+	writeMetricHeader(w, `simplerelay_entries_total`, `counter`, `Entries handed to the ingest muxer.`)
+	for _, s := range samples {
+		fmt.Fprintf(w, "simplerelay_entries_total{listener=%s,tag=%s} %d\n", label(s.listener), label(s.tag), s.entries)
+	}
+	writeMetricHeader(w, `simplerelay_bytes_total`, `counter`, `Bytes of entry data handed to the ingest muxer.`)
+	for _, s := range samples {
+		fmt.Fprintf(w, "simplerelay_bytes_total{listener=%s,tag=%s} %d\n", label(s.listener), label(s.tag), s.bytes)
+	}
+	listenerMetric := func(name, typ, help string, val func(*listenerStats) int64) {
+		writeMetricHeader(w, name, typ, help)
+		for i, ls := range set {
+			fmt.Fprintf(w, "%s{listener=%s} %d\n", name, label(names[i]), val(ls))
+		}
+	}
+	listenerMetric(`simplerelay_parse_errors_total`, `counter`, `Records with an invalid syslog header or CEF/LEEF body.`,
+		func(ls *listenerStats) int64 { return int64(ls.parseErrs.Load()) })
+	listenerMetric(`simplerelay_active_connections`, `gauge`, `Open stream connections.`,
+		func(ls *listenerStats) int64 { return ls.active.Load() })
+	listenerMetric(`simplerelay_connections_total`, `counter`, `Stream connections accepted.`,
+		func(ls *listenerStats) int64 { return int64(ls.conns.Load()) })
+	listenerMetric(`simplerelay_connection_errors_total`, `counter`, `Connections that failed to be accepted or to complete a TLS, PROXY, or gzip handshake.`,
+		func(ls *listenerStats) int64 { return int64(ls.connErrs.Load()) })
+	listenerMetric(`simplerelay_rate_limit_events_total`, `counter`, `Entries delayed by Max-Lines-Per-Second or Max-Bytes-Per-Second.`,
+		func(ls *listenerStats) int64 { return int64(ls.rateLimits.Load()) })
+	listenerMetric(`simplerelay_timestamps_clamped_total`, `counter`, `Entries whose timestamp was outside Max-Timestamp-Skew and was set to now.`,
+		func(ls *listenerStats) int64 { return int64(ls.clamped.Load()) })
+	listenerMetric(`simplerelay_timestamps_dropped_total`, `counter`, `Entries dropped for a timestamp outside Max-Timestamp-Skew.`,
+		func(ls *listenerStats) int64 { return int64(ls.dropped.Load()) })
+	listenerMetric(`simplerelay_oversize_lines_total`, `counter`, `Lines longer than Max-Line-Length that were truncated or dropped.`,
+		func(ls *listenerStats) int64 { return int64(ls.oversize.Load()) })
+	listenerMetric(`simplerelay_duplicates_dropped_total`, `counter`, `Entries dropped as duplicates within Dedup-Window.`,
+		func(ls *listenerStats) int64 { return int64(ls.duplicates.Load()) })
+	listenerMetric(`simplerelay_listener_up`, `gauge`, `Whether the listener is bound.`,
+		func(ls *listenerStats) int64 {
+			if ls.bound.Load() {
+				return 1
+			}
+			return 0
+		})
+}
+
+func writeMetricHeader(w *bufio.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// label quotes a label value as the exposition format expects
+func label(v string) string {
+	return `"` + labelEscaper.Replace(v) + `"`
+}
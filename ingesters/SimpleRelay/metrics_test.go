@@ -0,0 +1,240 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+	"github.com/gravwell/gravwell/v3/ingest/processors"
+)
+
+type fakeMuxer struct {
+	hot  int
+	err  error
+	tags map[entry.EntryTag]string
+}
+
+func (fm *fakeMuxer) Hot() (int, error) {
+	return fm.hot, fm.err
+}
+
+func (fm *fakeMuxer) LookupTag(tg entry.EntryTag) (name string, ok bool) {
+	name, ok = fm.tags[tg]
+	return
+}
+
+func TestMetricsOutput(t *testing.T) {
+	fm := &fakeMuxer{hot: 1, tags: map[entry.EntryTag]string{1: `syslog`, 2: `auth`}}
+	rm := newRelayMetrics(fm)
+	ls := rm.listener(`relay "a"`)
+	ls.setBound(true)
+	ls.wrote(&entry.Entry{Tag: 1, Data: []byte(`hello`)})
+	ls.wrote(&entry.Entry{Tag: 1, Data: []byte(`world!`)})
+	ls.wrote(&entry.Entry{Tag: 2, Data: []byte(`x`)})
+	ls.connOpened()
+	ls.connOpened()
+	ls.connClosed()
+	ls.parseError()
+	ls.rateLimited()
+	ls.skewClamped()
+	ls.skewDropped()
+	ls.skewDropped()
+	if rm.listener(`relay "a"`) != ls {
+		t.Fatal("listener stats were not reused")
+	}
+
+	bb := bytes.NewBuffer(nil)
+	w := bufio.NewWriter(bb)
+	rm.writeMetrics(w)
+	w.Flush()
+	out := bb.String()
+	for _, exp := range []string{
+		"# TYPE simplerelay_entries_total counter\n",
+		`simplerelay_entries_total{listener="relay \"a\"",tag="auth"} 1` + "\n",
+		`simplerelay_entries_total{listener="relay \"a\"",tag="syslog"} 2` + "\n",
+		`simplerelay_bytes_total{listener="relay \"a\"",tag="syslog"} 11` + "\n",
+		`simplerelay_parse_errors_total{listener="relay \"a\""} 1` + "\n",
+		"# TYPE simplerelay_active_connections gauge\n",
+		`simplerelay_active_connections{listener="relay \"a\""} 1` + "\n",
+		`simplerelay_rate_limit_events_total{listener="relay \"a\""} 1` + "\n",
+		`simplerelay_listener_up{listener="relay \"a\""} 1` + "\n",
+		`simplerelay_timestamps_clamped_total{listener="relay \"a\""} 1` + "\n",
+		`simplerelay_timestamps_dropped_total{listener="relay \"a\""} 2` + "\n",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Fatalf("missing %q in\n%s", exp, out)
+		}
+	}
+	// tags are sorted within a listener
+	if strings.Index(out, `tag="auth"`) > strings.Index(out, `tag="syslog"`) {
+		t.Fatalf("tags out of order:\n%s", out)
+	}
+}
+
+func TestMetricsHealth(t *testing.T) {
+	fm := &fakeMuxer{hot: 1}
+	rm := newRelayMetrics(fm)
+	a, b := rm.listener(`a`), rm.listener(`b`)
+	a.setBound(true)
+
+	check := func(exp int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		rm.serveHealth(rec, httptest.NewRequest(http.MethodGet, healthPath, nil))
+		if rec.Code != exp {
+			t.Fatalf("bad health status %d != %d: %s", rec.Code, exp, rec.Body.String())
+		}
+	}
+	check(http.StatusServiceUnavailable) // b never bound
+	b.setBound(true)
+	check(http.StatusOK)
+	fm.hot = 0
+	check(http.StatusServiceUnavailable)
+	fm.hot, fm.err = 1, errors.New("Not running")
+	check(http.StatusServiceUnavailable)
+	fm.err = nil
+	b.setBound(false)
+	check(http.StatusServiceUnavailable)
+	rm.remove(`b`)
+	check(http.StatusOK)
+}
+
+func TestNilMetrics(t *testing.T) {
+	var rm *relayMetrics
+	ls := rm.listener(`a`)
+	if ls != nil {
+		t.Fatal("disabled metrics returned listener stats")
+	}
+	// none of these may panic
+	ls.setBound(true)
+	ls.connOpened()
+	ls.connClosed()
+	ls.parseError()
+	ls.rateLimited()
+	ls.skewClamped()
+	ls.skewDropped()
+	ls.connError(errors.New("bad"))
+	ls.wrote(&entry.Entry{})
+	rm.remove(`a`)
+	rm.logSnapshot(log.NewDiscardLogger())
+}
+
+func TestMetricsSnapshot(t *testing.T) {
+	fm := &fakeMuxer{hot: 1, tags: map[entry.EntryTag]string{1: `syslog`, 2: `auth`}}
+	rm := newRelayMetrics(fm)
+	a, b := rm.listener(`a`), rm.listener(`b`)
+	a.setBound(true)
+	a.wrote(&entry.Entry{Tag: 1, Data: []byte(`hello`)})
+	a.wrote(&entry.Entry{Tag: 2, Data: []byte(`world!`)})
+	a.connOpened()
+	a.connOpened()
+	a.connClosed()
+	a.parseError()
+	a.connError(errors.New("TLS handshake failed"))
+	b.connOpened()
+
+	bb := &bufCloser{}
+	rm.logSnapshot(log.New(bb))
+	out := bb.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("bad line count %d:\n%s", len(lines), out)
+	}
+	for _, exp := range []string{`listener="a"`, `bound="true"`, `active_connections="1"`, `connections="2"`, `entries="2"`,
+		`bytes="11"`, `parse_errors="1"`, `connection_errors="1"`, `last_error="TLS handshake failed"`, `last_error_time=`} {
+		if !strings.Contains(lines[1], exp) {
+			t.Fatalf("missing %s in %s", exp, lines[1])
+		}
+	}
+	if !strings.Contains(lines[2], `listener="b"`) || strings.Contains(lines[2], `last_error`) {
+		t.Fatalf("bad snapshot of listener b: %s", lines[2])
+	}
+
+	// the snapshot and the metrics endpoint read the same counters
+	mb := bytes.NewBuffer(nil)
+	w := bufio.NewWriter(mb)
+	rm.writeMetrics(w)
+	w.Flush()
+	for _, exp := range []string{`simplerelay_connections_total{listener="a"} 2`, `simplerelay_connection_errors_total{listener="a"} 1`} {
+		if !strings.Contains(mb.String(), exp) {
+			t.Fatalf("missing %q in\n%s", exp, mb.String())
+		}
+	}
+}
+
+func TestMeteredWriterSkew(t *testing.T) {
+	tc, err := processors.NewTimestampClamp(`1h`, processors.SkewPolicyClamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls := newRelayMetrics(&fakeMuxer{}).listener(`a`)
+	mw := meteredWriter{stats: ls, skew: tc}
+	good := entry.Now()
+	ents := []*entry.Entry{{TS: good}, {TS: entry.UnixTime(0, 0)}}
+	if ents = mw.checkSkewBatch(ents); len(ents) != 2 {
+		t.Fatalf("clamp dropped entries: %d", len(ents))
+	} else if ents[0].TS != good {
+		t.Fatal("in bounds timestamp was modified")
+	} else if time.Since(ents[1].TS.StandardTime()) > time.Minute {
+		t.Fatalf("timestamp was not clamped: %v", ents[1].TS)
+	} else if ls.clamped.Load() != 1 {
+		t.Fatalf("bad clamped count %d", ls.clamped.Load())
+	}
+
+	if mw.skew, err = processors.NewTimestampClamp(`1h`, processors.SkewPolicyDrop); err != nil {
+		t.Fatal(err)
+	}
+	ents = []*entry.Entry{{TS: entry.UnixTime(0, 0)}, {TS: good}, {TS: entry.FromStandard(time.Now().Add(48 * time.Hour))}}
+	if ents = mw.checkSkewBatch(ents); len(ents) != 1 || ents[0].TS != good {
+		t.Fatalf("bad entries after drop: %d", len(ents))
+	} else if ls.dropped.Load() != 2 {
+		t.Fatalf("bad dropped count %d", ls.dropped.Load())
+	}
+
+	// no clamp configured
+	mw.skew = nil
+	if !mw.checkSkew(&entry.Entry{TS: entry.UnixTime(0, 0)}) {
+		t.Fatal("entry dropped without a clamp")
+	}
+}
+
+func TestMetricsBindConfig(t *testing.T) {
+	cfgPath, err := dropConfig(metricsBindConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := GetConfig(cfgPath, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Metrics_Bind != `127.0.0.1:9100` {
+		t.Fatalf("bad Metrics-Bind %q", cfg.Metrics_Bind)
+	}
+}
+
+const metricsBindConfig string = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023 #example of adding a cleartext connection
+Log-Level=INFO
+Log-File=/tmp/simple_relay.log
+Metrics-Bind=127.0.0.1:9100
+
+[Listener "relay"]
+	Bind-String="tcp://0.0.0.0:7777"
+`
@@ -0,0 +1,177 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+const (
+	proxyHeaderTimeout = 5 * time.Second
+	proxyV1MaxLen      = 107 // longest possible v1 header, including the CRLF
+
+	proxyV2Local = 0x0
+	proxyV2Proxy = 0x1
+	proxyV2Inet  = 0x1
+	proxyV2Inet6 = 0x2
+)
+
+var (
+	proxyV1Sig = []byte("PROXY ")
+	proxyV2Sig = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	errProxyHeader = errors.New("invalid PROXY protocol header")
+)
+
+// proxyConn strips the PROXY protocol header a load balancer sends at the start of a
+// connection, reporting the client address it carries as the remote address.
+// readHeader must be called before the connection is used.
+type proxyConn struct {
+	net.Conn
+	br     *bufio.Reader
+	remote net.Addr // nil if the header did not carry a client address
+}
+
+// readHeader consumes the PROXY protocol header, the connection should be closed on error
+func (pc *proxyConn) readHeader() (err error) {
+	if err = pc.Conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout)); err != nil {
+		return
+	}
+	pc.br = bufio.NewReader(pc.Conn)
+	if pc.remote, err = readProxyHeader(pc.br); err != nil {
+		return
+	}
+	return pc.Conn.SetReadDeadline(time.Time{})
+}
+
+func (pc *proxyConn) Read(b []byte) (int, error) {
+	//drain anything buffered while reading the header, then go straight to the connection
+	if pc.br != nil && pc.br.Buffered() > 0 {
+		return pc.br.Read(b)
+	}
+	return pc.Conn.Read(b)
+}
+
+func (pc *proxyConn) RemoteAddr() net.Addr {
+	if pc.remote != nil {
+		return pc.remote
+	}
+	return pc.Conn.RemoteAddr()
+}
+
+// readProxyHeader reads a v1 or v2 PROXY protocol header, returning the client address.
+// The address is nil for v1 UNKNOWN and v2 LOCAL headers and for v2 address families
+// other than IPv4 and IPv6, in which case the connection address should be used.
+func readProxyHeader(br *bufio.Reader) (net.Addr, error) {
+	sig, err := br.Peek(len(proxyV1Sig))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errProxyHeader, err)
+	}
+	if bytes.Equal(sig, proxyV1Sig) {
+		return readProxyV1(br)
+	}
+	if sig, err = br.Peek(len(proxyV2Sig)); err != nil {
+		return nil, fmt.Errorf("%w: %v", errProxyHeader, err)
+	} else if !bytes.Equal(sig, proxyV2Sig) {
+		return nil, fmt.Errorf("%w: missing signature", errProxyHeader)
+	}
+	return readProxyV2(br)
+}
+
+// readProxyV1 parses the text header, e.g. "PROXY TCP4 192.0.2.1 192.0.2.2 56324 514\r\n"
+func readProxyV1(br *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for len(line) < proxyV1MaxLen {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errProxyHeader, err)
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, fmt.Errorf("%w: v1 header is not terminated", errProxyHeader)
+	}
+	flds := strings.Split(string(line[:len(line)-2]), " ")
+	if len(flds) >= 2 && flds[1] == `UNKNOWN` {
+		return nil, nil
+	} else if len(flds) != 6 || (flds[1] != `TCP4` && flds[1] != `TCP6`) {
+		return nil, fmt.Errorf("%w: malformed v1 header %q", errProxyHeader, line)
+	}
+	ip := net.ParseIP(flds[2])
+	if ip == nil || (ip.To4() != nil) != (flds[1] == `TCP4`) {
+		return nil, fmt.Errorf("%w: bad v1 source address %q", errProxyHeader, flds[2])
+	}
+	port, err := strconv.ParseUint(flds[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad v1 source port %q", errProxyHeader, flds[4])
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyV2 parses the binary header
+func readProxyV2(br *bufio.Reader) (net.Addr, error) {
+	var hdr [16]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", errProxyHeader, err)
+	}
+	if ver := hdr[12] >> 4; ver != 2 {
+		return nil, fmt.Errorf("%w: unsupported v2 version %d", errProxyHeader, ver)
+	}
+	cmd, fam := hdr[12]&0xf, hdr[13]>>4
+	body := make([]byte, binary.BigEndian.Uint16(hdr[14:]))
+	if _, err := io.ReadFull(br, body); err != nil {
+		return nil, fmt.Errorf("%w: %v", errProxyHeader, err)
+	}
+	switch cmd {
+	case proxyV2Local:
+		//health checks from the balancer itself
+		return nil, nil
+	case proxyV2Proxy:
+	default:
+		return nil, fmt.Errorf("%w: unsupported v2 command %d", errProxyHeader, cmd)
+	}
+	switch fam {
+	case proxyV2Inet:
+		if len(body) < 12 {
+			return nil, fmt.Errorf("%w: short v2 IPv4 addresses", errProxyHeader)
+		}
+		return &net.TCPAddr{IP: net.IP(append([]byte(nil), body[0:4]...)), Port: int(binary.BigEndian.Uint16(body[8:]))}, nil
+	case proxyV2Inet6:
+		if len(body) < 36 {
+			return nil, fmt.Errorf("%w: short v2 IPv6 addresses", errProxyHeader)
+		}
+		return &net.TCPAddr{IP: net.IP(append([]byte(nil), body[0:16]...)), Port: int(binary.BigEndian.Uint16(body[32:]))}, nil
+	}
+	return nil, nil
+}
+
+// acceptProxy reads the PROXY header of a new connection and applies the remote filter to the
+// client address, returning false if the connection should be closed
+func (hc handlerConfig) acceptProxy(pc *proxyConn) bool {
+	if err := pc.readHeader(); err != nil {
+		hc.stats.connError(err)
+		lg.Error("invalid PROXY protocol header, closing connection", log.KV("address", pc.Conn.RemoteAddr()), log.KV("listener", hc.name), log.KVErr(err))
+		return false
+	}
+	return hc.remotes.allowed(pc.RemoteAddr())
+}
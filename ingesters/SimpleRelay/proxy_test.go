@@ -0,0 +1,112 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+// proxyV2 builds a v2 header with the given command, family, and address block
+func proxyV2(cmd, fam byte, addrs []byte) []byte {
+	b := append([]byte(nil), proxyV2Sig...)
+	b = append(b, 0x20|cmd, fam<<4|0x1)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(addrs)))
+	return append(b, addrs...)
+}
+
+func TestReadProxyHeader(t *testing.T) {
+	v4 := []byte{192, 0, 2, 1, 192, 0, 2, 2, 0xdc, 0x04, 0x02, 0x02}
+	v6 := make([]byte, 36)
+	copy(v6, net.ParseIP("2001:db8::1"))
+	binary.BigEndian.PutUint16(v6[32:], 4000)
+	tests := []struct {
+		hdr  []byte
+		addr string // empty if no address is expected
+		bad  bool
+	}{
+		{[]byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 514\r\n"), `192.0.2.1:56324`, false},
+		{[]byte("PROXY TCP6 2001:db8::1 2001:db8::2 4000 514\r\n"), `[2001:db8::1]:4000`, false},
+		{[]byte("PROXY UNKNOWN\r\n"), ``, false},
+		{proxyV2(proxyV2Proxy, proxyV2Inet, v4), `192.0.2.1:56324`, false},
+		{proxyV2(proxyV2Proxy, proxyV2Inet6, v6), `[2001:db8::1]:4000`, false},
+		{proxyV2(proxyV2Local, 0, nil), ``, false},
+		{proxyV2(proxyV2Proxy, 0x3, make([]byte, 216)), ``, false}, // AF_UNIX keeps the connection address
+		{[]byte("<13>Oct 11 22:14:15 host app: no header\n"), ``, true},
+		{[]byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 514\n"), ``, true},
+		{[]byte("PROXY TCP4 2001:db8::1 192.0.2.2 56324 514\r\n"), ``, true},
+		{[]byte("PROXY TCP4 192.0.2.1 192.0.2.2 99999 514\r\n"), ``, true},
+		{append([]byte("PROXY TCP4 "), bytes.Repeat([]byte{'1'}, 200)...), ``, true},
+		{proxyV2(0x2, proxyV2Inet, v4), ``, true},
+		{proxyV2(proxyV2Proxy, proxyV2Inet, v4[:8]), ``, true},
+		{proxyV2(proxyV2Proxy, proxyV2Inet, v4)[:20], ``, true},
+	}
+	for i, tt := range tests {
+		addr, err := readProxyHeader(bufio.NewReader(bytes.NewReader(tt.hdr)))
+		if tt.bad {
+			if !errors.Is(err, errProxyHeader) {
+				t.Fatalf("%d: failed to catch bad header: %v", i, err)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if tt.addr == `` && addr != nil {
+			t.Fatalf("%d: unexpected address %v", i, addr)
+		} else if tt.addr != `` && (addr == nil || addr.String() != tt.addr) {
+			t.Fatalf("%d: bad address %v != %v", i, addr, tt.addr)
+		}
+	}
+}
+
+func TestProxyConn(t *testing.T) {
+	payload := "<13>Oct 11 22:14:15 host app: hello\n<13>Oct 11 22:14:16 host app: world\n"
+	for _, hdr := range [][]byte{
+		[]byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 514\r\n"),
+		proxyV2(proxyV2Proxy, proxyV2Inet, []byte{192, 0, 2, 1, 192, 0, 2, 2, 0xdc, 0x04, 0x02, 0x02}),
+	} {
+		client, server := net.Pipe()
+		go func() {
+			client.Write(append(append([]byte(nil), hdr...), payload...))
+			client.Close()
+		}()
+		pc := &proxyConn{Conn: server}
+		if err := pc.readHeader(); err != nil {
+			t.Fatal(err)
+		} else if pc.RemoteAddr().String() != `192.0.2.1:56324` {
+			t.Fatalf("bad remote address %v", pc.RemoteAddr())
+		}
+		b, err := io.ReadAll(pc)
+		if err != nil {
+			t.Fatal(err)
+		} else if string(b) != payload {
+			t.Fatalf("payload was not preserved: %q", b)
+		}
+		pc.Close()
+	}
+
+	// connections without a client address report the connection address
+	client, server := net.Pipe()
+	go func() {
+		client.Write([]byte("PROXY UNKNOWN\r\n"))
+		client.Close()
+	}()
+	pc := &proxyConn{Conn: server}
+	if err := pc.readHeader(); err != nil {
+		t.Fatal(err)
+	} else if pc.RemoteAddr() != server.RemoteAddr() {
+		t.Fatalf("bad remote address %v", pc.RemoteAddr())
+	}
+	pc.Close()
+}
@@ -0,0 +1,64 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiter is a per-connection token bucket that limits the number of entries
+// and/or bytes a listener will accept. A nil rateLimiter is unlimited.
+type rateLimiter struct {
+	lines *rate.Limiter
+	bytes *rate.Limiter
+	burst int
+}
+
+func newRateLimiter(lps, bps int) *rateLimiter {
+	if lps <= 0 && bps <= 0 {
+		return nil
+	}
+	rl := &rateLimiter{}
+	if lps > 0 {
+		rl.lines = rate.NewLimiter(rate.Limit(lps), lps)
+	}
+	if bps > 0 {
+		rl.bytes = rate.NewLimiter(rate.Limit(bps), bps)
+		rl.burst = bps
+	}
+	return rl
+}
+
+// wait blocks until an entry of sz bytes is allowed through the limiter.
+// Entries larger than the byte burst are metered in burst sized chunks.
+func (rl *rateLimiter) wait(ctx context.Context, sz int) (err error) {
+	if rl == nil {
+		return
+	}
+	if rl.lines != nil {
+		if err = rl.lines.Wait(ctx); err != nil {
+			return
+		}
+	}
+	if rl.bytes != nil {
+		for sz > 0 {
+			n := sz
+			if n > rl.burst {
+				n = rl.burst
+			}
+			if err = rl.bytes.WaitN(ctx, n); err != nil {
+				return
+			}
+			sz -= n
+		}
+	}
+	return
+}
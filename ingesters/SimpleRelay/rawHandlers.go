@@ -0,0 +1,131 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+// rawFraming describes how the raw reader splits a stream into binary frames, frames are
+// either a fixed length or carry a length prefix which is not included in the entry
+type rawFraming struct {
+	length int              // fixed frame length, zero when frames are length prefixed
+	prefix int              // size of the length prefix in bytes
+	order  binary.ByteOrder // byte order of the length prefix
+}
+
+var (
+	errShortFrame  = errors.New("connection closed in the middle of a frame")
+	errFrameLength = errors.New("frame length exceeds the maximum")
+)
+
+// next reads a single frame, io.EOF is only returned if the stream ended cleanly between frames
+func (rf rawFraming) next(r io.Reader) (b []byte, err error) {
+	n := rf.length
+	if rf.prefix > 0 {
+		var hdr [8]byte
+		if _, err = io.ReadFull(r, hdr[:rf.prefix]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = errShortFrame
+			}
+			return
+		}
+		var v uint64
+		switch rf.prefix {
+		case 1:
+			v = uint64(hdr[0])
+		case 2:
+			v = uint64(rf.order.Uint16(hdr[:2]))
+		case 4:
+			v = uint64(rf.order.Uint32(hdr[:4]))
+		default:
+			v = rf.order.Uint64(hdr[:8])
+		}
+		if v > uint64(maxDataSize) {
+			err = fmt.Errorf("%w: %d > %d", errFrameLength, v, maxDataSize)
+			return
+		}
+		n = int(v)
+	}
+	b = make([]byte, n)
+	if _, err = io.ReadFull(r, b); err == io.ErrUnexpectedEOF || (err == io.EOF && rf.prefix > 0) {
+		err = errShortFrame
+	}
+	return
+}
+
+// rawConnHandlerTCP ingests each binary frame on the connection as a single entry, frames
+// carry no parseable timestamp so every entry is stamped with its time of receipt
+func rawConnHandlerTCP(c net.Conn, cfg handlerConfig) {
+	cfg.wg.Add(1)
+	id := addConn(c)
+	defer cfg.wg.Done()
+	defer delConn(id)
+	defer c.Close()
+	if !tlsHandshake(c, cfg.name, cfg.stats) {
+		return
+	}
+	var rip net.IP
+
+	if cfg.src == nil {
+		ipstr, _, err := net.SplitHostPort(c.RemoteAddr().String())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get host from rmote addr \"%s\": %v\n", c.RemoteAddr().String(), err)
+			return
+		}
+		if rip = net.ParseIP(ipstr); rip == nil {
+			fmt.Fprintf(os.Stderr, "Failed to get remote addr from \"%s\"\n", ipstr)
+			return
+		}
+	} else {
+		rip = cfg.src
+	}
+
+	lim := cfg.meter(c.RemoteAddr())
+	defer lim.closed()
+	bio := bufio.NewReader(c)
+	for {
+		data, err := cfg.raw.next(bio)
+		if err != nil {
+			if err == errShortFrame || errors.Is(err, errFrameLength) {
+				cfg.stats.parseError()
+				cfg.logEvent(log.WARN, "invalid frame, closing connection", log.KV("remote_addr", c.RemoteAddr()), log.KVErr(err))
+			} else if err != io.EOF {
+				lerr, ok := err.(*net.OpError)
+				if !ok || lerr.Temporary() {
+					fmt.Fprintf(os.Stderr, "Failed to read frame: %v\n", err)
+				}
+			}
+			return
+		} else if len(data) == 0 {
+			continue
+		}
+		if err = lim.wait(cfg.ctx, len(data)); err != nil {
+			return
+		}
+		ent := &entry.Entry{
+			SRC:  rip,
+			TS:   entry.Now(),
+			Tag:  cfg.lineTag(data),
+			Data: data,
+		}
+		if err = cfg.process(ent); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/log"
+	"github.com/gravwell/gravwell/v3/ingest/processors"
+)
+
+func TestRawFramingNext(t *testing.T) {
+	//fixed length frames
+	rdr := bytes.NewReader([]byte("aaaabbbbcc"))
+	rf := rawFraming{length: 4}
+	for _, want := range []string{"aaaa", "bbbb"} {
+		if b, err := rf.next(rdr); err != nil || string(b) != want {
+			t.Fatalf("bad frame %q != %q: %v", b, want, err)
+		}
+	}
+	if _, err := rf.next(rdr); err != errShortFrame {
+		t.Fatalf("expected a short frame, got %v", err)
+	} else if _, err = rf.next(rdr); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+
+	//length prefixed frames in both byte orders, the prefix is not part of the frame
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		var bb bytes.Buffer
+		for _, v := range []string{"\x00\n\x01binary\r\n", "", "x"} {
+			binary.Write(&bb, order, uint16(len(v)))
+			bb.WriteString(v)
+		}
+		rf = rawFraming{prefix: 2, order: order}
+		for _, want := range []string{"\x00\n\x01binary\r\n", "", "x"} {
+			if b, err := rf.next(&bb); err != nil || string(b) != want {
+				t.Fatalf("%v: bad frame %q != %q: %v", order, b, want, err)
+			}
+		}
+		if _, err := rf.next(&bb); err != io.EOF {
+			t.Fatalf("%v: expected EOF, got %v", order, err)
+		}
+	}
+
+	//truncated and oversized frames
+	rf = rawFraming{prefix: 4, order: binary.BigEndian}
+	if _, err := rf.next(bytes.NewReader([]byte{0, 0})); err != errShortFrame {
+		t.Fatalf("expected a short prefix, got %v", err)
+	} else if _, err = rf.next(bytes.NewReader([]byte{0, 0, 0, 8, 'a'})); err != errShortFrame {
+		t.Fatalf("expected a short frame, got %v", err)
+	} else if _, err = rf.next(bytes.NewReader([]byte{0, 0, 0, 8})); err != errShortFrame {
+		t.Fatalf("expected a short frame, got %v", err)
+	} else if _, err = rf.next(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff})); !errors.Is(err, errFrameLength) {
+		t.Fatalf("expected an oversized frame, got %v", err)
+	}
+}
+
+func TestRawConnHandler(t *testing.T) {
+	if lg == nil {
+		lg = log.NewDiscardLogger()
+	}
+	connClosers = make(map[int]closer, 1)
+	frames := []string{"\x01\x02\x03\n\x04", "2024-01-02T03:04:05Z not a timestamp"}
+	srv, cli := net.Pipe()
+	trk := &lockedTracker{}
+	cfg := handlerConfig{
+		lrt:   rawReader,
+		tags:  testTags(0),
+		src:   net.ParseIP("10.0.0.1"),
+		wg:    &sync.WaitGroup{},
+		ctx:   context.Background(),
+		proc:  processors.NewProcessorSet(&nilWriter{}),
+		raw:   rawFraming{prefix: 4, order: binary.LittleEndian},
+		stats: relayStats.listener(`raw`),
+	}
+	cfg.proc.AddProcessor(trk)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		rawConnHandlerTCP(srv, cfg)
+	}()
+	start := time.Now()
+	for _, v := range frames {
+		binary.Write(cli, binary.LittleEndian, uint32(len(v)))
+		cli.Write([]byte(v))
+	}
+	cli.Close()
+	<-done
+
+	r := trk.data()
+	if len(r) != len(frames) {
+		t.Fatalf("bad entry count %d != %d: %q", len(r), len(frames), r)
+	}
+	for i := range r {
+		if r[i] != frames[i] {
+			t.Fatalf("frame was modified: %q != %q", r[i], frames[i])
+		} else if ts := trk.ents[i].TS.StandardTime(); ts.Before(start.Add(-time.Second)) {
+			t.Fatalf("timestamp was not the time of receipt: %v", ts)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+)
+
+// A Reader-Options section holds the settings that only mean something to one reader type.
+// The configuration format cannot nest sections, so the section shares the name of the Listener
+// it belongs to, ex:
+//
+//	[Listener "syslog"]
+//		Bind-String="tcp://0.0.0.0:601"
+//		Reader-Type=rfc5424
+//	[Reader-Options "syslog"]
+//		RFC6587-Framing=auto
+//		Drop-Priority=true
+//
+// The variables a section accepts depend on the Reader-Type of its listener, so a setting that
+// the reader would ignore is a configuration error. The same settings are still accepted in the
+// Listener section itself, that form is deprecated.
+
+type lineReaderOptions struct {
+	Line_Continuation_Regex string
+	Max_Multiline_Bytes     int
+	Multiline_Flush_Timeout string
+	Max_Line_Length         int
+	On_Oversize             string
+	Line_Delimiter          string
+	Strip_BOM               bool
+	Trim_CR                 bool
+}
+
+type jsonReaderOptions struct {
+	Timestamp_Field  string
+	Timestamp_Format string
+	Max_Line_Length  int
+	On_Oversize      string
+	Line_Delimiter   string
+	Strip_BOM        bool
+	Trim_CR          bool
+}
+
+// cefReaderOptions are shared by the CEF and LEEF readers
+type cefReaderOptions struct {
+	Tag_From_Vendor     bool
+	Allowed_Vendor_Tags []string
+	Max_Vendor_Tags     int
+	Max_Line_Length     int
+	On_Oversize         string
+	Line_Delimiter      string
+	Strip_BOM           bool
+	Trim_CR             bool
+}
+
+type rfc5424ReaderOptions struct {
+	Drop_Priority      bool
+	Source_From_Header bool
+	RFC6587_Framing    string
+	Strip_BOM          bool
+	Trim_CR            bool
+}
+
+type rfc6587ReaderOptions struct {
+	Drop_Priority      bool
+	Source_From_Header bool
+}
+
+type rawReaderOptions struct {
+	Frame_Length         int
+	Length_Prefix_Bytes  int
+	Length_Prefix_Endian string
+}
+
+// readerOptionFields names every listener field that may be given in a Reader-Options section
+var readerOptionFields = func() (names []string) {
+	seen := map[string]bool{}
+	for _, lt := range []readerType{lineReader, rfc5424Reader, rfc6587Reader, jsonReader, cefReader, leefReader, rawReader} {
+		tp := reflect.TypeOf(readerOptions(lt)).Elem()
+		for i := 0; i < tp.NumField(); i++ {
+			if n := tp.Field(i).Name; !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+	return
+}()
+
+// readerOptions returns a pointer to the empty options of a reader type
+func readerOptions(lt readerType) interface{} {
+	switch lt {
+	case lineReader:
+		return &lineReaderOptions{}
+	case jsonReader:
+		return &jsonReaderOptions{}
+	case cefReader, leefReader:
+		return &cefReaderOptions{}
+	case rfc5424Reader:
+		return &rfc5424ReaderOptions{}
+	case rfc6587Reader:
+		return &rfc6587ReaderOptions{}
+	case rawReader:
+		return &rawReaderOptions{}
+	}
+	return nil
+}
+
+// applyReaderOptions checks a Reader-Options section against the reader type of the listener
+// and copies its settings into the listener. A setting given in both sections must agree.
+func (l *listener) applyReaderOptions(vc *config.VariableConfig) (err error) {
+	var lt readerType
+	if vc == nil {
+		return
+	} else if lt, err = translateReaderType(l.Reader_Type); err != nil {
+		return
+	}
+	opts := readerOptions(lt)
+	if err = vc.MapToStrict(opts); err != nil {
+		if errors.Is(err, config.ErrUnknownVariable) {
+			err = fmt.Errorf("%w for reader type %s", err, lt)
+		}
+		return
+	}
+	src := reflect.ValueOf(opts).Elem()
+	dst := reflect.ValueOf(l).Elem()
+	for _, n := range vc.Names() {
+		name := strings.ReplaceAll(n, `-`, `_`)
+		match := func(fn string) bool { return strings.EqualFold(fn, name) }
+		sv, dv := src.FieldByNameFunc(match), dst.FieldByNameFunc(match)
+		if !dv.IsZero() && !reflect.DeepEqual(sv.Interface(), dv.Interface()) {
+			return fmt.Errorf("%s is set to different values in the Listener and Reader-Options sections", n)
+		}
+		dv.Set(sv)
+	}
+	return
+}
+
+// deprecatedOptions returns the reader specific settings given in the Listener section rather
+// than the Reader-Options section vc, which may be nil
+func (l *listener) deprecatedOptions(vc *config.VariableConfig) (names []string) {
+	lv := reflect.ValueOf(l).Elem()
+	for _, fn := range readerOptionFields {
+		n := strings.ReplaceAll(fn, `_`, `-`)
+		if lv.FieldByName(fn).IsZero() {
+			continue
+		} else if vc != nil && vc.Vals[vc.Idx(n)] != nil {
+			continue
+		}
+		names = append(names, n)
+	}
+	return
+}
@@ -26,6 +26,8 @@ type regexListener struct {
 func (rl regexListener) Validate() error {
 	if err := rl.baseConfig.Validate(); err != nil {
 		return err
+	} else if err = rl.baseConfig.checkNoUnix(); err != nil {
+		return err
 	}
 	//process the default tag
 	if _, err := rl.defaultTag(); err != nil {
@@ -16,14 +16,12 @@ import (
 	"fmt"
 	"io"
 	"net"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gravwell/gravwell/v3/ingest"
-	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
@@ -38,15 +36,12 @@ type regexHandlerConfig struct {
 	name             string
 	defTag           entry.EntryTag
 	ignoreTimestamps bool
-	setLocalTime     bool
-	timezoneOverride string
 	src              net.IP
 	wg               *sync.WaitGroup
-	formatOverride   string
 	proc             *processors.ProcessorSet
 	ctx              context.Context
 	regex            string
-	timeFormats      config.CustomTimeFormat
+	timeCfg          processors.TimeExtractorConfig
 	trimWhitespace   bool
 	maxBuffer        int
 }
@@ -63,16 +58,18 @@ func startRegexListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGr
 			name:             k,
 			wg:               wg,
 			ignoreTimestamps: v.Ignore_Timestamps,
-			setLocalTime:     v.Assume_Local_Timezone,
-			timezoneOverride: v.Timezone_Override,
 			ctx:              ctx,
-			formatOverride:   v.Timestamp_Format_Override,
-			timeFormats:      cfg.TimeFormat,
+			timeCfg:          v.timeConfig(cfg.TimeFormat),
 			regex:            v.Regex,
 			trimWhitespace:   v.Trim_Whitespace,
 			maxBuffer:        v.Max_Buffer,
 		}
-		if rhc.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
+		stats := relayStats.listener(k)
+		mw := meteredWriter{IngestMuxer: igst, stats: stats}
+		if mw.skew, err = v.timestampClamp(); err != nil {
+			return err
+		}
+		if rhc.proc, err = cfg.Preprocessor.ProcessorSet(mw, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor error", log.KVErr(err))
 		}
 		f.Add(rhc.proc)
@@ -96,62 +93,58 @@ func startRegexListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGr
 			return err
 		}
 
-		tp, str, err := translateBindType(v.Bind_String)
-		if err != nil {
-			lg.FatalCode(0, "invalid bind", log.KV("bindstring", v.Bind_String), log.KVErr(err))
-		}
-
-		if tp.TCP() {
-			//get the socket
-			addr, err := net.ResolveTCPAddr("tcp", str)
-			if err != nil {
-				return fmt.Errorf("%s Bind-String \"%s\" is invalid: %v\n", k, v.Bind_String, err)
-			}
-			l, err := net.ListenTCP("tcp", addr)
-			if err != nil {
-				return fmt.Errorf("%s Failed to listen on \"%s\": %v\n", k, addr, err)
-			}
-			connID := addConn(l)
-			//start the acceptor
-			wg.Add(1)
-			go regexAcceptor(l, connID, igst, rhc, tp)
-		} else if tp.TLS() {
-			config := &tls.Config{
-				MinVersion: tls.VersionTLS12,
-			}
-
-			config.Certificates = make([]tls.Certificate, 1)
-			config.Certificates[0], err = tls.LoadX509KeyPair(v.Cert_File, v.Key_File)
+		for _, bstr := range v.Bind_String {
+			tp, str, err := translateBindType(bstr)
 			if err != nil {
-				lg.Fatal("failed to load certificate", log.KV("certfile", v.Cert_File), log.KV("keyfile", v.Key_File), log.KVErr(err))
+				lg.FatalCode(0, "invalid bind", log.KV("bindstring", bstr), log.KVErr(err))
 			}
-			//get the socket
-			addr, err := net.ResolveTCPAddr("tcp", str)
-			if err != nil {
-				lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", v.Bind_String), log.KV("regexlistener", k), log.KVErr(err))
-			}
-			l, err := tls.Listen("tcp", addr.String(), config)
-			if err != nil {
-				lg.FatalCode(0, "failed to listen via TLS", log.KV("address", addr), log.KV("regexlistener", k), log.KVErr(err))
-			}
-			connID := addConn(l)
-			//start the acceptor
-			wg.Add(1)
-			go regexAcceptor(l, connID, igst, rhc, tp)
-		} else if tp.UDP() {
-			addr, err := net.ResolveUDPAddr(`udp`, str)
-			if err != nil {
-				lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", v.Bind_String), log.KV("listener", k), log.KVErr(err))
-			}
-			l, err := net.ListenUDP(`udp`, addr)
-			if err != nil {
-				lg.FatalCode(0, "failed to listen via udp", log.KV("address", addr), log.KV("listener", k), log.KVErr(err))
+			if tp.TCP() {
+				//get the socket
+				addr, err := net.ResolveTCPAddr("tcp", str)
+				if err != nil {
+					return fmt.Errorf("%s Bind-String \"%s\" is invalid: %v\n", k, bstr, err)
+				}
+				l, err := net.ListenTCP("tcp", addr)
+				if err != nil {
+					return fmt.Errorf("%s Failed to listen on \"%s\": %v\n", k, addr, err)
+				}
+				connID := addConn(l)
+				//start the acceptor
+				wg.Add(1)
+				go regexAcceptor(l, connID, igst, rhc, tp)
+			} else if tp.TLS() {
+				config, err := v.tlsConfig()
+				if err != nil {
+					lg.FatalCode(0, "failed to load TLS configuration", log.KV("certfile", v.Cert_File), log.KV("keyfile", v.Key_File), log.KV("clientcafile", v.Client_CA_File), log.KV("regexlistener", k), log.KVErr(err))
+				}
+				//get the socket
+				addr, err := net.ResolveTCPAddr("tcp", str)
+				if err != nil {
+					lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", bstr), log.KV("regexlistener", k), log.KVErr(err))
+				}
+				l, err := tls.Listen("tcp", addr.String(), config)
+				if err != nil {
+					lg.FatalCode(0, "failed to listen via TLS", log.KV("address", addr), log.KV("regexlistener", k), log.KVErr(err))
+				}
+				connID := addConn(l)
+				//start the acceptor
+				wg.Add(1)
+				go regexAcceptor(l, connID, igst, rhc, tp)
+			} else if tp.UDP() {
+				addr, err := net.ResolveUDPAddr(`udp`, str)
+				if err != nil {
+					lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", bstr), log.KV("listener", k), log.KVErr(err))
+				}
+				l, err := net.ListenUDP(`udp`, addr)
+				if err != nil {
+					lg.FatalCode(0, "failed to listen via udp", log.KV("address", addr), log.KV("listener", k), log.KVErr(err))
+				}
+				connID := addConn(l)
+				wg.Add(1)
+				go regexAcceptorUDP(l, connID, rhc, igst)
 			}
-			connID := addConn(l)
-			wg.Add(1)
-			go regexAcceptorUDP(l, connID, rhc, igst)
 		}
-
+		stats.setBound(true)
 	}
 	debugout("Started %d regex listeners\n", len(cfg.RegexListener))
 	return nil
@@ -190,32 +183,10 @@ func regexAcceptorUDP(conn *net.UDPConn, id int, cfg regexHandlerConfig, igst *i
 	defer conn.Close()
 
 	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
-	tcfg := timegrinder.Config{
-		EnableLeftMostSeed: true,
-	}
-	tg, err := timegrinder.NewTimeGrinder(tcfg)
+	tg, err := newTimegrinder(cfg.timeCfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get a handle on the timegrinder: %v\n", err)
+		lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
 		return
-	} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load custom time formats: %v\n", err)
-		return
-	}
-	if cfg.setLocalTime {
-		tg.SetLocalTime()
-	}
-	if cfg.timezoneOverride != `` {
-		err = tg.SetTimezone(cfg.timezoneOverride)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to set timezone to %v: %v\n", cfg.timezoneOverride, err)
-			return
-		}
-	}
-	if cfg.formatOverride != `` {
-		if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-			lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-			return
-		}
 	}
 	regex, err := regexp.Compile(cfg.regex)
 	if err != nil {
@@ -262,6 +233,9 @@ func regexConnHandler(c net.Conn, cfg regexHandlerConfig, igst *ingest.IngestMux
 	defer cfg.wg.Done()
 	defer delConn(id)
 	defer c.Close()
+	if !tlsHandshake(c, cfg.name, nil) {
+		return
+	}
 	var rip net.IP
 
 	if cfg.src == nil {
@@ -295,33 +269,10 @@ func regexConnHandler(c net.Conn, cfg regexHandlerConfig, igst *ingest.IngestMux
 	var tg *timegrinder.TimeGrinder
 	if !cfg.ignoreTimestamps {
 		var err error
-		tcfg := timegrinder.Config{
-			EnableLeftMostSeed: true,
-		}
-		tg, err = timegrinder.NewTimeGrinder(tcfg)
-		if err != nil {
-			lg.Error("failed to get a handle on the timegrinder", log.KVErr(err))
-			return
-		} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-			lg.Error("failed to load custom time formats", log.KVErr(err))
+		if tg, err = newTimegrinder(cfg.timeCfg); err != nil {
+			lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
 			return
 		}
-		if cfg.setLocalTime {
-			tg.SetLocalTime()
-		}
-		if cfg.timezoneOverride != `` {
-			err = tg.SetTimezone(cfg.timezoneOverride)
-			if err != nil {
-				lg.Error("failed to set timezone", log.KV("timezone", cfg.timezoneOverride), log.KVErr(err))
-				return
-			}
-		}
-		if cfg.formatOverride != `` {
-			if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-				lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-				return
-			}
-		}
 	}
 
 	regexLoop(c, cfg, rip, rs, tg)
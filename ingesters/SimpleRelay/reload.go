@@ -0,0 +1,174 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+// reloadConfig re-reads the configuration and applies any changes to the simple listeners.
+// A configuration that fails to load or verify is rejected and the running listeners are left alone.
+func reloadConfig(sl *simpleListeners, path, overlayPath string) {
+	lg.Info("reloading configuration", log.KV("path", path))
+	cfg, err := GetConfig(path, overlayPath)
+	if err != nil {
+		lg.Error("rejected configuration reload", log.KV("path", path), log.KVErr(err))
+		return
+	}
+	sl.reload(cfg)
+}
+
+// reload diffs the Listener blocks against the running listeners. Removed listeners are stopped
+// and new listeners are started. Listeners whose Tag-Name or Tag-Router changed have their tags
+// re-negotiated in place, any other change tears the listener down and recreates it.
+// RegexListener and JSONListener blocks are not reloaded.
+func (sl *simpleListeners) reload(cfg *cfgType) {
+	sl.Lock()
+	defer sl.Unlock()
+	var restart []*liveListener
+	for k, ll := range sl.live {
+		v, ok := cfg.Listener[k]
+		if !ok {
+			lg.Info("stopping removed listener", log.KV("listener", k))
+			ll.stop(sl.f)
+			delete(sl.live, k)
+			relayStats.remove(k)
+		} else if listenerChanged(ll, v, cfg) {
+			lg.Info("stopping changed listener", log.KV("listener", k))
+			ll.stop(sl.f)
+			delete(sl.live, k)
+			restart = append(restart, ll)
+		} else if tagsChanged(&ll.cfg, v) {
+			if tags, err := resolveTags(sl.igst, v); err != nil {
+				lg.Error("failed to update listener tags", log.KV("listener", k), log.KVErr(err))
+			} else {
+				ll.hcfg.tags.Store(tags)
+				ll.cfg.Tag_Name, ll.cfg.Tag_Router = v.Tag_Name, v.Tag_Router
+				lg.Info("updated listener tags", log.KV("listener", k), log.KV("tag", v.Tag_Name))
+			}
+		}
+	}
+	//sockets of stopped listeners are released, so anything new may now bind
+	var names []string
+	for k := range cfg.Listener {
+		if _, ok := sl.live[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		ll, err := sl.start(k, cfg.Listener[k], cfg)
+		if err == nil {
+			lg.Info("started listener", log.KV("listener", k))
+			sl.live[k] = ll
+			continue
+		}
+		lg.Error("failed to start listener", log.KV("listener", k), log.KVErr(err))
+		//fall back to the previous configuration of a recreated listener
+		for _, prev := range restart {
+			if prev.name != k {
+				continue
+			}
+			if ll, err = sl.start(k, &prev.cfg, prev.parent); err != nil {
+				lg.Error("failed to restore listener", log.KV("listener", k), log.KVErr(err))
+			} else {
+				lg.Warn("restored previous listener configuration", log.KV("listener", k))
+				sl.live[k] = ll
+			}
+		}
+	}
+	if !reflect.DeepEqual(sl.boot.RegexListener, cfg.RegexListener) || !reflect.DeepEqual(sl.boot.JSONListener, cfg.JSONListener) {
+		lg.Warn("RegexListener and JSONListener changes require a restart")
+	}
+}
+
+// listenerChanged reports whether a listener must be recreated to apply the new configuration,
+// including changes to the global settings and preprocessors that the listener inherits
+func listenerChanged(ll *liveListener, v *listener, cfg *cfgType) bool {
+	if ll.parent.Source_Override != cfg.Source_Override || !reflect.DeepEqual(ll.parent.TimeFormat, cfg.TimeFormat) {
+		return true
+	}
+	for _, name := range v.Preprocessor {
+		if !reflect.DeepEqual(ll.parent.Preprocessor[name], cfg.Preprocessor[name]) {
+			return true
+		}
+	}
+	a, b := ll.cfg, *v
+	a.Tag_Name, a.Tag_Router = ``, nil
+	b.Tag_Name, b.Tag_Router = ``, nil
+	return !reflect.DeepEqual(a, b)
+}
+
+// tagsChanged reports whether the Tag-Name or Tag-Router of a listener changed
+func tagsChanged(a, b *listener) bool {
+	return a.Tag_Name != b.Tag_Name || !reflect.DeepEqual(a.Tag_Router, b.Tag_Router)
+}
+
+// resolveTags negotiates the Tag-Name and every Tag-Router target of a listener
+func resolveTags(tn tagNegotiator, v *listener) (lt *listenerTags, err error) {
+	rts, err := v.tagRoutes()
+	if err != nil {
+		return
+	}
+	lt = &listenerTags{}
+	if lt.tag, err = tn.NegotiateTag(v.Tag_Name); err != nil {
+		return nil, err
+	}
+	for _, rt := range rts {
+		rtag, err := tn.NegotiateTag(rt.tag)
+		if err != nil {
+			return nil, err
+		}
+		lt.router = append(lt.router, routedTag{re: rt.re, tag: rtag})
+	}
+	return
+}
+
+// connSet tracks the active connections of a listener so they can be closed when it is stopped
+type connSet struct {
+	sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func (cs *connSet) add(c net.Conn) {
+	if cs == nil {
+		return
+	}
+	cs.Lock()
+	if cs.conns == nil {
+		cs.conns = map[net.Conn]struct{}{}
+	}
+	cs.conns[c] = struct{}{}
+	cs.Unlock()
+}
+
+func (cs *connSet) remove(c net.Conn) {
+	if cs == nil {
+		return
+	}
+	cs.Lock()
+	delete(cs.conns, c)
+	cs.Unlock()
+}
+
+func (cs *connSet) closeAll() {
+	if cs == nil {
+		return
+	}
+	cs.Lock()
+	for c := range cs.conns {
+		c.Close()
+	}
+	cs.Unlock()
+}
@@ -0,0 +1,187 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+const (
+	reloadGlobal = `
+[Global]
+Ingest-Secret = IngestSecrets
+Cleartext-Backend-target=127.0.0.1:4023
+Log-Level=INFO
+`
+	reloadBase = reloadGlobal + `
+[Listener "same"]
+	Bind-String="tcp://127.0.0.1:0"
+	Tag-Name=same
+
+[Listener "retag"]
+	Bind-String="udp://127.0.0.1:0"
+	Tag-Name=before
+
+[Listener "recreate"]
+	Bind-String="tcp://127.0.0.2:0"
+	Tag-Name=recreate
+
+[Listener "removed"]
+	Bind-String="udp://127.0.0.2:0"
+	Tag-Name=removed
+`
+	reloadUpdated = reloadGlobal + `
+[Listener "same"]
+	Bind-String="tcp://127.0.0.1:0"
+	Tag-Name=same
+
+[Listener "retag"]
+	Bind-String="udp://127.0.0.1:0"
+	Tag-Name=after
+	Tag-Router="sshd=auth"
+
+[Listener "recreate"]
+	Bind-String="tcp://127.0.0.2:0"
+	Tag-Name=recreate
+	Reader-Type=rfc5424
+
+[Listener "added"]
+	Bind-String="udp://127.0.0.3:0"
+	Tag-Name=added
+`
+	reloadInvalid = reloadGlobal + `
+[Listener "same"]
+	Bind-String="tcp://127.0.0.1:0"
+	Reader-Type=bogus
+`
+)
+
+func TestReload(t *testing.T) {
+	if lg == nil {
+		lg = log.NewDiscardLogger()
+	}
+	connClosers = make(map[int]closer, 1)
+	load := func(s string) *cfgType {
+		t.Helper()
+		pth, err := dropConfig(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := GetConfig(pth, ``)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+	cfg := load(reloadBase)
+	tags, err := cfg.Tags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	igst, err := ingest.NewMuxer(ingest.MuxerConfig{
+		Destinations: []ingest.Target{{Address: `127.0.0.1:4023`, Secret: `IngestSecrets`}},
+		Tags:         tags,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	var f flusher
+	sl, err := startSimpleListeners(cfg, igst, &wg, &f, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := make(map[string]*liveListener, len(sl.live))
+	for k, v := range sl.live {
+		before[k] = v
+	}
+
+	sl.reload(load(reloadUpdated))
+	if len(sl.live) != 4 {
+		t.Fatalf("bad listener count: %d", len(sl.live))
+	} else if _, ok := sl.live[`removed`]; ok {
+		t.Fatal("removed listener is still running")
+	} else if _, ok = sl.live[`added`]; !ok {
+		t.Fatal("added listener was not started")
+	}
+	if sl.live[`same`] != before[`same`] {
+		t.Fatal("unchanged listener was recreated")
+	} else if sl.live[`recreate`] == before[`recreate`] {
+		t.Fatal("listener with a new reader type was not recreated")
+	}
+	rt := sl.live[`retag`]
+	if rt != before[`retag`] {
+		t.Fatal("tag change recreated the listener")
+	}
+	after, err := igst.GetTag(`after`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := igst.GetTag(`auth`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := rt.hcfg.lineTag([]byte(`kernel: hello`)); tag != after {
+		t.Fatalf("tag was not re-negotiated: %d != %d", tag, after)
+	} else if tag = rt.hcfg.lineTag([]byte(`sshd: login`)); tag != auth {
+		t.Fatalf("router was not updated: %d != %d", tag, auth)
+	}
+
+	//a configuration that fails verification leaves everything running
+	pth, err := dropConfig(reloadInvalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	running := make(map[string]*liveListener, len(sl.live))
+	for k, v := range sl.live {
+		running[k] = v
+	}
+	reloadConfig(sl, pth, ``)
+	if len(sl.live) != len(running) {
+		t.Fatalf("invalid config changed the running set: %d != %d", len(sl.live), len(running))
+	}
+	for k, v := range running {
+		if sl.live[k] != v {
+			t.Fatalf("invalid config modified listener %s", k)
+		}
+	}
+
+	//a recreated listener that fails to bind falls back to its previous configuration
+	busy, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	bs := fmt.Sprintf("tcp://%s", busy.Addr())
+	prev := sl.live[`recreate`]
+	sl.reload(load(strings.Replace(reloadUpdated, `"tcp://127.0.0.2:0"`, `"`+bs+`"`, 1)))
+	if rc, ok := sl.live[`recreate`]; !ok {
+		t.Fatal("listener that failed to bind was not restored")
+	} else if rc == prev {
+		t.Fatal("changed listener was not recreated")
+	} else if rc.cfg.Bind_String[0] != prev.cfg.Bind_String[0] {
+		t.Fatalf("restored listener has the wrong bind: %v", rc.cfg.Bind_String)
+	}
+	if len(sl.live) != 4 {
+		t.Fatalf("bad listener count after failed bind: %d", len(sl.live))
+	}
+
+	for _, ll := range sl.live {
+		ll.stop(&f)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,117 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+const (
+	// repeated events are logged on the first occurrence and then every logSampleInterval after that
+	logSampleInterval = 1000
+)
+
+// remoteFilter restricts the remote addresses a listener will accept data from.
+// Deny rules win over allow rules, and an empty allow list allows everything
+// that is not denied. A nil remoteFilter accepts everything.
+type remoteFilter struct {
+	name     string
+	allow    []*net.IPNet
+	deny     []*net.IPNet
+	rejected atomic.Uint64
+}
+
+func newRemoteFilter(name string, allow, deny []string) (rf *remoteFilter, err error) {
+	if len(allow) == 0 && len(deny) == 0 {
+		return
+	}
+	rf = &remoteFilter{name: name}
+	if rf.allow, err = parseCIDRs(allow); err != nil {
+		return nil, fmt.Errorf("Allow-Remote %v", err)
+	} else if rf.deny, err = parseCIDRs(deny); err != nil {
+		return nil, fmt.Errorf("Deny-Remote %v", err)
+	}
+	return
+}
+
+// allowed reports whether the remote address may deliver data, rejections are counted and
+// periodically logged. Addresses that cannot be resolved to an IP are rejected.
+func (rf *remoteFilter) allowed(addr net.Addr) bool {
+	if rf == nil {
+		return true
+	}
+	ip := remoteIP(addr)
+	if ip != nil && !containsIP(rf.deny, ip) && (len(rf.allow) == 0 || containsIP(rf.allow, ip)) {
+		return true
+	}
+	if n := rf.rejected.Add(1); n == 1 || n%logSampleInterval == 0 {
+		lg.Info("rejected remote address", log.KV("address", addr), log.KV("listener", rf.name), log.KV("rejected", n))
+	}
+	return false
+}
+
+// count returns the number of rejected connections or packets
+func (rf *remoteFilter) count() uint64 {
+	if rf == nil {
+		return 0
+	}
+	return rf.rejected.Load()
+}
+
+// parseCIDRs parses a list of CIDR blocks, bare addresses are treated as a single host
+func parseCIDRs(vals []string) (r []*net.IPNet, err error) {
+	for _, v := range vals {
+		v = strings.TrimSpace(v)
+		if !strings.Contains(v, `/`) {
+			if ip := net.ParseIP(v); ip != nil {
+				if ip4 := ip.To4(); ip4 != nil {
+					ip = ip4
+				}
+				r = append(r, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+				continue
+			}
+		}
+		var ipn *net.IPNet
+		if _, ipn, err = net.ParseCIDR(v); err != nil {
+			err = fmt.Errorf("%q is not a valid CIDR", v)
+			return
+		}
+		r = append(r, ipn)
+	}
+	return
+}
+
+func containsIP(set []*net.IPNet, ip net.IP) bool {
+	for _, n := range set {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP extracts the IP from a TCP or UDP remote address
+func remoteIP(addr net.Addr) net.IP {
+	switch v := addr.(type) {
+	case *net.TCPAddr:
+		if v != nil {
+			return v.IP
+		}
+	case *net.UDPAddr:
+		if v != nil {
+			return v.IP
+		}
+	}
+	return nil
+}
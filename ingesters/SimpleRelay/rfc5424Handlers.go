@@ -13,13 +13,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"regexp"
+	"strconv"
 
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
-	"github.com/gravwell/gravwell/v3/ingest/processors"
 	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
@@ -29,6 +30,9 @@ func rfc5424ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 	defer cfg.wg.Done()
 	defer delConn(id)
 	defer c.Close()
+	if !tlsHandshake(c, cfg.name, cfg.stats) {
+		return
+	}
 	var rip net.IP
 	debugout("new connection from %v\n", c.RemoteAddr().String())
 
@@ -46,150 +50,216 @@ func rfc5424ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 		rip = cfg.src
 	}
 
-	tcfg := timegrinder.Config{
-		EnableLeftMostSeed: true,
-	}
-	tg, err := timegrinder.NewTimeGrinder(tcfg)
+	tg, err := newTimegrinder(cfg.timeCfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get a handle on the timegrinder: %v\n", err)
+		lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
 		return
-	} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load custom time formats: %v\n", err)
-		return
-	}
-
-	if cfg.setLocalTime {
-		tg.SetLocalTime()
 	}
-	if cfg.timezoneOverride != `` {
-		err = tg.SetTimezone(cfg.timezoneOverride)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to set timezone to %v: %v\n", cfg.timezoneOverride, err)
-			return
-		}
-	}
-	if cfg.formatOverride != `` {
-		if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-			lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-			return
-		}
+	var rdr io.Reader = c
+	if cfg.stripBOM {
+		br := bufio.NewReader(c)
+		skipBOM(br)
+		rdr = br
 	}
-	s := bufio.NewScanner(c)
+	s := bufio.NewScanner(rdr)
 	s.Buffer(make([]byte, initDataSize), maxDataSize)
-	splitter := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		idx, sz := rfc5424StartIndex(data)
-		if idx == -1 {
-			if atEOF {
-				token = data
-				err = bufio.ErrFinalToken
-			} else if len(data) >= maxRFCSize {
-				//we are oversized, just throw what we have
-				advance = maxRFCSize
-				token = data[0:advance]
-			}
-			return //ask for more data
-		}
-		if idx > 0 {
-			advance = idx //advance to start the match
-			token = data[:advance]
-			return
-		}
-		//at the start, so scan again
-		idx2, _ := rfc5424StartIndex(data[idx+sz:]) //advance past the min size
-		if idx2 == -1 {
-			if atEOF {
-				token = data
-				err = bufio.ErrFinalToken
-			}
-			return //ask for more data
-		}
-		advance = sz + idx2
-		token = data[:advance]
-		return
-	}
-	s.Split(splitter)
+	s.Split(rfc5424Splitter(cfg.framing))
+	lim := cfg.meter(c.RemoteAddr())
+	defer lim.closed()
 	for s.Scan() {
 		data := bytes.TrimSpace(s.Bytes())
+		lim.checkHeader(data)
 		if cfg.dropPriority {
 			data = dropPriority(data)
 		}
 		if len(data) == 0 {
 			continue
 		}
-		data = bytes.Clone(data) // the scanner re-uses bytes, so we have to clone
-		if ent, err := handleLog(data, rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
+		data = cfg.cleanRecord(bytes.Clone(data)) // the scanner re-uses bytes, so we have to clone
+		if err := lim.wait(cfg.ctx, len(data)); err != nil {
+			return
+		} else if ent, err := handleLog(data, headerSource(data, rip, cfg.srcFromHeader), cfg.ignoreTimestamps, cfg.lineTag(data), tg); err != nil {
 			return
-		} else if err = cfg.proc.ProcessContext(ent, cfg.ctx); err != nil {
+		} else if err = cfg.process(ent); err != nil {
 			return
 		}
 	}
 }
 
-func dropPriority(buff []byte) []byte {
-	//scoot past the '>'
-	if prioIdx := bytes.IndexByte(buff, '>'); prioIdx > 1 {
-		buff = buff[prioIdx+1:]
+// rfc5424Splitter returns the scanner split function for the given RFC6587 framing
+func rfc5424Splitter(f framingType) bufio.SplitFunc {
+	switch f {
+	case octetFraming:
+		return octetCountSplit
+	case autoFraming:
+		return autoFramingSplit
 	}
-	return buff
+	return rfc5424Split
 }
 
-func rfc5424ConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
-	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
-	tcfg := timegrinder.Config{
-		EnableLeftMostSeed: true,
+// rfc5424Split splits non-transparently framed messages on the start of each RFC5424 header
+func rfc5424Split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	idx, sz := rfc5424StartIndex(data)
+	if idx == -1 {
+		if atEOF {
+			token = data
+			err = bufio.ErrFinalToken
+		} else if len(data) >= maxRFCSize {
+			//we are oversized, just throw what we have
+			advance = maxRFCSize
+			token = data[0:advance]
+		}
+		return //ask for more data
 	}
-	tg, err := timegrinder.NewTimeGrinder(tcfg)
-	if err != nil {
-		lg.Error("Failed to get a handle on the timegrinder", log.KVErr(err))
-		return
-	} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-		lg.Error("Failed to load custom time formats", log.KVErr(err))
+	if idx > 0 {
+		advance = idx //advance to start the match
+		token = data[:advance]
 		return
 	}
+	//at the start, so scan again
+	idx2, _ := rfc5424StartIndex(data[idx+sz:]) //advance past the min size
+	if idx2 == -1 {
+		if atEOF {
+			token = data
+			err = bufio.ErrFinalToken
+		}
+		return //ask for more data
+	}
+	advance = sz + idx2
+	token = data[:advance]
+	return
+}
 
-	if cfg.setLocalTime {
-		tg.SetLocalTime()
+// octetCountSplit splits octet counted messages as defined in RFC6587 section 3.4.1: MSG-LEN SP SYSLOG-MSG
+// Stray framing bytes between messages are discarded and a malformed length header is handled
+// by emitting everything up to the next newline so that we can resync
+func octetCountSplit(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if skip := len(data) - len(bytes.TrimLeft(data, "\n\r\x00")); skip > 0 {
+		advance = skip
+		return
 	}
-	if cfg.timezoneOverride != `` {
-		err = tg.SetTimezone(cfg.timezoneOverride)
-		if err != nil {
-			lg.Error("Failed to load timezeone override", log.KV("override", cfg.timezoneOverride), log.KVErr(err))
-			return
+	if len(data) == 0 {
+		return //ask for more data
+	}
+	sp := bytes.IndexByte(data, ' ')
+	if sp == -1 {
+		if atEOF {
+			token = data
+			err = bufio.ErrFinalToken
+		} else if len(data) > maxOctetHeaderLen {
+			return octetResync(data, atEOF)
 		}
+		return //ask for more data
 	}
-	if cfg.formatOverride != `` {
-		if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-			lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-			return
+	n, perr := strconv.ParseUint(string(data[:sp]), 10, 32)
+	if perr != nil || sp > maxOctetHeaderLen || n == 0 || n > maxRFCSize {
+		return octetResync(data, atEOF)
+	}
+	end := sp + 1 + int(n)
+	if end > len(data) {
+		if atEOF {
+			token = data[sp+1:]
+			err = bufio.ErrFinalToken
 		}
+		return //ask for more data
+	}
+	advance = end
+	token = data[sp+1 : end]
+	return
+}
+
+// octetResync handles a malformed octet counting header by throwing the data up to the next newline
+func octetResync(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if idx := bytes.IndexByte(data, '\n'); idx != -1 {
+		advance = idx + 1
+		token = data[:idx]
+	} else if atEOF {
+		token = data
+		err = bufio.ErrFinalToken
+	} else if len(data) >= maxRFCSize {
+		advance = maxRFCSize
+		token = data[:advance]
+	}
+	return
+}
+
+// autoFramingSplit uses octet counting for frames that begin with a digit and non-transparent framing otherwise
+func autoFramingSplit(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if skip := len(data) - len(bytes.TrimLeft(data, "\n\r\x00")); skip > 0 {
+		advance = skip
+		return
+	}
+	if len(data) > 0 && data[0] >= '0' && data[0] <= '9' {
+		return octetCountSplit(data, atEOF)
+	}
+	return rfc5424Split(data, atEOF)
+}
+
+func dropPriority(buff []byte) []byte {
+	//scoot past the '>'
+	if prioIdx := bytes.IndexByte(buff, '>'); prioIdx > 1 {
+		buff = buff[prioIdx+1:]
+	}
+	return buff
+}
+
+func rfc5424ConnHandlerUDP(c net.PacketConn, cfg handlerConfig) {
+	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
+	tg, err := newTimegrinder(cfg.timeCfg)
+	if err != nil {
+		lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
+		return
 	}
 
 	var rip net.IP
+	lim := cfg.meter(nil)
 	for {
-		n, raddr, err := c.ReadFromUDP(buff)
+		n, raddr, err := c.ReadFrom(buff)
 		if err != nil {
 			break
 		}
-		if n > 0 {
-			if raddr == nil {
+		lim.addr = raddr
+		if n > 0 && cfg.remotes.allowed(raddr) {
+			if rip = packetSource(raddr, cfg.src); rip == nil {
 				continue
 			}
 			if n > len(buff) {
 				continue
 			}
-			if cfg.src == nil {
-				rip = raddr.IP
+			pkt := cfg.cleanRecord(cfg.cleanDatagram(append([]byte(nil), buff[:n]...)))
+			if cfg.datagramEntry {
+				//the datagram is the message, embedded headers are not split out
+				cfg.handleRFC5424Datagram(pkt, rip, tg, lim)
 			} else {
-				rip = cfg.src
+				handleRFC5424Packet(pkt, rip, cfg.ignoreTimestamps, cfg.dropPriority, cfg.srcFromHeader, cfg.lineTag, tg, cfg.process, lim, cfg.ctx)
 			}
-			handleRFC5424Packet(append([]byte(nil), buff[:n]...), rip, cfg.ignoreTimestamps, cfg.dropPriority, cfg.tag, tg, cfg.proc, cfg.ctx)
 		}
 	}
 
 }
 
+// handleRFC5424Datagram sends an entire datagram as a single entry
+func (hc handlerConfig) handleRFC5424Datagram(buff []byte, ip net.IP, tg *timegrinder.TimeGrinder, lim *connMeter) error {
+	token := bytes.TrimSpace(buff)
+	lim.checkHeader(token)
+	if hc.dropPriority {
+		token = dropPriority(token)
+	}
+	if len(token) == 0 {
+		return nil
+	}
+	if err := lim.wait(hc.ctx, len(token)); err != nil {
+		return err
+	}
+	ent, err := handleLog(token, headerSource(token, ip, hc.srcFromHeader), hc.ignoreTimestamps, hc.lineTag(token), tg)
+	if err != nil {
+		return err
+	}
+	return hc.process(ent)
+}
+
 // we can be very very fast on this one by just manually scanning the buffer
-func handleRFC5424Packet(buff []byte, ip net.IP, ignoreTS, dropPrio bool, tag entry.EntryTag, tg *timegrinder.TimeGrinder, proc *processors.ProcessorSet, ctx context.Context) {
+func handleRFC5424Packet(buff []byte, ip net.IP, ignoreTS, dropPrio, srcHdr bool, tagFn func([]byte) entry.EntryTag, tg *timegrinder.TimeGrinder, process func(*entry.Entry) error, lim *connMeter, ctx context.Context) {
 	var idx []int
 	var idx2 []int
 	var token []byte
@@ -198,12 +268,15 @@ func handleRFC5424Packet(buff []byte, ip net.IP, ignoreTS, dropPrio bool, tag en
 		if idx = re.FindIndex(buff); idx == nil || len(idx) != 2 {
 			//did not find our header at all, just throw the buff up stream
 			token = bytes.TrimSpace(buff)
+			lim.checkHeader(token)
 			if dropPrio {
 				token = dropPriority(token)
 			}
-			if ent, err := handleLog(token, ip, ignoreTS, tag, tg); err != nil {
+			if err := lim.wait(ctx, len(token)); err != nil {
+				return
+			} else if ent, err := handleLog(token, headerSource(token, ip, srcHdr), ignoreTS, tagFn(token), tg); err != nil {
 				return
-			} else if err = proc.ProcessContext(ent, ctx); err != nil {
+			} else if err = process(ent); err != nil {
 				return
 			}
 			return
@@ -213,12 +286,15 @@ func handleRFC5424Packet(buff []byte, ip net.IP, ignoreTS, dropPrio bool, tag en
 			if idx2 = re.FindIndex(buff[idx[1]:]); idx2 == nil || len(idx2) != 2 {
 				//not found, this is the end of our input, throw it all
 				token = bytes.TrimSpace(buff)
+				lim.checkHeader(token)
 				if dropPrio {
 					token = dropPriority(token)
 				}
-				if ent, err := handleLog(token, ip, ignoreTS, tag, tg); err != nil {
+				if err := lim.wait(ctx, len(token)); err != nil {
+					return
+				} else if ent, err := handleLog(token, headerSource(token, ip, srcHdr), ignoreTS, tagFn(token), tg); err != nil {
 					return
-				} else if err = proc.ProcessContext(ent, ctx); err != nil {
+				} else if err = process(ent); err != nil {
 					return
 				}
 				return
@@ -228,12 +304,15 @@ func handleRFC5424Packet(buff []byte, ip net.IP, ignoreTS, dropPrio bool, tag en
 			token = buff[0:end]
 			buff = buff[end:]
 			token = bytes.TrimSpace(token)
+			lim.checkHeader(token)
 			if dropPrio {
 				token = dropPriority(token)
 			}
-			if ent, err := handleLog(token, ip, ignoreTS, tag, tg); err != nil {
+			if err := lim.wait(ctx, len(token)); err != nil {
 				return
-			} else if err = proc.ProcessContext(ent, ctx); err != nil {
+			} else if ent, err := handleLog(token, headerSource(token, ip, srcHdr), ignoreTS, tagFn(token), tg); err != nil {
+				return
+			} else if err = process(ent); err != nil {
 				return
 			}
 		} else {
@@ -242,18 +321,56 @@ func handleRFC5424Packet(buff []byte, ip net.IP, ignoreTS, dropPrio bool, tag en
 			buff = buff[idx[0]:]
 
 			token = bytes.TrimSpace(token)
+			lim.checkHeader(token)
 			if dropPrio {
 				token = dropPriority(token)
 			}
-			if ent, err := handleLog(token, ip, ignoreTS, tag, tg); err != nil {
+			if err := lim.wait(ctx, len(token)); err != nil {
+				return
+			} else if ent, err := handleLog(token, headerSource(token, ip, srcHdr), ignoreTS, tagFn(token), tg); err != nil {
 				return
-			} else if err = proc.ProcessContext(ent, ctx); err != nil {
+			} else if err = process(ent); err != nil {
 				return
 			}
 		}
 	}
 }
 
+// headerSource returns the IP from the syslog HOSTNAME field if enabled and the
+// HOSTNAME is an IP address, otherwise the provided default is returned.
+func headerSource(b []byte, def net.IP, enabled bool) net.IP {
+	if enabled {
+		if ip := syslogHostIP(b); ip != nil {
+			return ip
+		}
+	}
+	return def
+}
+
+// syslogHostIP attempts to pull the HOSTNAME field out of RFC5424 and RFC3164
+// formatted messages, returning nil if the HOSTNAME is missing or is not an IP.
+func syslogHostIP(b []byte) net.IP {
+	//skip the priority if its still there
+	if len(b) > 0 && b[0] == '<' {
+		if idx := bytes.IndexByte(b, '>'); idx > 0 && idx < 5 {
+			b = b[idx+1:]
+		}
+	}
+	flds := bytes.Fields(b)
+	if len(flds) < 3 {
+		return nil
+	}
+	var host []byte
+	if len(flds[0]) <= 2 && flds[0][0] >= '0' && flds[0][0] <= '9' {
+		//RFC5424 VERSION TIMESTAMP HOSTNAME
+		host = flds[2]
+	} else if len(flds) > 3 {
+		//RFC3164 Mmm dd hh:mm:ss HOSTNAME
+		host = flds[3]
+	}
+	return net.ParseIP(string(host))
+}
+
 var sepStart = []byte{'\n', '<'}
 
 const sepEnd = byte('>')
@@ -9,6 +9,10 @@
 package main
 
 import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
 	"testing"
 )
 
@@ -49,3 +53,75 @@ func TestRfcOffset(t *testing.T) {
 }
 
 var testVal = []byte(`` + "\n<123>")
+
+func TestSyslogHostIP(t *testing.T) {
+	tsts := []struct {
+		val string
+		ip  string
+	}{
+		{val: `<34>1 2003-10-11T22:14:15.003Z 10.0.0.1 su - ID47 - BOM'su root' failed`, ip: `10.0.0.1`},
+		{val: `1 2003-10-11T22:14:15.003Z fe80::1 su - ID47 - BOM'su root' failed`, ip: `fe80::1`},
+		{val: `<34>Oct 11 22:14:15 192.168.1.2 su: 'su root' failed for lonvick on /dev/pts/8`, ip: `192.168.1.2`},
+		{val: `<34>Oct  1 22:14:15 192.168.1.3 su: 'su root' failed for lonvick on /dev/pts/8`, ip: `192.168.1.3`},
+		{val: `<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - BOM'su root' failed`},
+		{val: `<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8`},
+		{val: `garbage`},
+	}
+	for _, v := range tsts {
+		ip := syslogHostIP([]byte(v.val))
+		if v.ip == `` {
+			if ip != nil {
+				t.Fatalf("got unexpected IP %v from %q", ip, v.val)
+			}
+		} else if ip == nil || !ip.Equal(net.ParseIP(v.ip)) {
+			t.Fatalf("invalid IP from %q: %v != %v", v.val, ip, v.ip)
+		}
+	}
+}
+
+func scanFrames(t *testing.T, f framingType, input string) (r []string) {
+	s := bufio.NewScanner(strings.NewReader(input))
+	s.Split(rfc5424Splitter(f))
+	for s.Scan() {
+		if tok := strings.TrimSpace(s.Text()); tok != `` {
+			r = append(r, tok)
+		}
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestRFC6587Framing(t *testing.T) {
+	msgA := "<34>1 2003-10-11T22:14:15.003Z host su - ID47 - first line\nsecond line"
+	msgB := "<34>1 2003-10-11T22:14:16.003Z host su - ID48 - single line"
+	octet := fmt.Sprintf("%d %s%d %s\n", len(msgA), msgA, len(msgB), msgB)
+
+	if r := scanFrames(t, octetFraming, octet); len(r) != 2 || r[0] != msgA || r[1] != msgB {
+		t.Fatalf("bad octet framing: %q", r)
+	}
+	if r := scanFrames(t, autoFraming, octet); len(r) != 2 || r[0] != msgA || r[1] != msgB {
+		t.Fatalf("bad auto framing of octet counted messages: %q", r)
+	}
+
+	// LF framing keeps the existing header based splitting
+	lf := msgB + "\n" + msgB + "\n"
+	if r := scanFrames(t, lfFraming, lf); len(r) != 2 || r[0] != msgB || r[1] != msgB {
+		t.Fatalf("bad lf framing: %q", r)
+	}
+	if r := scanFrames(t, autoFraming, lf); len(r) != 2 || r[0] != msgB || r[1] != msgB {
+		t.Fatalf("bad auto framing of lf messages: %q", r)
+	}
+
+	// a malformed header resyncs on the next newline
+	bad := "garbage header\n" + fmt.Sprintf("%d %s", len(msgB), msgB)
+	if r := scanFrames(t, octetFraming, bad); len(r) != 2 || r[0] != "garbage header" || r[1] != msgB {
+		t.Fatalf("bad octet resync: %q", r)
+	}
+
+	// a truncated final frame is still delivered
+	if r := scanFrames(t, octetFraming, fmt.Sprintf("%d %s", len(msgB)+10, msgB)); len(r) != 1 || r[0] != msgB {
+		t.Fatalf("bad truncated frame: %q", r)
+	}
+}
@@ -19,11 +19,11 @@ import (
 	"strings"
 
 	"github.com/gravwell/gravwell/v3/ingest/log"
-	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
 const (
-	maxRFCSize = 100000 //100KB
+	maxRFCSize        = 100000 //100KB
+	maxOctetHeaderLen = 6      //digits required to express maxRFCSize
 )
 
 var (
@@ -63,6 +63,9 @@ func rfc6587ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 	defer cfg.wg.Done()
 	defer delConn(id)
 	defer c.Close()
+	if !tlsHandshake(c, cfg.name, cfg.stats) {
+		return
+	}
 	var rip net.IP
 	debugout("new connection from %v\n", c.RemoteAddr().String())
 
@@ -80,34 +83,11 @@ func rfc6587ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 		rip = cfg.src
 	}
 
-	tcfg := timegrinder.Config{
-		EnableLeftMostSeed: true,
-	}
-	tg, err := timegrinder.NewTimeGrinder(tcfg)
+	tg, err := newTimegrinder(cfg.timeCfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get a handle on the timegrinder: %v\n", err)
-		return
-	} else if err = cfg.timeFormats.LoadFormats(tg); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load custom time formats: %v\n", err)
+		lg.Error("failed to load the timegrinder", log.KV("listener", cfg.name), log.KVErr(err))
 		return
 	}
-
-	if cfg.setLocalTime {
-		tg.SetLocalTime()
-	}
-	if cfg.timezoneOverride != `` {
-		err = tg.SetTimezone(cfg.timezoneOverride)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to set timezone to %v: %v\n", cfg.timezoneOverride, err)
-			return
-		}
-	}
-	if cfg.formatOverride != `` {
-		if err = tg.SetFormatOverride(cfg.formatOverride); err != nil {
-			lg.Error("Failed to load format override", log.KV("override", cfg.formatOverride), log.KVErr(err))
-			return
-		}
-	}
 	s := bufio.NewScanner(c)
 	s.Buffer(make([]byte, initDataSize), maxDataSize)
 	splitter := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
@@ -161,8 +141,11 @@ func rfc6587ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 		return
 	}
 	s.Split(splitter)
+	lim := cfg.meter(c.RemoteAddr())
+	defer lim.closed()
 	for s.Scan() {
 		data := bytes.Trim(s.Bytes(), "\n\r\t \x00")
+		lim.checkHeader(data)
 		if cfg.dropPriority {
 			data = dropPriority(data)
 		}
@@ -170,9 +153,11 @@ func rfc6587ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 			continue
 		}
 		data = bytes.Clone(data) // we have to copy due to the scanner reusing its underlying buffer
-		if ent, err := handleLog(data, rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
+		if err := lim.wait(cfg.ctx, len(data)); err != nil {
+			return
+		} else if ent, err := handleLog(data, headerSource(data, rip, cfg.srcFromHeader), cfg.ignoreTimestamps, cfg.lineTag(data), tg); err != nil {
 			return
-		} else if err = cfg.proc.ProcessContext(ent, cfg.ctx); err != nil {
+		} else if err = cfg.process(ent); err != nil {
 			return
 		}
 	}
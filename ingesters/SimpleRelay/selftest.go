@@ -0,0 +1,195 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/config/validate"
+)
+
+var (
+	fTestConfig = flag.Bool("test-config", false, "Deprecated alias of -validate")
+)
+
+func init() {
+	// the validate flag is common to every ingester, the relay extends it with the self test
+	if f := flag.Lookup("validate"); f != nil {
+		f.Usage = "Load the configuration, connect to the indexers, negotiate every tag, and bind every listener without ingesting, then exit"
+	}
+}
+
+// validateRequested returns whether the relay should run the self test and exit
+func validateRequested() bool {
+	return validate.Requested() || *fTestConfig
+}
+
+// selfTest checks everything the relay needs in order to run short of ingesting: the indexers
+// accept the connection and every tag, and every listener can bind. Each check is reported
+// to w, the return value is false if any of them failed.
+func selfTest(w io.Writer, cfg *cfgType, mcfg ingest.UniformMuxerConfig) bool {
+	okIdx := checkIndexers(w, cfg, mcfg)
+	okBind := checkBinds(w, cfg)
+	checkDeprecated(w, cfg)
+	return okIdx && okBind
+}
+
+func report(w io.Writer, err error, format string, args ...interface{}) bool {
+	msg := fmt.Sprintf(format, args...)
+	if err != nil {
+		fmt.Fprintf(w, "FAIL\t%s: %v\n", msg, err)
+		return false
+	}
+	fmt.Fprintf(w, "ok\t%s\n", msg)
+	return true
+}
+
+// checkIndexers connects to every indexer and negotiates every tag the listeners may use
+func checkIndexers(w io.Writer, cfg *cfgType, mcfg ingest.UniformMuxerConfig) bool {
+	//the cache belongs to the running relay, the test never ingests so it does not need one
+	mcfg.CachePath, mcfg.CacheMode = ``, ``
+	igst, err := ingest.NewUniformMuxer(mcfg)
+	if err != nil {
+		return report(w, err, "build ingest muxer")
+	} else if err = igst.Start(); err != nil {
+		return report(w, err, "start ingest muxer")
+	}
+	defer igst.Close()
+	if err = igst.WaitForHot(cfg.Timeout()); err != nil {
+		return report(w, err, "connect to indexers %v (check addresses, Ingest-Secret, and tags)", mcfg.Destinations)
+	}
+	hot, _ := igst.Hot()
+	ok := report(w, nil, "connected to %d of %d indexers", hot, len(mcfg.Destinations))
+	if hot < len(mcfg.Destinations) {
+		fmt.Fprintf(w, "WARN\t%d indexers are unreachable\n", len(mcfg.Destinations)-hot)
+	}
+
+	//the configured tags were negotiated as part of the connection, this catches allowlist violations
+	tags := append([]string(nil), mcfg.Tags...)
+	sort.Strings(tags)
+	for _, tag := range tags {
+		_, err := igst.NegotiateTag(tag)
+		ok = report(w, err, "negotiate tag %s", tag) && ok
+	}
+	return ok
+}
+
+// checkBinds binds and immediately releases every listener and the metrics endpoint
+func checkBinds(w io.Writer, cfg *cfgType) bool {
+	ok := true
+	check := func(kind, name string, bc *baseConfig) {
+		for _, bstr := range bc.Bind_String {
+			ok = report(w, testBind(bc, bstr), "%s %q bind %s", kind, name, bstr) && ok
+		}
+	}
+	for _, name := range sortedKeys(cfg.Listener) {
+		check(`Listener`, name, &cfg.Listener[name].baseConfig)
+	}
+	for _, name := range sortedKeys(cfg.JSONListener) {
+		check(`JSONListener`, name, &cfg.JSONListener[name].baseConfig)
+	}
+	for _, name := range sortedKeys(cfg.RegexListener) {
+		check(`RegexListener`, name, &cfg.RegexListener[name].baseConfig)
+	}
+	if cfg.Metrics_Bind != `` {
+		var err error
+		var l net.Listener
+		if l, err = net.Listen("tcp", cfg.Metrics_Bind); err == nil {
+			l.Close()
+		}
+		ok = report(w, err, "Metrics-Bind %s", cfg.Metrics_Bind) && ok
+	}
+	return ok
+}
+
+// checkDeprecated warns about deprecated settings, they do not fail the test
+func checkDeprecated(w io.Writer, cfg *cfgType) {
+	for _, name := range sortedKeys(cfg.Listener) {
+		for _, n := range cfg.Listener[name].deprecatedOptions(cfg.Reader_Options[name]) {
+			fmt.Fprintf(w, "WARN\tListener %q sets %s, move it to a Reader-Options section\n", name, n)
+		}
+	}
+}
+
+func sortedKeys[T any](mp map[string]T) (keys []string) {
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// testBind confirms a bind string is free by listening on it and closing the listener,
+// TLS binds must also have a loadable certificate
+func testBind(bc *baseConfig, bstr string) (err error) {
+	tp, str, err := translateBindType(bstr)
+	if err != nil {
+		return
+	}
+	switch {
+	case tp.TCP(), tp.TLS():
+		if tp.TLS() {
+			if _, err = bc.tlsConfig(); err != nil {
+				return
+			}
+		}
+		nw := `tcp`
+		if tp == tcp6 {
+			nw = `tcp6`
+		}
+		var l net.Listener
+		if l, err = net.Listen(nw, str); err == nil {
+			err = l.Close()
+		}
+	case tp.UDP():
+		var l net.PacketConn
+		if l, err = net.ListenPacket(tp.String(), str); err == nil {
+			err = l.Close()
+		}
+	case tp.Unix():
+		err = testUnixBind(tp, str)
+	}
+	return
+}
+
+// testUnixBind checks a unix socket path without disturbing a relay that is already serving it.
+// A socket nobody is listening on is stale and would be replaced at startup.
+func testUnixBind(tp bindType, pth string) error {
+	if fi, err := os.Lstat(pth); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s exists and is not a socket", pth)
+		}
+		if c, err := net.Dial(tp.String(), pth); err == nil {
+			c.Close()
+			return errors.New("socket is in use")
+		}
+		return nil
+	}
+	addr := &net.UnixAddr{Name: pth, Net: tp.String()}
+	if tp == unix {
+		l, err := net.ListenUnix(tp.String(), addr)
+		if err != nil {
+			return err
+		}
+		return l.Close() //closing a unix listener removes the socket file
+	}
+	l, err := net.ListenUnixgram(tp.String(), addr)
+	if err != nil {
+		return err
+	}
+	l.Close()
+	return os.Remove(pth)
+}
@@ -0,0 +1,70 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckBinds(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	busyUnix, err := net.Listen("unix", filepath.Join(t.TempDir(), "busy.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busyUnix.Close()
+	dir := t.TempDir()
+	notSock := filepath.Join(dir, "file")
+	if err := os.WriteFile(notSock, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &cfgType{
+		Listener: map[string]*listener{
+			"free": {baseConfig: baseConfig{Bind_String: []string{
+				"tcp://127.0.0.1:0", "udp://127.0.0.1:0", "unix://" + filepath.Join(dir, "free.sock"),
+			}}},
+			"busy": {baseConfig: baseConfig{Bind_String: []string{
+				"tcp://" + busy.Addr().String(), "unix://" + busyUnix.Addr().String(), "unix://" + notSock,
+			}}},
+		},
+	}
+	var out bytes.Buffer
+	if checkBinds(&out, cfg) {
+		t.Fatalf("failed to catch busy binds:\n%s", out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("bad report:\n%s", out.String())
+	}
+	for i, ln := range lines {
+		//listeners are reported in name order
+		if pass := strings.HasPrefix(ln, "ok\t"); pass != (i >= 3) {
+			t.Fatalf("bad result on line %d:\n%s", i, out.String())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "free.sock")); !os.IsNotExist(err) {
+		t.Fatalf("test socket was left behind: %v", err)
+	}
+
+	delete(cfg.Listener, "busy")
+	out.Reset()
+	if !checkBinds(&out, cfg) {
+		t.Fatalf("free binds failed:\n%s", out.String())
+	}
+}
@@ -9,23 +9,44 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
+	"os"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gravwell/gravwell/v3/ingest"
-	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
 	"github.com/gravwell/gravwell/v3/timegrinder"
+
+	"github.com/gravwell/jsonparser"
+)
+
+const (
+	tlsHandshakeTimeout = 10 * time.Second
+
+	defaultMaxMultilineBytes = 1024 * 1024
+	defaultMultilineFlush    = 2 * time.Second
+	defaultMaxLineLength     = 4 * 1024 * 1024
+	maxLineDelimiter         = 16
 )
 
 var (
+	newline = []byte("\n")
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 	connClosers map[int]closer
 	connId      int
 	mtx         sync.Mutex
@@ -37,132 +58,379 @@ type closer interface {
 
 type handlerConfig struct {
 	name             string
-	tag              entry.EntryTag
+	tags             *atomic.Pointer[listenerTags]
 	lrt              readerType
 	ignoreTimestamps bool
-	setLocalTime     bool
 	dropPriority     bool
-	timezoneOverride string
+	srcFromHeader    bool
 	src              net.IP
 	wg               *sync.WaitGroup
-	formatOverride   string
+	tsField          []string
+	maxLPS           int
+	maxBPS           int
+	lineCont         *regexp.Regexp
+	maxMultiline     int
+	multilineFlush   time.Duration // idle time after which a pending multiline entry is emitted
+	maxLine          int
+	dropOversize     bool
+	delim            []byte // record delimiter of line based readers
+	tagFromVendor    bool
+	framing          framingType
+	vendorTags       *vendorTags // shared by every connection of the listener, nil unless Tag-From-Vendor is set
+	conns            *connLimit
+	idleTimeout      time.Duration
+	gzip             bool
 	proc             *processors.ProcessorSet
 	ctx              context.Context
-	timeFormats      config.CustomTimeFormat
+	timeCfg          processors.TimeExtractorConfig
+	remotes          *remoteFilter
+	datagramEntry    bool
+	stripBOM         bool
+	trimCR           bool
+	active           *connSet
+	logLevel         log.Level
+	stats            *listenerStats
+	raw              rawFraming
+	proxyProtocol    bool
+	evs              []entry.EnumeratedValue // listener Enrichment values
+	dedup            *dedupCache             // shared by every connection of the listener, nil if Dedup-Window is unset
 }
 
-func startSimpleListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGroup, f *flusher, ctx context.Context) error {
-	//short circuit out on empty
-	if len(cfg.Listener) == 0 {
-		return nil
-	}
+// listenerTags are the resolved tags of a listener, a reload that only changes
+// the tags of a listener swaps them in place without interrupting connections
+type listenerTags struct {
+	tag    entry.EntryTag
+	router []routedTag
+}
+
+// routedTag is a Tag-Router rule with its tag resolved
+type routedTag struct {
+	re  *regexp.Regexp
+	tag entry.EntryTag
+}
+
+// simpleListeners tracks the running simple listeners so that they can be stopped and started individually
+type simpleListeners struct {
+	sync.Mutex
+	igst *ingest.IngestMuxer
+	wg   *sync.WaitGroup
+	f    *flusher
+	ctx  context.Context
+	boot *cfgType // configuration the ingester started with
+	live map[string]*liveListener
+}
+
+// liveListener is a running simple listener along with the sockets, connections, and preprocessors it owns
+type liveListener struct {
+	name   string
+	cfg    listener
+	parent *cfgType
+	hcfg   handlerConfig
+	socks  []closer
+	active *connSet
+	wg     sync.WaitGroup
+}
 
+func startSimpleListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGroup, f *flusher, ctx context.Context) (*simpleListeners, error) {
+	sl := &simpleListeners{
+		igst: igst,
+		wg:   wg,
+		f:    f,
+		ctx:  ctx,
+		boot: cfg,
+		live: make(map[string]*liveListener, len(cfg.Listener)),
+	}
 	//fire up our simple backends
 	for k, v := range cfg.Listener {
-		var src net.IP
-		if v.Source_Override != `` {
-			src = net.ParseIP(v.Source_Override)
-			if src == nil {
-				return fmt.Errorf("Listener %v invalid source override \"%s\"", k, v.Source_Override)
-			}
-		} else if cfg.Source_Override != `` {
-			// global override
-			src = net.ParseIP(cfg.Source_Override)
-			if src == nil {
-				return fmt.Errorf("global source override \"%s\" is invalid", cfg.Source_Override)
+		ll, err := sl.start(k, v, cfg)
+		if err != nil {
+			return nil, err
+		}
+		sl.live[k] = ll
+	}
+	debugout("Started %d listeners\n", len(cfg.Listener))
+	return sl, nil
+}
+
+// start binds and starts a single listener, anything that was started is torn down if the listener fails
+func (sl *simpleListeners) start(k string, v *listener, cfg *cfgType) (_ *liveListener, err error) {
+	ll := &liveListener{
+		name:   k,
+		cfg:    *v,
+		parent: cfg,
+		active: &connSet{},
+	}
+	defer func() {
+		if err != nil {
+			ll.stop(sl.f)
+		}
+	}()
+	if dep := v.deprecatedOptions(cfg.Reader_Options[k]); len(dep) > 0 {
+		lg.Warn("reader settings in a Listener section are deprecated, move them to a Reader-Options section",
+			log.KV("listener", k), log.KV("settings", strings.Join(dep, ",")))
+	}
+	var src net.IP
+	if v.Source_Override != `` {
+		src = net.ParseIP(v.Source_Override)
+		if src == nil {
+			return nil, fmt.Errorf("Listener %v invalid source override \"%s\"", k, v.Source_Override)
+		}
+	} else if cfg.Source_Override != `` {
+		// global override
+		src = net.ParseIP(cfg.Source_Override)
+		if src == nil {
+			return nil, fmt.Errorf("global source override \"%s\" is invalid", cfg.Source_Override)
+		}
+	}
+	//get the tags for this listener
+	tags, err := resolveTags(sl.igst, v)
+	if err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	lrt, err := translateReaderType(v.Reader_Type)
+	if err != nil {
+		return nil, fmt.Errorf("Listener %v invalid reader type %q: %v", k, v.Reader_Type, err)
+	}
+	hcfg := handlerConfig{
+		name:             k,
+		tags:             &atomic.Pointer[listenerTags]{},
+		lrt:              lrt,
+		ignoreTimestamps: v.Ignore_Timestamps,
+		dropPriority:     v.Drop_Priority,
+		srcFromHeader:    v.Source_From_Header,
+		src:              src,
+		wg:               &ll.wg,
+		ctx:              sl.ctx,
+		timeCfg:          v.timeConfig(cfg.TimeFormat),
+		maxLPS:           v.Max_Lines_Per_Second,
+		maxBPS:           v.Max_Bytes_Per_Second,
+		tagFromVendor:    v.Tag_From_Vendor,
+		datagramEntry:    v.UDP_Datagram_Per_Entry,
+		stripBOM:         v.Strip_BOM,
+		trimCR:           v.Trim_CR,
+		proxyProtocol:    v.Proxy_Protocol,
+		active:           ll.active,
+		stats:            relayStats.listener(k),
+		dedup:            newDedupCache(v.Dedup_Window),
+	}
+	hcfg.tags.Store(tags)
+	if v.Tag_From_Vendor {
+		hcfg.vendorTags = newVendorTags(k, sl.igst, v.Allowed_Vendor_Tags, v.Max_Vendor_Tags)
+	}
+	if v.Max_Connections > 0 {
+		hcfg.conns = &connLimit{max: int32(v.Max_Connections)}
+	}
+	if hcfg.evs, err = v.enrichment(cfg.Attach.Names()); err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	if hcfg.idleTimeout, err = v.idleTimeout(); err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	if hcfg.framing, err = translateFramingType(v.RFC6587_Framing); err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	if hcfg.gzip, err = v.gzipCompression(); err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	if hcfg.remotes, err = newRemoteFilter(k, v.Allow_Remote, v.Deny_Remote); err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	if hcfg.logLevel, err = v.logLevel(); err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	if v.Line_Continuation_Regex != `` {
+		if hcfg.lineCont, err = regexp.Compile(v.Line_Continuation_Regex); err != nil {
+			return nil, fmt.Errorf("Listener %v invalid Line-Continuation-Regex %q: %v", k, v.Line_Continuation_Regex, err)
+		}
+		if hcfg.maxMultiline = v.Max_Multiline_Bytes; hcfg.maxMultiline == 0 {
+			hcfg.maxMultiline = defaultMaxMultilineBytes
+		}
+		if hcfg.multilineFlush, err = v.multilineFlush(); err != nil {
+			return nil, fmt.Errorf("Listener %v %v", k, err)
+		}
+	}
+	switch lrt {
+	case lineReader, jsonReader, cefReader, leefReader:
+		if hcfg.maxLine, hcfg.dropOversize, err = v.maxLineLength(); err != nil {
+			return nil, fmt.Errorf("Listener %v %v", k, err)
+		} else if hcfg.delim, err = v.lineDelimiter(); err != nil {
+			return nil, fmt.Errorf("Listener %v %v", k, err)
+		}
+	}
+	if lrt == rawReader {
+		if hcfg.raw, err = v.rawFraming(); err != nil {
+			return nil, fmt.Errorf("Listener %v %v", k, err)
+		}
+	}
+	if lrt == jsonReader {
+		if v.Timestamp_Field != `` {
+			if hcfg.tsField, err = getJsonFields(v.Timestamp_Field); err != nil {
+				return nil, fmt.Errorf("Listener %v invalid Timestamp-Field %q: %v", k, v.Timestamp_Field, err)
 			}
 		}
-		//get the tag for this listener
-		tag, err := igst.GetTag(v.Tag_Name)
+		if v.Timestamp_Format != `` {
+			hcfg.timeCfg.FormatOverride = v.Timestamp_Format
+		}
+	}
+	mw := meteredWriter{IngestMuxer: sl.igst, stats: hcfg.stats}
+	if mw.skew, err = v.timestampClamp(); err != nil {
+		return nil, fmt.Errorf("Listener %v %v", k, err)
+	}
+	if hcfg.proc, err = cfg.Preprocessor.ProcessorSet(mw, v.Preprocessor); err != nil {
+		return nil, fmt.Errorf("Listener %v preprocessor error: %v", k, err)
+	}
+	sl.f.Add(hcfg.proc)
+	ll.hcfg = hcfg
+	//each bind string gets its own accept loop, all sharing the listener configuration
+	for _, bstr := range v.Bind_String {
+		if err = ll.bind(bstr, sl.igst); err != nil {
+			return nil, err
+		}
+	}
+	//hold the parent wait group until every goroutine owned by the listener has exited
+	sl.wg.Add(1)
+	go func() {
+		ll.wg.Wait()
+		sl.wg.Done()
+	}()
+	hcfg.stats.setBound(true)
+	return ll, nil
+}
+
+// bind opens the socket for a single bind string and starts its accept loop
+func (ll *liveListener) bind(bstr string, igst *ingest.IngestMuxer) error {
+	k, v, hcfg := ll.name, &ll.cfg, ll.hcfg
+	tp, str, err := translateBindType(bstr)
+	if err != nil {
+		return fmt.Errorf("%s invalid Bind-String %q: %v", k, bstr, err)
+	}
+	if tp.TCP() {
+		//get the socket
+		addr, err := net.ResolveTCPAddr(tp.String(), str)
+		if err != nil {
+			return fmt.Errorf("%s Bind-String \"%s\" is invalid: %v\n", k, bstr, err)
+		}
+		l, err := net.ListenTCP(tp.String(), addr)
+		if err != nil {
+			return fmt.Errorf("%s Failed to listen on \"%s\": %v\n", k, addr, err)
+		}
+		ll.socks = append(ll.socks, l)
+		connID := addConn(l)
+		//start the acceptor
+		ll.wg.Add(1)
+		go acceptor(l, connID, igst, hcfg, tp)
+	} else if tp.TLS() {
+		config, err := v.tlsConfig()
+		if err != nil {
+			return fmt.Errorf("%s failed to load TLS configuration: %v", k, err)
+		}
+		//get the socket
+		addr, err := net.ResolveTCPAddr("tcp", str)
+		if err != nil {
+			return fmt.Errorf("%s Bind-String %q is invalid: %v", k, bstr, err)
+		}
+		l, err := tls.Listen("tcp", addr.String(), config)
+		if err != nil {
+			return fmt.Errorf("%s failed to listen via TLS on %q: %v", k, addr, err)
+		}
+		ll.socks = append(ll.socks, l)
+		connID := addConn(l)
+		//start the acceptor
+		ll.wg.Add(1)
+		go acceptor(l, connID, igst, hcfg, tp)
+	} else if tp.UDP() {
+		addr, err := net.ResolveUDPAddr(tp.String(), str)
 		if err != nil {
-			lg.Fatal("failed to resolve tag", log.KV("tag", v.Tag_Name), log.KVErr(err))
+			return fmt.Errorf("%s Bind-String %q is invalid: %v", k, bstr, err)
 		}
-		tp, str, err := translateBindType(v.Bind_String)
+		l, err := net.ListenUDP(tp.String(), addr)
 		if err != nil {
-			lg.FatalCode(0, "invalid bind", log.KV("bindstring", v.Bind_String), log.KVErr(err))
+			return fmt.Errorf("%s failed to listen via udp on %q: %v", k, addr, err)
 		}
-		lrt, err := translateReaderType(v.Reader_Type)
+		ll.socks = append(ll.socks, l)
+		connID := addConn(l)
+		ll.wg.Add(1)
+		go acceptorUDP(l, connID, hcfg, igst)
+	} else if tp.Unix() {
+		mode, err := v.socketPermissions()
 		if err != nil {
-			lg.FatalCode(0, "invalid reader type", log.KV("readertype", v.Reader_Type), log.KVErr(err))
-		}
-		hcfg := handlerConfig{
-			name:             k,
-			tag:              tag,
-			lrt:              lrt,
-			ignoreTimestamps: v.Ignore_Timestamps,
-			setLocalTime:     v.Assume_Local_Timezone,
-			dropPriority:     v.Drop_Priority,
-			timezoneOverride: v.Timezone_Override,
-			src:              src,
-			wg:               wg,
-			formatOverride:   v.Timestamp_Format_Override,
-			ctx:              ctx,
-			timeFormats:      cfg.TimeFormat,
-		}
-		if hcfg.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
-			lg.Fatal("preprocessor error", log.KVErr(err))
-		}
-		f.Add(hcfg.proc)
-		if tp.TCP() {
-			//get the socket
-			addr, err := net.ResolveTCPAddr(tp.String(), str)
+			return fmt.Errorf("%s %v", k, err)
+		}
+		//unix sockets carry no remote address, attribute entries to the local host
+		ucfg := hcfg
+		if ucfg.src == nil {
+			ucfg.src = net.IPv4(127, 0, 0, 1)
+		}
+		if err = removeStaleSocket(str); err != nil {
+			return fmt.Errorf("%s failed to remove stale unix socket %q: %v", k, str, err)
+		}
+		addr := &net.UnixAddr{Name: str, Net: tp.String()}
+		if tp == unix {
+			l, err := net.ListenUnix(tp.String(), addr)
 			if err != nil {
-				return fmt.Errorf("%s Bind-String \"%s\" is invalid: %v\n", k, v.Bind_String, err)
+				return fmt.Errorf("%s failed to listen via unix socket %q: %v", k, str, err)
 			}
-			l, err := net.ListenTCP(tp.String(), addr)
-			if err != nil {
-				return fmt.Errorf("%s Failed to listen on \"%s\": %v\n", k, addr, err)
+			ll.socks = append(ll.socks, l)
+			if err = chmodSocket(str, mode); err != nil {
+				return fmt.Errorf("%s failed to set unix socket permissions on %q: %v", k, str, err)
 			}
 			connID := addConn(l)
-			//start the acceptor
-			wg.Add(1)
-			go acceptor(l, connID, igst, hcfg, tp)
-		} else if tp.TLS() {
-			config := &tls.Config{
-				MinVersion: tls.VersionTLS12,
-			}
-
-			config.Certificates = make([]tls.Certificate, 1)
-			config.Certificates[0], err = tls.LoadX509KeyPair(v.Cert_File, v.Key_File)
+			ll.wg.Add(1)
+			go acceptor(l, connID, igst, ucfg, tp)
+		} else {
+			l, err := net.ListenUnixgram(tp.String(), addr)
 			if err != nil {
-				lg.FatalCode(0, "failed to load certificate", log.KV("certfile", v.Cert_File), log.KV("keyfile", v.Key_File), log.KVErr(err))
+				return fmt.Errorf("%s failed to listen via unixgram socket %q: %v", k, str, err)
 			}
-			//get the socket
-			addr, err := net.ResolveTCPAddr("tcp", str)
-			if err != nil {
-				lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", v.Bind_String), log.KV("listener", k), log.KVErr(err))
-			}
-			l, err := tls.Listen("tcp", addr.String(), config)
-			if err != nil {
-				lg.FatalCode(0, "failed to listen via TLS", log.KV("address", addr), log.KV("listener", k), log.KVErr(err))
+			ll.socks = append(ll.socks, l)
+			if err = chmodSocket(str, mode); err != nil {
+				os.Remove(str)
+				return fmt.Errorf("%s failed to set unix socket permissions on %q: %v", k, str, err)
 			}
 			connID := addConn(l)
-			//start the acceptor
-			wg.Add(1)
-			go acceptor(l, connID, igst, hcfg, tp)
-		} else if tp.UDP() {
-			addr, err := net.ResolveUDPAddr(tp.String(), str)
-			if err != nil {
-				lg.FatalCode(0, "invalid Bind-String", log.KV("bindstring", v.Bind_String), log.KV("listener", k), log.KVErr(err))
-			}
-			l, err := net.ListenUDP(tp.String(), addr)
-			if err != nil {
-				lg.FatalCode(0, "failed to listen via udp", log.KV("address", addr), log.KV("listener", k), log.KVErr(err))
-			}
-			connID := addConn(l)
-			wg.Add(1)
-			go acceptorUDP(l, connID, hcfg, igst)
+			ll.wg.Add(1)
+			go func() {
+				//datagram sockets are not unlinked on close
+				defer os.Remove(str)
+				acceptorUDP(l, connID, ucfg, igst)
+			}()
 		}
 	}
-	debugout("Started %d listeners\n", len(cfg.Listener))
 	return nil
 }
 
+// stop closes the sockets and connections owned by the listener, waits briefly for its
+// handlers to exit, and then flushes and closes its preprocessors
+func (ll *liveListener) stop(f *flusher) {
+	ll.hcfg.stats.setBound(false)
+	for _, c := range ll.socks {
+		c.Close()
+	}
+	ll.active.closeAll()
+	wch := make(chan struct{})
+	go func() {
+		ll.wg.Wait()
+		close(wch)
+	}()
+	select {
+	case <-wch:
+	case <-time.After(time.Second):
+		lg.Error("Failed to wait for listener connections to close", log.KV("listener", ll.name), log.KV("timeout", time.Second))
+	}
+	if ll.hcfg.proc != nil {
+		f.Remove(ll.hcfg.proc)
+		if err := ll.hcfg.proc.Close(); err != nil {
+			lg.Error("failed to close preprocessors", log.KV("listener", ll.name), log.KVErr(err))
+		}
+	}
+}
+
 func acceptor(lst net.Listener, id int, igst *ingest.IngestMuxer, cfg handlerConfig, tp bindType) {
 	var failCount int
 	defer cfg.wg.Done()
 	defer delConn(id)
 	defer lst.Close()
+	defer cfg.logRejected()
 	for {
 		conn, err := lst.Accept()
 		if err != nil {
@@ -171,36 +439,156 @@ func acceptor(lst net.Listener, id int, igst *ingest.IngestMuxer, cfg handlerCon
 				break
 			}
 			failCount++
+			cfg.stats.connError(err)
 			lg.Warn("failed to accept TCP connection", log.KVErr(err))
 			if failCount > 3 {
 				break
 			}
 			continue
 		}
-		debugout("Accepted %v connection from %s in %v mode\n", conn.RemoteAddr(), cfg.lrt, tp.String())
-		lg.Info("accepted connection", log.KV("address", conn.RemoteAddr()), log.KV("readertype", cfg.lrt), log.KV("mode", tp), log.KV("listener", cfg.name))
 		failCount = 0
+		//behind a load balancer the remote filter applies to the client address in the PROXY header
+		if !cfg.proxyProtocol && !cfg.remotes.allowed(conn.RemoteAddr()) {
+			conn.Close()
+			continue
+		}
+		debugout("Accepted %v connection from %s in %v mode\n", conn.RemoteAddr(), cfg.lrt, tp.String())
+		cfg.logEvent(log.INFO, "accepted connection", log.KV("remote_addr", conn.RemoteAddr()), log.KV("mode", tp))
+		var handler func(net.Conn, handlerConfig)
 		switch cfg.lrt {
-		case lineReader:
-			go lineConnHandlerTCP(conn, cfg)
+		case lineReader, jsonReader, cefReader, leefReader:
+			handler = lineConnHandlerTCP
 		case rfc5424Reader:
-			go rfc5424ConnHandlerTCP(conn, cfg)
+			handler = rfc5424ConnHandlerTCP
 		case rfc6587Reader:
-			go rfc6587ConnHandlerTCP(conn, cfg)
+			handler = rfc6587ConnHandlerTCP
+		case rawReader:
+			handler = rawConnHandlerTCP
 		default:
+			conn.Close()
 			lg.Error("invalid reader type", log.KV("readertype", cfg.lrt))
 			return
 		}
+		if !cfg.conns.acquire() {
+			lg.Warn("connection limit reached, refusing connection", log.KV("address", conn.RemoteAddr()), log.KV("listener", cfg.name), log.KV("limit", cfg.conns.max))
+			conn.Close()
+			continue
+		}
+		var pc *proxyConn
+		if cfg.proxyProtocol {
+			pc = &proxyConn{Conn: conn}
+			conn = pc
+		}
+		if cfg.idleTimeout > 0 {
+			conn = &idleConn{Conn: conn, timeout: cfg.idleTimeout, name: cfg.name}
+		}
+		if cfg.gzip {
+			conn = &gzipConn{Conn: conn, name: cfg.name, stats: cfg.stats}
+		}
+		cfg.active.add(conn)
+		cfg.stats.connOpened()
+		cfg.wg.Add(1)
+		go func(c net.Conn) {
+			defer cfg.wg.Done()
+			defer cfg.stats.connClosed()
+			defer cfg.active.remove(c)
+			defer cfg.conns.release()
+			if pc != nil && !cfg.acceptProxy(pc) {
+				c.Close()
+				return
+			}
+			handler(c, cfg)
+		}(conn)
+	}
+}
+
+// connLimit caps the number of concurrent connections on a listener, a nil connLimit is unlimited
+type connLimit struct {
+	max    int32
+	active atomic.Int32
+}
+
+func (cl *connLimit) acquire() bool {
+	if cl == nil {
+		return true
+	}
+	if cl.active.Add(1) > cl.max {
+		cl.active.Add(-1)
+		return false
+	}
+	return true
+}
+
+func (cl *connLimit) release() {
+	if cl != nil {
+		cl.active.Add(-1)
+	}
+}
+
+// idleConn closes out connections that go longer than the timeout without delivering any data
+type idleConn struct {
+	net.Conn
+	timeout time.Duration
+	name    string
+}
+
+func (ic *idleConn) Read(b []byte) (n int, err error) {
+	if err = ic.Conn.SetReadDeadline(time.Now().Add(ic.timeout)); err != nil {
+		return
+	}
+	if n, err = ic.Conn.Read(b); err != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+		lg.Info("closing idle connection", log.KV("address", ic.RemoteAddr()), log.KV("listener", ic.name), log.KV("timeout", ic.timeout))
+	}
+	return
+}
+
+// gzipConn decompresses a gzip stream, concatenated members are read as a single stream
+// the gzip reader is created on the first read so that it does not consume the TLS handshake
+type gzipConn struct {
+	net.Conn
+	zr    *gzip.Reader
+	name  string
+	stats *listenerStats
+}
+
+func (gc *gzipConn) Read(b []byte) (n int, err error) {
+	if gc.zr == nil {
+		if gc.zr, err = gzip.NewReader(gc.Conn); err != nil {
+			gc.zr = nil
+			gc.logCorrupt(err)
+			return
+		}
+	}
+	if n, err = gc.zr.Read(b); err != nil {
+		gc.logCorrupt(err)
+	}
+	return
+}
+
+func (gc *gzipConn) Close() error {
+	if gc.zr != nil {
+		gc.zr.Close()
 	}
+	return gc.Conn.Close()
 }
 
-func acceptorUDP(conn *net.UDPConn, id int, cfg handlerConfig, igst *ingest.IngestMuxer) {
+// logCorrupt logs errors caused by a bad gzip stream, as opposed to the connection going away
+func (gc *gzipConn) logCorrupt(err error) {
+	var ce flate.CorruptInputError
+	if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) || errors.As(err, &ce) {
+		gc.stats.connError(err)
+		lg.Warn("invalid gzip stream, closing connection", log.KV("address", gc.RemoteAddr()), log.KV("listener", gc.name), log.KVErr(err))
+	}
+}
+
+func acceptorUDP(conn net.PacketConn, id int, cfg handlerConfig, igst *ingest.IngestMuxer) {
 	defer cfg.wg.Done()
 	defer delConn(id)
 	defer conn.Close()
+	defer cfg.logRejected()
 	//read packets off
 	switch cfg.lrt {
-	case lineReader:
+	case lineReader, jsonReader, cefReader, leefReader:
 		lineConnHandlerUDP(conn, cfg)
 	case rfc5424Reader:
 		rfc5424ConnHandlerUDP(conn, cfg)
@@ -210,6 +598,149 @@ func acceptorUDP(conn *net.UDPConn, id int, cfg handlerConfig, igst *ingest.Inge
 	}
 }
 
+// logRejected logs the total number of connections or packets refused by the remote filter
+func (hc handlerConfig) logRejected() {
+	if n := hc.remotes.count(); n > 0 {
+		lg.Info("remote filter rejections", log.KV("listener", hc.name), log.KV("rejected", n))
+	}
+}
+
+// limiter returns a new rate limiter for a single connection, nil if the listener is unlimited.
+// Handlers use the connMeter returned by meter, which wraps the limiter.
+func (hc handlerConfig) limiter() *rateLimiter {
+	return newRateLimiter(hc.maxLPS, hc.maxBPS)
+}
+
+// process drops duplicate entries, attaches the listener enrichment, and hands the entry to the preprocessors
+func (hc handlerConfig) process(ent *entry.Entry) error {
+	if hc.dedup.duplicate(ent) {
+		hc.stats.duplicateDropped()
+		return nil
+	}
+	if len(hc.evs) > 0 {
+		ent.AddEnumeratedValues(hc.evs)
+	}
+	return hc.proc.ProcessContext(ent, hc.ctx)
+}
+
+// multiline returns a new multiline buffer for a single connection, nil if line continuation is disabled
+func (hc handlerConfig) multiline() *multilineBuffer {
+	if hc.lineCont == nil {
+		return nil
+	}
+	return &multilineBuffer{re: hc.lineCont, max: hc.maxMultiline}
+}
+
+// routeTag returns the tag of the first Tag-Router rule that matches the data, ok is false if none match
+func (hc handlerConfig) routeTag(b []byte) (tag entry.EntryTag, ok bool) {
+	for _, rt := range hc.tags.Load().router {
+		if rt.re.Match(b) {
+			return rt.tag, true
+		}
+	}
+	return
+}
+
+// lineTag returns the tag for an entry, falling back to the listener tag when no Tag-Router rule matches
+func (hc handlerConfig) lineTag(b []byte) entry.EntryTag {
+	if tag, ok := hc.routeTag(b); ok {
+		return tag
+	}
+	return hc.defaultTag()
+}
+
+// defaultTag returns the Tag-Name tag of the listener
+func (hc handlerConfig) defaultTag() entry.EntryTag {
+	return hc.tags.Load().tag
+}
+
+// datagramRecords returns the records carried by a datagram, the whole datagram is
+// a single record when UDP-Datagram-Per-Entry is set, otherwise it is split on the line delimiter
+func (hc handlerConfig) datagramRecords(b []byte) [][]byte {
+	if hc.datagramEntry {
+		return [][]byte{b}
+	} else if len(hc.delim) == 0 {
+		return bytes.Split(b, newline)
+	}
+	return bytes.Split(b, hc.delim)
+}
+
+// skipBOM discards a UTF-8 byte order mark at the start of a stream, it is called once
+// per connection before the first record is read. Read errors are left for the reader.
+func skipBOM(br *bufio.Reader) {
+	if b, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(b, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+}
+
+// cleanDatagram applies Strip-BOM to a datagram before it is split into records
+func (hc handlerConfig) cleanDatagram(b []byte) []byte {
+	if hc.stripBOM {
+		b = bytes.TrimPrefix(b, utf8BOM)
+	}
+	return b
+}
+
+// cleanRecord applies Trim-CR to a single record, b is modified in place
+func (hc handlerConfig) cleanRecord(b []byte) []byte {
+	if hc.trimCR {
+		b = trimCR(b)
+	}
+	return b
+}
+
+// trimCR removes the carriage returns from the end of a record, so a trailing CRLF becomes a LF.
+// Carriage returns within the record, such as the line endings of a multiline entry, are left
+// alone. b is modified in place.
+func trimCR(b []byte) []byte {
+	if n := len(b); n > 0 && b[n-1] == '\n' {
+		return append(bytes.TrimRight(b[:n-1], "\r"), '\n')
+	}
+	return bytes.TrimRight(b, "\r")
+}
+
+// packetSource returns the source address for a datagram, the override wins when set
+func packetSource(raddr net.Addr, override net.IP) net.IP {
+	if override != nil {
+		return override
+	}
+	if ua, ok := raddr.(*net.UDPAddr); ok && ua != nil {
+		return ua.IP
+	}
+	return nil
+}
+
+// removeStaleSocket removes a unix socket left behind by a previous run
+func removeStaleSocket(pth string) error {
+	fi, err := os.Lstat(pth)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	} else if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", pth)
+	}
+	return os.Remove(pth)
+}
+
+func chmodSocket(pth string, mode os.FileMode) error {
+	if mode == 0 {
+		return nil
+	}
+	return os.Chmod(pth, mode)
+}
+
+// newTimegrinder builds the timegrinder for a single connection or datagram listener, every
+// reader type routes through the shared time extraction config so the options behave the same.
+func newTimegrinder(tc processors.TimeExtractorConfig) (*timegrinder.TimeGrinder, error) {
+	te, err := processors.NewTimeExtractor(tc)
+	if err != nil {
+		return nil, err
+	}
+	return te.TimeGrinder(), nil
+}
+
 func handleLog(b []byte, ip net.IP, ignoreTS bool, tag entry.EntryTag, tg *timegrinder.TimeGrinder) (ent *entry.Entry, err error) {
 	if len(b) == 0 {
 		return
@@ -238,6 +769,69 @@ func handleLog(b []byte, ip net.IP, ignoreTS bool, tag entry.EntryTag, tg *timeg
 	return
 }
 
+// tlsHandshake forces the handshake on TLS connections so that failures are logged
+// against the listener instead of surfacing as an opaque read error, plain
+// connections are passed through untouched. Failures are counted against stats, which may be nil.
+func tlsHandshake(c net.Conn, name string, stats *listenerStats) bool {
+	if gc, ok := c.(*gzipConn); ok {
+		c = gc.Conn
+	}
+	if ic, ok := c.(*idleConn); ok {
+		c = ic.Conn
+	}
+	tc, ok := c.(*tls.Conn)
+	if !ok {
+		return true
+	}
+	tc.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+	if err := tc.Handshake(); err != nil {
+		stats.connError(err)
+		lg.Warn("TLS handshake failed", log.KV("address", c.RemoteAddr()), log.KV("listener", name), log.KVErr(err))
+		return false
+	}
+	tc.SetDeadline(time.Time{})
+	return true
+}
+
+// handleLine builds an entry out of a single line, JSON readers with a timestamp
+// field pull the timestamp out of that field rather than scanning the whole line
+func (hc handlerConfig) handleLine(b []byte, ip net.IP, tg *timegrinder.TimeGrinder) (*entry.Entry, error) {
+	switch hc.lrt {
+	case jsonReader:
+		if len(hc.tsField) > 0 && !hc.ignoreTimestamps {
+			return handleJSONLog(b, ip, hc.tsField, hc.lineTag(b), tg)
+		}
+	case cefReader, leefReader:
+		return hc.handleEventLog(b, ip, tg)
+	}
+	return handleLog(b, ip, hc.ignoreTimestamps, hc.lineTag(b), tg)
+}
+
+// handleJSONLog extracts the timestamp from a field in a JSON record, if the record
+// is not valid JSON or the timestamp cannot be extracted the current time is used
+func handleJSONLog(b []byte, ip net.IP, flds []string, tag entry.EntryTag, tg *timegrinder.TimeGrinder) (ent *entry.Entry, err error) {
+	if len(b) == 0 {
+		return
+	}
+	ts := entry.Now()
+	if v, _, _, lerr := jsonparser.Get(b, flds...); lerr == nil {
+		var extracted time.Time
+		var ok bool
+		if extracted, ok, err = tg.Extract(v); err != nil {
+			return
+		} else if ok {
+			ts = entry.FromStandard(extracted)
+		}
+	}
+	ent = &entry.Entry{
+		SRC:  ip,
+		TS:   ts,
+		Tag:  tag,
+		Data: b,
+	}
+	return
+}
+
 func addConn(c closer) int {
 	mtx.Lock()
 	connId++
@@ -0,0 +1,727 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+	"github.com/gravwell/gravwell/v3/ingest/processors"
+	"github.com/gravwell/gravwell/v3/timegrinder"
+)
+
+func TestHandleJSONLog(t *testing.T) {
+	tg, err := timegrinder.NewTimeGrinder(timegrinder.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flds, err := getJsonFields(`meta.created`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ip := net.ParseIP("192.168.1.1")
+	good := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	ent, err := handleJSONLog([]byte(`{"ts": "2020-01-01T00:00:00Z", "meta": {"created": "2021-03-04T05:06:07Z"}}`), ip, flds, 0, tg)
+	if err != nil {
+		t.Fatal(err)
+	} else if !ent.TS.StandardTime().Equal(good) {
+		t.Fatalf("invalid timestamp: %v != %v", ent.TS, good)
+	}
+
+	//bad JSON and missing fields fall back to now
+	for _, v := range []string{`not json`, `{"meta": {"other": 1}}`} {
+		start := time.Now().Add(-time.Second)
+		if ent, err = handleJSONLog([]byte(v), ip, flds, 0, tg); err != nil {
+			t.Fatal(err)
+		} else if ent == nil || string(ent.Data) != v {
+			t.Fatalf("entry data was modified: %v", ent)
+		} else if ent.TS.StandardTime().Before(start) {
+			t.Fatalf("timestamp did not fall back to now: %v", ent.TS)
+		}
+	}
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir := t.TempDir()
+	pth := filepath.Join(dir, `relay.sock`)
+	if err := removeStaleSocket(pth); err != nil {
+		t.Fatalf("missing socket should not be an error: %v", err)
+	}
+	l, err := net.ListenUnixgram(`unixgram`, &net.UnixAddr{Name: pth, Net: `unixgram`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	if err = removeStaleSocket(pth); err != nil {
+		t.Fatal(err)
+	} else if _, err = os.Stat(pth); !os.IsNotExist(err) {
+		t.Fatalf("stale socket was not removed: %v", err)
+	}
+
+	//regular files must never be removed
+	reg := filepath.Join(dir, `regular`)
+	if err = os.WriteFile(reg, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = removeStaleSocket(reg); err == nil {
+		t.Fatal("failed to reject a regular file")
+	}
+}
+
+func TestPacketSource(t *testing.T) {
+	ip := net.ParseIP("10.0.0.1")
+	override := net.ParseIP("192.168.1.1")
+	if r := packetSource(&net.UDPAddr{IP: ip}, nil); !r.Equal(ip) {
+		t.Fatalf("bad source: %v", r)
+	} else if r = packetSource(&net.UDPAddr{IP: ip}, override); !r.Equal(override) {
+		t.Fatalf("override ignored: %v", r)
+	} else if r = packetSource(&net.UnixAddr{}, nil); r != nil {
+		t.Fatalf("unix address produced a source: %v", r)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	if rl := newRateLimiter(0, 0); rl != nil {
+		t.Fatal("zero limits should be unlimited")
+	} else if err := rl.wait(ctx, 1024); err != nil {
+		t.Fatal(err)
+	}
+
+	//burst is one second worth, so another 20 lines should take ~200ms
+	rl := newRateLimiter(100, 0)
+	start := time.Now()
+	for i := 0; i < 120; i++ {
+		if err := rl.wait(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 150*time.Millisecond {
+		t.Fatalf("line limiter did not block: %v", d)
+	}
+
+	//entries larger than the burst must still make it through
+	rl = newRateLimiter(0, 1000)
+	start = time.Now()
+	if err := rl.wait(ctx, 1200); err != nil {
+		t.Fatal(err)
+	} else if d := time.Since(start); d < 150*time.Millisecond {
+		t.Fatalf("byte limiter did not block: %v", d)
+	}
+
+	cctx, cf := context.WithCancel(ctx)
+	cf()
+	if err := rl.wait(cctx, 100); err == nil {
+		t.Fatal("failed to abort on a cancelled context")
+	}
+}
+
+func TestMultilineBuffer(t *testing.T) {
+	ml := &multilineBuffer{re: regexp.MustCompile(`^\s`), max: 135}
+	lines := []string{
+		`Exception in thread "main" java.lang.NullPointerException`,
+		`	at com.example.Foo.bar(Foo.java:16)`,
+		`	at com.example.Main.main(Main.java:4)`,
+		`next record`,
+		`	continued`,
+		`	this continuation is long enough to push the record past the cap`,
+		`	so it starts a brand new record instead of being joined`,
+	}
+	var recs []string
+	for _, ln := range lines {
+		if rec := ml.add([]byte(ln)); len(rec) > 0 {
+			recs = append(recs, string(rec))
+		}
+	}
+	if rec := ml.flush(); len(rec) > 0 {
+		recs = append(recs, string(rec))
+	}
+	expected := []string{
+		"Exception in thread \"main\" java.lang.NullPointerException\n\tat com.example.Foo.bar(Foo.java:16)\n\tat com.example.Main.main(Main.java:4)",
+		"next record\n\tcontinued\n\tthis continuation is long enough to push the record past the cap",
+		"\tso it starts a brand new record instead of being joined",
+	}
+	if len(recs) != len(expected) {
+		t.Fatalf("bad record count %d: %q", len(recs), recs)
+	}
+	for i := range expected {
+		if recs[i] != expected[i] {
+			t.Fatalf("bad record %d: %q != %q", i, recs[i], expected[i])
+		}
+	}
+	if rec := ml.flush(); rec != nil {
+		t.Fatalf("flush did not reset the buffer: %q", rec)
+	}
+}
+
+func TestMultilineIdleFlush(t *testing.T) {
+	if lg == nil {
+		lg = log.NewDiscardLogger()
+	}
+	connClosers = make(map[int]closer, 1)
+	srv, cli := net.Pipe()
+	trk := &lockedTracker{}
+	cfg := handlerConfig{
+		tags:             testTags(0),
+		src:              net.ParseIP("10.0.0.1"),
+		wg:               &sync.WaitGroup{},
+		ctx:              context.Background(),
+		proc:             processors.NewProcessorSet(&nilWriter{}),
+		stats:            relayStats.listener(`multiline`),
+		ignoreTimestamps: true,
+		maxLine:          1024,
+		lineCont:         regexp.MustCompile(`^\s`),
+		maxMultiline:     1024,
+		multilineFlush:   20 * time.Millisecond,
+	}
+	cfg.proc.AddProcessor(trk)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lineConnHandlerTCP(srv, cfg)
+	}()
+	if _, err := cli.Write([]byte("panic: oops\n  at a\n  at b\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the trailing record is emitted while the connection stays open
+	deadline := time.Now().Add(5 * time.Second)
+	for len(trk.data()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("pending multiline record was not flushed on an idle connection")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if r := trk.data(); len(r) != 1 || r[0] != "panic: oops\n  at a\n  at b" {
+		t.Fatalf("bad flushed record: %q", r)
+	}
+
+	// later records are still joined and pushed when the connection closes
+	cli.Write([]byte("next\n  at c\n"))
+	cli.Close()
+	<-done
+	if r := trk.data(); len(r) != 2 || r[1] != "next\n  at c" {
+		t.Fatalf("bad records after close: %q", r)
+	}
+}
+
+func TestBoundedLineReader(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	input := "short\n" + long + "\nexactly16bytes..\n" + long + "tail"
+	lr := &boundedLineReader{br: bufio.NewReaderSize(strings.NewReader(input), 16), max: 16}
+	expected := []struct {
+		line     string
+		oversize bool
+	}{
+		{"short", false},
+		{long[:16], true},
+		{"exactly16bytes..", false},
+		{long[:16], true},
+	}
+	for i, exp := range expected {
+		ln, oversize, err := lr.readLine()
+		if i == len(expected)-1 {
+			if err != io.EOF {
+				t.Fatalf("expected EOF on the final line: %v", err)
+			}
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if string(ln) != exp.line || oversize != exp.oversize {
+			t.Fatalf("bad line %d: %q (oversize %v) != %q (oversize %v)", i, ln, oversize, exp.line, exp.oversize)
+		}
+	}
+}
+
+func TestBoundedLineReaderDelimiter(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	tests := []struct {
+		delim string
+		input string
+		lines []string
+		over  []bool
+	}{
+		// a lone \n is part of the record when the delimiter is \r\n
+		{`\r\n`, "a\nb\r\nc\r\r\n", []string{"a\nb", "c\r"}, []bool{false, false}},
+		{`\0`, "first\x00second\nline\x00", []string{"first", "second\nline"}, []bool{false, false}},
+		// the delimiter may span the reader buffer and does not count towards the line length
+		{`<END>`, "exactly16bytes..<END>" + long + "<END>tail", []string{"exactly16bytes..", long[:16], "tail"}, []bool{false, true, false}},
+		// a partial delimiter split across the truncation point is discarded with the rest of the line
+		{`\x1e\x1e`, "0123456789abcdef\x1e0\x1e\x1eok\x1e\x1e", []string{"0123456789abcdef", "ok"}, []bool{true, false}},
+	}
+	for _, tt := range tests {
+		l := listener{Line_Delimiter: tt.delim}
+		delim, err := l.lineDelimiter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lr := &boundedLineReader{br: bufio.NewReaderSize(strings.NewReader(tt.input), 16), max: 16, delim: delim}
+		for i, exp := range tt.lines {
+			ln, oversize, err := lr.readLine()
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			} else if string(ln) != exp || oversize != tt.over[i] {
+				t.Fatalf("%q: bad line %d: %q (oversize %v) != %q (oversize %v)", tt.delim, i, ln, oversize, exp, tt.over[i])
+			}
+		}
+		if ln, _, err := lr.readLine(); err != io.EOF || len(ln) != 0 {
+			t.Fatalf("%q: expected EOF: %q %v", tt.delim, ln, err)
+		}
+	}
+}
+
+func TestLineDelimiter(t *testing.T) {
+	good := map[string]string{
+		``:          "\n",
+		`\0`:        "\x00",
+		`\r\n`:      "\r\n",
+		`\x1E`:      "\x1e",
+		`|`:         "|",
+		`\\n`:       "\\n",
+		`\t\x00END`: "\t\x00END",
+	}
+	for v, exp := range good {
+		l := listener{Line_Delimiter: v}
+		if delim, err := l.lineDelimiter(); err != nil {
+			t.Fatalf("%q: %v", v, err)
+		} else if string(delim) != exp {
+			t.Fatalf("%q: bad delimiter %q != %q", v, delim, exp)
+		}
+	}
+	for _, v := range []string{`\`, `\q`, `\x1`, `\xzz`, strings.Repeat(`x`, maxLineDelimiter+1)} {
+		l := listener{Line_Delimiter: v}
+		if _, err := l.lineDelimiter(); err == nil {
+			t.Fatalf("failed to catch bad delimiter %q", v)
+		}
+	}
+}
+
+func TestConnLimit(t *testing.T) {
+	var unlimited *connLimit
+	if !unlimited.acquire() {
+		t.Fatal("nil limit refused a connection")
+	}
+	unlimited.release()
+
+	cl := &connLimit{max: 2}
+	if !cl.acquire() || !cl.acquire() {
+		t.Fatal("refused connection under the limit")
+	} else if cl.acquire() {
+		t.Fatal("failed to refuse connection over the limit")
+	}
+	cl.release()
+	if !cl.acquire() {
+		t.Fatal("released slot was not reusable")
+	}
+}
+
+func TestIdleConn(t *testing.T) {
+	srv, cli := net.Pipe()
+	defer cli.Close()
+	ic := &idleConn{Conn: srv, timeout: 50 * time.Millisecond}
+	defer ic.Close()
+	go cli.Write([]byte("hello"))
+	buff := make([]byte, 16)
+	if n, err := ic.Read(buff); err != nil || string(buff[:n]) != "hello" {
+		t.Fatalf("bad read %q: %v", buff[:n], err)
+	}
+	start := time.Now()
+	if _, err := ic.Read(buff); err == nil {
+		t.Fatal("idle read did not time out")
+	} else if d := time.Since(start); d > time.Second {
+		t.Fatalf("idle timeout took too long: %v", d)
+	}
+}
+
+func TestGzipConn(t *testing.T) {
+	if lg == nil {
+		lg = log.NewDiscardLogger()
+	}
+	srv, cli := net.Pipe()
+	gc := &gzipConn{Conn: srv}
+	defer gc.Close()
+	go func() {
+		defer cli.Close()
+		//each batch is a separate gzip member on the same connection
+		for _, batch := range []string{"line one\nline two\n", "line three\n"} {
+			zw := gzip.NewWriter(cli)
+			zw.Write([]byte(batch))
+			zw.Close()
+		}
+	}()
+	var lines []string
+	s := bufio.NewScanner(gc)
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 3 || lines[0] != "line one" || lines[2] != "line three" {
+		t.Fatalf("bad lines: %q", lines)
+	}
+
+	//raw data is rejected rather than passed through as garbage
+	srv, cli = net.Pipe()
+	gc = &gzipConn{Conn: srv}
+	defer gc.Close()
+	go func() {
+		cli.Write([]byte("not compressed at all\n"))
+		cli.Close()
+	}()
+	if _, err := io.ReadAll(gc); !errors.Is(err, gzip.ErrHeader) {
+		t.Fatalf("expected a gzip header error, got %v", err)
+	}
+}
+
+// testTags returns a resolved listener tag set for handler tests
+func testTags(tag entry.EntryTag, router ...routedTag) *atomic.Pointer[listenerTags] {
+	p := &atomic.Pointer[listenerTags]{}
+	p.Store(&listenerTags{tag: tag, router: router})
+	return p
+}
+
+func TestTagRouter(t *testing.T) {
+	hc := handlerConfig{
+		tags: testTags(1,
+			routedTag{re: regexp.MustCompile(`sshd|sudo`), tag: 2},
+			routedTag{re: regexp.MustCompile(`kernel:`), tag: 3},
+			routedTag{re: regexp.MustCompile(`sudo`), tag: 4},
+		),
+	}
+	for _, v := range []struct {
+		line string
+		tag  entry.EntryTag
+	}{
+		{`<38>Jan  1 00:00:00 host sshd[12]: accepted publickey`, 2},
+		{`<38>Jan  1 00:00:00 host sudo: user : TTY=pts/0`, 2}, //first match wins
+		{`<4>Jan  1 00:00:00 host kernel: oom-killer`, 3},
+		{`<14>Jan  1 00:00:00 host cron[1]: job started`, 1},
+	} {
+		if tag := hc.lineTag([]byte(v.line)); tag != v.tag {
+			t.Fatalf("bad tag for %q: %d != %d", v.line, tag, v.tag)
+		}
+	}
+	hc.tags.Store(&listenerTags{tag: 5})
+	if tag := hc.lineTag([]byte(`sshd`)); tag != 5 {
+		t.Fatalf("empty router did not fall back to the listener tag: %d", tag)
+	}
+}
+
+func TestRemoteFilter(t *testing.T) {
+	if lg == nil {
+		lg = log.NewDiscardLogger()
+	}
+	var open *remoteFilter
+	if !open.allowed(&net.TCPAddr{IP: net.ParseIP("8.8.8.8")}) {
+		t.Fatal("nil filter rejected an address")
+	}
+	rf, err := newRemoteFilter(`test`, []string{`10.0.0.0/8`, `192.168.1.5`, `fd00::/8`}, []string{`10.1.0.0/16`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		addr net.Addr
+		ok   bool
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("10.0.0.1")}, true},
+		{&net.UDPAddr{IP: net.ParseIP("::ffff:10.2.3.4")}, true},
+		{&net.UDPAddr{IP: net.ParseIP("192.168.1.5")}, true},
+		{&net.TCPAddr{IP: net.ParseIP("fd00::1")}, true},
+		{&net.TCPAddr{IP: net.ParseIP("10.1.2.3")}, false}, //deny wins
+		{&net.UDPAddr{IP: net.ParseIP("192.168.1.6")}, false},
+		{&net.TCPAddr{IP: net.ParseIP("8.8.8.8")}, false},
+		{&net.UnixAddr{Name: "/tmp/sock"}, false},
+	} {
+		if ok := rf.allowed(v.addr); ok != v.ok {
+			t.Fatalf("bad result for %v: %v != %v", v.addr, ok, v.ok)
+		}
+	}
+	if n := rf.count(); n != 4 {
+		t.Fatalf("bad rejected count: %d != 4", n)
+	}
+
+	//deny only allows everything else
+	if rf, err = newRemoteFilter(`test`, nil, []string{`0.0.0.0/0`}); err != nil {
+		t.Fatal(err)
+	} else if rf.allowed(&net.UDPAddr{IP: net.ParseIP("1.2.3.4")}) || !rf.allowed(&net.UDPAddr{IP: net.ParseIP("::1")}) {
+		t.Fatal("deny only filter is broken")
+	}
+	for _, bad := range []string{`10.0.0.0/33`, `not an ip`, ``} {
+		if _, err = newRemoteFilter(`test`, []string{bad}, nil); err == nil {
+			t.Fatalf("failed to catch bad CIDR %q", bad)
+		}
+	}
+}
+
+// lockedTracker is a tracker that is safe to inspect while a handler is running
+type lockedTracker struct {
+	sync.Mutex
+	tracker
+}
+
+func (lt *lockedTracker) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	lt.Lock()
+	defer lt.Unlock()
+	return lt.tracker.Process(ents)
+}
+
+func (lt *lockedTracker) data() (r []string) {
+	lt.Lock()
+	defer lt.Unlock()
+	for _, ent := range lt.ents {
+		r = append(r, string(ent.Data))
+	}
+	return
+}
+
+func TestProcessEnrichment(t *testing.T) {
+	l := listener{Enrichment: []string{`datacenter=us-east-1`, `collector=relay07`}}
+	evs, err := l.enrichment(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trk := &lockedTracker{}
+	cfg := handlerConfig{
+		ctx:  context.Background(),
+		proc: processors.NewProcessorSet(&nilWriter{}),
+		evs:  evs,
+	}
+	cfg.proc.AddProcessor(trk)
+	if err = cfg.process(&entry.Entry{Data: []byte(`hello`)}); err != nil {
+		t.Fatal(err)
+	} else if len(trk.ents) != 1 {
+		t.Fatalf("bad entry count %d", len(trk.ents))
+	}
+	for _, ev := range evs {
+		if v, ok := trk.ents[0].GetEnumeratedValue(ev.Name); !ok {
+			t.Fatalf("preprocessors did not see enrichment %s", ev.Name)
+		} else if v != ev.Value.String() {
+			t.Fatalf("bad enrichment %s value %v", ev.Name, v)
+		}
+	}
+}
+
+func TestProcessDedup(t *testing.T) {
+	trk := &lockedTracker{}
+	cfg := handlerConfig{
+		ctx:   context.Background(),
+		proc:  processors.NewProcessorSet(&nilWriter{}),
+		stats: &listenerStats{},
+		dedup: newDedupCache(2),
+	}
+	cfg.proc.AddProcessor(trk)
+	ts := entry.UnixTime(1700000000, 0)
+	ent := func(data, src string) *entry.Entry {
+		return &entry.Entry{TS: ts, SRC: net.ParseIP(src), Data: []byte(data)}
+	}
+	for _, e := range []*entry.Entry{
+		ent(`a`, `10.0.0.1`),
+		ent(`a`, `10.0.0.1`), // duplicate
+		ent(`a`, `10.0.0.2`), // different source
+		ent(`b`, `10.0.0.1`), // evicts the first
+		ent(`a`, `10.0.0.1`), // outside the window
+		{TS: entry.UnixTime(1700000001, 0), SRC: net.ParseIP(`10.0.0.1`), Data: []byte(`b`)},
+	} {
+		if err := cfg.process(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(trk.ents) != 5 {
+		t.Fatalf("bad entry count %d", len(trk.ents))
+	} else if n := cfg.stats.duplicates.Load(); n != 1 {
+		t.Fatalf("bad duplicate count %d", n)
+	}
+
+	if newDedupCache(0) != nil {
+		t.Fatal("a zero window should disable dedup")
+	}
+}
+
+func TestDatagramPerEntry(t *testing.T) {
+	datagrams := []string{
+		"first line\nsecond line\n",
+		"<13>Jan  1 00:00:00 host app: one\n<13>Jan  1 00:00:01 host app: two",
+	}
+	run := func(lrt readerType, perEntry bool, want int) (r []string) {
+		t.Helper()
+		pc, err := net.ListenPacket(`udp`, `127.0.0.1:0`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trk := &lockedTracker{}
+		cfg := handlerConfig{
+			lrt:              lrt,
+			tags:             testTags(0),
+			ignoreTimestamps: true,
+			datagramEntry:    perEntry,
+			ctx:              context.Background(),
+			proc:             processors.NewProcessorSet(&nilWriter{}),
+		}
+		cfg.proc.AddProcessor(trk)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if lrt == rfc5424Reader {
+				rfc5424ConnHandlerUDP(pc, cfg)
+			} else {
+				lineConnHandlerUDP(pc, cfg)
+			}
+		}()
+		cli, err := net.Dial(`udp`, pc.LocalAddr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cli.Close()
+		for _, d := range datagrams {
+			if _, err = cli.Write([]byte(d)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for deadline := time.Now().Add(2 * time.Second); len(r) < want && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+			r = trk.data()
+		}
+		pc.Close()
+		<-done
+		if len(r) != want {
+			t.Fatalf("%v: bad entry count %d != %d: %q", lrt, len(r), want, r)
+		}
+		return
+	}
+
+	//the line reader splits on newlines, the rfc5424 reader splits on headers
+	run(lineReader, false, 4)
+	run(rfc5424Reader, false, 3)
+	for _, lrt := range []readerType{lineReader, rfc5424Reader} {
+		r := run(lrt, true, 2)
+		for i := range r {
+			if r[i] != strings.TrimSpace(datagrams[i]) {
+				t.Fatalf("%v: datagram was modified: %q != %q", lrt, r[i], datagrams[i])
+			}
+		}
+	}
+}
+
+func TestStripBOMTrimCR(t *testing.T) {
+	// multi-byte content, including a BOM within the data, must survive untouched
+	utf := "caf\u00e9 \u65e5\u672c \ufeff \U0001f600"
+	trims := map[string]string{
+		"a\r\nb\r\n":       "a\r\nb\n", // only the end of a multiline record is trimmed
+		"a\r\nb\r":         "a\r\nb",
+		"lone\rcr":         "lone\rcr",
+		"\r\r\n":           "\n",
+		"\r":               "",
+		utf + "\r\n" + utf: utf + "\r\n" + utf,
+		utf + "\r\n":       utf + "\n",
+		utf + "\r":         utf,
+		utf:                utf,
+	}
+	for in, exp := range trims {
+		if r := trimCR([]byte(in)); string(r) != exp {
+			t.Fatalf("bad trim of %q: %q != %q", in, r, exp)
+		}
+	}
+
+	for in, exp := range map[string]string{
+		"\ufeff" + utf:  utf,
+		"\ufeff\ufeffx": "\ufeffx", // only the first is a byte order mark
+		"\xef\xbb":      "\xef\xbb",
+		utf:             utf,
+	} {
+		br := bufio.NewReader(strings.NewReader(in))
+		skipBOM(br)
+		if r, err := io.ReadAll(br); err != nil || string(r) != exp {
+			t.Fatalf("bad stream BOM strip of %q: %q != %q (%v)", in, r, exp, err)
+		}
+		hc := handlerConfig{stripBOM: true}
+		if r := hc.cleanDatagram([]byte(in)); string(r) != exp {
+			t.Fatalf("bad datagram BOM strip of %q: %q != %q", in, r, exp)
+		}
+	}
+
+	// both options are off by default and the data is left as is
+	var hc handlerConfig
+	if r := hc.cleanRecord([]byte("a\r\nb")); string(r) != "a\r\nb" {
+		t.Fatalf("record modified with Trim-CR unset: %q", r)
+	} else if r = hc.cleanDatagram([]byte("\ufeffa")); string(r) != "\ufeffa" {
+		t.Fatalf("datagram modified with Strip-BOM unset: %q", r)
+	}
+}
+
+type bufCloser struct {
+	bytes.Buffer
+}
+
+func (bc *bufCloser) Close() error { return nil }
+
+func TestConnLogging(t *testing.T) {
+	for _, v := range []struct {
+		line string
+		ok   bool
+	}{
+		{`<13>1 2026-01-01T00:00:00Z host app - - - msg`, true},
+		{`<1>Jan  1 00:00:00 host app: msg`, true},
+		{`<>missing`, false},
+		{`<1234>too long`, false},
+		{`<12`, false},
+		{`no header at all`, false},
+	} {
+		if ok := validPriority([]byte(v.line)); ok != v.ok {
+			t.Fatalf("bad priority check for %q: %v", v.line, ok)
+		}
+	}
+
+	prev := lg
+	defer func() { lg = prev }()
+	bb := &bufCloser{}
+	lg = log.New(bb)
+	hc := handlerConfig{name: `syslog`, lrt: rfc5424Reader, logLevel: log.WARN}
+	cm := hc.meter(&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000})
+	if err := cm.wait(context.Background(), 10); err != nil {
+		t.Fatal(err)
+	}
+	cm.checkHeader([]byte(`<13>fine`))
+	cm.closed() //INFO is below the listener level
+	if bb.Len() != 0 {
+		t.Fatalf("events below the listener level were logged: %s", bb.String())
+	}
+	cm.checkHeader(bytes.Repeat([]byte("x"), 1024))
+	out := bb.String()
+	for _, want := range []string{`invalid syslog header`, `listener="syslog"`, `reader_type="RFC5424"`, `remote_addr="10.0.0.1:5000"`, strings.Repeat("x", maxLoggedLine) + `..."`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %s in %s", want, out)
+		}
+	}
+	if cm.lines != 1 || cm.bytes != 10 || cm.invalid != 1 {
+		t.Fatalf("bad counters: %+v", cm)
+	}
+
+	bb.Reset()
+	hc.logLevel = log.OFF
+	hc.meter(nil).checkHeader([]byte(`garbage`))
+	if bb.Len() != 0 {
+		t.Fatalf("OFF listener logged: %s", bb.String())
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// handleStatsSignal logs a snapshot of the listener counters every time SIGUSR1 is received,
+// so the relay can be inspected in the field without binding the metrics endpoint.
+func handleStatsSignal(rm *relayMetrics) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1)
+	for range c {
+		rm.logSnapshot(lg)
+	}
+}
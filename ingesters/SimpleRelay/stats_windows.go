@@ -0,0 +1,15 @@
+//go:build windows
+// +build windows
+
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+// handleStatsSignal is a no-op, there is no SIGUSR1 on windows
+func handleStatsSignal(rm *relayMetrics) {}
@@ -54,6 +54,9 @@ type IngesterBaseConfig struct {
 	DefaultConfigLocation        string
 	DefaultConfigOverlayLocation string
 	GetConfigFunc                interface{}
+	// ExtendedValidate is set by ingesters which run further checks of their own when the
+	// validate flag is set, Init then returns rather than exiting once the configuration is valid.
+	ExtendedValidate bool
 }
 
 type IngesterBase struct {
@@ -61,8 +64,12 @@ type IngesterBase struct {
 	Verbose bool
 	Logger  *log.Logger
 	Cfg     interface{}
-	id      uuid.UUID
-	sm      *utils.StatsManager
+	// ConfigLocation and ConfigOverlayLocation are the paths the configuration was loaded from,
+	// ingesters that support reloading use them to re-read the configuration.
+	ConfigLocation        string
+	ConfigOverlayLocation string
+	id                    uuid.UUID
+	sm                    *utils.StatsManager
 }
 
 func Init(ibc IngesterBaseConfig) (ib IngesterBase, err error) {
@@ -83,7 +90,11 @@ func Init(ibc IngesterBaseConfig) (ib IngesterBase, err error) {
 	if err = ibc.validate(); err != nil {
 		return
 	}
-	validate.ValidateIngesterConfig(ib.GetConfigFunc, *confLoc, *confdLoc)
+	if ibc.ExtendedValidate {
+		validate.CheckIngesterConfig(ib.GetConfigFunc, *confLoc, *confdLoc)
+	} else {
+		validate.ValidateIngesterConfig(ib.GetConfigFunc, *confLoc, *confdLoc)
+	}
 
 	var fp string
 	if pth := filepath.Clean(*stderrOverride); pth != `` && pth != `.` {
@@ -100,6 +111,7 @@ func Init(ibc IngesterBaseConfig) (ib IngesterBase, err error) {
 	}
 	ib.Logger.SetAppname(ibc.AppName)
 	ib.Verbose = *verbose
+	ib.ConfigLocation, ib.ConfigOverlayLocation = *confLoc, *confdLoc
 	debug.SetTraceback("all")
 
 	//now try to call getConfig and extract the base ingester configuration
@@ -168,7 +180,9 @@ func (ib *IngesterBase) AssignConfig(v interface{}) (err error) {
 	return nil
 }
 
-func (ib *IngesterBase) GetMuxer() (igst *ingest.IngestMuxer, err error) {
+// MuxerConfig builds the ingest muxer configuration described by the ingester configuration
+// without connecting to anything, GetMuxer hands it to ingest.NewUniformMuxer.
+func (ib *IngesterBase) MuxerConfig() (igCfg ingest.UniformMuxerConfig, err error) {
 	//now try to call getConfig and extract the base ingester configuration
 	if ib.Cfg == nil {
 		err = errors.New("nil config")
@@ -189,14 +203,19 @@ func (ib *IngesterBase) GetMuxer() (igst *ingest.IngestMuxer, err error) {
 
 	conns, err := cfg.Targets()
 	if err != nil {
-		ib.Logger.FatalCode(0, "failed to get backend targets from configuration", log.KVErr(err))
+		err = fmt.Errorf("failed to get backend targets from configuration %w", err)
 		return
 	}
-	ib.Debug("Handling %d tags over %d targets\n", len(tags), len(conns))
+	failovers, err := cfg.FailoverTargets()
+	if err != nil {
+		err = fmt.Errorf("failed to get failover targets from configuration %w", err)
+		return
+	}
+	ib.Debug("Handling %d tags over %d targets with %d failover targets\n", len(tags), len(conns), len(failovers))
 
 	lmt, err := cfg.RateLimit()
 	if err != nil {
-		ib.Logger.FatalCode(0, "failed to get rate limit from configuration", log.KVErr(err))
+		err = fmt.Errorf("failed to get rate limit from configuration %w", err)
 		return
 	}
 	ib.Debug("Rate limiting connection to %d bps\n", lmt)
@@ -208,7 +227,7 @@ func (ib *IngesterBase) GetMuxer() (igst *ingest.IngestMuxer, err error) {
 		id = uuid.Nil //set to the zero UUID, we attempt to write one back during init, but if that fails... just use zero
 	}
 	ib.id = id
-	igCfg := ingest.UniformMuxerConfig{
+	igCfg = ingest.UniformMuxerConfig{
 		IngestStreamConfig: cfg.IngestStreamConfig,
 		Destinations:       conns,
 		Tags:               tags,
@@ -226,7 +245,22 @@ func (ib *IngesterBase) GetMuxer() (igst *ingest.IngestMuxer, err error) {
 		CacheMode:          cfg.Cache_Mode,
 		LogSourceOverride:  net.ParseIP(cfg.Log_Source_Override),
 		Attach:             ch.AttachConfig(),
+
+		CacheEvictionPolicy:   cfg.Cache_Eviction_Policy,
+		FailoverDestinations:  failovers,
+		FailoverCheckInterval: cfg.FailoverCheckInterval(),
+		TagAllowlist:          cfg.Tag_Allowlist,
+		IngestDistribution:    cfg.Ingest_Distribution,
+	}
+	return
+}
+
+func (ib *IngesterBase) GetMuxer() (igst *ingest.IngestMuxer, err error) {
+	igCfg, err := ib.MuxerConfig()
+	if err != nil {
+		return
 	}
+	cfg := ib.Cfg.(cfgHelper).IngestBaseConfig()
 	if igst, err = ingest.NewUniformMuxer(igCfg); err != nil {
 		ib.Logger.Fatal("failed to build our ingest system", log.KVErr(err))
 		return
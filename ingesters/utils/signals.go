@@ -34,3 +34,20 @@ func GetQuitChannel() chan os.Signal {
 	signal.Notify(quitSig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL, syscall.SIGTERM)
 	return quitSig
 }
+
+// WaitForQuitOrReload waits until it receives one of the following signals:
+// SIGINT, SIGQUIT, SIGTERM
+// Each SIGHUP received while waiting calls reload instead of quitting.
+// It returns the received quit signal.
+func WaitForQuitOrReload(reload func()) (r os.Signal) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	for r = range sig {
+		if r != syscall.SIGHUP {
+			break
+		}
+		reload()
+	}
+	return
+}
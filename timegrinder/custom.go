@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -253,3 +254,92 @@ func (pep preExtractProcessor) Extract(d []byte, loc *time.Location) (t time.Tim
 func (pep preExtractProcessor) Name() string {
 	return pep.name
 }
+
+// layoutTokens maps Go reference time layout elements to regular expression fragments.
+// Longer elements must come before any element they are a prefix of.
+var layoutTokens = []struct {
+	tok string
+	rx  string
+}{
+	{`January`, `[A-Z][a-z]{2,8}`},
+	{`Jan`, `[A-Z][a-z]{2}`},
+	{`Monday`, `[A-Z][a-z]{5,8}`},
+	{`Mon`, `[A-Z][a-z]{2}`},
+	{`MST`, `(?:[A-Z]{3,5}|[+-]\d{2,4})`},
+	{`2006`, `\d{4}`},
+	{`_2006`, `_\d{4}`},
+	{`__2`, `[ \d]{2}\d`},
+	{`002`, `\d{3}`},
+	{`_2`, `[ \d]\d`},
+	{`01`, `\d{2}`},
+	{`02`, `\d{2}`},
+	{`03`, `\d{2}`},
+	{`04`, `\d{2}`},
+	{`05`, `\d{2}`},
+	{`06`, `\d{2}`},
+	{`15`, `\d{2}`},
+	{`1`, `\d{1,2}`},
+	{`2`, `\d{1,2}`},
+	{`3`, `\d{1,2}`},
+	{`4`, `\d{1,2}`},
+	{`5`, `\d{1,2}`},
+	{`PM`, `[AP]M`},
+	{`pm`, `[ap]m`},
+	{`-07:00:00`, `[+-]\d{2}:\d{2}:\d{2}`},
+	{`-070000`, `[+-]\d{6}`},
+	{`-07:00`, `[+-]\d{2}:\d{2}`},
+	{`-0700`, `[+-]\d{4}`},
+	{`-07`, `[+-]\d{2}`},
+	{`Z07:00:00`, `(?:Z|[+-]\d{2}:\d{2}:\d{2})`},
+	{`Z070000`, `(?:Z|[+-]\d{6})`},
+	{`Z07:00`, `(?:Z|[+-]\d{2}:\d{2})`},
+	{`Z0700`, `(?:Z|[+-]\d{4})`},
+	{`Z07`, `(?:Z|[+-]\d{2})`},
+}
+
+// LayoutRegex builds an extraction regular expression for a Go reference time layout
+// such as "01/02/2006 15:04:05".  The regex is suitable for use in a CustomFormat.
+func LayoutRegex(layout string) (string, error) {
+	if layout == `` {
+		return ``, ErrMissingFormat
+	}
+	var sb strings.Builder
+	var elements int
+	for i := 0; i < len(layout); {
+		//fractional seconds are a separator followed by a run of 0s or 9s
+		if c := layout[i]; (c == '.' || c == ',') && i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+			j := i + 1
+			for j < len(layout) && layout[j] == layout[i+1] {
+				j++
+			}
+			if j == len(layout) || layout[j] < '0' || layout[j] > '9' {
+				if layout[i+1] == '0' {
+					fmt.Fprintf(&sb, `[.,]\d{%d}`, j-i-1)
+				} else {
+					sb.WriteString(`(?:[.,]\d+)?`)
+				}
+				elements++
+				i = j
+				continue
+			}
+		}
+		var matched bool
+		for _, lt := range layoutTokens {
+			if strings.HasPrefix(layout[i:], lt.tok) {
+				sb.WriteString(lt.rx)
+				i += len(lt.tok)
+				elements++
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sb.WriteString(regexp.QuoteMeta(layout[i : i+1]))
+			i++
+		}
+	}
+	if elements == 0 {
+		return ``, ErrInvalidFormat
+	}
+	return sb.String(), nil
+}
@@ -220,3 +220,37 @@ func TestCustomWithPreExtactor(t *testing.T) {
 	}
 
 }
+
+func TestLayoutRegex(t *testing.T) {
+	layouts := []string{
+		`01/02/2006 15:04:05`,
+		`02/01/2006 03:04:05 PM`,
+		`Jan _2 15:04:05.000`,
+		`Monday, 02-Jan-06 15:04:05 MST`,
+		`2006-01-02T15:04:05.999999Z07:00`,
+		`20060102150405 -0700`,
+	}
+	ts := time.Date(2023, 11, 4, 9, 8, 7, 123456000, time.UTC)
+	for _, l := range layouts {
+		rx, err := LayoutRegex(l)
+		if err != nil {
+			t.Fatalf("failed to build regex for %q: %v", l, err)
+		}
+		cf := CustomFormat{
+			Name:   `test`,
+			Regex:  rx,
+			Format: l,
+		}
+		p, err := NewCustomProcessor(cf)
+		if err != nil {
+			t.Fatalf("failed to build processor for %q (%s): %v", l, rx, err)
+		}
+		val := `prefix ` + ts.Format(l) + ` suffix`
+		if _, ok, _ := p.Extract([]byte(val), time.UTC); !ok {
+			t.Fatalf("failed to extract %q with layout %q (%s)", val, l, rx)
+		}
+	}
+	if _, err := LayoutRegex(`no layout here`); err == nil {
+		t.Fatal("failed to reject a layout without any time elements")
+	}
+}
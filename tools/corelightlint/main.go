@@ -0,0 +1,196 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/processors"
+)
+
+// used when no configuration is given, so the built-in log types can be checked
+const defaultConfig = `
+[Preprocessor "corelight"]
+	Type=corelight
+`
+
+var (
+	configPath = flag.String("config-path", "", "Optional path to a corelight preprocessor configuration")
+	dataPath   = flag.String("data-path", "", "Path to a line delimited sample of Corelight JSON (specify - for stdin)")
+	strict     = flag.Bool("strict", false, "Exit with status 2 if any log types are unmapped")
+)
+
+func main() {
+	flag.Parse()
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	cl, err := processors.NewCorelight(cfg, &testTagHandler{})
+	if err != nil {
+		fmt.Printf("Failed to create corelight processor: %v\n", err)
+		os.Exit(1)
+	}
+
+	var rdr io.Reader
+	switch *dataPath {
+	case ``:
+		fmt.Println("missing data-path")
+		os.Exit(1)
+	case `-`:
+		rdr = os.Stdin
+	default:
+		fin, err := os.Open(*dataPath)
+		if err != nil {
+			fmt.Printf("Failed to open data file %s: %v\n", *dataPath, err)
+			os.Exit(1)
+		}
+		defer fin.Close()
+		rdr = fin
+	}
+
+	res, err := cl.Lint(rdr)
+	if err != nil {
+		fmt.Printf("Failed to read data file: %v\n", err)
+		os.Exit(1)
+	}
+	report(os.Stdout, res)
+	if *strict && len(res.Unmapped) > 0 {
+		os.Exit(2)
+	}
+}
+
+// loadConfig loads the single corelight preprocessor stanza from the file at p,
+// or the default configuration if p is empty.
+func loadConfig(p string) (cfg processors.CorelightConfig, err error) {
+	var tc testConfig
+	var vc *config.VariableConfig
+	if p == `` {
+		err = config.LoadConfigBytes(&tc, []byte(defaultConfig))
+	} else {
+		err = config.LoadConfigFile(&tc, p)
+	}
+	if err != nil {
+		err = fmt.Errorf("Failed to load config file %q: %w", p, err)
+		return
+	} else if tc.count() != 1 {
+		err = fmt.Errorf("config does not contain exactly one corelight configuration: count %d", tc.count())
+		return
+	} else if vc, err = tc.pop(); err != nil {
+		return
+	}
+	if cfg, err = processors.CorelightLoadConfig(vc); err != nil {
+		err = fmt.Errorf("Failed to load corelight config: %w", err)
+	}
+	return
+}
+
+// report writes the lint results as a set of sorted, human readable sections
+func report(w io.Writer, res processors.CorelightLint) {
+	fmt.Fprintf(w, "RECORDS: %d\n", res.Records)
+	section(w, "TAGS", res.Tags)
+	section(w, "DISABLED", res.Disabled)
+	dt := res.DefaultTag
+	if dt == `` {
+		dt = "ingester tag"
+	}
+	section(w, "UNMAPPED (to "+dt+")", res.Unmapped)
+	section(w, "QUARANTINED (failed Validate-Schema)", res.Quarantined)
+	fmt.Fprintf(w, "FAILED: %d\n", res.Failed)
+	if res.Oversized > 0 {
+		fmt.Fprintf(w, "OVERSIZED: %d\n", res.Oversized)
+	}
+}
+
+func section(w io.Writer, name string, mp map[string]uint64) {
+	if len(mp) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(mp))
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "%s:\n", name)
+	for _, k := range keys {
+		fmt.Fprintf(w, "\t%s\t%d\n", k, mp[k])
+	}
+}
+
+type testConfig struct {
+	Preprocessor map[string]*config.VariableConfig
+}
+
+func (tc testConfig) count() int {
+	return len(tc.Preprocessor)
+}
+
+// pop returns the corelight configuration
+func (tc testConfig) pop() (vc *config.VariableConfig, err error) {
+	for k, v := range tc.Preprocessor {
+		if v == nil {
+			continue
+		}
+		var ptype string
+		if ptype, err = v.GetString("type"); err != nil {
+			return
+		} else if ptype = strings.TrimSpace(strings.ToLower(ptype)); ptype != processors.CorelightProcessor {
+			err = fmt.Errorf("Preprocessor %q is of the wrong type: %q != %s", k, ptype, processors.CorelightProcessor)
+			return
+		}
+		return v, nil
+	}
+	return nil, errors.New("failed to pull corelight configuration")
+}
+
+type testTagHandler struct {
+	mp map[string]entry.EntryTag
+}
+
+func (tth *testTagHandler) NegotiateTag(v string) (r entry.EntryTag, err error) {
+	if err = ingest.CheckTag(v); err != nil {
+		return
+	}
+	var ok bool
+	if r, ok = tth.mp[v]; !ok {
+		r = entry.EntryTag(len(tth.mp))
+		if tth.mp == nil {
+			tth.mp = map[string]entry.EntryTag{}
+		}
+		tth.mp[v] = r
+	}
+	return
+}
+
+func (tth *testTagHandler) LookupTag(tag entry.EntryTag) (r string, ok bool) {
+	for k, v := range tth.mp {
+		if v == tag {
+			r, ok = k, true
+			break
+		}
+	}
+	return
+}
+
+func (tth *testTagHandler) KnownTags() (r []string) {
+	for k := range tth.mp {
+		r = append(r, k)
+	}
+	return
+}
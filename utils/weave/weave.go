@@ -22,6 +22,7 @@ import (
 	"github.com/Jeffail/gabs/v2"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"gopkg.in/yaml.v3"
 )
 
 //#region errors
@@ -254,6 +255,42 @@ func ToJSON[Any any](st []Any, columns []string) (string, error) {
 	return toRet + "]", nil // close JSON array
 }
 
+// Given an array of an arbitrary struct and the list of *fully-qualified* fields,
+// outputs a YAML sequence containing the data in the array of the struct.
+// Output mirrors ToJSON, including its alphabetical sorting.
+func ToYAML[Any any](st []Any, columns []string) (string, error) {
+	j, err := ToJSON(st, columns)
+	if err != nil {
+		return "", err
+	}
+	return JSONToYAML([]byte(j))
+}
+
+// JSONToYAML re-encodes a JSON document as block-style YAML, preserving key order and numeric
+// precision.
+func JSONToYAML(j []byte) (string, error) {
+	// YAML is a superset of JSON, so the document can be decoded directly
+	var n yaml.Node
+	if err := yaml.Unmarshal(j, &n); err != nil {
+		return "", err
+	}
+	clearStyle(&n)
+	b, err := yaml.Marshal(&n)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// clearStyle drops the flow and quoting styles inherited from JSON so nodes are emitted as block
+// YAML. The encoder re-quotes any strings that would otherwise be ambiguous.
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
+
 // BROKEN UNTIL Gabs ISSUE#141 IS RESOLVED
 // Given an array of an arbitrary struct, outputs a JSON array containing the
 // data in the array of the struct, minus the blacklisted columns
@@ -1007,6 +1007,55 @@ func TestToJSON(t *testing.T) {
 
 }
 
+func TestToYAML(t *testing.T) {
+	t.Run("superfluous", func(t *testing.T) {
+		actual, err := ToYAML[any](nil, []string{"A"})
+		if err != nil {
+			t.Error("Expected no error, got: ", err)
+		} else if actual != "[]" {
+			t.Errorf("expected '[]', got %v", actual)
+		}
+	})
+
+	t.Run("depth 1 with numerics", func(t *testing.T) {
+		type d1 struct {
+			A int
+			B string
+			C []string
+			D uint64
+			E struct {
+				F float64
+			}
+		}
+		data := []d1{
+			{A: 1, B: "two", C: []string{"a", "b"}, D: math.MaxUint64, E: struct{ F float64 }{1.5}},
+			{A: 2, B: "123"},
+		}
+
+		actual, err := ToYAML(data, []string{"A", "B", "C", "D", "E.F"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "- A: 1\n" +
+			"  B: two\n" +
+			"  C:\n" +
+			"    - a\n" +
+			"    - b\n" +
+			"  D: 18446744073709551615\n" +
+			"  E:\n" +
+			"    F: 1.5\n" +
+			"- A: 2\n" +
+			"  B: \"123\"\n" +
+			"  C: []\n" +
+			"  D: 0\n" +
+			"  E:\n" +
+			"    F: 0"
+		if want != actual {
+			t.Errorf("want <> actual:\nwant: '%v'\nactual: '%v'\n", want, actual)
+		}
+	})
+}
+
 func TestToJSONExclude(t *testing.T) {
 	const emptyJSON = "[]"
 